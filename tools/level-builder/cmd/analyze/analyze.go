@@ -0,0 +1,352 @@
+package analyze
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/analyze"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/explore"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/random"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/ui"
+)
+
+var (
+	dir      string
+	outPath  string
+	top      int
+	levelID  int
+	filePath string
+	dotPath  string
+
+	moduleID  int
+	maxStates int
+	tolerance float64
+
+	samples      int
+	difficulties []string
+	rngLevelID   int
+	startSeed    int64
+	strategy     string
+	rngName      string
+	pairs        int
+	alpha        float64
+)
+
+// analyzeCmd groups the analytics subcommands
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Aggregate statistics across level files, modules and single levels",
+	Long:  `Aggregate statistics across every level file in a directory, chart a module's difficulty curve, inspect one level's blocking graph, or test the generator's output for statistical biases.`,
+}
+
+// coverageCmd represents the analyze coverage command
+var coverageCmd = &cobra.Command{
+	Use:   "coverage",
+	Short: "Show which grid cells, head directions and vine lengths levels favour",
+	Long: `Aggregate occupancy across all level files to expose placement biases.
+
+Levels are grouped by grid size. For each size a heatmap shows the percentage
+of levels with a vine on every cell (origin lower-left, "-" where every level
+masks the cell out), followed by the most and least used cells. Head
+direction and vine length distributions cover all levels; tail heads of
+multi-head vines count as heads.
+
+--out writes the full report as JSON, including per-cell head counts.
+
+Examples:
+  level-builder analyze coverage
+  level-builder analyze coverage --dir /tmp/regen --top 10
+  level-builder analyze coverage --out coverage_report.json`,
+	RunE: runCoverage,
+}
+
+// blockingCmd represents the analyze blocking command
+var blockingCmd = &cobra.Command{
+	Use:   "blocking",
+	Short: "Show which vines block which and export the graph to Graphviz",
+	Long: `Build a level's vine blocking graph and export it for visual debugging.
+
+An edge A -> B means vine A sits in the cell vine B's head would move into, as
+judged by the generator's DFSBlockingAnalyzer. The command prints the number
+of blocking edges, the deepest blocking chain and every group of vines that
+block each other in a loop.
+
+--dot writes the graph in Graphviz DOT format: vines on a loop and the edges
+between them are red, the deepest chain is bold blue and every vine is
+labelled with the length of the longest chain it starts. Render it with
+"dot -Tsvg out.dot -o out.svg".
+
+Examples:
+  level-builder analyze blocking --id 12
+  level-builder analyze blocking --id 12 --dot level_12.dot
+  level-builder analyze blocking --file /tmp/regen/level_40.json --dot out.dot`,
+	RunE: runBlocking,
+}
+
+// moduleCmd represents the analyze module command
+var moduleCmd = &cobra.Command{
+	Use:   "module",
+	Short: "Chart a module's difficulty curve and flag levels easier than earlier ones",
+	Long: `Score every level of a module in play order and flag difficulty regressions.
+
+Levels are taken from modules.json: the module's levels, then its challenge
+level. Each is solved and scored as
+
+  solution length + 2 x max blocking depth + log2(1 + solver states)
+
+where the blocking depth is the longest chain of vines waiting on each other
+(see "analyze blocking") and solver states are 0 when the greedy solver clears
+the level. A level regresses when its score is more than --tolerance below the
+hardest level before it, e.g. level 12 playing easier than level 7.
+
+The command fails when any level regresses, so progression can be checked in
+CI before a module ships. Levels that cannot be loaded or solved are listed
+but not scored. --out writes the curve as JSON.
+
+Examples:
+  level-builder analyze module --id 1
+  level-builder analyze module --id 2 --tolerance 0.1
+  level-builder analyze module --id 3 --out module_3_curve.json`,
+	// A regression is a finding, not a misused flag, so skip the usage text
+	SilenceUsage: true,
+	RunE:         runModule,
+}
+
+// rngCmd represents the analyze rng command
+var rngCmd = &cobra.Command{
+	Use:   "rng",
+	Short: "Test generator output for statistically significant biases",
+	Long: `Generate many levels per difficulty from consecutive seeds and run
+significance tests on the output:
+
+  head direction balance  chi-square of head counts (tail heads included)
+                          against an even split over up, down, left, right
+  mean vine length        chi-square of levels whose mean vine length falls
+                          outside the tier's avg_length_range, flagged only
+                          when more than 5% miss it
+  seed avalanche          levels from seeds one bit apart should differ as
+                          much as levels from unrelated seeds; a one-sided
+                          z-test flags pairs that stay alike
+
+A test is flagged when its p-value is below --alpha. The command fails when
+any test is flagged, so a generator change can be checked before it ships.
+Sampling is slow on the hard tiers; narrow it with --difficulty.
+
+Examples:
+  level-builder analyze rng --samples 500
+  level-builder analyze rng --difficulty Seedling --difficulty Sprout --samples 200
+  level-builder analyze rng --rng pcg --alpha 0.001 --out rng_report.json`,
+	RunE: runRNG,
+}
+
+func init() {
+	coverageCmd.Flags().StringVarP(&dir, "dir", "d", "", "levels directory to scan (default: assets/levels)")
+	coverageCmd.Flags().StringVar(&outPath, "out", "", "write the report as JSON to this file")
+	coverageCmd.Flags().IntVar(&top, "top", 5, "number of most and least used cells to list per grid size")
+	analyzeCmd.AddCommand(coverageCmd)
+
+	blockingCmd.Flags().IntVarP(&levelID, "id", "i", 0, "Level ID to analyze (uses assets/levels/level_<id>.json)")
+	blockingCmd.Flags().StringVarP(&filePath, "file", "f", "", "Path to a level JSON file to analyze")
+	blockingCmd.Flags().StringVar(&dotPath, "dot", "", "write the blocking graph in Graphviz DOT format to this file")
+	analyzeCmd.AddCommand(blockingCmd)
+
+	moduleCmd.Flags().IntVarP(&moduleID, "id", "i", 0, "Module ID to analyze (from modules.json)")
+	moduleCmd.Flags().IntVar(&maxStates, "max-states", 200000, "solver state budget per level")
+	moduleCmd.Flags().Float64Var(&tolerance, "tolerance", 0.25, "fraction a level's score may fall below an earlier level's before it is flagged")
+	moduleCmd.Flags().StringVar(&outPath, "out", "", "write the curve as JSON to this file")
+	analyzeCmd.AddCommand(moduleCmd)
+
+	rngCmd.Flags().IntVar(&samples, "samples", 500, "levels generated per difficulty")
+	rngCmd.Flags().StringSliceVar(&difficulties, "difficulty", nil, "difficulty tiers to sample (repeatable; default: all five)")
+	rngCmd.Flags().IntVar(&rngLevelID, "level-id", 1, "level ID used for grid sizing and the default start seed")
+	rngCmd.Flags().Int64Var(&startSeed, "start-seed", 0, "first seed per tier (0 uses the batch default for --level-id)")
+	rngCmd.Flags().StringVar(&strategy, "strategy", "", "placement strategy (default: batch default)")
+	rngCmd.Flags().StringVar(&rngName, "rng", "", "random source: math (default), pcg or splitmix")
+	rngCmd.Flags().IntVar(&pairs, "pairs", 0, "seeds regenerated with one bit flipped for the avalanche test (default: samples/10, at least 10)")
+	rngCmd.Flags().Float64Var(&alpha, "alpha", 0.01, "significance level below which a test is flagged")
+	rngCmd.Flags().StringVar(&outPath, "out", "", "write the report as JSON to this file")
+	analyzeCmd.AddCommand(rngCmd)
+}
+
+// GetCommand returns the analyze command for registration with root
+func GetCommand() *cobra.Command {
+	return analyzeCmd
+}
+
+func runCoverage(cmd *cobra.Command, args []string) error {
+	scanDir := dir
+	if scanDir == "" {
+		levelsDir, err := common.LevelsDir()
+		if err != nil {
+			return fmt.Errorf("failed to resolve levels directory: %w", err)
+		}
+		scanDir = levelsDir
+	}
+
+	levels, err := common.ReadLevelsFromDir(scanDir)
+	if err != nil {
+		return err
+	}
+	report := analyze.Coverage(levels)
+	analyze.WriteText(cmd.OutOrStdout(), report, top)
+
+	if outPath == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(outPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outPath, err)
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nWrote report to %s\n", outPath)
+	return nil
+}
+
+func runBlocking(cmd *cobra.Command, args []string) error {
+	path := filePath
+	if path == "" {
+		if levelID == 0 {
+			return fmt.Errorf("please provide --id or --file")
+		}
+		var err error
+		if path, err = common.LevelFilePath(levelID); err != nil {
+			return fmt.Errorf("failed to resolve level file path: %w", err)
+		}
+	}
+	level, err := common.ReadLevel(path)
+	if err != nil {
+		return fmt.Errorf("failed to read level file: %w", err)
+	}
+
+	graph := analyze.Blocking(level)
+	graph.WriteText(cmd.OutOrStdout())
+	if dotPath == "" {
+		return nil
+	}
+
+	f, err := os.Create(dotPath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dotPath, err)
+	}
+	if err := graph.WriteDOT(f); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write %s: %w", dotPath, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dotPath, err)
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nWrote blocking graph to %s\n", dotPath)
+	return nil
+}
+
+func runModule(cmd *cobra.Command, args []string) error {
+	if moduleID == 0 {
+		return fmt.Errorf("please provide --id")
+	}
+	if tolerance < 0 || tolerance >= 1 {
+		return fmt.Errorf("--tolerance must be in [0, 1), got %g", tolerance)
+	}
+	modulesFile, err := common.ModulesFile()
+	if err != nil {
+		return fmt.Errorf("failed to resolve modules file: %w", err)
+	}
+	assetsDir, err := common.AssetsDir()
+	if err != nil {
+		return fmt.Errorf("failed to resolve assets directory: %w", err)
+	}
+	registry, err := common.LoadModuleRegistry(modulesFile)
+	if err != nil {
+		return err
+	}
+	mod, err := common.GetModuleByID(registry, moduleID)
+	if err != nil {
+		return err
+	}
+
+	load := func(key string) (*model.Level, error) {
+		rel, ok := registry.LevelMappings[key]
+		if !ok {
+			return nil, fmt.Errorf("no level_mappings entry for %s", key)
+		}
+		return common.ReadLevel(filepath.Join(assetsDir, rel))
+	}
+	curve, err := analyze.Module(cmd.Context(), *mod, load, maxStates, tolerance)
+	if err != nil {
+		return err
+	}
+	curve.WriteText(cmd.OutOrStdout())
+
+	if outPath != "" {
+		data, err := json.MarshalIndent(curve, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(outPath, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", outPath, err)
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nWrote module curve to %s\n", outPath)
+	}
+
+	if len(curve.Regressions) > 0 {
+		return fmt.Errorf("module %d has %d difficulty regressions", moduleID, len(curve.Regressions))
+	}
+	return nil
+}
+
+func runRNG(cmd *cobra.Command, args []string) error {
+	if samples < 2 {
+		return fmt.Errorf("--samples must be at least 2, got %d", samples)
+	}
+	if alpha <= 0 || alpha >= 1 {
+		return fmt.Errorf("--alpha must be in (0, 1), got %g", alpha)
+	}
+	if err := random.Check(rngName); err != nil {
+		return err
+	}
+
+	spin := ui.NewSpinner("Sampling levels...")
+	spin.Start()
+	report, err := explore.RNG(cmd.Context(), explore.RNGOptions{
+		Difficulties:   difficulties,
+		Samples:        samples,
+		LevelID:        rngLevelID,
+		StartSeed:      startSeed,
+		Strategy:       strategy,
+		RNG:            rngName,
+		AvalanchePairs: pairs,
+		Alpha:          alpha,
+		Workers:        common.Workers,
+		OnProgress: func(done, total int) {
+			spin.UpdateMessage("Sampling levels (%d/%d)...", done, total)
+		},
+	})
+	spin.Stop()
+	if err != nil {
+		return fmt.Errorf("rng analysis failed: %w", err)
+	}
+	report.WriteText(cmd.OutOrStdout())
+
+	if outPath != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(outPath, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", outPath, err)
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nWrote report to %s\n", outPath)
+	}
+
+	if biases := report.Biases(); len(biases) > 0 {
+		return fmt.Errorf("%d significant biases in generator output", len(biases))
+	}
+	return nil
+}
@@ -17,6 +17,13 @@ Features:
   - Per-level validation with fail-fast error reporting
   - Dry-run mode for previewing generation without writing files
   - LIFO mode for guaranteed solvability and 100% coverage
+  - Mirror mode emitting a verified reflected companion for every level
+  - Resume mode continuing an interrupted run from generation_metadata.json
+  - Mask mode selection (hide or show) for levels with unfilled cells
+  - Generation profiles (aesthetic, dense, speedrun) bundling placement settings
+  - Board theme metadata (biome, decoration density, accent cells) derived
+    from the module's theme_seed in modules.json
+  - Prometheus textfile metrics for monitoring scheduled runs (--metrics-out)
 
 Usage examples:
 
@@ -24,6 +31,10 @@ Usage examples:
 	level-builder batch --module 2 --lifo --overwrite
 	level-builder batch --module 3 --dry-run
 	level-builder batch --module 4 --backup
+	level-builder batch --module 5 --mirror --overwrite
+	level-builder batch --module 2 --resume
+	level-builder batch --module 3 --mask-mode show
+	level-builder batch --module 2 --metrics-out /var/lib/node_exporter/level_builder_batch.prom
 
 The command generates levels sequentially, validates each immediately after generation,
 and reports a summary of success/failure statistics at the end.
@@ -34,12 +45,21 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	batchsvc "github.com/eng618/parable-bloom/tools/level-builder/pkg/batch"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/constraints"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator"
+	genconfig "github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/random"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/strategies"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/prom"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/ui"
 )
 
 var (
@@ -55,6 +75,32 @@ var (
 	minCoverage float64
 	outputDir   string
 	strategy    string
+	profile     string
+	filler      string
+	maskMode    string
+	stencilPath string
+	portals     bool
+	hints       int
+	inject      bool
+	optimize    int
+	rngName     string
+	rngTrace    string
+	// Mirror options
+	mirror         bool
+	mirrorAxis     string
+	mirrorIDOffset int
+	// Difficulty calibration
+	noDifficultyCheck bool
+	constraintsPath   string
+	minAesthetics     float64
+	// Multi-seed racing
+	race int
+	// Resume an interrupted run
+	resume bool
+	// Live progress display
+	tui bool
+	// Prometheus textfile metrics
+	metricsOut string
 )
 
 // batchCmd represents the batch command
@@ -72,13 +118,99 @@ For module N, level IDs are (N-1)*21+1 through (N-1)*21+21.
 
 The command generates levels sequentially, validates each immediately,
 updates modules.json with the new level array, and optionally backs up
-existing level files.
+existing level files. Levels whose deepest blocking chain or difficulty
+score (blocking depth, forced moves, solution length, solver effort) falls
+outside their tier's band are regenerated unless --no-difficulty-check is set.
+
+Cells left empty are masked. --mask-mode hide (default) lists the hidden
+cells; --mask-mode show lists the occupied cells instead, so the level
+carries an explicit playable region.
+
+--stencil FILE leaves a pattern such as letters or an icon empty but
+visible in every level, as decorative gaps. The file draws the pattern one
+grid row per line, top row first: '.' or a space is a playable cell and any
+other character is a gap. It is centered on each level's grid and may leave
+at most half of it empty:
+  #...#
+  .#.#.
+  ..#..
+Vines never occupy the gaps, and every other cell is covered, so stencil
+levels have no hidden cells and --mask-mode does not apply. Without
+--strategy or a profile strategy, levels use full-coverage placement;
+direction-first, center-out and chain also keep out of the gaps.
+
+--portals links 1-2 pairs of empty cells with portals on Nurturing and
+higher tiers: a vine head entering one portal continues from its twin. Only
+pairs that keep the level solvable are added, and the game must support the
+portals field before such levels ship.
+
+--inject-difficulty runs a post-pass that reverses or moves placed vines,
+one at a time, to deepen the longest blocker chain toward the middle of the
+tier's blocking depth range. Each edit is checked for blocking cycles, so
+the level stays solvable.
+
+--optimize-iterations N runs N simulated annealing moves over each
+placement: vines are reversed, regrown, or have their tails grown or
+trimmed, scored on coverage, aesthetics and distance from the tier's
+blocking depth target. Only solvable placements are visited and the best
+one is kept.
+
+--hints N embeds the first N vines of a solution as the level's hints, so
+the game can offer hints without a solver of its own.
+
+--profile picks a bundle of generation settings for a style of level:
+  aesthetic  long winding vines from direction-first placement
+  dense      full-coverage placement filling every cell
+  speedrun   as few vines as the tier allows, for short solutions
+The profile sets the strategy, coverage target, backtracking and vine-length
+mix; --strategy, --min-coverage and --aggressive still override it. Each
+level records its profile as generation_profile. Profiles shift vine counts,
+so pair them with --no-difficulty-check if levels keep leaving their band.
+
+--race N generates each level from N base seeds at once and keeps the
+first valid level, cancelling the rest. It shortens the slow tail of hard
+tiers such as Transcendent, but which seed wins depends on timing, so raced
+levels cannot be regenerated from their level ID alone.
+
+--constraints FILE attaches hand-authored requirements, one per line, and
+levels are regenerated until they meet them. Lines before any section apply
+to every level; [Sprout] scopes lines to a tier and [level 30] or
+[levels 30-32] to level IDs:
+  no heads pointing down
+  [Flourishing]
+  at least 2 multi-head vines
+  [level 30]
+  exactly 2 vines longer than 8
+  solution length >= 15
+Metrics: vines, vines longer/shorter than N, vines of length N, multi-head
+vines, locked vines, longest/shortest vine, heads pointing DIR, portals,
+solution length, blocking depth, coverage, grid width, grid height.
+
+Progress for every level is recorded in generation_metadata.json in the
+output directory as the run goes. If a run is interrupted or some levels
+fail, --resume skips levels recorded as done whose files still validate and
+generates the rest, starting from the first missing or failed level.
+
+When stdout is a terminal, a live display shows one progress bar per level
+(attempts spent, strategy, relaxations) with the module's elapsed time and
+ETA. It is off with --tui=false, --verbose or --log-format json.
 
 Examples:
   level-builder batch --module 1
   level-builder batch --module 2 --lifo --overwrite
   level-builder batch --module 3 --dry-run
-  level-builder batch --module 4 --backup`,
+  level-builder batch --module 4 --backup
+  level-builder batch --module 5 --mirror --overwrite
+  level-builder batch --module 2 --resume
+  level-builder batch --module 3 --mask-mode show
+  level-builder batch --module 3 --stencil heart.txt
+  level-builder batch --module 4 --portals
+  level-builder batch --module 2 --hints 3
+  level-builder batch --module 3 --profile aesthetic
+  level-builder batch --module 3 --rng pcg
+  level-builder batch --module 4 --min-aesthetics 60
+  level-builder batch --module 5 --race 4
+  level-builder batch --module 2 --constraints module_2.constraints`,
 	RunE: runBatch,
 }
 
@@ -87,7 +219,7 @@ func init() {
 	batchCmd.Flags().BoolVar(&overwrite, "overwrite", false, "overwrite existing level files")
 	batchCmd.Flags().BoolVar(&useLIFO, "lifo", false, "use LIFO mode for guaranteed solvability and 100% coverage")
 	batchCmd.Flags().BoolVar(&dryRun, "dry-run", false, "preview what would be generated without writing files")
-	batchCmd.Flags().BoolVar(&backup, "backup", true, "backup existing levels before overwriting")
+	batchCmd.Flags().BoolVar(&backup, "backup", true, "back up overwritten levels to <out>/.backups (see restore)")
 
 	// New flags to support aggressive LIFO runs and dump directory
 	batchCmd.Flags().BoolVar(&aggressive, "aggressive", false, "enable aggressive backtracking defaults for batch runs (window=6 attempts=6)")
@@ -95,8 +227,29 @@ func init() {
 	batchCmd.Flags().StringVar(&statsOut, "stats-out", "", "optional directory to write per-level generation stats JSON files")
 	batchCmd.Flags().Float64Var(&minCoverage, "min-coverage", 0.0, "optional override for minimum coverage (0.0-1.0). 0 means no override")
 	// Optional explicit output directory for generated level files (absolute or relative)
-	batchCmd.Flags().StringVar(&outputDir, "output-dir", "", "directory to write generated level files (default: assets/levels)")
-	batchCmd.Flags().StringVar(&strategy, "strategy", "", "force a specific placement strategy for all levels (direction-first, center-out)")
+	batchCmd.Flags().StringVar(&outputDir, "output-dir", "", "directory to write generated level files (default: the workspace's assets/levels)")
+	batchCmd.Flags().StringVar(&strategy, "strategy", "", "force a specific placement strategy for all levels (direction-first, center-out, full-coverage, chain)")
+	batchCmd.Flags().BoolVar(&noDifficultyCheck, "no-difficulty-check", false, "accept levels whose blocking depth or difficulty score falls outside their tier's band")
+	batchCmd.Flags().Float64Var(&minAesthetics, "min-aesthetics", 0, "regenerate levels whose aesthetics score (0-100) is below this; 0 disables the check")
+	batchCmd.Flags().StringVar(&constraintsPath, "constraints", "", "constraints file of hand-authored level requirements; levels are regenerated until they meet them")
+	batchCmd.Flags().BoolVar(&resume, "resume", false, "skip levels already written and validated by a previous run (see generation_metadata.json)")
+	batchCmd.Flags().StringVar(&profile, "profile", "", "generation profile: "+strings.Join(genconfig.ProfileNames(), ", ")+" (default none)")
+	batchCmd.Flags().StringVar(&filler, "filler-strategy", "", "gap filler used by center-out placement (lifo, gap; default lifo)")
+	batchCmd.Flags().StringVar(&maskMode, "mask-mode", model.MaskModeHide, "how empty cells are masked: hide (list hidden cells) or show (list the playable region)")
+	batchCmd.Flags().StringVar(&stencilPath, "stencil", "", "pattern file of decorative gaps left empty but visible in every level")
+	batchCmd.Flags().BoolVar(&portals, "portals", false, "link empty cells with portal pairs on Nurturing and higher tiers")
+	batchCmd.Flags().BoolVar(&tui, "tui", true, "show live per-level progress bars when stdout is a terminal")
+	batchCmd.Flags().BoolVar(&inject, "inject-difficulty", false, "after placement, reverse or move vines to deepen blocker chains toward the tier's target")
+	batchCmd.Flags().IntVar(&optimize, "optimize-iterations", 0, "anneal each placement for N moves, trading coverage, aesthetics and blocking depth (0 = off)")
+	batchCmd.Flags().IntVar(&hints, "hints", 0, "embed the first N vines of a solution in each level as hints (0 = none)")
+	batchCmd.Flags().StringVar(&rngName, "rng", random.Default, "random source seeds are expanded with: "+strings.Join(random.Names(), ", "))
+	batchCmd.Flags().IntVar(&race, "race", 0, "race N seeds per level and keep the first valid level; faster on hard tiers but not reproducible from the level ID")
+	batchCmd.Flags().StringVar(&metricsOut, "metrics-out", "", "write generation metrics in Prometheus textfile format to this file (e.g. for node_exporter)")
+	batchCmd.Flags().StringVar(&rngTrace, "rng-trace", "", "debug: write every random draw and its call site per attempt to this directory (compare runs with rngdiff)")
+
+	batchCmd.Flags().BoolVar(&mirror, "mirror", false, "also emit a verified mirrored companion for each level and pair them in modules.json")
+	batchCmd.Flags().StringVar(&mirrorAxis, "mirror-axis", common.MirrorHorizontal, "mirror axis: horizontal or vertical")
+	batchCmd.Flags().IntVar(&mirrorIDOffset, "mirror-id-offset", batchsvc.DefaultMirrorIDOffset, "offset added to a level ID to form its mirror's ID")
 
 	_ = batchCmd.MarkFlagRequired("module")
 }
@@ -111,6 +264,39 @@ func runBatch(cmd *cobra.Command, args []string) error {
 	if err := validateModuleID(moduleID); err != nil {
 		return err
 	}
+	if filler != "" {
+		if _, err := strategies.GetFiller(filler); err != nil {
+			return err
+		}
+	}
+	if err := model.CheckMaskMode(maskMode); err != nil {
+		return err
+	}
+	if profile != "" {
+		if _, err := genconfig.ProfileFor(profile); err != nil {
+			return err
+		}
+	}
+	if err := random.Check(rngName); err != nil {
+		return err
+	}
+	var levelConstraints *constraints.File
+	if constraintsPath != "" {
+		var err error
+		if levelConstraints, err = constraints.ParseFile(constraintsPath); err != nil {
+			return fmt.Errorf("invalid --constraints: %w", err)
+		}
+	}
+	var stencil *model.Stencil
+	if stencilPath != "" {
+		data, err := common.ReadInput(stencilPath)
+		if err != nil {
+			return fmt.Errorf("failed to read --stencil: %w", err)
+		}
+		if stencil, err = model.ParseStencil(string(data)); err != nil {
+			return fmt.Errorf("invalid --stencil: %w", err)
+		}
+	}
 
 	// If user did not provide a dump dir or stats-out, emit into a timestamped
 	// directory under the root logs/ directory.
@@ -125,9 +311,15 @@ func runBatch(cmd *cobra.Command, args []string) error {
 		common.Info("No --stats-out provided, defaulting to %s", statsOut)
 	}
 
-	config := buildConfig()
+	config, err := buildConfig()
+	if err != nil {
+		return err
+	}
 	config.DumpDir = dumpDir
 	config.StatsOut = statsOut
+	config.Constraints = levelConstraints
+	config.Stencil = stencil
+	config.ThemeSeed = moduleThemeSeed(moduleID)
 
 	// Ensure dump and stats directories exist
 	if err := os.MkdirAll(config.DumpDir, 0o755); err != nil {
@@ -138,14 +330,31 @@ func runBatch(cmd *cobra.Command, args []string) error {
 	}
 
 	levelIDs := buildModuleLevelIDs(moduleID)
-	performBackupGuarded(levelIDs, config, backup, dryRun)
+	// Levels this run replaces are copied to one backup under the output
+	// directory's .backups, which `level-builder restore` rolls back to
+	common.BackupOnOverwrite = backup && !dryRun && config.Overwrite
 
 	// Generate the module
-	batchResult, err := batchsvc.GenerateModule(config)
+	var displayed <-chan struct{}
+	if tui && ui.LiveDisplay() {
+		events := make(chan batchsvc.ProgressEvent)
+		config.Progress = events
+		displayed = showProgress(moduleID, events)
+	}
+	batchResult, err := batchsvc.GenerateModule(cmd.Context(), config)
+	if displayed != nil {
+		<-displayed
+	}
+	writeMetrics(batchResult, err == nil && batchResult.FailureCount == 0)
 	if err != nil {
 		return err
 	}
 
+	reportBudget(batchResult, config.StatsOut, dryRun)
+	if len(batchResult.Strategies) > 0 {
+		batchsvc.WriteStrategyText(os.Stdout, batchResult.Strategies)
+	}
+
 	if err := reportSummary(batchResult); err != nil {
 		return err
 	}
@@ -155,7 +364,7 @@ func runBatch(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	if err := updateModulesRegistry(moduleID, levelIDs); err != nil {
+	if err := updateModulesRegistry(moduleID, levelIDs, batchResult); err != nil {
 		return err
 	}
 
@@ -170,23 +379,45 @@ func validateModuleID(id int) error {
 	return nil
 }
 
-func buildConfig() batchsvc.Config {
+func buildConfig() (batchsvc.Config, error) {
 	out := outputDir
 	if out == "" {
-		out = "assets/levels"
+		levelsDir, err := common.LevelsDir()
+		if err != nil {
+			return batchsvc.Config{}, fmt.Errorf("failed to resolve levels directory: %w", err)
+		}
+		out = levelsDir
 	}
 	return batchsvc.Config{
-		ModuleID:    moduleID,
-		UseLIFO:     useLIFO,
-		Overwrite:   overwrite,
-		DryRun:      dryRun,
-		OutputDir:   out,
-		Aggressive:  aggressive,
-		DumpDir:     dumpDir,
-		StatsOut:    statsOut,
-		MinCoverage: minCoverage,
-		Strategy:    strategy,
-	}
+		ModuleID:       moduleID,
+		UseLIFO:        useLIFO,
+		Overwrite:      overwrite,
+		DryRun:         dryRun,
+		OutputDir:      out,
+		Aggressive:     aggressive,
+		DumpDir:        dumpDir,
+		StatsOut:       statsOut,
+		MinCoverage:    minCoverage,
+		Strategy:       strategy,
+		Profile:        profile,
+		FillerStrategy: filler,
+		MaskMode:       maskMode,
+		Portals:        portals,
+		Hints:          hints,
+		RNG:            rngName,
+		RNGTraceDir:    rngTrace,
+		Mirror:         mirror,
+		MirrorAxis:     mirrorAxis,
+		MirrorIDOffset: mirrorIDOffset,
+
+		SkipDifficultyCheck: noDifficultyCheck,
+		InjectDifficulty:    inject,
+		OptimizeIterations:  optimize,
+		MinAesthetics:       minAesthetics,
+		Race:                race,
+		Resume:              resume,
+		Workers:             common.Workers,
+	}, nil
 }
 
 func buildModuleLevelIDs(moduleID int) []int {
@@ -198,24 +429,26 @@ func buildModuleLevelIDs(moduleID int) []int {
 	return levelIDs
 }
 
-func performBackup(levelIDs []int, sourceDir string) {
-	if _, err := common.BackupLevels(levelIDs, sourceDir, "assets/levels_backup"); err != nil {
-		common.Warning("Backup failed: %v (continuing anyway)", err)
-	}
-}
-
-func performBackupGuarded(levelIDs []int, cfg batchsvc.Config, doBackup bool, isDryRun bool) {
-	if !doBackup || isDryRun || !cfg.Overwrite {
-		return
+// moduleThemeSeed returns the module's theme_seed from modules.json, or the
+// generator's default for the module when the registry has none.
+func moduleThemeSeed(moduleID int) string {
+	if modulesPath, err := common.ModulesFile(); err == nil {
+		if registry, err := common.LoadModuleRegistry(modulesPath); err == nil {
+			for _, mod := range registry.Modules {
+				if mod.ID == moduleID && mod.ThemeSeed != "" {
+					return mod.ThemeSeed
+				}
+			}
+		}
 	}
-	performBackup(levelIDs, cfg.OutputDir)
+	return generator.ThemeSeedFor(moduleID)
 }
 
 func logicalLevelID(levelID int) string {
 	return common.LogicalLevelID(levelID)
 }
 
-func updateModulesRegistry(moduleID int, levelIDs []int) error {
+func updateModulesRegistry(moduleID int, levelIDs []int, batchResult *batchsvc.ModuleBatch) error {
 	modulesPath, err := common.ModulesFile()
 	if err != nil {
 		return fmt.Errorf("failed to resolve modules.json path: %w", err)
@@ -245,6 +478,7 @@ func updateModulesRegistry(moduleID int, levelIDs []int) error {
 			registry.Modules[i].Levels = logicalKeys[:len(logicalKeys)-1]
 			// 21st level is the Transcendent challenge
 			registry.Modules[i].ChallengeLevel = logicalKeys[len(logicalKeys)-1]
+			registerMirrors(registry, &registry.Modules[i], batchResult)
 			found = true
 			break
 		}
@@ -262,14 +496,108 @@ func updateModulesRegistry(moduleID int, levelIDs []int) error {
 	return nil
 }
 
+// registerMirrors maps each written mirror level and pairs it with its source.
+func registerMirrors(registry *model.ModuleRegistry, mod *model.Module, batchResult *batchsvc.ModuleBatch) {
+	for _, result := range batchResult.Levels {
+		if result.MirrorLevelID == 0 {
+			continue
+		}
+		if mod.Mirrors == nil {
+			mod.Mirrors = make(map[string]string)
+		}
+		mirrorKey := common.MirrorLogicalLevelID(result.LevelID)
+		registry.LevelMappings[mirrorKey] = fmt.Sprintf("levels/level_%d.json", result.MirrorLevelID)
+		mod.Mirrors[logicalLevelID(result.LevelID)] = mirrorKey
+	}
+}
+
+// reportBudget prints the per-tier budget report and saves it alongside the stats.
+func reportBudget(batchResult *batchsvc.ModuleBatch, statsDir string, isDryRun bool) {
+	report := batchsvc.BuildBudgetReport(batchResult.Levels)
+	report.WriteText(os.Stdout)
+	if isDryRun || statsDir == "" {
+		return
+	}
+	path := filepath.Join(statsDir, "budget_report.json")
+	if err := report.WriteJSON(path); err != nil {
+		common.Warning("Failed to write budget report: %v", err)
+		return
+	}
+	common.Info("Wrote budget report: %s", path)
+}
+
+// writeMetrics writes the run's metrics to --metrics-out, if set. A failed
+// run without results still records that it ran and failed.
+func writeMetrics(batchResult *batchsvc.ModuleBatch, ok bool) {
+	if metricsOut == "" {
+		return
+	}
+	reg := prom.NewRegistry()
+	if batchResult != nil {
+		reg.ObserveBatch(batchResult)
+	}
+	reg.MarkRun("batch", time.Now(), ok)
+	if err := reg.WriteFile(metricsOut); err != nil {
+		common.Warning("%v", err)
+		return
+	}
+	common.Verbose("Wrote metrics: %s", metricsOut)
+}
+
 func reportSummary(batchResult *batchsvc.ModuleBatch) error {
 	common.Info("\n=== Batch Generation Summary ===")
 	common.Info("Module: %d", batchResult.ModuleID)
 	common.Info("Total Time: %v", batchResult.TotalTime)
 	common.Info("Success: %d / %d", batchResult.SuccessCount, len(batchResult.Levels))
 	common.Info("Failures: %d", batchResult.FailureCount)
+	if batchResult.ResumedCount > 0 {
+		common.Info("Resumed: %d levels reused from a previous run", batchResult.ResumedCount)
+	}
+	if batchResult.MirrorCount > 0 || batchResult.MirrorFails > 0 {
+		common.Info("Mirrors: %d written, %d failed", batchResult.MirrorCount, batchResult.MirrorFails)
+	}
+	rejections := 0
+	for _, result := range batchResult.Levels {
+		rejections += result.DifficultyRejections
+	}
+	if rejections > 0 {
+		common.Info("Difficulty rejections: %d (regenerated out-of-band levels)", rejections)
+	}
+	rejections = 0
+	for _, result := range batchResult.Levels {
+		rejections += result.DepthRejections
+	}
+	if rejections > 0 {
+		common.Info("Depth rejections: %d (regenerated levels with too shallow or deep blocking chains)", rejections)
+	}
+	rejections = 0
+	for _, result := range batchResult.Levels {
+		rejections += result.ConstraintRejections
+	}
+	if rejections > 0 {
+		common.Info("Constraint rejections: %d (regenerated levels that missed a constraint)", rejections)
+	}
+	rejections = 0
+	for _, result := range batchResult.Levels {
+		rejections += result.AestheticRejections
+	}
+	if rejections > 0 {
+		common.Info("Aesthetic rejections: %d (regenerated levels below --min-aesthetics)", rejections)
+	}
+
+	if batchResult.MirrorFails > 0 {
+		common.Warning("\nFailed mirrors:")
+		for _, result := range batchResult.Levels {
+			if result.MirrorError != "" {
+				common.Warning("  Level %d: %s", result.LevelID, result.MirrorError)
+			}
+		}
+	}
 
 	if batchResult.FailureCount == 0 {
+		if batchResult.MirrorFails > 0 {
+			return fmt.Errorf("batch generation completed with %d mirror failures", batchResult.MirrorFails)
+		}
 		return nil
 	}
 
@@ -279,5 +607,6 @@ func reportSummary(batchResult *batchsvc.ModuleBatch) error {
 			common.Warning("  Level %d (%s): %s", result.LevelID, result.Difficulty, result.Error)
 		}
 	}
+	common.Warning("Re-run with --resume to retry only the failed levels.")
 	return fmt.Errorf("batch generation completed with %d failures", batchResult.FailureCount)
 }
@@ -0,0 +1,72 @@
+package batch
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	batchsvc "github.com/eng618/parable-bloom/tools/level-builder/pkg/batch"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/ui"
+)
+
+// showProgress draws a live board from events, one bar per level, until the
+// channel closes. The returned channel closes once the final frame is drawn.
+func showProgress(moduleID int, events <-chan batchsvc.ProgressEvent) <-chan struct{} {
+	done := make(chan struct{})
+	board := ui.NewBoard(os.Stdout)
+	go func() {
+		defer close(done)
+		defer board.Close()
+		for e := range events {
+			if e.LevelID == 0 {
+				board.Println(e.Message)
+			} else {
+				label := fmt.Sprintf("Level %d %s", e.LevelID, e.Difficulty)
+				fraction, status := levelStatus(e)
+				board.SetRow(e.LevelID, label, fraction, status)
+				if e.State == batchsvc.LevelFailed && e.Message != "" {
+					board.Println(fmt.Sprintf("✗ Level %d: %s", e.LevelID, e.Message))
+				}
+			}
+			eta := "estimating"
+			if e.Completed == e.Total {
+				eta = "0s"
+			} else if e.ETA > 0 {
+				eta = e.ETA.Round(time.Second).String()
+			}
+			board.SetFooter("Module %d: %d/%d levels · elapsed %s · ETA %s",
+				moduleID, e.Completed, e.Total, e.Elapsed.Round(time.Second), eta)
+		}
+	}()
+	return done
+}
+
+// levelStatus returns a level's bar fraction and status text. A running
+// level's bar fills as it spends its attempt budget.
+func levelStatus(e batchsvc.ProgressEvent) (float64, string) {
+	switch e.State {
+	case batchsvc.LevelQueued:
+		return 0, "queued"
+	case batchsvc.LevelDone:
+		if e.Strategy == "" {
+			return 1, "✓ done"
+		}
+		return 1, fmt.Sprintf("✓ %s, %d attempts", e.Strategy, e.Attempts)
+	case batchsvc.LevelFailed:
+		return 1, fmt.Sprintf("✗ failed after %d attempts", e.Attempts)
+	case batchsvc.LevelResumed:
+		return 1, "↺ resumed"
+	}
+	if e.Strategy == "" {
+		return 0, "starting"
+	}
+	fraction := 0.0
+	if e.MaxAttempts > 0 {
+		fraction = float64(e.Attempts) / float64(e.MaxAttempts)
+	}
+	status := fmt.Sprintf("%s attempt %d (%d/%d)", e.Strategy, e.Attempt, e.Attempts, e.MaxAttempts)
+	if e.Relaxations > 0 {
+		status += fmt.Sprintf(", %d relaxed", e.Relaxations)
+	}
+	return fraction, status
+}
@@ -0,0 +1,163 @@
+package bench
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/bench"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/ui"
+)
+
+var (
+	suiteName      string
+	baselinePath   string
+	updateBaseline bool
+	outPath        string
+	levelID        int
+	maxStates      int
+	thresholds     = bench.DefaultThresholds()
+)
+
+// benchCmd represents the bench command
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Benchmark generation against a stored baseline",
+	Long: `Run a fixed matrix of seeds and difficulties through each placement strategy
+and compare the results with a stored baseline.
+
+Every case generates one level with a single strategy (no fallback) and
+verifies it with the solver, recording generation and solve time, coverage,
+placement attempts and solver states. Cases run one at a time so timings are
+comparable between runs.
+
+Results are summed per difficulty and strategy. A group regresses when it
+fails more cases than the baseline, or when its time, placement attempts or
+solver states grow beyond the threshold ratios, or its mean coverage drops by
+more than --max-coverage-drop points. Any regression makes the command exit
+non-zero.
+
+Suites: ` + strings.Join(bench.SuiteNames(), ", ") + `
+
+Examples:
+  level-builder bench --suite standard --baseline bench_baseline.json
+  level-builder bench --suite standard --baseline bench_baseline.json --update-baseline
+  level-builder bench --suite quick --out /tmp/bench.json`,
+	RunE: runBench,
+}
+
+func init() {
+	benchCmd.Flags().StringVar(&suiteName, "suite", "standard", "benchmark suite to run ("+strings.Join(bench.SuiteNames(), ", ")+")")
+	benchCmd.Flags().StringVar(&baselinePath, "baseline", "", "baseline report to compare against")
+	benchCmd.Flags().BoolVar(&updateBaseline, "update-baseline", false, "save this run as the new --baseline instead of comparing")
+	benchCmd.Flags().StringVar(&outPath, "out", "", "also write this run's report here")
+	benchCmd.Flags().IntVar(&levelID, "level-id", 1, "level ID used for grid sizing")
+	benchCmd.Flags().IntVar(&maxStates, "max-states", bench.DefaultMaxStates, "solver budget per level")
+	benchCmd.Flags().Float64Var(&thresholds.MaxTimeRatio, "max-time-ratio", thresholds.MaxTimeRatio, "allowed time growth per group (current/baseline)")
+	benchCmd.Flags().Float64Var(&thresholds.MinTimeDeltaMS, "min-time-delta-ms", thresholds.MinTimeDeltaMS, "ignore slowdowns smaller than this many milliseconds")
+	benchCmd.Flags().Float64Var(&thresholds.MaxCoverageDrop, "max-coverage-drop", thresholds.MaxCoverageDrop, "allowed drop in mean coverage (percentage points)")
+	benchCmd.Flags().Float64Var(&thresholds.MaxAttemptsRatio, "max-attempts-ratio", thresholds.MaxAttemptsRatio, "allowed placement attempts growth per group")
+	benchCmd.Flags().Float64Var(&thresholds.MaxStatesRatio, "max-states-ratio", thresholds.MaxStatesRatio, "allowed solver states growth per group")
+}
+
+// GetCommand returns the bench command for registration with root
+func GetCommand() *cobra.Command {
+	return benchCmd
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	suite, ok := bench.Suites[suiteName]
+	if !ok {
+		return fmt.Errorf("unknown suite %q (available: %s)", suiteName, strings.Join(bench.SuiteNames(), ", "))
+	}
+	if updateBaseline && baselinePath == "" {
+		return fmt.Errorf("--update-baseline requires --baseline")
+	}
+
+	spin := ui.NewSpinner(fmt.Sprintf("Running %s suite...", suite.Name))
+	spin.Start()
+	report, err := bench.Run(cmd.Context(), suite, bench.Options{
+		LevelID:   levelID,
+		MaxStates: maxStates,
+		OnProgress: func(done, total int) {
+			spin.UpdateMessage("Running %s suite (%d/%d)...", suite.Name, done, total)
+		},
+	})
+	spin.Stop()
+	if err != nil {
+		return fmt.Errorf("benchmark failed: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	writeGroups(out, report.Groups())
+
+	if outPath != "" {
+		if err := report.Save(outPath); err != nil {
+			return fmt.Errorf("failed to write %s: %w", outPath, err)
+		}
+		_, _ = fmt.Fprintf(out, "\nWrote report to %s\n", outPath)
+	}
+
+	if baselinePath == "" {
+		return nil
+	}
+	if updateBaseline {
+		if err := report.Save(baselinePath); err != nil {
+			return fmt.Errorf("failed to write baseline %s: %w", baselinePath, err)
+		}
+		_, _ = fmt.Fprintf(out, "\nSaved baseline to %s\n", baselinePath)
+		return nil
+	}
+
+	if _, err := os.Stat(baselinePath); errors.Is(err, fs.ErrNotExist) {
+		_, _ = fmt.Fprintf(out, "\nNo baseline at %s; rerun with --update-baseline to record one.\n", baselinePath)
+		return nil
+	}
+	baseline, err := bench.LoadReport(baselinePath)
+	if err != nil {
+		return err
+	}
+	if baseline.Suite != report.Suite || baseline.LevelID != report.LevelID {
+		_, _ = fmt.Fprintf(out, "\nWarning: baseline ran suite %q at level %d; this run is suite %q at level %d\n",
+			baseline.Suite, baseline.LevelID, report.Suite, report.LevelID)
+	}
+
+	cmp := bench.Compare(baseline, report, thresholds)
+	writeComparison(out, cmp)
+	if regressions := cmp.Regressions(); len(regressions) > 0 {
+		return fmt.Errorf("%d regressions against %s", len(regressions), baselinePath)
+	}
+	return nil
+}
+
+func writeGroups(out io.Writer, groups []bench.Group) {
+	_, _ = fmt.Fprintf(out, "%-13s %-18s %-6s %-6s %-10s %-10s %-9s %-9s %s\n",
+		"Difficulty", "Strategy", "Cases", "Fails", "GenMS", "SolveMS", "Coverage", "Attempts", "States")
+	for _, g := range groups {
+		_, _ = fmt.Fprintf(out, "%-13s %-18s %-6d %-6d %-10.0f %-10.0f %-9.1f %-9d %d\n",
+			g.Difficulty, g.Strategy, g.Cases, g.Failures, g.GenerationMS, g.SolveMS,
+			g.MeanCoverage, g.PlacementAttempts, g.SolverStates)
+	}
+}
+
+func writeComparison(out io.Writer, cmp bench.Comparison) {
+	_, _ = fmt.Fprintln(out, "\nBaseline comparison:")
+	for _, d := range cmp.Deltas {
+		switch {
+		case d.Baseline == nil:
+			_, _ = fmt.Fprintf(out, "  NEW   %s\n", d.Current.Key())
+		case len(d.Problems) == 0:
+			_, _ = fmt.Fprintf(out, "  PASS  %s\n", d.Current.Key())
+		default:
+			_, _ = fmt.Fprintf(out, "  FAIL  %s: %s\n", d.Current.Key(), strings.Join(d.Problems, "; "))
+		}
+	}
+	for _, key := range cmp.Missing {
+		_, _ = fmt.Fprintf(out, "  GONE  %s (in baseline only)\n", key)
+	}
+}
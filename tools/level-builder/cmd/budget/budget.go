@@ -0,0 +1,64 @@
+package budget
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	batchsvc "github.com/eng618/parable-bloom/tools/level-builder/pkg/batch"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+)
+
+var (
+	statsDirs []string
+	jsonOut   string
+)
+
+// budgetCmd represents the budget command
+var budgetCmd = &cobra.Command{
+	Use:   "budget",
+	Short: "Aggregate per-tier generation cost across batch runs",
+	Long: `Build a per-tier generation budget report from batch stats directories.
+
+Each batch run writes per-level stats (level_*_stats.json) to its --stats-out
+directory, for failed levels as well as generated ones. Pass one directory
+per module to aggregate a whole campaign. The report shows failures, mean
+attempts, relaxations, backtracks, dumps and strategy
+fallbacks per tier, and flags tiers whose share of generation time is
+disproportionate to their share of levels.
+
+Examples:
+  level-builder budget --stats-dir logs/20260101_120000/runs/stats
+  level-builder budget --stats-dir run1/stats --stats-dir run2/stats --json-out budget.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		results, err := batchsvc.LoadResultsFromStats(statsDirs...)
+		if err != nil {
+			return fmt.Errorf("failed to load stats: %w", err)
+		}
+		if len(results) == 0 {
+			return fmt.Errorf("no level stats found in %v", statsDirs)
+		}
+
+		report := batchsvc.BuildBudgetReport(results)
+		report.WriteText(cmd.OutOrStdout())
+
+		if jsonOut != "" {
+			if err := report.WriteJSON(jsonOut); err != nil {
+				return err
+			}
+			common.Info("Wrote budget report: %s", jsonOut)
+		}
+		return nil
+	},
+}
+
+func init() {
+	budgetCmd.Flags().StringArrayVar(&statsDirs, "stats-dir", nil, "batch stats directory to include (repeatable, required)")
+	budgetCmd.Flags().StringVar(&jsonOut, "json-out", "", "optional path to write the report as JSON")
+	_ = budgetCmd.MarkFlagRequired("stats-dir")
+}
+
+// GetCommand returns the budget command for registration with root
+func GetCommand() *cobra.Command {
+	return budgetCmd
+}
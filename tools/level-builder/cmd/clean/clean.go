@@ -17,6 +17,7 @@ var cleanCmd = &cobra.Command{
 
 Deletes:
   - All level_*.json files in assets/levels/
+  - assets/levels/generation_metadata.json (batch progress)
   - assets/data/modules.json
 
 This is a destructive operation. Use with caution.
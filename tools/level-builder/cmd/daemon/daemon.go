@@ -0,0 +1,118 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/daemon"
+)
+
+var (
+	portFlag        int
+	hostFlag        string
+	concurrencyFlag int
+	queueSizeFlag   int
+	jobTimeoutFlag  time.Duration
+	retainFlag      int
+	maxStatesFlag   int
+	dumpDirFlag     string
+)
+
+// daemonCmd runs generation and validation as an HTTP service.
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run level generation and validation as a long-lived HTTP service",
+	Long: `Run generation and validation as an HTTP service so a backend can generate
+levels on demand (daily challenges, say) without spawning a process per level.
+
+Generation runs as jobs: submit a generation config, then poll the job or
+stream its progress. --concurrency workers take jobs from a queue of up to
+--queue-size; submissions beyond that get 503. Generated levels are returned
+in the job, never written to assets. Jobs are kept in memory only.
+
+  POST   /v1/jobs              submit a job (202 with the queued job)
+  GET    /v1/jobs              list jobs
+  GET    /v1/jobs/{id}         job status, stats and level once it succeeded
+  GET    /v1/jobs/{id}/events  NDJSON stream: an "event" line per attempt
+                               outcome, then a "result" line with the job
+  DELETE /v1/jobs/{id}         cancel a queued or running job
+  POST   /v1/validate          validate a level JSON body (?solvable=false skips the solver)
+  GET    /v1/health            worker and queue status
+
+A job request mirrors the batch generation options:
+
+  {"level_id": 900, "difficulty": "Nurturing", "seed": 20261017,
+   "strategy": "direction-first", "portals": true, "hints": 3,
+   "constraints": ["solution length >= 15"]}
+
+Examples:
+  level-builder daemon
+  level-builder daemon --port 9090 --concurrency 4 --job-timeout 2m
+  curl -s -d '{"level_id": 900, "difficulty": "Sprout"}' localhost:8090/v1/jobs
+  curl -N localhost:8090/v1/jobs/1/events`,
+	Args: cobra.NoArgs,
+	RunE: runDaemon,
+}
+
+func init() {
+	daemonCmd.Flags().IntVarP(&portFlag, "port", "p", 8090, "Port to listen on")
+	daemonCmd.Flags().StringVar(&hostFlag, "host", "localhost", "Interface to listen on")
+	daemonCmd.Flags().IntVar(&concurrencyFlag, "concurrency", 1, "Jobs generated at once")
+	daemonCmd.Flags().IntVar(&queueSizeFlag, "queue-size", 64, "Jobs that can wait for a worker before submissions are refused")
+	daemonCmd.Flags().DurationVar(&jobTimeoutFlag, "job-timeout", 5*time.Minute, "Time limit for each job (0 = none)")
+	daemonCmd.Flags().IntVar(&retainFlag, "retain", 256, "Finished jobs kept for lookup")
+	daemonCmd.Flags().IntVar(&maxStatesFlag, "max-states", 1000000, "Solver state budget for /v1/validate")
+	daemonCmd.Flags().StringVar(&dumpDirFlag, "dump-dir", "", "Where placers write failure dumps (default failing_dumps)")
+}
+
+// GetCommand returns the daemon command for registration with root
+func GetCommand() *cobra.Command {
+	return daemonCmd
+}
+
+func runDaemon(cmd *cobra.Command, args []string) error {
+	svc := daemon.New(daemon.Options{
+		Workers:    concurrencyFlag,
+		QueueSize:  queueSizeFlag,
+		JobTimeout: jobTimeoutFlag,
+		Retain:     retainFlag,
+		MaxStates:  maxStatesFlag,
+		DumpDir:    dumpDirFlag,
+	})
+
+	ln, err := net.Listen("tcp", net.JoinHostPort(hostFlag, strconv.Itoa(portFlag)))
+	if err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+	srv := &http.Server{
+		Handler:           svc.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	workersDone := make(chan struct{})
+	go func() {
+		svc.Run(ctx)
+		close(workersDone)
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdown)
+	}()
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Level daemon listening on http://%s with %d workers\n", ln.Addr(), concurrencyFlag)
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	<-workersDone
+	return nil
+}
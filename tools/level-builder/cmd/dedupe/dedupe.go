@@ -0,0 +1,78 @@
+package dedupe
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/fingerprint"
+)
+
+var (
+	dir            string
+	threshold      float64
+	includeMirrors bool
+	strict         bool
+)
+
+// dedupeCmd represents the dedupe command
+var dedupeCmd = &cobra.Command{
+	Use:   "dedupe",
+	Short: "Find duplicate and near-duplicate levels",
+	Long: `Scan a levels directory for puzzles that repeat each other.
+
+Every level is reduced to a canonical form that ignores vine order, vine IDs,
+colors and the eight rotations/reflections of the grid. Levels sharing a
+canonical form are reported as identical. Distinct levels are also compared
+cell by cell under each symmetry; pairs whose similarity reaches --threshold
+are reported as near-duplicates.
+
+Levels that declare themselves the mirror of another scanned level (mirror_of)
+are skipped unless --include-mirrors is set. With --strict the command fails
+when anything is flagged, for use in CI.
+
+Examples:
+  level-builder dedupe
+  level-builder dedupe --dir /tmp/regen --threshold 0.8
+  level-builder dedupe --strict --include-mirrors`,
+	RunE: runDedupe,
+}
+
+func init() {
+	dedupeCmd.Flags().StringVarP(&dir, "dir", "d", "", "levels directory to scan (default: assets/levels)")
+	dedupeCmd.Flags().Float64Var(&threshold, "threshold", fingerprint.DefaultThreshold, "similarity (0-1] at which distinct levels count as near-duplicates")
+	dedupeCmd.Flags().BoolVar(&includeMirrors, "include-mirrors", false, "also flag levels declared as mirrors of another level")
+	dedupeCmd.Flags().BoolVar(&strict, "strict", false, "exit with an error when duplicates are found")
+}
+
+// GetCommand returns the dedupe command for registration with root
+func GetCommand() *cobra.Command {
+	return dedupeCmd
+}
+
+func runDedupe(cmd *cobra.Command, args []string) error {
+	if threshold <= 0 || threshold > 1 {
+		return fmt.Errorf("--threshold must be in (0, 1], got %g", threshold)
+	}
+
+	scanDir := dir
+	if scanDir == "" {
+		levelsDir, err := common.LevelsDir()
+		if err != nil {
+			return fmt.Errorf("failed to resolve levels directory: %w", err)
+		}
+		scanDir = levelsDir
+	}
+
+	report, err := fingerprint.Scan(scanDir, fingerprint.Options{Threshold: threshold, IncludeMirrors: includeMirrors})
+	if err != nil {
+		return err
+	}
+	fingerprint.WriteText(cmd.OutOrStdout(), report)
+
+	if strict && report.Duplicates() > 0 {
+		return fmt.Errorf("%d duplicate groups or pairs found", report.Duplicates())
+	}
+	return nil
+}
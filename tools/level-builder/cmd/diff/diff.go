@@ -0,0 +1,125 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/leveldiff"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+var (
+	pathA     string
+	pathB     string
+	maxStates int
+	style     string
+	noRender  bool
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare two level files or two generation runs",
+	Long: `Compare two versions of a level and report what changed.
+
+Reports differences in grid size, vine count, per-vine paths, head
+directions and colors, masks, and solver stats (solvability, states explored,
+solution length, max blocking depth), then renders both levels side by side.
+
+When --a and --b are directories (for example two batch --output-dir runs),
+level_*.json files are paired by name: changed levels are diffed and files
+present on only one side are listed.
+
+Examples:
+  level-builder diff --a level_5.json --b level_5_new.json
+  level-builder diff --a assets/levels --b /tmp/regen --no-render
+  level-builder diff --a old/level_21.json --b new/level_21.json --style unicode`,
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&pathA, "a", "", "original level file or run directory (required)")
+	diffCmd.Flags().StringVar(&pathB, "b", "", "new level file or run directory (required)")
+	diffCmd.Flags().IntVar(&maxStates, "max-states", 1000000, "max states budget for the solver")
+	diffCmd.Flags().StringVarP(&style, "style", "s", "ascii", "Render style: ascii or unicode")
+	diffCmd.Flags().BoolVar(&noRender, "no-render", false, "skip the side-by-side render")
+
+	_ = diffCmd.MarkFlagRequired("a")
+	_ = diffCmd.MarkFlagRequired("b")
+}
+
+// GetCommand returns the diff command for registration with root
+func GetCommand() *cobra.Command {
+	return diffCmd
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	infoA, err := os.Stat(pathA)
+	if err != nil {
+		return fmt.Errorf("cannot read --a: %w", err)
+	}
+	infoB, err := os.Stat(pathB)
+	if err != nil {
+		return fmt.Errorf("cannot read --b: %w", err)
+	}
+	if infoA.IsDir() != infoB.IsDir() {
+		return fmt.Errorf("--a and --b must both be files or both be directories")
+	}
+
+	out := cmd.OutOrStdout()
+	if infoA.IsDir() {
+		return diffRuns(out)
+	}
+
+	a, err := common.ReadLevel(pathA)
+	if err != nil {
+		return err
+	}
+	b, err := common.ReadLevel(pathB)
+	if err != nil {
+		return err
+	}
+	writeLevelDiff(out, leveldiff.Compare(a, b, maxStates))
+	return nil
+}
+
+func diffRuns(out io.Writer) error {
+	run, err := leveldiff.CompareDirs(pathA, pathB, maxStates)
+	if err != nil {
+		return err
+	}
+
+	changed := 0
+	for _, d := range run.Levels {
+		if d.Identical() {
+			continue
+		}
+		changed++
+		_, _ = fmt.Fprintf(out, "=== %s ===\n", d.Name)
+		writeLevelDiff(out, d.LevelDiff)
+		_, _ = fmt.Fprintln(out)
+	}
+
+	for _, name := range run.OnlyInA {
+		_, _ = fmt.Fprintf(out, "- %s (only in %s)\n", name, pathA)
+	}
+	for _, name := range run.OnlyInB {
+		_, _ = fmt.Fprintf(out, "+ %s (only in %s)\n", name, pathB)
+	}
+	_, _ = fmt.Fprintf(out, "\n%d compared, %d changed, %d only in A, %d only in B\n",
+		len(run.Levels), changed, len(run.OnlyInA), len(run.OnlyInB))
+	return nil
+}
+
+func writeLevelDiff(out io.Writer, d leveldiff.LevelDiff) {
+	leveldiff.WriteText(out, d)
+	if noRender {
+		return
+	}
+	_, _ = fmt.Fprintln(out)
+	common.RenderLevelsSideBySide(out, []*model.Level{d.A, d.B}, []string{"A: " + pathA, "B: " + pathB}, style, false)
+}
@@ -0,0 +1,203 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/pack"
+)
+
+var (
+	modulesFlag string
+	outFlag     string
+	dirFlag     string
+)
+
+// exportCmd groups the export subcommands
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Bundle level content for app releases",
+	Long:  `Bundle level content into release artifacts for the Flutter app.`,
+}
+
+// packCmd represents the export pack command
+var packCmd = &cobra.Command{
+	Use:   "pack",
+	Short: "Bundle modules, levels and lessons into one verified zip",
+	Long: `Bundle modules into a level pack the app consumes as one artifact.
+
+The zip mirrors the assets layout: data/modules.json (trimmed to the chosen
+modules), each module's levels, challenge level and mirrored levels, and every
+tutorial lesson. manifest.json records the pack format, the modules.json schema
+version, each file's size and SHA-256, and a pack checksum over that list.
+Files are checked against their JSON Schemas before bundling, and the written
+zip is read back and verified. Packs are reproducible: the same content gives
+the same bytes.
+
+--modules takes IDs and ranges such as "1-5" or "1,3,5" (default: all).
+--out defaults to parable_pack_v<schema major version>.zip.
+
+An --out ending in .pbpack writes a level library instead: every level file
+in --dir (default: the assets levels directory) in one gzip-compressed file
+with an index, so tools read single levels without inflating the rest.
+level-builder extract unpacks it and validate --pack checks it in place.
+
+Examples:
+  level-builder export pack --modules 1-5 --out parable_pack_v3.zip
+  level-builder export pack --modules 2
+  level-builder export pack --out levels.pbpack`,
+	RunE: runPack,
+}
+
+func init() {
+	packCmd.Flags().StringVarP(&modulesFlag, "modules", "m", "", `Module IDs or ranges to bundle, e.g. "1-5" or "1,3" (default: all)`)
+	packCmd.Flags().StringVarP(&outFlag, "out", "o", "", "Path of the pack zip, or of a level library when it ends in .pbpack (default: parable_pack_v<N>.zip)")
+	packCmd.Flags().StringVarP(&dirFlag, "dir", "d", "", "Levels directory a .pbpack library is built from (default: assets/levels)")
+	exportCmd.AddCommand(packCmd)
+}
+
+// GetCommand returns the export command for registration with root
+func GetCommand() *cobra.Command {
+	return exportCmd
+}
+
+func runPack(cmd *cobra.Command, args []string) error {
+	if strings.HasSuffix(outFlag, pack.LibraryExt) {
+		return runLibrary(cmd)
+	}
+	if dirFlag != "" {
+		return fmt.Errorf("--dir only applies to %s libraries", pack.LibraryExt)
+	}
+
+	modulesFile, err := common.ModulesFile()
+	if err != nil {
+		return fmt.Errorf("failed to resolve modules file: %w", err)
+	}
+	assetsDir, err := common.AssetsDir()
+	if err != nil {
+		return fmt.Errorf("failed to resolve assets directory: %w", err)
+	}
+	registry, err := common.LoadModuleRegistry(modulesFile)
+	if err != nil {
+		return err
+	}
+
+	ids, err := parseModules(modulesFlag, registry)
+	if err != nil {
+		return err
+	}
+	p, err := pack.Build(registry, assetsDir, ids)
+	if err != nil {
+		return fmt.Errorf("failed to build pack: %w", err)
+	}
+
+	out := outFlag
+	if out == "" {
+		major, _, _ := strings.Cut(registry.Version, ".")
+		out = fmt.Sprintf("parable_pack_v%s.zip", major)
+	}
+	if err := p.WriteFile(out); err != nil {
+		return fmt.Errorf("failed to write %s: %w", out, err)
+	}
+
+	// Read the zip back so a bad write never ships
+	f, err := os.Open(out)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if _, err := pack.Verify(f, info.Size()); err != nil {
+		return fmt.Errorf("written pack failed verification: %w", err)
+	}
+
+	m := p.Manifest
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s: modules %v, %d levels, %d lessons, schema %s (%d bytes)\n",
+		out, m.Modules, m.Levels, m.Lessons, m.SchemaVersion, info.Size())
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Checksum: %s\n", m.Checksum)
+	return nil
+}
+
+// runLibrary writes --dir to a level library at --out and reads it back.
+func runLibrary(cmd *cobra.Command) error {
+	if modulesFlag != "" {
+		return fmt.Errorf("--modules does not apply to %s libraries, which hold a whole levels directory", pack.LibraryExt)
+	}
+	dir := dirFlag
+	if dir == "" {
+		levelsDir, err := common.LevelsDir()
+		if err != nil {
+			return fmt.Errorf("failed to resolve levels directory: %w", err)
+		}
+		dir = levelsDir
+	}
+
+	index, err := pack.WriteLibraryFile(outFlag, dir)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", outFlag, err)
+	}
+
+	// Read the library back so a bad write never ships
+	lib, err := pack.OpenLibraryFile(outFlag)
+	if err != nil {
+		return fmt.Errorf("written library failed verification: %w", err)
+	}
+	defer func() { _ = lib.Close() }()
+	if err := lib.Verify(); err != nil {
+		return fmt.Errorf("written library failed verification: %w", err)
+	}
+
+	info, err := os.Stat(outFlag)
+	if err != nil {
+		return err
+	}
+	var size int
+	for _, e := range index.Levels {
+		size += e.Size
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s: %d levels, %d bytes (%d uncompressed)\n",
+		outFlag, len(index.Levels), info.Size(), size)
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Checksum: %s\n", index.Checksum)
+	return nil
+}
+
+// parseModules turns "1-5" or "1,3,5" into module IDs; empty means every
+// module in the registry.
+func parseModules(spec string, registry *model.ModuleRegistry) ([]int, error) {
+	if strings.TrimSpace(spec) == "" {
+		ids := make([]int, 0, len(registry.Modules))
+		for _, m := range registry.Modules {
+			ids = append(ids, m.ID)
+		}
+		return ids, nil
+	}
+
+	var ids []int
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		lo, hi, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(strings.TrimSpace(lo))
+		if err != nil {
+			return nil, fmt.Errorf("invalid --modules %q: %q is not a module ID", spec, part)
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(strings.TrimSpace(hi)); err != nil || last < first {
+				return nil, fmt.Errorf("invalid --modules %q: bad range %q", spec, part)
+			}
+		}
+		for id := first; id <= last; id++ {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,138 @@
+package extract
+
+import (
+	"fmt"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/pack"
+)
+
+var (
+	dirFlag       string
+	levelsFlag    []int
+	listFlag      bool
+	overwriteFlag bool
+	backupFlag    bool
+	dryRunFlag    bool
+)
+
+// extractCmd unpacks a level library written by export pack.
+var extractCmd = &cobra.Command{
+	Use:   "extract <library.pbpack>",
+	Short: "Unpack levels from a .pbpack level library",
+	Long: `Write the level files in a level library (export pack --out levels.pbpack)
+back out as level_<id>.json files, byte for byte as they were packed.
+
+Only the library's index is read up front; --level inflates just the levels
+asked for, and --list prints the index without inflating any. Each level is
+checked against the SHA-256 in the index before it is written.
+
+Existing files are left alone unless --overwrite is set, and are backed up to
+<dir>/.backups before being replaced, so level-builder restore undoes an
+extract.
+
+Examples:
+  level-builder extract levels.pbpack --list
+  level-builder extract levels.pbpack --dir /tmp/levels
+  level-builder extract levels.pbpack --level 22 --level 23 --overwrite`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExtract,
+}
+
+func init() {
+	extractCmd.Flags().StringVarP(&dirFlag, "dir", "d", "", "Directory to write the levels to (default: assets/levels)")
+	extractCmd.Flags().IntSliceVar(&levelsFlag, "level", nil, "Extract only these level IDs (repeatable)")
+	extractCmd.Flags().BoolVar(&listFlag, "list", false, "List the library's levels and exit")
+	extractCmd.Flags().BoolVarP(&overwriteFlag, "overwrite", "o", false, "Replace level files that already exist")
+	extractCmd.Flags().BoolVar(&backupFlag, "backup", true, "Back up levels to <dir>/.backups before replacing them")
+	extractCmd.Flags().BoolVarP(&dryRunFlag, "dry-run", "n", false, "Show what would be extracted without writing files")
+}
+
+// GetCommand returns the extract command for registration with root
+func GetCommand() *cobra.Command {
+	return extractCmd
+}
+
+func runExtract(cmd *cobra.Command, args []string) error {
+	lib, err := pack.OpenLibraryFile(args[0])
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lib.Close() }()
+	out := cmd.OutOrStdout()
+
+	entries := lib.Index.Levels
+	if len(levelsFlag) > 0 {
+		entries = nil
+		for _, id := range levelsFlag {
+			e, ok := lib.Entry(id)
+			if !ok {
+				return fmt.Errorf("%s has no level %d", args[0], id)
+			}
+			entries = append(entries, e)
+		}
+	}
+
+	if listFlag {
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "ID\tNAME\tDIFFICULTY\tSIZE\tPACKED")
+		for _, e := range entries {
+			_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%d\n", e.ID, e.Name, e.Difficulty, e.Size, e.Length)
+		}
+		return w.Flush()
+	}
+
+	dir := dirFlag
+	if dir == "" {
+		if dir, err = common.LevelsDir(); err != nil {
+			return fmt.Errorf("failed to resolve levels directory: %w", err)
+		}
+	}
+	if !dryRunFlag {
+		if err := common.EnsureDir(dir); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+	}
+	common.BackupOnOverwrite = backupFlag && !dryRunFlag
+
+	written, skipped := 0, 0
+	for _, e := range entries {
+		path := filepath.Join(dir, e.File)
+		if common.FileExists(path) && !overwriteFlag {
+			common.Verbose("Skipping %s: file exists (use --overwrite to replace)", path)
+			skipped++
+			continue
+		}
+		data, err := lib.ReadFile(e)
+		if err != nil {
+			return err
+		}
+		if dryRunFlag {
+			_, _ = fmt.Fprintf(out, "Would write %s (%d bytes)\n", path, len(data))
+			written++
+			continue
+		}
+		if err := common.BackupBeforeOverwrite(path); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+		if err := common.WriteFileAtomic(path, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		written++
+	}
+
+	verb := "Extracted"
+	if dryRunFlag {
+		verb = "Would extract"
+	}
+	_, _ = fmt.Fprintf(out, "%s %d levels to %s", verb, written, dir)
+	if skipped > 0 {
+		_, _ = fmt.Fprintf(out, " (%d skipped: already exist, see --overwrite)", skipped)
+	}
+	_, _ = fmt.Fprintln(out)
+	return nil
+}
@@ -0,0 +1,148 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/importer"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+var (
+	formatFlag      string
+	idFlag          int
+	nameFlag        string
+	difficultyFlag  string
+	outFlag         string
+	overwriteFlag   bool
+	backupFlag      bool
+	ignoreOccupancy bool
+	maxStates       int
+)
+
+// importCmd converts a level drawn in another tool into level JSON.
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Convert a CSV or Tiled grid into a validated level",
+	Long: `Convert a level drawn in a spreadsheet or in Tiled into level JSON.
+
+Formats (--format, default from the file extension: .csv or .tmx):
+
+  csv    One cell per field, rows from the top of the grid down. An empty
+         field or "." is an empty cell; other text is the ID of the vine
+         covering the cell. Mark the head with a trailing "*" and, for a
+         multi-head vine, the tail head with "+". "A:3" pins a cell to
+         position 3 along vine A (0 is the head).
+  tiled  A Tiled TMX map saved with CSV layer format. Cells of the "vines"
+         layer painted with the same tile form one vine; any tile on the
+         "heads" layer marks a head, on "tails" a tail head, and tile n on
+         "order" pins the cell to position n.
+
+Each vine's cells must allow exactly one path from its head; number the cells
+of tightly folded vines. Head directions come from the paths. Touching vines
+get different colors, empty cells are masked, and min_moves and max_moves
+come from the solver. The level then goes through the same validation as
+validate --check-solvable before it is written. --out - writes the level
+JSON to stdout and the log lines to stderr, for piping into other commands.
+
+Examples:
+  level-builder import sketch.csv --id 130 --difficulty Sprout
+  level-builder import --format tiled garden.tmx --id 131 --out /tmp/level_131.json
+  level-builder import sketch.csv --id 130 --out - | level-builder render --file -`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+func init() {
+	importCmd.Flags().StringVar(&formatFlag, "format", "", "Input format: "+strings.Join(importer.Formats, " or ")+" (default: from the extension)")
+	importCmd.Flags().IntVarP(&idFlag, "id", "i", 0, "Level ID to assign (required)")
+	importCmd.Flags().StringVar(&nameFlag, "name", "", `Level name (default: "Level <id>")`)
+	importCmd.Flags().StringVarP(&difficultyFlag, "difficulty", "d", "Seedling", "Difficulty tier")
+	importCmd.Flags().StringVarP(&outFlag, "out", "o", "", `Output path, "-" for stdout (default: assets/levels/level_<id>.json)`)
+	importCmd.Flags().BoolVar(&overwriteFlag, "overwrite", false, "Replace an existing level file")
+	importCmd.Flags().BoolVar(&backupFlag, "backup", true, "With --overwrite, back up the replaced level to .backups next to it")
+	importCmd.Flags().BoolVar(&ignoreOccupancy, "ignore-occupancy", false, "Warn instead of failing when vines cover less of the grid than the tier requires")
+	importCmd.Flags().IntVar(&maxStates, "max-states", 200000, "Solver state budget")
+}
+
+// GetCommand returns the import command for registration with root
+func GetCommand() *cobra.Command {
+	return importCmd
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	if idFlag <= 0 {
+		return fmt.Errorf("please provide --id")
+	}
+	if outFlag == common.StdioPath {
+		common.ReserveStdout()
+	}
+	format := formatFlag
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(args[0])) {
+		case ".csv":
+			format = "csv"
+		case ".tmx":
+			format = "tiled"
+		default:
+			return fmt.Errorf("cannot tell the format of %s; pass --format", args[0])
+		}
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	sketch, err := importer.Parse(format, f)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", args[0], err)
+	}
+	level, err := importer.Build(cmd.Context(), sketch, importer.Options{
+		ID:         idFlag,
+		Name:       nameFlag,
+		Difficulty: difficultyFlag,
+		MaxStates:  maxStates,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to import %s: %w", args[0], err)
+	}
+
+	out := outFlag
+	if out == "" {
+		if out, err = common.LevelFilePath(idFlag); err != nil {
+			return fmt.Errorf("failed to resolve level file path: %w", err)
+		}
+	}
+	// Validate before writing so a failing level is never saved
+	result, ok, err := validator.ValidateLevel(cmd.Context(), level, true, maxStates, ignoreOccupancy)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return cmd.Context().Err()
+	}
+	if result.Error != "" {
+		return fmt.Errorf("imported level failed validation: %s", result.Error)
+	}
+	if result.Unsolvable() {
+		return fmt.Errorf("imported level failed validation: not solvable within %d states", maxStates)
+	}
+
+	summary, target := cmd.OutOrStdout(), out
+	if out == common.StdioPath {
+		summary, target = cmd.ErrOrStderr(), "stdout"
+	}
+	common.BackupOnOverwrite = backupFlag
+	if err := common.WriteLevel(out, level, overwriteFlag); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(summary, "Imported %d vines on a %dx%d grid to %s (min_moves %d, max_moves %d)\n",
+		len(level.Vines), level.GridSize[0], level.GridSize[1], target, level.MinMoves, level.MaxMoves)
+	return nil
+}
@@ -0,0 +1,455 @@
+package modules
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/modules"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/naming"
+)
+
+var (
+	fixFlag       bool
+	moduleFlag    int
+	toFlag        int
+	levelFlag     string
+	positionFlag  int
+	challengeFlag bool
+	nameFlag      string
+	themeSeedFlag string
+	forceFlag     bool
+	dryRunFlag    bool
+	outFlag       string
+	checkFlag     bool
+)
+
+// modulesCmd groups the module registry subcommands
+var modulesCmd = &cobra.Command{
+	Use:   "modules",
+	Short: "Check and edit the module registry (modules.json)",
+	Long: `Check the module registry, assets/data/modules.json, against the level files
+it refers to, and edit it without hand-editing JSON.
+
+The editing subcommands refuse edits that would name a level twice, reuse a
+module ID or name, or give a module a second challenge level. They write
+modules.json atomically and then run the same checks as modules check,
+printing any remaining issues as warnings. --dry-run prints the issues
+without writing.
+
+Level positions are 1-based slots among a module's regular levels; 0 (the
+default) appends. --challenge targets the module's challenge level instead.`,
+}
+
+// addCmd represents the modules add command
+var addCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a level to a module, or add a new module",
+	Long: `With --level, add a logical level key to --module at --position (default:
+the end) or, with --challenge, as its challenge level. Without --level, add
+a new module with ID --module, named --name and themed --theme-seed
+(default: the module's standard seed).
+
+Examples:
+  level-builder modules add --module 2 --level lvl_sprout_21 --position 5
+  level-builder modules add --module 6 --level lvl_bonus_challenge --challenge
+  level-builder modules add --module 6 --name Bonus --theme-seed aurora`,
+	RunE: runAdd,
+}
+
+// removeCmd represents the modules remove command
+var removeCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove a level from its module, or remove a module",
+	Long: `With --level, take a level key out of whichever module holds it, together
+with its mirror pairing. Without --level, remove --module; a module that
+still has levels is only removed with --force. Level files and
+level_mappings entries are left alone; modules check reports the files as
+orphans.
+
+Examples:
+  level-builder modules remove --level lvl_sprout_07
+  level-builder modules remove --module 6 --force`,
+	RunE: runRemove,
+}
+
+// moveCmd represents the modules move command
+var moveCmd = &cobra.Command{
+	Use:   "move",
+	Short: "Move a level to another module",
+	Long: `Move --level to module --to at --position (default: the end) or, with
+--challenge, as its challenge level. The level's mirror pairing moves with it.
+
+Examples:
+  level-builder modules move --level lvl_seed_20 --to 2 --position 1
+  level-builder modules move --level lvl_sprout_challenge --to 3 --challenge`,
+	RunE: runMove,
+}
+
+// reorderCmd represents the modules reorder command
+var reorderCmd = &cobra.Command{
+	Use:   "reorder",
+	Short: "Reorder a module's levels, or the modules themselves",
+	Long: `With --level, move the level to --position within its module. Without
+--level, move --module to --position in the module list, the order the game
+presents modules in.
+
+Examples:
+  level-builder modules reorder --level lvl_seed_12 --position 3
+  level-builder modules reorder --module 5 --position 1`,
+	RunE: runReorder,
+}
+
+// renameCmd represents the modules rename command
+var renameCmd = &cobra.Command{
+	Use:   "rename",
+	Short: "Rename a module",
+	Long: `Give --module a new --name; no two modules may share a name.
+
+Example:
+  level-builder modules rename --module 2 --name "First Shoots"`,
+	RunE: runRename,
+}
+
+// checkCmd represents the modules check command
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Cross-check modules.json against the levels directory",
+	Long: `Cross-check modules.json against the levels directory:
+
+  - every level and challenge level a module names has a level file, and no
+    level is named twice
+  - each level's difficulty matches its slot in the module progression
+    (5 Seedling, 5 Sprout, 5 Nurturing, 5 Flourishing)
+  - every challenge level is Transcendent
+  - every theme_seed is a known biome
+  - every level_mappings entry points at a file, and no level file is
+    orphaned: each is reached by a module, a mirror or a mapping
+
+A logical key without a level_mappings entry resolves to level_<id>.json by
+the standard numbering (lvl_sprout_03 is level_24.json).
+
+--fix repairs what the registry alone can: unknown theme seeds are reset to
+the module's default, duplicate regular levels are dropped from the later
+module, and stale mappings are repointed at level_<id>.json or removed. The
+registry is rewritten atomically. Missing files, wrong difficulties and
+orphans need a person and are listed again after the fix. The command fails
+while any issue remains.
+
+Examples:
+  level-builder modules check
+  level-builder modules check --fix`,
+	RunE: runCheck,
+}
+
+// namesCmd represents the modules names command
+var namesCmd = &cobra.Command{
+	Use:   "names",
+	Short: "Write the localizable names catalog (data/names.json)",
+	Long: `Collect the names of every level file and module into the names catalog,
+assets/data/names.json, so the app can show localized titles instead of the
+English name strings.
+
+Generated levels and modules carry a name_ref next to their English name: a
+message key and its parameters, such as level.name with {"number": 22} for
+"Level 22" or level.name.mirror wrapping the mirrored level's name. The
+catalog lists each level's and module's name_ref by ID, and the English text
+of every key in use. Names written before name_ref existed are read from
+their English text; names that follow no standard pattern get a key of their
+own, level.<id>.name or module.<id>.name, whose English text is the name.
+
+Translations provide their own text for the same keys. The catalog fails to
+build when a name no longer matches its name_ref, which happens when a name
+is edited by hand.
+
+--check compares the catalog with the one on disk and fails when it is stale,
+for CI.
+
+Examples:
+  level-builder modules names
+  level-builder modules names --check`,
+	RunE: runNames,
+}
+
+func init() {
+	namesCmd.Flags().StringVarP(&outFlag, "out", "o", "", "catalog path (default: assets/data/names.json)")
+	namesCmd.Flags().BoolVar(&checkFlag, "check", false, "fail when the catalog on disk is out of date instead of writing it")
+	modulesCmd.AddCommand(namesCmd)
+
+	checkCmd.Flags().BoolVar(&fixFlag, "fix", false, "repair fixable issues and rewrite modules.json")
+	modulesCmd.AddCommand(checkCmd)
+
+	addCmd.Flags().IntVarP(&moduleFlag, "module", "m", 0, "module to add to, or the ID of the new module")
+	addCmd.Flags().StringVar(&levelFlag, "level", "", "logical level key to add (e.g. lvl_sprout_05)")
+	addCmd.Flags().IntVarP(&positionFlag, "position", "p", modules.End, "1-based slot for the level (0 appends)")
+	addCmd.Flags().BoolVar(&challengeFlag, "challenge", false, "add the level as the module's challenge level")
+	addCmd.Flags().StringVar(&nameFlag, "name", "", "name of the new module")
+	addCmd.Flags().StringVar(&themeSeedFlag, "theme-seed", "", "theme seed of the new module (default: the module's standard seed)")
+
+	removeCmd.Flags().IntVarP(&moduleFlag, "module", "m", 0, "module to remove")
+	removeCmd.Flags().StringVar(&levelFlag, "level", "", "level key to remove from its module")
+	removeCmd.Flags().BoolVar(&forceFlag, "force", false, "remove a module even though it still has levels")
+
+	moveCmd.Flags().StringVar(&levelFlag, "level", "", "level key to move")
+	moveCmd.Flags().IntVar(&toFlag, "to", 0, "module to move the level to")
+	moveCmd.Flags().IntVarP(&positionFlag, "position", "p", modules.End, "1-based slot in the target module (0 appends)")
+	moveCmd.Flags().BoolVar(&challengeFlag, "challenge", false, "make the level the target module's challenge level")
+
+	reorderCmd.Flags().StringVar(&levelFlag, "level", "", "level key to move within its module")
+	reorderCmd.Flags().IntVarP(&moduleFlag, "module", "m", 0, "module to move in the module list")
+	reorderCmd.Flags().IntVarP(&positionFlag, "position", "p", 0, "1-based position to move to")
+
+	renameCmd.Flags().IntVarP(&moduleFlag, "module", "m", 0, "module to rename")
+	renameCmd.Flags().StringVar(&nameFlag, "name", "", "new module name")
+
+	for _, c := range []*cobra.Command{addCmd, removeCmd, moveCmd, reorderCmd, renameCmd} {
+		c.Flags().BoolVarP(&dryRunFlag, "dry-run", "n", false, "check the edit without writing modules.json")
+		modulesCmd.AddCommand(c)
+	}
+}
+
+// GetCommand returns the modules command for registration with root
+func GetCommand() *cobra.Command {
+	return modulesCmd
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	modulesFile, err := common.ModulesFile()
+	if err != nil {
+		return fmt.Errorf("failed to resolve modules file: %w", err)
+	}
+	paths, err := modules.DefaultPaths()
+	if err != nil {
+		return err
+	}
+	registry, err := common.LoadModuleRegistry(modulesFile)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if fixFlag {
+		fixed := modules.Fix(registry, paths)
+		for _, f := range fixed {
+			_, _ = fmt.Fprintf(out, "fixed %s\n", f)
+		}
+		if len(fixed) > 0 {
+			if err := common.SaveModuleRegistry(modulesFile, registry); err != nil {
+				return err
+			}
+			common.Info("Wrote %d fixes to %s", len(fixed), modulesFile)
+		}
+	}
+
+	issues, err := modules.Check(registry, paths)
+	if err != nil {
+		return err
+	}
+	fixable := 0
+	for _, issue := range issues {
+		_, _ = fmt.Fprintln(out, issue)
+		if issue.Fixable {
+			fixable++
+		}
+	}
+	if len(issues) == 0 {
+		common.Info("✓ modules.json: %d modules consistent with the levels directory", len(registry.Modules))
+		return nil
+	}
+	if fixable > 0 {
+		common.Info("%d of %d issues can be repaired with --fix", fixable, len(issues))
+	}
+	return fmt.Errorf("modules.json has %d issues", len(issues))
+}
+
+func runNames(cmd *cobra.Command, args []string) error {
+	modulesFile, err := common.ModulesFile()
+	if err != nil {
+		return fmt.Errorf("failed to resolve modules file: %w", err)
+	}
+	levelsDir, err := common.LevelsDir()
+	if err != nil {
+		return fmt.Errorf("failed to resolve levels directory: %w", err)
+	}
+	out := outFlag
+	if out == "" {
+		dataDir, err := common.DataDir()
+		if err != nil {
+			return fmt.Errorf("failed to resolve data directory: %w", err)
+		}
+		out = filepath.Join(dataDir, naming.CatalogFile)
+	}
+
+	registry, err := common.LoadModuleRegistry(modulesFile)
+	if err != nil {
+		return err
+	}
+	levels, err := common.ReadLevelsFromDir(levelsDir)
+	if err != nil {
+		return err
+	}
+	catalog, err := naming.BuildCatalog(registry, levels)
+	if err != nil {
+		return err
+	}
+	data, err := common.MarshalCanonical(catalog, common.LevelIndent)
+	if err != nil {
+		return err
+	}
+
+	if checkFlag {
+		current, err := os.ReadFile(out)
+		if err != nil || !bytes.Equal(current, data) {
+			return fmt.Errorf("%s is out of date; run level-builder modules names", out)
+		}
+		common.Info("✓ %s is up to date", out)
+		return nil
+	}
+	if err := common.WriteFileAtomic(out, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", out, err)
+	}
+	common.Info("Wrote %s: %d levels, %d modules, %d messages", out, len(catalog.Levels), len(catalog.Modules), len(catalog.Messages))
+	return nil
+}
+
+func runAdd(cmd *cobra.Command, args []string) error {
+	if moduleFlag == 0 {
+		return fmt.Errorf("please provide --module")
+	}
+	return edit(cmd, func(reg *model.ModuleRegistry) (string, error) {
+		if levelFlag != "" {
+			if err := modules.AddLevel(reg, moduleFlag, levelFlag, levelPosition()); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Added %s to module %d", levelFlag, moduleFlag), nil
+		}
+		if nameFlag == "" {
+			return "", fmt.Errorf("please provide --level, or --name for a new module")
+		}
+		seed := themeSeedFlag
+		if seed == "" {
+			seed = generator.ThemeSeedFor(moduleFlag)
+		}
+		if err := modules.AddModule(reg, model.Module{ID: moduleFlag, Name: nameFlag, ThemeSeed: seed, Levels: []string{}}); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Added module %d (%s)", moduleFlag, nameFlag), nil
+	})
+}
+
+func runRemove(cmd *cobra.Command, args []string) error {
+	if (levelFlag == "") == (moduleFlag == 0) {
+		return fmt.Errorf("please provide either --level or --module")
+	}
+	return edit(cmd, func(reg *model.ModuleRegistry) (string, error) {
+		if levelFlag != "" {
+			from, err := modules.RemoveLevel(reg, levelFlag)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Removed %s from module %d", levelFlag, from), nil
+		}
+		if err := modules.RemoveModule(reg, moduleFlag, forceFlag); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Removed module %d", moduleFlag), nil
+	})
+}
+
+func runMove(cmd *cobra.Command, args []string) error {
+	if levelFlag == "" || toFlag == 0 {
+		return fmt.Errorf("please provide --level and --to")
+	}
+	return edit(cmd, func(reg *model.ModuleRegistry) (string, error) {
+		if err := modules.MoveLevel(reg, levelFlag, toFlag, levelPosition()); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Moved %s to module %d", levelFlag, toFlag), nil
+	})
+}
+
+func runReorder(cmd *cobra.Command, args []string) error {
+	if (levelFlag == "") == (moduleFlag == 0) || positionFlag < 1 {
+		return fmt.Errorf("please provide --position and either --level or --module")
+	}
+	return edit(cmd, func(reg *model.ModuleRegistry) (string, error) {
+		if levelFlag == "" {
+			if err := modules.MoveModule(reg, moduleFlag, positionFlag); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Moved module %d to position %d", moduleFlag, positionFlag), nil
+		}
+		owner, ok := modules.ModuleOf(reg, levelFlag)
+		if !ok {
+			return "", fmt.Errorf("%s is not in any module", levelFlag)
+		}
+		if err := modules.MoveLevel(reg, levelFlag, owner, positionFlag); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Moved %s to position %d of module %d", levelFlag, positionFlag, owner), nil
+	})
+}
+
+func runRename(cmd *cobra.Command, args []string) error {
+	if moduleFlag == 0 || nameFlag == "" {
+		return fmt.Errorf("please provide --module and --name")
+	}
+	return edit(cmd, func(reg *model.ModuleRegistry) (string, error) {
+		if err := modules.RenameModule(reg, moduleFlag, nameFlag); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Renamed module %d to %q", moduleFlag, nameFlag), nil
+	})
+}
+
+// levelPosition is the slot --position and --challenge select.
+func levelPosition() int {
+	if challengeFlag {
+		return modules.Challenge
+	}
+	return positionFlag
+}
+
+// edit loads modules.json, applies change, reports what it did and the issues
+// modules check finds in the result, and writes the registry back unless
+// --dry-run is set.
+func edit(cmd *cobra.Command, change func(reg *model.ModuleRegistry) (string, error)) error {
+	if positionFlag < 0 {
+		return fmt.Errorf("--position must not be negative")
+	}
+	modulesFile, err := common.ModulesFile()
+	if err != nil {
+		return fmt.Errorf("failed to resolve modules file: %w", err)
+	}
+	registry, err := common.LoadModuleRegistry(modulesFile)
+	if err != nil {
+		return err
+	}
+	done, err := change(registry)
+	if err != nil {
+		return err
+	}
+
+	if paths, err := modules.DefaultPaths(); err == nil {
+		if issues, err := modules.Check(registry, paths); err == nil {
+			for _, issue := range issues {
+				common.Warning("%s", issue)
+			}
+		}
+	}
+	if dryRunFlag {
+		common.Info("%s (dry run, modules.json not written)", done)
+		return nil
+	}
+	if err := common.SaveModuleRegistry(modulesFile, registry); err != nil {
+		return err
+	}
+	common.Info("%s", done)
+	return nil
+}
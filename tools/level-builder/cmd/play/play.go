@@ -0,0 +1,200 @@
+package play
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/play"
+)
+
+var (
+	fileFlag  string
+	idFlag    int
+	styleFlag string
+)
+
+// playCmd plays a level in the terminal.
+var playCmd = &cobra.Command{
+	Use:   "play",
+	Short: "Play a level in the terminal under the game's rules",
+	Long: `Play a level in the terminal to check how it feels without launching the game.
+
+The board is drawn with coordinates after every move. Select a vine by typing
+its ID or the coordinates of any of its cells ("3,4" or "3 4"); the vine slides
+out if one of its heads has a clear path, following portals, exactly as in the
+game. Every tap is a move. A tap that cannot clear bumps the vine and costs one
+grace the first time that vine fails; tutorials never drop below one grace.
+The game ends when the board is clear, grace runs out or max_moves is reached
+with vines left.
+
+Other input: "vines" lists the vines in play, "q" quits.
+
+Examples:
+  level-builder play --id 12
+  level-builder play --file assets/levels/level_33.json --style ascii`,
+	RunE: runPlay,
+}
+
+func init() {
+	playCmd.Flags().StringVarP(&fileFlag, "file", "f", "", "Path to a level JSON file to play")
+	playCmd.Flags().IntVarP(&idFlag, "id", "i", 0, "Level ID to play (uses assets/levels/level_<id>.json)")
+	playCmd.Flags().StringVarP(&styleFlag, "style", "s", "unicode", "Board style: ascii or unicode")
+}
+
+// GetCommand returns the play command for registration with root
+func GetCommand() *cobra.Command {
+	return playCmd
+}
+
+func runPlay(cmd *cobra.Command, args []string) error {
+	path := fileFlag
+	if path == common.StdioPath {
+		return fmt.Errorf("play reads moves from stdin, so --file - is not supported")
+	}
+	if path == "" {
+		if idFlag == 0 {
+			return fmt.Errorf("please provide either --file or --id to play a level")
+		}
+		var err error
+		if path, err = common.LevelFilePath(idFlag); err != nil {
+			return fmt.Errorf("failed to resolve level file path: %w", err)
+		}
+	}
+	level, err := common.ReadLevel(path)
+	if err != nil {
+		return fmt.Errorf("failed to read level: %w", err)
+	}
+
+	playLevel(cmd.InOrStdin(), cmd.OutOrStdout(), level)
+	return nil
+}
+
+// playLevel runs the game loop until the game ends, input runs out or the
+// player quits.
+func playLevel(in io.Reader, out io.Writer, level *model.Level) {
+	g := play.New(level)
+	grace, _ := g.Grace()
+	_, _ = fmt.Fprintf(out, "Level %d: %s (%s), %d vines, grace %d", level.ID, level.Name, level.Difficulty, len(level.Vines), grace)
+	if level.MaxMoves > 0 {
+		_, _ = fmt.Fprintf(out, ", %d moves", level.MaxMoves)
+	}
+	_, _ = fmt.Fprintln(out)
+
+	// The board is redrawn only after a tap; listings and bad input just prompt again
+	scanner := bufio.NewScanner(in)
+	redraw := true
+	for g.Status() == play.Playing {
+		if redraw {
+			_, _ = fmt.Fprintln(out)
+			common.RenderLevelToWriter(out, g.Board(), styleFlag, true)
+			_, _ = fmt.Fprintln(out, statusLine(g, level))
+		}
+		redraw = false
+		_, _ = fmt.Fprint(out, "> ")
+		if !scanner.Scan() {
+			_, _ = fmt.Fprintln(out)
+			return
+		}
+
+		input := strings.TrimSpace(scanner.Text())
+		switch strings.ToLower(input) {
+		case "":
+			continue
+		case "q", "quit":
+			_, _ = fmt.Fprintf(out, "Quit after %d moves with %d vines left\n", g.Moves(), len(g.Remaining()))
+			return
+		case "vines":
+			listVines(out, g)
+			continue
+		}
+
+		id, err := vineFor(g, input)
+		if err != nil {
+			_, _ = fmt.Fprintln(out, err)
+			continue
+		}
+		tap, err := g.Tap(id)
+		if err != nil {
+			_, _ = fmt.Fprintln(out, err)
+			continue
+		}
+		redraw = true
+		msg := tap.String()
+		if tap.Locked {
+			msg += fmt.Sprintf(" until %d vines have cleared", vineLock(level, id))
+		}
+		if tap.GraceLost {
+			msg += " (-1 grace)"
+		}
+		_, _ = fmt.Fprintln(out, msg)
+	}
+
+	_, _ = fmt.Fprintln(out)
+	common.RenderLevelToWriter(out, g.Board(), styleFlag, true)
+	switch g.Status() {
+	case play.Won:
+		grace, total := g.Grace()
+		_, _ = fmt.Fprintf(out, "Cleared in %d moves (min %d, max %d) with %d/%d grace left\n",
+			g.Moves(), level.MinMoves, level.MaxMoves, grace, total)
+		_, _ = fmt.Fprintf(out, "Order: %s\n", strings.Join(g.Cleared(), ", "))
+	default:
+		_, _ = fmt.Fprintf(out, "Game over: %s after %d moves with %d vines left\n", g.Status(), g.Moves(), len(g.Remaining()))
+	}
+}
+
+// vineFor resolves input to a vine ID, either directly or by a cell it covers.
+func vineFor(g *play.Game, input string) (string, error) {
+	fields := strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' })
+	if len(fields) == 2 {
+		x, errX := strconv.Atoi(fields[0])
+		y, errY := strconv.Atoi(fields[1])
+		if errX == nil && errY == nil {
+			id, ok := g.VineAt(model.Point{X: x, Y: y})
+			if !ok {
+				return "", fmt.Errorf("no vine at (%d,%d)", x, y)
+			}
+			return id, nil
+		}
+	}
+	return input, nil
+}
+
+func statusLine(g *play.Game, level *model.Level) string {
+	grace, total := g.Grace()
+	moves := strconv.Itoa(g.Moves())
+	if level.MaxMoves > 0 {
+		moves += "/" + strconv.Itoa(level.MaxMoves)
+	}
+	return fmt.Sprintf("Vines %d/%d  Moves %s  Grace %d/%d  (vine ID or x,y; \"vines\" lists, \"q\" quits)",
+		len(g.Remaining()), len(level.Vines), moves, grace, total)
+}
+
+func listVines(out io.Writer, g *play.Game) {
+	for _, v := range g.Remaining() {
+		head := v.OrderedPath[0]
+		line := fmt.Sprintf("  %-10s head (%d,%d) %s, length %d", v.ID, head.X, head.Y, v.HeadDirection, len(v.OrderedPath))
+		if v.IsMultiHead() {
+			line += ", tail head " + v.TailDirection
+		}
+		if v.LockedUntil > 0 {
+			line += fmt.Sprintf(", locked until %d clears", v.LockedUntil)
+		}
+		_, _ = fmt.Fprintln(out, line)
+	}
+}
+
+func vineLock(level *model.Level, id string) int {
+	for _, v := range level.Vines {
+		if v.ID == id {
+			return v.LockedUntil
+		}
+	}
+	return 0
+}
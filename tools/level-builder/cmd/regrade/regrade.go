@@ -0,0 +1,102 @@
+package regrade
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/metrics"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/regrade"
+)
+
+var (
+	dirFlag       string
+	applyFlag     bool
+	maxStatesFlag int
+	backupFlag    bool
+	strictFlag    bool
+)
+
+// regradeCmd re-scores the level library against the current difficulty bands.
+var regradeCmd = &cobra.Command{
+	Use:   "regrade",
+	Short: "Re-score existing levels and find stale difficulty tiers",
+	Long: `Recompute the difficulty metrics of every level in a levels directory
+with the current scorer and report levels whose stored difficulty tier no
+longer matches how hard they measure.
+
+A level keeps its tier while its difficulty score and deepest blocker chain
+both lie inside that tier's bands, the same check batch applies when it
+generates levels. Otherwise it is regraded to the tier whose bands it misses
+by the least, preferring the tier nearest its stored one. Levels whose
+complexity does not match their tier are reported too. Tutorial levels and
+other tiers without bands are skipped.
+
+--apply sets the difficulty and complexity of the reported levels and
+writes them back in canonical form, as other commands that edit levels in
+place do. Levels are backed up first unless --backup=false, so
+level-builder restore can roll the run back. With --strict the command
+fails when any level is stale, for use in CI.
+
+Examples:
+  level-builder regrade
+  level-builder regrade --apply
+  level-builder regrade --dir /tmp/regen --strict`,
+	Args: cobra.NoArgs,
+	RunE: runRegrade,
+}
+
+func init() {
+	regradeCmd.Flags().StringVarP(&dirFlag, "dir", "d", "", "Levels directory (default: assets/levels)")
+	regradeCmd.Flags().BoolVar(&applyFlag, "apply", false, "Rewrite the difficulty and complexity of stale levels")
+	regradeCmd.Flags().IntVar(&maxStatesFlag, "max-states", metrics.DefaultScorerMaxStates, "Solver state budget per level")
+	regradeCmd.Flags().BoolVar(&backupFlag, "backup", true, "Back up levels to <dir>/.backups before rewriting them")
+	regradeCmd.Flags().BoolVar(&strictFlag, "strict", false, "Exit with an error when any level is stale")
+}
+
+// GetCommand returns the regrade command for registration with root
+func GetCommand() *cobra.Command {
+	return regradeCmd
+}
+
+func runRegrade(cmd *cobra.Command, args []string) error {
+	dir := dirFlag
+	if dir == "" {
+		var err error
+		if dir, err = common.LevelsDir(); err != nil {
+			return fmt.Errorf("failed to resolve levels directory: %w", err)
+		}
+	}
+
+	report, err := regrade.Scan(dir, regrade.Options{MaxStates: maxStatesFlag})
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	regrade.WriteText(out, report)
+
+	regraded, stale, _, failed := report.Counts()
+	if applyFlag {
+		common.BackupOnOverwrite = backupFlag
+		written := 0
+		for _, res := range report.Results {
+			if !res.Apply() {
+				continue
+			}
+			if err := common.WriteLevel(res.File, res.Level, true); err != nil {
+				return err
+			}
+			written++
+		}
+		_, _ = fmt.Fprintf(out, "Rewrote %d levels\n", written)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d levels could not be scored", failed)
+	}
+	if strictFlag && !applyFlag && regraded+stale > 0 {
+		return fmt.Errorf("%d levels have a stale difficulty or complexity", regraded+stale)
+	}
+	return nil
+}
@@ -2,32 +2,56 @@ package render
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
 )
 
 var (
-	fileFlag   string
-	idFlag     int
-	styleFlag  string
-	coordsFlag bool
+	fileFlag      string
+	idFlag        int
+	styleFlag     string
+	coordsFlag    bool
+	animateFlag   bool
+	outFlag       string
+	cellSizeFlag  int
+	stepDelayFlag time.Duration
+	maxStatesFlag int
 )
 
 // RenderCmd renders a level to the terminal for visual inspection.
 var RenderCmd = &cobra.Command{
 	Use:   "render",
-	Short: "Render a level to the terminal (ASCII/Unicode)",
+	Short: "Render a level to the terminal or an SVG/PNG image, or animate its solution",
 	Long: `Render a level to the terminal for quick visual inspection.
 
 You can supply a file path with --file (-f) or a level id with --id (-i) (looks in assets/levels).
+--file - reads the level JSON from stdin.
+
+--style svg and --style png write an image to --out instead, using the
+level's color scheme with head arrows and joined vine bodies; --cell-size
+sets the pixels per grid cell. Useful for documentation and store assets.
+
+With --animate the level is solved and the clearing sequence is written to
+--out as an animation of the vines sliding out in order. The format follows
+the file extension: .gif for an animated GIF, .svg for an animated SVG.
 
 Examples:
   level-builder render --id 1
   level-builder render --file assets/levels/level_33.json
+  cat level_33.json | level-builder render --file -
   level-builder render --id 10 --style ascii --coords
+  level-builder render --id 12 --style svg --out docs/level_12.svg
+  level-builder render --id 12 --style png --out level_12.png --cell-size 48
+  level-builder render --id 5 --animate --out level_5.gif
+  level-builder render --id 5 --animate --out level_5.svg --cell-size 32 --step-delay 40ms
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var level *model.Level
@@ -51,9 +75,16 @@ Examples:
 			return fmt.Errorf("please provide either --file or --id to render a level")
 		}
 
+		if animateFlag {
+			return writeAnimation(cmd, level)
+		}
+
 		if styleFlag == "" {
 			styleFlag = "unicode"
 		}
+		if styleFlag == common.ImageSVG || styleFlag == common.ImagePNG {
+			return writeImage(cmd, level)
+		}
 
 		common.RenderLevelToWriter(cmd.OutOrStdout(), level, styleFlag, coordsFlag)
 		return nil
@@ -61,8 +92,69 @@ Examples:
 }
 
 func init() {
-	RenderCmd.Flags().StringVarP(&fileFlag, "file", "f", "", "Path to a level JSON file to render")
+	RenderCmd.Flags().StringVarP(&fileFlag, "file", "f", "", `Path to a level JSON file to render ("-" for stdin)`)
 	RenderCmd.Flags().IntVarP(&idFlag, "id", "i", 0, "Level ID to render (uses assets/levels/level_<id>.json)")
-	RenderCmd.Flags().StringVarP(&styleFlag, "style", "s", "unicode", "Render style: ascii or unicode")
+	RenderCmd.Flags().StringVarP(&styleFlag, "style", "s", "unicode", "Render style: ascii, unicode, svg or png")
 	RenderCmd.Flags().BoolVarP(&coordsFlag, "coords", "c", false, "Show axis coordinates")
+	RenderCmd.Flags().BoolVar(&animateFlag, "animate", false, "Write an animation of the solution to --out")
+	RenderCmd.Flags().StringVarP(&outFlag, "out", "o", "", "Output file for --style svg/png or --animate (.gif or .svg)")
+	RenderCmd.Flags().IntVar(&cellSizeFlag, "cell-size", common.DefaultImageCellSize, "Pixels per grid cell for image and animation output")
+	RenderCmd.Flags().DurationVar(&stepDelayFlag, "step-delay", 60*time.Millisecond, "Animation time for a vine to slide one cell")
+	RenderCmd.Flags().IntVar(&maxStatesFlag, "max-states", 1000000, "max states budget for the solver")
+}
+
+// writeImage renders level as a static SVG or PNG to --out.
+func writeImage(cmd *cobra.Command, level *model.Level) error {
+	if outFlag == "" {
+		return fmt.Errorf("--style %s requires --out", styleFlag)
+	}
+	f, err := os.Create(outFlag)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", outFlag, err)
+	}
+	if err := common.RenderLevelImage(f, level, styleFlag, cellSizeFlag); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to render image: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outFlag, err)
+	}
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s render of level %d to %s\n", styleFlag, level.ID, outFlag)
+	return nil
+}
+
+// writeAnimation solves level and writes the clearing sequence to --out.
+func writeAnimation(cmd *cobra.Command, level *model.Level) error {
+	if outFlag == "" {
+		return fmt.Errorf("--animate requires --out (.gif or .svg)")
+	}
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(outFlag)), ".")
+	if format != common.AnimationGIF && format != common.AnimationSVG {
+		return fmt.Errorf("unsupported animation file %q: use a .gif or .svg extension", outFlag)
+	}
+
+	ok, solution, stats, err := validator.SolveContext(cmd.Context(), *level, maxStatesFlag)
+	if err != nil {
+		return fmt.Errorf("failed to solve level %d: %w", level.ID, err)
+	}
+	if !ok {
+		return fmt.Errorf("level %d is not solvable (solver %s, %d states)", level.ID, stats.Solver, stats.StatesExplored)
+	}
+
+	f, err := os.Create(outFlag)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", outFlag, err)
+	}
+	opts := common.AnimationOptions{CellSize: cellSizeFlag, StepDelay: stepDelayFlag}
+	if err := common.WriteSolutionAnimation(f, level, solution, format, opts); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write animation: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outFlag, err)
+	}
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Wrote %d-move animation of level %d to %s\n", len(solution), level.ID, outFlag)
+	return nil
 }
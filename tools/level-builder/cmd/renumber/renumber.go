@@ -0,0 +1,118 @@
+package renumber
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+var (
+	dirFlag    string
+	dryRunFlag bool
+	backupFlag bool
+)
+
+// renumberCmd gives the vines of level files canonical, deterministic IDs.
+var renumberCmd = &cobra.Command{
+	Use:   "renumber [level files...]",
+	Short: "Renumber vines to canonical IDs in head raster order",
+	Long: `Rename the vines of level files to the canonical vine_1, vine_2, ...
+scheme, numbered by head position in raster order: top row first, left to
+right within a row. Vines are stored in that order too.
+
+Older placers and recovery paths named vines v7 or numbered fillers past
+the placed vines, so the same number could appear in two styles and IDs
+said nothing about where a vine is. After renumber the same layout always
+gets the same IDs. Colors, locks and directions stay with their vines, and
+hints are rewritten to the new IDs.
+
+Without arguments every level in --dir is renumbered. Levels already in
+canonical order are left untouched. Replaced files are backed up to
+<dir>/.backups first; level-builder restore rolls the renumber back.
+
+Examples:
+  level-builder renumber
+  level-builder renumber --dry-run
+  level-builder renumber assets/levels/level_12.json`,
+	RunE: runRenumber,
+}
+
+func init() {
+	renumberCmd.Flags().StringVarP(&dirFlag, "dir", "d", "", "Levels directory to renumber (default: assets/levels)")
+	renumberCmd.Flags().BoolVarP(&dryRunFlag, "dry-run", "n", false, "Show the renames without writing files")
+	renumberCmd.Flags().BoolVar(&backupFlag, "backup", true, "Back up levels to <dir>/.backups before replacing them")
+}
+
+// GetCommand returns the renumber command for registration with root
+func GetCommand() *cobra.Command {
+	return renumberCmd
+}
+
+func runRenumber(cmd *cobra.Command, args []string) error {
+	paths := args
+	if len(paths) == 0 {
+		dir := dirFlag
+		if dir == "" {
+			var err error
+			if dir, err = common.LevelsDir(); err != nil {
+				return fmt.Errorf("failed to resolve levels directory: %w", err)
+			}
+		}
+		var err error
+		if paths, err = filepath.Glob(filepath.Join(dir, "level_*.json")); err != nil {
+			return err
+		}
+		if len(paths) == 0 {
+			return fmt.Errorf("no level files in %s", dir)
+		}
+	}
+
+	common.BackupOnOverwrite = backupFlag && !dryRunFlag
+	out := cmd.OutOrStdout()
+	changed := 0
+	for _, path := range paths {
+		level, err := common.ReadLevel(path)
+		if err != nil {
+			return err
+		}
+		renamed := level.RenumberVines()
+		if len(renamed) == 0 {
+			common.Verbose("%s already canonical", path)
+			continue
+		}
+		changed++
+		_, _ = fmt.Fprintf(out, "%s: %d vines renamed\n", filepath.Base(path), len(renamed))
+		if common.VerboseEnabled || dryRunFlag {
+			ids := make([]string, 0, len(renamed))
+			for id := range renamed {
+				ids = append(ids, id)
+			}
+			sort.Slice(ids, func(i, j int) bool {
+				a, _ := model.VineIndex(ids[i])
+				b, _ := model.VineIndex(ids[j])
+				return a < b
+			})
+			for _, id := range ids {
+				_, _ = fmt.Fprintf(out, "  %s -> %s\n", renamed[id], id)
+			}
+		}
+		if dryRunFlag {
+			continue
+		}
+		if err := common.WriteLevel(path, level, true); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	verb := "Renumbered"
+	if dryRunFlag {
+		verb = "Would renumber"
+	}
+	_, _ = fmt.Fprintf(out, "%s %d of %d levels\n", verb, changed, len(paths))
+	return nil
+}
@@ -1,6 +1,7 @@
 package repair
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,6 +16,8 @@ import (
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/strategies"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/utils"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/naming"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/repair"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
 )
 
@@ -22,7 +25,10 @@ var (
 	directoryFlag string
 	overwriteFlag bool
 	dryRunFlag    bool
+	backupFlag    bool
 	fixDuplicates bool
+	minimalFlag   bool
+	maxStatesFlag int
 )
 
 var levelFileRE = regexp.MustCompile(`^level_(\d+)\.json$`)
@@ -34,10 +40,26 @@ var RepairCmd = &cobra.Command{
 	Long: `Scan a levels directory and regenerate any files that fail to parse.
 This helps recover from partial writes or corrupted files produced by earlier runs.
 
+--minimal also repairs levels that parse but are broken, editing them in place
+instead of regenerating them so the rest of the design is preserved:
+  - cells out of bounds, on portals or shared with another vine are dropped
+  - out-of-order paths are reordered into a connected chain
+  - head_direction and tail_direction are recomputed from the path
+  - a vine blocking its own exit is reversed, or regrown over its own cells
+  - one vine of each circular-blocking deadlock is reversed or regrown, and
+    locks that can never open are removed
+  - min_moves, max_moves and hints are updated and the mask is rebuilt
+Only the offending vines change; each edit is logged. Files that do not parse
+are still regenerated.
+
+Every level a repair replaces is first copied to one backup under the
+directory's .backups; level-builder restore rolls the repair back.
+
 Examples:
   level-builder repair
   level-builder repair --directory assets/levels
   level-builder repair --dry-run
+  level-builder repair --minimal --dry-run
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if directoryFlag == "" {
@@ -48,18 +70,22 @@ Examples:
 			}
 		}
 
-		return repairDirectory(directoryFlag, overwriteFlag, dryRunFlag)
+		common.BackupOnOverwrite = backupFlag && !dryRunFlag
+		return repairDirectory(cmd.Context(), directoryFlag, overwriteFlag, dryRunFlag)
 	},
 }
 
 func init() {
-	RepairCmd.Flags().StringVarP(&directoryFlag, "directory", "d", "", "Directory containing level files to repair (default: ../../assets/levels)")
+	RepairCmd.Flags().StringVarP(&directoryFlag, "directory", "d", "", "Directory containing level files to repair (default: assets/levels)")
 	RepairCmd.Flags().BoolVarP(&overwriteFlag, "overwrite", "o", true, "Overwrite repaired files")
 	RepairCmd.Flags().BoolVarP(&dryRunFlag, "dry-run", "n", false, "Scan and report without writing files")
+	RepairCmd.Flags().BoolVar(&backupFlag, "backup", true, "Back up levels to <directory>/.backups before replacing them")
 	RepairCmd.Flags().BoolVar(&fixDuplicates, "fix-duplicates", false, "Automatically fix duplicate vine IDs and duplicate entries (keeps first occurrence)")
+	RepairCmd.Flags().BoolVar(&minimalFlag, "minimal", false, "Fix broken levels in place with minimal edits instead of only regenerating unparseable files")
+	RepairCmd.Flags().IntVar(&maxStatesFlag, "max-states", 100000, "Solver state budget per check when --minimal looks for deadlocks")
 }
 
-func repairDirectory(dir string, overwrite, dryRun bool) error {
+func repairDirectory(ctx context.Context, dir string, overwrite, dryRun bool) error {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return fmt.Errorf("failed to read directory %s: %w", dir, err)
@@ -82,7 +108,13 @@ func repairDirectory(dir string, overwrite, dryRun bool) error {
 		path := filepath.Join(dir, name)
 		common.Verbose("Checking %s", path)
 
-		repaired, repairErr := repairFileIfNeeded(path, m[1], overwrite, dryRun)
+		var repaired bool
+		var repairErr error
+		if minimalFlag {
+			repaired, repairErr = repairFileMinimal(ctx, path, m[1], overwrite, dryRun)
+		} else {
+			repaired, repairErr = repairFileIfNeeded(path, m[1], overwrite, dryRun)
+		}
 		if repaired {
 			if repairErr != nil {
 				failed++
@@ -183,7 +215,9 @@ func repairFileIfNeeded(path, idStr string, overwrite, dryRun bool) (bool, error
 	}
 
 	// Generate vines using tiling algorithm
-	vines, genErr := strategies.ClearableFirstPlacement(gridSize, spec, profile, cfg, levelSeed, 0.3, common.MinGridCoverage, true)
+	// Fill to the tier's coverage floor, as the legacy clearable strategy does
+	coverage, _ := config.CoverageFor(difficulty, "", 0)
+	vines, genErr := strategies.ClearableFirstPlacement(context.Background(), gridSize, spec, profile, cfg, levelSeed, 0.3, coverage.Minimum, true)
 	if genErr != nil {
 		return true, fmt.Errorf("failed to generate vines for level %d: %w", id, genErr)
 	}
@@ -210,9 +244,11 @@ func repairFileIfNeeded(path, idStr string, overwrite, dryRun bool) (bool, error
 		Points: maskedPoints,
 	}
 
+	name := naming.Level(id)
 	level := model.Level{
 		ID:          id,
-		Name:        fmt.Sprintf("Level %d", id),
+		Name:        naming.Render(name),
+		NameRef:     &name,
 		Difficulty:  difficulty,
 		GridSize:    gridSize,
 		Vines:       vines,
@@ -242,6 +278,42 @@ func repairFileIfNeeded(path, idStr string, overwrite, dryRun bool) (bool, error
 	return true, nil
 }
 
+// repairFileMinimal fixes a broken level with the smallest edits repair.Level finds,
+// leaving sound vines untouched. Files that are not valid level JSON are regenerated.
+func repairFileMinimal(ctx context.Context, path, idStr string, overwrite, dryRun bool) (bool, error) {
+	lvl, err := common.ReadLevelRaw(path)
+	if err != nil {
+		return repairFileIfNeeded(path, idStr, overwrite, dryRun)
+	}
+
+	fixes, err := repair.Level(ctx, lvl, maxStatesFlag)
+	for _, f := range fixes {
+		common.Info("%s: %s", filepath.Base(path), f)
+	}
+	if err != nil {
+		common.Warning("Failed to repair %s: %v", path, err)
+		return true, err
+	}
+	if len(fixes) == 0 {
+		return false, nil
+	}
+	if errs := validator.ValidateStructural(*lvl); len(errs) > 0 {
+		common.Warning("%s still fails structural validation after %d fixes: %v", path, len(fixes), errs[0])
+		return true, fmt.Errorf("level %d still has %d structural errors", lvl.ID, len(errs))
+	}
+
+	if dryRun {
+		common.Info("Would apply %d fixes to level %d -> %s", len(fixes), lvl.ID, path)
+		return true, nil
+	}
+	if err := common.WriteLevel(path, lvl, overwrite); err != nil {
+		common.Error("Failed to write repaired level %d to %s: %v", lvl.ID, path, err)
+		return true, err
+	}
+	common.Info("Repaired level %d in place (%d fixes)", lvl.ID, len(fixes))
+	return true, nil
+}
+
 // sanitizeLevelDuplicateIDs removes duplicate vine entries (same ID) keeping the
 // first occurrence, and renames duplicates with differing ordered_path to a
 // new unique vine_N id to avoid overlap collisions.
@@ -0,0 +1,160 @@
+package replay
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/strategies"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/levelgen"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+var (
+	dumpPath   string
+	step       bool
+	difficulty string
+	strategy   string
+)
+
+// replayCmd represents the replay command
+var replayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "Replay a failed generation from its failure dump",
+	Long: `Re-run the placement attempt recorded in a failure dump.
+
+Failure dumps record the generation config and seed of the failing attempt.
+Replay reconstructs both, re-runs placement with verbose tracing and reports
+whether the failure reproduced: the replay must fail with the same message
+and the same placed vines. With --step it then walks through the placements
+one vine at a time, rendering the grid after each.
+
+Dumps written before configs were recorded only carry the level ID, grid and
+seed; the config is rebuilt from --difficulty and --strategy with the dump's
+grid, which may not match the original run.
+
+Examples:
+  level-builder replay --dump failing_dumps/failure_level_12_seed_376044_attempt_0_20250101_120000.json
+  level-builder replay --dump failure.json --step`,
+	RunE: runReplay,
+}
+
+func init() {
+	replayCmd.Flags().StringVar(&dumpPath, "dump", "", "failure dump JSON to replay (required)")
+	replayCmd.Flags().BoolVar(&step, "step", false, "step through placements interactively after the replay")
+	replayCmd.Flags().StringVar(&difficulty, "difficulty", "Seedling", "difficulty tier for dumps without a recorded config")
+	replayCmd.Flags().StringVar(&strategy, "strategy", "", "placement strategy for dumps without a recorded config")
+	_ = replayCmd.MarkFlagRequired("dump")
+}
+
+// GetCommand returns the replay command for registration with root
+func GetCommand() *cobra.Command {
+	return replayCmd
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	dump, err := strategies.LoadFailureDump(dumpPath)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+
+	cfg, err := replayConfig(out, dump)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "Replaying level %d (%dx%d, %s, %s, seed %d)\n",
+		cfg.LevelID, cfg.GridWidth, cfg.GridHeight, cfg.Difficulty, cfg.Strategy, dump.Seed)
+	_, _ = fmt.Fprintf(out, "Original failure: %s (%d vines placed)\n\n", dump.Message, len(dump.Vines))
+
+	prevVerbose := common.VerboseEnabled
+	common.VerboseEnabled = true
+	result, err := generator.Replay(cmd.Context(), cfg, dump)
+	common.VerboseEnabled = prevVerbose
+	if err != nil {
+		return fmt.Errorf("replay failed: %w", err)
+	}
+
+	_, _ = fmt.Fprintln(out)
+	switch {
+	case result.Reproduced:
+		_, _ = fmt.Fprintln(out, "Reproduced: the replay failed identically.")
+	case result.Err == nil:
+		_, _ = fmt.Fprintf(out, "Not reproduced: placement now succeeds with %d vines.\n", len(result.Vines))
+	default:
+		_, _ = fmt.Fprintf(out, "Not reproduced: placement failed differently: %v\n", result.Err)
+		for _, d := range result.Dumps {
+			_, _ = fmt.Fprintf(out, "  dump: %s (%d vines placed)\n", d.Message, len(d.Vines))
+		}
+	}
+
+	if !step {
+		return nil
+	}
+	// Step through the state the failure was recorded in, so the last step
+	// shows the grid the placer gave up on
+	vines := dump.Vines
+	for _, d := range result.Dumps {
+		if d.Same(dump) {
+			vines = d.Vines
+			break
+		}
+	}
+	stepPlacements(cmd.InOrStdin(), out, cfg, vines, dump.Message)
+	return nil
+}
+
+// replayConfig returns the dump's recorded config, or rebuilds one for dumps
+// that predate recorded configs.
+func replayConfig(out io.Writer, dump strategies.FailureDump) (config.GenerationConfig, error) {
+	if dump.Config != nil {
+		return *dump.Config, nil
+	}
+	_, _ = fmt.Fprintf(out, "Warning: dump has no recorded config; rebuilding it for %s\n", difficulty)
+	cfg, err := levelgen.ConfigFor(levelgen.GenerateOptions{
+		LevelID:    dump.LevelID,
+		Difficulty: difficulty,
+		Seed:       dump.Seed,
+		Strategy:   strategy,
+	})
+	if err != nil {
+		return config.GenerationConfig{}, err
+	}
+	cfg.GridWidth, cfg.GridHeight = dump.Grid[0], dump.Grid[1]
+	return cfg, nil
+}
+
+// stepPlacements renders the grid after each placement, waiting for Enter
+// between steps; "q" stops early.
+func stepPlacements(in io.Reader, out io.Writer, cfg config.GenerationConfig, vines []model.Vine, message string) {
+	if len(vines) == 0 {
+		_, _ = fmt.Fprintln(out, "\nThe dump recorded no placed vines to step through.")
+		return
+	}
+	scanner := bufio.NewScanner(in)
+	for i, v := range vines {
+		level := model.Level{
+			ID:       cfg.LevelID,
+			GridSize: []int{cfg.GridWidth, cfg.GridHeight},
+			Vines:    vines[:i+1],
+		}
+		_, _ = fmt.Fprintf(out, "\nStep %d/%d: placed %s (length %d, head %s at %d,%d)\n",
+			i+1, len(vines), v.ID, len(v.OrderedPath), v.HeadDirection, v.OrderedPath[0].X, v.OrderedPath[0].Y)
+		common.RenderLevelToWriter(out, &level, "ascii", true)
+
+		if i == len(vines)-1 {
+			break
+		}
+		_, _ = fmt.Fprint(out, "[Enter] next, [q] quit: ")
+		if !scanner.Scan() || strings.EqualFold(strings.TrimSpace(scanner.Text()), "q") {
+			return
+		}
+	}
+	_, _ = fmt.Fprintf(out, "\nFailure: %s\n", message)
+}
@@ -0,0 +1,119 @@
+package restore
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+)
+
+var (
+	dirFlag    string
+	listFlag   bool
+	levelsFlag []int
+	dryRunFlag bool
+)
+
+// restoreCmd rolls level files back to a backup taken when they were overwritten.
+var restoreCmd = &cobra.Command{
+	Use:   "restore [backup]",
+	Short: "Roll levels back to a backup taken before they were overwritten",
+	Long: `Copy level files from a backup in assets/levels/.backups back into the
+levels directory.
+
+batch, repair, renumber and import --overwrite copy every level they replace
+into one backup_<timestamp> directory per run. restore puts those files back:
+the newest backup by default, or the one named. --level restores only some
+levels.
+
+The levels being replaced are backed up first, so running restore again
+without arguments undoes the restore.
+
+Examples:
+  level-builder restore --list
+  level-builder restore
+  level-builder restore backup_20260101_120000 --level 22 --level 23
+  level-builder restore --dir /tmp/levels --dry-run`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRestore,
+}
+
+func init() {
+	restoreCmd.Flags().StringVarP(&dirFlag, "dir", "d", "", "Levels directory to restore into (default: assets/levels)")
+	restoreCmd.Flags().BoolVar(&listFlag, "list", false, "List the backups, newest first, and exit")
+	restoreCmd.Flags().IntSliceVar(&levelsFlag, "level", nil, "Restore only these level IDs (repeatable)")
+	restoreCmd.Flags().BoolVarP(&dryRunFlag, "dry-run", "n", false, "Show what would be restored without writing files")
+}
+
+// GetCommand returns the restore command for registration with root
+func GetCommand() *cobra.Command {
+	return restoreCmd
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	dir := dirFlag
+	if dir == "" {
+		var err error
+		if dir, err = common.LevelsDir(); err != nil {
+			return fmt.Errorf("failed to resolve levels directory: %w", err)
+		}
+	}
+	backups, err := common.ListBackups(dir)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+
+	if listFlag {
+		if len(backups) == 0 {
+			_, _ = fmt.Fprintf(out, "No backups in %s\n", common.BackupsDir(dir))
+			return nil
+		}
+		for _, b := range backups {
+			_, _ = fmt.Fprintf(out, "%s  %s  %d levels\n", b.Name, b.Time.Format("2006-01-02 15:04:05"), len(b.Files))
+		}
+		return nil
+	}
+
+	if len(backups) == 0 {
+		return fmt.Errorf("no backups in %s", common.BackupsDir(dir))
+	}
+	backup := backups[0]
+	if len(args) == 1 {
+		found := false
+		for _, b := range backups {
+			if b.Name == args[0] {
+				backup, found = b, true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("no backup named %s in %s (see --list)", args[0], common.BackupsDir(dir))
+		}
+	}
+
+	var files []string
+	for _, id := range levelsFlag {
+		files = append(files, fmt.Sprintf("level_%d.json", id))
+	}
+	if len(files) == 0 && len(backup.Files) == 0 {
+		return fmt.Errorf("backup %s holds no levels", backup.Name)
+	}
+
+	if dryRunFlag {
+		if len(files) == 0 {
+			files = backup.Files
+		}
+		_, _ = fmt.Fprintf(out, "Would restore %d levels from %s: %s\n", len(files), backup.Name, strings.Join(files, ", "))
+		return nil
+	}
+
+	restored, err := common.RestoreBackup(dir, backup, files)
+	if err != nil {
+		return fmt.Errorf("restore from %s failed after %d levels: %w", backup.Name, len(restored), err)
+	}
+	_, _ = fmt.Fprintf(out, "Restored %d levels from %s\n", len(restored), backup.Name)
+	return nil
+}
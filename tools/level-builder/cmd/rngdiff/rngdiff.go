@@ -0,0 +1,196 @@
+package rngdiff
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/random"
+)
+
+var (
+	pathA string
+	pathB string
+)
+
+// rngdiffCmd represents the rngdiff command
+var rngdiffCmd = &cobra.Command{
+	Use:   "rngdiff",
+	Short: "Find the first random draw at which two generation runs diverge",
+	Long: `Compare RNG traces from two runs of the same seed and report the first
+draw that differs.
+
+batch --rng-trace DIR records every random draw made while generating, with
+the value and the generation code that drew it, one file per attempt. When the
+same seed produces different levels, run both builds with --rng-trace and
+compare the traces: the first divergent draw shows the call site whose draw
+count or order changed, and the last shared call site shows what ran just
+before it.
+
+When --a and --b are directories, *.rng.json files are paired by name and
+files present on only one side are listed.
+
+Examples:
+  level-builder batch --module 1 --rng-trace /tmp/trace_a
+  level-builder batch --module 1 --rng-trace /tmp/trace_b
+  level-builder rngdiff --a /tmp/trace_a --b /tmp/trace_b
+  level-builder rngdiff --a a/level_3_attempt_1.rng.json --b b/level_3_attempt_1.rng.json`,
+	RunE: runRNGDiff,
+}
+
+func init() {
+	rngdiffCmd.Flags().StringVar(&pathA, "a", "", "original trace file or trace directory (required)")
+	rngdiffCmd.Flags().StringVar(&pathB, "b", "", "new trace file or trace directory (required)")
+
+	_ = rngdiffCmd.MarkFlagRequired("a")
+	_ = rngdiffCmd.MarkFlagRequired("b")
+}
+
+// GetCommand returns the rngdiff command for registration with root
+func GetCommand() *cobra.Command {
+	return rngdiffCmd
+}
+
+func runRNGDiff(cmd *cobra.Command, args []string) error {
+	infoA, err := os.Stat(pathA)
+	if err != nil {
+		return fmt.Errorf("cannot read --a: %w", err)
+	}
+	infoB, err := os.Stat(pathB)
+	if err != nil {
+		return fmt.Errorf("cannot read --b: %w", err)
+	}
+	if infoA.IsDir() != infoB.IsDir() {
+		return fmt.Errorf("--a and --b must both be files or both be directories")
+	}
+
+	out := cmd.OutOrStdout()
+	if !infoA.IsDir() {
+		diverged, err := diffTraces(out, pathA, pathB, filepath.Base(pathB))
+		if err != nil {
+			return err
+		}
+		if diverged {
+			return fmt.Errorf("traces diverged")
+		}
+		_, _ = fmt.Fprintln(out, "Traces are identical.")
+		return nil
+	}
+
+	namesA, err := traceNames(pathA)
+	if err != nil {
+		return err
+	}
+	namesB, err := traceNames(pathB)
+	if err != nil {
+		return err
+	}
+
+	diverged, missing := 0, 0
+	for _, name := range sortedUnion(namesA, namesB) {
+		switch {
+		case !namesA[name]:
+			_, _ = fmt.Fprintf(out, "%s: only in --b\n", name)
+			missing++
+		case !namesB[name]:
+			_, _ = fmt.Fprintf(out, "%s: only in --a\n", name)
+			missing++
+		default:
+			d, err := diffTraces(out, filepath.Join(pathA, name), filepath.Join(pathB, name), name)
+			if err != nil {
+				return err
+			}
+			if d {
+				diverged++
+			}
+		}
+	}
+
+	_, _ = fmt.Fprintf(out, "\n%d traces compared: %d diverged, %d on one side only\n",
+		len(namesA)+len(namesB)-countShared(namesA, namesB), diverged, missing)
+	if diverged > 0 || missing > 0 {
+		return fmt.Errorf("runs diverged")
+	}
+	return nil
+}
+
+// diffTraces prints where the traces at a and b first differ, labelled name,
+// and reports whether they did.
+func diffTraces(out io.Writer, a, b, name string) (bool, error) {
+	recA, err := random.LoadRecording(a)
+	if err != nil {
+		return false, err
+	}
+	recB, err := random.LoadRecording(b)
+	if err != nil {
+		return false, err
+	}
+
+	if recA.Source != recB.Source || recA.Seed != recB.Seed {
+		_, _ = fmt.Fprintf(out, "%s: runs used different seeds (%s %d vs %s %d)\n",
+			name, recA.Source, recA.Seed, recB.Source, recB.Seed)
+	}
+	d := random.Diverge(recA, recB)
+	if d == nil {
+		return false, nil
+	}
+
+	_, _ = fmt.Fprintf(out, "%s: diverged at draw %d of %d/%d\n", name, d.Index, len(recA.Draws), len(recB.Draws))
+	if d.Shared != "" {
+		_, _ = fmt.Fprintf(out, "  last shared: %s\n", d.Shared)
+	}
+	_, _ = fmt.Fprintf(out, "  a: %s\n", describe(d.A))
+	_, _ = fmt.Fprintf(out, "  b: %s\n", describe(d.B))
+	return true, nil
+}
+
+func describe(d random.Draw) string {
+	if d.Missing {
+		return "(no more draws)"
+	}
+	if d.Site == "" {
+		return fmt.Sprintf("%d", d.Value)
+	}
+	return fmt.Sprintf("%d from %s", d.Value, d.Site)
+}
+
+// traceNames returns the *.rng.json file names in dir.
+func traceNames(dir string) (map[string]bool, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.rng.json"))
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		names[filepath.Base(p)] = true
+	}
+	return names, nil
+}
+
+func sortedUnion(a, b map[string]bool) []string {
+	var names []string
+	for name := range a {
+		names = append(names, name)
+	}
+	for name := range b {
+		if !a[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func countShared(a, b map[string]bool) int {
+	n := 0
+	for name := range a {
+		if b[name] {
+			n++
+		}
+	}
+	return n
+}
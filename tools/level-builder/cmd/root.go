@@ -1,21 +1,45 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"runtime"
-	"strconv"
-	"strings"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
 
+	"github.com/eng618/parable-bloom/tools/level-builder/cmd/analyze"
 	"github.com/eng618/parable-bloom/tools/level-builder/cmd/batch"
+	"github.com/eng618/parable-bloom/tools/level-builder/cmd/bench"
+	"github.com/eng618/parable-bloom/tools/level-builder/cmd/budget"
 	"github.com/eng618/parable-bloom/tools/level-builder/cmd/clean"
+	"github.com/eng618/parable-bloom/tools/level-builder/cmd/daemon"
+	"github.com/eng618/parable-bloom/tools/level-builder/cmd/dedupe"
+	"github.com/eng618/parable-bloom/tools/level-builder/cmd/diff"
+	"github.com/eng618/parable-bloom/tools/level-builder/cmd/explore"
+	"github.com/eng618/parable-bloom/tools/level-builder/cmd/export"
+	"github.com/eng618/parable-bloom/tools/level-builder/cmd/extract"
+	"github.com/eng618/parable-bloom/tools/level-builder/cmd/importer"
+	"github.com/eng618/parable-bloom/tools/level-builder/cmd/modules"
+	"github.com/eng618/parable-bloom/tools/level-builder/cmd/play"
+	"github.com/eng618/parable-bloom/tools/level-builder/cmd/regrade"
 	"github.com/eng618/parable-bloom/tools/level-builder/cmd/render"
+	"github.com/eng618/parable-bloom/tools/level-builder/cmd/renumber"
 	"github.com/eng618/parable-bloom/tools/level-builder/cmd/repair"
+	"github.com/eng618/parable-bloom/tools/level-builder/cmd/replay"
+	"github.com/eng618/parable-bloom/tools/level-builder/cmd/restore"
+	"github.com/eng618/parable-bloom/tools/level-builder/cmd/rngdiff"
+	"github.com/eng618/parable-bloom/tools/level-builder/cmd/schema"
+	"github.com/eng618/parable-bloom/tools/level-builder/cmd/seedsearch"
+	"github.com/eng618/parable-bloom/tools/level-builder/cmd/serve"
+	"github.com/eng618/parable-bloom/tools/level-builder/cmd/solve"
+	"github.com/eng618/parable-bloom/tools/level-builder/cmd/stats"
 	"github.com/eng618/parable-bloom/tools/level-builder/cmd/tutorials"
 	"github.com/eng618/parable-bloom/tools/level-builder/cmd/validate"
+	"github.com/eng618/parable-bloom/tools/level-builder/cmd/variant"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
 )
 
 var (
@@ -24,9 +48,10 @@ var (
 	workers    string
 	workingDir string
 	logFile    string
-
-	// Parsed workers value
-	WorkersCount int
+	logFormat  string
+	configPath string
+	jsonIndent string
+	sortKeys   bool
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -49,18 +74,42 @@ It provides commands for:
 		// Set log file in common package
 		common.LogFile = logFile
 
+		switch logFormat {
+		case common.LogFormatText, common.LogFormatJSON:
+			common.LogFormat = logFormat
+		default:
+			return fmt.Errorf("invalid --log-format %q: must be 'text' or 'json'", logFormat)
+		}
+
+		indent, err := common.ParseJSONIndent(jsonIndent)
+		if err != nil {
+			return fmt.Errorf("invalid --json-indent value: %w", err)
+		}
+		common.JSONIndent = indent
+		common.JSONSortKeys = sortKeys
+
 		// Parse workers flag
-		count, err := parseWorkers(workers)
+		count, err := common.ParseWorkers(workers)
 		if err != nil {
 			return fmt.Errorf("invalid --workers value: %w", err)
 		}
-		WorkersCount = count
-		common.Verbose("Workers: %d (from flag: %s)", WorkersCount, workers)
+		common.Workers = count
+		common.Verbose("Workers: %d (from flag: %s)", common.Workers, workers)
+
+		// Load the difficulty config before changing directory, so a relative
+		// --config path resolves against where the command was run
+		if configPath != "" {
+			if err := config.Load(configPath); err != nil {
+				return fmt.Errorf("invalid --config: %w", err)
+			}
+			common.Verbose("Loaded difficulty config: %s", configPath)
+		}
 
-		// Handle working directory
+		// Handle working directory: every asset path and relative flag value
+		// resolves from here on
 		if workingDir != "" {
 			common.Verbose("Changing working directory to: %s", workingDir)
-			if err := os.Chdir(workingDir); err != nil {
+			if err := common.SetWorkingDir(workingDir); err != nil {
 				return fmt.Errorf("failed to change working directory: %w", err)
 			}
 		}
@@ -71,8 +120,18 @@ It provides commands for:
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// SIGINT/SIGTERM cancel the command context so long-running generation and
+// solving stop cleanly; a second signal exits immediately.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop() // restore default handling so a second Ctrl+C kills the process
+	}()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		stop()
 		os.Exit(1)
 	}
 }
@@ -81,41 +140,41 @@ func init() {
 	// Persistent flags (available to all subcommands)
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose output for debugging")
 	rootCmd.PersistentFlags().StringVarP(&workers, "workers", "j", "half", "number of concurrent workers (integer, 'half', or 'full')")
-	rootCmd.PersistentFlags().StringVarP(&workingDir, "working-dir", "w", "", "working directory for asset paths (default: current directory)")
+	rootCmd.PersistentFlags().StringVarP(&workingDir, "working-dir", "w", "", "directory to resolve paths and find the workspace root from (default: current directory)")
 	rootCmd.PersistentFlags().StringVarP(&logFile, "log-file", "l", "", "path to log file (default: stdout)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", common.LogFormatText, "log output format: 'text' or 'json' (one structured event per line)")
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "difficulty config (YAML or JSON) merged onto the embedded defaults")
+	rootCmd.PersistentFlags().StringVar(&jsonIndent, "json-indent", "", "indent for written level, lesson and module files: spaces (1-8) or 'tab' (default: each file's convention)")
+	rootCmd.PersistentFlags().BoolVar(&sortKeys, "json-sort-keys", false, "write JSON object keys in alphabetical order instead of schema order")
 
 	// Register subcommands
 	rootCmd.AddCommand(batch.GetCommand())
 	rootCmd.AddCommand(validate.GetCommand())
 	rootCmd.AddCommand(render.RenderCmd)
 	rootCmd.AddCommand(repair.RepairCmd)
+	rootCmd.AddCommand(renumber.GetCommand())
+	rootCmd.AddCommand(restore.GetCommand())
 	rootCmd.AddCommand(clean.GetCommand())
 	rootCmd.AddCommand(tutorials.GetCommand())
-}
-
-// parseWorkers parses the workers flag value
-// Accepts: "full" -> NumCPU(), "half" -> NumCPU()/2, or integer string -> that value
-func parseWorkers(value string) (int, error) {
-	value = strings.TrimSpace(strings.ToLower(value))
-
-	switch value {
-	case "full":
-		return runtime.NumCPU(), nil
-	case "half":
-		count := runtime.NumCPU() / 2
-		if count < 1 {
-			count = 1
-		}
-		return count, nil
-	default:
-		// Try to parse as integer
-		count, err := strconv.Atoi(value)
-		if err != nil {
-			return 0, fmt.Errorf("must be 'full', 'half', or a positive integer (got: %s)", value)
-		}
-		if count < 1 {
-			return 0, fmt.Errorf("must be at least 1 (got: %d)", count)
-		}
-		return count, nil
-	}
+	rootCmd.AddCommand(explore.GetCommand())
+	rootCmd.AddCommand(budget.GetCommand())
+	rootCmd.AddCommand(solve.GetCommand())
+	rootCmd.AddCommand(diff.GetCommand())
+	rootCmd.AddCommand(dedupe.GetCommand())
+	rootCmd.AddCommand(seedsearch.GetCommand())
+	rootCmd.AddCommand(bench.GetCommand())
+	rootCmd.AddCommand(replay.GetCommand())
+	rootCmd.AddCommand(rngdiff.GetCommand())
+	rootCmd.AddCommand(analyze.GetCommand())
+	rootCmd.AddCommand(modules.GetCommand())
+	rootCmd.AddCommand(schema.GetCommand())
+	rootCmd.AddCommand(stats.GetCommand())
+	rootCmd.AddCommand(play.GetCommand())
+	rootCmd.AddCommand(export.GetCommand())
+	rootCmd.AddCommand(extract.GetCommand())
+	rootCmd.AddCommand(variant.GetCommand())
+	rootCmd.AddCommand(regrade.GetCommand())
+	rootCmd.AddCommand(importer.GetCommand())
+	rootCmd.AddCommand(serve.GetCommand())
+	rootCmd.AddCommand(daemon.GetCommand())
 }
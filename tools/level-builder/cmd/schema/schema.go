@@ -0,0 +1,93 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/schema"
+)
+
+var (
+	outDir     string
+	schemaType string
+)
+
+// schemaCmd groups the JSON Schema subcommands
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "JSON Schemas for level, lesson and module files",
+	Long: `JSON Schemas derived from the level-builder's Go model types.
+
+validate checks every file against these schemas before its structural
+checks, so unknown fields and wrong types are reported instead of ignored.`,
+}
+
+// exportCmd represents the schema export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write the level, lesson and module JSON Schemas",
+	Long: `Write JSON Schemas (draft 2020-12) for level, lesson and module files.
+
+Schemas are generated from the Go model types: fields tagged omitempty are
+optional and every object rejects unknown fields. With --out, each schema is
+written to <name>.schema.json in that directory; without it, the schema
+chosen with --type is printed to stdout.
+
+Examples:
+  level-builder schema export --out schemas
+  level-builder schema export --type level > level.schema.json`,
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&outDir, "out", "", "directory to write <name>.schema.json files to (default: stdout)")
+	exportCmd.Flags().StringVar(&schemaType, "type", "", fmt.Sprintf("schema to export: %s (default: all)", strings.Join(schema.Names(), ", ")))
+	schemaCmd.AddCommand(exportCmd)
+}
+
+// GetCommand returns the schema command for registration with root
+func GetCommand() *cobra.Command {
+	return schemaCmd
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	names := schema.Names()
+	if schemaType != "" {
+		if _, ok := schema.Files[schemaType]; !ok {
+			return fmt.Errorf("unknown --type %q (want one of %s)", schemaType, strings.Join(names, ", "))
+		}
+		names = []string{schemaType}
+	}
+	if outDir == "" && len(names) > 1 {
+		return fmt.Errorf("--type is required when writing to stdout")
+	}
+
+	if outDir != "" {
+		if err := os.MkdirAll(outDir, 0o755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", outDir, err)
+		}
+	}
+	for _, name := range names {
+		data, err := json.MarshalIndent(schema.Files[name](), "", "  ")
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+
+		if outDir == "" {
+			_, err = cmd.OutOrStdout().Write(data)
+			return err
+		}
+		path := filepath.Join(outDir, name+".schema.json")
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s\n", path)
+	}
+	return nil
+}
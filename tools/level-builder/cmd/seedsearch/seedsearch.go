@@ -0,0 +1,130 @@
+package seedsearch
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/explore"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/ui"
+)
+
+var (
+	levelID     int
+	difficulty  string
+	strategy    string
+	minCoverage float64
+	startSeed   int64
+	count       int
+	top         int
+	targetScore float64
+	maxStates   int
+	outDir      string
+)
+
+// seedSearchCmd represents the seedsearch command
+var seedSearchCmd = &cobra.Command{
+	Use:   "seedsearch",
+	Short: "Search many seeds for the best levels of a difficulty",
+	Long: `Generate a level for each of many seeds and report the best ones.
+
+Every candidate is solved and rated with the difficulty scorer (blocking
+depth, forced moves, solution length, solver effort). Candidates whose score
+falls inside the tier's band rank first, then those closest to the target
+score (default: the middle of the band), then those with more coverage.
+
+Seeds are tried consecutively from --start-seed. The reported seed reproduces
+the level with the same difficulty, level ID and strategy. With --out-dir the
+top levels are written as level_<id>_seed_<seed>.json for review.
+
+Examples:
+  level-builder seedsearch --difficulty Flourishing --count 1000 --top 10
+  level-builder seedsearch --difficulty Sprout --target-score 12 --out-dir /tmp/candidates
+  level-builder seedsearch --difficulty Transcendent --strategy center-out --start-seed 1`,
+	RunE: runSeedSearch,
+}
+
+func init() {
+	seedSearchCmd.Flags().IntVar(&levelID, "level-id", 1, "level ID used for grid sizing and the default start seed")
+	seedSearchCmd.Flags().StringVar(&difficulty, "difficulty", "Nurturing", "difficulty tier (Seedling, Sprout, Nurturing, Flourishing, Transcendent)")
+	seedSearchCmd.Flags().StringVar(&strategy, "strategy", "", "placement strategy (default: batch default)")
+	seedSearchCmd.Flags().Float64Var(&minCoverage, "min-coverage", 0, "minimum vine coverage (0 = full coverage)")
+	seedSearchCmd.Flags().Int64Var(&startSeed, "start-seed", 0, "first seed to try (0 uses the batch default for --level-id)")
+	seedSearchCmd.Flags().IntVar(&count, "count", 100, "number of seeds to try")
+	seedSearchCmd.Flags().IntVar(&top, "top", 10, "number of best seeds to report")
+	seedSearchCmd.Flags().Float64Var(&targetScore, "target-score", 0, "difficulty score to aim for (0 = middle of the tier's band)")
+	seedSearchCmd.Flags().IntVar(&maxStates, "max-states", 100000, "max states budget for scoring each level")
+	seedSearchCmd.Flags().StringVar(&outDir, "out-dir", "", "write the top levels to this directory")
+}
+
+// GetCommand returns the seedsearch command for registration with root
+func GetCommand() *cobra.Command {
+	return seedSearchCmd
+}
+
+func runSeedSearch(cmd *cobra.Command, args []string) error {
+	spin := ui.NewSpinner(fmt.Sprintf("Searching %d seeds for %s...", count, difficulty))
+	spin.Start()
+	result, err := explore.SearchSeeds(cmd.Context(), explore.SeedSearchOptions{
+		LevelID:     levelID,
+		Difficulty:  difficulty,
+		Strategy:    strategy,
+		MinCoverage: minCoverage,
+		StartSeed:   startSeed,
+		Count:       count,
+		Top:         top,
+		TargetScore: targetScore,
+		MaxStates:   maxStates,
+		Workers:     common.Workers,
+		OnProgress: func(done, total int) {
+			spin.UpdateMessage("Searching seeds for %s (%d/%d)...", difficulty, done, total)
+		},
+	})
+	spin.Stop()
+	if err != nil {
+		return fmt.Errorf("seed search failed: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	writeResult(out, result)
+
+	if outDir == "" || len(result.Candidates) == 0 {
+		return nil
+	}
+	if err := common.EnsureDir(outDir); err != nil {
+		return fmt.Errorf("failed to create %s: %w", outDir, err)
+	}
+	for _, c := range result.Candidates {
+		path := filepath.Join(outDir, fmt.Sprintf("level_%d_seed_%d.json", c.Level.ID, c.Seed))
+		level := c.Level
+		if err := common.WriteLevel(path, &level, true); err != nil {
+			return err
+		}
+	}
+	_, _ = fmt.Fprintf(out, "\nWrote %d levels to %s\n", len(result.Candidates), outDir)
+	return nil
+}
+
+func writeResult(out io.Writer, r explore.SeedSearchResult) {
+	band := "none"
+	if r.Band != [2]float64{} {
+		band = fmt.Sprintf("[%.1f, %.1f]", r.Band[0], r.Band[1])
+	}
+	_, _ = fmt.Fprintf(out, "%d seeds tried: %d generated, %d solvable, %d in band %s (target score %.2f)\n",
+		r.Tried, r.Generated, r.Solvable, r.InBand, band, r.Target)
+	if len(r.Candidates) == 0 {
+		_, _ = fmt.Fprintln(out, "No solvable candidates found.")
+		return
+	}
+
+	_, _ = fmt.Fprintf(out, "\n%-4s %-12s %-7s %-7s %-6s %-9s %-8s %-6s %-9s %s\n",
+		"Rank", "Seed", "Score", "InBand", "Vines", "Coverage", "MaxDepth", "Moves", "Forced", "States")
+	for i, c := range r.Candidates {
+		_, _ = fmt.Fprintf(out, "%-4d %-12d %-7.2f %-7v %-6d %-9.1f %-8d %-6d %-9.2f %d\n",
+			i+1, c.Seed, c.Metrics.Score, c.InBand, len(c.Level.Vines), c.Coverage*100,
+			c.Metrics.MaxBlockingDepth, c.Metrics.SolutionLength, c.Metrics.ForcedMoveRatio, c.Metrics.StatesExplored)
+	}
+}
@@ -0,0 +1,102 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/serve"
+)
+
+var (
+	portFlag            int
+	hostFlag            string
+	maxStatesFlag       int
+	cellSizeFlag        int
+	generateTimeoutFlag time.Duration
+)
+
+// serveCmd runs the web preview server.
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a browsable preview of the level library over HTTP",
+	Long: `Serve a web page that lists every level in assets/levels, renders the selected
+level as SVG, animates its solution and validates it, plus a form that
+generates new levels on demand without saving them. Designers can browse the
+library without the CLI; level files are re-read on every request.
+
+The page is built on JSON endpoints that scripts can use directly:
+
+  GET  /api/levels                    level summaries
+  GET  /api/levels/{id}               level JSON
+  GET  /api/levels/{id}/svg           static SVG render
+  GET  /api/levels/{id}/solution.svg  animated solution
+  GET  /api/levels/{id}/validate      validation result (?solvable=false skips the solver)
+  POST /api/validate                  validate a posted level JSON
+  POST /api/generate                  {"id": 7, "difficulty": "Sprout", "seed": 0, "strategy": ""}
+
+The server listens on localhost by default; pass --host 0.0.0.0 to share it
+on the network. Ctrl+C stops it.
+
+Examples:
+  level-builder serve
+  level-builder serve --port 9000 --host 0.0.0.0 --generate-timeout 1m`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+func init() {
+	serveCmd.Flags().IntVarP(&portFlag, "port", "p", 8080, "Port to listen on")
+	serveCmd.Flags().StringVar(&hostFlag, "host", "localhost", "Interface to listen on")
+	serveCmd.Flags().IntVar(&maxStatesFlag, "max-states", 1000000, "Solver state budget for validation and solution animations")
+	serveCmd.Flags().IntVar(&cellSizeFlag, "cell-size", common.DefaultImageCellSize, "Pixels per grid cell in SVG renders")
+	serveCmd.Flags().DurationVar(&generateTimeoutFlag, "generate-timeout", 2*time.Minute, "Time limit for each generate request (0 = none)")
+}
+
+// GetCommand returns the serve command for registration with root
+func GetCommand() *cobra.Command {
+	return serveCmd
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	levelsDir, err := common.LevelsDir()
+	if err != nil {
+		return fmt.Errorf("failed to resolve levels directory: %w", err)
+	}
+	handler := serve.NewHandler(serve.Options{
+		LevelsDir:       levelsDir,
+		MaxStates:       maxStatesFlag,
+		CellSize:        cellSizeFlag,
+		GenerateTimeout: generateTimeoutFlag,
+	})
+
+	ln, err := net.Listen("tcp", net.JoinHostPort(hostFlag, strconv.Itoa(portFlag)))
+	if err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+	srv := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdown)
+	}()
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Serving %s at http://%s\n", levelsDir, ln.Addr())
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package solve
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+var (
+	levelID     int
+	filePath    string
+	all         bool
+	maxStates   int
+	style       string
+	coords      bool
+	noSnapshots bool
+)
+
+// solveCmd represents the solve command
+var solveCmd = &cobra.Command{
+	Use:   "solve",
+	Short: "Print the move sequence that clears a level",
+	Long: `Solve a level and print the order in which its vines are cleared.
+
+After the clearing order, the board is rendered at the start and after every
+move so designers can step through the solution when a level "feels
+impossible". Unsolvable levels are reported with the solver that gave up and
+how many states it tried.
+
+Examples:
+  level-builder solve --id 12
+  level-builder solve --file assets/levels/level_33.json --style unicode --coords
+  level-builder solve --all --no-snapshots`,
+	RunE: runSolve,
+}
+
+func init() {
+	solveCmd.Flags().IntVarP(&levelID, "id", "i", 0, "Level ID to solve (uses assets/levels/level_<id>.json)")
+	solveCmd.Flags().StringVarP(&filePath, "file", "f", "", `Path to a level JSON file to solve ("-" for stdin)`)
+	solveCmd.Flags().BoolVar(&all, "all", false, "solve every level in assets/levels")
+	solveCmd.Flags().IntVar(&maxStates, "max-states", 1000000, "max states budget for the solver")
+	solveCmd.Flags().StringVarP(&style, "style", "s", "ascii", "Snapshot render style: ascii or unicode")
+	solveCmd.Flags().BoolVarP(&coords, "coords", "c", false, "Show axis coordinates in snapshots")
+	solveCmd.Flags().BoolVar(&noSnapshots, "no-snapshots", false, "only print the clearing order")
+}
+
+// GetCommand returns the solve command for registration with root
+func GetCommand() *cobra.Command {
+	return solveCmd
+}
+
+func runSolve(cmd *cobra.Command, args []string) error {
+	levels, err := loadLevels()
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	var unsolved []int
+	for i, level := range levels {
+		if i > 0 {
+			_, _ = fmt.Fprintln(out)
+		}
+		if err := cmd.Context().Err(); err != nil {
+			return err
+		}
+		if !writeSolution(cmd.Context(), out, level) {
+			unsolved = append(unsolved, level.ID)
+		}
+	}
+
+	if len(unsolved) > 0 {
+		return fmt.Errorf("%d of %d levels not solved: %v", len(unsolved), len(levels), unsolved)
+	}
+	return nil
+}
+
+// loadLevels resolves the levels selected by --file, --id or --all.
+func loadLevels() ([]*model.Level, error) {
+	switch {
+	case all:
+		dir, err := common.LevelsDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve levels directory: %w", err)
+		}
+		levels, err := common.ReadLevelsFromDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(levels, func(i, j int) bool { return levels[i].ID < levels[j].ID })
+		return levels, nil
+	case filePath != "":
+		level, err := common.ReadLevel(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read level file: %w", err)
+		}
+		return []*model.Level{level}, nil
+	case levelID != 0:
+		path, err := common.LevelFilePath(levelID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve level file path: %w", err)
+		}
+		level, err := common.ReadLevel(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read level %d: %w", levelID, err)
+		}
+		return []*model.Level{level}, nil
+	default:
+		return nil, fmt.Errorf("please provide --id, --file or --all")
+	}
+}
+
+// writeSolution solves level and prints its clearing order followed by
+// snapshots of the starting board and the board after each move. It reports
+// whether a solution was found.
+func writeSolution(ctx context.Context, w io.Writer, level *model.Level) bool {
+	ok, solution, stats, err := validator.SolveContext(ctx, *level, maxStates)
+	if err != nil || !ok {
+		reason := "no solution"
+		if err != nil {
+			reason = err.Error()
+		} else if stats.GaveUp {
+			reason = "state budget exhausted"
+		}
+		_, _ = fmt.Fprintf(w, "Level %d: UNSOLVED (%s; solver %s, %d states)\n", level.ID, reason, stats.Solver, stats.StatesExplored)
+		return false
+	}
+
+	_, _ = fmt.Fprintf(w, "Level %d: %d moves (solver %s, %d states)\n", level.ID, len(solution), stats.Solver, stats.StatesExplored)
+	_, _ = fmt.Fprintf(w, "Order: %s\n", strings.Join(solution, " -> "))
+	if noSnapshots {
+		return true
+	}
+
+	_, _ = fmt.Fprintln(w, "\nStart:")
+	common.RenderLevelToWriter(w, level, style, coords)
+
+	board := *level
+	board.Vines = append([]model.Vine(nil), level.Vines...)
+	for i, id := range solution {
+		board.Vines = removeVine(board.Vines, id)
+		_, _ = fmt.Fprintf(w, "\nMove %d/%d: clear %s\n", i+1, len(solution), id)
+		common.RenderLevelToWriter(w, &board, style, coords)
+	}
+	return true
+}
+
+func removeVine(vines []model.Vine, id string) []model.Vine {
+	kept := vines[:0]
+	for _, v := range vines {
+		if v.ID != id {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,153 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	batchsvc "github.com/eng618/parable-bloom/tools/level-builder/pkg/batch"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+)
+
+var (
+	levelDirs []string
+	top       int
+	jsonOut   string
+)
+
+// statsCmd groups the reports over generated levels and solver runs.
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Report generation and solver statistics",
+	Long: `Report statistics about generated levels and solver runs.
+
+Examples:
+  level-builder stats levels
+  level-builder stats levels --dir assets/levels --top 5 --json-out level_stats.json
+  level-builder stats solver validation_stats.json`,
+}
+
+// levelsCmd represents the stats levels command
+var levelsCmd = &cobra.Command{
+	Use:   "levels",
+	Short: "Aggregate the generation telemetry stored in level files",
+	Long: `Aggregate the generation telemetry stored in level files.
+
+Generated levels record the retry budget they used: generation_strategy,
+generation_attempts, generation_relaxations, generation_backtracks and
+generation_elapsed_ms. Unlike budget, which needs a run's --stats-out
+directory, this reads the level files themselves, so it covers every level
+that has been generated.
+
+The report shows the per-tier budget, a per-strategy breakdown within each
+tier and the most expensive levels. Tiers and strategies whose share of
+generation time exceeds their share of levels are flagged. Levels without
+telemetry (hand-made levels, lessons, mirrors, older runs) are skipped.
+
+Examples:
+  level-builder stats levels
+  level-builder stats levels --dir assets/levels --dir tmp/levels --top 20
+  level-builder stats levels --json-out level_stats.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dirs := levelDirs
+		if len(dirs) == 0 {
+			levelsDir, err := common.LevelsDir()
+			if err != nil {
+				return fmt.Errorf("failed to resolve levels directory: %w", err)
+			}
+			dirs = []string{levelsDir}
+		}
+		results, untracked, err := batchsvc.LoadResultsFromLevels(dirs...)
+		if err != nil {
+			return fmt.Errorf("failed to load levels: %w", err)
+		}
+		if len(results) == 0 {
+			return fmt.Errorf("no levels with generation telemetry found in %v", dirs)
+		}
+
+		report := batchsvc.BuildLevelStatsReport(results, untracked, top)
+		report.WriteText(cmd.OutOrStdout())
+
+		if jsonOut != "" {
+			if err := report.WriteJSON(jsonOut); err != nil {
+				return err
+			}
+			common.Info("Wrote level stats report: %s", jsonOut)
+		}
+		return nil
+	},
+}
+
+// solverCmd represents the stats solver command
+var solverCmd = &cobra.Command{
+	Use:   "solver <file> [file...]",
+	Short: "Summarize solver effort from validation stats files",
+	Long: `Summarize solver effort from validation stats files (validation_stats.json,
+written by validate --check-solvable): levels, mean and max states explored,
+and mean solve time per file.
+
+Examples:
+  level-builder stats solver validation_stats.json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, p := range args {
+			if err := summarizeSolver(cmd.OutOrStdout(), p); err != nil {
+				common.Warning("error summarizing %s: %v", p, err)
+			}
+		}
+		return nil
+	},
+}
+
+// solverStat is one entry of a validation stats file.
+type solverStat struct {
+	File           string `json:"file"`
+	LevelID        int    `json:"level_id"`
+	StatesExplored int    `json:"states_explored"`
+	TimeMs         int64  `json:"time_ms"`
+}
+
+func summarizeSolver(w io.Writer, path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var arr []solverStat
+	if err := json.Unmarshal(b, &arr); err != nil {
+		return err
+	}
+	totalStates := 0
+	maxStates := 0
+	totalTime := int64(0)
+	for _, s := range arr {
+		totalStates += s.StatesExplored
+		if s.StatesExplored > maxStates {
+			maxStates = s.StatesExplored
+		}
+		totalTime += s.TimeMs
+	}
+	n := len(arr)
+	if n == 0 {
+		_, _ = fmt.Fprintf(w, "%s: levels=0 avg_states=0.0 max_states=0 avg_time_ms=0.0\n", path)
+		return nil
+	}
+	_, _ = fmt.Fprintf(w, "%s: levels=%d avg_states=%.1f max_states=%d avg_time_ms=%.1f\n", path, n, float64(totalStates)/float64(n), maxStates, float64(totalTime)/float64(n))
+	return nil
+}
+
+func init() {
+	levelsCmd.Flags().StringArrayVar(&levelDirs, "dir", nil, "level directory to include (repeatable; default: assets/levels)")
+	levelsCmd.Flags().IntVar(&top, "top", 10, "number of most expensive levels to list")
+	levelsCmd.Flags().StringVar(&jsonOut, "json-out", "", "optional path to write the report as JSON")
+
+	statsCmd.AddCommand(levelsCmd)
+	statsCmd.AddCommand(solverCmd)
+}
+
+// GetCommand returns the stats command for registration with root
+func GetCommand() *cobra.Command {
+	return statsCmd
+}
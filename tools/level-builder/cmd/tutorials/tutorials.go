@@ -2,23 +2,51 @@ package tutorials
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/lessons"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
 )
 
 var (
 	checkSolvable bool
 	maxStates     int
+
+	lessonID  int
+	pattern   string
+	outPath   string
+	overwrite bool
+	dryRun    bool
 )
 
-// tutorialsCmd represents the validate-tutorials command
+// tutorialsCmd represents the tutorials command. Run without a subcommand it
+// validates, as the validate-tutorials command it replaces did.
 var tutorialsCmd = &cobra.Command{
-	Use:     "validate-tutorials",
-	Aliases: []string{"tut", "tutorials"},
-	Short:   "Validate tutorial/lesson levels",
+	Use:     "tutorials",
+	Aliases: []string{"tut", "validate-tutorials"},
+	Short:   "Validate or generate tutorial/lesson levels",
+	Long: `Validate and generate tutorial and lesson levels.
+
+Run without a subcommand, tutorials validates (see tutorials validate).
+
+Examples:
+  level-builder tutorials validate --check-solvable
+  level-builder tutorials generate --lesson 2
+  level-builder validate-tutorials`,
+	RunE: runValidate,
+}
+
+// validateCmd represents the tutorials validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate tutorial/lesson levels",
 	Long: `Validate tutorial and lesson levels with relaxed rules.
 
 Unlike main level validation, tutorial levels allow:
@@ -26,31 +54,153 @@ Unlike main level validation, tutorial levels allow:
   - Smaller grids for teaching specific mechanics
   - Simplified color schemes
 
-Structural validation is still performed, and solvability checks
-can be optionally enabled.
+Structural validation is still performed, including the instructional text
+limits the game enforces, and solvability checks can be optionally enabled.
 
 Examples:
-  level-builder validate-tutorials
-  level-builder tut --check-solvable
-  level-builder tutorials --check-solvable --max-states 50000 --verbose`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		common.Info("Starting tutorial/lesson validation...")
-		common.Verbose("Check solvable: %v, Max states: %d", checkSolvable, maxStates)
-
-		if err := validator.ValidateTutorials(checkSolvable, maxStates); err != nil {
-			return fmt.Errorf("tutorial validation failed: %w", err)
-		}
+  level-builder tutorials validate
+  level-builder tut validate --check-solvable
+  level-builder tutorials validate --check-solvable --max-states 50000 --verbose`,
+	RunE: runValidate,
+}
 
-		return nil
-	},
+// generateCmd represents the tutorials generate command
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate a teaching lesson from a templated pattern",
+	Long: `Generate an ultra-simple teaching lesson from a templated pattern.
+
+Patterns, in lesson order:
+  straight   a single straight vine
+  block      one vine blocking another's exit
+  lifo-pair  two vines in a row; the front one leaves first
+
+--lesson N uses the Nth pattern unless --pattern picks another. The lesson
+gets the instructional fields the game requires (title, objective,
+instructions, learning_points) and is checked against the lesson rules, for
+solvability and for the clear order it teaches before it is written.
+
+The lesson is written to assets/lessons/lesson_N.json unless --out names
+another file ("-" for stdout). Register new lessons in modules.json
+(tutorials and level_mappings) for the game to load them.
+
+--dry-run generates and checks the lesson the same way, then prints its
+board, steps and the path it would be written to; no file is written.
+
+Examples:
+  level-builder tutorials generate --lesson 1 --out -
+  level-builder tutorials generate --lesson 4 --pattern block --dry-run
+  level-builder tutorials generate --lesson 6 --pattern lifo-pair
+  level-builder tutorials generate --lesson 3 --overwrite`,
+	RunE: runGenerate,
 }
 
 func init() {
-	tutorialsCmd.Flags().BoolVarP(&checkSolvable, "check-solvable", "s", false, "run solvability checks (may be slow)")
-	tutorialsCmd.Flags().IntVar(&maxStates, "max-states", 100000, "max states budget for solver heuristic")
+	for _, cmd := range []*cobra.Command{tutorialsCmd, validateCmd} {
+		cmd.Flags().BoolVarP(&checkSolvable, "check-solvable", "s", false, "run solvability checks (may be slow)")
+		cmd.Flags().IntVar(&maxStates, "max-states", 100000, "max states budget for solver heuristic")
+	}
+
+	generateCmd.Flags().IntVar(&lessonID, "lesson", 0, "lesson number to generate (required)")
+	generateCmd.Flags().StringVar(&pattern, "pattern", "",
+		fmt.Sprintf("teaching pattern: %s (default: the lesson's own)", strings.Join(lessons.PatternNames(), ", ")))
+	generateCmd.Flags().StringVarP(&outPath, "out", "o", "", `output file, "-" for stdout (default: assets/lessons/lesson_N.json)`)
+	generateCmd.Flags().BoolVar(&overwrite, "overwrite", false, "replace an existing lesson file")
+	generateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the lesson and where it would go without writing files")
+	_ = generateCmd.MarkFlagRequired("lesson")
+
+	tutorialsCmd.AddCommand(validateCmd, generateCmd)
 }
 
-// GetCommand returns the tutorials validation command for registration with root
+// GetCommand returns the tutorials command for registration with root
 func GetCommand() *cobra.Command {
 	return tutorialsCmd
 }
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	common.Info("Starting tutorial/lesson validation...")
+	common.Verbose("Check solvable: %v, Max states: %d", checkSolvable, maxStates)
+
+	if err := validator.ValidateTutorials(checkSolvable, maxStates); err != nil {
+		return fmt.Errorf("tutorial validation failed: %w", err)
+	}
+
+	return nil
+}
+
+func runGenerate(cmd *cobra.Command, args []string) error {
+	if lessonID < 1 {
+		return fmt.Errorf("--lesson must be at least 1")
+	}
+	if outPath == "-" && !dryRun {
+		common.ReserveStdout()
+	}
+	p, err := lessons.ForLesson(lessonID)
+	if pattern != "" {
+		p, err = lessons.Find(pattern)
+	}
+	if err != nil {
+		return err
+	}
+
+	lesson, err := lessons.Generate(lessonID, p)
+	if err != nil {
+		return fmt.Errorf("failed to generate lesson %d: %w", lessonID, err)
+	}
+
+	data, err := common.MarshalCanonical(lesson, common.LessonIndent)
+	if err != nil {
+		return err
+	}
+
+	if outPath == "-" && !dryRun {
+		_, err := cmd.OutOrStdout().Write(data)
+		return err
+	}
+	path := outPath
+	if path == "" {
+		lessonsDir, err := common.LessonsDir()
+		if err != nil {
+			return fmt.Errorf("failed to resolve lessons directory: %w", err)
+		}
+		path = filepath.Join(lessonsDir, fmt.Sprintf("lesson_%d.json", lessonID))
+	}
+	if dryRun {
+		return printDryRun(cmd.OutOrStdout(), lesson, p, path)
+	}
+	if _, err := os.Stat(path); err == nil && !overwrite {
+		return fmt.Errorf("%s already exists (use --overwrite to replace it)", path)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	common.Info("Wrote lesson %d (%s pattern): %s", lessonID, p.Name, path)
+	return nil
+}
+
+// printDryRun describes the generated lesson and where it would be written.
+func printDryRun(w io.Writer, lesson model.Lesson, p lessons.Pattern, path string) error {
+	lvl := lesson.Level()
+	cells := 0
+	for _, v := range lesson.Vines {
+		cells += len(v.OrderedPath)
+	}
+	steps := make([]string, len(lesson.Steps))
+	for i, s := range lesson.Steps {
+		steps[i] = s.Vine
+	}
+
+	_, _ = fmt.Fprintf(w, "%s (%s pattern)\n", lesson.Title, p.Name)
+	_, _ = fmt.Fprintf(w, "Grid %dx%d, %d vines, %d cells, steps: %s\n",
+		lesson.GridSize[0], lesson.GridSize[1], len(lesson.Vines), cells, strings.Join(steps, " -> "))
+	common.RenderHighlightedToWriter(w, &lvl, "unicode", false, lesson.HighlightVines)
+
+	target := path
+	if path == "-" {
+		target = "stdout"
+	} else if _, err := os.Stat(path); err == nil && !overwrite {
+		target += " (exists; needs --overwrite)"
+	}
+	_, _ = fmt.Fprintf(w, "Dry run: would write %s\n", target)
+	return nil
+}
@@ -0,0 +1,53 @@
+package tutorials
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runGenerateArgs runs tutorials generate with args and returns its output.
+func runGenerateArgs(t *testing.T, args ...string) string {
+	t.Helper()
+	t.Cleanup(func() {
+		lessonID, pattern, outPath, overwrite, dryRun = 0, "", "", false, false
+	})
+	var out bytes.Buffer
+	tutorialsCmd.SetOut(&out)
+	tutorialsCmd.SetArgs(append([]string{"generate"}, args...))
+	if err := tutorialsCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+
+func TestGenerateDryRunWritesNothing(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "lesson_4.json")
+
+	out := runGenerateArgs(t, "--lesson", "4", "--pattern", "block", "--out", path, "--dry-run")
+	if !strings.Contains(out, "Dry run: would write "+path) {
+		t.Errorf("output does not name the target:\n%s", out)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("dry run wrote %d entries, e.g. %s", len(entries), entries[0].Name())
+	}
+
+	// An existing lesson is left alone, even with --overwrite, and no backup is taken
+	if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGenerateArgs(t, "--lesson", "4", "--out", path, "--overwrite", "--dry-run")
+	if data, err := os.ReadFile(path); err != nil || string(data) != "{}" {
+		t.Errorf("dry run changed the existing lesson: %q, %v", data, err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("dry run left %d entries, want only the existing lesson", len(entries))
+	}
+}
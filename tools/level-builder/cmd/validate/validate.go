@@ -1,11 +1,18 @@
 package validate
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/prom"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
 )
 
@@ -14,7 +21,26 @@ var (
 	maxStates       int
 	useAstar        bool
 	astarWeight     int
+	maxMemoryMB     int
+	perLevelTimeout time.Duration
+	totalTimeout    time.Duration
 	ignoreOccupancy bool
+	reportFormat    string
+	reportOut       string
+	auditSolvers    bool
+	auditOut        string
+	watch           bool
+	onlyRules       []string
+	skipRules       []string
+	listRules       bool
+	strict          bool
+	partialMoves    bool
+	growingVines    bool
+	reveals         map[string]int
+	metricsOut      string
+	fileFlags       []string
+	idFlags         []int
+	packFlag        string
 )
 
 // validateCmd represents the validate command
@@ -34,22 +60,115 @@ When --check-solvable is enabled, the validator uses advanced solvers
 to ensure all levels can be completed. Results are written to
 validation_stats.json for analysis.
 
+The game ends once max_moves run out, so a level counts as solvable only
+when the solution the solver finds fits in max_moves. A level solvable only
+past its budget fails with its solution length.
+
+By default a tapped vine either leaves the grid or, when blocked, bumps and
+returns to where it was, as in the game. --partial-moves instead lets a
+blocked vine advance up to its blocker and stay there, at the cost of a move,
+and solves levels under that rule. Such solutions can take more moves than
+there are vines, so max_moves is checked against them. --growing-vines
+extends every remaining vine's tail by one cell after each move, and
+--reveals keeps the named vines off the grid until that many moves have been
+made (vine_3=2 reveals vine_3 after the second move, in every level that has
+one). Verdicts under these mechanics are not cached and unsolvable levels are
+not diagnosed.
+
+When a level is proven unsolvable, a diagnostic pass shrinks it to a minimal
+set of vines that still cannot all be cleared and reports that set, the
+cycle in which they block each other's exit rays (or the locks that can
+never open) and a board render highlighting them. Levels the solver gave up
+on are not diagnosed.
+
+--report-format selects how results are reported: text (default), json,
+junit or markdown. With --report-out the report is written to that file and
+the text summary still goes to stdout, which suits CI jobs that publish JUnit
+results. Without it the report replaces the text summary on stdout.
+
+--max-memory-mb caps the state table of each solver search (one runs per
+CPU). A search that fills it continues depth-first, remembering only a
+bounded set of dead-end states, so huge levels are still checked within
+--max-states instead of exhausting memory. 0 removes the cap.
+
+--per-level-timeout bounds the wall-clock time of each level's solver search
+and --total-timeout the whole solvability pass, so a pathological level
+cannot stall CI. A level whose search runs out of time fails as timed out
+rather than unsolvable and is not cached. When the total timeout runs out,
+searches in flight stop as timed out, the remaining levels get their
+structural checks only and are reported as skipped, and the report is still
+written in full; the run fails so the gap is not mistaken for a pass. 0 (the
+default) leaves either unbounded.
+
+Structural checks are a registry of named rules, each with a severity.
+--list-rules prints them; --only-rule runs just the named rules and
+--skip-rule leaves rules out (both repeatable or comma separated). Every
+level's report lists the rules that ran and their findings, and failures
+name the rules that failed.
+
+Every finding has a severity. Errors fail the level; warnings, such as
+unmasked empty cells or low occupancy under --ignore-occupancy, are listed
+in the report but pass; info findings (the uncovered cells themselves) are
+shown with --verbose. --strict promotes warnings to errors so CI fails on
+them.
+
+--watch validates every level once and then keeps watching the levels
+directory: each level file that is saved, added or removed is re-validated on
+its own and its pass/fail result printed immediately, followed by the levels
+still failing. It runs until interrupted and prints text only.
+
+--id and --file validate only the selected levels instead of the whole
+levels directory, with the same checks, cache and report formats;
+modules.json is not checked. --id takes level IDs from the levels directory.
+--file takes level file paths or glob patterns (quote them so the shell
+leaves them alone). Both are repeatable and can be combined. An ID without a
+file or a pattern matching nothing fails the run. A lone --file "-" reads
+one level from stdin, so generated or converted levels can be piped in
+without a temp file. File names must still match the level ID; a piped level
+is named after its ID.
+
+--pack validates the levels inside a .pbpack level library (written by
+export pack --out levels.pbpack) without extracting it; --id picks levels
+from the library. Reports name each level as <library>:level_<id>.json, and
+a level whose bytes no longer match the library index fails.
+
+--metrics-out writes the run's outcome counts, per-solver check times and
+states explored, and the time and result of the run in Prometheus textfile
+format, for node_exporter's textfile collector to pick up from scheduled
+runs. The file is replaced atomically on every run.
+
+--audit-solvers skips normal validation and instead runs the greedy, exact
+BFS and A* solvers on every level with the same --max-states budget. It
+prints per-solver totals (verdicts, states, time) and every level where
+conclusive verdicts disagree or a returned clear order fails replay, writes
+the full comparison to solver_audit.json (--audit-out) and fails when any
+level disagrees. Searches that exhaust the budget are inconclusive.
+
 Examples:
   level-builder validate
   level-builder val --check-solvable
   level-builder v --check-solvable --max-states 100000 --verbose
-  level-builder validate --check-solvable --use-astar --astar-weight 10`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		common.Info("Starting level validation...")
-		common.Verbose("Check solvable: %v, Max states: %d, Use A*: %v, A* weight: %d",
-			checkSolvable, maxStates, useAstar, astarWeight)
-
-		if err := validator.Validate(checkSolvable, maxStates, useAstar, astarWeight, ignoreOccupancy); err != nil {
-			return fmt.Errorf("validation failed: %w", err)
-		}
-
-		return nil
-	},
+  level-builder validate --check-solvable --use-astar --astar-weight 10
+  level-builder validate --check-solvable --max-memory-mb 256
+  level-builder validate --check-solvable --partial-moves
+  level-builder validate --check-solvable --growing-vines --id 42
+  level-builder validate --check-solvable --reveals vine_3=2,vine_5=4 --id 42
+  level-builder validate --check-solvable --metrics-out /var/lib/node_exporter/level_builder_validate.prom
+  level-builder validate --check-solvable --per-level-timeout 30s --total-timeout 10m
+  level-builder validate --check-solvable --report-format junit --report-out validation.xml
+  level-builder validate --report-format markdown
+  level-builder validate --watch --check-solvable
+  level-builder validate --strict --report-format junit --report-out validation.xml
+  level-builder validate --list-rules
+  level-builder validate --skip-rule hints,move-budget
+  level-builder validate --only-rule overlaps --only-rule portals --report-format json
+  level-builder validate --audit-solvers --max-states 200000
+  level-builder validate --file assets/levels/level_42.json --check-solvable
+  level-builder validate --id 42 --id 43 --check-solvable
+  level-builder validate --file 'assets/levels/level_4?.json'
+  level-builder validate --pack levels.pbpack --check-solvable
+  level-builder import grid.csv --id 200 --out - | level-builder validate --file -`,
+	RunE: runValidate,
 }
 
 func init() {
@@ -57,10 +176,204 @@ func init() {
 	validateCmd.Flags().IntVar(&maxStates, "max-states", 500000, "max states budget for solver heuristic")
 	validateCmd.Flags().BoolVar(&useAstar, "use-astar", true, "use A* guided search for exact solver")
 	validateCmd.Flags().IntVar(&astarWeight, "astar-weight", validator.DefaultAStarWeight, "weight multiplier for A* heuristic")
+	validateCmd.Flags().IntVar(&maxMemoryMB, "max-memory-mb", 1024, "state table budget per solver search in MB (0 = unbounded)")
+	validateCmd.Flags().DurationVar(&perLevelTimeout, "per-level-timeout", 0, "wall-clock limit for each level's solvability check (0 = unbounded)")
+	validateCmd.Flags().DurationVar(&totalTimeout, "total-timeout", 0, "wall-clock limit for the whole solvability pass (0 = unbounded)")
+	validateCmd.Flags().BoolVar(&partialMoves, "partial-moves", false, "solve with blocked vines advancing up to their blocker instead of bumping back")
+	validateCmd.Flags().BoolVar(&growingVines, "growing-vines", false, "solve with every remaining vine's tail growing one cell after each move")
+	validateCmd.Flags().StringToIntVar(&reveals, "reveals", nil, "solve with vines revealed after a number of moves (vine_id=moves,...)")
 	validateCmd.Flags().BoolVar(&ignoreOccupancy, "ignore-occupancy", false, "ignore minimum grid occupancy threshold (useful when running quick repairs)")
+	validateCmd.Flags().StringVar(&reportFormat, "report-format", validator.FormatText,
+		"report format: "+strings.Join(validator.ReportFormats, "|"))
+	validateCmd.Flags().StringVar(&reportOut, "report-out", "", "write the report to this file instead of stdout")
+	validateCmd.Flags().StringVar(&metricsOut, "metrics-out", "", "write validation metrics in Prometheus textfile format to this file (e.g. for node_exporter)")
+	validateCmd.Flags().BoolVar(&auditSolvers, "audit-solvers", false, "compare greedy, BFS and A* verdicts on every level instead of validating")
+	validateCmd.Flags().StringVar(&auditOut, "audit-out", "solver_audit.json", "where --audit-solvers writes its JSON artifact")
+	validateCmd.Flags().BoolVar(&watch, "watch", false, "keep running and re-validate level files as they change")
+	validateCmd.Flags().StringSliceVar(&onlyRules, "only-rule", nil, "run only these structural rules (repeatable)")
+	validateCmd.Flags().StringSliceVar(&skipRules, "skip-rule", nil, "skip these structural rules (repeatable)")
+	validateCmd.Flags().BoolVar(&strict, "strict", false, "fail levels on warnings as well as errors")
+	validateCmd.Flags().BoolVar(&listRules, "list-rules", false, "list the structural rules and exit")
+	validateCmd.Flags().StringArrayVarP(&fileFlags, "file", "f", nil, `validate only these level files or glob patterns ("-" for stdin; repeatable)`)
+	validateCmd.Flags().IntSliceVarP(&idFlags, "id", "i", nil, "validate only these level IDs (repeatable)")
+	validateCmd.Flags().StringVar(&packFlag, "pack", "", "validate the levels in this .pbpack level library instead of the levels directory")
 }
 
 // GetCommand returns the validate command for registration with root
 func GetCommand() *cobra.Command {
 	return validateCmd
 }
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	selected := len(fileFlags) > 0 || len(idFlags) > 0
+	if selected && (watch || auditSolvers) {
+		return fmt.Errorf("--id and --file cannot be combined with --watch or --audit-solvers")
+	}
+	stdin := slices.Contains(fileFlags, common.StdioPath)
+	if stdin && (len(fileFlags) > 1 || len(idFlags) > 0) {
+		return fmt.Errorf(`--file %s cannot be combined with other --file or --id selections`, common.StdioPath)
+	}
+	if packFlag != "" && (len(fileFlags) > 0 || watch || auditSolvers) {
+		return fmt.Errorf("--pack cannot be combined with --file, --watch or --audit-solvers")
+	}
+	if listRules {
+		return printRules(cmd)
+	}
+	if watch {
+		return runWatch(cmd)
+	}
+	if auditSolvers {
+		return runAudit(cmd)
+	}
+	if err := validator.CheckReportFormat(reportFormat); err != nil {
+		return err
+	}
+	if perLevelTimeout < 0 || totalTimeout < 0 {
+		return fmt.Errorf("--per-level-timeout and --total-timeout must not be negative")
+	}
+
+	if reportOut != "" || reportFormat == validator.FormatText || reportFormat == validator.FormatMarkdown {
+		// Keep stdout parseable when a JSON or JUnit report is written there
+		common.Info("Starting level validation...")
+	}
+	common.Verbose("Check solvable: %v, Max states: %d, Use A*: %v, A* weight: %d, Max memory: %d MB, Timeouts: %s per level, %s total",
+		checkSolvable, maxStates, useAstar, astarWeight, maxMemoryMB, perLevelTimeout, totalTimeout)
+
+	ctx, err := validateContext(cmd)
+	if err != nil {
+		return err
+	}
+	var report validator.Report
+	switch {
+	case packFlag != "":
+		report, err = validator.ValidatePack(ctx, packFlag, idFlags, checkSolvable, maxStates, useAstar, astarWeight, ignoreOccupancy)
+	case stdin:
+		var ok bool
+		report, ok, err = validator.ValidateInput(ctx, common.StdioPath, checkSolvable, maxStates, useAstar, astarWeight, ignoreOccupancy)
+		if err == nil && !ok {
+			err = ctx.Err()
+		}
+	case selected:
+		files, serr := selectFiles()
+		if serr != nil {
+			return serr
+		}
+		report, err = validator.ValidateFiles(ctx, files, checkSolvable, maxStates, useAstar, astarWeight, ignoreOccupancy)
+	default:
+		report, err = validator.ValidateReport(ctx, checkSolvable, maxStates, useAstar, astarWeight, ignoreOccupancy)
+	}
+	if err != nil {
+		writeMetrics(nil, false)
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	writeMetrics(&report, report.Err() == nil)
+
+	if reportOut == "" {
+		if err := validator.WriteReport(cmd.OutOrStdout(), report, reportFormat); err != nil {
+			return err
+		}
+	} else {
+		report.WriteText(cmd.OutOrStdout())
+		if err := writeReportFile(report); err != nil {
+			return err
+		}
+		common.Info("Wrote %s report: %s", reportFormat, reportOut)
+	}
+
+	if err := report.Err(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	return nil
+}
+
+// writeMetrics writes the run's metrics to --metrics-out, if set. A run that
+// stopped without a report still records that it ran and failed.
+func writeMetrics(report *validator.Report, ok bool) {
+	if metricsOut == "" {
+		return
+	}
+	reg := prom.NewRegistry()
+	if report != nil {
+		reg.ObserveValidation(*report)
+	}
+	reg.MarkRun("validate", time.Now(), ok)
+	if err := reg.WriteFile(metricsOut); err != nil {
+		common.Warning("%v", err)
+		return
+	}
+	common.Verbose("Wrote metrics: %s", metricsOut)
+}
+
+// selectFiles resolves --id and --file to the level files to validate.
+func selectFiles() ([]string, error) {
+	levelsDir := ""
+	if len(idFlags) > 0 {
+		dir, err := common.LevelsDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve levels directory: %w", err)
+		}
+		levelsDir = dir
+	}
+	return validator.SelectLevelFiles(levelsDir, idFlags, fileFlags)
+}
+
+// validateContext carries the worker, memory, time, rule, strictness and mechanics settings into
+// the validator.
+func validateContext(cmd *cobra.Command) (context.Context, error) {
+	rules, err := validator.NewRuleFilter(onlyRules, skipRules)
+	if err != nil {
+		return nil, err
+	}
+	ctx := validator.WithWorkers(cmd.Context(), common.Workers)
+	ctx = validator.WithTimeouts(validator.WithMaxMemory(ctx, maxMemoryMB), perLevelTimeout, totalTimeout)
+	ctx = validator.WithMechanics(ctx, validator.Mechanics{
+		GrowingVines: growingVines,
+		Reveals:      reveals,
+		PartialMoves: partialMoves,
+	})
+	return validator.WithStrict(validator.WithRuleFilter(ctx, rules), strict), nil
+}
+
+func printRules(cmd *cobra.Command) error {
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "RULE\tSEVERITY\tDESCRIPTION")
+	for _, r := range validator.ListRules() {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", r.ID, r.Severity, r.Description)
+	}
+	return tw.Flush()
+}
+
+func writeReportFile(report validator.Report) error {
+	f, err := os.Create(reportOut)
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %w", err)
+	}
+	if err := validator.WriteReport(f, report, reportFormat); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return f.Close()
+}
+
+func runAudit(cmd *cobra.Command) error {
+	levelsDir, err := common.LevelsDir()
+	if err != nil {
+		return fmt.Errorf("failed to resolve levels directory: %w", err)
+	}
+	common.Info("Auditing solvers on %s (max states %d)...", levelsDir, maxStates)
+
+	audit, err := validator.AuditLevels(validator.WithMaxMemory(cmd.Context(), maxMemoryMB), levelsDir, maxStates, astarWeight)
+	if err != nil {
+		return fmt.Errorf("solver audit failed: %w", err)
+	}
+	audit.WriteText(cmd.OutOrStdout())
+	if err := audit.Save(auditOut); err != nil {
+		return fmt.Errorf("failed to write %s: %w", auditOut, err)
+	}
+	common.Info("Wrote solver audit: %s", auditOut)
+
+	if audit.Disagreements > 0 {
+		return fmt.Errorf("solvers disagree on %d levels", audit.Disagreements)
+	}
+	return nil
+}
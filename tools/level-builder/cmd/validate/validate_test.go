@@ -0,0 +1,72 @@
+package validate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+func mustVine(t *testing.T, id string, path ...model.Point) model.Vine {
+	t.Helper()
+	v, err := model.NewVine(id, path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+// solveWithFlags parses args onto the validate command and solves lvl under
+// the context it builds.
+func solveWithFlags(t *testing.T, lvl model.Level, args ...string) (bool, validator.LevelStat) {
+	t.Helper()
+	t.Cleanup(func() {
+		growingVines, reveals, partialMoves = false, nil, false
+	})
+	if err := validateCmd.ParseFlags(args); err != nil {
+		t.Fatal(err)
+	}
+	validateCmd.SetContext(context.Background())
+	ctx, err := validateContext(validateCmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, stat, err := validator.IsSolvableWithStatsContext(ctx, lvl, 1000, true, validator.DefaultAStarWeight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ok, stat
+}
+
+func TestGrowingVinesFlag(t *testing.T) {
+	// C clears first; A then grows its tail into B's only exit but can still
+	// leave and free B.
+	lvl := model.Level{GridSize: []int{3, 3}, Vines: []model.Vine{
+		mustVine(t, "A", model.Point{X: 0, Y: 2}, model.Point{X: 1, Y: 2}),
+		mustVine(t, "B", model.Point{X: 2, Y: 1}, model.Point{X: 2, Y: 0}),
+		mustVine(t, "C", model.Point{X: 0, Y: 0}, model.Point{X: 1, Y: 0}),
+	}}
+
+	if _, stat := solveWithFlags(t, lvl); stat.Solver == "full-state" {
+		t.Fatal("plain validation used the full-state solver")
+	}
+	ok, stat := solveWithFlags(t, lvl, "--growing-vines")
+	if !ok || stat.Solver != "full-state" {
+		t.Fatalf("--growing-vines: solvable = %v with solver %q, want solvable by the full-state solver", ok, stat.Solver)
+	}
+}
+
+func TestRevealsFlag(t *testing.T) {
+	// B is blocked by A, so A must move first, and Z then appears on B's neck.
+	lvl := model.Level{GridSize: []int{4, 3}, Vines: []model.Vine{
+		mustVine(t, "A", model.Point{X: 0, Y: 0}, model.Point{X: 1, Y: 0}),
+		mustVine(t, "B", model.Point{X: 1, Y: 1}, model.Point{X: 1, Y: 2}),
+		mustVine(t, "Z", model.Point{X: 1, Y: 2}, model.Point{X: 0, Y: 2}),
+	}}
+
+	ok, stat := solveWithFlags(t, lvl, "--reveals", "Z=1")
+	if ok || stat.Solver != "full-state" {
+		t.Fatalf("--reveals Z=1: solvable = %v with solver %q, want unsolvable by the full-state solver", ok, stat.Solver)
+	}
+}
@@ -0,0 +1,96 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+// watchDebounce is how long the levels directory must be quiet before changed files are
+// re-validated, so an editor's save lands as one change.
+const watchDebounce = 200 * time.Millisecond
+
+// runWatch validates every level once, then re-validates each level file as it changes
+// until the command is interrupted.
+func runWatch(cmd *cobra.Command) error {
+	if auditSolvers || reportOut != "" || reportFormat != validator.FormatText {
+		return fmt.Errorf("--watch prints text results and cannot be combined with --audit-solvers, --report-format or --report-out")
+	}
+	levelsDir, err := common.LevelsDir()
+	if err != nil {
+		return fmt.Errorf("failed to resolve levels directory: %w", err)
+	}
+
+	ctx, err := validateContext(cmd)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	report, err := validator.ValidateReport(ctx, checkSolvable, maxStates, useAstar, astarWeight, ignoreOccupancy)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	report.WriteText(out)
+
+	failing := make(map[string]bool)
+	for _, l := range report.Levels {
+		if !l.Passed() {
+			failing[l.File] = true
+		}
+	}
+	common.Info("Watching %s for changes (Ctrl+C to stop)...", levelsDir)
+
+	return common.WatchFiles(ctx, levelsDir, "level_*.json", watchDebounce, func(paths []string) {
+		_, _ = fmt.Fprintln(out)
+		for _, path := range paths {
+			name := filepath.Base(path)
+			stamp := time.Now().Format("15:04:05")
+			if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+				delete(failing, name)
+				_, _ = fmt.Fprintf(out, "[%s] %s removed\n", stamp, name)
+				continue
+			}
+
+			result, ok := validator.ValidateFile(ctx, path, checkSolvable, maxStates, useAstar, astarWeight, ignoreOccupancy)
+			if !ok {
+				return
+			}
+			_, _ = fmt.Fprintf(out, "[%s] ", stamp)
+			result.WriteText(out)
+			if result.Passed() {
+				delete(failing, name)
+			} else {
+				failing[name] = true
+			}
+		}
+		writeFailing(out, failing)
+	})
+}
+
+// writeFailing prints the levels that currently fail, or that all pass.
+func writeFailing(w io.Writer, failing map[string]bool) {
+	if len(failing) == 0 {
+		_, _ = fmt.Fprintln(w, "✓ All levels pass")
+		return
+	}
+	names := make([]string, 0, len(failing))
+	for name := range failing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	_, _ = fmt.Fprintf(w, "❌ %d levels failing: %s\n", len(names), strings.Join(names, ", "))
+}
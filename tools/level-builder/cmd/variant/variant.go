@@ -0,0 +1,154 @@
+package variant
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/variant"
+)
+
+var (
+	idFlag        int
+	mutationsFlag int
+	countFlag     int
+	seedFlag      int64
+	kindsFlag     []string
+	outIDFlag     int
+	dirFlag       string
+	maxStatesFlag int
+	overwriteFlag bool
+	backupFlag    bool
+	dryRunFlag    bool
+)
+
+// variantCmd remixes a level into gameplay-preserving variants.
+var variantCmd = &cobra.Command{
+	Use:   "variant",
+	Short: "Remix a level into gameplay-preserving variants",
+	Long: `Write variants of an existing level for A/B tests or remixed challenge
+modes. A variant applies --mutations distinct mutations, picked at random
+from --kinds:
+
+  mirror    reflect the grid horizontally or vertically
+  rotate    turn the grid 90, 180 or 270 degrees clockwise; a quarter turn
+            swaps the grid's width and height
+  recolor   permute the colors the vines use
+  swap      two equal-length vines of different colors trade places
+
+None of them changes how the level plays, and each variant is checked
+before it is written: the same vine lengths, blocking relations, move
+budget and solvability as the source, and identical difficulty metrics
+when both are scored along the same solution. A variant that fails is an
+error, never a file.
+
+Variants are written as level_<id>.json, numbered from --out-id (default:
+the first ID above every level in --dir), named "<source> (Variant N)" and
+linked back through variant_of and variant_mutations. They are not added
+to modules.json. The same --seed always gives the same variants; it
+defaults to the source level's ID.
+
+Examples:
+  level-builder variant --id 12 --mutations 3
+  level-builder variant --id 12 --count 4 --kinds rotate,recolor --mutations 2
+  level-builder variant --id 12 --out-id 500 --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runVariant,
+}
+
+func init() {
+	variantCmd.Flags().IntVar(&idFlag, "id", 0, "Source level ID (required)")
+	variantCmd.Flags().IntVarP(&mutationsFlag, "mutations", "m", 3, "Distinct mutations per variant")
+	variantCmd.Flags().IntVarP(&countFlag, "count", "c", 1, "Number of variants to write")
+	variantCmd.Flags().Int64Var(&seedFlag, "seed", 0, "Seed for picking mutations (default: the source level ID)")
+	variantCmd.Flags().StringSliceVar(&kindsFlag, "kinds", variant.Kinds, "Mutations to choose from: "+strings.Join(variant.Kinds, ", "))
+	variantCmd.Flags().IntVar(&outIDFlag, "out-id", 0, "ID of the first variant (default: next unused level ID)")
+	variantCmd.Flags().StringVarP(&dirFlag, "dir", "d", "", "Levels directory (default: assets/levels)")
+	variantCmd.Flags().IntVar(&maxStatesFlag, "max-states", 1000000, "Solver state budget for verification")
+	variantCmd.Flags().BoolVarP(&overwriteFlag, "overwrite", "o", false, "Replace level files that already exist")
+	variantCmd.Flags().BoolVar(&backupFlag, "backup", true, "Back up levels to <dir>/.backups before replacing them")
+	variantCmd.Flags().BoolVarP(&dryRunFlag, "dry-run", "n", false, "Verify and print the variants without writing files")
+	_ = variantCmd.MarkFlagRequired("id")
+}
+
+// GetCommand returns the variant command for registration with root
+func GetCommand() *cobra.Command {
+	return variantCmd
+}
+
+func runVariant(cmd *cobra.Command, args []string) error {
+	if countFlag < 1 {
+		return fmt.Errorf("--count must be at least 1, got %d", countFlag)
+	}
+	dir := dirFlag
+	if dir == "" {
+		var err error
+		if dir, err = common.LevelsDir(); err != nil {
+			return fmt.Errorf("failed to resolve levels directory: %w", err)
+		}
+	}
+	src, err := common.ReadLevel(common.GetLevelFilePath(idFlag, dir))
+	if err != nil {
+		return err
+	}
+
+	nextID := outIDFlag
+	if nextID <= 0 {
+		if nextID, err = nextLevelID(dir); err != nil {
+			return err
+		}
+	}
+	seed := seedFlag
+	if !cmd.Flags().Changed("seed") {
+		seed = int64(src.ID)
+	}
+
+	common.BackupOnOverwrite = backupFlag && !dryRunFlag
+	out := cmd.OutOrStdout()
+	for n := 1; n <= countFlag; n++ {
+		id := nextID + n - 1
+		v, err := variant.Generate(src, id, variant.Options{
+			Mutations: mutationsFlag,
+			Kinds:     kindsFlag,
+			Seed:      seed + int64(n-1),
+			Number:    n,
+		})
+		if err != nil {
+			return err
+		}
+		if err := variant.Verify(*src, v, maxStatesFlag); err != nil {
+			return fmt.Errorf("variant %d of level %d is not equivalent: %w", n, src.ID, err)
+		}
+
+		path := common.GetLevelFilePath(id, dir)
+		mutations := strings.Join(v.VariantMutations, ", ")
+		if dryRunFlag {
+			_, _ = fmt.Fprintf(out, "Would write %s: %s (%s)\n", path, v.Name, mutations)
+			continue
+		}
+		if err := common.WriteLevel(path, &v, overwriteFlag); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(out, "Wrote %s: %s (%s)\n", path, v.Name, mutations)
+	}
+	return nil
+}
+
+// nextLevelID returns the first ID above every level_<id>.json in dir.
+func nextLevelID(dir string) (int, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "level_*.json"))
+	if err != nil {
+		return 0, err
+	}
+	next := 1
+	for _, p := range paths {
+		var id int
+		if _, err := fmt.Sscanf(filepath.Base(p), "level_%d.json", &id); err == nil && id >= next {
+			next = id + 1
+		}
+	}
+	return next, nil
+}
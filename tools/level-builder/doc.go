@@ -68,11 +68,135 @@
 //	--count           Number of modules to generate
 //	--base-difficulty Base difficulty for progression
 //
+// ## batch
+//
+// Generate all 21 levels of a module (5 per tier plus a Transcendent
+// challenge). Each level's outcome is recorded in generation_metadata.json
+// beside the level files as soon as it finishes, so a long run that stops
+// partway can be continued:
+//
+//	level-builder batch --module 2
+//	level-builder batch --module 2 --resume
+//
+// With --resume, levels recorded as done whose files still validate are
+// skipped and generation restarts at the first missing or failed level.
+//
+// Cells the placer leaves empty are masked so every visible cell is covered.
+// --mask-mode hide (default) lists the hidden cells; --mask-mode show lists the
+// occupied cells instead, giving the level an explicit playable region:
+//
+//	level-builder batch --module 3 --mask-mode show
+//
+// --stencil leaves a pattern, such as letters or an icon, empty but visible in
+// every level. The file draws it one grid row per line, top row first: '.' or
+// a space is a playable cell and any other character is a gap. The pattern is
+// centered on each grid and may cover at most half of it. Vines never occupy
+// the gaps and every other cell is covered, so the level gets a "show-all"
+// mask listing the gaps instead of hidden cells. Without --strategy or a
+// profile strategy, stencil levels use full-coverage placement:
+//
+//	level-builder batch --module 3 --stencil heart.txt
+//
+// Every level gets a theme derived from the module's theme_seed: the biome,
+// a decoration density and a few accent cells among the empty or masked ones,
+// so the game can theme the board. Mirrors reflect the accent cells.
+//
+// --portals links 1-2 pairs of empty cells with portals on Nurturing and
+// higher tiers. A vine head entering one portal cell continues from its twin,
+// and only pairs that keep the level solvable are kept:
+//
+//	level-builder batch --module 4 --portals
+//
+// --inject-difficulty reverses or moves placed vines, one at a time, until
+// the longest blocker chain reaches the middle of the tier's blocking depth
+// range. Each edit is checked for blocking cycles, so levels stay solvable:
+//
+//	level-builder batch --module 5 --inject-difficulty
+//
+// --optimize-iterations N runs N simulated annealing moves over the placed
+// vines (reverse, regrow, grow or trim a tail), scoring coverage, aesthetics
+// and closeness to the blocking depth target together. Only moves that keep
+// the level solvable are tried, and the best placement seen is kept:
+//
+//	level-builder batch --module 3 --optimize-iterations 500
+//
+// --hints N stores the first N vines of a solution in each level's hints
+// field, so the game can offer hints without a solver of its own:
+//
+//	level-builder batch --module 2 --hints 3
+//
+// Every generated level also carries the whole solution as solution_order.
+// validate replays it in linear time instead of searching ("embedded" in the
+// solver stats), and falls back to a search when an edit has made it stale.
+//
+// --profile applies a generation profile: aesthetic (long winding vines),
+// dense (full coverage) or speedrun (few long vines). It sets the strategy,
+// coverage target, backtracking and vine-length mix, which --strategy,
+// --min-coverage and --aggressive still override, and each level records it
+// in generation_profile:
+//
+//	level-builder batch --module 3 --profile aesthetic
+//
+// --rng selects the random source seeds are expanded with: math (default,
+// Go's math/rand), pcg (PCG-DXSM) or splitmix (SplitMix64). Generation stays
+// deterministic for a seed and source, and levels built with a non-default
+// source record it in generation_rng:
+//
+//	level-builder batch --module 3 --rng pcg
+//
+// --constraints attaches a file of hand-authored requirements, one per line
+// ("exactly 2 vines longer than 8", "no heads pointing down", "solution
+// length >= 15"), and regenerates levels until they meet them. Lines before
+// any section apply to every level; [Sprout] and [level 30] or
+// [levels 30-32] sections scope them to a tier or to level IDs:
+//
+//	level-builder batch --module 2 --constraints module_2.constraints
+//
+// Every accepted level gets an aesthetics score (0-100) from how often its
+// vines turn, how much of it is long straight runs and how tightly short
+// filler vines clump; the score and its parts are written to the per-level
+// stats in --stats-out. --min-aesthetics N regenerates levels scoring below N:
+//
+//	level-builder batch --module 4 --min-aesthetics 60
+//
+// The difficulty check also holds each tier's deepest blocking chain inside
+// its blocking_depth_range, so Seedling levels do not hide long cascades and
+// Flourishing levels are not trivially shallow. Levels outside the band are
+// regenerated and counted as depth rejections; --no-difficulty-check skips it.
+//
+// --race N generates each level from N base seeds at once and keeps the
+// first valid level. Hard tiers finish sooner, but the winning seed depends on
+// timing; callers of levelgen.Generate that set Seed always get the
+// deterministic single-seed run:
+//
+//	level-builder batch --module 5 --race 4
+//
+// On a terminal, batch shows a live board with one progress bar per level
+// (strategy, attempts spent of the chain's budget, relaxations) and a footer
+// with levels done, elapsed time and ETA. Rejected attempts update the
+// level's row instead of scrolling past. --tui=false, --verbose and
+// --log-format json fall back to plain output. Go callers get the same
+// stream by setting batch.Config.Progress to a channel of ProgressEvent.
+//
+// --metrics-out writes Prometheus textfile metrics for the run (package prom):
+// levels by result, attempts, fallbacks, per-level generation time, attempts
+// and coverage by difficulty, plus when the run finished and whether it
+// succeeded. Point it at node_exporter's textfile directory so nightly
+// regeneration shows up on dashboards; validate --metrics-out does the same
+// for validation runs (outcomes, solver time and states explored).
+//
+//	level-builder batch --module 2 --metrics-out /var/lib/node_exporter/level_builder_batch.prom
+//
+// Ctrl+C (SIGINT) or SIGTERM cancels in-flight placement and solver searches
+// across every command. Levels already finished stay recorded, so an
+// interrupted batch continues with --resume. A second Ctrl+C exits at once.
+//
 // ## validate
 //
 // Validate puzzle levels for structural integrity and solvability.
 //
 // Performs comprehensive validation including:
+//   - Strict JSON Schema checks (unknown fields, wrong types) before parsing
 //   - Module and level file parsing
 //   - Grid size and occupancy checks
 //   - Color scheme validation
@@ -80,10 +204,59 @@
 //   - Head/neck orientation validation
 //   - Circular blocking detection (deadlock prevention)
 //   - Mask validation (vines can't occupy hidden cells)
+//   - Gap validation ("show-all" gap cells are in bounds and left empty;
+//     they count as covered)
+//   - Portal validation (free, visible, non-adjacent cells)
+//   - Lock validation (locked_until reachable given the blocking chains)
+//   - Hint validation (hints clear one after another from the start)
+//   - Solution order validation (an embedded solution_order clears every vine)
+//   - Move budget validation (min_moves equals and max_moves covers the vine count)
 //   - Optional solvability checks using BFS or A* algorithms
 //
 // When --check-solvable is enabled, results are written to validation_stats.json
-// for detailed analysis including solver performance metrics.
+// for detailed analysis including solver performance metrics and the length of
+// each solution found. A level whose solution does not fit in max_moves fails
+// the check even though it can be cleared, since the game ends when the moves
+// run out. --partial-moves solves under a rule where a blocked vine advances up
+// to its blocker and stays there instead of bumping back, as it does in the
+// game today; those solutions can take more moves than there are vines.
+// --growing-vines grows every remaining vine's tail by one cell after each
+// move, and --reveals vine_3=2 keeps vine_3 off the grid until two moves have
+// been made.
+//
+// Proven-unsolvable levels are explained: the report names a minimal deadlocked
+// vine set, the blocking cycle among those vines (or the locks that can never
+// open) and renders the board with the offending vines highlighted.
+//
+// --report-format chooses text (default), json, junit or markdown output, and
+// --report-out writes that report to a file while the text summary stays on
+// stdout. CI jobs can publish the JUnit file; the Markdown report is meant for
+// pasting into pull requests.
+//
+// --max-memory-mb caps each solver search's state table (default 1024 MB). A
+// search that fills it continues depth-first and keeps only a bounded,
+// least-recently-used set of dead-end states, so memory stays flat on huge
+// levels while the verdict stays exact within --max-states.
+//
+// --watch validates every level once and then watches the levels directory,
+// re-validating only the level files that are saved, added or removed and
+// printing each pass/fail result immediately, for fast feedback while
+// hand-editing JSON. It runs until interrupted.
+//
+// --id and --file check only the selected levels instead of the whole levels
+// directory, so iterating on one level stays fast. --id takes level IDs and
+// --file takes paths or glob patterns; both are repeatable. A lone --file "-"
+// reads a level from stdin, so levels can be piped in from import or other
+// programs.
+//
+// --pack validates the levels inside a .pbpack level library from export pack
+// without extracting it, inflating only the levels --id picks. Levels are
+// reported as <library>:level_<id>.json.
+//
+// --audit-solvers runs the greedy, exact BFS and A* solvers side by side on
+// every level instead of validating. It lists levels where conclusive
+// verdicts disagree or a solver's clear order fails replay, with states and
+// timings per solver, writes solver_audit.json and fails on any disagreement.
 //
 // Examples:
 //
@@ -99,24 +272,56 @@
 //	# Verbose validation for debugging
 //	level-builder validate --check-solvable --verbose
 //
+//	# JUnit results for CI, Markdown summary for a PR
+//	level-builder validate --check-solvable --report-format junit --report-out validation.xml
+//	level-builder validate --check-solvable --report-format markdown
+//
+//	# Re-validate levels as they are edited
+//	level-builder validate --watch --check-solvable
+//
+//	# Compare solver verdicts and cost across all levels
+//	level-builder validate --audit-solvers --max-states 200000
+//
+//	# Re-check only the levels being worked on
+//	level-builder validate --id 42 --file 'assets/levels/level_5?.json' --check-solvable
+//
+//	# Check a level without writing it to assets
+//	level-builder import sketch.csv --id 130 --out - | level-builder validate --file -
+//
+//	# Check a level library before shipping it
+//	level-builder validate --pack levels.pbpack --check-solvable
+//
 // Flags:
 //
 //	-s, --check-solvable    Run solvability checks (may be slow)
 //	--max-states            Max states budget for solver heuristic (default: 100000)
 //	--use-astar             Use A* guided search for exact solver (default: true)
 //	--astar-weight          Weight multiplier for A* heuristic (default: 10)
+//	--max-memory-mb         State table budget per solver search (default: 1024, 0 = unbounded)
+//	--report-format         Report format: text, json, junit or markdown (default: text)
+//	--report-out            Write the report to this file instead of stdout
+//	--audit-solvers         Compare greedy, BFS and A* verdicts instead of validating
+//	--audit-out             Audit artifact path (default: solver_audit.json)
+//	--watch                 Keep running and re-validate level files as they change
+//	-f, --file              Validate only these level files or globs ("-" for stdin)
+//	-i, --id                Validate only these level IDs
+//	--pack                  Validate the levels in a .pbpack level library
 //
 // Output:
 //   - Console: Per-level validation status with timing
 //   - validation_stats.json: Detailed metrics (when --check-solvable is used)
+//   - solver_audit.json: Per-level solver comparison (when --audit-solvers is used)
 //
 // ## render
 //
-// Render puzzle levels as ASCII or Unicode visualizations.
+// Render puzzle levels as ASCII or Unicode visualizations, or as SVG/PNG
+// images.
 //
 // Generates human-readable grid visualizations for debugging, documentation,
 // and visual inspection of level layouts. Supports both ASCII and Unicode
-// rendering styles with optional coordinate display.
+// rendering styles with optional coordinate display. The svg and png styles
+// write an image to --out in the level's color scheme, with head arrows and
+// joined vine bodies, for documentation and marketing assets.
 //
 // Examples:
 //
@@ -132,16 +337,120 @@
 //	# Unicode rendering (default style)
 //	level-builder render --id 5 --style unicode
 //
+//	# Static images
+//	level-builder render --id 12 --style svg --out docs/level_12.svg
+//	level-builder render --id 12 --style png --out level_12.png --cell-size 48
+//
+//	# Animate the solution: vines slide out in clearing order
+//	level-builder render --id 5 --animate --out level_5.gif
+//	level-builder render --id 5 --animate --out level_5.svg --cell-size 32
+//
 // Flags:
 //
 //	--id               Level ID to render
-//	--file             Path to level JSON file
-//	--style            Rendering style: unicode or ascii (default: unicode)
-//	--coords           Show coordinate grid labels
+//	--file             Path to level JSON file ("-" for stdin)
+//	--style            Rendering style: unicode, ascii, svg or png (default: unicode)
+//	--coords           Show coordinate grid labels (text styles)
+//	--animate          Solve the level and write an animation to --out
+//	--out              Image file for svg/png styles, or animation file:
+//	                   .gif (animated GIF) or .svg (SMIL SVG)
+//	--cell-size        Image/animation pixels per grid cell (default: 16)
+//	--step-delay       Time for a vine to slide one cell (default: 60ms)
+//	--max-states       Solver budget for --animate (default: 1000000)
 //
 // Unicode glyphs: ↑ ↓ ← → (heads), ┼ ├ ┤ ┴ ┬ │ ─ (connectors)
 // ASCII glyphs:   ^ v < > (heads), + | - (connectors), o (tail)
 //
+// ## play
+//
+// Play a level in the terminal under the game's rules, to check how it feels
+// without launching the game.
+//
+// The board is drawn with coordinates after each move. Select a vine by its
+// ID or by the coordinates of any of its cells; it slides out if a head has a
+// clear path, following portals. Every tap is a move, and a tap that cannot
+// clear costs one grace the first time that vine fails (tutorials keep their
+// last grace). The game ends when the board is clear, grace runs out or
+// max_moves is reached, and prints the moves used against the level's budget.
+//
+// Examples:
+//
+//	level-builder play --id 12
+//	level-builder play --file assets/levels/level_33.json --style ascii
+//
+// Flags:
+//
+//	--id               Level ID to play
+//	--file             Path to level JSON file
+//	--style            Board style: unicode or ascii (default: unicode)
+//
+// ## solve
+//
+// Print the move sequence that clears a level.
+//
+// Runs the solver and prints the vine clearing order, then renders the board
+// at the start and after every move. Useful when a level "feels impossible":
+// step through the snapshots to see which vine unblocks which.
+//
+// Examples:
+//
+//	level-builder solve --id 12
+//	level-builder solve --file assets/levels/level_33.json --style unicode
+//	level-builder solve --all --no-snapshots
+//
+// Flags:
+//
+//	--id               Level ID to solve
+//	--file             Path to level JSON file ("-" for stdin)
+//	--all              Solve every level in assets/levels
+//	--max-states       Solver budget (default: 1000000)
+//	--style            Snapshot style: ascii or unicode (default: ascii)
+//	--coords           Show coordinate grid labels
+//	--no-snapshots     Only print the clearing order
+//
+// ## diff
+//
+// Compare two level files, or two directories of generated levels.
+//
+// Reports changes to grid size, vine count, per-vine paths, masks and solver
+// stats, then renders both versions side by side. Directories are paired by
+// level file name, which makes it easy to see what a regeneration changed.
+//
+// Examples:
+//
+//	level-builder diff --a level_5.json --b level_5_new.json
+//	level-builder diff --a assets/levels --b /tmp/regen --no-render
+//
+// Flags:
+//
+//	--a                Original level file or run directory
+//	--b                New level file or run directory
+//	--max-states       Solver budget (default: 1000000)
+//	--style            Render style: ascii or unicode (default: ascii)
+//	--no-render        Skip the side-by-side render
+//
+// ## dedupe
+//
+// Find duplicate and near-duplicate levels in a directory.
+//
+// Each level is reduced to a canonical form (vines sorted, IDs and colors
+// dropped, minimised over the grid's rotations and reflections). Levels with
+// the same form are identical; distinct levels whose cell-by-cell similarity
+// under some symmetry reaches the threshold are near-duplicates. Declared
+// mirrors (mirror_of) are skipped by default.
+//
+// Examples:
+//
+//	level-builder dedupe
+//	level-builder dedupe --dir /tmp/regen --threshold 0.8 --strict
+//
+// Flags:
+//
+//	--dir              Levels directory (default: assets/levels)
+//	--threshold        Near-duplicate similarity cutoff (default: 0.9)
+//	--include-mirrors  Also flag declared mirror levels
+//	--strict           Exit non-zero when duplicates are found
+//
 // ## repair
 //
 // Scan and repair corrupted level files.
@@ -164,18 +473,145 @@
 //	# Force overwrite without prompting
 //	level-builder repair --overwrite
 //
+//	# Fix broken levels in place, logging each edit
+//	level-builder repair --minimal --dry-run
+//
 // Flags:
 //
-//	--directory        Directory containing level files (default: ../../assets/levels)
+//	--directory        Directory containing level files (default: assets/levels)
 //	--overwrite        Overwrite files without prompting
 //	--dry-run          Show what would be repaired without making changes
+//	--minimal          Fix levels that parse but are broken with minimal edits
+//	--max-states       Solver budget when --minimal checks for deadlocks (default: 100000)
+//	--backup           Back up replaced levels to <directory>/.backups (default: true)
 //
 // Repair process:
 //  1. Scan directory for level_*.json files
 //  2. Attempt to read and parse each file
 //  3. If parsing fails, regenerate using TileGridIntoVines
 //  4. Validate solvability before writing
-//  5. Write repaired file with backup of original
+//  5. Back up the original (see restore) and write the repaired file atomically
+//
+// With --minimal, files that are valid JSON are instead edited in place by
+// pkg/repair, which keeps every sound vine: bad cells are dropped, shuffled
+// paths reordered, head and tail directions recomputed from the path, vines
+// that block their own exit or deadlock with others reversed (or regrown when
+// that is not enough), impossible locks removed, and the move budget, hints,
+// solution_order and mask updated to match.
+//
+// ## renumber
+//
+// Give vines canonical, deterministic IDs.
+//
+// Every placer names vines vine_1, vine_2, ... (model.VineID), and fillers
+// number on after the vines already placed (model.NextVineIndex). renumber
+// brings existing files into the same scheme: vines are sorted by head
+// position in raster order (top row first, then left to right) and renamed
+// in that order. Colors, locks and directions stay with their vines, hints
+// and solution_order follow the rename, so the level plays exactly as
+// before. Files already in canonical order are left alone.
+//
+// Examples:
+//
+//	level-builder renumber --dry-run
+//	level-builder renumber
+//	level-builder renumber assets/levels/level_12.json
+//
+// Flags:
+//
+//	--dir       Levels directory when no files are named (default: assets/levels)
+//	--dry-run   Show the renames without writing files
+//	--backup    Back up replaced levels to <dir>/.backups (default: true)
+//
+// ## variant
+//
+// Remix a level into gameplay-preserving variants for A/B tests or remixed
+// challenge modes. Each variant applies --mutations distinct mutations,
+// picked at random from mirror (reflect the grid), rotate (turn it 90, 180
+// or 270 degrees; a quarter turn swaps width and height), recolor (permute
+// the colors in use) and swap (two equal-length vines of different colors
+// trade places, with hints and solution_order following). Vines keep their
+// index, so vine i of the variant plays like vine i of the source.
+//
+// Before a variant is written, validator.VerifyVariantEquivalence checks
+// vine lengths, blocking relations, the move budget and solvability, and
+// both levels are scored along the same solution, which must give identical
+// difficulty metrics. Variants are named "<source> (Variant N)" under the
+// level.name.variant key, record variant_of and variant_mutations, and are
+// not added to modules.json.
+//
+// Examples:
+//
+//	level-builder variant --id 12 --mutations 3
+//	level-builder variant --id 12 --count 4 --kinds rotate,recolor --mutations 2
+//	level-builder variant --id 12 --out-id 500 --dry-run
+//
+// Flags:
+//
+//	--id               Source level ID (required)
+//	--mutations, -m    Distinct mutations per variant (default: 3)
+//	--count, -c        Variants to write (default: 1)
+//	--seed             Seed for picking mutations (default: the source ID)
+//	--kinds            Mutations to choose from (default: all four)
+//	--out-id           ID of the first variant (default: next unused ID)
+//	--dir, -d          Levels directory (default: assets/levels)
+//	--max-states       Solver budget for verification (default: 1000000)
+//	--overwrite, -o    Replace existing level files
+//	--backup           Back up replaced levels (default: true)
+//	--dry-run, -n      Verify and print the variants without writing files
+//
+// ## regrade
+//
+// Re-score every level in a levels directory with the current difficulty
+// scorer and report levels whose stored tier no longer matches how hard they
+// measure, for example after the scorer or the tier bands change. A level
+// keeps its tier while its difficulty score and deepest blocker chain lie
+// inside that tier's bands, the check batch applies to new levels. Otherwise
+// it is regraded to the tier whose bands it misses by the least, nearest its
+// stored tier on ties. Levels whose complexity does not match their tier are
+// reported too; tutorials are skipped.
+//
+// --apply sets difficulty and complexity on the reported levels and writes
+// them back, backing them up first. --strict fails when any level is stale.
+//
+// Examples:
+//
+//	level-builder regrade
+//	level-builder regrade --apply
+//	level-builder regrade --dir /tmp/regen --strict
+//
+// Flags:
+//
+//	--dir, -d       Levels directory (default: assets/levels)
+//	--apply         Rewrite the difficulty and complexity of stale levels
+//	--max-states    Solver budget per level (default: 100000)
+//	--backup        Back up rewritten levels (default: true)
+//	--strict        Exit with an error when any level is stale
+//
+// ## restore
+//
+// Roll level files back to a backup.
+//
+// Level files are always written through a temporary file renamed into place,
+// so a crash never leaves a truncated level. batch, repair, renumber,
+// regrade --apply and import --overwrite also copy every level they replace
+// into one .backups/backup_<timestamp> directory next to it (disable with
+// --backup=false). restore copies a backup back: the newest by default, or
+// the one named. It backs up the levels it replaces first, so running it again
+// undoes it.
+//
+// Examples:
+//
+//	level-builder restore --list
+//	level-builder restore
+//	level-builder restore backup_20260101_120000 --level 22 --level 23
+//
+// Flags:
+//
+//	--dir       Levels directory (default: assets/levels)
+//	--list      List backups, newest first
+//	--level     Restore only these level IDs (repeatable)
+//	--dry-run   Show what would be restored
 //
 // ## clean
 //
@@ -193,25 +629,498 @@
 //   - validation_stats.json (validation metrics)
 //   - Temporary files from failed generations
 //
+// ## explore
+//
+// Compare how generation settings trade off for a fixed seed and difficulty.
+//
+// Generates the same level at several MinCoverage settings (0.85, 0.90, 0.95,
+// 1.0 by default) and reports achieved coverage, solver effort, blocking depth
+// and generation time, followed by a side-by-side render. Nothing is written.
+//
+// Examples:
+//
+//	level-builder explore --difficulty Nurturing
+//	level-builder explore --difficulty Flourishing --seed 42 --coverages 0.8,0.9,1.0
+//
+// Flags:
+//
+//	--level-id         Level ID used for grid sizing and default seed (default: 1)
+//	--difficulty       Difficulty tier (default: Nurturing)
+//	--seed             Generation seed (default: batch seed for --level-id)
+//	--strategy         Placement strategy to hold fixed
+//	--coverages        MinCoverage settings to compare
+//	--max-states       Solver budget (default: 500000)
+//	--no-render        Only print the metrics table
+//
+// ## seedsearch
+//
+// Search many seeds for the best levels of a difficulty.
+//
+// Generates the level once per seed, scores each solvable result with the
+// difficulty scorer, and reports the top seeds: in-band scores first, then
+// those closest to the target score, then higher coverage. A reported seed
+// reproduces its level with the same difficulty, level ID and strategy.
+//
+// Examples:
+//
+//	level-builder seedsearch --difficulty Flourishing --count 1000 --top 10
+//	level-builder seedsearch --difficulty Sprout --target-score 12 --out-dir /tmp/candidates
+//
+// Flags:
+//
+//	--level-id         Level ID used for grid sizing and default start seed (default: 1)
+//	--difficulty       Difficulty tier (default: Nurturing)
+//	--start-seed       First seed to try (default: batch seed for --level-id)
+//	--count            Seeds to try (default: 100)
+//	--top              Seeds to report (default: 10)
+//	--target-score     Score to aim for (default: middle of the tier's band)
+//	--strategy         Placement strategy
+//	--min-coverage     Minimum vine coverage
+//	--max-states       Scoring solver budget (default: 100000)
+//	--out-dir          Write the top levels here as level_<id>_seed_<seed>.json
+//
+// ## bench
+//
+// Benchmark generation against a stored baseline.
+//
+// Runs a fixed suite of seeds and difficulties through each placement
+// strategy, one case at a time, recording generation and solve time,
+// coverage, placement attempts and solver states. Results are summed per
+// difficulty and strategy and compared with the baseline; a group that fails
+// more cases or exceeds a threshold counts as a regression and the command
+// exits non-zero.
+//
+// Examples:
+//
+//	level-builder bench --suite standard --baseline bench_baseline.json
+//	level-builder bench --suite standard --baseline bench_baseline.json --update-baseline
+//
+// Flags:
+//
+//	--suite              Suite to run: quick or standard (default: standard)
+//	--baseline           Baseline report to compare against
+//	--update-baseline    Save this run as the new baseline
+//	--out                Also write this run's report here
+//	--level-id           Level ID used for grid sizing (default: 1)
+//	--max-states         Solver budget per level (default: 500000)
+//	--max-time-ratio     Allowed time growth per group (default: 1.5)
+//	--min-time-delta-ms  Ignore smaller slowdowns (default: 50)
+//	--max-coverage-drop  Allowed mean coverage drop in points (default: 1)
+//	--max-attempts-ratio Allowed placement attempts growth (default: 1.25)
+//	--max-states-ratio   Allowed solver states growth (default: 1.25)
+//
+// ## replay
+//
+// Replay a failed generation from its failure dump.
+//
+// Placers write a JSON failure dump (with an ASCII render beside it) when
+// placement fails. The dump records the generation config and seed, so
+// replay can re-run the same placement with verbose tracing and report
+// whether it fails identically. --step then renders the grid after each
+// recorded placement, waiting for Enter between vines.
+//
+// Examples:
+//
+//	level-builder replay --dump failing_dumps/failure_level_12_seed_376044_attempt_0_20250101_120000.json
+//	level-builder replay --dump failure.json --step
+//
+// Flags:
+//
+//	--dump             Failure dump to replay (required)
+//	--step             Step through placements interactively
+//	--difficulty       Difficulty for dumps without a recorded config (default: Seedling)
+//	--strategy         Strategy for dumps without a recorded config
+//
+// ## rngdiff
+//
+// Find the first random draw at which two generation runs diverge.
+//
+// batch --rng-trace DIR writes, for every generation attempt, each random
+// draw with its value and the call site that made it
+// (level_N_attempt_M.rng.json). When the same seed produces a different
+// level, trace both runs and compare them: rngdiff reports the first draw
+// whose value or call site differs and the last call site both runs shared.
+// Directories are paired by file name.
+//
+// Examples:
+//
+//	level-builder batch --module 1 --rng-trace /tmp/trace_a
+//	level-builder rngdiff --a /tmp/trace_a --b /tmp/trace_b
+//
+// Flags:
+//
+//	--a                Original trace file or directory (required)
+//	--b                New trace file or directory (required)
+//
+// ## analyze coverage
+//
+// Aggregate occupancy across all level files to expose placement biases.
+//
+// Levels are grouped by grid size; each size gets a heatmap of the percentage
+// of levels with a vine on every cell, plus its most and least used cells.
+// Head direction and vine length distributions cover all levels.
+//
+// Examples:
+//
+//	level-builder analyze coverage
+//	level-builder analyze coverage --dir /tmp/regen --out coverage_report.json
+//
+// Flags:
+//
+//	--dir, -d          Levels directory (default: assets/levels)
+//	--out              Write the full report (with per-cell head counts) as JSON
+//	--top              Most/least used cells listed per grid size (default: 5)
+//
+// ## analyze blocking
+//
+// Build one level's vine blocking graph (A -> B: A sits in the cell B's head
+// would move into) and report its deepest chain and circular blocking.
+//
+// --dot exports the graph for Graphviz: vines on a loop are red, the deepest
+// chain is bold blue and each vine is labelled with its blocking depth.
+//
+// Examples:
+//
+//	level-builder analyze blocking --id 12
+//	level-builder analyze blocking --id 12 --dot level_12.dot
+//
+// Flags:
+//
+//	--id, -i           Level ID to analyze (uses assets/levels/level_<id>.json)
+//	--file, -f         Path to a level JSON file to analyze
+//	--dot              Write the blocking graph in Graphviz DOT format
+//
+// ## analyze module
+//
+// Chart a module's difficulty curve across its levels and challenge level, in
+// play order, and flag regressions. Each level is scored as solution length +
+// 2 x max blocking depth + log2(1 + solver states); a level regresses when it
+// scores more than --tolerance below the hardest level before it. The command
+// fails when any level regresses, so progression can be checked before a
+// module ships.
+//
+// Examples:
+//
+//	level-builder analyze module --id 1
+//	level-builder analyze module --id 3 --tolerance 0.1 --out module_3_curve.json
+//
+// Flags:
+//
+//	--id, -i           Module ID to analyze (from modules.json)
+//	--max-states       Solver state budget per level (default: 200000)
+//	--tolerance        Allowed score drop below an earlier level (default: 0.25)
+//	--out              Write the curve as JSON
+//
+// ## analyze rng
+//
+// Generate --samples levels per difficulty from consecutive seeds and test the
+// output for statistically significant biases: a chi-square of head
+// directions against an even split, a chi-square of levels whose mean vine
+// length misses the tier's avg_length_range (beyond a 5% allowance), and a
+// one-sided z-test that levels from seeds one bit apart differ as much as
+// levels from unrelated seeds. Tests with a p-value below --alpha are flagged
+// and fail the command.
+//
+// Examples:
+//
+//	level-builder analyze rng --samples 500
+//	level-builder analyze rng --difficulty Seedling --samples 200 --out rng.json
+//
+// Flags:
+//
+//	--samples          Levels per difficulty (default: 500)
+//	--difficulty       Tiers to sample, repeatable (default: all five)
+//	--pairs            One-bit-flipped seeds for the avalanche test (default: samples/10)
+//	--alpha            Significance level (default: 0.01)
+//	--rng, --strategy  Random source and placement strategy under test
+//	--out              Write the report as JSON
+//
+// ## modules check
+//
+// Cross-check modules.json against the levels directory.
+//
+// Every level and challenge level a module names must have a file and appear
+// once; each level's difficulty must match its slot in the module progression
+// (5 Seedling, 5 Sprout, 5 Nurturing, 5 Flourishing) and challenge levels must
+// be Transcendent. Theme seeds must name a known biome, level_mappings entries
+// must point at files, and every level file must be reached by a module, a
+// mirror or a mapping. Keys without a mapping resolve by the standard
+// numbering (lvl_sprout_03 is level_24.json).
+//
+// --fix repairs what the registry alone can (unknown theme seeds, duplicate
+// regular levels, stale mappings) and rewrites modules.json atomically; the
+// rest is listed again and fails the run.
+//
+// Examples:
+//
+//	level-builder modules check
+//	level-builder modules check --fix
+//
+// ## modules add / remove / move / reorder / rename
+//
+// Edit modules.json without hand-editing JSON.
+//
+// add puts a level key into a module (or, without --level, creates a module),
+// remove takes a level out of its module (or removes an empty module), move
+// sends a level to another module, reorder moves a level within its module
+// (or a module within the module list) and rename renames a module. Positions
+// are 1-based slots among a module's regular levels; --challenge targets the
+// challenge level instead. Edits that would name a level twice, reuse a
+// module ID or name, or overwrite a challenge level are refused. Mirror
+// pairings follow their levels. The registry is written atomically, and the
+// modules check issues of the result are printed as warnings; --dry-run skips
+// the write.
+//
+// Examples:
+//
+//	level-builder modules move --level lvl_seed_20 --to 2 --position 1 --dry-run
+//	level-builder modules reorder --level lvl_seed_12 --position 3
+//	level-builder modules add --module 6 --name Bonus --theme-seed aurora
+//	level-builder modules rename --module 2 --name "First Shoots"
+//
+// ## modules names
+//
+// Write the names catalog, assets/data/names.json, so the app can localize
+// level and module titles instead of parsing English names.
+//
+// Generated levels and modules carry a name_ref beside their English name: a
+// message key and its parameters, e.g. {"key": "level.name", "params":
+// {"number": 22}} for "Level 22", {"key": "level.name.challenge"} for a
+// module's Transcendent level, or {"key": "level.name.mirror", "of": ...}
+// wrapping the mirrored level's name. The catalog maps each level and module
+// ID to its name_ref and lists the English text of every key in use, with
+// ICU-style {placeholders}. Names without a name_ref are read from their
+// English text; a name that follows no standard pattern gets its own key
+// (level.<id>.name or module.<id>.name) whose English text is the name
+// itself. A name edited by hand so it no longer matches its name_ref fails
+// the build. rename clears a module's name_ref.
+//
+// Examples:
+//
+//	level-builder modules names
+//	level-builder modules names --check   # fail in CI when names.json is stale
+//
+// ## schema export
+//
+// Write JSON Schemas (draft 2020-12) for level, lesson and module files.
+//
+// The schemas are derived from the Go model types: omitempty fields are
+// optional and every object rejects unknown fields. validate and tutorials
+// validate check files against the same schemas before their structural
+// checks.
+//
+// Examples:
+//
+//	level-builder schema export --out schemas
+//	level-builder schema export --type level > level.schema.json
+//
+// Flags:
+//
+//	--out              Directory for <name>.schema.json files (default: stdout)
+//	--type             level, lesson or modules (default: all; required for stdout)
+//
+// ## import
+//
+// Convert a level drawn in a spreadsheet (csv) or in Tiled (tiled, a TMX map
+// with CSV layers) into level JSON.
+//
+// In csv, each field is a cell, rows run from the top of the grid down, and a
+// cell holds the ID of the vine covering it ("." or empty for none). "A*"
+// marks A's head, "A+" its tail head and "A:3" pins a cell to position 3
+// along A. In Tiled, tiles on the "vines" layer give each cell's vine and the
+// "heads", "tails" and "order" layers carry the same markers. Each vine's
+// cells must allow exactly one path from its head. Head directions come from
+// the paths, touching vines get different colors, empty cells are masked and
+// the move budget comes from the solver. The level must pass the same checks
+// as validate --check-solvable before it is written.
+//
+// Examples:
+//
+//	level-builder import sketch.csv --id 130 --difficulty Sprout
+//	level-builder import --format tiled garden.tmx --id 131 --out /tmp/level_131.json
+//
+// Flags:
+//
+//	--format           csv or tiled (default: from the .csv or .tmx extension)
+//	--id, -i           Level ID (required)
+//	--difficulty, -d   Difficulty tier (default: Seedling)
+//	--name             Level name (default: "Level <id>")
+//	--out, -o          Output path, "-" for stdout (default: assets/levels/level_<id>.json)
+//	--overwrite        Replace an existing file
+//	--ignore-occupancy Warn instead of failing on low vine occupancy
+//	--max-states       Solver state budget (default: 200000)
+//
+// ## serve
+//
+// Serve a web page for browsing the level library: a filterable level list,
+// SVG renders, solution animations, validation results and a form that
+// generates levels on demand without saving them. The page is built on JSON
+// endpoints (/api/levels, /api/levels/{id}, /api/levels/{id}/svg,
+// /api/levels/{id}/solution.svg, /api/levels/{id}/validate, POST
+// /api/validate and POST /api/generate) that scripts can call directly.
+//
+// Examples:
+//
+//	level-builder serve
+//	level-builder serve --port 9000 --host 0.0.0.0
+//
+// Flags:
+//
+//	--port, -p          Port to listen on (default: 8080)
+//	--host              Interface to listen on (default: localhost)
+//	--max-states        Solver budget for validation and animations (default: 1000000)
+//	--cell-size         Pixels per grid cell in SVG renders
+//	--generate-timeout  Time limit per generate request (default: 2m)
+//
+// ## daemon
+//
+// Run generation and validation as a long-lived HTTP service, so a backend can
+// generate levels (daily challenges, say) without spawning a process per
+// level. A client submits a generation config as a job (POST /v1/jobs), then
+// polls it (GET /v1/jobs/{id}) or streams its progress as NDJSON (GET
+// /v1/jobs/{id}/events): an "event" line per attempt outcome, then a "result"
+// line with the finished job and its level. DELETE /v1/jobs/{id} cancels a
+// job, POST /v1/validate validates a level body and GET /v1/health reports
+// the workers and queue. Jobs are kept in memory and levels are never written
+// to assets.
+//
+// Examples:
+//
+//	level-builder daemon --concurrency 4
+//	curl -s -d '{"level_id": 900, "difficulty": "Sprout"}' localhost:8090/v1/jobs
+//	curl -N localhost:8090/v1/jobs/1/events
+//
+// Flags:
+//
+//	--port, -p      Port to listen on (default: 8090)
+//	--host          Interface to listen on (default: localhost)
+//	--concurrency   Jobs generated at once (default: 1)
+//	--queue-size    Jobs waiting before submissions get 503 (default: 64)
+//	--job-timeout   Time limit per job (default: 5m, 0 = none)
+//	--retain        Finished jobs kept for lookup (default: 256)
+//	--max-states    Solver budget for /v1/validate (default: 1000000)
+//	--dump-dir      Where placers write failure dumps
+//
+// ## export pack
+//
+// Bundle modules into one zip for an app release.
+//
+// The pack mirrors the assets layout: data/modules.json trimmed to the chosen
+// modules, their levels, challenge levels and mirrored levels, and every
+// tutorial lesson. manifest.json records the pack format, the modules.json
+// schema version, each file's size and SHA-256, and a pack checksum over that
+// list. Files are schema-checked before bundling, the written zip is read back
+// and verified, and the same content always gives the same bytes.
+//
+// Examples:
+//
+//	level-builder export pack --modules 1-5 --out parable_pack_v3.zip
+//	level-builder export pack --modules 1,3
+//	level-builder export pack --out levels.pbpack
+//
+// An --out ending in .pbpack writes a level library instead, for level sets
+// too large to handle as loose files: every level file in --dir in one file,
+// each level its own gzip member, with a compressed index of IDs, names,
+// offsets and SHA-256s at the end. Readers open the index only and inflate
+// the levels they need. Libraries are also reproducible, and the written file
+// is read back and verified.
+//
+// Flags:
+//
+//	--modules, -m      Module IDs or ranges, e.g. "1-5" or "1,3" (default: all)
+//	--out, -o          Pack path (default: parable_pack_v<schema major>.zip)
+//	--dir, -d          Levels directory for a .pbpack (default: assets/levels)
+//
+// ## extract
+//
+// Unpack levels from a .pbpack level library, byte for byte as they were
+// packed. Each level is checked against the index before it is written, and
+// existing files are kept unless --overwrite is set, in which case they are
+// backed up first so restore undoes the extract.
+//
+// Examples:
+//
+//	level-builder extract levels.pbpack --list
+//	level-builder extract levels.pbpack --dir /tmp/levels
+//	level-builder extract levels.pbpack --level 22 --overwrite
+//
+// Flags:
+//
+//	--dir, -d          Directory to write to (default: assets/levels)
+//	--level            Extract only these level IDs (repeatable)
+//	--list             Print the index without inflating any level
+//	--overwrite, -o    Replace existing level files
+//	--backup           Back up replaced levels (default: true)
+//	--dry-run, -n      Show what would be written
+//
+// ## budget
+//
+// Aggregate per-tier generation cost from batch stats directories.
+//
+// Every batch run prints a budget report and saves budget_report.json next to
+// its per-level stats. The budget command rebuilds the report across several
+// runs (e.g. a whole campaign), flagging tiers whose share of generation time
+// is disproportionate to their share of levels.
+//
+// Examples:
+//
+//	level-builder budget --stats-dir logs/<ts>/runs/stats
+//	level-builder budget --stats-dir run1/stats --stats-dir run2/stats --json-out budget.json
+//
+// ## stats
+//
+// Report generation and solver statistics.
+//
+// stats levels aggregates the retry budget generated levels record in their
+// files (generation_strategy, generation_attempts, generation_relaxations,
+// generation_backtracks, generation_elapsed_ms). It shows the per-tier budget,
+// a per-strategy breakdown within each tier and the most expensive levels, so
+// chronically expensive tiers and strategies stand out without a run's stats
+// directory. stats solver summarizes validation_stats.json files.
+//
+// Examples:
+//
+//	level-builder stats levels
+//	level-builder stats levels --dir assets/levels --top 20 --json-out level_stats.json
+//	level-builder stats solver logs/validation_stats.json
+//
 // ## tutorials
 //
-// Validate tutorial/lesson files with special rules.
+// Validate tutorial/lesson files with special rules, or generate new lessons
+// from teaching patterns.
 //
 // Tutorial validation has stricter requirements than regular levels:
 //   - Simpler layouts for teaching
-//   - Required instructional metadata
+//   - Required instructional metadata (title, objective, instructions and at
+//     least two learning_points, within the game's length limits)
+//   - Required lesson metadata: camelCase text_keys for the instructional
+//     text, allowed_interactions (tap, hint, restart; tap is mandatory) and
+//     highlight_vines naming vines of the lesson
+//   - Guided lessons (with steps) have at most 3 vines, one step per vine,
+//     and a forced order: at each step only that step's vine can clear
 //   - Guaranteed solvability
 //   - Progressive difficulty within lesson sequence
 //
+// tutorials generate --lesson N builds lesson N from a templated pattern
+// (straight, block, lifo-pair; lesson N defaults to the Nth) as a guided
+// lesson, one step per move of the clear order it teaches, and checks it
+// against the lesson rules and for solvability. --dry-run prints the lesson's
+// board, steps and target file instead of writing it. The old validate-tutorials name still runs validation.
+//
 // Examples:
 //
 //	# Validate all lesson files
 //	level-builder tutorials validate
 //
-//	# Validate specific lesson
-//	level-builder tutorials validate --id 1
+//	# Validate with solvability checks
+//	level-builder tutorials validate --check-solvable
+//
+//	# Preview lesson 2, or add lesson 6 as a stacked pair
+//	level-builder tutorials generate --lesson 2 --out -
+//	level-builder tutorials generate --lesson 2 --dry-run
+//	level-builder tutorials generate --lesson 6 --pattern lifo-pair
 //
-// Lesson files location: ../../assets/lessons/lesson_*.json
+// Lesson files location: assets/lessons/lesson_*.json
 //
 // # Architecture
 //
@@ -225,18 +1134,34 @@
 //	  ├─ render/      - Rendering commands
 //	  ├─ repair/      - Repair commands
 //	  ├─ clean/       - Cleanup commands
-//	  └─ tutorials/   - Tutorial validation
+//	  └─ tutorials/   - Tutorial validation and generation
 //	pkg/
 //	  ├─ common/      - Shared types, utilities, logging
+//	  ├─ constraints/ - Hand-authored level requirements for batch --constraints
+//	  ├─ daemon/      - Generation job queue and HTTP API behind daemon
 //	  ├─ generator/   - Level generation algorithms
 //	  │  ├─ tiling.go           - Core tiling algorithm
 //	  │  ├─ solver_aware.go     - Intelligent placement
 //	  │  └─ module_generation.go - Batch generation
+//	  ├─ fingerprint/ - Canonical level forms and duplicate detection
+//	  ├─ explore/     - Coverage comparison and seed search
+//	  ├─ importer/    - CSV and Tiled parsing behind import
+//	  ├─ levelgen/    - Public API for generating one level from Go code
+//	  ├─ lessons/     - Teaching patterns behind tutorials generate
+//	  ├─ modules/     - modules.json checks, repairs and edits behind modules
+//	  ├─ naming/      - Localizable name keys and the names.json catalog
+//	  ├─ pack/        - Release packs and .pbpack libraries behind export pack and extract
+//	  ├─ play/        - Game rules behind play
+//	  ├─ prom/        - Prometheus textfile metrics behind --metrics-out
+//	  ├─ regrade/     - Library difficulty re-grading behind regrade
+//	  ├─ schema/      - JSON Schemas derived from the model types
+//	  ├─ serve/       - HTTP preview server and its page
 //	  ├─ validator/   - Validation logic
 //	  │  ├─ validator.go        - Main validation orchestration
 //	  │  ├─ structural.go       - Structural checks
 //	  │  └─ solver.go           - Solvability algorithms
-//	  └─ model/       - Data models (Level, Vine, Module)
+//	  ├─ variant/     - Gameplay-preserving level remixes behind variant
+//	  └─ model/       - Data models (Level, Lesson, Vine, Module)
 //
 // ## Key Algorithms
 //
@@ -258,6 +1183,59 @@
 //  4. Predict exit paths to create controlled complexity
 //  5. Validate solvability with configurable search budgets
 //
+// ### Center-Out Placement (--strategy center-out)
+//
+// Seeds each vine near the center, gives its head a clear exit and grows the
+// body inward, so vines clear in reverse placement order. A tier's center_out
+// block (or config.CenterOutTuning on GenerationConfig) tunes the texture:
+//
+//	center_out:
+//	  center_bias: 0.75         # seeds come from the nearest 25% of free cells
+//	  seed_window: 5            # ...but never fewer than 5 cells
+//	  growth_greed: 0.8         # chance growth takes its best-scored cell
+//	  seed_pattern: round-robin # sweep top-down, bottom-up, left-right, right-left
+//
+// ### Full-Coverage Placement (--strategy full-coverage)
+//
+// Fills every cell so levels never need masks:
+//  1. Seed vine heads from the center outward, sweeping rings in round-robin order
+//  2. Give each head a clear exit and grow its body with backtracking
+//  3. Extend vine tails (or heads, along their exit path) into leftover cells
+//  4. Rip up vines around any remaining holes and re-place until full
+//
+// ### Chain Placement (--strategy chain)
+//
+// Builds the cascading locks of Transcendent levels on purpose:
+//  1. Start a chain with a vine the incremental solver accepts
+//  2. Add a vine whose head faces a cell of the previous one, so it waits on it
+//  3. Repeat until the chain is max_blocking_depth deep, then start another
+//  4. Reject any vine that closes a blocking cycle, blocks its own exit or
+//     pushes another chain past the target depth
+//
+// The gap filler covers what the chains leave, and fillers can lengthen a
+// chain, so pair it with the difficulty check.
+//
+// ### Head-Direction Balancing
+//
+// Placers share a DirBalancer (pkg/generator/utils) that counts placed heads
+// per direction. Once a direction's share passes its profile target by more
+// than the tier's dir_balance_tolerance, its weight drops exponentially, and
+// head selection favors the other edges.
+//
+// ### Vine Length Distributions
+//
+// A tier's length_distribution (normal, bimodal or power_law) replaces the
+// placers' own target lengths with draws from that curve. Transcendent mixes
+// many short vines with a few long spines:
+//
+//	length_distribution:
+//	  shape: bimodal
+//	  mean: 7
+//	  std_dev: 2
+//	  long_mean: 24
+//	  long_std_dev: 4
+//	  long_share: 0.3
+//
 // ### Validation Pipeline
 //
 //  1. Parse JSON and check schema compliance
@@ -268,6 +1246,21 @@
 //  6. Circular blocking detection (DFS cycle detection)
 //  7. Optional solvability check (BFS or A* search)
 //
+// ## Embedding the Generator
+//
+// Go services can generate levels without shelling out to the CLI through
+// pkg/levelgen. It runs the same strategy chain, validation and difficulty
+// calibration as batch, but returns the level instead of writing it:
+//
+//	level, stats, err := levelgen.Generate(ctx, levelgen.GenerateOptions{
+//		LevelID:    7,
+//		Difficulty: "Sprout",
+//		OnProgress: func(e levelgen.Event) { log.Println(e.Message) },
+//	})
+//
+// Cancelling ctx interrupts placement and solving. The batch command is a
+// thin wrapper that adds file output, mirrors, stats files and resume.
+//
 // # Development Workflow
 //
 // ## Typical Level Generation Flow
@@ -293,6 +1286,10 @@
 //	# Run Go tests
 //	go test ./...
 //
+//	# Fuzz the level parser, structural rules or solver (FuzzReadLevelFile,
+//	# FuzzValidateStructural, FuzzSolve); crashers land in testdata/fuzz
+//	go test ./pkg/validator -run '^$' -fuzz FuzzSolve -fuzztime 1m
+//
 //	# Run with verbose logging
 //	level-builder generate --id 99 --verbose
 //
@@ -322,7 +1319,51 @@
 //
 //	-v, --verbose              Enable verbose output for debugging
 //	-j, --workers string       Number of concurrent workers (integer, 'half', or 'full')
-//	-w, --working-dir string   Working directory for asset paths
+//	-w, --working-dir string   Directory to resolve paths and find the workspace root from
+//	-l, --log-file string      Also append log lines to this file
+//	    --log-format string    Log format: text (default) or json
+//	    --config string        Difficulty config (YAML or JSON) to tune generation
+//	    --json-indent string   Indent for written level, lesson and module files
+//	    --json-sort-keys       Write JSON object keys alphabetically
+//
+// --workers (default half) bounds how many levels validate checks for
+// solvability, batch generates and seedsearch and analyze rng generate at once.
+//
+// --config overrides the difficulty tables (vine counts, length ranges, grid
+// occupancy, grid sizes, move multipliers and the color palette) without
+// recompiling. The defaults live in pkg/generator/config/difficulty_config.yaml,
+// which is embedded in the binary and is the template to copy. A config only
+// needs the tiers and fields it changes; the rest keep their defaults:
+//
+//	# tuning.yaml
+//	difficulty_specs:
+//	  Sprout:
+//	    vine_count_range: [10, 60]
+//	    min_grid_occupancy: 0.9
+//
+//	level-builder --config tuning.yaml batch --module 2
+//
+// Coverage resolves in one place (config.CoverageFor). Placement aims for
+// --min-coverage when given, else the profile's coverage, else every cell. A
+// tier's min_grid_occupancy is the floor that partial placers fill to and that
+// validate checks, capped at that target.
+//
+// With --log-format json every log line is a JSON object with time, level and
+// msg keys, the spinner is disabled, and batch runs add structured events
+// (level_id, difficulty, strategy, attempt, phase, duration_ms, coverage) for
+// each attempt and finished level, ready for log analysis tooling:
+//
+//	level-builder --log-format json batch --module 2 | jq 'select(.phase == "generated")'
+//
+// Level, lesson and module files are written in one canonical form
+// (common.MarshalCanonical) so regenerating a level only changes the lines
+// that changed: keys in schema order, map keys sorted, numbers in their
+// shortest form, no HTML escaping and exactly one trailing newline. Levels and
+// modules.json indent with two spaces and lessons with four; --json-indent
+// (1-8 spaces or 'tab') overrides both, and --json-sort-keys sorts every
+// object's keys, for matching files written by other tools:
+//
+//	level-builder --json-sort-keys --json-indent tab batch --module 2
 //
 // ## Path Resolution
 //
@@ -334,6 +1375,15 @@
 //  2. assets (Standalone/Legacy standard)
 //
 // This allows the tool to be run from any subdirectory within the monorepo.
+// Default locations (batch --output-dir and its backups, stats levels --dir,
+// repair --directory, lessons, modules.json and logs) all come from the
+// resolved root, never from paths relative to the current directory.
+// --working-dir changes the directory the search starts from and the one
+// relative flag values resolve against, so
+//
+//	level-builder --working-dir ~/src/parable-bloom validate
+//
+// behaves the same from any directory.
 //
 // ## Environment Variables
 //
@@ -0,0 +1,210 @@
+package analyze
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/strategies"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// BlockingGraph is the "who blocks whom" graph of one level, as the generator's
+// DFSBlockingAnalyzer sees it: an edge A -> B means vine A sits in the cell
+// vine B's head would move into.
+type BlockingGraph struct {
+	LevelID int
+	Vines   []string            // in level order
+	Edges   map[string][]string // blocker -> vines it blocks
+	Depth   map[string]int      // longest blocking chain starting at each vine
+	// MaxDepth is the longest blocking chain in the level
+	MaxDepth int
+	// DeepestChain is one chain of MaxDepth+1 vines, each blocking the next
+	DeepestChain []string
+	// Cycles groups the vines that block each other in a loop; a level with
+	// any cycle cannot be cleared by moving heads alone
+	Cycles [][]string
+}
+
+// Blocking builds the blocking graph of level.
+func Blocking(level *model.Level) BlockingGraph {
+	analyzer := &strategies.DFSBlockingAnalyzer{}
+	occupied := make(map[string]string)
+	g := BlockingGraph{LevelID: level.ID}
+	for _, v := range level.Vines {
+		g.Vines = append(g.Vines, v.ID)
+		for _, p := range v.OrderedPath {
+			occupied[fmt.Sprintf("%d,%d", p.X, p.Y)] = v.ID
+		}
+	}
+
+	g.Edges = analyzer.BlockingGraph(level.Vines, occupied)
+	g.Depth = analyzer.BlockingDepths(g.Edges, level.Vines)
+	start := ""
+	for _, id := range g.Vines {
+		if g.Depth[id] > g.MaxDepth {
+			g.MaxDepth, start = g.Depth[id], id
+		}
+	}
+	g.DeepestChain = g.chainFrom(start)
+	g.Cycles = g.stronglyConnected()
+	return g
+}
+
+// chainFrom follows the deepest blocked vine from start until the chain ends.
+func (g BlockingGraph) chainFrom(start string) []string {
+	if start == "" {
+		return nil
+	}
+	chain := []string{start}
+	seen := map[string]bool{start: true}
+	for cur := start; ; {
+		next := ""
+		for _, id := range g.Edges[cur] {
+			if !seen[id] && g.Depth[id] == g.Depth[cur]-1 {
+				next = id
+				break
+			}
+		}
+		if next == "" {
+			return chain
+		}
+		chain = append(chain, next)
+		seen[next] = true
+		cur = next
+	}
+}
+
+// stronglyConnected returns the groups of two or more vines that reach each
+// other through the graph (Tarjan's algorithm), in level order.
+func (g BlockingGraph) stronglyConnected() [][]string {
+	index := make(map[string]int)
+	low := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var groups [][]string
+
+	var visit func(id string)
+	visit = func(id string) {
+		index[id] = len(index)
+		low[id] = index[id]
+		stack = append(stack, id)
+		onStack[id] = true
+		for _, next := range g.Edges[id] {
+			if _, ok := index[next]; !ok {
+				visit(next)
+				low[id] = min(low[id], low[next])
+			} else if onStack[next] {
+				low[id] = min(low[id], index[next])
+			}
+		}
+		if low[id] != index[id] {
+			return
+		}
+		var group []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			group = append(group, top)
+			if top == id {
+				break
+			}
+		}
+		if len(group) > 1 {
+			groups = append(groups, g.inLevelOrder(group))
+		}
+	}
+
+	for _, id := range g.Vines {
+		if _, ok := index[id]; !ok {
+			visit(id)
+		}
+	}
+	return groups
+}
+
+// inLevelOrder sorts ids by their position in the level.
+func (g BlockingGraph) inLevelOrder(ids []string) []string {
+	in := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		in[id] = true
+	}
+	ordered := make([]string, 0, len(ids))
+	for _, id := range g.Vines {
+		if in[id] {
+			ordered = append(ordered, id)
+		}
+	}
+	return ordered
+}
+
+// WriteText prints the graph's size, deepest chain and cycles.
+func (g BlockingGraph) WriteText(w io.Writer) {
+	edges := 0
+	for _, blocked := range g.Edges {
+		edges += len(blocked)
+	}
+	_, _ = fmt.Fprintf(w, "Level %d: %d vines, %d blocking edges, max depth %d\n", g.LevelID, len(g.Vines), edges, g.MaxDepth)
+	if len(g.DeepestChain) > 1 {
+		_, _ = fmt.Fprintf(w, "Deepest chain: %s\n", strings.Join(g.DeepestChain, " -> "))
+	}
+	if len(g.Cycles) == 0 {
+		_, _ = fmt.Fprintf(w, "Circular blocking: none\n")
+		return
+	}
+	for _, c := range g.Cycles {
+		_, _ = fmt.Fprintf(w, "Circular blocking: %s\n", strings.Join(c, ", "))
+	}
+}
+
+// WriteDOT writes the graph in Graphviz DOT format. Vines and edges on a cycle
+// are red and the deepest chain is drawn bold blue; each vine is labelled with
+// its blocking depth.
+func (g BlockingGraph) WriteDOT(w io.Writer) error {
+	cycle := make(map[string]int) // vine -> 1 + index of its group in Cycles
+	for i, c := range g.Cycles {
+		for _, id := range c {
+			cycle[id] = i + 1
+		}
+	}
+	chain := make(map[[2]string]bool)
+	for i := 1; i < len(g.DeepestChain); i++ {
+		chain[[2]string{g.DeepestChain[i-1], g.DeepestChain[i]}] = true
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph level_%d {\n", g.LevelID)
+	fmt.Fprintf(&b, "  label=%q;\n", fmt.Sprintf("Level %d blocking graph (A -> B: A blocks B)", g.LevelID))
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=rounded];\n")
+	for _, id := range g.Vines {
+		attrs := fmt.Sprintf("label=%q", fmt.Sprintf("%s\ndepth %d", id, g.Depth[id]))
+		if cycle[id] > 0 {
+			attrs += ", color=red, fontcolor=red"
+		}
+		fmt.Fprintf(&b, "  %q [%s];\n", id, attrs)
+	}
+	for _, from := range g.Vines {
+		for _, to := range g.Edges[from] {
+			var attrs []string
+			if cycle[from] > 0 && cycle[from] == cycle[to] {
+				attrs = append(attrs, "color=red")
+			} else if chain[[2]string{from, to}] {
+				attrs = append(attrs, "color=blue")
+			}
+			if chain[[2]string{from, to}] {
+				attrs = append(attrs, "penwidth=2")
+			}
+			if len(attrs) == 0 {
+				fmt.Fprintf(&b, "  %q -> %q;\n", from, to)
+			} else {
+				fmt.Fprintf(&b, "  %q -> %q [%s];\n", from, to, strings.Join(attrs, ", "))
+			}
+		}
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
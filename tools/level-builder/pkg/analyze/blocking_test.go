@@ -0,0 +1,62 @@
+package analyze
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func TestBlocking(t *testing.T) {
+	// a and b face each other; c points into b and d points into c. e is free.
+	level := &model.Level{
+		ID:       7,
+		GridSize: []int{5, 3},
+		Vines: []model.Vine{
+			{ID: "a", HeadDirection: "right", OrderedPath: []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}},
+			{ID: "b", HeadDirection: "left", OrderedPath: []model.Point{{X: 2, Y: 0}, {X: 3, Y: 0}}},
+			{ID: "c", HeadDirection: "down", OrderedPath: []model.Point{{X: 2, Y: 1}, {X: 3, Y: 1}}},
+			{ID: "d", HeadDirection: "down", OrderedPath: []model.Point{{X: 2, Y: 2}, {X: 1, Y: 2}}},
+			{ID: "e", HeadDirection: "up", OrderedPath: []model.Point{{X: 4, Y: 1}, {X: 4, Y: 0}}},
+		},
+	}
+
+	g := Blocking(level)
+	wantEdges := map[string][]string{"a": {"b"}, "b": {"a", "c"}, "c": {"d"}}
+	if !reflect.DeepEqual(g.Edges, wantEdges) {
+		t.Fatalf("edges = %v, want %v", g.Edges, wantEdges)
+	}
+	if !reflect.DeepEqual(g.Cycles, [][]string{{"a", "b"}}) {
+		t.Errorf("cycles = %v, want [[a b]]", g.Cycles)
+	}
+	if g.MaxDepth != 3 || g.Depth["e"] != 0 || g.Depth["c"] != 1 {
+		t.Errorf("max depth %d, depths %v", g.MaxDepth, g.Depth)
+	}
+	if len(g.DeepestChain) != g.MaxDepth+1 || g.DeepestChain[len(g.DeepestChain)-1] != "d" {
+		t.Errorf("deepest chain = %v", g.DeepestChain)
+	}
+
+	var text bytes.Buffer
+	g.WriteText(&text)
+	if !strings.Contains(text.String(), "Circular blocking: a, b") {
+		t.Errorf("text report missing cycle:\n%s", text.String())
+	}
+
+	var dot bytes.Buffer
+	if err := g.WriteDOT(&dot); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"digraph level_7 {",
+		`"a" [label="a\ndepth`,
+		`"a" -> "b" [color=red`,
+		`"c" -> "d" [color=blue, penwidth=2];`,
+		`"e" [label="e\ndepth 0"];`,
+	} {
+		if !strings.Contains(dot.String(), want) {
+			t.Errorf("DOT output missing %q:\n%s", want, dot.String())
+		}
+	}
+}
@@ -0,0 +1,220 @@
+// Package analyze aggregates statistics across many level files so designers
+// can spot systemic biases in generated levels.
+package analyze
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// Directions lists head directions in report order.
+var Directions = []string{"up", "down", "left", "right"}
+
+// GridHeatmap sums cell usage over every level of one grid size. Rows are
+// indexed [y][x] with y = 0 at the bottom, as in level files.
+type GridHeatmap struct {
+	Width    int     `json:"width"`
+	Height   int     `json:"height"`
+	Levels   int     `json:"levels"`
+	Occupied [][]int `json:"occupied"` // levels with a vine on the cell
+	Heads    [][]int `json:"heads"`    // vine heads (tail heads included) on the cell
+	Masked   [][]int `json:"masked"`   // levels that mask the cell out
+}
+
+// CellUse is one cell's share of the levels of its grid size.
+type CellUse struct {
+	X, Y  int
+	Share float64 // fraction of levels with a vine on the cell
+}
+
+// CoverageReport is the result of Coverage.
+type CoverageReport struct {
+	Levels         int            `json:"levels"`
+	Vines          int            `json:"vines"`
+	Grids          []GridHeatmap  `json:"grids"`           // sorted by size
+	HeadDirections map[string]int `json:"head_directions"` // tail heads included
+	VineLengths    map[int]int    `json:"vine_lengths"`    // length -> vines
+}
+
+// Coverage aggregates occupancy, head positions, head directions and vine
+// lengths across levels. Levels are grouped by grid size, since cells only
+// line up between levels of the same size.
+func Coverage(levels []*model.Level) CoverageReport {
+	report := CoverageReport{
+		HeadDirections: make(map[string]int),
+		VineLengths:    make(map[int]int),
+	}
+	grids := make(map[[2]int]*GridHeatmap)
+
+	for _, level := range levels {
+		w, h := level.GetGridWidth(), level.GetGridHeight()
+		if w <= 0 || h <= 0 {
+			continue
+		}
+		key := [2]int{w, h}
+		g, ok := grids[key]
+		if !ok {
+			g = &GridHeatmap{Width: w, Height: h, Occupied: newCounts(w, h), Heads: newCounts(w, h), Masked: newCounts(w, h)}
+			grids[key] = g
+		}
+		g.Levels++
+		report.Levels++
+
+		occupied := make(map[model.Point]bool)
+		for _, v := range level.Vines {
+			report.Vines++
+			report.VineLengths[len(v.OrderedPath)]++
+			for _, p := range v.OrderedPath {
+				if inBounds(p, w, h) && !occupied[p] {
+					occupied[p] = true
+					g.Occupied[p.Y][p.X]++
+				}
+			}
+			if len(v.OrderedPath) == 0 {
+				continue
+			}
+			for _, head := range v.Heads() {
+				report.HeadDirections[head.HeadDirection]++
+				if p := head.OrderedPath[0]; inBounds(p, w, h) {
+					g.Heads[p.Y][p.X]++
+				}
+			}
+		}
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				if !level.IsCellVisible(x, y) {
+					g.Masked[y][x]++
+				}
+			}
+		}
+	}
+
+	for _, g := range grids {
+		report.Grids = append(report.Grids, *g)
+	}
+	sort.Slice(report.Grids, func(i, j int) bool {
+		a, b := report.Grids[i], report.Grids[j]
+		if a.Width*a.Height != b.Width*b.Height {
+			return a.Width*a.Height < b.Width*b.Height
+		}
+		return a.Width < b.Width
+	})
+	return report
+}
+
+// Cells returns the grid's cells from most to least used, ties in row order
+// from the top. Cells every level masks out are left out.
+func (g GridHeatmap) Cells() []CellUse {
+	var cells []CellUse
+	for y := g.Height - 1; y >= 0; y-- {
+		for x := 0; x < g.Width; x++ {
+			if g.Masked[y][x] == g.Levels {
+				continue
+			}
+			cells = append(cells, CellUse{X: x, Y: y, Share: float64(g.Occupied[y][x]) / float64(g.Levels)})
+		}
+	}
+	sort.SliceStable(cells, func(i, j int) bool { return cells[i].Share > cells[j].Share })
+	return cells
+}
+
+// WriteText prints the report: head direction and vine length distributions,
+// then an occupancy heatmap per grid size with its most and least used cells.
+// Heatmap cells show the percentage of levels with a vine there; cells every
+// level masks out show as "-".
+func WriteText(out io.Writer, r CoverageReport, extremes int) {
+	_, _ = fmt.Fprintf(out, "%d levels, %d vines, %d grid sizes\n", r.Levels, r.Vines, len(r.Grids))
+	if r.Levels == 0 {
+		return
+	}
+
+	heads := 0
+	for _, n := range r.HeadDirections {
+		heads += n
+	}
+	_, _ = fmt.Fprintln(out, "\nHead directions:")
+	for _, dir := range Directions {
+		n := r.HeadDirections[dir]
+		_, _ = fmt.Fprintf(out, "  %-6s %6d  %5.1f%%\n", dir, n, percent(n, heads))
+	}
+
+	lengths := make([]int, 0, len(r.VineLengths))
+	total := 0
+	for length, n := range r.VineLengths {
+		lengths = append(lengths, length)
+		total += length * n
+	}
+	sort.Ints(lengths)
+	_, _ = fmt.Fprintf(out, "\nVine lengths (mean %.2f):\n", float64(total)/float64(max(r.Vines, 1)))
+	for _, length := range lengths {
+		n := r.VineLengths[length]
+		_, _ = fmt.Fprintf(out, "  %3d  %6d  %5.1f%%\n", length, n, percent(n, r.Vines))
+	}
+
+	for _, g := range r.Grids {
+		_, _ = fmt.Fprintf(out, "\nGrid %dx%d (%d levels), %% of levels occupying each cell:\n", g.Width, g.Height, g.Levels)
+		for y := g.Height - 1; y >= 0; y-- {
+			_, _ = fmt.Fprintf(out, "%3d |", y)
+			for x := 0; x < g.Width; x++ {
+				if g.Masked[y][x] == g.Levels {
+					_, _ = fmt.Fprint(out, "   -")
+					continue
+				}
+				_, _ = fmt.Fprintf(out, " %3.0f", percent(g.Occupied[y][x], g.Levels))
+			}
+			_, _ = fmt.Fprintln(out)
+		}
+		_, _ = fmt.Fprint(out, "    +")
+		for x := 0; x < g.Width; x++ {
+			_, _ = fmt.Fprint(out, "----")
+		}
+		_, _ = fmt.Fprint(out, "\n     ")
+		for x := 0; x < g.Width; x++ {
+			_, _ = fmt.Fprintf(out, " %3d", x)
+		}
+		_, _ = fmt.Fprintln(out)
+
+		cells := g.Cells()
+		n := min(extremes, len(cells)/2)
+		if n <= 0 {
+			continue
+		}
+		_, _ = fmt.Fprintf(out, "  most used:  %s\n", cellList(cells[:n]))
+		least := append([]CellUse(nil), cells...)
+		sort.SliceStable(least, func(i, j int) bool { return least[i].Share < least[j].Share })
+		_, _ = fmt.Fprintf(out, "  least used: %s\n", cellList(least[:n]))
+	}
+}
+
+func cellList(cells []CellUse) string {
+	s := ""
+	for i, c := range cells {
+		if i > 0 {
+			s += ", "
+		}
+		s += fmt.Sprintf("(%d,%d) %.0f%%", c.X, c.Y, c.Share*100)
+	}
+	return s
+}
+
+func newCounts(w, h int) [][]int {
+	rows := make([][]int, h)
+	for y := range rows {
+		rows[y] = make([]int, w)
+	}
+	return rows
+}
+
+func inBounds(p model.Point, w, h int) bool {
+	return p.X >= 0 && p.X < w && p.Y >= 0 && p.Y < h
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(n) / float64(total)
+}
@@ -0,0 +1,66 @@
+package analyze
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func TestCoverage(t *testing.T) {
+	a := &model.Level{
+		GridSize: []int{3, 2},
+		Vines: []model.Vine{
+			{ID: "v1", HeadDirection: "right", OrderedPath: []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}},
+			{ID: "v2", HeadDirection: "up", TailDirection: "down", OrderedPath: []model.Point{{X: 2, Y: 1}, {X: 2, Y: 0}}},
+		},
+	}
+	b := &model.Level{
+		GridSize: []int{3, 2},
+		Mask:     &model.Mask{Mode: model.MaskModeHide, Points: []model.Point{{X: 0, Y: 1}}},
+		Vines: []model.Vine{
+			{ID: "v1", HeadDirection: "left", OrderedPath: []model.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}}},
+		},
+	}
+	other := &model.Level{
+		GridSize: []int{4, 4},
+		Vines:    []model.Vine{{ID: "v1", HeadDirection: "up", OrderedPath: []model.Point{{X: 0, Y: 1}, {X: 0, Y: 0}}}},
+	}
+
+	r := Coverage([]*model.Level{a, b, other})
+	if r.Levels != 3 || r.Vines != 4 || len(r.Grids) != 2 {
+		t.Fatalf("got %d levels, %d vines, %d grids", r.Levels, r.Vines, len(r.Grids))
+	}
+	want := map[string]int{"up": 2, "down": 1, "left": 1, "right": 1}
+	for dir, n := range want {
+		if r.HeadDirections[dir] != n {
+			t.Errorf("head direction %s = %d, want %d", dir, r.HeadDirections[dir], n)
+		}
+	}
+	if r.VineLengths[2] != 3 || r.VineLengths[3] != 1 {
+		t.Errorf("vine lengths = %v", r.VineLengths)
+	}
+
+	g := r.Grids[0]
+	if g.Width != 3 || g.Levels != 2 {
+		t.Fatalf("first grid = %dx%d with %d levels, want 3x2 with 2", g.Width, g.Height, g.Levels)
+	}
+	if g.Occupied[0][0] != 2 || g.Occupied[1][0] != 0 || g.Occupied[1][2] != 1 {
+		t.Errorf("occupied = %v", g.Occupied)
+	}
+	if g.Masked[1][0] != 1 || g.Heads[0][2] != 1 {
+		t.Errorf("masked = %v, heads = %v", g.Masked, g.Heads)
+	}
+	if cells := g.Cells(); len(cells) != 6 || cells[0].Share != 1 || cells[3] != (CellUse{X: 2, Y: 1, Share: 0.5}) {
+		t.Errorf("cells = %v", cells)
+	}
+
+	var out bytes.Buffer
+	WriteText(&out, r, 2)
+	for _, want := range []string{"3 levels, 4 vines, 2 grid sizes", "Grid 3x2 (2 levels)", "least used: (0,1) 0%"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
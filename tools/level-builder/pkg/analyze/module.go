@@ -0,0 +1,166 @@
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+// CurvePoint is one level's place on a module's difficulty curve.
+type CurvePoint struct {
+	Key            string  `json:"key"` // logical level key, e.g. lvl_seed_07
+	LevelID        int     `json:"level_id,omitempty"`
+	Vines          int     `json:"vines"`
+	SolutionLength int     `json:"solution_length"`
+	BlockingDepth  int     `json:"blocking_depth"`
+	SolverStates   int     `json:"solver_states"`
+	Solver         string  `json:"solver,omitempty"`
+	Score          float64 `json:"score"`
+	Error          string  `json:"error,omitempty"` // level could not be loaded or solved; it has no score
+}
+
+// Regression is a level that plays easier than one before it in the module.
+type Regression struct {
+	Level   string  `json:"level"`
+	Earlier string  `json:"earlier"`
+	Score   float64 `json:"score"`
+	Peak    float64 `json:"peak"` // the earlier level's score
+}
+
+// ModuleCurve is the result of Module.
+type ModuleCurve struct {
+	ModuleID    int          `json:"module_id"`
+	Name        string       `json:"name"`
+	Tolerance   float64      `json:"tolerance"`
+	Levels      []CurvePoint `json:"levels"` // in play order
+	Regressions []Regression `json:"regressions"`
+}
+
+// LevelLoader loads the level behind a logical level key.
+type LevelLoader func(key string) (*model.Level, error)
+
+// Module scores every level of mod in play order (its levels, then its challenge level)
+// and flags regressions. A level's score is
+//
+//	solution length + 2 × max blocking depth + log2(1 + solver states)
+//
+// so more vines, longer blocking chains and levels the greedy solver cannot clear (it
+// explores no states) all count as harder. A level regresses when its score is more than
+// tolerance (a fraction) below the hardest level before it. maxStates bounds each solve.
+func Module(ctx context.Context, mod model.Module, load LevelLoader, maxStates int, tolerance float64) (ModuleCurve, error) {
+	curve := ModuleCurve{ModuleID: mod.ID, Name: mod.Name, Tolerance: tolerance, Regressions: []Regression{}}
+	keys := mod.Levels
+	if mod.ChallengeLevel != "" && !contains(keys, mod.ChallengeLevel) {
+		keys = append(append([]string{}, keys...), mod.ChallengeLevel)
+	}
+
+	for _, key := range keys {
+		point, err := scoreLevel(ctx, key, load, maxStates)
+		if err != nil {
+			return curve, err
+		}
+		curve.Levels = append(curve.Levels, point)
+	}
+
+	peak := -1
+	for i, p := range curve.Levels {
+		if p.Error != "" {
+			continue
+		}
+		if peak >= 0 && p.Score < curve.Levels[peak].Score*(1-tolerance) {
+			curve.Regressions = append(curve.Regressions, Regression{
+				Level: p.Key, Earlier: curve.Levels[peak].Key, Score: p.Score, Peak: curve.Levels[peak].Score,
+			})
+		}
+		if peak < 0 || p.Score > curve.Levels[peak].Score {
+			peak = i
+		}
+	}
+	return curve, nil
+}
+
+// scoreLevel loads and solves one level. Load and solver failures are recorded on the
+// point; only cancellation is returned as an error.
+func scoreLevel(ctx context.Context, key string, load LevelLoader, maxStates int) (CurvePoint, error) {
+	point := CurvePoint{Key: key}
+	level, err := load(key)
+	if err != nil {
+		point.Error = err.Error()
+		return point, nil
+	}
+	point.LevelID = level.ID
+	point.Vines = len(level.Vines)
+	point.BlockingDepth = Blocking(level).MaxDepth
+
+	ok, solution, stats, err := validator.SolveContext(ctx, *level, maxStates)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return point, ctxErr
+	}
+	point.Solver, point.SolverStates = stats.Solver, stats.StatesExplored
+	switch {
+	case err != nil:
+		point.Error = err.Error()
+	case stats.GaveUp:
+		point.Error = fmt.Sprintf("solver gave up after %d states", stats.StatesExplored)
+	case !ok:
+		point.Error = "not solvable"
+	default:
+		point.SolutionLength = len(solution)
+		point.Score = float64(point.SolutionLength) + 2*float64(point.BlockingDepth) + math.Log2(1+float64(point.SolverStates))
+	}
+	return point, nil
+}
+
+// WriteText prints the curve as a table followed by its regressions.
+func (c ModuleCurve) WriteText(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "Module %d (%s): %d levels\n\n", c.ModuleID, c.Name, len(c.Levels))
+	_, _ = fmt.Fprintf(w, "  %-3s %-26s %5s %5s %5s %8s %6s\n", "#", "Level", "Vines", "Moves", "Depth", "States", "Score")
+	for i, p := range c.Levels {
+		name := p.Key
+		if p.LevelID != 0 {
+			name = fmt.Sprintf("%s (%d)", p.Key, p.LevelID)
+		}
+		if p.Error != "" {
+			_, _ = fmt.Fprintf(w, "  %-3d %-26s ❌ %s\n", i+1, name, p.Error)
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "  %-3d %-26s %5d %5d %5d %8d %6.1f %s\n",
+			i+1, name, p.Vines, p.SolutionLength, p.BlockingDepth, p.SolverStates, p.Score, bar(p.Score))
+	}
+
+	_, _ = fmt.Fprintln(w)
+	if len(c.Regressions) == 0 {
+		_, _ = fmt.Fprintf(w, "✓ No regressions: no level scores more than %.0f%% below an earlier one\n", c.Tolerance*100)
+		return
+	}
+	_, _ = fmt.Fprintf(w, "⚠ %d regressions (score more than %.0f%% below an earlier level):\n", len(c.Regressions), c.Tolerance*100)
+	for _, r := range c.Regressions {
+		_, _ = fmt.Fprintf(w, "  %s (%.1f) is easier than %s (%.1f)\n", r.Level, r.Score, r.Earlier, r.Peak)
+	}
+}
+
+// bar draws a score as a row of blocks, one per two points.
+func bar(score float64) string {
+	n := int(score / 2)
+	if n > 40 {
+		n = 40
+	}
+	b := make([]rune, n)
+	for i := range b {
+		b[i] = '▇'
+	}
+	return string(b)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
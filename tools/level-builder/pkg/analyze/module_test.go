@@ -0,0 +1,66 @@
+package analyze
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// rowLevel returns a level of n independent vines, one per row, all exiting right.
+func rowLevel(id, n int) *model.Level {
+	lvl := &model.Level{ID: id, GridSize: []int{3, n}}
+	for y := 0; y < n; y++ {
+		lvl.Vines = append(lvl.Vines, model.Vine{
+			ID: fmt.Sprintf("v%d", y), HeadDirection: "right",
+			OrderedPath: []model.Point{{X: 1, Y: y}, {X: 0, Y: y}},
+		})
+	}
+	return lvl
+}
+
+func TestModule(t *testing.T) {
+	levels := map[string]*model.Level{
+		"lvl_01":        rowLevel(1, 2),
+		"lvl_02":        rowLevel(2, 8),
+		"lvl_03":        rowLevel(3, 7), // within tolerance of lvl_02
+		"lvl_04":        rowLevel(4, 3), // far easier than lvl_02
+		"lvl_challenge": rowLevel(5, 10),
+	}
+	load := func(key string) (*model.Level, error) {
+		if lvl, ok := levels[key]; ok {
+			return lvl, nil
+		}
+		return nil, fmt.Errorf("no level for %s", key)
+	}
+	mod := model.Module{ID: 1, Name: "Test", Levels: []string{"lvl_01", "lvl_02", "lvl_03", "lvl_04", "lvl_missing"}, ChallengeLevel: "lvl_challenge"}
+
+	curve, err := Module(context.Background(), mod, load, 10000, 0.25)
+	if err != nil {
+		t.Fatalf("Module: %v", err)
+	}
+	if len(curve.Levels) != 6 || curve.Levels[5].Key != "lvl_challenge" {
+		t.Fatalf("levels = %+v, want the 5 module levels then the challenge", curve.Levels)
+	}
+	if p := curve.Levels[1]; p.Vines != 8 || p.SolutionLength != 8 || p.Score < 8 {
+		t.Errorf("lvl_02 = %+v", p)
+	}
+	if p := curve.Levels[4]; p.Error == "" || p.Score != 0 {
+		t.Errorf("missing level should carry an error and no score: %+v", p)
+	}
+	if len(curve.Regressions) != 1 || curve.Regressions[0].Level != "lvl_04" || curve.Regressions[0].Earlier != "lvl_02" {
+		t.Errorf("regressions = %+v, want lvl_04 easier than lvl_02", curve.Regressions)
+	}
+
+	var buf bytes.Buffer
+	curve.WriteText(&buf)
+	out := buf.String()
+	for _, want := range []string{"Module 1 (Test): 6 levels", "lvl_missing", "❌ no level for lvl_missing", "lvl_04 (3.0) is easier than lvl_02 (8.0)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("text missing %q:\n%s", want, out)
+		}
+	}
+}
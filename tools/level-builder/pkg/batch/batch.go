@@ -1,16 +1,19 @@
 package batch
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
-	"runtime"
+	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/constraints"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/levelgen"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/ui"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
@@ -29,9 +32,66 @@ type Config struct {
 	DumpDir     string
 	StatsOut    string  // Optional directory to write per-level stats JSON files
 	MinCoverage float64 // Optional override for minimum coverage (0.0-1.0). 0 = no override
-	Strategy    string  // Optional strategy override (direction-first, center-out)
+	Strategy    string  // Optional strategy override (direction-first, center-out, full-coverage)
+	// FillerStrategy selects the gap filler for placers that support it (e.g. lifo, gap)
+	FillerStrategy string
+	// MaskMode selects how unfilled cells are masked: "hide" (default) or "show"
+	MaskMode string
+	// Stencil leaves its pattern empty but visible in every level (see
+	// levelgen.GenerateOptions.Stencil)
+	Stencil *model.Stencil
+	// Portals adds portal pairs to levels on tiers that allow them
+	Portals bool
+	// Hints embeds the first moves of a solution in each level (0 = none)
+	Hints int
+	// InjectDifficulty deepens blocker chains toward each tier's target
+	// after placement
+	InjectDifficulty bool
+	// OptimizeIterations anneals each placement for that many moves (0 = off)
+	OptimizeIterations int
+	// Profile names a generation profile (see config.GenerationProfiles)
+	Profile string
+	// RNG names the random source (see package random; default math)
+	RNG string
+	// RNGTraceDir, when set, receives a trace of every random draw per attempt
+	RNGTraceDir string
+	// Mirror options: emit a reflected companion for every generated level
+	Mirror         bool
+	MirrorAxis     string // "horizontal" (default) or "vertical"
+	MirrorIDOffset int    // Mirror level ID = source ID + offset (default: DefaultMirrorIDOffset)
+	// ThemeSeed themes every level (see generator.ThemeFor); empty leaves
+	// levels unthemed
+	ThemeSeed string
+	// SkipDifficultyCheck accepts levels regardless of their difficulty score band
+	SkipDifficultyCheck bool
+	// MinAesthetics regenerates levels whose aesthetics score falls below it (0 = off)
+	MinAesthetics float64
+	// Race generates each level with this many racing seeds (see
+	// levelgen.GenerateOptions.Race); 0 or 1 generates deterministically
+	Race int
+	// Constraints holds hand-authored requirements; each level must meet
+	// those for every level, for its tier and for its ID
+	Constraints *constraints.File
+	// Workers is how many levels are generated at once (0 = runtime.NumCPU())
+	Workers int
+	// Resume skips levels that generation_metadata.json records as done and
+	// whose files still validate
+	Resume bool
+	// Progress, if set, receives a ProgressEvent for every level state change,
+	// attempt and log line in place of the spinner. GenerateModule closes it
+	// when it returns; sends block, so drain it until then.
+	Progress chan<- ProgressEvent
 }
 
+// logger prints batch messages; *ui.Spinner and *moduleProgress implement it.
+type logger interface {
+	LogInfo(format string, args ...interface{})
+	LogWarning(format string, args ...interface{})
+}
+
+// DefaultMirrorIDOffset separates mirror level IDs from the regular campaign range.
+const DefaultMirrorIDOffset = 1000
+
 // Result contains results for a single level in a batch.
 type Result struct {
 	LevelID       int
@@ -41,6 +101,26 @@ type Result struct {
 	Coverage      float64
 	BlockingDepth int
 	GenerationMS  int64
+	MirrorLevelID int    // Set when a verified mirror was written
+	MirrorError   string // Set when mirroring or equivalence verification failed
+	// Budget accounting, summed over every attempt made for the level
+	Strategy          string // Strategy that produced the level (empty on failure)
+	Attempts          int    // Generation attempts across all strategies
+	Fallbacks         int    // Strategies abandoned before success
+	PlacementAttempts int
+	Backtracks        int
+	Dumps             int
+	Relaxations       int
+	// Difficulty calibration
+	DifficultyScore      float64 // Score of the accepted level
+	DifficultyRejections int     // Valid levels regenerated because their score was out of band
+	DepthRejections      int     // Valid levels regenerated because their blocking depth was out of band
+	ConstraintRejections int     // Valid levels regenerated because they missed a constraint
+	AestheticsScore      float64 // Aesthetics score of the accepted level
+	AestheticRejections  int     // Valid levels regenerated because they scored below MinAesthetics
+	Resumed              bool    // Reused from a previous run instead of generated
+	// Strategies breaks the attempts down by strategy, in the order tried
+	Strategies []levelgen.StrategyStats
 }
 
 // ModuleBatch represents a complete batch of levels for a module.
@@ -50,6 +130,11 @@ type ModuleBatch struct {
 	TotalTime    time.Duration
 	SuccessCount int
 	FailureCount int
+	MirrorCount  int
+	MirrorFails  int
+	ResumedCount int
+	// Strategies aggregates attempts, successes and time per strategy
+	Strategies []StrategyTelemetry
 }
 
 // difficultyTier maps a tier index (0-4) to difficulty name and specs.
@@ -72,13 +157,24 @@ func getDifficultyTiers() []difficultyTier {
 // GenerateModule generates all 21 levels for a module (5 per tier + 1 Transcendent) concurrently.
 // Pattern: levels 1-5 (Seedling), 6-10 (Sprout), 11-15 (Nurturing), 16-20 (Flourishing), 21 (Transcendent).
 // For module N, level IDs start at (N-1)*21+1.
-func GenerateModule(batchCfg Config) (*ModuleBatch, error) {
+//
+// Cancelling ctx stops in-flight levels and skips the rest. Levels finished
+// before cancellation stay recorded in generation_metadata.json so the module
+// can be completed with Resume, and the returned error wraps ctx.Err().
+func GenerateModule(ctx context.Context, batchCfg Config) (*ModuleBatch, error) {
+	if batchCfg.Progress != nil {
+		defer close(batchCfg.Progress)
+	}
 	if batchCfg.ModuleID < 1 || batchCfg.ModuleID > 5 {
 		return nil, fmt.Errorf("invalid module ID: %d (must be 1-5)", batchCfg.ModuleID)
 	}
 
 	if batchCfg.OutputDir == "" {
-		batchCfg.OutputDir = "assets/levels"
+		levelsDir, err := common.LevelsDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve levels directory: %w", err)
+		}
+		batchCfg.OutputDir = levelsDir
 	}
 
 	startTime := time.Now()
@@ -89,9 +185,26 @@ func GenerateModule(batchCfg Config) (*ModuleBatch, error) {
 
 	startLevelID := (batchCfg.ModuleID-1)*21 + 1
 
-	spin := ui.NewSpinner(fmt.Sprintf("Generating Module %d...", batchCfg.ModuleID))
-	spin.Start()
-	defer spin.Stop()
+	// Per-level progress lets an interrupted run pick up where it stopped
+	var progress *Progress
+	progressPath := ProgressPath(batchCfg.OutputDir)
+	if !batchCfg.DryRun {
+		var err error
+		if progress, err = LoadProgress(progressPath); err != nil {
+			return nil, err
+		}
+	}
+
+	// Progress events replace the spinner when the caller consumes them
+	tracker := newModuleProgress(batchCfg.Progress, 21, levelgen.ResolveStrategy(generateOptions(0, "", batchCfg)))
+	var spin *ui.Spinner
+	var log logger = tracker
+	if tracker == nil {
+		spin = ui.NewSpinner(fmt.Sprintf("Generating Module %d...", batchCfg.ModuleID))
+		spin.Start()
+		defer spin.Stop()
+		log = spin
+	}
 
 	// 1. Gather all levels to generate
 	type levelToGen struct {
@@ -116,17 +229,44 @@ func GenerateModule(batchCfg Config) (*ModuleBatch, error) {
 		difficulty: "Transcendent",
 	})
 
+	resultsMap := make(map[int]Result)
+	completed := 0
+
+	if batchCfg.Resume && progress != nil {
+		var pending []levelToGen
+		for _, l := range levelsToGen {
+			if result, ok := progress.resumable(l.id, batchCfg.OutputDir); ok {
+				resultsMap[l.id] = result
+				completed++
+				tracker.level(l.id, l.difficulty, LevelResumed)
+				continue
+			}
+			pending = append(pending, l)
+		}
+		if len(pending) > 0 {
+			log.LogInfo("Resuming module %d at level %d (%d/21 already done)", batchCfg.ModuleID, pending[0].id, completed)
+		}
+		levelsToGen = pending
+	}
+
 	// 2. Process levels concurrently using bounded worker pool
-	concurrency := runtime.NumCPU()
+	concurrency := common.WorkerCount(batchCfg.Workers)
 	if concurrency > len(levelsToGen) {
 		concurrency = len(levelsToGen)
 	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if tracker != nil {
+		tracker.concurrency = concurrency
+		for _, l := range levelsToGen {
+			tracker.level(l.id, l.difficulty, LevelQueued)
+		}
+	}
 
 	sem := make(chan struct{}, concurrency)
 	var wg sync.WaitGroup
 	var mu sync.Mutex
-	resultsMap := make(map[int]Result)
-	completed := 0
 
 	for _, l := range levelsToGen {
 		l := l
@@ -135,28 +275,54 @@ func GenerateModule(batchCfg Config) (*ModuleBatch, error) {
 			defer wg.Done()
 			sem <- struct{}{}
 			defer func() { <-sem }()
+			if ctx.Err() != nil {
+				return
+			}
 
 			mu.Lock()
-			spin.UpdateMessage("Generating Level ID %d (%d/21 complete)...", l.id, completed)
+			if spin != nil {
+				spin.UpdateMessage("Generating Level ID %d (%d/21 complete)...", l.id, completed)
+			}
 			mu.Unlock()
+			tracker.level(l.id, l.difficulty, LevelRunning)
 
 			result := generateSingleLevel(
+				ctx,
 				l.id,
 				l.difficulty,
 				batchCfg,
-				spin,
+				log,
+				tracker,
 			)
+			if ctx.Err() != nil && !result.Success {
+				// Interrupted levels are left unrecorded so --resume regenerates them
+				return
+			}
+			tracker.finish(result)
 
 			mu.Lock()
 			resultsMap[l.id] = result
+			if progress != nil {
+				progress.Record(batchCfg.ModuleID, result)
+				if err := progress.Save(progressPath); err != nil {
+					log.LogWarning("Could not save progress to %s: %v", progressPath, err)
+				}
+			}
 			completed++
-			spin.UpdateMessage("Completed Level ID %d (%d/21 complete)...", l.id, completed)
+			if spin != nil {
+				spin.UpdateMessage("Completed Level ID %d (%d/21 complete)...", l.id, completed)
+			}
 			mu.Unlock()
 		}()
 	}
 
 	wg.Wait()
 
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("module %d interrupted with %d/21 levels done (re-run with --resume to continue): %w",
+			batchCfg.ModuleID, completed, err)
+	}
+
 	// 3. Re-order results by Level ID so the batch outputs are perfectly deterministic
 	for i := 0; i < 21; i++ {
 		levelID := startLevelID + i
@@ -170,252 +336,301 @@ func GenerateModule(batchCfg Config) (*ModuleBatch, error) {
 		} else {
 			batch.FailureCount++
 		}
+		if result.MirrorLevelID != 0 {
+			batch.MirrorCount++
+		}
+		if result.MirrorError != "" {
+			batch.MirrorFails++
+		}
+		if result.Resumed {
+			batch.ResumedCount++
+		}
 	}
 
 	batch.TotalTime = time.Since(startTime)
+	batch.Strategies = BuildStrategyTelemetry(batch.Levels)
+
+	// The summary sits with the per-level stats so tuning runs can be compared
+	if batchCfg.StatsOut != "" && !batchCfg.DryRun {
+		path := filepath.Join(batchCfg.StatsOut, SummaryFileName)
+		if err := BuildBatchSummary(batch).WriteJSON(path); err != nil {
+			log.LogWarning("Could not write %s: %v", path, err)
+		}
+	}
 
 	return batch, nil
 }
 
-// generateSingleLevel generates a single level and returns results.
-func generateSingleLevel(levelID int, difficulty string, batchCfg Config, spin *ui.Spinner) Result {
+// generateSingleLevel generates a single level and returns results. Attempt
+// events go to tracker when it is set and to log otherwise.
+func generateSingleLevel(ctx context.Context, levelID int, difficulty string, batchCfg Config, log logger, tracker *moduleProgress) Result {
 	result := Result{
 		LevelID:    levelID,
 		Difficulty: difficulty,
 	}
 
-	startTime := time.Now()
-
-	var level model.Level
-	var stats config.GenerationStats
-	var genCfg config.GenerationConfig
-
-	// Strategy Chain:
-	// 1. Requested Strategy (from config or auto-determined)
-	// 2. Center-Out (LIFO) - strongest solvability guarantee
-	// 3. Direction-First - backup for organic shapes
-
-	strategiesToTry := []string{}
-
-	// Determine primary strategy
-	primary := determineStrategy(levelID, difficulty, batchCfg)
-	strategiesToTry = append(strategiesToTry, primary)
-
-	if primary != config.StrategyCenterOut {
-		strategiesToTry = append(strategiesToTry, config.StrategyCenterOut)
-	}
-
-	const maxRetriesPerStrategy = 20
-
-	for _, strat := range strategiesToTry {
-		for retry := 0; retry < maxRetriesPerStrategy; retry++ {
-			var err error
-			// Vary seed for each attempt
-			currentSeed := (int64(levelID) * 31337) + int64(retry*12345) + int64(len(strat))
-
-			genCfg, err = buildGenerationConfig(levelID, difficulty, batchCfg)
-			if err != nil {
-				result.Success = false
-				result.Error = err.Error()
-				return result
-			}
-			genCfg.Seed = currentSeed
-			genCfg.Strategy = strat
-
-			if batchCfg.DryRun {
-				result.Success = true
-				result.GenerationMS = 0
-				result.Coverage = 100.0
-				result.BlockingDepth = 2
-				spin.LogInfo("DRY RUN: Would generate level %d (%s) using %s", levelID, difficulty, strat)
-				return result
-			}
-
-			// Generate
-			level, stats, err = generateLevel(genCfg)
-
-			// Validate
-			valid := false
-			if err == nil {
-				_, valErr := validateGeneratedLevel(level)
-				if valErr == nil {
-					valid = true
-				} else {
-					spin.LogWarning("  Validation failed for level %d (%s): %v", levelID, strat, valErr)
-				}
-			}
-
-			if valid {
-				coverage, _ := validateGeneratedLevel(level)
-				result.Success = true
-				result.Coverage = coverage
-				result.BlockingDepth = 2 // TODO: calculate actual blocking depth
-				result.GenerationMS = time.Since(startTime).Milliseconds()
-				spin.LogInfo("  ✓ Level %d generated using %s (Attempt %d)", levelID, strat, retry+1)
-				goto success
-			}
+	opts := generateOptions(levelID, difficulty, batchCfg)
+	if batchCfg.DryRun {
+		if _, err := levelgen.ConfigFor(opts); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+			return result
 		}
-
-		spin.LogWarning("  Level %d: Strategy %s failed after %d attempts. Trying next fallback...", levelID, strat, maxRetriesPerStrategy)
+		result.Success = true
+		result.GenerationMS = 0
+		result.Coverage = 100.0
+		result.BlockingDepth = 2
+		log.LogInfo("DRY RUN: Would generate level %d (%s) using %s", levelID, difficulty, levelgen.ResolveStrategy(opts))
+		return result
 	}
 
-	result.Success = false
-	result.Error = "failed to generate solvable level after exhausting all strategies"
-	return result
-
-success:
-
-	// Optionally write per-level stats JSON to StatsOut directory
-	if batchCfg.StatsOut != "" {
-		_ = os.MkdirAll(batchCfg.StatsOut, 0o755)
-		statsObj := map[string]interface{}{
-			"level_id":             levelID,
-			"coverage":             result.Coverage,
-			"generation_ms":        result.GenerationMS,
-			"placement_attempts":   stats.PlacementAttempts,
-			"backtracks_attempted": stats.BacktracksAttempted,
-			"dumps_produced":       stats.DumpsProduced,
-			"max_blocking_depth":   stats.MaxBlockingDepth,
+	attemptStart := time.Now()
+	opts.OnProgress = func(e levelgen.Event) {
+		fields := common.Fields{
+			"level_id":   levelID,
+			"difficulty": difficulty,
+			"strategy":   e.Strategy,
+			"attempt":    e.Attempt,
+			"phase":      string(e.Kind),
 		}
-		if stats.BlockingDepthSamples > 0 {
-			statsObj["avg_blocking_depth"] = float64(stats.TotalBlockingDepth) / float64(stats.BlockingDepthSamples)
+		switch e.Kind {
+		case levelgen.EventAttempt:
+			attemptStart = time.Now()
+			common.Event(e.Message, fields)
+			tracker.attempt(difficulty, e)
+			return
+		case levelgen.EventRejected, levelgen.EventAccepted:
+			fields["duration_ms"] = time.Since(attemptStart).Milliseconds()
+		}
+		if common.JSONLogging() {
+			common.Event(e.Message, fields)
+		}
+		if tracker != nil {
+			tracker.attempt(difficulty, e)
+			return
+		}
+		if common.JSONLogging() {
+			return
+		}
+		switch e.Kind {
+		case levelgen.EventRejected, levelgen.EventFallback:
+			log.LogWarning("  %s", e.Message)
+		case levelgen.EventAccepted:
+			log.LogInfo("  ✓ %s", e.Message)
 		}
-		fname := fmt.Sprintf("%s/level_%d_stats.json", batchCfg.StatsOut, levelID)
-		b, _ := json.MarshalIndent(statsObj, "", "  ")
-		_ = os.WriteFile(fname, b, 0o644)
-		spin.LogInfo("Wrote per-level stats: %s", fname)
 	}
 
-	spin.LogInfo("Generated level %d (%s) - Coverage: %.1f%%, Time: %dms",
-		levelID, difficulty, result.Coverage, result.GenerationMS)
+	level, stats, err := levelgen.Generate(ctx, opts)
+	result.Attempts = stats.Attempts
+	result.Fallbacks = stats.Fallbacks
+	result.PlacementAttempts = stats.PlacementAttempts
+	result.Backtracks = stats.Backtracks
+	result.Dumps = stats.Dumps
+	result.Relaxations = stats.Relaxations
+	result.DifficultyRejections = stats.DifficultyRejections
+	result.DepthRejections = stats.DepthRejections
+	result.ConstraintRejections = stats.ConstraintRejections
+	result.AestheticRejections = stats.AestheticRejections
+	result.Strategies = stats.Strategies
+	if err != nil {
+		result.Success = false
+		result.Error = err.Error()
+		result.GenerationMS = stats.Duration.Milliseconds()
+		writeLevelStats(result, stats, batchCfg, log)
+		logLevelResult(result)
+		return result
+	}
 
-	return result
-}
+	if batchCfg.ThemeSeed != "" {
+		theme := generator.ThemeFor(batchCfg.ThemeSeed, level)
+		level.Theme = &theme
+	}
 
-func buildGenerationConfig(levelID int, difficulty string, batchCfg Config) (config.GenerationConfig, error) { // Renamed param to avoid collision
-	spec, ok := config.DifficultySpecs[difficulty]
-	if !ok {
-		return config.GenerationConfig{}, fmt.Errorf("unknown difficulty: %s", difficulty)
-	}
-
-	gridRange, ok := config.GridSizeRanges[difficulty]
-	if !ok {
-		return config.GenerationConfig{}, fmt.Errorf("no grid size config for difficulty: %s", difficulty)
-	}
-
-	gridWidth := (gridRange.MinW + gridRange.MaxW) / 2
-	gridHeight := (gridRange.MinH + gridRange.MaxH) / 2
-	if gridWidth < 2 || gridHeight < 2 {
-		return config.GenerationConfig{}, fmt.Errorf("invalid grid size computed for %s", difficulty)
-	}
-
-	totalCells := gridWidth * gridHeight
-	// Enforce 100% coverage for all levels as per Design Doc
-	targetCoverage := 1.0
-	vineCount := computeVineCount(spec, totalCells, targetCoverage)
-	maxMoves := vineCount * 2
-
-	// Default backtracking settings
-	backtrackWindow := 3
-	maxBackAttempts := 2
-	if batchCfg.Aggressive {
-		backtrackWindow = 6
-		maxBackAttempts = 6
-	}
-
-	genCfg := config.GenerationConfig{
-		LevelID:              levelID,
-		GridWidth:            gridWidth,
-		GridHeight:           gridHeight,
-		VineCount:            vineCount,
-		MaxMoves:             maxMoves,
-		OutputFile:           fmt.Sprintf("%s/level_%d.json", batchCfg.OutputDir, levelID),
-		Randomize:            false,
-		Seed:                 int64(levelID) * 31337,
-		Overwrite:            batchCfg.Overwrite,
-		MinCoverage:          targetCoverage,
-		Difficulty:           difficulty,
-		Strategy:             determineStrategy(levelID, difficulty, batchCfg),
-		BacktrackWindow:      backtrackWindow,
-		MaxBacktrackAttempts: maxBackAttempts,
-	}
-
-	// Apply global MinCoverage override if provided (0 = no override)
-	if batchCfg.MinCoverage > 0 {
-		if batchCfg.MinCoverage < 0.0 || batchCfg.MinCoverage > 1.0 {
-			return config.GenerationConfig{}, fmt.Errorf("invalid MinCoverage override: %v", batchCfg.MinCoverage)
+	genCfg := stats.Config
+	genCfg.OutputFile = levelPath(batchCfg.OutputDir, levelID)
+	genCfg.Overwrite = batchCfg.Overwrite
+	if err := generator.WriteLevelFile(level, genCfg); err != nil {
+		result.Success = false
+		result.Error = err.Error()
+		result.GenerationMS = stats.Duration.Milliseconds()
+		writeLevelStats(result, stats, batchCfg, log)
+		logLevelResult(result)
+		return result
+	}
+	result.Success = true
+	result.Coverage = stats.Coverage
+	result.BlockingDepth = stats.Difficulty.MaxBlockingDepth
+	result.DifficultyScore = stats.Difficulty.Score
+	result.AestheticsScore = stats.Aesthetics.Score
+	result.GenerationMS = stats.Duration.Milliseconds()
+	result.Strategy = stats.Strategy
+
+	if batchCfg.Mirror {
+		mirrorID, err := writeMirrorLevel(level, batchCfg)
+		if err != nil {
+			result.MirrorError = err.Error()
+			log.LogWarning("  Mirror for level %d failed: %v", levelID, err)
+		} else {
+			result.MirrorLevelID = mirrorID
+			log.LogInfo("  ✓ Mirror level %d written for level %d", mirrorID, levelID)
 		}
-		genCfg.MinCoverage = batchCfg.MinCoverage
-	} else {
-		// Default to 1.0 if no override
-		genCfg.MinCoverage = 1.0
 	}
 
-	if batchCfg.DumpDir != "" {
-		genCfg.DumpDir = batchCfg.DumpDir
+	writeLevelStats(result, stats, batchCfg, log)
+
+	if common.JSONLogging() {
+		logLevelResult(result)
+	} else if tracker == nil {
+		log.LogInfo("Generated level %d (%s) - Coverage: %.1f%%, Time: %dms",
+			levelID, difficulty, result.Coverage, result.GenerationMS)
 	}
 
-	return genCfg, nil
+	return result
 }
 
-func computeVineCount(spec config.DifficultySpec, totalCells int, targetCoverage float64) int {
-	avgLength := (spec.AvgLengthRange[0] + spec.AvgLengthRange[1]) / 2
-	if avgLength < 2 {
-		avgLength = 2
+// writeLevelStats writes the level's per-level stats JSON to StatsOut, if
+// set. Failed levels get a file too, with their error and the effort spent,
+// so budget reports count the retries they burned.
+func writeLevelStats(result Result, stats levelgen.Stats, batchCfg Config, log logger) {
+	if batchCfg.StatsOut == "" {
+		return
 	}
-
-	targetOccupiedCells := int(float64(totalCells) * targetCoverage)
-	vineCount := targetOccupiedCells / avgLength
-
-	if vineCount < spec.VineCountRange[0] {
-		vineCount = spec.VineCountRange[0]
+	_ = os.MkdirAll(batchCfg.StatsOut, 0o755)
+	statsObj := map[string]interface{}{
+		"level_id":              result.LevelID,
+		"difficulty":            result.Difficulty,
+		"success":               result.Success,
+		"strategy":              result.Strategy,
+		"attempts":              result.Attempts,
+		"fallbacks":             result.Fallbacks,
+		"relaxations":           result.Relaxations,
+		"difficulty_rejections": result.DifficultyRejections,
+		"depth_rejections":      result.DepthRejections,
+		"constraint_rejections": result.ConstraintRejections,
+		"aesthetic_rejections":  result.AestheticRejections,
+		"generation_ms":         result.GenerationMS,
+		"placement_attempts":    result.PlacementAttempts,
+		"backtracks_attempted":  result.Backtracks,
+		"dumps_produced":        result.Dumps,
 	}
-	if vineCount > spec.VineCountRange[1] {
-		vineCount = spec.VineCountRange[1]
+	if !result.Success {
+		statsObj["error"] = result.Error
+	} else {
+		statsObj["filler_strategy"] = stats.Config.FillerStrategy
+		statsObj["aesthetics"] = stats.Aesthetics
+		statsObj["coverage"] = result.Coverage
+		statsObj["placement_attempts"] = stats.Generation.PlacementAttempts
+		statsObj["backtracks_attempted"] = stats.Generation.BacktracksAttempted
+		statsObj["dumps_produced"] = stats.Generation.DumpsProduced
+		statsObj["max_blocking_depth"] = stats.Generation.MaxBlockingDepth
+		if !batchCfg.SkipDifficultyCheck {
+			statsObj["difficulty_score"] = stats.Difficulty.Score
+			statsObj["difficulty_metrics"] = stats.Difficulty
+		}
+		if stats.Generation.BlockingDepthSamples > 0 {
+			statsObj["avg_blocking_depth"] = float64(stats.Generation.TotalBlockingDepth) / float64(stats.Generation.BlockingDepthSamples)
+		}
 	}
+	fname := fmt.Sprintf("%s/level_%d_stats.json", batchCfg.StatsOut, result.LevelID)
+	b, _ := json.MarshalIndent(statsObj, "", "  ")
+	_ = os.WriteFile(fname, b, 0o644)
+	log.LogInfo("Wrote per-level stats: %s", fname)
+}
 
-	if vineCount > totalCells/4 {
-		vineCount = totalCells / 4
+// logLevelResult records a level's outcome as a structured event.
+func logLevelResult(result Result) {
+	fields := common.Fields{
+		"level_id":    result.LevelID,
+		"difficulty":  result.Difficulty,
+		"strategy":    result.Strategy,
+		"attempt":     result.Attempts,
+		"phase":       "generated",
+		"duration_ms": result.GenerationMS,
+		"coverage":    result.Coverage,
+		"fallbacks":   result.Fallbacks,
+	}
+	msg := fmt.Sprintf("Generated level %d (%s)", result.LevelID, result.Difficulty)
+	if !result.Success {
+		fields["phase"] = "failed"
+		fields["error"] = result.Error
+		msg = fmt.Sprintf("Failed to generate level %d (%s)", result.LevelID, result.Difficulty)
+	} else {
+		fields["difficulty_score"] = result.DifficultyScore
+		fields["blocking_depth"] = result.BlockingDepth
 	}
-	if vineCount < 3 {
-		vineCount = 3
+	common.Event(msg, fields)
+}
+
+// generateOptions maps batch settings onto the library options for one level.
+func generateOptions(levelID int, difficulty string, batchCfg Config) levelgen.GenerateOptions {
+	return levelgen.GenerateOptions{
+		LevelID:             levelID,
+		Difficulty:          difficulty,
+		Strategy:            batchCfg.Strategy,
+		FillerStrategy:      batchCfg.FillerStrategy,
+		MaskMode:            batchCfg.MaskMode,
+		Stencil:             batchCfg.Stencil,
+		Portals:             batchCfg.Portals,
+		Hints:               batchCfg.Hints,
+		InjectDifficulty:    batchCfg.InjectDifficulty,
+		OptimizeIterations:  batchCfg.OptimizeIterations,
+		Profile:             batchCfg.Profile,
+		RNG:                 batchCfg.RNG,
+		RNGTraceDir:         batchCfg.RNGTraceDir,
+		MinCoverage:         batchCfg.MinCoverage,
+		Aggressive:          batchCfg.Aggressive,
+		DumpDir:             batchCfg.DumpDir,
+		SkipDifficultyCheck: batchCfg.SkipDifficultyCheck,
+		MinAesthetics:       batchCfg.MinAesthetics,
+		Race:                batchCfg.Race,
+		Constraints:         batchCfg.Constraints.For(levelID, difficulty),
 	}
+}
 
-	return vineCount
+// BuildGenerationConfig derives the generation config batch runs use for a level.
+func BuildGenerationConfig(levelID int, difficulty string, batchCfg Config) (config.GenerationConfig, error) {
+	genCfg, err := levelgen.ConfigFor(generateOptions(levelID, difficulty, batchCfg))
+	if err != nil {
+		return config.GenerationConfig{}, err
+	}
+	genCfg.OutputFile = levelPath(batchCfg.OutputDir, levelID)
+	genCfg.Overwrite = batchCfg.Overwrite
+	return genCfg, nil
 }
 
-func generateLevel(genConfig config.GenerationConfig) (model.Level, config.GenerationStats, error) {
-	return generator.GenerateLevel(genConfig)
+func levelPath(outputDir string, levelID int) string {
+	return fmt.Sprintf("%s/level_%d.json", outputDir, levelID)
 }
 
-func determineStrategy(levelID int, difficulty string, batchCfg Config) string {
-	// If explicit strategy override provided, use it
-	if batchCfg.Strategy != "" {
-		return batchCfg.Strategy
+// MirrorLevelID returns the ID assigned to the mirror of levelID.
+func MirrorLevelID(levelID int, batchCfg Config) int {
+	offset := batchCfg.MirrorIDOffset
+	if offset <= 0 {
+		offset = DefaultMirrorIDOffset
 	}
-
-	// Always use ClearableFirst (optimized) for high coverage >95%
-	return config.StrategyLegacyClearable
+	return levelID + offset
 }
 
-func validateGeneratedLevel(level model.Level) (float64, error) {
-	structErrors := validator.ValidateStructural(level)
-	if len(structErrors) > 0 {
-		for _, e := range structErrors {
-			common.Warning("  [STRUCTURAL ERROR] Level %d: %v", level.ID, e)
-		}
-		return 0, fmt.Errorf("structural validation failed: %d errors", len(structErrors))
+// writeMirrorLevel reflects level, verifies it is equivalent to the source and
+// writes it next to the source level. Returns the mirror's level ID.
+func writeMirrorLevel(level model.Level, batchCfg Config) (int, error) {
+	axis := batchCfg.MirrorAxis
+	if axis == "" {
+		axis = common.MirrorHorizontal
 	}
+	mirrorID := MirrorLevelID(level.ID, batchCfg)
 
-	solvable, _, err := validator.IsSolvable(level, 1000000)
+	mirror, err := common.MirrorLevel(&level, mirrorID, axis)
 	if err != nil {
-		return 0, fmt.Errorf("solvability check error: %v", err)
+		return 0, err
 	}
-
-	if !solvable {
-		return 0, fmt.Errorf("level not solvable")
+	if err := validator.VerifyMirrorEquivalence(level, mirror, 1000000); err != nil {
+		return 0, fmt.Errorf("mirror of level %d is not equivalent: %w", level.ID, err)
 	}
 
-	coverage := (float64(level.GetOccupiedCells()) / float64(level.GetTotalCells())) * 100.0
-	return coverage, nil
+	path := levelPath(batchCfg.OutputDir, mirrorID)
+	if err := common.WriteLevel(path, &mirror, batchCfg.Overwrite); err != nil {
+		return 0, err
+	}
+	return mirrorID, nil
 }
@@ -1,10 +1,16 @@
 package batch
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/levelgen"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/ui"
 )
 
@@ -21,7 +27,7 @@ func TestGenerateModuleWritesStats(t *testing.T) {
 
 	// Run GenerateModule for a single level via generateSingleLevel helper
 	spin := ui.NewSpinner("test")
-	result := generateSingleLevel(1, "Seedling", cfg, spin)
+	result := generateSingleLevel(context.Background(), 1, "Seedling", cfg, spin, nil)
 	if !result.Success {
 		t.Fatalf("expected generation to succeed, got error: %s", result.Error)
 	}
@@ -31,4 +37,243 @@ func TestGenerateModuleWritesStats(t *testing.T) {
 	if _, err := os.Stat(statsFile); os.IsNotExist(err) {
 		t.Fatalf("expected stats file to exist: %s", statsFile)
 	}
+
+	// The level file carries its own telemetry for stats levels
+	writeTinyLevel(t, cfg.OutputDir, 2)
+	results, untracked, err := LoadResultsFromLevels(cfg.OutputDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].LevelID != 1 || results[0].Strategy != result.Strategy || results[0].Attempts != result.Attempts {
+		t.Errorf("expected level 1's telemetry matching %+v, got %+v", result, results)
+	}
+	if len(untracked) != 1 || untracked[0] != 2 {
+		t.Errorf("expected level 2 untracked, got %v", untracked)
+	}
+}
+
+func TestWriteMirrorLevel(t *testing.T) {
+	a, err := model.NewVine("vine_1", []model.Point{{X: 0, Y: 0}, {X: 1, Y: 0}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := model.NewVine("vine_2", []model.Point{{X: 2, Y: 1}, {X: 2, Y: 0}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	level := model.Level{
+		ID:          2,
+		Name:        "Level 2",
+		GridSize:    []int{3, 2},
+		Vines:       []model.Vine{a, b},
+		MaxMoves:    4,
+		MinMoves:    2,
+		Grace:       3,
+		ColorScheme: []string{"#000000"},
+		Mask:        &model.Mask{Mode: "hide", Points: []model.Point{{X: 0, Y: 1}, {X: 1, Y: 1}}},
+	}
+	cfg := Config{OutputDir: filepath.Join(t.TempDir(), "levels")}
+
+	mirrorID, err := writeMirrorLevel(level, cfg)
+	if err != nil {
+		t.Fatalf("expected mirror to verify, got: %v", err)
+	}
+	if wantID := 2 + DefaultMirrorIDOffset; mirrorID != wantID {
+		t.Fatalf("expected mirror ID %d, got %d", wantID, mirrorID)
+	}
+
+	mirror, err := common.ReadLevel(filepath.Join(cfg.OutputDir, "level_1002.json"))
+	if err != nil {
+		t.Fatalf("expected readable mirror file: %v", err)
+	}
+	if mirror.MirrorOf != 2 || mirror.MirrorAxis != common.MirrorHorizontal {
+		t.Fatalf("expected mirror metadata linking to level 2, got of=%d axis=%q", mirror.MirrorOf, mirror.MirrorAxis)
+	}
+}
+
+func TestBuildBudgetReportFlagsHotTier(t *testing.T) {
+	results := []Result{
+		{Difficulty: "Seedling", Success: true, GenerationMS: 10, Attempts: 1},
+		{Difficulty: "Seedling", Success: true, GenerationMS: 10, Attempts: 1},
+		{Difficulty: "Transcendent", Success: false, GenerationMS: 380, Attempts: 40, Fallbacks: 1, Dumps: 3},
+	}
+
+	report := BuildBudgetReport(results)
+	if report.Levels != 3 || report.TotalMS != 400 {
+		t.Fatalf("unexpected totals: levels=%d total=%d", report.Levels, report.TotalMS)
+	}
+	if len(report.Tiers) != 2 || report.Tiers[0].Difficulty != "Seedling" {
+		t.Fatalf("expected tiers in campaign order, got %+v", report.Tiers)
+	}
+
+	seedling, transcendent := report.Tiers[0], report.Tiers[1]
+	if seedling.Disproportionate {
+		t.Errorf("Seedling should not be flagged")
+	}
+	if !transcendent.Disproportionate || transcendent.Failures != 1 || transcendent.MeanAttempts != 40 {
+		t.Errorf("expected Transcendent to be flagged with its costs, got %+v", transcendent)
+	}
+}
+
+func TestBuildBatchSummaryAggregatesStrategies(t *testing.T) {
+	batch := &ModuleBatch{
+		ModuleID: 1,
+		Levels: []Result{
+			{Difficulty: "Seedling", Success: true, Strategies: []levelgen.StrategyStats{
+				{Strategy: "direction-first", Attempts: 2, Rejections: 1, Accepted: true, Duration: 30 * time.Millisecond},
+			}},
+			{Difficulty: "Transcendent", Success: true, Strategies: []levelgen.StrategyStats{
+				{Strategy: "direction-first", Attempts: 4, Rejections: 4, Duration: 50 * time.Millisecond},
+				{Strategy: "center-out", Attempts: 1, Accepted: true, Duration: 20 * time.Millisecond},
+			}},
+			{Difficulty: "Seedling", Success: true, Resumed: true},
+		},
+	}
+
+	summary := BuildBatchSummary(batch)
+	if len(summary.Strategies) != 2 || summary.Strategies[0].Strategy != "center-out" {
+		t.Fatalf("expected two strategies sorted by name, got %+v", summary.Strategies)
+	}
+	df := summary.Strategies[1]
+	if df.Levels != 2 || df.Attempts != 6 || df.Successes != 1 || df.Fallbacks != 1 || df.Rejections != 5 || df.TotalMS != 80 {
+		t.Errorf("unexpected direction-first totals: %+v", df)
+	}
+	if df.SuccessRate != 0.5 {
+		t.Errorf("expected direction-first success rate 0.5, got %v", df.SuccessRate)
+	}
+	if len(summary.Tiers) != 2 || summary.Tiers[0].Difficulty != "Seedling" || len(summary.Tiers[0].Strategies) != 1 {
+		t.Fatalf("expected per-tier telemetry in campaign order, got %+v", summary.Tiers)
+	}
+
+	path := filepath.Join(t.TempDir(), SummaryFileName)
+	if err := summary.WriteJSON(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected %s to be written: %v", path, err)
+	}
+}
+
+func TestBuildLevelStatsReport(t *testing.T) {
+	results := []Result{
+		{LevelID: 1, Difficulty: "Seedling", Strategy: "center-out", GenerationMS: 10, Attempts: 1},
+		{LevelID: 2, Difficulty: "Seedling", Strategy: "legacy-clearable", GenerationMS: 20, Attempts: 2, Relaxations: 1},
+		{LevelID: 21, Difficulty: "Transcendent", Strategy: "center-out", GenerationMS: 370, Attempts: 9, Backtracks: 12},
+	}
+
+	report := BuildLevelStatsReport(results, []int{5}, 2)
+	if report.Budget.Levels != 3 || len(report.Untracked) != 1 {
+		t.Fatalf("unexpected totals: %+v", report.Budget)
+	}
+	if len(report.Strategies) != 3 || report.Strategies[0].Strategy != "center-out" || report.Strategies[2].Difficulty != "Transcendent" {
+		t.Fatalf("expected strategies in tier then name order, got %+v", report.Strategies)
+	}
+	if hot := report.Strategies[2]; !hot.Disproportionate || hot.MaxAttempts != 9 || hot.Backtracks != 12 {
+		t.Errorf("expected Transcendent center-out flagged with its costs, got %+v", hot)
+	}
+	if len(report.Expensive) != 2 || report.Expensive[0].LevelID != 21 || report.Expensive[1].LevelID != 2 {
+		t.Errorf("expected the two slowest levels, slowest first, got %+v", report.Expensive)
+	}
+}
+
+// writeTinyLevel writes a small solvable level with the given ID to dir.
+func writeTinyLevel(t *testing.T, dir string, id int) {
+	t.Helper()
+	a, _ := model.NewVine("vine_1", []model.Point{{X: 0, Y: 0}, {X: 1, Y: 0}}, "")
+	b, _ := model.NewVine("vine_2", []model.Point{{X: 2, Y: 1}, {X: 2, Y: 0}}, "")
+	level := model.Level{
+		ID: id, Name: "Tiny", GridSize: []int{3, 2}, Vines: []model.Vine{a, b},
+		MaxMoves: 4, MinMoves: 2, Grace: 3, ColorScheme: []string{"#000000"},
+		Mask: &model.Mask{Mode: "hide", Points: []model.Point{{X: 0, Y: 1}, {X: 1, Y: 1}}},
+	}
+	if err := common.WriteLevel(filepath.Join(dir, fmt.Sprintf("level_%d.json", id)), &level, true); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProgressResumable(t *testing.T) {
+	dir := t.TempDir()
+	path := ProgressPath(dir)
+
+	progress, err := LoadProgress(path)
+	if err != nil || len(progress.Levels) != 0 {
+		t.Fatalf("missing progress file should load empty, got %v, %v", progress, err)
+	}
+
+	writeTinyLevel(t, dir, 1)
+	progress.Record(1, Result{LevelID: 1, Difficulty: "Seedling", Success: true, Strategy: "center-out", Attempts: 3})
+	progress.Record(1, Result{LevelID: 2, Difficulty: "Seedling", Error: "boom"})
+	progress.Record(1, Result{LevelID: 3, Difficulty: "Seedling", Success: true})
+	if err := progress.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadProgress(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Levels[2]; got.Status != StatusFailed || got.Error != "boom" {
+		t.Errorf("failed level not round-tripped: %+v", got)
+	}
+
+	if result, ok := loaded.resumable(1, dir); !ok || !result.Resumed || result.Strategy != "center-out" || result.Attempts != 3 {
+		t.Errorf("level 1 should resume with its recorded result, got %+v, %v", result, ok)
+	}
+	if _, ok := loaded.resumable(2, dir); ok {
+		t.Error("failed level must not resume")
+	}
+	if _, ok := loaded.resumable(3, dir); ok {
+		t.Error("level without a file on disk must not resume")
+	}
+}
+
+func TestGenerateModuleResumeSkipsDoneLevels(t *testing.T) {
+	dir := t.TempDir()
+	progress, _ := LoadProgress(ProgressPath(dir))
+	for id := 1; id <= 21; id++ {
+		writeTinyLevel(t, dir, id)
+		progress.Record(1, Result{LevelID: id, Difficulty: "Seedling", Success: true})
+	}
+	if err := progress.Save(ProgressPath(dir)); err != nil {
+		t.Fatal(err)
+	}
+
+	batch, err := GenerateModule(context.Background(), Config{ModuleID: 1, OutputDir: dir, Resume: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batch.ResumedCount != 21 || batch.SuccessCount != 21 {
+		t.Fatalf("expected all 21 levels resumed, got resumed=%d success=%d", batch.ResumedCount, batch.SuccessCount)
+	}
+}
+
+func TestGenerateModuleStreamsProgress(t *testing.T) {
+	events := make(chan ProgressEvent)
+	var got []ProgressEvent
+	collected := make(chan struct{})
+	go func() {
+		defer close(collected)
+		for e := range events {
+			got = append(got, e)
+		}
+	}()
+
+	if _, err := GenerateModule(context.Background(), Config{ModuleID: 1, DryRun: true, Progress: events}); err != nil {
+		t.Fatal(err)
+	}
+	<-collected // GenerateModule closed the channel
+
+	states := make(map[LevelState]int)
+	for _, e := range got {
+		if e.LevelID != 0 {
+			states[e.State]++
+		}
+	}
+	if states[LevelQueued] != 21 || states[LevelRunning] != 21 || states[LevelDone] != 21 {
+		t.Fatalf("level states = %v", states)
+	}
+	last := got[len(got)-1]
+	if last.Completed != 21 || last.Total != 21 || last.MaxAttempts == 0 {
+		t.Errorf("last event = %+v", last)
+	}
 }
@@ -0,0 +1,177 @@
+package batch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DisproportionateFactor flags a tier whose share of generation time exceeds
+// its share of levels by this factor.
+const DisproportionateFactor = 1.5
+
+// TierBudget aggregates generation cost for one difficulty tier.
+type TierBudget struct {
+	Difficulty           string  `json:"difficulty"`
+	Levels               int     `json:"levels"`
+	Failures             int     `json:"failures"`
+	TotalMS              int64   `json:"total_ms"`
+	MeanMS               float64 `json:"mean_ms"`
+	MeanAttempts         float64 `json:"mean_attempts"`
+	MeanPlacementAttempt float64 `json:"mean_placement_attempts"`
+	Relaxations          int     `json:"relaxations"`
+	Backtracks           int     `json:"backtracks"`
+	Dumps                int     `json:"dumps"`
+	Fallbacks            int     `json:"fallbacks"`
+	TimeShare            float64 `json:"time_share"`  // fraction of total generation time
+	LevelShare           float64 `json:"level_share"` // fraction of total levels
+	Disproportionate     bool    `json:"disproportionate"`
+}
+
+// BudgetReport summarizes per-tier generation cost across one or more batches.
+type BudgetReport struct {
+	Levels  int          `json:"levels"`
+	TotalMS int64        `json:"total_ms"`
+	Tiers   []TierBudget `json:"tiers"`
+}
+
+// tierOrder keeps tiers in campaign order in reports.
+var tierOrder = map[string]int{
+	"Seedling": 0, "Sprout": 1, "Nurturing": 2, "Flourishing": 3, "Transcendent": 4,
+}
+
+// BuildBudgetReport aggregates results (from a module or a whole campaign) by tier.
+func BuildBudgetReport(results []Result) BudgetReport {
+	byTier := make(map[string]*TierBudget)
+	report := BudgetReport{}
+
+	for _, r := range results {
+		tb, ok := byTier[r.Difficulty]
+		if !ok {
+			tb = &TierBudget{Difficulty: r.Difficulty}
+			byTier[r.Difficulty] = tb
+		}
+		tb.Levels++
+		if !r.Success {
+			tb.Failures++
+		}
+		tb.TotalMS += r.GenerationMS
+		tb.MeanAttempts += float64(r.Attempts)
+		tb.MeanPlacementAttempt += float64(r.PlacementAttempts)
+		tb.Relaxations += r.Relaxations
+		tb.Backtracks += r.Backtracks
+		tb.Dumps += r.Dumps
+		tb.Fallbacks += r.Fallbacks
+
+		report.Levels++
+		report.TotalMS += r.GenerationMS
+	}
+
+	for _, tb := range byTier {
+		n := float64(tb.Levels)
+		tb.MeanMS = float64(tb.TotalMS) / n
+		tb.MeanAttempts /= n
+		tb.MeanPlacementAttempt /= n
+		tb.LevelShare = n / float64(report.Levels)
+		if report.TotalMS > 0 {
+			tb.TimeShare = float64(tb.TotalMS) / float64(report.TotalMS)
+		}
+		tb.Disproportionate = tb.TimeShare > tb.LevelShare*DisproportionateFactor
+		report.Tiers = append(report.Tiers, *tb)
+	}
+
+	sort.Slice(report.Tiers, func(i, j int) bool {
+		return tierLess(report.Tiers[i].Difficulty, report.Tiers[j].Difficulty)
+	})
+
+	return report
+}
+
+// WriteText prints the report as a table, marking disproportionate tiers.
+func (r BudgetReport) WriteText(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "\n=== Generation Budget Report (%d levels, %dms) ===\n", r.Levels, r.TotalMS)
+	_, _ = fmt.Fprintf(w, "%-13s %6s %5s %9s %8s %8s %6s %6s %6s %6s\n",
+		"Tier", "Levels", "Fail", "MeanMS", "Attempts", "Relaxed", "Backtr", "Dumps", "Fallbk", "Time%")
+	for _, t := range r.Tiers {
+		flag := ""
+		if t.Disproportionate {
+			flag = "  <-- disproportionate"
+		}
+		_, _ = fmt.Fprintf(w, "%-13s %6d %5d %9.1f %8.2f %8d %6d %6d %6d %5.1f%%%s\n",
+			t.Difficulty, t.Levels, t.Failures, t.MeanMS, t.MeanAttempts,
+			t.Relaxations, t.Backtracks, t.Dumps, t.Fallbacks, t.TimeShare*100, flag)
+	}
+}
+
+// WriteJSON writes the report to path as indented JSON.
+func (r BudgetReport) WriteJSON(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create report dir: %w", err)
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal budget report: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// levelStatsFile mirrors the per-level stats JSON written to Config.StatsOut.
+type levelStatsFile struct {
+	LevelID           int     `json:"level_id"`
+	Difficulty        string  `json:"difficulty"`
+	Strategy          string  `json:"strategy"`
+	Attempts          int     `json:"attempts"`
+	Fallbacks         int     `json:"fallbacks"`
+	Relaxations       int     `json:"relaxations"`
+	Coverage          float64 `json:"coverage"`
+	GenerationMS      int64   `json:"generation_ms"`
+	PlacementAttempts int     `json:"placement_attempts"`
+	Backtracks        int     `json:"backtracks_attempted"`
+	Dumps             int     `json:"dumps_produced"`
+	// Success is absent from files written before failed levels got stats
+	// files, all of which were successes
+	Success *bool  `json:"success"`
+	Error   string `json:"error"`
+}
+
+// LoadResultsFromStats reads per-level stats JSON files (level_*_stats.json)
+// from the given directories, so budget reports can span a whole campaign,
+// failed levels included.
+func LoadResultsFromStats(dirs ...string) ([]Result, error) {
+	var results []Result
+	for _, dir := range dirs {
+		files, err := filepath.Glob(filepath.Join(dir, "level_*_stats.json"))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			data, err := os.ReadFile(f)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", f, err)
+			}
+			var s levelStatsFile
+			if err := json.Unmarshal(data, &s); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", f, err)
+			}
+			results = append(results, Result{
+				LevelID:           s.LevelID,
+				Difficulty:        s.Difficulty,
+				Success:           s.Success == nil || *s.Success,
+				Error:             s.Error,
+				Coverage:          s.Coverage,
+				GenerationMS:      s.GenerationMS,
+				Strategy:          s.Strategy,
+				Attempts:          s.Attempts,
+				Fallbacks:         s.Fallbacks,
+				PlacementAttempts: s.PlacementAttempts,
+				Backtracks:        s.Backtracks,
+				Dumps:             s.Dumps,
+				Relaxations:       s.Relaxations,
+			})
+		}
+	}
+	return results, nil
+}
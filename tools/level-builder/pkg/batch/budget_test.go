@@ -0,0 +1,44 @@
+package batch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/levelgen"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/ui"
+)
+
+func TestBudgetReportFromStatsCountsFailures(t *testing.T) {
+	dir := t.TempDir()
+	cfg := Config{StatsOut: dir}
+	log := ui.NewSpinner("test")
+	writeLevelStats(Result{LevelID: 1, Difficulty: "Seedling", Success: true, Attempts: 1, GenerationMS: 100}, levelgen.Stats{}, cfg, log)
+	writeLevelStats(Result{LevelID: 2, Difficulty: "Sprout", Success: false, Error: "no valid level", Attempts: 12, GenerationMS: 900}, levelgen.Stats{}, cfg, log)
+	// Written before stats files recorded success: a successful level
+	legacy := `{"level_id": 3, "difficulty": "Sprout", "attempts": 2, "generation_ms": 100}`
+	if err := os.WriteFile(filepath.Join(dir, "level_3_stats.json"), []byte(legacy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := LoadResultsFromStats(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := BuildBudgetReport(results)
+	if report.Levels != 3 || report.TotalMS != 1100 || len(report.Tiers) != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+	seedling, sprout := report.Tiers[0], report.Tiers[1]
+	if seedling.Difficulty != "Seedling" || seedling.Failures != 0 {
+		t.Errorf("Seedling = %+v, want no failures", seedling)
+	}
+	if sprout.Difficulty != "Sprout" || sprout.Levels != 2 || sprout.Failures != 1 || sprout.TotalMS != 1000 || sprout.MeanAttempts != 7 {
+		t.Errorf("Sprout = %+v, want 2 levels, 1 failure, 1000ms, 7 attempts on average", sprout)
+	}
+	for _, r := range results {
+		if r.LevelID == 2 && r.Error != "no valid level" {
+			t.Errorf("level 2 error = %q", r.Error)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package batch
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/levelgen"
+)
+
+// LevelState is where a level is in a module run.
+type LevelState string
+
+const (
+	LevelQueued  LevelState = "queued"
+	LevelRunning LevelState = "running"
+	LevelDone    LevelState = "done"
+	LevelFailed  LevelState = "failed"
+	LevelResumed LevelState = "resumed" // reused from a previous run
+)
+
+// ProgressEvent reports module progress on Config.Progress. Level events
+// carry the level's state and attempt counters; log events have LevelID 0
+// and only a Message. Every event carries the module totals and ETA.
+type ProgressEvent struct {
+	LevelID    int
+	Difficulty string
+	State      LevelState
+	Strategy   string
+	// Attempt is the 1-based attempt within Strategy; Attempts and
+	// Relaxations total the finished attempts across strategies, out of
+	// MaxAttempts for the whole strategy chain
+	Attempt     int
+	Attempts    int
+	MaxAttempts int
+	Relaxations int
+	Message     string
+	Warning     bool
+	// Module totals at the time of the event
+	Completed int
+	Total     int
+	Elapsed   time.Duration
+	// ETA estimates the time left from the average generated level so far
+	// (zero until a level has been generated)
+	ETA time.Duration
+}
+
+// moduleProgress streams ProgressEvents for one GenerateModule run. A nil
+// *moduleProgress reports nothing, so callers need not check.
+type moduleProgress struct {
+	mu          sync.Mutex
+	ch          chan<- ProgressEvent
+	start       time.Time
+	total       int
+	completed   int
+	concurrency int
+	generated   int           // levels generated (not resumed) this run
+	genTime     time.Duration // summed generation time of those levels
+	maxAttempts int
+}
+
+// newModuleProgress tracks total levels whose strategy chain starts with strategy.
+func newModuleProgress(ch chan<- ProgressEvent, total int, strategy string) *moduleProgress {
+	if ch == nil {
+		return nil
+	}
+	return &moduleProgress{
+		ch:          ch,
+		start:       time.Now(),
+		total:       total,
+		concurrency: 1,
+		maxAttempts: len(levelgen.StrategyChain(strategy)) * levelgen.DefaultMaxRetriesPerStrategy,
+	}
+}
+
+// send stamps e with the module totals and delivers it. Sends block, so the
+// consumer must drain the channel until GenerateModule closes it.
+func (p *moduleProgress) send(e ProgressEvent) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch e.State {
+	case LevelDone, LevelFailed, LevelResumed:
+		p.completed++
+	}
+	e.MaxAttempts = p.maxAttempts
+	e.Completed, e.Total = p.completed, p.total
+	e.Elapsed = time.Since(p.start)
+	if p.generated > 0 {
+		avg := p.genTime / time.Duration(p.generated)
+		e.ETA = avg * time.Duration(p.total-p.completed) / time.Duration(p.concurrency)
+	}
+	p.ch <- e
+}
+
+// level reports a level changing state.
+func (p *moduleProgress) level(levelID int, difficulty string, state LevelState) {
+	p.send(ProgressEvent{LevelID: levelID, Difficulty: difficulty, State: state})
+}
+
+// attempt reports a levelgen progress event for a running level.
+func (p *moduleProgress) attempt(difficulty string, e levelgen.Event) {
+	if p == nil {
+		return
+	}
+	p.send(ProgressEvent{
+		LevelID:     e.LevelID,
+		Difficulty:  difficulty,
+		State:       LevelRunning,
+		Strategy:    e.Strategy,
+		Attempt:     e.Attempt,
+		Attempts:    e.Attempts,
+		Relaxations: e.Relaxations,
+		Message:     e.Message,
+		Warning:     e.Kind == levelgen.EventRejected || e.Kind == levelgen.EventFallback,
+	})
+}
+
+// finish reports a level's result and folds its time into the ETA.
+func (p *moduleProgress) finish(result Result) {
+	if p == nil {
+		return
+	}
+	state := LevelDone
+	if !result.Success {
+		state = LevelFailed
+	}
+	p.mu.Lock()
+	p.generated++
+	p.genTime += time.Duration(result.GenerationMS) * time.Millisecond
+	p.mu.Unlock()
+	p.send(ProgressEvent{
+		LevelID:     result.LevelID,
+		Difficulty:  result.Difficulty,
+		State:       state,
+		Strategy:    result.Strategy,
+		Attempts:    result.Attempts,
+		Relaxations: result.Relaxations,
+		Message:     result.Error,
+		Warning:     !result.Success,
+	})
+}
+
+// LogInfo sends a log line; it lets moduleProgress stand in for the spinner.
+func (p *moduleProgress) LogInfo(format string, args ...interface{}) {
+	p.send(ProgressEvent{Message: fmt.Sprintf(format, args...)})
+}
+
+// LogWarning sends a warning log line.
+func (p *moduleProgress) LogWarning(format string, args ...interface{}) {
+	p.send(ProgressEvent{Message: fmt.Sprintf(format, args...), Warning: true})
+}
@@ -0,0 +1,208 @@
+package batch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+)
+
+// StrategyBudget aggregates generation cost for one strategy within a tier.
+type StrategyBudget struct {
+	Difficulty       string  `json:"difficulty"`
+	Strategy         string  `json:"strategy"`
+	Levels           int     `json:"levels"`
+	MeanAttempts     float64 `json:"mean_attempts"`
+	MaxAttempts      int     `json:"max_attempts"`
+	Relaxations      int     `json:"relaxations"`
+	Backtracks       int     `json:"backtracks"`
+	MeanMS           float64 `json:"mean_ms"`
+	MaxMS            int64   `json:"max_ms"`
+	TimeShare        float64 `json:"time_share"`
+	Disproportionate bool    `json:"disproportionate"`
+}
+
+// LevelCost is one level's persisted generation telemetry.
+type LevelCost struct {
+	LevelID      int    `json:"level_id"`
+	Difficulty   string `json:"difficulty"`
+	Strategy     string `json:"strategy"`
+	Attempts     int    `json:"attempts"`
+	Relaxations  int    `json:"relaxations"`
+	Backtracks   int    `json:"backtracks"`
+	GenerationMS int64  `json:"generation_ms"`
+}
+
+// LevelStatsReport summarizes the telemetry persisted in level files.
+type LevelStatsReport struct {
+	Budget     BudgetReport     `json:"budget"`
+	Strategies []StrategyBudget `json:"strategies"`
+	// Expensive lists the levels that took longest to generate, slowest first
+	Expensive []LevelCost `json:"expensive"`
+	// Untracked lists levels without telemetry of their own: hand-made
+	// levels, lessons, levels from older runs and mirrors
+	Untracked []int `json:"untracked"`
+}
+
+// LoadResultsFromLevels reads the generation telemetry from the level files in
+// dirs. Levels without telemetry are returned by ID in untracked.
+func LoadResultsFromLevels(dirs ...string) (results []Result, untracked []int, err error) {
+	for _, dir := range dirs {
+		levels, err := common.ReadLevelsFromDir(dir)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, l := range levels {
+			// A mirror carries its source's telemetry; counting it would double it
+			if l.GenerationAttempts == 0 || l.MirrorOf != 0 {
+				untracked = append(untracked, l.ID)
+				continue
+			}
+			results = append(results, Result{
+				LevelID:      l.ID,
+				Difficulty:   l.Difficulty,
+				Success:      true,
+				GenerationMS: l.GenerationElapsedMS,
+				Strategy:     l.GenerationStrategy,
+				Attempts:     l.GenerationAttempts,
+				Relaxations:  l.GenerationRelaxations,
+				Backtracks:   l.GenerationBacktracks,
+			})
+		}
+	}
+	sort.Ints(untracked)
+	return results, untracked, nil
+}
+
+// BuildLevelStatsReport aggregates results by tier and by tier and strategy,
+// and keeps the top most expensive levels.
+func BuildLevelStatsReport(results []Result, untracked []int, top int) LevelStatsReport {
+	report := LevelStatsReport{Budget: BuildBudgetReport(results), Untracked: untracked}
+
+	type key struct{ difficulty, strategy string }
+	groups := make(map[key]*StrategyBudget)
+	for _, r := range results {
+		k := key{r.Difficulty, r.Strategy}
+		sb, ok := groups[k]
+		if !ok {
+			sb = &StrategyBudget{Difficulty: r.Difficulty, Strategy: r.Strategy}
+			groups[k] = sb
+		}
+		sb.Levels++
+		sb.MeanAttempts += float64(r.Attempts)
+		sb.MaxAttempts = max(sb.MaxAttempts, r.Attempts)
+		sb.Relaxations += r.Relaxations
+		sb.Backtracks += r.Backtracks
+		sb.MeanMS += float64(r.GenerationMS)
+		sb.MaxMS = max(sb.MaxMS, r.GenerationMS)
+	}
+
+	for _, sb := range groups {
+		totalMS := sb.MeanMS
+		n := float64(sb.Levels)
+		sb.MeanAttempts /= n
+		sb.MeanMS /= n
+		if report.Budget.TotalMS > 0 {
+			sb.TimeShare = totalMS / float64(report.Budget.TotalMS)
+		}
+		levelShare := n / float64(report.Budget.Levels)
+		sb.Disproportionate = sb.TimeShare > levelShare*DisproportionateFactor
+		report.Strategies = append(report.Strategies, *sb)
+	}
+	sort.Slice(report.Strategies, func(i, j int) bool {
+		a, b := report.Strategies[i], report.Strategies[j]
+		if a.Difficulty != b.Difficulty {
+			return tierLess(a.Difficulty, b.Difficulty)
+		}
+		return a.Strategy < b.Strategy
+	})
+
+	expensive := make([]LevelCost, 0, len(results))
+	for _, r := range results {
+		expensive = append(expensive, LevelCost{
+			LevelID:      r.LevelID,
+			Difficulty:   r.Difficulty,
+			Strategy:     r.Strategy,
+			Attempts:     r.Attempts,
+			Relaxations:  r.Relaxations,
+			Backtracks:   r.Backtracks,
+			GenerationMS: r.GenerationMS,
+		})
+	}
+	sort.Slice(expensive, func(i, j int) bool {
+		if expensive[i].GenerationMS != expensive[j].GenerationMS {
+			return expensive[i].GenerationMS > expensive[j].GenerationMS
+		}
+		return expensive[i].LevelID < expensive[j].LevelID
+	})
+	report.Expensive = expensive[:min(max(top, 0), len(expensive))]
+	return report
+}
+
+// tierLess orders difficulties in campaign order, unknown tiers last by name.
+func tierLess(a, b string) bool {
+	oa, aok := tierOrder[a]
+	ob, bok := tierOrder[b]
+	if aok != bok {
+		return aok
+	}
+	if oa != ob {
+		return oa < ob
+	}
+	return a < b
+}
+
+// WriteText prints the tier budget, the per-strategy breakdown and the most
+// expensive levels.
+func (r LevelStatsReport) WriteText(w io.Writer) {
+	r.Budget.WriteText(w)
+
+	_, _ = fmt.Fprintf(w, "\n=== By Strategy ===\n")
+	_, _ = fmt.Fprintf(w, "%-13s %-16s %6s %8s %7s %8s %6s %9s %8s %6s\n",
+		"Tier", "Strategy", "Levels", "Attempts", "MaxAtt", "Relaxed", "Backtr", "MeanMS", "MaxMS", "Time%")
+	for _, s := range r.Strategies {
+		flag := ""
+		if s.Disproportionate {
+			flag = "  <-- disproportionate"
+		}
+		_, _ = fmt.Fprintf(w, "%-13s %-16s %6d %8.2f %7d %8d %6d %9.1f %8d %5.1f%%%s\n",
+			s.Difficulty, strategyLabel(s.Strategy), s.Levels, s.MeanAttempts, s.MaxAttempts,
+			s.Relaxations, s.Backtracks, s.MeanMS, s.MaxMS, s.TimeShare*100, flag)
+	}
+
+	if len(r.Expensive) > 0 {
+		_, _ = fmt.Fprintf(w, "\n=== Most Expensive Levels ===\n")
+		for _, l := range r.Expensive {
+			_, _ = fmt.Fprintf(w, "level %-5d %-13s %-16s %6dms %3d attempts %3d relaxations %4d backtracks\n",
+				l.LevelID, l.Difficulty, strategyLabel(l.Strategy), l.GenerationMS, l.Attempts, l.Relaxations, l.Backtracks)
+		}
+	}
+
+	if len(r.Untracked) > 0 {
+		_, _ = fmt.Fprintf(w, "\n%d levels have no generation telemetry and were skipped\n", len(r.Untracked))
+	}
+}
+
+// strategyLabel names levels from generators that did not record a strategy.
+func strategyLabel(strategy string) string {
+	if strategy == "" {
+		return "unknown"
+	}
+	return strategy
+}
+
+// WriteJSON writes the report to path as indented JSON.
+func (r LevelStatsReport) WriteJSON(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create report dir: %w", err)
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal level stats report: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,135 @@
+package batch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/levelgen"
+)
+
+// ProgressFileName is the per-level progress file kept next to generated levels.
+const ProgressFileName = common.GenerationMetadataFile
+
+// Level progress states recorded in generation_metadata.json.
+const (
+	StatusDone   = "done"
+	StatusFailed = "failed"
+)
+
+// LevelProgress records the outcome of one level so an interrupted batch can resume.
+type LevelProgress struct {
+	LevelID         int       `json:"level_id"`
+	ModuleID        int       `json:"module_id"`
+	Difficulty      string    `json:"difficulty"`
+	Status          string    `json:"status"`
+	Error           string    `json:"error,omitempty"`
+	Strategy        string    `json:"strategy,omitempty"`
+	Attempts        int       `json:"attempts"`
+	Coverage        float64   `json:"coverage,omitempty"`
+	DifficultyScore float64   `json:"difficulty_score,omitempty"`
+	GenerationMS    int64     `json:"generation_ms"`
+	MirrorLevelID   int       `json:"mirror_level_id,omitempty"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Progress is the contents of generation_metadata.json, keyed by level ID.
+type Progress struct {
+	Levels map[int]LevelProgress `json:"levels"`
+}
+
+// ProgressPath returns the progress file location for an output directory.
+func ProgressPath(outputDir string) string {
+	return filepath.Join(outputDir, ProgressFileName)
+}
+
+// LoadProgress reads the progress file at path. A missing file yields empty progress.
+func LoadProgress(path string) (*Progress, error) {
+	progress := &Progress{Levels: make(map[int]LevelProgress)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return progress, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, progress); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if progress.Levels == nil {
+		progress.Levels = make(map[int]LevelProgress)
+	}
+	return progress, nil
+}
+
+// Save writes the progress file atomically so an interrupted run never leaves it half-written.
+func (p *Progress) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Record stores the outcome of result for moduleID.
+func (p *Progress) Record(moduleID int, result Result) {
+	entry := LevelProgress{
+		LevelID:         result.LevelID,
+		ModuleID:        moduleID,
+		Difficulty:      result.Difficulty,
+		Status:          StatusDone,
+		Strategy:        result.Strategy,
+		Attempts:        result.Attempts,
+		Coverage:        result.Coverage,
+		DifficultyScore: result.DifficultyScore,
+		GenerationMS:    result.GenerationMS,
+		MirrorLevelID:   result.MirrorLevelID,
+		UpdatedAt:       time.Now().UTC(),
+	}
+	if !result.Success {
+		entry.Status = StatusFailed
+		entry.Error = result.Error
+	}
+	p.Levels[result.LevelID] = entry
+}
+
+// resumable reports whether levelID was recorded as done and its file on disk
+// still passes validation, returning the result to reuse.
+func (p *Progress) resumable(levelID int, outputDir string) (Result, bool) {
+	entry, ok := p.Levels[levelID]
+	if !ok || entry.Status != StatusDone {
+		return Result{}, false
+	}
+
+	level, err := common.ReadLevel(filepath.Join(outputDir, fmt.Sprintf("level_%d.json", levelID)))
+	if err != nil {
+		return Result{}, false
+	}
+	if _, err := levelgen.Validate(*level); err != nil {
+		return Result{}, false
+	}
+
+	return Result{
+		LevelID:         levelID,
+		Difficulty:      entry.Difficulty,
+		Success:         true,
+		Coverage:        entry.Coverage,
+		GenerationMS:    entry.GenerationMS,
+		MirrorLevelID:   entry.MirrorLevelID,
+		Strategy:        entry.Strategy,
+		Attempts:        entry.Attempts,
+		DifficultyScore: entry.DifficultyScore,
+		Resumed:         true,
+	}, true
+}
@@ -0,0 +1,135 @@
+package batch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// SummaryFileName is the per-batch summary written to Config.StatsOut.
+const SummaryFileName = "batch_summary.json"
+
+// StrategyTelemetry aggregates how one strategy fared across a batch, so the
+// strategy chain and profile defaults can be tuned from real runs.
+type StrategyTelemetry struct {
+	Strategy    string  `json:"strategy"`
+	Levels      int     `json:"levels"`    // Levels that tried the strategy
+	Attempts    int     `json:"attempts"`  // Generation attempts made with it
+	Successes   int     `json:"successes"` // Levels it produced
+	Fallbacks   int     `json:"fallbacks"` // Levels that gave up on it
+	Rejections  int     `json:"rejections"`
+	TotalMS     int64   `json:"total_ms"`
+	SuccessRate float64 `json:"success_rate"` // Successes per level that tried it
+	MeanMS      float64 `json:"mean_attempt_ms"`
+}
+
+// TierStrategies is the strategy telemetry for one difficulty tier.
+type TierStrategies struct {
+	Difficulty string              `json:"difficulty"`
+	Strategies []StrategyTelemetry `json:"strategies"`
+}
+
+// BatchSummary is the contents of batch_summary.json.
+type BatchSummary struct {
+	ModuleID     int                 `json:"module_id"`
+	Levels       int                 `json:"levels"`
+	SuccessCount int                 `json:"success_count"`
+	FailureCount int                 `json:"failure_count"`
+	ResumedCount int                 `json:"resumed_count"`
+	TotalMS      int64               `json:"total_ms"`
+	Strategies   []StrategyTelemetry `json:"strategies"`
+	Tiers        []TierStrategies    `json:"tiers"`
+}
+
+// BuildStrategyTelemetry aggregates the per-strategy attempts of results.
+// Resumed levels made no attempts and are left out.
+func BuildStrategyTelemetry(results []Result) []StrategyTelemetry {
+	byStrategy := make(map[string]*StrategyTelemetry)
+
+	for _, r := range results {
+		for _, s := range r.Strategies {
+			st, ok := byStrategy[s.Strategy]
+			if !ok {
+				st = &StrategyTelemetry{Strategy: s.Strategy}
+				byStrategy[s.Strategy] = st
+			}
+			st.Levels++
+			st.Attempts += s.Attempts
+			st.Rejections += s.Rejections
+			st.TotalMS += s.Duration.Milliseconds()
+			if s.Accepted {
+				st.Successes++
+			} else if s.Attempts > 0 {
+				st.Fallbacks++
+			}
+		}
+	}
+
+	telemetry := make([]StrategyTelemetry, 0, len(byStrategy))
+	for _, st := range byStrategy {
+		st.SuccessRate = float64(st.Successes) / float64(st.Levels)
+		if st.Attempts > 0 {
+			st.MeanMS = float64(st.TotalMS) / float64(st.Attempts)
+		}
+		telemetry = append(telemetry, *st)
+	}
+	sort.Slice(telemetry, func(i, j int) bool {
+		return telemetry[i].Strategy < telemetry[j].Strategy
+	})
+	return telemetry
+}
+
+// BuildBatchSummary summarizes a module batch, with strategy telemetry for the
+// whole module and for each tier.
+func BuildBatchSummary(batch *ModuleBatch) BatchSummary {
+	summary := BatchSummary{
+		ModuleID:     batch.ModuleID,
+		Levels:       len(batch.Levels),
+		SuccessCount: batch.SuccessCount,
+		FailureCount: batch.FailureCount,
+		ResumedCount: batch.ResumedCount,
+		TotalMS:      batch.TotalTime.Milliseconds(),
+		Strategies:   BuildStrategyTelemetry(batch.Levels),
+	}
+
+	byTier := make(map[string][]Result)
+	for _, r := range batch.Levels {
+		byTier[r.Difficulty] = append(byTier[r.Difficulty], r)
+	}
+	for difficulty, results := range byTier {
+		summary.Tiers = append(summary.Tiers, TierStrategies{
+			Difficulty: difficulty,
+			Strategies: BuildStrategyTelemetry(results),
+		})
+	}
+	sort.Slice(summary.Tiers, func(i, j int) bool {
+		return tierLess(summary.Tiers[i].Difficulty, summary.Tiers[j].Difficulty)
+	})
+	return summary
+}
+
+// WriteStrategyText prints per-strategy telemetry as a table.
+func WriteStrategyText(w io.Writer, telemetry []StrategyTelemetry) {
+	_, _ = fmt.Fprintf(w, "\n=== Strategy Telemetry ===\n")
+	_, _ = fmt.Fprintf(w, "%-20s %6s %8s %7s %6s %8s %10s\n",
+		"Strategy", "Levels", "Attempts", "Success", "Fallbk", "Success%", "MeanMS")
+	for _, t := range telemetry {
+		_, _ = fmt.Fprintf(w, "%-20s %6d %8d %7d %6d %7.1f%% %10.1f\n",
+			t.Strategy, t.Levels, t.Attempts, t.Successes, t.Fallbacks, t.SuccessRate*100, t.MeanMS)
+	}
+}
+
+// WriteJSON writes the summary to path as indented JSON.
+func (s BatchSummary) WriteJSON(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create summary dir: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch summary: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,263 @@
+// Package bench runs a fixed matrix of seeds, difficulties and strategies
+// through the generator and compares the results with a stored baseline, so
+// performance regressions in placement or solving show up before they ship.
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/levelgen"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+// DefaultMaxStates is the solver budget used when Options.MaxStates is unset.
+const DefaultMaxStates = 500000
+
+// Suite is a benchmark matrix: every strategy generates every difficulty once
+// per seed.
+type Suite struct {
+	Name         string
+	Difficulties []string
+	Strategies   []string
+	Seeds        []int64
+}
+
+// Suites are the named suites available to the bench command.
+var Suites = map[string]Suite{
+	"quick": {
+		Name:         "quick",
+		Difficulties: []string{"Seedling", "Sprout"},
+		Strategies:   []string{config.StrategyCenterOut, config.StrategyLegacyClearable},
+		Seeds:        []int64{1, 2},
+	},
+	"standard": {
+		Name:         "standard",
+		Difficulties: []string{"Seedling", "Sprout", "Nurturing", "Flourishing"},
+		Strategies: []string{
+			config.StrategyDirectionFirst,
+			config.StrategyCenterOut,
+			config.StrategyFullCoverage,
+			config.StrategyLegacyClearable,
+		},
+		Seeds: []int64{1, 2, 3, 4, 5},
+	},
+}
+
+// SuiteNames returns the names of the available suites, sorted.
+func SuiteNames() []string {
+	names := make([]string, 0, len(Suites))
+	for name := range Suites {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Options configures Run.
+type Options struct {
+	LevelID   int    // level ID used for grid sizing (default 1)
+	MaxStates int    // solver budget per level (default DefaultMaxStates)
+	DumpDir   string // where placers write failure dumps (default failing_dumps)
+	// OnProgress, if set, is called after each case with the number of cases done.
+	OnProgress func(done, total int)
+}
+
+// CaseResult is the outcome of one strategy generating one difficulty with one seed.
+type CaseResult struct {
+	Difficulty        string  `json:"difficulty"`
+	Strategy          string  `json:"strategy"`
+	Seed              int64   `json:"seed"`
+	OK                bool    `json:"ok"` // generated and verified solvable
+	Error             string  `json:"error,omitempty"`
+	GenerationMS      float64 `json:"generation_ms"`
+	SolveMS           float64 `json:"solve_ms"`
+	Coverage          float64 `json:"coverage"` // vine coverage (0-100)
+	PlacementAttempts int     `json:"placement_attempts"`
+	Backtracks        int     `json:"backtracks"`
+	Solver            string  `json:"solver,omitempty"`
+	SolverStates      int     `json:"solver_states"`
+}
+
+// Report is a full suite run. Saved reports serve as baselines.
+type Report struct {
+	Suite     string       `json:"suite"`
+	LevelID   int          `json:"level_id"`
+	CreatedAt time.Time    `json:"created_at"`
+	Cases     []CaseResult `json:"cases"`
+}
+
+// Run generates every case of suite in turn and verifies each level with the
+// solver. Cases run one at a time so their timings are comparable between
+// runs. A failing case is recorded, not returned; cancelling ctx aborts the
+// run with ctx.Err().
+func Run(ctx context.Context, suite Suite, opts Options) (Report, error) {
+	levelID := opts.LevelID
+	if levelID <= 0 {
+		levelID = 1
+	}
+	maxStates := opts.MaxStates
+	if maxStates <= 0 {
+		maxStates = DefaultMaxStates
+	}
+
+	report := Report{Suite: suite.Name, LevelID: levelID, CreatedAt: time.Now().UTC()}
+	total := len(suite.Difficulties) * len(suite.Strategies) * len(suite.Seeds)
+	for _, difficulty := range suite.Difficulties {
+		for _, strategy := range suite.Strategies {
+			for _, seed := range suite.Seeds {
+				result, err := runCase(ctx, levelID, difficulty, strategy, seed, maxStates, opts.DumpDir)
+				if err != nil {
+					return Report{}, err
+				}
+				report.Cases = append(report.Cases, result)
+				if opts.OnProgress != nil {
+					opts.OnProgress(len(report.Cases), total)
+				}
+			}
+		}
+	}
+	return report, nil
+}
+
+func runCase(ctx context.Context, levelID int, difficulty, strategy string, seed int64, maxStates int, dumpDir string) (CaseResult, error) {
+	result := CaseResult{Difficulty: difficulty, Strategy: strategy, Seed: seed}
+
+	cfg, err := levelgen.ConfigFor(levelgen.GenerateOptions{
+		LevelID:    levelID,
+		Difficulty: difficulty,
+		Seed:       seed,
+		Strategy:   strategy,
+		DumpDir:    dumpDir,
+	})
+	if err != nil {
+		return CaseResult{}, err
+	}
+
+	start := time.Now()
+	level, stats, genErr := generator.GenerateRobust(ctx, cfg)
+	result.GenerationMS = milliseconds(time.Since(start))
+	result.PlacementAttempts = stats.PlacementAttempts
+	result.Backtracks = stats.BacktracksAttempted
+	if err := ctx.Err(); err != nil {
+		return CaseResult{}, err
+	}
+	if genErr != nil {
+		result.Error = genErr.Error()
+		return result, nil
+	}
+	if total := level.GetTotalCells(); total > 0 {
+		result.Coverage = 100 * float64(level.GetOccupiedCells()) / float64(total)
+	}
+
+	start = time.Now()
+	ok, solveStats, solveErr := validator.IsSolvableContext(ctx, level, maxStates)
+	result.SolveMS = milliseconds(time.Since(start))
+	result.Solver = solveStats.Solver
+	result.SolverStates = solveStats.StatesExplored
+	if err := ctx.Err(); err != nil {
+		return CaseResult{}, err
+	}
+	switch {
+	case solveErr != nil:
+		result.Error = solveErr.Error()
+	case !ok:
+		result.Error = "level not solvable"
+	default:
+		result.OK = true
+	}
+	return result, nil
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// LoadReport reads a saved report, such as a baseline.
+func LoadReport(path string) (Report, error) {
+	var report Report
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return Report{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return report, nil
+}
+
+// Save writes the report atomically.
+func (r Report) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Group sums the cases of one difficulty and strategy.
+type Group struct {
+	Difficulty        string
+	Strategy          string
+	Cases             int
+	Failures          int
+	GenerationMS      float64
+	SolveMS           float64
+	MeanCoverage      float64 // over successful cases
+	PlacementAttempts int
+	SolverStates      int
+}
+
+// Key identifies the group across reports.
+func (g Group) Key() string {
+	return g.Difficulty + "/" + g.Strategy
+}
+
+// Groups sums the report's cases by difficulty and strategy, in the order
+// they first appear.
+func (r Report) Groups() []Group {
+	var groups []Group
+	index := make(map[string]int)
+	covered := make(map[string]int)
+	for _, c := range r.Cases {
+		key := c.Difficulty + "/" + c.Strategy
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, Group{Difficulty: c.Difficulty, Strategy: c.Strategy})
+		}
+		g := &groups[i]
+		g.Cases++
+		g.GenerationMS += c.GenerationMS
+		g.SolveMS += c.SolveMS
+		g.PlacementAttempts += c.PlacementAttempts
+		g.SolverStates += c.SolverStates
+		if !c.OK {
+			g.Failures++
+			continue
+		}
+		g.MeanCoverage += c.Coverage
+		covered[key]++
+	}
+	for i := range groups {
+		if n := covered[groups[i].Key()]; n > 0 {
+			groups[i].MeanCoverage /= float64(n)
+		}
+	}
+	return groups
+}
@@ -0,0 +1,88 @@
+package bench
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+)
+
+func TestRunAndRoundTrip(t *testing.T) {
+	suite := Suite{
+		Name:         "tiny",
+		Difficulties: []string{"Seedling"},
+		Strategies:   []string{config.StrategyCenterOut},
+		Seeds:        []int64{1, 2},
+	}
+	calls := 0
+	report, err := Run(context.Background(), suite, Options{
+		DumpDir:    t.TempDir(),
+		OnProgress: func(done, total int) { calls++ },
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(report.Cases) != 2 || calls != 2 {
+		t.Fatalf("got %d cases and %d progress calls, want 2 and 2", len(report.Cases), calls)
+	}
+	for _, c := range report.Cases {
+		if !c.OK {
+			t.Errorf("seed %d failed: %s", c.Seed, c.Error)
+		}
+		if c.Coverage <= 0 || c.Solver == "" {
+			t.Errorf("seed %d missing metrics: %+v", c.Seed, c)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := report.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := LoadReport(path)
+	if err != nil {
+		t.Fatalf("LoadReport: %v", err)
+	}
+	if cmp := Compare(loaded, report, DefaultThresholds()); !cmp.Passed() {
+		t.Errorf("run regressed against itself: %v", cmp.Regressions())
+	}
+}
+
+func TestCompare(t *testing.T) {
+	baseline := Report{Cases: []CaseResult{
+		{Difficulty: "Seedling", Strategy: "a", OK: true, GenerationMS: 100, Coverage: 100, PlacementAttempts: 10, SolverStates: 100},
+		{Difficulty: "Seedling", Strategy: "b", OK: true, GenerationMS: 100, Coverage: 100, PlacementAttempts: 10, SolverStates: 100},
+		{Difficulty: "Seedling", Strategy: "gone", OK: true},
+	}}
+	current := Report{Cases: []CaseResult{
+		// Within thresholds: small slowdown, same attempts
+		{Difficulty: "Seedling", Strategy: "a", OK: true, GenerationMS: 140, Coverage: 99.5, PlacementAttempts: 12, SolverStates: 120},
+		// Regressed on every measure
+		{Difficulty: "Seedling", Strategy: "b", Error: "boom", GenerationMS: 400, PlacementAttempts: 20, SolverStates: 300},
+		{Difficulty: "Seedling", Strategy: "new", OK: true},
+	}}
+
+	cmp := Compare(baseline, current, DefaultThresholds())
+	if len(cmp.Deltas) != 3 {
+		t.Fatalf("got %d deltas, want 3", len(cmp.Deltas))
+	}
+	if p := cmp.Deltas[0].Problems; len(p) != 0 {
+		t.Errorf("Seedling/a should pass, got %v", p)
+	}
+	problems := strings.Join(cmp.Deltas[1].Problems, "; ")
+	for _, want := range []string{"failures", "time", "placement attempts", "solver states"} {
+		if !strings.Contains(problems, want) {
+			t.Errorf("Seedling/b problems %q missing %q", problems, want)
+		}
+	}
+	if cmp.Deltas[2].Baseline != nil || len(cmp.Deltas[2].Problems) != 0 {
+		t.Errorf("Seedling/new should be reported without a baseline")
+	}
+	if len(cmp.Missing) != 1 || cmp.Missing[0] != "Seedling/gone" {
+		t.Errorf("Missing = %v, want [Seedling/gone]", cmp.Missing)
+	}
+	if cmp.Passed() {
+		t.Error("comparison with regressions should not pass")
+	}
+}
@@ -0,0 +1,114 @@
+package bench
+
+import "fmt"
+
+// Thresholds bound how far a run may drift from its baseline before a group
+// counts as a regression. Ratios compare group totals (current/baseline).
+type Thresholds struct {
+	MaxTimeRatio     float64 // generation + solve time
+	MinTimeDeltaMS   float64 // slowdowns smaller than this are treated as noise
+	MaxCoverageDrop  float64 // mean coverage, in percentage points
+	MaxAttemptsRatio float64 // placement attempts
+	MaxStatesRatio   float64 // solver states explored
+}
+
+// DefaultThresholds returns the thresholds used by the bench command.
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		MaxTimeRatio:     1.5,
+		MinTimeDeltaMS:   50,
+		MaxCoverageDrop:  1,
+		MaxAttemptsRatio: 1.25,
+		MaxStatesRatio:   1.25,
+	}
+}
+
+// Delta pairs a group with its baseline. Baseline is nil for groups the
+// baseline did not run.
+type Delta struct {
+	Current  Group
+	Baseline *Group
+	Problems []string // threshold violations; empty when the group passed
+}
+
+// Comparison is the result of comparing a run against a baseline.
+type Comparison struct {
+	Deltas  []Delta
+	Missing []string // baseline groups the current run did not cover
+}
+
+// Regressions returns every threshold violation, prefixed with its group.
+func (c Comparison) Regressions() []string {
+	var out []string
+	for _, d := range c.Deltas {
+		for _, p := range d.Problems {
+			out = append(out, d.Current.Key()+": "+p)
+		}
+	}
+	return out
+}
+
+// Passed reports whether no group regressed.
+func (c Comparison) Passed() bool {
+	return len(c.Regressions()) == 0
+}
+
+// Compare checks each group of current against the same group in baseline.
+// Groups are compared on totals, so both reports should come from the same
+// suite; groups only one side ran are reported but never fail.
+func Compare(baseline, current Report, th Thresholds) Comparison {
+	base := make(map[string]Group)
+	for _, g := range baseline.Groups() {
+		base[g.Key()] = g
+	}
+
+	var cmp Comparison
+	seen := make(map[string]bool)
+	for _, g := range current.Groups() {
+		seen[g.Key()] = true
+		d := Delta{Current: g}
+		if b, ok := base[g.Key()]; ok {
+			d.Baseline = &b
+			d.Problems = groupProblems(b, g, th)
+		}
+		cmp.Deltas = append(cmp.Deltas, d)
+	}
+	for _, g := range baseline.Groups() {
+		if !seen[g.Key()] {
+			cmp.Missing = append(cmp.Missing, g.Key())
+		}
+	}
+	return cmp
+}
+
+func groupProblems(base, cur Group, th Thresholds) []string {
+	var problems []string
+	if cur.Failures > base.Failures {
+		problems = append(problems, fmt.Sprintf("failures %d -> %d", base.Failures, cur.Failures))
+	}
+
+	baseMS := base.GenerationMS + base.SolveMS
+	curMS := cur.GenerationMS + cur.SolveMS
+	if curMS-baseMS > th.MinTimeDeltaMS && exceeds(baseMS, curMS, th.MaxTimeRatio) {
+		problems = append(problems, fmt.Sprintf("time %.0fms -> %.0fms", baseMS, curMS))
+	}
+	if base.MeanCoverage-cur.MeanCoverage > th.MaxCoverageDrop {
+		problems = append(problems, fmt.Sprintf("coverage %.1f%% -> %.1f%%", base.MeanCoverage, cur.MeanCoverage))
+	}
+	if exceeds(float64(base.PlacementAttempts), float64(cur.PlacementAttempts), th.MaxAttemptsRatio) {
+		problems = append(problems, fmt.Sprintf("placement attempts %d -> %d", base.PlacementAttempts, cur.PlacementAttempts))
+	}
+	if exceeds(float64(base.SolverStates), float64(cur.SolverStates), th.MaxStatesRatio) {
+		problems = append(problems, fmt.Sprintf("solver states %d -> %d", base.SolverStates, cur.SolverStates))
+	}
+	return problems
+}
+
+// exceeds reports whether cur is more than ratio times base. A ratio of zero
+// or less disables the check.
+func exceeds(base, cur, ratio float64) bool {
+	if ratio <= 0 {
+		return false
+	}
+	return cur > base*ratio
+}
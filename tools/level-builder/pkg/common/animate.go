@@ -0,0 +1,295 @@
+package common
+
+import (
+	"fmt"
+	"image"
+	"image/gif"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// Animation file formats accepted by WriteSolutionAnimation.
+const (
+	AnimationGIF = "gif"
+	AnimationSVG = "svg"
+)
+
+// AnimationOptions controls how a solution replay is drawn.
+type AnimationOptions struct {
+	CellSize  int           // pixels per grid cell (default DefaultImageCellSize)
+	StepDelay time.Duration // time for a vine to slide one cell (default 60ms)
+	// MaxFramesPerMove caps GIF frames per cleared vine; longer slides skip
+	// cells so big levels stay a reasonable size. SVG animation is continuous
+	// and ignores it. Default 8.
+	MaxFramesPerMove int
+}
+
+func (o AnimationOptions) withDefaults() AnimationOptions {
+	if o.CellSize <= 0 {
+		o.CellSize = DefaultImageCellSize
+	}
+	if o.StepDelay <= 0 {
+		o.StepDelay = 60 * time.Millisecond
+	}
+	if o.MaxFramesPerMove <= 0 {
+		o.MaxFramesPerMove = 8
+	}
+	return o
+}
+
+const (
+	animationHold    = 500 * time.Millisecond  // pause on the starting board
+	animationEndHold = 1000 * time.Millisecond // pause on the cleared board
+)
+
+// WriteSolutionAnimation replays solution (vine IDs in clearing order) on level
+// and writes it as format: an animated GIF or an SVG with SMIL animation. Each
+// vine slides out along its head direction in turn; multi-head vines leave by
+// whichever head is free when their turn comes.
+func WriteSolutionAnimation(w io.Writer, level *model.Level, solution []string, format string, opts AnimationOptions) error {
+	order, err := solutionIndices(level, solution)
+	if err != nil {
+		return err
+	}
+	level = leavingViews(level, order)
+	opts = opts.withDefaults()
+	switch format {
+	case AnimationGIF:
+		return writeSolutionGIF(w, level, order, opts)
+	case AnimationSVG:
+		return writeSolutionSVG(w, level, order, opts)
+	default:
+		return fmt.Errorf("unknown animation format %q (want %s or %s)", format, AnimationGIF, AnimationSVG)
+	}
+}
+
+func solutionIndices(level *model.Level, solution []string) ([]int, error) {
+	index := make(map[string]int, len(level.Vines))
+	for i, v := range level.Vines {
+		index[v.ID] = i
+	}
+	order := make([]int, len(solution))
+	for i, id := range solution {
+		idx, ok := index[id]
+		if !ok {
+			return nil, fmt.Errorf("solution references unknown vine %q", id)
+		}
+		order[i] = idx
+	}
+	return order, nil
+}
+
+// leavingViews returns a copy of level in which every multi-head vine that
+// leaves tail first in order is replaced by its reversed view, so the
+// animation can always slide vines head first.
+func leavingViews(level *model.Level, order []int) *model.Level {
+	occupied := make(map[string]string)
+	for _, v := range level.Vines {
+		for _, p := range v.OrderedPath {
+			occupied[coordKey(p.X, p.Y)] = v.ID
+		}
+	}
+
+	out := *level
+	out.Vines = append([]model.Vine(nil), level.Vines...)
+	for _, i := range order {
+		v := out.Vines[i]
+		if len(v.OrderedPath) == 0 {
+			continue
+		}
+		if v.IsMultiHead() && !IsLevelExitPathClear(level, v.OrderedPath[0], v.HeadDirection, occupied) {
+			rev := v.Reversed()
+			if IsLevelExitPathClear(level, rev.OrderedPath[0], rev.HeadDirection, occupied) {
+				out.Vines[i] = rev
+			}
+		}
+		for _, p := range v.OrderedPath {
+			delete(occupied, coordKey(p.X, p.Y))
+		}
+	}
+	return &out
+}
+
+// exitTrack returns the cells a vine passes through as it leaves the grid:
+// its body from tail to head, then its exit ray (through any portals) extended
+// far enough for the tail to leave. At step k the vine covers
+// track[k : k+len(path)]; after steps steps it is fully off the grid.
+func exitTrack(level *model.Level, v model.Vine) (track []model.Point, steps int) {
+	n := len(v.OrderedPath)
+	for i := n - 1; i >= 0; i-- {
+		track = append(track, v.OrderedPath[i])
+	}
+	if n == 0 {
+		return track, 0
+	}
+	exit, _ := level.ExitRay(v.OrderedPath[0], v.HeadDirection, func(p model.Point) bool {
+		track = append(track, p)
+		return true
+	})
+	dx, dy := DeltaForDirection(v.HeadDirection)
+	for j := 0; j < n; j++ {
+		track = append(track, model.Point{X: exit.X + j*dx, Y: exit.Y + j*dy})
+	}
+	return track, len(track) - n
+}
+
+// trackRuns splits the exit track of v into runs the vine moves along without
+// jumping, as [start, end) index pairs. Past the head each cell is one step
+// further in the head direction, except where the vine comes out of a portal.
+func trackRuns(v model.Vine, track []model.Point) [][2]int {
+	dx, dy := DeltaForDirection(v.HeadDirection)
+	var runs [][2]int
+	start := 0
+	for j := 1; j <= len(track); j++ {
+		if j == len(track) || (j >= len(v.OrderedPath) && track[j] != model.Point{X: track[j-1].X + dx, Y: track[j-1].Y + dy}) {
+			runs = append(runs, [2]int{start, j})
+			start = j
+		}
+	}
+	return runs
+}
+
+func writeSolutionGIF(w io.Writer, level *model.Level, order []int, opts AnimationOptions) error {
+	c := newPixelCanvas(level, opts.CellSize)
+	present := make([]bool, len(level.Vines))
+	for i := range present {
+		present[i] = true
+	}
+	centis := func(d time.Duration) int {
+		if cs := int(d / (10 * time.Millisecond)); cs > 0 {
+			return cs
+		}
+		return 1
+	}
+
+	anim := &gif.GIF{}
+	add := func(img *image.Paletted, d time.Duration) {
+		anim.Image = append(anim.Image, img)
+		anim.Delay = append(anim.Delay, centis(d))
+	}
+
+	add(c.frame(present, -1, 0), animationHold)
+	for _, i := range order {
+		_, steps := exitTrack(level, level.Vines[i])
+		stride := (steps + opts.MaxFramesPerMove - 1) / opts.MaxFramesPerMove
+		if stride < 1 {
+			stride = 1
+		}
+		for step := stride; step < steps; step += stride {
+			add(c.frame(present, i, step), time.Duration(stride)*opts.StepDelay)
+		}
+		present[i] = false
+	}
+	add(c.frame(present, -1, 0), animationEndHold)
+	return gif.EncodeAll(w, anim)
+}
+
+func writeSolutionSVG(w io.Writer, level *model.Level, order []int, opts AnimationOptions) error {
+	height := level.GetGridHeight()
+	cell := float64(opts.CellSize)
+	seconds := func(d time.Duration) string {
+		return fmt.Sprintf("%.3fs", d.Seconds())
+	}
+
+	var b strings.Builder
+	svgOpen(&b, level, opts.CellSize, fmt.Sprintf("Level %d solution (%d moves)", level.ID, len(order)))
+	b.WriteString("  <defs><clipPath id=\"board\"><rect x=\"0\" y=\"0\" width=\"100%\" height=\"100%\"/></clipPath></defs>\n")
+
+	// Each vine is a dash sliding along its exit track: the dash covers the
+	// body at rest and dashoffset moves it out past the grid edge.
+	begin := map[int]time.Duration{}
+	duration := map[int]time.Duration{}
+	at := animationHold
+	for _, i := range order {
+		_, steps := exitTrack(level, level.Vines[i])
+		begin[i] = at
+		duration[i] = time.Duration(steps) * opts.StepDelay
+		at += duration[i]
+	}
+
+	b.WriteString("  <g clip-path=\"url(#board)\">\n")
+	for i, v := range level.Vines {
+		if len(v.OrderedPath) == 0 {
+			continue
+		}
+		track, steps := exitTrack(level, v)
+		n := len(v.OrderedPath)
+		body := float64(n-1) * cell
+		travel := float64(steps) * cell
+		_, _ = fmt.Fprintf(&b, "    <g id=\"%s\">\n", v.ID)
+
+		// A portal splits the track into runs; each run slides its own dash,
+		// offset by where the run starts so the pieces stay in step
+		for _, run := range trackRuns(v, track) {
+			points := make([]string, 0, run[1]-run[0])
+			for _, p := range track[run[0]:run[1]] {
+				x, y := svgCenter(p, height, cell)
+				points = append(points, fmt.Sprintf("%g,%g", x, y))
+			}
+			start := float64(run[0]) * cell
+			_, _ = fmt.Fprintf(&b, "      <polyline points=\"%s\" %s stroke-dasharray=\"%g %g\"",
+				strings.Join(points, " "), svgVineStroke(level, i, cell), body, body+travel+cell)
+			if start != 0 {
+				_, _ = fmt.Fprintf(&b, " stroke-dashoffset=\"%g\"", start)
+			}
+			b.WriteString(">\n")
+			if d, ok := duration[i]; ok {
+				_, _ = fmt.Fprintf(&b, "        <animate attributeName=\"stroke-dashoffset\" from=\"%g\" to=\"%g\" begin=\"%s\" dur=\"%s\" fill=\"freeze\"/>\n",
+					start, start-travel, seconds(begin[i]), seconds(d))
+			}
+			b.WriteString("      </polyline>\n")
+		}
+
+		// The head arrow slides along each run the head passes through, shown
+		// only while the head is on that run
+		dx, dy := DeltaForDirection(v.HeadDirection)
+		runs := trackRuns(v, track)
+		for k, run := range runs {
+			if run[1] <= n-1 {
+				continue // the body's tail side, never reached by the head
+			}
+			from := max(run[0], n-1)
+			last := k == len(runs)-1
+			_, _ = fmt.Fprintf(&b, "      <polygon points=\"%s\" fill=\"%s\"", svgArrow(track[from], v.HeadDirection, height, cell), hexColor(imageHead))
+			if from != n-1 {
+				b.WriteString(" visibility=\"hidden\"")
+			}
+			b.WriteString(">\n")
+			if _, ok := duration[i]; !ok {
+				b.WriteString("      </polygon>\n")
+				continue
+			}
+			at := begin[i] + time.Duration(from-(n-1))*opts.StepDelay
+			if from != n-1 {
+				_, _ = fmt.Fprintf(&b, "        <set attributeName=\"visibility\" to=\"visible\" begin=\"%s\"/>\n", seconds(at))
+			}
+			if moves := run[1] - 1 - from; moves > 0 {
+				dist := float64(moves) * cell
+				dur := time.Duration(moves) * opts.StepDelay
+				_, _ = fmt.Fprintf(&b, "        <animateTransform attributeName=\"transform\" type=\"translate\" from=\"0 0\" to=\"%g %g\" begin=\"%s\" dur=\"%s\" fill=\"freeze\"/>\n",
+					float64(dx)*dist, -float64(dy)*dist, seconds(at), seconds(dur))
+			}
+			if !last {
+				_, _ = fmt.Fprintf(&b, "        <set attributeName=\"visibility\" to=\"hidden\" begin=\"%s\"/>\n",
+					seconds(begin[i]+time.Duration(run[1]-(n-1))*opts.StepDelay))
+			}
+			b.WriteString("      </polygon>\n")
+		}
+		if heads := v.Heads(); len(heads) > 1 {
+			// The trailing head stays put and disappears once the vine starts moving
+			_, _ = fmt.Fprintf(&b, "      <polygon points=\"%s\" fill=\"%s\">\n", svgHeadArrow(heads[1], height, cell), hexColor(imageHead))
+			if _, ok := duration[i]; ok {
+				_, _ = fmt.Fprintf(&b, "        <set attributeName=\"visibility\" to=\"hidden\" begin=\"%s\"/>\n", seconds(begin[i]))
+			}
+			b.WriteString("      </polygon>\n")
+		}
+		b.WriteString("    </g>\n")
+	}
+	b.WriteString("  </g>\n</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,164 @@
+package common
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/color"
+	"image/gif"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func animationLevel() *model.Level {
+	return &model.Level{
+		ID:          7,
+		GridSize:    []int{4, 3},
+		ColorScheme: []string{"#FF0000", "#00FF00"},
+		Vines: []model.Vine{
+			// a is blocked by b until b leaves upward
+			{ID: "a", HeadDirection: DirRight, OrderedPath: []model.Point{{X: 2, Y: 1}, {X: 1, Y: 1}}, ColorIndex: 0},
+			{ID: "b", HeadDirection: DirUp, OrderedPath: []model.Point{{X: 3, Y: 2}, {X: 3, Y: 1}, {X: 3, Y: 0}}, ColorIndex: 1},
+		},
+		Mask: &model.Mask{Mode: model.MaskModeHide, Points: []model.Point{{X: 0, Y: 0}}},
+	}
+}
+
+func TestExitTrack(t *testing.T) {
+	level := animationLevel()
+	track, steps := exitTrack(level, level.Vines[0])
+	// Head at x=2 facing right: 1 cell to the edge plus 2 body cells
+	if steps != 3 {
+		t.Fatalf("steps = %d, want 3", steps)
+	}
+	want := []model.Point{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1}, {X: 4, Y: 1}, {X: 5, Y: 1}}
+	if len(track) != len(want) {
+		t.Fatalf("track = %v, want %v", track, want)
+	}
+	for i := range want {
+		if track[i] != want[i] {
+			t.Fatalf("track = %v, want %v", track, want)
+		}
+	}
+}
+
+func TestWriteSolutionAnimationGIF(t *testing.T) {
+	level := animationLevel()
+	var buf bytes.Buffer
+	opts := AnimationOptions{CellSize: 8, MaxFramesPerMove: 2}
+	if err := WriteSolutionAnimation(&buf, level, []string{"b", "a"}, AnimationGIF, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	// start + (b: 3 steps, stride 2 -> 1 frame) + (a: 3 steps, stride 2 -> 1 frame) + end
+	if len(anim.Image) != 4 {
+		t.Fatalf("frames = %d, want 4", len(anim.Image))
+	}
+	if b := anim.Image[0].Bounds(); b.Dx() != 32 || b.Dy() != 24 {
+		t.Errorf("frame size %v, want 32x24", b)
+	}
+
+	// Pixel centers of cells; y is flipped so row 0 is at the bottom
+	at := func(frame, x, y int) color.Color {
+		return anim.Image[frame].At(x*8+2, (2-y)*8+2)
+	}
+	red := color.RGBA{0xFF, 0, 0, 0xFF}
+	if c := color.RGBAModel.Convert(at(0, 1, 1)); c != red {
+		t.Errorf("vine a body color %v, want %v", c, red)
+	}
+	if c := color.RGBAModel.Convert(at(0, 0, 0)); c != imageMasked {
+		t.Errorf("masked cell color %v, want %v", c, imageMasked)
+	}
+	last := len(anim.Image) - 1
+	if c := color.RGBAModel.Convert(at(last, 1, 1)); c != imageBackground {
+		t.Errorf("cleared board should be empty, got %v", c)
+	}
+}
+
+func TestWriteSolutionAnimationSVG(t *testing.T) {
+	level := animationLevel()
+	var buf bytes.Buffer
+	if err := WriteSolutionAnimation(&buf, level, []string{"b", "a"}, AnimationSVG, AnimationOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	dec := xml.NewDecoder(strings.NewReader(out))
+	for {
+		if _, err := dec.Token(); err != nil {
+			if err != io.EOF {
+				t.Fatalf("invalid SVG: %v\n%s", err, out)
+			}
+			break
+		}
+	}
+
+	if n := strings.Count(out, "attributeName=\"stroke-dashoffset\""); n != 2 {
+		t.Errorf("expected 2 vine animations, got %d", n)
+	}
+	// b clears first after the hold: 3 steps of 60ms
+	if !strings.Contains(out, `begin="0.500s" dur="0.180s"`) || !strings.Contains(out, `begin="0.680s" dur="0.180s"`) {
+		t.Errorf("unexpected timing:\n%s", out)
+	}
+}
+
+func TestWriteSolutionAnimationErrors(t *testing.T) {
+	level := animationLevel()
+	var buf bytes.Buffer
+	if err := WriteSolutionAnimation(&buf, level, []string{"missing"}, AnimationGIF, AnimationOptions{}); err == nil {
+		t.Error("expected error for unknown vine")
+	}
+	if err := WriteSolutionAnimation(&buf, level, []string{"b", "a"}, "mp4", AnimationOptions{}); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestExitTrackThroughPortal(t *testing.T) {
+	// a faces right into the portal at (2,1) and comes out of (0,2)
+	level := &model.Level{
+		ID:       8,
+		GridSize: []int{5, 3},
+		Vines: []model.Vine{
+			{ID: "a", HeadDirection: DirRight, OrderedPath: []model.Point{{X: 1, Y: 1}, {X: 0, Y: 1}}},
+		},
+		Portals: []model.Portal{{A: model.Point{X: 2, Y: 1}, B: model.Point{X: 0, Y: 2}}},
+	}
+	track, steps := exitTrack(level, level.Vines[0])
+	want := []model.Point{
+		{X: 0, Y: 1}, {X: 1, Y: 1}, // body, tail first
+		{X: 1, Y: 2}, {X: 2, Y: 2}, {X: 3, Y: 2}, {X: 4, Y: 2}, // past the twin
+		{X: 5, Y: 2}, {X: 6, Y: 2}, // off the grid
+	}
+	if steps != 6 || len(track) != len(want) {
+		t.Fatalf("track = %v (%d steps), want %v (6 steps)", track, steps, want)
+	}
+	for i := range want {
+		if track[i] != want[i] {
+			t.Fatalf("track = %v, want %v", track, want)
+		}
+	}
+	if runs := trackRuns(level.Vines[0], track); len(runs) != 2 || runs[0] != [2]int{0, 2} || runs[1] != [2]int{2, 8} {
+		t.Errorf("runs = %v, want [[0 2] [2 8]]", runs)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSolutionAnimation(&buf, level, []string{"a"}, AnimationSVG, AnimationOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if n := strings.Count(out, "<polyline"); n != 2 {
+		t.Errorf("expected the track split into 2 polylines, got %d", n)
+	}
+	if n := strings.Count(out, "<circle"); n != 2 {
+		t.Errorf("expected 2 portal rings, got %d", n)
+	}
+	if _, err := xml.NewDecoder(strings.NewReader(out)).Token(); err != nil {
+		t.Errorf("invalid SVG: %v", err)
+	}
+}
@@ -4,21 +4,48 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 )
 
+// BackupDirName is the directory, inside a levels directory, that holds its
+// backups. Each backup is a backup_<timestamp> directory of level files.
+const BackupDirName = ".backups"
+
+const backupPrefix = "backup_"
+
+// backupTimeFormat names backup directories so they sort chronologically.
+const backupTimeFormat = "20060102_150405"
+
+// BackupOnOverwrite makes WriteLevel copy a level file it is about to replace
+// into a backup next to it (see BackupDirName). All levels overwritten by one
+// run share one backup, so the run can be rolled back with `restore`.
+var BackupOnOverwrite bool
+
+var (
+	runBackupsMu sync.Mutex
+	runBackups   = map[string]string{} // backups root -> this run's backup dir
+)
+
+// Backup is one timestamped backup of level files.
+type Backup struct {
+	Name  string    // Directory name, e.g. backup_20260101_120000
+	Path  string    // Absolute path of the directory
+	Time  time.Time // When the backup was taken
+	Files []string  // Level file names, sorted
+}
+
 // BackupLevels creates a timestamped backup of the specified level files
 func BackupLevels(levelIDs []int, sourceDir, backupBaseDir string) (string, error) {
 	if len(levelIDs) == 0 {
 		return "", fmt.Errorf("no level IDs provided for backup")
 	}
 
-	// Create timestamped backup directory
-	timestamp := time.Now().Format("20060102_150405")
-	backupDir := filepath.Join(backupBaseDir, fmt.Sprintf("backup_%s", timestamp))
-
-	if err := os.MkdirAll(backupDir, 0o755); err != nil {
-		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	backupDir, err := newBackupDir(backupBaseDir)
+	if err != nil {
+		return "", err
 	}
 
 	// Copy each level file
@@ -31,14 +58,8 @@ func BackupLevels(levelIDs []int, sourceDir, backupBaseDir string) (string, erro
 			continue
 		}
 
-		// Copy the file
-		data, err := os.ReadFile(srcFile)
-		if err != nil {
-			return "", fmt.Errorf("failed to read %s: %w", srcFile, err)
-		}
-
-		if err := os.WriteFile(dstFile, data, 0o644); err != nil {
-			return "", fmt.Errorf("failed to write backup %s: %w", dstFile, err)
+		if err := copyFileAtomic(srcFile, dstFile); err != nil {
+			return "", err
 		}
 
 		Verbose("Backed up: %s -> %s", srcFile, dstFile)
@@ -47,3 +68,161 @@ func BackupLevels(levelIDs []int, sourceDir, backupBaseDir string) (string, erro
 	Info("Backup created at: %s", backupDir)
 	return backupDir, nil
 }
+
+// BackupsDir returns the directory backups of levelsDir are kept in.
+func BackupsDir(levelsDir string) string {
+	return filepath.Join(levelsDir, BackupDirName)
+}
+
+// BackupBeforeOverwrite copies filePath into this run's backup under its
+// directory's BackupsDir, when BackupOnOverwrite is set and the file exists.
+// Writers call it just before replacing the file.
+func BackupBeforeOverwrite(filePath string) error {
+	if !BackupOnOverwrite || !FileExists(filePath) {
+		return nil
+	}
+	root := BackupsDir(filepath.Dir(filePath))
+
+	runBackupsMu.Lock()
+	dir, ok := runBackups[root]
+	if !ok {
+		var err error
+		if dir, err = newBackupDir(root); err != nil {
+			runBackupsMu.Unlock()
+			return err
+		}
+		runBackups[root] = dir
+		Info("Backing up overwritten levels to: %s", dir)
+	}
+	runBackupsMu.Unlock()
+
+	dst := filepath.Join(dir, filepath.Base(filePath))
+	// The first copy is the state before this run; keep it
+	if FileExists(dst) {
+		return nil
+	}
+	if err := copyFileAtomic(filePath, dst); err != nil {
+		return err
+	}
+	Verbose("Backed up: %s -> %s", filePath, dst)
+	return nil
+}
+
+// newBackupDir creates a new timestamped backup directory under root. A
+// second backup in the same second gets a numeric suffix.
+func newBackupDir(root string) (string, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	name := backupPrefix + time.Now().Format(backupTimeFormat)
+	dir := filepath.Join(root, name)
+	for n := 2; ; n++ {
+		err := os.Mkdir(dir, 0o755)
+		if err == nil {
+			return dir, nil
+		}
+		if !os.IsExist(err) {
+			return "", fmt.Errorf("failed to create backup directory: %w", err)
+		}
+		dir = filepath.Join(root, fmt.Sprintf("%s_%d", name, n))
+	}
+}
+
+// ListBackups returns the backups of levelsDir, newest first. A levels
+// directory that was never backed up has none.
+func ListBackups(levelsDir string) ([]Backup, error) {
+	root := BackupsDir(levelsDir)
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backups in %s: %w", root, err)
+	}
+
+	var backups []Backup
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), backupPrefix) {
+			continue
+		}
+		stamp := strings.TrimPrefix(entry.Name(), backupPrefix)
+		ts, err := time.ParseInLocation(backupTimeFormat, stamp[:min(len(stamp), len(backupTimeFormat))], time.Local)
+		if err != nil {
+			continue
+		}
+		b := Backup{Name: entry.Name(), Path: filepath.Join(root, entry.Name()), Time: ts}
+		files, err := os.ReadDir(b.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read backup %s: %w", b.Name, err)
+		}
+		for _, f := range files {
+			if !f.IsDir() && isLevelFile(f.Name()) {
+				b.Files = append(b.Files, f.Name())
+			}
+		}
+		sort.Strings(b.Files)
+		backups = append(backups, b)
+	}
+	// Suffixed names sort after their base, which keeps same-second backups in order
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].Time.Equal(backups[j].Time) {
+			return backups[i].Time.After(backups[j].Time)
+		}
+		return len(backups[i].Name) > len(backups[j].Name) ||
+			(len(backups[i].Name) == len(backups[j].Name) && backups[i].Name > backups[j].Name)
+	})
+	return backups, nil
+}
+
+// RestoreBackup copies level files from backup b back into levelsDir, all of
+// them or only the named files. The levels being replaced are backed up first,
+// so a restore can itself be rolled back. It returns the restored file names.
+func RestoreBackup(levelsDir string, b Backup, files []string) ([]string, error) {
+	if len(files) == 0 {
+		files = b.Files
+	}
+	for _, name := range files {
+		if !FileExists(filepath.Join(b.Path, name)) {
+			return nil, fmt.Errorf("backup %s has no %s", b.Name, name)
+		}
+	}
+
+	var current []string
+	for _, name := range files {
+		if FileExists(filepath.Join(levelsDir, name)) {
+			current = append(current, name)
+		}
+	}
+	if len(current) > 0 {
+		undo, err := newBackupDir(BackupsDir(levelsDir))
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range current {
+			if err := copyFileAtomic(filepath.Join(levelsDir, name), filepath.Join(undo, name)); err != nil {
+				return nil, err
+			}
+		}
+		Info("Backed up current levels to: %s", undo)
+	}
+
+	for i, name := range files {
+		if err := copyFileAtomic(filepath.Join(b.Path, name), filepath.Join(levelsDir, name)); err != nil {
+			return files[:i], err
+		}
+		Verbose("Restored: %s", name)
+	}
+	return files, nil
+}
+
+// copyFileAtomic copies src to dst through WriteFileAtomic.
+func copyFileAtomic(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	if err := WriteFileAtomic(dst, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+	return nil
+}
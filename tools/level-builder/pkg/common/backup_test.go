@@ -0,0 +1,63 @@
+package common
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func TestBackupOnOverwriteAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "level_3.json")
+	write := func(name string) {
+		t.Helper()
+		level := model.Level{ID: 3, Name: name, GridSize: []int{2, 2}}
+		if err := WriteLevel(path, &level, true); err != nil {
+			t.Fatalf("WriteLevel: %v", err)
+		}
+	}
+	name := func() string {
+		t.Helper()
+		l, err := ReadLevel(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return l.Name
+	}
+
+	write("original")
+	BackupOnOverwrite = true
+	t.Cleanup(func() { BackupOnOverwrite = false })
+	write("second")
+	write("third") // the run's backup keeps the state before the run
+
+	backups, err := ListBackups(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 || len(backups[0].Files) != 1 || backups[0].Files[0] != "level_3.json" {
+		t.Fatalf("backups = %+v, want one holding level_3.json", backups)
+	}
+	if _, err := RestoreBackup(dir, backups[0], nil); err != nil {
+		t.Fatalf("RestoreBackup: %v", err)
+	}
+	if got := name(); got != "original" {
+		t.Errorf("restored name = %q, want original", got)
+	}
+
+	// The restore backed up what it replaced; restoring the newest undoes it
+	backups, err = ListBackups(dir)
+	if err != nil || len(backups) != 2 {
+		t.Fatalf("ListBackups = %d, %v; want 2", len(backups), err)
+	}
+	if _, err := RestoreBackup(dir, backups[0], []string{"level_3.json"}); err != nil {
+		t.Fatalf("RestoreBackup: %v", err)
+	}
+	if got := name(); got != "third" {
+		t.Errorf("name after undo = %q, want third", got)
+	}
+	if _, err := RestoreBackup(dir, backups[0], []string{"level_9.json"}); err == nil {
+		t.Error("expected an error for a level the backup does not hold")
+	}
+}
@@ -0,0 +1,77 @@
+package common
+
+import "testing"
+
+type canonicalSample struct {
+	Name   string             `json:"name"`
+	Scores map[string]float64 `json:"scores"`
+	Ratio  float64            `json:"ratio"`
+	Note   string             `json:"note,omitempty"`
+}
+
+func TestMarshalCanonical(t *testing.T) {
+	sample := canonicalSample{
+		Name:   "Roots & <Shoots>",
+		Scores: map[string]float64{"b": 1e21, "a": 0.1},
+		Ratio:  0.35,
+	}
+	tests := []struct {
+		name     string
+		indent   string
+		override string
+		sortKeys bool
+		want     string
+	}{
+		{
+			name:   "schema order",
+			indent: LevelIndent,
+			want: `{
+  "name": "Roots & <Shoots>",
+  "scores": {
+    "a": 0.1,
+    "b": 1e+21
+  },
+  "ratio": 0.35
+}
+`,
+		},
+		{
+			name:     "sorted keys and indent override",
+			indent:   LessonIndent,
+			override: "\t",
+			sortKeys: true,
+			want:     "{\n\t\"name\": \"Roots & <Shoots>\",\n\t\"ratio\": 0.35,\n\t\"scores\": {\n\t\t\"a\": 0.1,\n\t\t\"b\": 1e+21\n\t}\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			JSONIndent, JSONSortKeys = tt.override, tt.sortKeys
+			defer func() { JSONIndent, JSONSortKeys = "", false }()
+
+			got, err := MarshalCanonical(sample, tt.indent)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+			again, err := MarshalCanonical(sample, tt.indent)
+			if err != nil || string(again) != string(got) {
+				t.Errorf("second marshal differs: %q, %v", again, err)
+			}
+		})
+	}
+}
+
+func TestParseJSONIndent(t *testing.T) {
+	for in, want := range map[string]string{"": "", "tab": "\t", "4": "    ", " 2 ": "  "} {
+		if got, err := ParseJSONIndent(in); err != nil || got != want {
+			t.Errorf("ParseJSONIndent(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"0", "9", "two"} {
+		if _, err := ParseJSONIndent(in); err == nil {
+			t.Errorf("ParseJSONIndent(%q) succeeded", in)
+		}
+	}
+}
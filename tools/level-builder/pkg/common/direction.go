@@ -128,6 +128,16 @@ func IsExitPathClear(pos model.Point, dir string, gridWidth, gridHeight int, occ
 	return true // Reached edge without collision
 }
 
+// IsLevelExitPathClear is IsExitPathClear on level's grid: the path continues
+// from the twin of any portal it enters.
+func IsLevelExitPathClear(level *model.Level, pos model.Point, dir string, occupied map[string]string) bool {
+	_, ok := level.ExitRay(pos, dir, func(p model.Point) bool {
+		_, blocked := occupied[coordKey(p.X, p.Y)]
+		return !blocked
+	})
+	return ok
+}
+
 // coordKey returns a map key for coordinates (internal helper)
 func coordKey(x, y int) string {
 	return fmt.Sprintf("%d,%d", x, y)
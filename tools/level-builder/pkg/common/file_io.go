@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,9 +12,55 @@ import (
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
 )
 
-// ReadLevel reads a single level from a JSON file.
+// StdioPath is the file name that stands for stdin when reading a level and
+// stdout when writing one, so commands compose in shell pipelines.
+const StdioPath = "-"
+
+// Stdin and Stdout back StdioPath; tests replace them.
+var (
+	Stdin  io.Reader = os.Stdin
+	Stdout io.Writer = os.Stdout
+)
+
+// ReadInput returns the contents of filePath, or all of Stdin for StdioPath.
+func ReadInput(filePath string) ([]byte, error) {
+	if filePath == StdioPath {
+		return io.ReadAll(Stdin)
+	}
+	return os.ReadFile(filePath)
+}
+
+// ReadLevel reads a single level from a JSON file, or from stdin when
+// filePath is StdioPath.
 func ReadLevel(filePath string) (*model.Level, error) {
-	data, err := os.ReadFile(filePath)
+	level, err := ReadLevelRaw(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Normalize vines through NewVine so malformed paths or mismatched head
+	// directions are rejected at load time (and omitted directions derived).
+	// Tail heads of multi-head vines are checked the same way.
+	for i, v := range level.Vines {
+		nv, err := model.NewVine(v.ID, v.OrderedPath, v.HeadDirection)
+		if err == nil && v.IsMultiHead() {
+			nv, err = nv.WithTailHead(v.TailDirection)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid vine in level file %s: %w", filePath, err)
+		}
+		nv.ColorIndex = v.ColorIndex
+		nv.LockedUntil = v.LockedUntil
+		level.Vines[i] = nv
+	}
+
+	return level, nil
+}
+
+// ReadLevelRaw reads a level from a JSON file without checking its vines, so
+// tools that repair broken paths and head directions can load them.
+func ReadLevelRaw(filePath string) (*model.Level, error) {
+	data, err := ReadInput(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read level file %s: %w", filePath, err)
 	}
@@ -22,71 +69,106 @@ func ReadLevel(filePath string) (*model.Level, error) {
 	decoder := json.NewDecoder(bytes.NewReader(data))
 	decoder.DisallowUnknownFields()
 
-	err = decoder.Decode(&level)
-	if err != nil {
+	if err := decoder.Decode(&level); err != nil {
 		return nil, fmt.Errorf("failed to parse level file %s: %w", filePath, err)
 	}
-
 	return &level, nil
 }
 
-// WriteLevel writes a level to a JSON file with the new color_scheme format.
+// WriteLevel writes a level to a JSON file with the new color_scheme format,
+// or to stdout when filePath is StdioPath.
 // Returns error if file exists and overwrite is false.
 func WriteLevel(filePath string, level *model.Level, overwrite bool) error {
-	// Check if file exists
-	_, err := os.Stat(filePath)
-	fileExists := err == nil
+	fileExists := false
+	if filePath != StdioPath {
+		// Check if file exists
+		_, err := os.Stat(filePath)
+		fileExists = err == nil
 
-	if fileExists && !overwrite {
-		return fmt.Errorf("file already exists: %s (use --overwrite to replace)", filePath)
-	}
+		if fileExists && !overwrite {
+			return fmt.Errorf("file already exists: %s (use --overwrite to replace)", filePath)
+		}
 
-	// Create directory if needed
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		// Create directory if needed
+		dir := filepath.Dir(filePath)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
 	}
 
 	// Prepare a sanitized level for persistence (exclude runtime-only fields)
 	// NOTE: Uses ColorScheme []string instead of global color map
 	type persistLevel struct {
-		ID                  int          `json:"id"`
-		Name                string       `json:"name,omitempty"`
-		Difficulty          string       `json:"difficulty,omitempty"`
-		GridSize            []int        `json:"grid_size"` // Changed from [2]int to []int for compatibility
-		Mask                *model.Mask  `json:"mask,omitempty"`
-		Vines               []model.Vine `json:"vines"`
-		MaxMoves            int          `json:"max_moves"`
-		MinMoves            int          `json:"min_moves,omitempty"`
-		Complexity          string       `json:"complexity,omitempty"`
-		Grace               int          `json:"grace"`
-		ColorScheme         []string     `json:"color_scheme"`
-		GenerationSeed      int64        `json:"generation_seed,omitempty"`
-		GenerationAttempts  int          `json:"generation_attempts,omitempty"`
-		GenerationElapsedMS int64        `json:"generation_elapsed_ms,omitempty"`
-		GenerationScore     float64      `json:"generation_score,omitempty"`
+		ID                    int            `json:"id"`
+		Name                  string         `json:"name,omitempty"`
+		NameRef               *model.NameRef `json:"name_ref,omitempty"`
+		Difficulty            string         `json:"difficulty,omitempty"`
+		GridSize              []int          `json:"grid_size"` // Changed from [2]int to []int for compatibility
+		Mask                  *model.Mask    `json:"mask,omitempty"`
+		Portals               []model.Portal `json:"portals,omitempty"`
+		Vines                 []model.Vine   `json:"vines"`
+		MaxMoves              int            `json:"max_moves"`
+		MinMoves              int            `json:"min_moves,omitempty"`
+		Hints                 []string       `json:"hints,omitempty"`
+		Complexity            string         `json:"complexity,omitempty"`
+		Grace                 int            `json:"grace"`
+		ColorScheme           []string       `json:"color_scheme"`
+		GenerationSeed        int64          `json:"generation_seed,omitempty"`
+		GenerationAttempts    int            `json:"generation_attempts,omitempty"`
+		GenerationElapsedMS   int64          `json:"generation_elapsed_ms,omitempty"`
+		GenerationScore       float64        `json:"generation_score,omitempty"`
+		GenerationProfile     string         `json:"generation_profile,omitempty"`
+		GenerationRNG         string         `json:"generation_rng,omitempty"`
+		GenerationStrategy    string         `json:"generation_strategy,omitempty"`
+		GenerationRelaxations int            `json:"generation_relaxations,omitempty"`
+		GenerationBacktracks  int            `json:"generation_backtracks,omitempty"`
+		MirrorOf              int            `json:"mirror_of,omitempty"`
+		MirrorAxis            string         `json:"mirror_axis,omitempty"`
+		VariantOf             int            `json:"variant_of,omitempty"`
+		VariantMutations      []string       `json:"variant_mutations,omitempty"`
+		// Generated levels explain their grace
+		GraceBasis *model.GraceBasis `json:"grace_basis,omitempty"`
+		// A clear order known by construction, replayed instead of solved
+		SolutionOrder []string `json:"solution_order,omitempty"`
+		// Board theming hooks for the game
+		Theme *model.LevelTheme `json:"theme,omitempty"`
 	}
 
 	pLevel := persistLevel{
-		ID:                  level.ID,
-		Name:                level.Name,
-		Difficulty:          level.Difficulty,
-		GridSize:            level.GridSize,
-		Mask:                level.Mask,
-		Vines:               level.Vines,
-		MaxMoves:            level.MaxMoves,
-		MinMoves:            level.MinMoves,
-		Complexity:          level.Complexity,
-		Grace:               level.Grace,
-		ColorScheme:         level.ColorScheme,
-		GenerationSeed:      level.GenerationSeed,
-		GenerationAttempts:  level.GenerationAttempts,
-		GenerationElapsedMS: level.GenerationElapsedMS,
-		GenerationScore:     level.GenerationScore,
+		ID:                    level.ID,
+		Name:                  level.Name,
+		NameRef:               level.NameRef,
+		Difficulty:            level.Difficulty,
+		GridSize:              level.GridSize,
+		Mask:                  level.Mask,
+		Portals:               level.Portals,
+		Vines:                 level.Vines,
+		MaxMoves:              level.MaxMoves,
+		MinMoves:              level.MinMoves,
+		Hints:                 level.Hints,
+		Complexity:            level.Complexity,
+		Grace:                 level.Grace,
+		ColorScheme:           level.ColorScheme,
+		GenerationSeed:        level.GenerationSeed,
+		GenerationAttempts:    level.GenerationAttempts,
+		GenerationElapsedMS:   level.GenerationElapsedMS,
+		GenerationScore:       level.GenerationScore,
+		GenerationProfile:     level.GenerationProfile,
+		GenerationRNG:         level.GenerationRNG,
+		GenerationStrategy:    level.GenerationStrategy,
+		GenerationRelaxations: level.GenerationRelaxations,
+		GenerationBacktracks:  level.GenerationBacktracks,
+		MirrorOf:              level.MirrorOf,
+		MirrorAxis:            level.MirrorAxis,
+		VariantOf:             level.VariantOf,
+		VariantMutations:      level.VariantMutations,
+		GraceBasis:            level.GraceBasis,
+		SolutionOrder:         level.SolutionOrder,
+		Theme:                 level.Theme,
 	}
 
 	// Marshal sanitized level
-	data, err := json.MarshalIndent(pLevel, "", "  ")
+	data, err := MarshalCanonical(pLevel, LevelIndent)
 	if err != nil {
 		return fmt.Errorf("failed to marshal level to JSON: %w", err)
 	}
@@ -97,8 +179,19 @@ func WriteLevel(filePath string, level *model.Level, overwrite bool) error {
 		return fmt.Errorf("sanity check failed: marshaled JSON invalid: %w", err)
 	}
 
+	if filePath == StdioPath {
+		_, err := Stdout.Write(data)
+		return err
+	}
+
+	if fileExists {
+		if err := BackupBeforeOverwrite(filePath); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", filePath, err)
+		}
+	}
+
 	// Write atomically
-	if err := atomicWriteFile(filePath, data, 0o644); err != nil {
+	if err := WriteFileAtomic(filePath, data, 0o644); err != nil {
 		return fmt.Errorf("failed to write level file %s: %w", filePath, err)
 	}
 
@@ -161,10 +254,11 @@ func GetLevelFilePath(levelID int, baseDir string) string {
 	return filepath.Join(baseDir, fmt.Sprintf("level_%d.json", levelID))
 }
 
-// atomicWriteFile writes data to a temporary file and renames it into place to ensure atomicity.
-func atomicWriteFile(filePath string, data []byte, perm os.FileMode) error {
+// WriteFileAtomic writes data to a temporary file and renames it into place, so
+// a crash mid-write never leaves a truncated file behind.
+func WriteFileAtomic(filePath string, data []byte, perm os.FileMode) error {
 	dir := filepath.Dir(filePath)
-	tmpFile, err := os.CreateTemp(dir, "tmplevel-*.json")
+	tmpFile, err := os.CreateTemp(dir, ".tmp-*")
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,95 @@
+package common
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func TestWriteLevelRoundTrip(t *testing.T) {
+	level := model.Level{
+		ID:       7,
+		GridSize: []int{4, 4},
+		Portals:  []model.Portal{{A: model.Point{X: 0, Y: 3}, B: model.Point{X: 3, Y: 3}}},
+		Vines: []model.Vine{
+			{ID: "v1", HeadDirection: "right", OrderedPath: []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}},
+			{ID: "v2", HeadDirection: "up", OrderedPath: []model.Point{{X: 1, Y: 2}, {X: 1, Y: 1}}, LockedUntil: 1},
+		},
+		MaxMoves:    4,
+		Grace:       3,
+		ColorScheme: []string{"#000000"},
+	}
+
+	path := filepath.Join(t.TempDir(), "level_7.json")
+	if err := WriteLevel(path, &level, false); err != nil {
+		t.Fatalf("WriteLevel: %v", err)
+	}
+	got, err := ReadLevel(path)
+	if err != nil {
+		t.Fatalf("ReadLevel: %v", err)
+	}
+	if !reflect.DeepEqual(got.Portals, level.Portals) {
+		t.Errorf("portals = %v, want %v", got.Portals, level.Portals)
+	}
+	if got.Vines[1].LockedUntil != 1 {
+		t.Errorf("locked_until = %d, want 1", got.Vines[1].LockedUntil)
+	}
+}
+
+func TestReadLevelRawKeepsBrokenVines(t *testing.T) {
+	level := model.Level{
+		ID:       8,
+		GridSize: []int{4, 4},
+		Vines: []model.Vine{
+			{ID: "v1", HeadDirection: "up", OrderedPath: []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}},
+		},
+	}
+	path := filepath.Join(t.TempDir(), "level_8.json")
+	if err := WriteLevel(path, &level, false); err != nil {
+		t.Fatalf("WriteLevel: %v", err)
+	}
+
+	if _, err := ReadLevel(path); err == nil {
+		t.Error("ReadLevel accepted a head_direction that does not match the path")
+	}
+	got, err := ReadLevelRaw(path)
+	if err != nil {
+		t.Fatalf("ReadLevelRaw: %v", err)
+	}
+	if got.Vines[0].HeadDirection != "up" {
+		t.Errorf("head_direction = %q, want it kept as written", got.Vines[0].HeadDirection)
+	}
+}
+
+func TestLevelStdio(t *testing.T) {
+	oldIn, oldOut := Stdin, Stdout
+	t.Cleanup(func() { Stdin, Stdout = oldIn, oldOut })
+
+	level := model.Level{
+		ID:       9,
+		GridSize: []int{3, 3},
+		Vines: []model.Vine{
+			{ID: "v1", HeadDirection: "right", OrderedPath: []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}},
+		},
+		MaxMoves:    3,
+		Grace:       3,
+		ColorScheme: []string{"#000000"},
+	}
+	var piped bytes.Buffer
+	Stdout = &piped
+	if err := WriteLevel(StdioPath, &level, false); err != nil {
+		t.Fatalf("WriteLevel to stdout: %v", err)
+	}
+
+	Stdin = &piped
+	got, err := ReadLevel(StdioPath)
+	if err != nil {
+		t.Fatalf("ReadLevel from stdin: %v", err)
+	}
+	if got.ID != 9 || !reflect.DeepEqual(got.Vines[0].OrderedPath, level.Vines[0].OrderedPath) {
+		t.Errorf("piped level = %+v, want %+v", got, level)
+	}
+}
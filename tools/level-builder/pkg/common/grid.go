@@ -0,0 +1,295 @@
+package common
+
+import (
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// Grid is a dense occupancy set for a w×h board. Cells are addressed by the
+// index y*w+x (the same layout the solver uses), so hot loops can test and
+// mark cells without building "x,y" string keys.
+type Grid struct {
+	w, h  int
+	cells []uint64
+
+	// Scratch space reused by ReachableFromEdge
+	seen  []uint64
+	queue []int
+
+	// Cut cells cached by IsEdgeCut until the next Set or Unset
+	cuts      []uint64
+	cutsValid bool
+	disc, low []int32
+	stack     []cutFrame
+}
+
+// cutFrame is a depth-first search frame of IsEdgeCut's cut-cell search.
+type cutFrame struct {
+	cell, next int
+}
+
+// gridNeighborDeltas is the neighbor order shared by the placers: up, down, right, left.
+var gridNeighborDeltas = [4][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}}
+
+// NewGrid returns an empty grid of the given size.
+func NewGrid(w, h int) *Grid {
+	return &Grid{w: w, h: h, cells: make([]uint64, (w*h+63)/64)}
+}
+
+// GridFromOccupancy builds a grid from an occupancy map keyed by "x,y".
+// Keys that do not parse or fall outside the grid are ignored.
+func GridFromOccupancy(w, h int, occupied map[string]string) *Grid {
+	g := NewGrid(w, h)
+	for key := range occupied {
+		if x, y, ok := parseCoordKey(key); ok {
+			g.Set(x, y)
+		}
+	}
+	return g
+}
+
+// Width returns the number of columns.
+func (g *Grid) Width() int { return g.w }
+
+// Height returns the number of rows.
+func (g *Grid) Height() int { return g.h }
+
+// Index returns the cell index for (x, y).
+func (g *Grid) Index(x, y int) int { return y*g.w + x }
+
+// InBounds reports whether (x, y) lies on the grid.
+func (g *Grid) InBounds(x, y int) bool {
+	return x >= 0 && x < g.w && y >= 0 && y < g.h
+}
+
+// Has reports whether (x, y) is occupied. Cells off the grid are never occupied.
+func (g *Grid) Has(x, y int) bool {
+	if !g.InBounds(x, y) {
+		return false
+	}
+	i := g.Index(x, y)
+	return g.cells[i>>6]&(1<<(uint(i)&63)) != 0
+}
+
+// IsFree reports whether (x, y) is on the grid and unoccupied.
+func (g *Grid) IsFree(x, y int) bool {
+	return g.InBounds(x, y) && !g.Has(x, y)
+}
+
+// Set marks (x, y) occupied. Cells off the grid are ignored.
+func (g *Grid) Set(x, y int) {
+	if g.InBounds(x, y) {
+		i := g.Index(x, y)
+		g.cells[i>>6] |= 1 << (uint(i) & 63)
+		g.cutsValid = false
+	}
+}
+
+// Unset marks (x, y) free. Cells off the grid are ignored.
+func (g *Grid) Unset(x, y int) {
+	if g.InBounds(x, y) {
+		i := g.Index(x, y)
+		g.cells[i>>6] &^= 1 << (uint(i) & 63)
+		g.cutsValid = false
+	}
+}
+
+// SetPath marks every point in path occupied.
+func (g *Grid) SetPath(path []model.Point) {
+	for _, p := range path {
+		g.Set(p.X, p.Y)
+	}
+}
+
+// Count returns the number of occupied cells.
+func (g *Grid) Count() int {
+	n := 0
+	for _, word := range g.cells {
+		n += bits.OnesCount64(word)
+	}
+	return n
+}
+
+// Clone returns an independent copy of the grid.
+func (g *Grid) Clone() *Grid {
+	return &Grid{w: g.w, h: g.h, cells: append([]uint64(nil), g.cells...)}
+}
+
+// FreeNeighbors appends the free orthogonal neighbors of pos to dst in
+// up, down, right, left order and returns the extended slice.
+func (g *Grid) FreeNeighbors(pos model.Point, dst []model.Point) []model.Point {
+	for _, d := range gridNeighborDeltas {
+		if nx, ny := pos.X+d[0], pos.Y+d[1]; g.IsFree(nx, ny) {
+			dst = append(dst, model.Point{X: nx, Y: ny})
+		}
+	}
+	return dst
+}
+
+// CountFreeNeighbors returns how many orthogonal neighbors of (x, y) are free.
+func (g *Grid) CountFreeNeighbors(x, y int) int {
+	n := 0
+	for _, d := range gridNeighborDeltas {
+		if g.IsFree(x+d[0], y+d[1]) {
+			n++
+		}
+	}
+	return n
+}
+
+// IsExitPathClear reports whether every cell from pos (exclusive) to the
+// grid edge in direction dir is free. It mirrors the map-based IsExitPathClear.
+func (g *Grid) IsExitPathClear(pos model.Point, dir string) bool {
+	dx, dy := DeltaForDirection(dir)
+	for x, y := pos.X+dx, pos.Y+dy; g.InBounds(x, y); x, y = x+dx, y+dy {
+		if g.Has(x, y) {
+			return false
+		}
+	}
+	return true
+}
+
+// ReachableFromEdge counts the free cells connected to a free edge cell.
+// Free cells it does not count are enclosed pockets.
+func (g *Grid) ReachableFromEdge() int {
+	if len(g.seen) != len(g.cells) {
+		g.seen = make([]uint64, len(g.cells))
+	} else {
+		clear(g.seen)
+	}
+	queue := g.queue[:0]
+
+	visit := func(x, y int) {
+		i := g.Index(x, y)
+		if g.cells[i>>6]&(1<<(uint(i)&63)) != 0 || g.seen[i>>6]&(1<<(uint(i)&63)) != 0 {
+			return
+		}
+		g.seen[i>>6] |= 1 << (uint(i) & 63)
+		queue = append(queue, i)
+	}
+
+	for x := 0; x < g.w; x++ {
+		visit(x, 0)
+		visit(x, g.h-1)
+	}
+	for y := 1; y < g.h-1; y++ {
+		visit(0, y)
+		visit(g.w-1, y)
+	}
+
+	for head := 0; head < len(queue); head++ {
+		x, y := queue[head]%g.w, queue[head]/g.w
+		for _, d := range gridNeighborDeltas {
+			if nx, ny := x+d[0], y+d[1]; g.InBounds(nx, ny) {
+				visit(nx, ny)
+			}
+		}
+	}
+
+	g.queue = queue
+	return len(queue)
+}
+
+// IsEdgeCut reports whether occupying the free cell (x, y) would cut other
+// free cells off from the edge, i.e. whether ReachableFromEdge would drop by
+// more than the cell itself. Enclosed and occupied cells are never cuts.
+//
+// The cut cells are found together in one pass and cached until the grid
+// next changes, so checking every candidate of a growth step costs one
+// search instead of a ReachableFromEdge per candidate.
+func (g *Grid) IsEdgeCut(x, y int) bool {
+	if !g.InBounds(x, y) {
+		return false
+	}
+	if !g.cutsValid {
+		g.findEdgeCuts()
+	}
+	i := g.Index(x, y)
+	return g.cuts[i>>6]&(1<<(uint(i)&63)) != 0
+}
+
+// findEdgeCuts marks the articulation points of the free cells joined to a
+// virtual node outside the grid that every free edge cell touches (Tarjan's
+// low-link search, iterative so large grids cannot overflow the stack).
+func (g *Grid) findEdgeCuts() {
+	w, h := g.w, g.h
+	if len(g.cuts) != len(g.cells) {
+		g.cuts = make([]uint64, len(g.cells))
+		g.disc = make([]int32, w*h)
+		g.low = make([]int32, w*h)
+	} else {
+		clear(g.cuts)
+		clear(g.disc)
+	}
+	cells, disc, low := g.cells, g.disc, g.low
+	stack := g.stack[:0]
+	var timer int32
+
+	enter := func(i int) {
+		timer++
+		disc[i], low[i] = timer, timer
+		if x, y := i%w, i/w; x == 0 || y == 0 || x == w-1 || y == h-1 {
+			low[i] = 0 // touches the outside node
+		}
+		stack = append(stack, cutFrame{cell: i})
+	}
+
+	for i := range w * h {
+		x, y := i%w, i/w
+		if (x != 0 && y != 0 && x != w-1 && y != h-1) || cells[i>>6]&(1<<(uint(i)&63)) != 0 || disc[i] != 0 {
+			continue
+		}
+		enter(i)
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			v := top.cell
+			if top.next < len(gridNeighborDeltas) {
+				d := gridNeighborDeltas[top.next]
+				top.next++
+				nx, ny := v%w+d[0], v/w+d[1]
+				if nx < 0 || ny < 0 || nx >= w || ny >= h {
+					continue
+				}
+				j := ny*w + nx
+				if cells[j>>6]&(1<<(uint(j)&63)) != 0 {
+					continue
+				}
+				if disc[j] == 0 {
+					enter(j)
+				} else if disc[j] < low[v] {
+					low[v] = disc[j]
+				}
+				continue
+			}
+
+			stack = stack[:len(stack)-1]
+			if len(stack) > 0 {
+				p := stack[len(stack)-1].cell
+				if low[v] < low[p] {
+					low[p] = low[v]
+				}
+				// Nothing below v reaches the edge without going through p
+				if low[v] >= disc[p] {
+					g.cuts[p>>6] |= 1 << (uint(p) & 63)
+				}
+			}
+		}
+	}
+
+	g.stack = stack
+	g.cutsValid = true
+}
+
+// parseCoordKey parses an "x,y" key without going through fmt.
+func parseCoordKey(key string) (x, y int, ok bool) {
+	comma := strings.IndexByte(key, ',')
+	if comma < 0 {
+		return 0, 0, false
+	}
+	x, errX := strconv.Atoi(key[:comma])
+	y, errY := strconv.Atoi(key[comma+1:])
+	return x, y, errX == nil && errY == nil
+}
@@ -0,0 +1,239 @@
+package common
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func TestGridSetUnsetCount(t *testing.T) {
+	g := NewGrid(9, 9) // 81 cells spans two words
+	g.Set(0, 0)
+	g.Set(8, 8)
+	g.Set(8, 8)
+	g.Set(9, 0) // off grid, ignored
+
+	if !g.Has(0, 0) || !g.Has(8, 8) {
+		t.Fatal("expected set cells to be occupied")
+	}
+	if g.Has(9, 0) || g.IsFree(9, 0) {
+		t.Error("off-grid cells must be neither occupied nor free")
+	}
+	if got := g.Count(); got != 2 {
+		t.Errorf("Count = %d, want 2", got)
+	}
+
+	c := g.Clone()
+	g.Unset(8, 8)
+	if g.Has(8, 8) || !c.Has(8, 8) {
+		t.Error("Unset should not affect a clone")
+	}
+}
+
+func TestGridFromOccupancy(t *testing.T) {
+	g := GridFromOccupancy(4, 3, map[string]string{"1,2": "a", "3,0": "b", "7,7": "c", "bad": "d"})
+	if g.Count() != 2 || !g.Has(1, 2) || !g.Has(3, 0) {
+		t.Errorf("unexpected grid contents, count %d", g.Count())
+	}
+}
+
+func TestGridFreeNeighborsOrder(t *testing.T) {
+	g := NewGrid(3, 3)
+	g.Set(2, 1)
+	got := g.FreeNeighbors(model.Point{X: 1, Y: 1}, nil)
+	want := []model.Point{{X: 1, Y: 2}, {X: 1, Y: 0}, {X: 0, Y: 1}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("FreeNeighbors = %v, want %v", got, want)
+	}
+	if n := g.CountFreeNeighbors(0, 0); n != 2 {
+		t.Errorf("CountFreeNeighbors(0,0) = %d, want 2", n)
+	}
+}
+
+func TestGridIsExitPathClear(t *testing.T) {
+	occupied := map[string]string{"3,1": "a"}
+	g := GridFromOccupancy(5, 5, occupied)
+	pos := model.Point{X: 1, Y: 1}
+	for _, dir := range AllDirections {
+		want := IsExitPathClear(pos, dir, 5, 5, occupied)
+		if got := g.IsExitPathClear(pos, dir); got != want {
+			t.Errorf("%s: grid says %v, map says %v", dir, got, want)
+		}
+	}
+}
+
+func TestGridReachableFromEdge(t *testing.T) {
+	// A ring around (2,2) encloses a single free cell
+	g := NewGrid(5, 5)
+	for _, p := range []model.Point{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1}, {X: 1, Y: 2}, {X: 3, Y: 2}, {X: 1, Y: 3}, {X: 2, Y: 3}, {X: 3, Y: 3}} {
+		g.Set(p.X, p.Y)
+	}
+	if got := g.ReachableFromEdge(); got != 16 {
+		t.Errorf("ReachableFromEdge = %d, want 16", got)
+	}
+
+	g.Unset(2, 1)
+	if got := g.ReachableFromEdge(); got != 18 {
+		t.Errorf("ReachableFromEdge after opening ring = %d, want 18", got)
+	}
+}
+
+// benchOccupancy returns a Transcendent-size (24x40) board at about 60% occupancy.
+func benchOccupancy() (w, h int, occupied map[string]string) {
+	w, h = 24, 40
+	rng := rand.New(rand.NewSource(1))
+	occupied = make(map[string]string)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if rng.Float64() < 0.6 {
+				occupied[fmt.Sprintf("%d,%d", x, y)] = "v"
+			}
+		}
+	}
+	return w, h, occupied
+}
+
+// reachableStringKeys is the string-keyed flood fill the placers used before Grid.
+func reachableStringKeys(w, h int, occupied map[string]string) int {
+	visited := make(map[string]bool)
+	var queue []model.Point
+	push := func(x, y int) {
+		key := fmt.Sprintf("%d,%d", x, y)
+		if _, occ := occupied[key]; occ || visited[key] {
+			return
+		}
+		visited[key] = true
+		queue = append(queue, model.Point{X: x, Y: y})
+	}
+	for x := 0; x < w; x++ {
+		push(x, 0)
+		push(x, h-1)
+	}
+	for y := 1; y < h-1; y++ {
+		push(0, y)
+		push(w-1, y)
+	}
+	for i := 0; i < len(queue); i++ {
+		for _, d := range gridNeighborDeltas {
+			if nx, ny := queue[i].X+d[0], queue[i].Y+d[1]; nx >= 0 && nx < w && ny >= 0 && ny < h {
+				push(nx, ny)
+			}
+		}
+	}
+	return len(queue)
+}
+
+func TestGridReachableMatchesStringKeys(t *testing.T) {
+	w, h, occupied := benchOccupancy()
+	if got, want := GridFromOccupancy(w, h, occupied).ReachableFromEdge(), reachableStringKeys(w, h, occupied); got != want {
+		t.Errorf("grid reachable %d, string keys %d", got, want)
+	}
+}
+
+func BenchmarkReachableFromEdge(b *testing.B) {
+	w, h, occupied := benchOccupancy()
+
+	b.Run("string-keys", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			reachableStringKeys(w, h, occupied)
+		}
+	})
+	b.Run("grid", func(b *testing.B) {
+		g := GridFromOccupancy(w, h, occupied)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			g.ReachableFromEdge()
+		}
+	})
+}
+
+func BenchmarkFreeNeighbors(b *testing.B) {
+	w, h, occupied := benchOccupancy()
+
+	b.Run("string-keys", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for y := 0; y < h; y++ {
+				for x := 0; x < w; x++ {
+					for _, d := range gridNeighborDeltas {
+						nx, ny := x+d[0], y+d[1]
+						if nx >= 0 && nx < w && ny >= 0 && ny < h {
+							_, _ = occupied[fmt.Sprintf("%d,%d", nx, ny)]
+						}
+					}
+				}
+			}
+		}
+	})
+	b.Run("grid", func(b *testing.B) {
+		g := GridFromOccupancy(w, h, occupied)
+		buf := make([]model.Point, 0, 4)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for y := 0; y < h; y++ {
+				for x := 0; x < w; x++ {
+					buf = g.FreeNeighbors(model.Point{X: x, Y: y}, buf[:0])
+				}
+			}
+		}
+	})
+}
+
+func TestGridIsEdgeCutMatchesReachable(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for trial := 0; trial < 200; trial++ {
+		w, h := 2+rng.Intn(9), 2+rng.Intn(9)
+		g := NewGrid(w, h)
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				if rng.Float64() < 0.45 {
+					g.Set(x, y)
+				}
+			}
+		}
+		base := g.ReachableFromEdge()
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				if g.Has(x, y) {
+					if g.IsEdgeCut(x, y) {
+						t.Fatalf("%dx%d: occupied (%d,%d) reported as a cut", w, h, x, y)
+					}
+					continue
+				}
+				g.Set(x, y)
+				want := g.ReachableFromEdge() < base-1
+				g.Unset(x, y)
+				if got := g.IsEdgeCut(x, y); got != want {
+					t.Fatalf("%dx%d trial %d: IsEdgeCut(%d,%d) = %v, want %v", w, h, trial, x, y, got, want)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkEdgeCutCheck(b *testing.B) {
+	w, h, occupied := benchOccupancy()
+	g := GridFromOccupancy(w, h, occupied)
+	candidates := []model.Point{{X: w / 2, Y: h / 2}, {X: w/2 + 1, Y: h / 2}, {X: w / 2, Y: h/2 + 1}}
+
+	// One growth step: every candidate checked against the same grid
+	b.Run("reachable", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			base := g.ReachableFromEdge()
+			for _, c := range candidates {
+				g.Set(c.X, c.Y)
+				_ = g.ReachableFromEdge() < base-1
+				g.Unset(c.X, c.Y)
+			}
+		}
+	})
+	b.Run("edge-cut", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			g.cutsValid = false // the grid changes every step
+			for _, c := range candidates {
+				_ = g.IsEdgeCut(c.X, c.Y)
+			}
+		}
+	})
+}
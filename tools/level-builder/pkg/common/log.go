@@ -1,8 +1,20 @@
 package common
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Log formats accepted by --log-format.
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
 )
 
 var (
@@ -10,8 +22,33 @@ var (
 	VerboseEnabled = false
 	// LogFile is the path to write logs to (empty means stdout only)
 	LogFile = ""
+	// LogFormat selects plain text messages (LogFormatText) or one JSON object
+	// per line (LogFormatJSON) for machine ingestion
+	LogFormat = LogFormatText
+
+	// logMu keeps concurrent log lines from interleaving
+	logMu sync.Mutex
+	// stdoutReserved moves stdout log lines to stderr (see ReserveStdout)
+	stdoutReserved bool
 )
 
+// ReserveStdout sends the log lines that normally go to stdout to stderr, for
+// commands writing data such as level JSON to stdout.
+func ReserveStdout() {
+	logMu.Lock()
+	defer logMu.Unlock()
+	stdoutReserved = true
+}
+
+// Fields holds the structured attributes of a log event, e.g. level_id,
+// attempt, phase, duration_ms and coverage.
+type Fields map[string]interface{}
+
+// JSONLogging reports whether logs are emitted as JSON lines.
+func JSONLogging() bool {
+	return LogFormat == LogFormatJSON
+}
+
 // writeToLogFile writes a message to the log file if LogFile is set
 func writeToLogFile(message string) {
 	if LogFile != "" {
@@ -23,16 +60,80 @@ func writeToLogFile(message string) {
 	}
 }
 
+// emit writes one log line to w and the log file. In JSON mode the line is
+// an object with time, level, msg and any fields; otherwise text is written
+// as-is and fields are appended as key=value pairs.
+func emit(w io.Writer, level, text string, fields Fields) {
+	var line string
+	if JSONLogging() {
+		line = formatJSONLine(time.Now(), level, strings.TrimSpace(text), fields)
+	} else {
+		line = text + formatTextFields(fields)
+	}
+
+	logMu.Lock()
+	defer logMu.Unlock()
+	if stdoutReserved && w == os.Stdout {
+		w = os.Stderr
+	}
+	_, _ = fmt.Fprintln(w, line)
+	writeToLogFile(line)
+}
+
+// formatJSONLine encodes a log event as a single JSON object. Fields never
+// override the time, level and msg keys.
+func formatJSONLine(t time.Time, level, msg string, fields Fields) string {
+	obj := make(map[string]interface{}, len(fields)+3)
+	for k, v := range fields {
+		obj[k] = v
+	}
+	obj["time"] = t.UTC().Format(time.RFC3339Nano)
+	obj["level"] = level
+	obj["msg"] = msg
+	b, err := json.Marshal(obj)
+	if err != nil {
+		b, _ = json.Marshal(map[string]interface{}{
+			"time":  obj["time"],
+			"level": level,
+			"msg":   msg,
+			"error": fmt.Sprintf("unencodable fields: %v", err),
+		})
+	}
+	return string(b)
+}
+
+// formatTextFields renders fields as " key=value" pairs in key order.
+func formatTextFields(fields Fields) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	for _, k := range keys {
+		_, _ = fmt.Fprintf(&sb, " %s=%v", k, fields[k])
+	}
+	return sb.String()
+}
+
 // Info prints a message to stdout (always shown, regardless of verbose mode)
 func Info(format string, args ...interface{}) {
-	message := fmt.Sprintf(format, args...)
-	fmt.Println(message)
-	writeToLogFile(message)
+	emit(os.Stdout, "info", fmt.Sprintf(format, args...), nil)
 }
 
-// InfoNoNewline prints a message to stdout without a newline
+// InfoNoNewline prints a message to stdout without a newline. In JSON mode
+// it emits a complete line like Info.
 func InfoNoNewline(format string, args ...interface{}) {
 	message := fmt.Sprintf(format, args...)
+	if JSONLogging() {
+		emit(os.Stdout, "info", message, nil)
+		return
+	}
+	logMu.Lock()
+	defer logMu.Unlock()
 	fmt.Print(message)
 	writeToLogFile(message)
 }
@@ -40,9 +141,11 @@ func InfoNoNewline(format string, args ...interface{}) {
 // Verbose prints a message only when verbose mode is enabled
 func Verbose(format string, args ...interface{}) {
 	if VerboseEnabled {
-		message := fmt.Sprintf("[VERBOSE] "+format, args...)
-		fmt.Println(message)
-		writeToLogFile(message)
+		message := fmt.Sprintf(format, args...)
+		if !JSONLogging() {
+			message = "[VERBOSE] " + message
+		}
+		emit(os.Stdout, "debug", message, nil)
 	}
 }
 
@@ -53,14 +156,32 @@ func Debug(format string, args ...interface{}) {
 
 // Error prints an error message to stderr (always shown)
 func Error(format string, args ...interface{}) {
-	message := fmt.Sprintf("ERROR: "+format, args...)
-	fmt.Fprintln(os.Stderr, message)
-	writeToLogFile(message)
+	message := fmt.Sprintf(format, args...)
+	if !JSONLogging() {
+		message = "ERROR: " + message
+	}
+	emit(os.Stderr, "error", message, nil)
 }
 
 // Warning prints a warning message (always shown)
 func Warning(format string, args ...interface{}) {
-	message := fmt.Sprintf("WARNING: "+format, args...)
-	fmt.Println(message)
-	writeToLogFile(message)
+	message := fmt.Sprintf(format, args...)
+	if !JSONLogging() {
+		message = "WARNING: " + message
+	}
+	emit(os.Stdout, "warn", message, nil)
+}
+
+// Event records a structured event such as a generation attempt finishing.
+// In JSON mode it is always emitted with its fields as top-level keys; in
+// text mode it is shown only in verbose mode, with fields as key=value pairs,
+// since the text output already carries human-readable progress.
+func Event(msg string, fields Fields) {
+	if JSONLogging() {
+		emit(os.Stdout, "info", msg, fields)
+		return
+	}
+	if VerboseEnabled {
+		emit(os.Stdout, "debug", "[VERBOSE] "+msg, fields)
+	}
 }
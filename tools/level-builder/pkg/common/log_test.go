@@ -0,0 +1,83 @@
+package common
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatJSONLine(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	line := formatJSONLine(ts, "info", "attempt done", Fields{
+		"level_id": 12, "attempt": 3, "phase": "rejected", "duration_ms": int64(42), "msg": "ignored",
+	})
+
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", line, err)
+	}
+	want := map[string]interface{}{
+		"time": "2024-05-01T12:00:00Z", "level": "info", "msg": "attempt done",
+		"level_id": 12.0, "attempt": 3.0, "phase": "rejected", "duration_ms": 42.0,
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+func TestFormatTextFields(t *testing.T) {
+	if got := formatTextFields(nil); got != "" {
+		t.Errorf("empty fields = %q", got)
+	}
+	got := formatTextFields(Fields{"phase": "accepted", "attempt": 2, "coverage": 97.5})
+	if want := " attempt=2 coverage=97.5 phase=accepted"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestJSONLogFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.log")
+	prevFile, prevFormat, prevVerbose := LogFile, LogFormat, VerboseEnabled
+	t.Cleanup(func() { LogFile, LogFormat, VerboseEnabled = prevFile, prevFormat, prevVerbose })
+	LogFile, LogFormat, VerboseEnabled = path, LogFormatJSON, false
+
+	// Silence stdout while logging
+	stdout := os.Stdout
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = devNull
+	Warning("disk %s", "low")
+	Event("level done", Fields{"level_id": 7, "coverage": 100.0})
+	Verbose("hidden")
+	os.Stdout = stdout
+	_ = devNull.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d:\n%s", len(lines), data)
+	}
+	var warn, event map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &warn); err != nil {
+		t.Fatal(err)
+	}
+	if warn["level"] != "warn" || warn["msg"] != "disk low" {
+		t.Errorf("unexpected warning line: %s", lines[0])
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &event); err != nil {
+		t.Fatal(err)
+	}
+	if event["level_id"] != 7.0 || event["coverage"] != 100.0 || event["msg"] != "level done" {
+		t.Errorf("unexpected event line: %s", lines[1])
+	}
+}
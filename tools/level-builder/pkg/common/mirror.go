@@ -0,0 +1,128 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/naming"
+)
+
+// Mirror axes supported by MirrorLevel.
+const (
+	MirrorHorizontal = "horizontal" // reflect x: left <-> right
+	MirrorVertical   = "vertical"   // reflect y: up <-> down
+)
+
+// MirrorLevel returns a copy of level reflected along axis and renumbered to
+// newID. Vine IDs, colors and move budgets are preserved so the mirror is a
+// drop-in companion of the source; MirrorOf/MirrorAxis link it back.
+func MirrorLevel(level *model.Level, newID int, axis string) (model.Level, error) {
+	out, err := ReflectLevel(level, axis)
+	if err != nil {
+		return model.Level{}, err
+	}
+	out.ID = newID
+	out.Name = fmt.Sprintf("%s (Mirror)", level.Name)
+	if src := naming.ForLevel(level); src != nil {
+		name := naming.Mirror(*src)
+		out.NameRef = &name
+	}
+	out.MirrorOf = level.ID
+	out.MirrorAxis = axis
+	return out, nil
+}
+
+// ReflectLevel returns a copy of level reflected along axis, keeping its ID,
+// name and metadata.
+func ReflectLevel(level *model.Level, axis string) (model.Level, error) {
+	if axis != MirrorHorizontal && axis != MirrorVertical {
+		return model.Level{}, fmt.Errorf("invalid mirror axis %q", axis)
+	}
+	w, h := level.GetGridWidth(), level.GetGridHeight()
+	reflect := func(p model.Point) model.Point {
+		if axis == MirrorHorizontal {
+			return model.Point{X: w - 1 - p.X, Y: p.Y}
+		}
+		return model.Point{X: p.X, Y: h - 1 - p.Y}
+	}
+	out, err := transformLevel(level, w, h, reflect, func(dir string) string { return MirrorDirection(dir, axis) })
+	if err != nil {
+		return model.Level{}, fmt.Errorf("failed to mirror level %d: %w", level.ID, err)
+	}
+	return out, nil
+}
+
+// transformLevel returns a copy of level on a w x h grid with every vine,
+// mask cell, portal and accent cell moved by point and every head direction
+// turned by dir. point must be a symmetry of the grid, so the copy plays
+// exactly like the source.
+func transformLevel(level *model.Level, w, h int, point func(model.Point) model.Point, dir func(string) string) (model.Level, error) {
+	out := *level
+	out.GridSize = []int{w, h}
+	out.ColorScheme = append([]string(nil), level.ColorScheme...)
+	out.Hints = append([]string(nil), level.Hints...)
+	out.SolutionOrder = append([]string(nil), level.SolutionOrder...)
+	out.BlockingGraph = nil
+	out.ColorDistribution = nil
+
+	out.Vines = make([]model.Vine, len(level.Vines))
+	for i, v := range level.Vines {
+		path := make([]model.Point, len(v.OrderedPath))
+		for j, p := range v.OrderedPath {
+			path[j] = point(p)
+		}
+		mv, err := model.NewVine(v.ID, path, dir(v.HeadDirection))
+		if err == nil && v.IsMultiHead() {
+			mv, err = mv.WithTailHead(dir(v.TailDirection))
+		}
+		if err != nil {
+			return model.Level{}, err
+		}
+		mv.ColorIndex = v.ColorIndex
+		mv.LockedUntil = v.LockedUntil
+		out.Vines[i] = mv
+	}
+
+	if level.Mask != nil {
+		points := make([]model.Point, len(level.Mask.Points))
+		for i, p := range level.Mask.Points {
+			points[i] = point(p)
+		}
+		out.Mask = &model.Mask{Mode: level.Mask.Mode, Points: points}
+	}
+
+	if len(level.Portals) > 0 {
+		out.Portals = make([]model.Portal, len(level.Portals))
+		for i, pt := range level.Portals {
+			out.Portals[i] = model.Portal{A: point(pt.A), B: point(pt.B)}
+		}
+	}
+
+	if level.Theme != nil {
+		theme := *level.Theme
+		theme.AccentCells = make([]model.Point, len(level.Theme.AccentCells))
+		for i, p := range level.Theme.AccentCells {
+			theme.AccentCells[i] = point(p)
+		}
+		out.Theme = &theme
+	}
+
+	return out, nil
+}
+
+// MirrorDirection reflects a head direction along axis.
+func MirrorDirection(dir, axis string) string {
+	switch {
+	case axis == MirrorHorizontal && (dir == DirLeft || dir == DirRight):
+		return OppositeDirection(dir)
+	case axis == MirrorVertical && (dir == DirUp || dir == DirDown):
+		return OppositeDirection(dir)
+	default:
+		return dir
+	}
+}
+
+// MirrorLogicalLevelID returns the logical key for the mirror of levelID.
+func MirrorLogicalLevelID(levelID int) string {
+	return LogicalLevelID(levelID) + "_mirror"
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
 )
@@ -37,8 +39,7 @@ func SaveModuleRegistry(filePath string, registry *model.ModuleRegistry) error {
 		return fmt.Errorf("failed to create directory %s: %w", dir, err)
 	}
 
-	// Marshal to JSON with nice formatting
-	data, err := json.MarshalIndent(registry, "", "  ")
+	data, err := MarshalCanonical(registry, LevelIndent)
 	if err != nil {
 		return fmt.Errorf("failed to marshal modules.json: %w", err)
 	}
@@ -103,6 +104,30 @@ func GetModuleByID(registry *model.ModuleRegistry, moduleID int) (*model.Module,
 	return nil, fmt.Errorf("module %d not found", moduleID)
 }
 
+// logicalTiers are the key prefixes of modules 1-5, in module order.
+var logicalTiers = []string{"seed", "sprout", "blossom", "flourish", "harvest"}
+
+// PhysicalLevelID maps a logical level key back to the level integer
+// LogicalLevelID gives it, e.g. "lvl_sprout_03" to 24 and "lvl_seed_challenge"
+// to 21. ok is false for keys outside that scheme, such as lessons or mirrors.
+func PhysicalLevelID(key string) (id int, ok bool) {
+	for m, tier := range logicalTiers {
+		rest, found := strings.CutPrefix(key, "lvl_"+tier+"_")
+		if !found {
+			continue
+		}
+		if rest == "challenge" {
+			return (m + 1) * 21, true
+		}
+		n, err := strconv.Atoi(rest)
+		if err != nil || n < 1 || n > 20 || rest != fmt.Sprintf("%02d", n) {
+			return 0, false
+		}
+		return m*21 + n, true
+	}
+	return 0, false
+}
+
 // LogicalLevelID maps a physical level integer to its logical String ID.
 func LogicalLevelID(levelID int) string {
 	if levelID == 21 {
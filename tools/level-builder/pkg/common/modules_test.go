@@ -0,0 +1,17 @@
+package common
+
+import "testing"
+
+func TestPhysicalLevelIDInvertsLogicalLevelID(t *testing.T) {
+	for id := 1; id <= 105; id++ {
+		key := LogicalLevelID(id)
+		if got, ok := PhysicalLevelID(key); !ok || got != id {
+			t.Errorf("PhysicalLevelID(%q) = %d, %v; want %d", key, got, ok, id)
+		}
+	}
+	for _, key := range []string{"lesson_1", "lvl_seed_1", "lvl_seed_21", "lvl_sprout_03_mirror", "lvl_bloom_01"} {
+		if id, ok := PhysicalLevelID(key); ok {
+			t.Errorf("PhysicalLevelID(%q) = %d, want not ok", key, id)
+		}
+	}
+}
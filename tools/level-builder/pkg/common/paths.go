@@ -7,8 +7,12 @@ import (
 	"sync"
 )
 
+// GenerationMetadataFile is the batch progress file written next to generated levels.
+const GenerationMetadataFile = "generation_metadata.json"
+
 // Singleton for resolved asset paths
 var (
+	resolvedRepoRoot    string
 	resolvedAssetsDir   string
 	resolvedLevelsDir   string
 	resolvedDataDir     string
@@ -25,9 +29,8 @@ var (
 var RepoMarkerFiles = []string{"nx.json", "bun.lock", "pubspec.yaml"}
 
 // initPaths resolves asset paths once at startup.
-// It looks for the repo root by checking:
-// 1. Current working directory
-// 2. Parent directories (up to 5 levels)
+// It looks for the repo root by checking the current working directory and
+// then each parent directory up to the filesystem root.
 // Returns error if repo root cannot be found.
 func initPaths() {
 	pathsOnce.Do(func() {
@@ -36,6 +39,7 @@ func initPaths() {
 			pathsError = err
 			return
 		}
+		resolvedRepoRoot = repoRoot
 
 		// Support both monorepo and legacy structures
 		monorepoAssets := filepath.Join(repoRoot, "apps", "parable-bloom", "assets")
@@ -65,15 +69,15 @@ func findRepoRoot() (string, error) {
 		return "", fmt.Errorf("failed to get current directory: %w", err)
 	}
 
-	// Check current directory and up to 5 parent directories
+	// Check the current directory and every parent; filepath.Dir returns
+	// the volume root unchanged on both Unix and Windows
 	dir := cwd
-	for i := 0; i < 6; i++ {
+	for {
 		if isRepoRoot(dir) {
 			return dir, nil
 		}
 		parent := filepath.Dir(dir)
 		if parent == dir {
-			// Reached filesystem root
 			break
 		}
 		dir = parent
@@ -102,6 +106,40 @@ func isRepoRoot(dir string) bool {
 	return false
 }
 
+// SetWorkingDir makes dir the directory every path is resolved from: it
+// changes the process directory, so relative flag values resolve against it,
+// and drops any asset paths resolved from the old one. The --working-dir flag
+// calls it before any command runs.
+func SetWorkingDir(dir string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", abs)
+	}
+	if err := os.Chdir(abs); err != nil {
+		return err
+	}
+	ResetPaths()
+	return nil
+}
+
+// WorkspaceRoot returns the absolute path to the repository root, the nearest
+// directory at or above the working directory holding one of RepoMarkerFiles
+// and an assets directory.
+func WorkspaceRoot() (string, error) {
+	initPaths()
+	if pathsError != nil {
+		return "", pathsError
+	}
+	return resolvedRepoRoot, nil
+}
+
 // AssetsDir returns the absolute path to the assets directory.
 func AssetsDir() (string, error) {
 	initPaths()
@@ -196,6 +234,7 @@ func MustLogsDir() string {
 
 // ResetPaths resets the cached paths (useful for testing)
 func ResetPaths() {
+	resolvedRepoRoot = ""
 	resolvedAssetsDir = ""
 	resolvedLevelsDir = ""
 	resolvedDataDir = ""
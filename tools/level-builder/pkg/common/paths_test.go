@@ -0,0 +1,59 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWorkspaceRootFromDeepDirectory(t *testing.T) {
+	root := t.TempDir()
+	assets := filepath.Join(root, "apps", "parable-bloom", "assets")
+	deep := filepath.Join(root, "tools", "level-builder", "pkg", "generator", "strategies", "testdata", "x")
+	for _, dir := range []string{assets, deep} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "nx.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(t.TempDir())
+	t.Cleanup(ResetPaths)
+
+	// More than five levels below the root, which the search used to give up at
+	if err := SetWorkingDir(deep); err != nil {
+		t.Fatal(err)
+	}
+	got, err := WorkspaceRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mustEval(t, got) != mustEval(t, root) {
+		t.Errorf("WorkspaceRoot() = %s, want %s", got, root)
+	}
+	levels, err := LevelsDir()
+	if err != nil || mustEval(t, filepath.Dir(levels)) != mustEval(t, assets) {
+		t.Errorf("LevelsDir() = %s, %v; want under %s", levels, err, assets)
+	}
+
+	// Switching directory drops the paths resolved from the old one
+	if err := SetWorkingDir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WorkspaceRoot(); err == nil {
+		t.Error("expected no workspace root outside the repository")
+	}
+	if err := SetWorkingDir(filepath.Join(root, "nx.json")); err == nil {
+		t.Error("expected a file to be rejected as working directory")
+	}
+}
+
+func mustEval(t *testing.T, path string) string {
+	t.Helper()
+	p, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
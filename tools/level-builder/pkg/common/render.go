@@ -1,9 +1,11 @@
 package common
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
 )
@@ -11,6 +13,22 @@ import (
 // RenderLevelToWriter prints a visual representation of a level to the given writer.
 // style can be "ascii" or "unicode".
 func RenderLevelToWriter(w io.Writer, level *model.Level, style string, showCoords bool) {
+	renderLevel(w, level, style, showCoords, nil)
+}
+
+// RenderHighlightedToWriter renders a level like RenderLevelToWriter but draws
+// only the vines in highlight with their glyphs; every other vine is shaded
+// so the highlighted ones stand out.
+func RenderHighlightedToWriter(w io.Writer, level *model.Level, style string, showCoords bool, highlight []string) {
+	keep := make(map[string]bool, len(highlight))
+	for _, id := range highlight {
+		keep[id] = true
+	}
+	renderLevel(w, level, style, showCoords, keep)
+}
+
+// renderLevel draws level, shading vines missing from highlight when it is non-nil.
+func renderLevel(w io.Writer, level *model.Level, style string, showCoords bool, highlight map[string]bool) {
 	width := level.GridSize[0]
 	height := level.GridSize[1]
 
@@ -20,13 +38,13 @@ func RenderLevelToWriter(w io.Writer, level *model.Level, style string, showCoor
 	}
 
 	// Default cell filler
-	var emptyCell string
+	var emptyCell, shadeCell string
 	var headMap map[string]string
 	if strings.ToLower(style) == "ascii" {
-		emptyCell = "."
+		emptyCell, shadeCell = ".", ":"
 		headMap = map[string]string{"up": "^", "down": "v", "left": "<", "right": ">"}
 	} else {
-		emptyCell = "·"
+		emptyCell, shadeCell = "·", "░"
 		headMap = map[string]string{"up": "↑", "down": "↓", "left": "←", "right": "→"}
 	}
 
@@ -45,6 +63,10 @@ func RenderLevelToWriter(w io.Writer, level *model.Level, style string, showCoor
 
 	for y := 0; y < height; y++ {
 		for x := 0; x < width; x++ {
+			if highlight != nil && shadedCell(level, occ, x, y, highlight) {
+				grid[y][x] = shadeCell
+				continue
+			}
 			grid[y][x] = computeCellGlyph(level, occ, x, y, style, emptyCell, headMap)
 		}
 	}
@@ -93,8 +115,69 @@ func RenderLevelToWriter(w io.Writer, level *model.Level, style string, showCoor
 	// Legend
 	_, _ = fmt.Fprintln(
 		w,
-		"\nLegend: each non-empty symbol represents a vine; head shown as arrow; '*' indicates collision of vines.",
+		"\nLegend: each non-empty symbol represents a vine; head shown as arrow; '*' indicates collision of vines; matching capital letters mark linked portals.",
 	)
+	if highlight != nil {
+		_, _ = fmt.Fprintf(w, "Vines other than the highlighted ones are shaded '%s'.\n", shadeCell)
+	}
+
+	var locked []string
+	for _, v := range level.Vines {
+		if v.LockedUntil > 0 {
+			locked = append(locked, fmt.Sprintf("%s after %d", v.ID, v.LockedUntil))
+		}
+	}
+	if len(locked) > 0 {
+		_, _ = fmt.Fprintf(w, "Locked (clears needed): %s\n", strings.Join(locked, ", "))
+	}
+}
+
+// RenderLevelsSideBySide renders several levels next to each other, each
+// column headed by the matching label. Missing levels render as blank columns.
+func RenderLevelsSideBySide(w io.Writer, levels []*model.Level, labels []string, style string, showCoords bool) {
+	const gutter = "    "
+
+	columns := make([][]string, len(levels))
+	widths := make([]int, len(levels))
+	rows := 0
+	for i, level := range levels {
+		var buf bytes.Buffer
+		if i < len(labels) {
+			_, _ = fmt.Fprintln(&buf, labels[i])
+		}
+		if level != nil {
+			RenderLevelToWriter(&buf, level, style, showCoords)
+		}
+		// Drop the trailing legend so columns stay compact
+		text := strings.TrimRight(buf.String(), "\n")
+		if idx := strings.Index(text, "\n\nLegend:"); idx >= 0 {
+			text = text[:idx]
+		}
+		columns[i] = strings.Split(text, "\n")
+		for _, line := range columns[i] {
+			if n := utf8.RuneCountInString(line); n > widths[i] {
+				widths[i] = n
+			}
+		}
+		if len(columns[i]) > rows {
+			rows = len(columns[i])
+		}
+	}
+
+	for r := 0; r < rows; r++ {
+		var line strings.Builder
+		for i, col := range columns {
+			cell := ""
+			if r < len(col) {
+				cell = col[r]
+			}
+			if i < len(columns)-1 {
+				cell += strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)) + gutter
+			}
+			line.WriteString(cell)
+		}
+		_, _ = fmt.Fprintln(w, strings.TrimRight(line.String(), " "))
+	}
 }
 
 // buildOccupancy creates a map of cell -> list of segment entries.
@@ -123,6 +206,9 @@ func computeCellGlyph(
 	if level.Mask != nil && level.Mask.IsMasked(x, y) {
 		return emptyCell
 	}
+	if glyph, ok := portalGlyph(level, x, y); ok {
+		return glyph
+	}
 	key := fmt.Sprintf("%d,%d", x, y)
 	entries := occ[key]
 	if len(entries) == 0 {
@@ -163,6 +249,29 @@ func computeCellGlyph(
 	return connectorGlyph(style, h, r, d, l)
 }
 
+// shadedCell reports whether the cell shows a single vine that is not highlighted.
+func shadedCell(level *model.Level, occ map[string][]struct{ vineIdx, segIdx int }, x, y int, highlight map[string]bool) bool {
+	if level.Mask != nil && level.Mask.IsMasked(x, y) {
+		return false
+	}
+	if _, ok := portalGlyph(level, x, y); ok {
+		return false
+	}
+	entries := occ[fmt.Sprintf("%d,%d", x, y)]
+	return len(entries) == 1 && !highlight[level.Vines[entries[0].vineIdx].ID]
+}
+
+// portalGlyph labels both cells of the i-th portal pair with the i-th letter.
+func portalGlyph(level *model.Level, x, y int) (string, bool) {
+	p := model.Point{X: x, Y: y}
+	for i, pt := range level.Portals {
+		if p == pt.A || p == pt.B {
+			return string(rune('A' + i%26)), true
+		}
+	}
+	return "", false
+}
+
 func headGlyph(vine model.Vine, j int, headMap map[string]string) (string, bool) {
 	if j == 0 {
 		arrow, ok := headMap[vine.HeadDirection]
@@ -170,6 +279,13 @@ func headGlyph(vine model.Vine, j int, headMap map[string]string) (string, bool)
 			return arrow, true
 		}
 	}
+	// The tail of a multi-head vine is drawn as a second head
+	if j == len(vine.OrderedPath)-1 && vine.IsMultiHead() {
+		arrow, ok := headMap[vine.TailDirection]
+		if ok {
+			return arrow, true
+		}
+	}
 	return "", false
 }
 
@@ -0,0 +1,365 @@
+package common
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// Image formats accepted by RenderLevelImage.
+const (
+	ImageSVG = "svg"
+	ImagePNG = "png"
+)
+
+// DefaultImageCellSize is the pixels per grid cell used when none is given.
+const DefaultImageCellSize = 16
+
+var (
+	imageBackground = color.RGBA{0xFA, 0xFA, 0xF5, 0xFF}
+	imageGridLine   = color.RGBA{0xE0, 0xE0, 0xDA, 0xFF}
+	imageMasked     = color.RGBA{0x9E, 0x9E, 0x9E, 0xFF}
+	imageHead       = color.RGBA{0x33, 0x33, 0x33, 0xFF}
+	// imagePortals colors portal pairs in turn, so linked cells share a color.
+	imagePortals = [2]color.RGBA{{0x5E, 0x35, 0xB1, 0xFF}, {0x00, 0x83, 0x8F, 0xFF}}
+	// imageFallback colors vines when the level has no usable color scheme.
+	imageFallback = []color.RGBA{
+		{0x7C, 0xB3, 0x42, 0xFF}, {0xFF, 0x98, 0x00, 0xFF}, {0x42, 0xA5, 0xF5, 0xFF},
+		{0xAB, 0x47, 0xBC, 0xFF}, {0xEF, 0x53, 0x50, 0xFF}, {0x26, 0xA6, 0x9A, 0xFF},
+	}
+)
+
+// Head arrow geometry, in cells from the head cell's center along the exit
+// direction: the tip sits ahead of center, the base behind it.
+const (
+	arrowTip       = 0.3
+	arrowBase      = -0.2
+	arrowHalfWidth = 0.25
+)
+
+// RenderLevelImage draws level as a static SVG or PNG image. Vines use the
+// level's ColorScheme, bodies are joined through their turns and each head
+// carries an arrow pointing in its exit direction. Masked cells are grey and
+// each portal pair is drawn as two rings of one color. The SVG also labels
+// locked vines with the clears they wait for.
+func RenderLevelImage(w io.Writer, level *model.Level, format string, cellSize int) error {
+	if level.GetGridWidth() <= 0 || level.GetGridHeight() <= 0 {
+		return fmt.Errorf("invalid grid size: %dx%d", level.GetGridWidth(), level.GetGridHeight())
+	}
+	if cellSize <= 0 {
+		cellSize = DefaultImageCellSize
+	}
+	switch format {
+	case ImageSVG:
+		return writeLevelSVG(w, level, cellSize)
+	case ImagePNG:
+		present := make([]bool, len(level.Vines))
+		for i := range present {
+			present[i] = true
+		}
+		return png.Encode(w, newPixelCanvas(level, cellSize).frame(present, -1, 0))
+	default:
+		return fmt.Errorf("unknown image format %q (want %s or %s)", format, ImageSVG, ImagePNG)
+	}
+}
+
+func writeLevelSVG(w io.Writer, level *model.Level, cellSize int) error {
+	height := level.GetGridHeight()
+	cell := float64(cellSize)
+
+	var b strings.Builder
+	svgOpen(&b, level, cellSize, fmt.Sprintf("Level %d: %s", level.ID, level.Name))
+	for i, v := range level.Vines {
+		if len(v.OrderedPath) == 0 {
+			continue
+		}
+		points := make([]string, len(v.OrderedPath))
+		for j := range v.OrderedPath {
+			x, y := svgCenter(v.OrderedPath[len(v.OrderedPath)-1-j], height, cell)
+			points[j] = fmt.Sprintf("%g,%g", x, y)
+		}
+		_, _ = fmt.Fprintf(&b, "  <g id=\"%s\">\n", v.ID)
+		_, _ = fmt.Fprintf(&b, "    <polyline points=\"%s\" %s/>\n", strings.Join(points, " "), svgVineStroke(level, i, cell))
+		for _, head := range v.Heads() {
+			_, _ = fmt.Fprintf(&b, "    <polygon points=\"%s\" fill=\"%s\"/>\n", svgHeadArrow(head, height, cell), hexColor(imageHead))
+		}
+		if v.LockedUntil > 0 && len(v.OrderedPath) > 1 {
+			// The lock count sits on the neck, clear of the head arrow
+			x, y := svgCenter(v.OrderedPath[1], height, cell)
+			_, _ = fmt.Fprintf(&b, "    <text x=\"%g\" y=\"%g\" font-size=\"%g\" font-family=\"sans-serif\" font-weight=\"bold\" text-anchor=\"middle\" dominant-baseline=\"central\" fill=\"%s\">%d</text>\n",
+				x, y, round2(cell*0.5), hexColor(imageHead), v.LockedUntil)
+		}
+		b.WriteString("  </g>\n")
+	}
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// svgOpen writes the <svg> header, a title and the board: grid lines, free
+// cells, masked cells and portals.
+func svgOpen(b *strings.Builder, level *model.Level, cellSize int, title string) {
+	width, height := level.GetGridWidth(), level.GetGridHeight()
+	cell := float64(cellSize)
+	_, _ = fmt.Fprintf(b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n",
+		width*cellSize, height*cellSize, width*cellSize, height*cellSize)
+	_, _ = fmt.Fprintf(b, "  <title>%s</title>\n", xmlEscape(title))
+	_, _ = fmt.Fprintf(b, "  <rect width=\"100%%\" height=\"100%%\" fill=\"%s\"/>\n", hexColor(imageGridLine))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			fill := imageBackground
+			if level.Mask.IsMasked(x, y) {
+				fill = imageMasked
+			}
+			_, _ = fmt.Fprintf(b, "  <rect x=\"%g\" y=\"%g\" width=\"%g\" height=\"%g\" fill=\"%s\"/>\n",
+				float64(x)*cell+0.5, float64(height-1-y)*cell+0.5, cell-1, cell-1, hexColor(fill))
+		}
+	}
+	for i, pt := range level.Portals {
+		for _, p := range []model.Point{pt.A, pt.B} {
+			cx, cy := svgCenter(p, height, cell)
+			_, _ = fmt.Fprintf(b, "  <circle cx=\"%g\" cy=\"%g\" r=\"%g\" fill=\"none\" stroke=\"%s\" stroke-width=\"%g\"/>\n",
+				cx, cy, cell*0.3, hexColor(imagePortals[i%len(imagePortals)]), cell*0.12)
+		}
+	}
+}
+
+// svgCenter returns the pixel center of grid point p; grid y grows upward.
+func svgCenter(p model.Point, height int, cell float64) (float64, float64) {
+	return (float64(p.X) + 0.5) * cell, (float64(height-1-p.Y) + 0.5) * cell
+}
+
+// svgVineStroke returns the stroke attributes shared by static and animated vines.
+func svgVineStroke(level *model.Level, i int, cell float64) string {
+	return fmt.Sprintf("fill=\"none\" stroke=\"%s\" stroke-width=\"%g\" stroke-linecap=\"square\" stroke-linejoin=\"miter\"",
+		hexColor(vineColor(level, i)), cell*0.75)
+}
+
+// svgHeadArrow returns polygon points for the arrow on v's head.
+func svgHeadArrow(v model.Vine, height int, cell float64) string {
+	return svgArrow(v.OrderedPath[0], v.HeadDirection, height, cell)
+}
+
+// svgArrow returns polygon points for an arrow in cell p pointing in dir.
+func svgArrow(p model.Point, dir string, height int, cell float64) string {
+	cx, cy := svgCenter(p, height, cell)
+	dx, dy := DeltaForDirection(dir)
+	fx, fy := float64(dx), -float64(dy) // forward, in pixel space
+	lx, ly := -fy, fx                   // lateral
+	pt := func(f, l float64) string {
+		return fmt.Sprintf("%g,%g", round2(cx+(f*fx+l*lx)*cell), round2(cy+(f*fy+l*ly)*cell))
+	}
+	return strings.Join([]string{
+		pt(arrowTip, 0), pt(arrowBase, arrowHalfWidth), pt(arrowBase, -arrowHalfWidth),
+	}, " ")
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '&':
+			b.WriteString("&amp;")
+		case '<':
+			b.WriteString("&lt;")
+		case '>':
+			b.WriteString("&gt;")
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func vineColor(level *model.Level, i int) color.RGBA {
+	v := level.Vines[i]
+	if v.ColorIndex >= 0 && v.ColorIndex < len(level.ColorScheme) {
+		if c, ok := parseHexColor(level.ColorScheme[v.ColorIndex]); ok {
+			return c
+		}
+	}
+	return imageFallback[i%len(imageFallback)]
+}
+
+// parseHexColor parses "#RRGGBB".
+func parseHexColor(s string) (color.RGBA, bool) {
+	var r, g, b uint8
+	if len(s) != 7 || s[0] != '#' {
+		return color.RGBA{}, false
+	}
+	if _, err := fmt.Sscanf(s[1:], "%02x%02x%02x", &r, &g, &b); err != nil {
+		return color.RGBA{}, false
+	}
+	return color.RGBA{R: r, G: g, B: b, A: 0xFF}, true
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+}
+
+// pixelCanvas draws boards into paletted images for PNG and GIF output.
+type pixelCanvas struct {
+	level   *model.Level
+	w, h    int
+	cell    int
+	palette color.Palette
+	vineIdx []uint8 // palette index per vine
+}
+
+func newPixelCanvas(level *model.Level, cell int) *pixelCanvas {
+	c := &pixelCanvas{
+		level:   level,
+		w:       level.GetGridWidth(),
+		h:       level.GetGridHeight(),
+		cell:    cell,
+		palette: color.Palette{imageBackground, imageGridLine, imageMasked, imageHead},
+		vineIdx: make([]uint8, len(level.Vines)),
+	}
+	if len(level.Portals) > 0 {
+		// Portal colors follow the four board colors
+		c.palette = append(c.palette, imagePortals[0], imagePortals[1])
+	}
+	firstVine := uint8(len(c.palette))
+	seen := make(map[color.RGBA]uint8)
+	for i := range level.Vines {
+		col := vineColor(level, i)
+		idx, ok := seen[col]
+		switch {
+		case ok:
+		case len(c.palette) < 256:
+			idx = uint8(len(c.palette))
+			seen[col] = idx
+			c.palette = append(c.palette, col)
+		default:
+			idx = firstVine // palette full: reuse the first vine color
+		}
+		c.vineIdx[i] = idx
+	}
+	return c
+}
+
+// fillRect paints the pixels in [x0,x1) x [y0,y1).
+func (c *pixelCanvas) fillRect(img *image.Paletted, x0, y0, x1, y1 int, idx uint8) {
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			img.SetColorIndex(x, y, idx)
+		}
+	}
+}
+
+// fillRing paints a portal ring in the cell at (px, py).
+func (c *pixelCanvas) fillRing(img *image.Paletted, px, py int, idx uint8) {
+	size := float64(c.cell)
+	for y := 0; y < c.cell; y++ {
+		for x := 0; x < c.cell; x++ {
+			rx, ry := (float64(x)+0.5)/size-0.5, (float64(y)+0.5)/size-0.5
+			if r := math.Hypot(rx, ry); r >= 0.24 && r <= 0.36 {
+				img.SetColorIndex(px+x, py+y, idx)
+			}
+		}
+	}
+}
+
+// fillArrow paints the head arrow for direction dir in the cell at (px, py).
+func (c *pixelCanvas) fillArrow(img *image.Paletted, px, py int, dir string) {
+	dx, dy := DeltaForDirection(dir)
+	if dx == 0 && dy == 0 {
+		return
+	}
+	fx, fy := float64(dx), -float64(dy)
+	lx, ly := -fy, fx
+	size := float64(c.cell)
+	for y := 0; y < c.cell; y++ {
+		for x := 0; x < c.cell; x++ {
+			rx, ry := (float64(x)+0.5)/size-0.5, (float64(y)+0.5)/size-0.5
+			f, l := rx*fx+ry*fy, rx*lx+ry*ly
+			if f > arrowTip || f < arrowBase {
+				continue
+			}
+			if math.Abs(l) <= arrowHalfWidth*(arrowTip-f)/(arrowTip-arrowBase) {
+				img.SetColorIndex(px+x, py+y, 3)
+			}
+		}
+	}
+}
+
+// frame draws the board with the vines in present; moving (if >= 0) is drawn
+// step cells along its exit track instead of at rest.
+func (c *pixelCanvas) frame(present []bool, moving, step int) *image.Paletted {
+	img := image.NewPaletted(image.Rect(0, 0, c.w*c.cell, c.h*c.cell), c.palette)
+	for y := 0; y < c.h; y++ {
+		for x := 0; x < c.w; x++ {
+			px, py := x*c.cell, (c.h-1-y)*c.cell
+			c.fillRect(img, px, py, px+c.cell, py+c.cell, 1)
+			idx := uint8(0)
+			if c.level.Mask.IsMasked(x, y) {
+				idx = 2
+			}
+			c.fillRect(img, px+1, py+1, px+c.cell, py+c.cell, idx)
+		}
+	}
+
+	for i, pt := range c.level.Portals {
+		for _, p := range []model.Point{pt.A, pt.B} {
+			c.fillRing(img, p.X*c.cell, (c.h-1-p.Y)*c.cell, 4+uint8(i%len(imagePortals)))
+		}
+	}
+
+	inGrid := func(p model.Point) bool { return p.X >= 0 && p.X < c.w && p.Y >= 0 && p.Y < c.h }
+	// joined(j) reports whether cells j and j+1 are drawn as one body; they are
+	// not when the vine is split across a portal
+	draw := func(i int, cells []model.Point, tailDir string, joined func(j int) bool) {
+		inset := c.cell / 8
+		for j, p := range cells {
+			if !inGrid(p) {
+				continue
+			}
+			px, py := p.X*c.cell, (c.h-1-p.Y)*c.cell
+			c.fillRect(img, px+inset, py+inset, px+c.cell-inset, py+c.cell-inset, c.vineIdx[i])
+			// Bridge the inset gap to the next segment so the body reads as one vine
+			if j+1 < len(cells) && inGrid(cells[j+1]) && joined(j) {
+				qx, qy := cells[j+1].X*c.cell, (c.h-1-cells[j+1].Y)*c.cell
+				c.fillRect(img, min(px, qx)+inset, min(py, qy)+inset, max(px, qx)+c.cell-inset, max(py, qy)+c.cell-inset, c.vineIdx[i])
+			}
+		}
+		if len(cells) > 0 && inGrid(cells[0]) {
+			c.fillArrow(img, cells[0].X*c.cell, (c.h-1-cells[0].Y)*c.cell, c.level.Vines[i].HeadDirection)
+		}
+		if tailDir != "" && len(cells) > 0 && inGrid(cells[len(cells)-1]) {
+			tail := cells[len(cells)-1]
+			c.fillArrow(img, tail.X*c.cell, (c.h-1-tail.Y)*c.cell, tailDir)
+		}
+	}
+	for i, v := range c.level.Vines {
+		if !present[i] {
+			continue
+		}
+		if i != moving {
+			draw(i, v.OrderedPath, v.TailDirection, func(int) bool { return true })
+			continue
+		}
+		track, _ := exitTrack(c.level, v)
+		n := len(v.OrderedPath)
+		cells := make([]model.Point, n)
+		for j := range cells {
+			cells[j] = track[step+n-1-j] // head first
+		}
+		runStart := make(map[int]bool)
+		for _, run := range trackRuns(v, track) {
+			runStart[run[0]] = true
+		}
+		draw(i, cells, "", func(j int) bool { return !runStart[step+n-1-j] })
+	}
+	return img
+}
@@ -0,0 +1,93 @@
+package common
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRenderLevelImageSVG(t *testing.T) {
+	level := animationLevel()
+	level.Name = "Rocks & Roots"
+	var buf bytes.Buffer
+	if err := RenderLevelImage(&buf, level, ImageSVG, 10); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	dec := xml.NewDecoder(strings.NewReader(out))
+	for {
+		if _, err := dec.Token(); err != nil {
+			if err != io.EOF {
+				t.Fatalf("invalid SVG: %v\n%s", err, out)
+			}
+			break
+		}
+	}
+
+	if !strings.Contains(out, `width="40" height="30"`) {
+		t.Errorf("expected a 40x30 image:\n%s", out)
+	}
+	for _, want := range []string{`stroke="#FF0000"`, `stroke="#00FF00"`, "Rocks &amp; Roots"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "<polygon"); n != len(level.Vines) {
+		t.Errorf("expected %d head arrows, got %d", len(level.Vines), n)
+	}
+	// Vine a's head is at (2,1) facing right: the tip is 0.3 cells right of
+	// the cell center (25,15)
+	if !strings.Contains(out, `<polygon points="28,15 `) {
+		t.Errorf("unexpected arrow for vine a:\n%s", out)
+	}
+}
+
+func TestRenderLevelImagePNG(t *testing.T) {
+	level := animationLevel()
+	var buf bytes.Buffer
+	if err := RenderLevelImage(&buf, level, ImagePNG, 10); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 40 || b.Dy() != 30 {
+		t.Fatalf("image size %v, want 40x30", b)
+	}
+
+	at := func(px, py int) color.RGBA {
+		return color.RGBAModel.Convert(img.At(px, py)).(color.RGBA)
+	}
+	// Cell (1,1) is vine a's body; (2,1) its head, with the arrow at the center
+	if c := at(1*10+5, 1*10+5); c != (color.RGBA{0xFF, 0, 0, 0xFF}) {
+		t.Errorf("body pixel %v, want red", c)
+	}
+	if c := at(2*10+5, 1*10+5); c != imageHead {
+		t.Errorf("head arrow pixel %v, want %v", c, imageHead)
+	}
+	// The gap between a's two cells is bridged
+	if c := at(2*10, 1*10+5); c != (color.RGBA{0xFF, 0, 0, 0xFF}) {
+		t.Errorf("connector pixel %v, want red", c)
+	}
+	if c := at(5, 2*10+5); c != imageMasked {
+		t.Errorf("masked cell %v, want %v", c, imageMasked)
+	}
+}
+
+func TestRenderLevelImageErrors(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderLevelImage(&buf, animationLevel(), "bmp", 10); err == nil {
+		t.Error("expected error for unknown format")
+	}
+	level := animationLevel()
+	level.GridSize = []int{0, 0}
+	if err := RenderLevelImage(&buf, level, ImageSVG, 10); err == nil {
+		t.Error("expected error for empty grid")
+	}
+}
@@ -0,0 +1,50 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// RotateDirection turns a head direction clockwise by quarterTurns quarter
+// turns (negative turns rotate counter-clockwise).
+func RotateDirection(dir string, quarterTurns int) string {
+	dx, dy := DeltaForDirection(dir)
+	if dx == 0 && dy == 0 {
+		return dir
+	}
+	for range ((quarterTurns % 4) + 4) % 4 {
+		dx, dy = dy, -dx
+	}
+	return DirectionFromDelta(dx, dy)
+}
+
+// RotateLevel returns a copy of level turned clockwise by quarterTurns
+// quarter turns, keeping its ID, name and metadata. A quarter turn swaps
+// the grid's width and height.
+func RotateLevel(level *model.Level, quarterTurns int) (model.Level, error) {
+	turns := ((quarterTurns % 4) + 4) % 4
+	w, h := level.GetGridWidth(), level.GetGridHeight()
+	rotate := func(p model.Point) model.Point {
+		switch turns {
+		case 1:
+			return model.Point{X: p.Y, Y: w - 1 - p.X}
+		case 2:
+			return model.Point{X: w - 1 - p.X, Y: h - 1 - p.Y}
+		case 3:
+			return model.Point{X: h - 1 - p.Y, Y: p.X}
+		default:
+			return p
+		}
+	}
+	newW, newH := w, h
+	if turns%2 == 1 {
+		newW, newH = h, w
+	}
+
+	out, err := transformLevel(level, newW, newH, rotate, func(dir string) string { return RotateDirection(dir, turns) })
+	if err != nil {
+		return model.Level{}, fmt.Errorf("failed to rotate level %d: %w", level.ID, err)
+	}
+	return out, nil
+}
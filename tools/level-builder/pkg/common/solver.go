@@ -2,6 +2,7 @@ package common
 
 import (
 	"fmt"
+	"math/bits"
 
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
 )
@@ -18,10 +19,19 @@ func NewSolver(level *model.Level) *Solver {
 
 // IsSolvableGreedy checks solvability using a fast greedy algorithm.
 func (s *Solver) IsSolvableGreedy() bool {
+	_, ok := s.GreedyClearOrder()
+	return ok
+}
+
+// GreedyClearOrder repeatedly clears the first vine that can exit and returns
+// the vine indices in the order they were cleared. ok is false when the greedy
+// pass gets stuck. A locked vine is skipped until enough other vines have
+// cleared.
+func (s *Solver) GreedyClearOrder() (order []int, ok bool) {
 	vines := s.level.Vines
 	vineCount := len(vines)
 	if vineCount == 0 {
-		return true
+		return []int{}, true
 	}
 
 	w := s.level.GetGridWidth()
@@ -65,7 +75,7 @@ func (s *Solver) IsSolvableGreedy() bool {
 		// Try to find a clearable vine
 		// Optimization: could iterate only active vines, but iterating all is simpler for now
 		for i := 0; i < vineCount; i++ {
-			if !activeVines[i] {
+			if !activeVines[i] || vines[i].IsLocked(len(order)) {
 				continue
 			}
 
@@ -73,6 +83,7 @@ func (s *Solver) IsSolvableGreedy() bool {
 				activeVines[i] = false
 				activeCount--
 				foundClearable = true
+				order = append(order, i)
 				// Restart loop to reflect new empty space immediately?
 				// Greedy Strategy: remove one, then re-evaluate.
 				// For LIFO check, removing one by one is correct.
@@ -81,11 +92,11 @@ func (s *Solver) IsSolvableGreedy() bool {
 		}
 
 		if !foundClearable {
-			return false
+			return nil, false
 		}
 	}
 
-	return true
+	return order, true
 }
 
 // IsSolvableBFS checks solvability using a thorough BFS algorithm.
@@ -140,9 +151,10 @@ func (s *Solver) IsSolvableBFS() bool {
 			}
 		}
 
-		// Try removing each clearable vine
+		// Try removing each clearable vine; every vine outside mask has cleared
+		cleared := vineCount - bits.OnesCount64(uint64(mask))
 		for i := 0; i < vineCount; i++ {
-			if (mask & (1 << uint64(i))) == 0 {
+			if (mask&(1<<uint64(i))) == 0 || vines[i].IsLocked(cleared) {
 				continue
 			}
 
@@ -159,34 +171,36 @@ func (s *Solver) IsSolvableBFS() bool {
 	return false
 }
 
+// canVineClearFast reports whether the vine can slide off the grid head
+// first or, for multi-head vines, tail first.
 func (s *Solver) canVineClearFast(vine *model.Vine, occupied []bool, selfIndices []int, w int) bool {
 	if len(vine.OrderedPath) == 0 {
 		return false
 	}
-
-	delta := HeadDirections[vine.HeadDirection]
-	dx, dy := delta[0], delta[1]
-	if dx == 0 && dy == 0 {
+	if s.canSlideOut(vine.HeadDirection, occupied, selfIndices, w) {
+		return true
+	}
+	if !vine.IsMultiHead() {
 		return false
 	}
+	reversed := make([]int, len(selfIndices))
+	for i, idx := range selfIndices {
+		reversed[len(selfIndices)-1-i] = idx
+	}
+	return s.canSlideOut(vine.TailDirection, occupied, reversed, w)
+}
 
-	h := s.level.GetGridHeight()
-
+// canSlideOut simulates a vine whose cells (leading head first) are
+// selfIndices sliding in dir, through any portals, until it leaves the grid
+// or hits another vine.
+func (s *Solver) canSlideOut(dir string, occupied []bool, selfIndices []int, w int) bool {
 	// Current positions (as indices)
 	positions := make([]int, len(selfIndices))
 	copy(positions, selfIndices)
 
-	maxSteps := w + h + len(positions)
-	for step := 0; step < maxSteps; step++ {
-		headIdx := positions[0]
-		hx, hy := headIdx%w, headIdx/w
-		nx, ny := hx+dx, hy+dy
-
-		if nx < 0 || nx >= w || ny < 0 || ny >= h {
-			return true
-		}
-
-		nextIdx := ny*w + nx
+	head := model.Point{X: positions[0] % w, Y: positions[0] / w}
+	_, ok := s.level.ExitRay(head, dir, func(p model.Point) bool {
+		nextIdx := p.Y*w + p.X
 		if occupied[nextIdx] {
 			collidesWithSelf := false
 			for _, si := range positions {
@@ -204,9 +218,9 @@ func (s *Solver) canVineClearFast(vine *model.Vine, occupied []bool, selfIndices
 			positions[i] = positions[i-1]
 		}
 		positions[0] = nextIdx
-	}
-
-	return false
+		return true
+	})
+	return ok
 }
 
 // canVineClear is a compatibility wrapper for tests
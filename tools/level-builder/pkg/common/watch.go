@@ -0,0 +1,67 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchFiles watches dir for files whose base name matches pattern (filepath.Match
+// syntax) being created, written, renamed or removed. Editors often save in several
+// steps, so events are collected until dir has been quiet for debounce and onChange
+// then receives each changed path once, sorted. It blocks until ctx is cancelled,
+// returning nil, or the watcher fails.
+func WatchFiles(ctx context.Context, dir, pattern string, debounce time.Duration, onChange func(paths []string)) error {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid watch pattern %q: %w", pattern, err)
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start file watcher: %w", err)
+	}
+	defer func() { _ = watcher.Close() }()
+	if err := watcher.Add(dir); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", dir, err)
+	}
+
+	pending := make(map[string]bool)
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if ev.Op == fsnotify.Chmod {
+				continue
+			}
+			if match, _ := filepath.Match(pattern, filepath.Base(ev.Name)); !match {
+				continue
+			}
+			pending[ev.Name] = true
+			timer.Reset(debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return fmt.Errorf("file watcher failed: %w", err)
+		case <-timer.C:
+			paths := make([]string, 0, len(pending))
+			for p := range pending {
+				paths = append(paths, p)
+			}
+			sort.Strings(paths)
+			clear(pending)
+			onChange(paths)
+		}
+	}
+}
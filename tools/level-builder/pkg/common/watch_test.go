@@ -0,0 +1,60 @@
+package common
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWatchFiles(t *testing.T) {
+	dir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan []string, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- WatchFiles(ctx, dir, "level_*.json", 50*time.Millisecond, func(paths []string) { changes <- paths })
+	}()
+	// Give the watcher time to register the directory
+	time.Sleep(100 * time.Millisecond)
+
+	level := filepath.Join(dir, "level_3.json")
+	for i := 0; i < 3; i++ {
+		if err := os.WriteFile(level, []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case paths := <-changes:
+		if !reflect.DeepEqual(paths, []string{level}) {
+			t.Errorf("changed paths = %v, want [%s]", paths, level)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change reported")
+	}
+
+	if err := os.Remove(level); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case paths := <-changes:
+		if !reflect.DeepEqual(paths, []string{level}) {
+			t.Errorf("removed paths = %v, want [%s]", paths, level)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no removal reported")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("WatchFiles returned %v after cancel", err)
+	}
+}
@@ -0,0 +1,43 @@
+package common
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Workers is the number of concurrent workers set by the global --workers flag,
+// for commands to pass on to the packages they run. 0 means it was never set.
+var Workers = 0
+
+// ParseWorkers parses a --workers value: "full" is runtime.NumCPU(), "half" is
+// half of it (at least 1) and an integer string is that many workers.
+func ParseWorkers(value string) (int, error) {
+	value = strings.TrimSpace(strings.ToLower(value))
+
+	switch value {
+	case "full":
+		return runtime.NumCPU(), nil
+	case "half":
+		return max(runtime.NumCPU()/2, 1), nil
+	default:
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("must be 'full', 'half', or a positive integer (got: %s)", value)
+		}
+		if count < 1 {
+			return 0, fmt.Errorf("must be at least 1 (got: %d)", count)
+		}
+		return count, nil
+	}
+}
+
+// WorkerCount returns n when it is positive and runtime.NumCPU() otherwise, the
+// default for worker options left at 0.
+func WorkerCount(n int) int {
+	if n > 0 {
+		return n
+	}
+	return runtime.NumCPU()
+}
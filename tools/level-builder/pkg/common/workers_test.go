@@ -0,0 +1,40 @@
+package common
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestParseWorkers(t *testing.T) {
+	cpus := runtime.NumCPU()
+	for _, tc := range []struct {
+		value string
+		want  int
+	}{
+		{"full", cpus},
+		{" FULL ", cpus},
+		{"half", max(cpus/2, 1)},
+		{"Half", max(cpus/2, 1)},
+		{"3", 3},
+		{"1", 1},
+	} {
+		got, err := ParseWorkers(tc.value)
+		if err != nil || got != tc.want {
+			t.Errorf("ParseWorkers(%q) = %d, %v; want %d", tc.value, got, err, tc.want)
+		}
+	}
+	for _, value := range []string{"", "0", "-2", "most", "1.5"} {
+		if got, err := ParseWorkers(value); err == nil {
+			t.Errorf("ParseWorkers(%q) = %d; want an error", value, got)
+		}
+	}
+}
+
+func TestWorkerCount(t *testing.T) {
+	if got := WorkerCount(0); got != runtime.NumCPU() {
+		t.Errorf("WorkerCount(0) = %d, want %d", got, runtime.NumCPU())
+	}
+	if got := WorkerCount(5); got != 5 {
+		t.Errorf("WorkerCount(5) = %d, want 5", got)
+	}
+}
@@ -0,0 +1,402 @@
+// Package constraints parses and evaluates hand-authored requirements on a
+// level, such as "exactly 2 vines longer than 8" or "solution length >= 15".
+//
+// A constraints file has one requirement per line; '#' starts a comment.
+// Lines before any section apply to every level. A "[level 12]" or
+// "[levels 12-15]" section scopes the lines after it to those level IDs, and a
+// "[Sprout]" section to a difficulty tier:
+//
+//	no heads pointing down
+//
+//	[Flourishing]
+//	at least 2 multi-head vines
+//
+//	[level 12]
+//	exactly 2 vines longer than 8
+//	solution length >= 15
+//
+// A requirement is either "<metric> <op> <n>" with op one of = == != < <= > >=
+// ≤ ≥, or "exactly n <metric>", "at least n <metric>", "at most n <metric>"
+// or "no <metric>". Metrics is the list of metrics.
+package constraints
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/analyze"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+// Constraint is one requirement on a level.
+type Constraint struct {
+	Text   string  // the requirement as written
+	Line   int     // 1-based line in its file
+	Metric string  // normalized metric, e.g. "vines longer than 8"
+	Op     string  // one of == != < <= > >=
+	Value  float64 // the bound
+	eval   func(*facts) (float64, error)
+}
+
+// String returns the requirement as written.
+func (c Constraint) String() string {
+	return c.Text
+}
+
+// Set is a list of constraints that must all hold.
+type Set []Constraint
+
+// File is a parsed constraints file.
+type File struct {
+	All    Set            // constraints for every level
+	Levels map[int]Set    // constraints for one level ID
+	Tiers  map[string]Set // constraints for a difficulty tier, keyed in lower case
+}
+
+// For returns the constraints that apply to a level: those for every level,
+// then its tier's, then its own. A nil File has none.
+func (f *File) For(levelID int, difficulty string) Set {
+	if f == nil {
+		return nil
+	}
+	var set Set
+	set = append(set, f.All...)
+	set = append(set, f.Tiers[strings.ToLower(difficulty)]...)
+	set = append(set, f.Levels[levelID]...)
+	return set
+}
+
+// Result is a constraint evaluated against a level.
+type Result struct {
+	Constraint Constraint
+	Actual     float64
+	OK         bool
+}
+
+// String describes the result, e.g. "✗ exactly 2 vines longer than 8: got 1".
+func (r Result) String() string {
+	mark := "✓"
+	if !r.OK {
+		mark = "✗"
+	}
+	return fmt.Sprintf("%s %s: got %s", mark, r.Constraint.Text, formatValue(r.Actual))
+}
+
+// metric is a measurable property of a level. pattern matches its name;
+// its submatch, if any, is passed to eval as arg.
+type metric struct {
+	pattern *regexp.Regexp
+	eval    func(f *facts, arg string) (float64, error)
+}
+
+// metrics is every metric a constraint may name, singular or plural.
+var metrics = []metric{
+	{regexp.MustCompile(`^vines?$`), func(f *facts, _ string) (float64, error) {
+		return float64(len(f.level.Vines)), nil
+	}},
+	{regexp.MustCompile(`^vines? longer than (\d+)$`), func(f *facts, arg string) (float64, error) {
+		n, _ := strconv.Atoi(arg)
+		return f.countVines(func(v model.Vine) bool { return len(v.OrderedPath) > n }), nil
+	}},
+	{regexp.MustCompile(`^vines? shorter than (\d+)$`), func(f *facts, arg string) (float64, error) {
+		n, _ := strconv.Atoi(arg)
+		return f.countVines(func(v model.Vine) bool { return len(v.OrderedPath) < n }), nil
+	}},
+	{regexp.MustCompile(`^vines? of length (\d+)$`), func(f *facts, arg string) (float64, error) {
+		n, _ := strconv.Atoi(arg)
+		return f.countVines(func(v model.Vine) bool { return len(v.OrderedPath) == n }), nil
+	}},
+	{regexp.MustCompile(`^multi-head vines?$`), func(f *facts, _ string) (float64, error) {
+		return f.countVines(model.Vine.IsMultiHead), nil
+	}},
+	{regexp.MustCompile(`^locked vines?$`), func(f *facts, _ string) (float64, error) {
+		return f.countVines(func(v model.Vine) bool { return v.LockedUntil > 0 }), nil
+	}},
+	{regexp.MustCompile(`^longest vine$`), func(f *facts, _ string) (float64, error) {
+		longest := 0
+		for _, v := range f.level.Vines {
+			longest = max(longest, len(v.OrderedPath))
+		}
+		return float64(longest), nil
+	}},
+	{regexp.MustCompile(`^shortest vine$`), func(f *facts, _ string) (float64, error) {
+		shortest := 0
+		for i, v := range f.level.Vines {
+			if i == 0 || len(v.OrderedPath) < shortest {
+				shortest = len(v.OrderedPath)
+			}
+		}
+		return float64(shortest), nil
+	}},
+	// Counts every head, so a multi-head vine's tail head counts too
+	{regexp.MustCompile(`^heads? pointing (up|down|left|right)$`), func(f *facts, dir string) (float64, error) {
+		n := 0
+		for _, v := range f.level.Vines {
+			for _, h := range v.Heads() {
+				if h.HeadDirection == dir {
+					n++
+				}
+			}
+		}
+		return float64(n), nil
+	}},
+	{regexp.MustCompile(`^portals?$`), func(f *facts, _ string) (float64, error) {
+		return float64(len(f.level.Portals)), nil
+	}},
+	{regexp.MustCompile(`^solution length$`), func(f *facts, _ string) (float64, error) {
+		solution, err := f.solve()
+		return float64(len(solution)), err
+	}},
+	{regexp.MustCompile(`^blocking depth$`), func(f *facts, _ string) (float64, error) {
+		return float64(analyze.Blocking(f.level).MaxDepth), nil
+	}},
+	{regexp.MustCompile(`^coverage$`), func(f *facts, _ string) (float64, error) {
+		if f.level.GetTotalCells() == 0 {
+			return 0, nil
+		}
+		return 100 * float64(f.level.GetOccupiedCells()) / float64(f.level.GetTotalCells()), nil
+	}},
+	{regexp.MustCompile(`^grid width$`), func(f *facts, _ string) (float64, error) {
+		return float64(f.level.GetGridWidth()), nil
+	}},
+	{regexp.MustCompile(`^grid height$`), func(f *facts, _ string) (float64, error) {
+		return float64(f.level.GetGridHeight()), nil
+	}},
+}
+
+// Metrics lists the metric names constraints accept, with n a number.
+var Metrics = []string{
+	"vines", "vines longer than n", "vines shorter than n", "vines of length n",
+	"multi-head vines", "locked vines", "longest vine", "shortest vine",
+	"heads pointing up|down|left|right", "portals", "solution length",
+	"blocking depth", "coverage", "grid width", "grid height",
+}
+
+var (
+	sectionPattern  = regexp.MustCompile(`^\[\s*(.*?)\s*\]$`)
+	levelsPattern   = regexp.MustCompile(`^levels?\s+(\d+)(?:\s*-\s*(\d+))?$`)
+	comparePattern  = regexp.MustCompile(`^(.+?)\s*(==|!=|<=|>=|≤|≥|=|<|>)\s*(-?\d+(?:\.\d+)?)%?$`)
+	quantifyPattern = regexp.MustCompile(`^(exactly|at least|at most)\s+(\d+(?:\.\d+)?)\s+(.+)$`)
+)
+
+// ParseFile reads a constraints file.
+func ParseFile(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	file, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return file, nil
+}
+
+// Parse reads constraints from r.
+func Parse(r io.Reader) (*File, error) {
+	file := &File{Levels: make(map[int]Set), Tiers: make(map[string]Set)}
+	add := func(c Constraint) { file.All = append(file.All, c) }
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.Index(text, "#"); i >= 0 {
+			text = text[:i]
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+
+		if m := sectionPattern.FindStringSubmatch(text); m != nil {
+			var err error
+			if add, err = file.section(m[1]); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			continue
+		}
+
+		c, err := ParseConstraint(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		c.Line = line
+		add(c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// section returns the function adding constraints to the named section.
+func (f *File) section(name string) (func(Constraint), error) {
+	if m := levelsPattern.FindStringSubmatch(strings.ToLower(name)); m != nil {
+		first, _ := strconv.Atoi(m[1])
+		last := first
+		if m[2] != "" {
+			last, _ = strconv.Atoi(m[2])
+		}
+		if last < first {
+			return nil, fmt.Errorf("section [%s]: empty level range", name)
+		}
+		return func(c Constraint) {
+			for id := first; id <= last; id++ {
+				f.Levels[id] = append(f.Levels[id], c)
+			}
+		}, nil
+	}
+	tier := strings.ToLower(name)
+	if !validTiers[tier] {
+		return nil, fmt.Errorf("unknown section [%s]: want [level N], [levels N-M] or a difficulty tier", name)
+	}
+	return func(c Constraint) { f.Tiers[tier] = append(f.Tiers[tier], c) }, nil
+}
+
+var validTiers = map[string]bool{
+	"tutorial": true, "seedling": true, "sprout": true, "nurturing": true, "flourishing": true, "transcendent": true,
+}
+
+// ParseConstraint parses a single requirement.
+func ParseConstraint(text string) (Constraint, error) {
+	c := Constraint{Text: strings.TrimSpace(text)}
+	phrase := strings.ToLower(strings.Join(strings.Fields(c.Text), " "))
+
+	var name, value string
+	switch {
+	case strings.HasPrefix(phrase, "no "):
+		name, c.Op, value = strings.TrimPrefix(phrase, "no "), "==", "0"
+	case quantifyPattern.MatchString(phrase):
+		m := quantifyPattern.FindStringSubmatch(phrase)
+		c.Op = map[string]string{"exactly": "==", "at least": ">=", "at most": "<="}[m[1]]
+		value, name = m[2], m[3]
+	case comparePattern.MatchString(phrase):
+		m := comparePattern.FindStringSubmatch(phrase)
+		name, value = m[1], m[3]
+		c.Op = map[string]string{"=": "==", "≤": "<=", "≥": ">="}[m[2]]
+		if c.Op == "" {
+			c.Op = m[2]
+		}
+	default:
+		return c, fmt.Errorf("cannot parse %q: want \"<metric> <op> <n>\", \"exactly|at least|at most <n> <metric>\" or \"no <metric>\"", c.Text)
+	}
+
+	var err error
+	if c.Value, err = strconv.ParseFloat(value, 64); err != nil {
+		return c, fmt.Errorf("%q: bad number %q", c.Text, value)
+	}
+	for _, m := range metrics {
+		sub := m.pattern.FindStringSubmatch(name)
+		if sub == nil {
+			continue
+		}
+		c.Metric = name
+		arg := ""
+		if len(sub) > 1 {
+			arg = sub[1]
+		}
+		eval := m.eval
+		c.eval = func(f *facts) (float64, error) { return eval(f, arg) }
+		return c, nil
+	}
+	return c, fmt.Errorf("%q: unknown metric %q (known: %s)", c.Text, name, strings.Join(Metrics, ", "))
+}
+
+// Evaluate checks every constraint against level. maxStates bounds the solve
+// behind "solution length"; the level is only solved when a constraint needs it.
+func (s Set) Evaluate(ctx context.Context, level model.Level, maxStates int) ([]Result, error) {
+	f := &facts{ctx: ctx, level: &level, maxStates: maxStates}
+	results := make([]Result, 0, len(s))
+	for _, c := range s {
+		actual, err := c.eval(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", c.Text, err)
+		}
+		results = append(results, Result{Constraint: c, Actual: actual, OK: compare(actual, c.Op, c.Value)})
+	}
+	return results, nil
+}
+
+// Unmet returns the results whose constraint does not hold.
+func Unmet(results []Result) []Result {
+	var unmet []Result
+	for _, r := range results {
+		if !r.OK {
+			unmet = append(unmet, r)
+		}
+	}
+	return unmet
+}
+
+func compare(actual float64, op string, value float64) bool {
+	const eps = 1e-9
+	switch op {
+	case "==":
+		return math.Abs(actual-value) < eps
+	case "!=":
+		return math.Abs(actual-value) >= eps
+	case "<":
+		return actual < value-eps
+	case "<=":
+		return actual <= value+eps
+	case ">":
+		return actual > value+eps
+	case ">=":
+		return actual >= value-eps
+	}
+	return false
+}
+
+func formatValue(v float64) string {
+	if v == math.Trunc(v) {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(v, 'f', 1, 64)
+}
+
+// facts measures one level, solving it at most once.
+type facts struct {
+	ctx       context.Context
+	level     *model.Level
+	maxStates int
+	solved    bool
+	solution  []string
+	solveErr  error
+}
+
+func (f *facts) countVines(pred func(model.Vine) bool) float64 {
+	n := 0
+	for _, v := range f.level.Vines {
+		if pred(v) {
+			n++
+		}
+	}
+	return float64(n)
+}
+
+func (f *facts) solve() ([]string, error) {
+	if f.solved {
+		return f.solution, f.solveErr
+	}
+	f.solved = true
+	ok, solution, stats, err := validator.SolveContext(f.ctx, *f.level, f.maxStates)
+	switch {
+	case err != nil:
+		f.solveErr = err
+	case stats.GaveUp:
+		f.solveErr = fmt.Errorf("solver gave up after %d states", stats.StatesExplored)
+	case !ok:
+		f.solveErr = fmt.Errorf("level is not solvable")
+	}
+	f.solution = solution
+	return f.solution, f.solveErr
+}
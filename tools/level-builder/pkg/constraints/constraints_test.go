@@ -0,0 +1,124 @@
+package constraints
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+const sample = `# applies everywhere
+no heads pointing down
+
+[Sprout]
+at least 1 vine longer than 2   # trailing comment
+
+[levels 3-4]
+Solution length ≥ 3
+coverage > 50%
+`
+
+func testLevel() model.Level {
+	return model.Level{
+		ID:       3,
+		GridSize: []int{3, 3},
+		Vines: []model.Vine{
+			{ID: "a", HeadDirection: "right", OrderedPath: []model.Point{{X: 2, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 0}}},
+			{ID: "b", HeadDirection: "up", OrderedPath: []model.Point{{X: 0, Y: 2}, {X: 0, Y: 1}}},
+			{ID: "c", HeadDirection: "up", OrderedPath: []model.Point{{X: 1, Y: 2}, {X: 1, Y: 1}}, TailDirection: "down"},
+		},
+	}
+}
+
+func TestParseSections(t *testing.T) {
+	f, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(f.All) != 1 || f.All[0].Line != 2 || f.All[0].Op != "==" || f.All[0].Value != 0 {
+		t.Errorf("All = %+v", f.All)
+	}
+
+	var texts []string
+	for _, c := range f.For(3, "Sprout") {
+		texts = append(texts, c.Text)
+	}
+	want := "no heads pointing down|at least 1 vine longer than 2|Solution length ≥ 3|coverage > 50%"
+	if got := strings.Join(texts, "|"); got != want {
+		t.Errorf("For(3, Sprout) = %q, want %q", got, want)
+	}
+	if n := len(f.For(5, "Seedling")); n != 1 {
+		t.Errorf("For(5, Seedling) has %d constraints, want 1", n)
+	}
+	if (*File)(nil).For(1, "Sprout") != nil {
+		t.Error("a nil File has constraints")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, src := range []string{
+		"vines longer than eight = 2",
+		"exactly 2 dragons",
+		"[Sequoia]\nvines > 1",
+		"[levels 5-2]",
+		"lots of vines",
+	} {
+		if _, err := Parse(strings.NewReader(src)); err == nil {
+			t.Errorf("Parse(%q) succeeded", src)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	cases := map[string]bool{
+		"exactly 3 vines":              true,
+		"exactly 1 vine longer than 2": true,
+		"vines of length 2 == 2":       true,
+		"at most 0 multi-head vines":   false,
+		"no heads pointing down":       false, // c's tail head points down
+		"heads pointing up = 2":        true,
+		"longest vine >= 4":            false,
+		"shortest vine = 2":            true,
+		"solution length >= 3":         true,
+		"coverage < 100":               true,
+		"grid width != 3":              false,
+		"no locked vines":              true,
+		"no portals":                   true,
+	}
+	for text, want := range cases {
+		c, err := ParseConstraint(text)
+		if err != nil {
+			t.Fatalf("ParseConstraint(%q): %v", text, err)
+		}
+		results, err := Set{c}.Evaluate(context.Background(), testLevel(), 10000)
+		if err != nil {
+			t.Fatalf("Evaluate(%q): %v", text, err)
+		}
+		if results[0].OK != want {
+			t.Errorf("%s", results[0])
+		}
+	}
+}
+
+func TestEvaluateUnsolvable(t *testing.T) {
+	// a and b face each other
+	lvl := model.Level{
+		ID:       9,
+		GridSize: []int{4, 2},
+		Vines: []model.Vine{
+			{ID: "a", HeadDirection: "right", OrderedPath: []model.Point{{X: 1, Y: 1}, {X: 0, Y: 1}}},
+			{ID: "b", HeadDirection: "left", OrderedPath: []model.Point{{X: 2, Y: 1}, {X: 3, Y: 1}}},
+		},
+	}
+
+	c, _ := ParseConstraint("solution length >= 1")
+	if _, err := (Set{c}).Evaluate(context.Background(), lvl, 10000); err == nil {
+		t.Error("solution length evaluated on an unsolvable level")
+	}
+	// Metrics that need no solve still evaluate
+	c, _ = ParseConstraint("vines = 2")
+	if results, err := (Set{c}).Evaluate(context.Background(), lvl, 10000); err != nil || len(Unmet(results)) != 0 {
+		t.Errorf("vines = 2: %v %v", results, err)
+	}
+}
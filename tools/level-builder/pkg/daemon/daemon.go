@@ -0,0 +1,545 @@
+// Package daemon runs level generation and validation as a long-lived HTTP
+// service, so a backend can generate levels (daily challenges, say) without
+// spawning a process per level.
+//
+// Generation is asynchronous: a client submits a job, then polls it or
+// streams its progress. A fixed pool of workers runs jobs from a bounded
+// queue, and finished jobs are kept for lookup until Retain newer ones have
+// finished. Validation is quick enough to answer inline.
+//
+//	POST   /v1/jobs              submit a JobRequest; 202 with the queued Job
+//	GET    /v1/jobs              every job the daemon remembers
+//	GET    /v1/jobs/{id}         one job, with its level once it succeeded
+//	GET    /v1/jobs/{id}/events  NDJSON stream of the job's progress and result
+//	DELETE /v1/jobs/{id}         cancel a queued or running job
+//	POST   /v1/validate          validate a level JSON body
+//	GET    /v1/health            worker and queue status
+//
+// Jobs live in memory only; a restart forgets them.
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/constraints"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/levelgen"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+// maxBodyBytes bounds request bodies; a large level is well under 1MB.
+const maxBodyBytes = 4 << 20
+
+// Job states.
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+// Options configures a Service.
+type Options struct {
+	Workers    int           // jobs generated at once (default 1)
+	QueueSize  int           // jobs waiting for a worker before submissions are refused (default 64)
+	JobTimeout time.Duration // limit on each job's generation (0 = none)
+	Retain     int           // finished jobs kept for lookup (default 256)
+	// MaxStates is the solver budget for POST /v1/validate
+	MaxStates int
+	// DumpDir is where placers write failure dumps (default failing_dumps)
+	DumpDir string
+}
+
+// JobRequest is the generation config a client submits. Its fields follow
+// levelgen.GenerateOptions; constraints are written as in a constraints file,
+// one requirement per entry.
+type JobRequest struct {
+	LevelID               int      `json:"level_id"`
+	Difficulty            string   `json:"difficulty,omitempty"` // default: the tier for LevelID
+	Seed                  int64    `json:"seed,omitempty"`
+	Strategy              string   `json:"strategy,omitempty"`
+	FillerStrategy        string   `json:"filler_strategy,omitempty"`
+	MaskMode              string   `json:"mask_mode,omitempty"`
+	Portals               bool     `json:"portals,omitempty"`
+	Hints                 int      `json:"hints,omitempty"`
+	InjectDifficulty      bool     `json:"inject_difficulty,omitempty"`
+	OptimizeIterations    int      `json:"optimize_iterations,omitempty"`
+	MinCoverage           float64  `json:"min_coverage,omitempty"`
+	Aggressive            bool     `json:"aggressive,omitempty"`
+	Profile               string   `json:"profile,omitempty"`
+	RNG                   string   `json:"rng,omitempty"`
+	SkipDifficultyCheck   bool     `json:"skip_difficulty_check,omitempty"`
+	MinAesthetics         float64  `json:"min_aesthetics,omitempty"`
+	Constraints           []string `json:"constraints,omitempty"`
+	MaxRetriesPerStrategy int      `json:"max_retries_per_strategy,omitempty"`
+	Race                  int      `json:"race,omitempty"` // ignored when Seed is set
+}
+
+// options converts r into generate options, rejecting configs Generate would.
+func (r *JobRequest) options(dumpDir string) (levelgen.GenerateOptions, error) {
+	if r.LevelID <= 0 {
+		return levelgen.GenerateOptions{}, errors.New("level_id must be a positive level ID")
+	}
+	if r.Difficulty == "" {
+		r.Difficulty = common.DifficultyForLevel(r.LevelID)
+	}
+	var set constraints.Set
+	for _, text := range r.Constraints {
+		c, err := constraints.ParseConstraint(text)
+		if err != nil {
+			return levelgen.GenerateOptions{}, fmt.Errorf("constraint %q: %w", text, err)
+		}
+		set = append(set, c)
+	}
+	opts := levelgen.GenerateOptions{
+		LevelID:               r.LevelID,
+		Difficulty:            r.Difficulty,
+		Seed:                  r.Seed,
+		Strategy:              r.Strategy,
+		FillerStrategy:        r.FillerStrategy,
+		MaskMode:              r.MaskMode,
+		Portals:               r.Portals,
+		Hints:                 r.Hints,
+		InjectDifficulty:      r.InjectDifficulty,
+		OptimizeIterations:    r.OptimizeIterations,
+		MinCoverage:           r.MinCoverage,
+		Aggressive:            r.Aggressive,
+		DumpDir:               dumpDir,
+		Profile:               r.Profile,
+		RNG:                   r.RNG,
+		SkipDifficultyCheck:   r.SkipDifficultyCheck,
+		MinAesthetics:         r.MinAesthetics,
+		Constraints:           set,
+		MaxRetriesPerStrategy: r.MaxRetriesPerStrategy,
+		Race:                  r.Race,
+	}
+	if _, err := levelgen.ConfigFor(opts); err != nil {
+		return levelgen.GenerateOptions{}, err
+	}
+	if _, err := generator.GetStrategy(levelgen.ResolveStrategy(opts)); err != nil {
+		return levelgen.GenerateOptions{}, err
+	}
+	return opts, nil
+}
+
+// Event is one progress notification from a job's generation.
+type Event struct {
+	Kind        levelgen.EventKind `json:"kind"`
+	Strategy    string             `json:"strategy"`
+	Attempt     int                `json:"attempt"`
+	Message     string             `json:"message"`
+	Attempts    int                `json:"attempts"`
+	Relaxations int                `json:"relaxations"`
+}
+
+// JobStats summarizes a finished job's generation.
+type JobStats struct {
+	Strategy             string  `json:"strategy,omitempty"`
+	Attempts             int     `json:"attempts"`
+	Fallbacks            int     `json:"fallbacks"`
+	Backtracks           int     `json:"backtracks"`
+	Relaxations          int     `json:"relaxations"`
+	DifficultyRejections int     `json:"difficulty_rejections"`
+	DepthRejections      int     `json:"depth_rejections"`
+	ConstraintRejections int     `json:"constraint_rejections"`
+	AestheticRejections  int     `json:"aesthetic_rejections"`
+	Coverage             float64 `json:"coverage"`
+	DifficultyScore      float64 `json:"difficulty_score,omitempty"`
+	AestheticsScore      float64 `json:"aesthetics_score,omitempty"`
+	DurationMs           int64   `json:"duration_ms"`
+}
+
+// Job is a snapshot of a submitted job.
+type Job struct {
+	ID        string       `json:"id"`
+	Status    string       `json:"status"`
+	Request   JobRequest   `json:"request"`
+	Submitted time.Time    `json:"submitted"`
+	Started   *time.Time   `json:"started,omitempty"`
+	Finished  *time.Time   `json:"finished,omitempty"`
+	Events    int          `json:"events"`
+	Level     *model.Level `json:"level,omitempty"`
+	Stats     *JobStats    `json:"stats,omitempty"`
+	Error     string       `json:"error,omitempty"`
+}
+
+// Done reports whether the job has finished, whatever the outcome.
+func (j Job) Done() bool {
+	return j.Status == StatusSucceeded || j.Status == StatusFailed || j.Status == StatusCancelled
+}
+
+// job is a Job plus what the service needs to run and stream it. Every field
+// is guarded by Service.mu.
+type job struct {
+	Job
+	opts   levelgen.GenerateOptions
+	events []Event
+	cancel context.CancelFunc // set while running
+	// changed is closed and replaced whenever the job changes, waking streams
+	changed chan struct{}
+}
+
+func (j *job) notify() {
+	close(j.changed)
+	j.changed = make(chan struct{})
+}
+
+// Service queues and runs generation jobs.
+type Service struct {
+	opts  Options
+	queue chan *job
+
+	mu       sync.Mutex
+	jobs     map[string]*job
+	finished []string // IDs in finishing order, for retention
+	nextID   int
+	running  int
+}
+
+// New returns a service; call Run to start its workers.
+func New(opts Options) *Service {
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 64
+	}
+	if opts.Retain <= 0 {
+		opts.Retain = 256
+	}
+	if opts.MaxStates <= 0 {
+		opts.MaxStates = 1000000
+	}
+	return &Service{
+		opts:  opts,
+		queue: make(chan *job, opts.QueueSize),
+		jobs:  make(map[string]*job),
+	}
+}
+
+// Run works through the queue until ctx is cancelled, which also cancels the
+// running jobs. It returns once every worker has stopped.
+func (s *Service) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range s.opts.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case j := <-s.queue:
+					s.run(ctx, j)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Submit queues a generation job. It fails when the request is invalid or
+// the queue is full.
+func (s *Service) Submit(req JobRequest) (Job, error) {
+	opts, err := req.options(s.opts.DumpDir)
+	if err != nil {
+		return Job{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	j := &job{
+		Job:     Job{ID: strconv.Itoa(s.nextID), Status: StatusQueued, Request: req, Submitted: time.Now()},
+		opts:    opts,
+		changed: make(chan struct{}),
+	}
+	select {
+	case s.queue <- j:
+	default:
+		return Job{}, ErrQueueFull
+	}
+	s.jobs[j.ID] = j
+	return j.Job, nil
+}
+
+// ErrQueueFull is returned by Submit when QueueSize jobs are already waiting.
+var ErrQueueFull = errors.New("the job queue is full; retry later")
+
+// Get returns a snapshot of job id.
+func (s *Service) Get(id string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return j.Job, true
+}
+
+// Cancel stops job id: a queued job is dropped and a running one interrupted.
+// It reports false when the job is unknown or already finished.
+func (s *Service) Cancel(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok || j.Done() {
+		return false
+	}
+	if j.cancel != nil {
+		j.cancel()
+		return true
+	}
+	s.finish(j, StatusCancelled, context.Canceled.Error())
+	return true
+}
+
+func (s *Service) run(ctx context.Context, j *job) {
+	s.mu.Lock()
+	if j.Done() { // cancelled while queued
+		s.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if s.opts.JobTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, s.opts.JobTimeout)
+		defer cancel()
+	}
+	now := time.Now()
+	j.Status, j.Started, j.cancel = StatusRunning, &now, cancel
+	s.running++
+	j.notify()
+	s.mu.Unlock()
+
+	opts := j.opts
+	opts.OnProgress = func(e levelgen.Event) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		j.events = append(j.events, Event{
+			Kind: e.Kind, Strategy: e.Strategy, Attempt: e.Attempt, Message: e.Message,
+			Attempts: e.Attempts, Relaxations: e.Relaxations,
+		})
+		j.Events = len(j.events)
+		j.notify()
+	}
+	level, stats, err := levelgen.Generate(ctx, opts)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running--
+	j.cancel = nil
+	j.Stats = &JobStats{
+		Strategy:             stats.Strategy,
+		Attempts:             stats.Attempts,
+		Fallbacks:            stats.Fallbacks,
+		Backtracks:           stats.Backtracks,
+		Relaxations:          stats.Relaxations,
+		DifficultyRejections: stats.DifficultyRejections,
+		DepthRejections:      stats.DepthRejections,
+		ConstraintRejections: stats.ConstraintRejections,
+		AestheticRejections:  stats.AestheticRejections,
+		Coverage:             stats.Coverage,
+		DifficultyScore:      stats.Difficulty.Score,
+		AestheticsScore:      stats.Aesthetics.Score,
+		DurationMs:           stats.Duration.Milliseconds(),
+	}
+	switch {
+	case err == nil:
+		j.Level = &level
+		s.finish(j, StatusSucceeded, "")
+	case errors.Is(err, context.Canceled):
+		s.finish(j, StatusCancelled, err.Error())
+	default:
+		s.finish(j, StatusFailed, err.Error())
+	}
+}
+
+// finish records j's outcome and forgets the oldest finished jobs beyond
+// Retain. s.mu must be held.
+func (s *Service) finish(j *job, status, errText string) {
+	now := time.Now()
+	j.Status, j.Error, j.Finished = status, errText, &now
+	j.notify()
+	s.finished = append(s.finished, j.ID)
+	for len(s.finished) > s.opts.Retain {
+		delete(s.jobs, s.finished[0])
+		s.finished = s.finished[1:]
+	}
+}
+
+// Handler returns the service's HTTP routes.
+func (s *Service) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/jobs", s.submitJob)
+	mux.HandleFunc("GET /v1/jobs", s.listJobs)
+	mux.HandleFunc("GET /v1/jobs/{id}", s.getJob)
+	mux.HandleFunc("GET /v1/jobs/{id}/events", s.streamJob)
+	mux.HandleFunc("DELETE /v1/jobs/{id}", s.cancelJob)
+	mux.HandleFunc("POST /v1/validate", s.validate)
+	mux.HandleFunc("GET /v1/health", s.health)
+	return mux
+}
+
+func (s *Service) submitJob(w http.ResponseWriter, r *http.Request) {
+	var req JobRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid job request: %w", err))
+		return
+	}
+	j, err := s.Submit(req)
+	switch {
+	case errors.Is(err, ErrQueueFull):
+		writeError(w, http.StatusServiceUnavailable, err)
+	case err != nil:
+		writeError(w, http.StatusBadRequest, err)
+	default:
+		w.Header().Set("Location", "/v1/jobs/"+j.ID)
+		writeJSON(w, http.StatusAccepted, j)
+	}
+}
+
+func (s *Service) listJobs(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	jobs := make([]Job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		view := j.Job
+		view.Level = nil // fetch levels one job at a time
+		jobs = append(jobs, view)
+	}
+	s.mu.Unlock()
+	slices.SortFunc(jobs, func(a, b Job) int { return a.Submitted.Compare(b.Submitted) })
+	writeJSON(w, http.StatusOK, jobs)
+}
+
+func (s *Service) getJob(w http.ResponseWriter, r *http.Request) {
+	if j, ok := s.Get(r.PathValue("id")); ok {
+		writeJSON(w, http.StatusOK, j)
+		return
+	}
+	writeError(w, http.StatusNotFound, fmt.Errorf("job %s not found", r.PathValue("id")))
+}
+
+func (s *Service) cancelJob(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if _, ok := s.Get(id); !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("job %s not found", id))
+		return
+	}
+	if !s.Cancel(id) {
+		writeError(w, http.StatusConflict, fmt.Errorf("job %s has already finished", id))
+		return
+	}
+	j, _ := s.Get(id)
+	writeJSON(w, http.StatusOK, j)
+}
+
+// StreamMessage is one line of a job's event stream: an "event" line for
+// each progress event, then a "result" line with the finished job.
+type StreamMessage struct {
+	Type  string `json:"type"`
+	Event *Event `json:"event,omitempty"`
+	Job   *Job   `json:"job,omitempty"`
+}
+
+// streamJob writes the job's events so far, then follows it until it
+// finishes or the client goes away.
+func (s *Service) streamJob(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	s.mu.Lock()
+	j, ok := s.jobs[id]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("job %s not found", id))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-cache")
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	sent := 0
+	for {
+		s.mu.Lock()
+		events := slices.Clone(j.events[sent:])
+		snapshot, changed := j.Job, j.changed
+		s.mu.Unlock()
+
+		for i := range events {
+			if err := enc.Encode(StreamMessage{Type: "event", Event: &events[i]}); err != nil {
+				return
+			}
+		}
+		sent += len(events)
+		if snapshot.Done() {
+			_ = enc.Encode(StreamMessage{Type: "result", Job: &snapshot})
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *Service) validate(w http.ResponseWriter, r *http.Request) {
+	var lvl model.Level
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(&lvl); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid level JSON: %w", err))
+		return
+	}
+	result, ok, err := validator.ValidateLevel(r.Context(), &lvl, r.URL.Query().Get("solvable") != "false", s.opts.MaxStates, false)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !ok {
+		writeError(w, http.StatusServiceUnavailable, errors.New("validation was cancelled"))
+		return
+	}
+	writeJSON(w, http.StatusOK, struct {
+		validator.LevelResult
+		Passed bool `json:"passed"`
+	}{result, result.Passed()})
+}
+
+func (s *Service) health(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	running := s.running
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{
+		"status":  "ok",
+		"workers": s.opts.Workers,
+		"running": running,
+		"queued":  len(s.queue),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
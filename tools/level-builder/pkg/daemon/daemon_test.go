@@ -0,0 +1,109 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestJobLifecycle(t *testing.T) {
+	svc := New(Options{DumpDir: t.TempDir()})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go svc.Run(ctx)
+	srv := httptest.NewServer(svc.Handler())
+	defer srv.Close()
+
+	res, err := http.Post(srv.URL+"/v1/jobs", "application/json", strings.NewReader(`{"level_id": 7, "difficulty": "Seedling"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var submitted Job
+	err = json.NewDecoder(res.Body).Decode(&submitted)
+	_ = res.Body.Close()
+	if err != nil || res.StatusCode != http.StatusAccepted || submitted.ID == "" {
+		t.Fatalf("submit: status %d, %+v, %v", res.StatusCode, submitted, err)
+	}
+
+	res, err = http.Get(srv.URL + "/v1/jobs/" + submitted.ID + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	var events int
+	var result *Job
+	scanner := bufio.NewScanner(res.Body)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var msg StreamMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			t.Fatalf("stream line %q: %v", scanner.Text(), err)
+		}
+		switch msg.Type {
+		case "event":
+			events++
+		case "result":
+			result = msg.Job
+		}
+	}
+	if result == nil || result.Status != StatusSucceeded || result.Level == nil || result.Level.ID != 7 {
+		t.Fatalf("result = %+v", result)
+	}
+	if events == 0 || result.Events != events || result.Stats.Attempts == 0 {
+		t.Errorf("streamed %d events, job reports %d and stats %+v", events, result.Events, result.Stats)
+	}
+
+	// The finished job can be fetched again and no longer cancelled
+	if j, ok := svc.Get(submitted.ID); !ok || j.Level == nil {
+		t.Errorf("Get after finishing = %+v, %v", j, ok)
+	}
+	req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/v1/jobs/"+submitted.ID, nil)
+	if res, err := http.DefaultClient.Do(req); err != nil || res.StatusCode != http.StatusConflict {
+		t.Errorf("cancel finished job: %v %v", res.StatusCode, err)
+	}
+}
+
+func TestSubmitRejects(t *testing.T) {
+	// No workers run, so submitted jobs stay queued
+	svc := New(Options{QueueSize: 1, Retain: 1})
+	for body, want := range map[string]string{
+		`{"level_id": 0}`:                                  "level_id",
+		`{"level_id": 3, "strategy": "nope"}`:              "nope",
+		`{"level_id": 3, "constraints": ["vines > lots"]}`: "constraint",
+	} {
+		var req JobRequest
+		if err := json.Unmarshal([]byte(body), &req); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := svc.Submit(req); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: error %v, want %q", body, err, want)
+		}
+	}
+
+	first, err := svc.Submit(JobRequest{LevelID: 3})
+	if err != nil || first.Request.Difficulty == "" {
+		t.Fatalf("Submit = %+v, %v; want the tier filled in", first, err)
+	}
+	if _, err := svc.Submit(JobRequest{LevelID: 4}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("second Submit: %v, want ErrQueueFull", err)
+	}
+	if !svc.Cancel(first.ID) || svc.Cancel(first.ID) {
+		t.Error("a queued job should cancel exactly once")
+	}
+	if j, _ := svc.Get(first.ID); j.Status != StatusCancelled {
+		t.Errorf("cancelled job = %+v", j)
+	}
+
+	// A worker skips the cancelled job left in the queue
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	svc.run(ctx, <-svc.queue)
+	if j, _ := svc.Get(first.ID); j.Status != StatusCancelled || j.Started != nil {
+		t.Errorf("run started a cancelled job: %+v", j)
+	}
+}
@@ -0,0 +1,117 @@
+// Package explore provides design-exploration helpers that regenerate the same
+// level under varied settings and collect comparable metrics, so designers can
+// see how a knob trades off against solver effort and structure.
+package explore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchsvc "github.com/eng618/parable-bloom/tools/level-builder/pkg/batch"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/utils"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+// DefaultCoverages are the MinCoverage settings compared when none are given.
+var DefaultCoverages = []float64{0.85, 0.90, 0.95, 1.0}
+
+// CoverageOptions configures a coverage sweep.
+type CoverageOptions struct {
+	LevelID    int
+	Difficulty string
+	Seed       int64 // 0 uses the batch default seed for LevelID
+	Strategy   string
+	Coverages  []float64
+	MaxStates  int
+}
+
+// CoverageSample holds the outcome of generating the level at one MinCoverage.
+type CoverageSample struct {
+	MinCoverage       float64
+	Level             model.Level
+	Coverage          float64 // achieved vine coverage (0.0-1.0)
+	VineCount         int
+	Solvable          bool
+	Solver            string
+	StatesExplored    int
+	GaveUp            bool
+	MaxBlockingDepth  int
+	PlacementAttempts int
+	Backtracks        int
+	GenerationTime    time.Duration
+	Error             string
+}
+
+// ExploreCoverage generates the configured level once per coverage setting,
+// holding seed, difficulty and strategy fixed, and returns one sample each.
+// Per-sample failures are recorded on the sample rather than aborting the sweep;
+// cancelling ctx aborts it with ctx.Err().
+func ExploreCoverage(ctx context.Context, opts CoverageOptions) ([]CoverageSample, error) {
+	coverages := opts.Coverages
+	if len(coverages) == 0 {
+		coverages = DefaultCoverages
+	}
+	maxStates := opts.MaxStates
+	if maxStates <= 0 {
+		maxStates = 500000
+	}
+
+	samples := make([]CoverageSample, 0, len(coverages))
+	for _, cov := range coverages {
+		if cov <= 0 || cov > 1.0 {
+			return nil, fmt.Errorf("invalid coverage %v (must be in (0, 1])", cov)
+		}
+
+		cfg, err := batchsvc.BuildGenerationConfig(opts.LevelID, opts.Difficulty, batchsvc.Config{
+			MinCoverage: cov,
+			Strategy:    opts.Strategy,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if opts.Seed != 0 {
+			cfg.Seed = opts.Seed
+		}
+		cfg.OutputFile = ""
+
+		sample := CoverageSample{MinCoverage: cov}
+		level, stats, err := generator.GenerateRobust(ctx, cfg)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		sample.GenerationTime = stats.GenerationTime
+		sample.PlacementAttempts = stats.PlacementAttempts
+		sample.Backtracks = stats.BacktracksAttempted
+		if err != nil {
+			sample.Error = err.Error()
+			samples = append(samples, sample)
+			continue
+		}
+
+		sample.Level = level
+		sample.VineCount = len(level.Vines)
+		if total := level.GetTotalCells(); total > 0 {
+			sample.Coverage = float64(level.GetOccupiedCells()) / float64(total)
+		}
+		sample.MaxBlockingDepth = utils.MaxBlockingDepth(utils.BuildLevelBlockingGraph(&level))
+
+		ok, solveStats, err := validator.IsSolvableContext(ctx, level, maxStates)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		sample.Solvable = ok
+		sample.Solver = solveStats.Solver
+		sample.StatesExplored = solveStats.StatesExplored
+		sample.GaveUp = solveStats.GaveUp
+		if err != nil {
+			sample.Error = err.Error()
+		}
+
+		samples = append(samples, sample)
+	}
+
+	return samples, nil
+}
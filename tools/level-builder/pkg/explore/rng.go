@@ -0,0 +1,481 @@
+package explore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/analyze"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/levelgen"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// RNGTiers lists the difficulty tiers RNG checks by default, in report order.
+var RNGTiers = []string{"Seedling", "Sprout", "Nurturing", "Flourishing", "Transcendent"}
+
+// LengthAllowance is the share of levels whose mean vine length may fall
+// outside the tier's avg_length_range before the length check can flag a bias.
+const LengthAllowance = 0.05
+
+// RNGOptions configures RNG.
+type RNGOptions struct {
+	Difficulties []string // tiers to sample (default RNGTiers)
+	Samples      int      // levels generated per tier (default 500)
+	LevelID      int      // level ID used for grid sizing (default 1)
+	StartSeed    int64    // first seed per tier (0 = levelgen.DefaultSeed(LevelID))
+	Strategy     string   // placement strategy (default: batch default)
+	RNG          string   // random source (see package random; default math)
+	// AvalanchePairs is the number of seeds regenerated with one bit flipped
+	// (default Samples/10, at least 10)
+	AvalanchePairs int
+	Alpha          float64 // significance level (default 0.01)
+	Workers        int     // concurrent generations (default runtime.NumCPU())
+	// OnProgress, if set, is called after each generation with the number
+	// done so far. Calls are serialized.
+	OnProgress func(done, total int)
+}
+
+// SignificanceTest is the outcome of one statistical check.
+type SignificanceTest struct {
+	Name      string    `json:"name"`
+	Labels    []string  `json:"labels"`
+	Observed  []float64 `json:"observed"`
+	Expected  []float64 `json:"expected"`
+	Statistic float64   `json:"statistic"` // chi-square, or z for the avalanche check
+	DF        int       `json:"df,omitempty"`
+	PValue    float64   `json:"p_value"`
+	Biased    bool      `json:"biased"` // PValue below alpha in the direction the check looks for
+	Note      string    `json:"note,omitempty"`
+}
+
+// TierRNGReport holds the checks of one tier.
+type TierRNGReport struct {
+	Difficulty string             `json:"difficulty"`
+	Generated  int                `json:"generated"`
+	Failed     int                `json:"failed"`
+	Lengths    map[int]int        `json:"vine_lengths"` // length -> vines
+	Tests      []SignificanceTest `json:"tests"`
+}
+
+// RNGReport is the result of RNG.
+type RNGReport struct {
+	Samples int             `json:"samples"`
+	Alpha   float64         `json:"alpha"`
+	Tiers   []TierRNGReport `json:"tiers"`
+}
+
+// Biases lists "tier: check" for every flagged test.
+func (r RNGReport) Biases() []string {
+	var out []string
+	for _, tier := range r.Tiers {
+		for _, t := range tier.Tests {
+			if t.Biased {
+				out = append(out, fmt.Sprintf("%s: %s", tier.Difficulty, t.Name))
+			}
+		}
+	}
+	return out
+}
+
+// RNG generates Samples levels per tier from consecutive seeds and tests the
+// output for statistically significant biases:
+//
+//   - head directions: chi-square of head counts (tail heads included)
+//     against an even split over the four directions;
+//   - vine lengths: chi-square of levels whose mean vine length falls outside
+//     the tier's avg_length_range, flagged only when they exceed
+//     LengthAllowance;
+//   - avalanche: levels from seeds one bit apart should differ as much as
+//     levels from unrelated seeds. A one-sided z-test flags seed pairs that
+//     stay more alike, measured as the share of cells whose covering head
+//     direction changes.
+//
+// Seeds that fail to generate are counted, not tested. Cancelling ctx aborts
+// with ctx.Err().
+func RNG(ctx context.Context, opts RNGOptions) (RNGReport, error) {
+	tiers := opts.Difficulties
+	if len(tiers) == 0 {
+		tiers = RNGTiers
+	}
+	samples := opts.Samples
+	if samples <= 0 {
+		samples = 500
+	}
+	pairs := opts.AvalanchePairs
+	if pairs <= 0 {
+		pairs = max(samples/10, 10)
+	}
+	pairs = min(pairs, samples)
+	alpha := opts.Alpha
+	if alpha <= 0 {
+		alpha = 0.01
+	}
+	levelID := opts.LevelID
+	if levelID <= 0 {
+		levelID = 1
+	}
+
+	report := RNGReport{Samples: samples, Alpha: alpha}
+	done, total := 0, len(tiers)*(samples+pairs)
+	progress := func() {
+		done++
+		if opts.OnProgress != nil {
+			opts.OnProgress(done, total)
+		}
+	}
+
+	for _, tier := range tiers {
+		spec, ok := config.DifficultySpecs[tier]
+		if !ok {
+			return RNGReport{}, fmt.Errorf("unknown difficulty: %s", tier)
+		}
+		cfg, err := levelgen.ConfigFor(levelgen.GenerateOptions{
+			LevelID:    levelID,
+			Difficulty: tier,
+			Strategy:   opts.Strategy,
+			RNG:        opts.RNG,
+		})
+		if err != nil {
+			return RNGReport{}, err
+		}
+		start := opts.StartSeed
+		if start == 0 {
+			start = levelgen.DefaultSeed(levelID)
+		}
+
+		seeds := make([]int64, 0, samples+pairs)
+		for i := 0; i < samples; i++ {
+			seeds = append(seeds, start+int64(i))
+		}
+		for i := 0; i < pairs; i++ {
+			seeds = append(seeds, (start+int64(i))^(1<<(i%62)))
+		}
+		levels, err := generateSeeds(ctx, cfg, seeds, opts.Workers, progress)
+		if err != nil {
+			return RNGReport{}, err
+		}
+
+		tr := TierRNGReport{Difficulty: tier, Lengths: make(map[int]int)}
+		var sampled []*model.Level
+		for _, l := range levels[:samples] {
+			if l == nil {
+				tr.Failed++
+				continue
+			}
+			tr.Generated++
+			sampled = append(sampled, l)
+			for _, v := range l.Vines {
+				tr.Lengths[len(v.OrderedPath)]++
+			}
+		}
+		tr.Tests = []SignificanceTest{
+			directionTest(sampled, alpha),
+			lengthTest(sampled, spec.AvgLengthRange, alpha),
+			avalancheTest(levels[:samples], levels[samples:], alpha),
+		}
+		report.Tiers = append(report.Tiers, tr)
+	}
+	return report, nil
+}
+
+// generateSeeds generates cfg once per seed; failed seeds leave nil.
+func generateSeeds(ctx context.Context, cfg config.GenerationConfig, seeds []int64, workers int, progress func()) ([]*model.Level, error) {
+	workers = common.WorkerCount(workers)
+	levels := make([]*model.Level, len(seeds))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				c := cfg
+				c.Seed = seeds[i]
+				level, _, err := generator.GenerateRobust(ctx, c)
+				mu.Lock()
+				if err == nil {
+					levels[i] = &level
+				}
+				progress()
+				mu.Unlock()
+			}
+		}()
+	}
+feed:
+	for i := range seeds {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return levels, ctx.Err()
+}
+
+// directionTest checks head directions against an even split.
+func directionTest(levels []*model.Level, alpha float64) SignificanceTest {
+	counts := make(map[string]float64)
+	heads := 0.0
+	for _, l := range levels {
+		for _, v := range l.Vines {
+			for _, h := range v.Heads() {
+				counts[h.HeadDirection]++
+				heads++
+			}
+		}
+	}
+	t := SignificanceTest{Name: "head direction balance", Labels: analyze.Directions, DF: len(analyze.Directions) - 1}
+	for _, dir := range analyze.Directions {
+		t.Observed = append(t.Observed, counts[dir])
+		t.Expected = append(t.Expected, heads/float64(len(analyze.Directions)))
+	}
+	if heads == 0 {
+		t.PValue, t.Note = 1, "no heads"
+		return t
+	}
+	t.Statistic = chiSquare(t.Observed, t.Expected)
+	t.PValue = ChiSquareSF(t.Statistic, t.DF)
+	t.Biased = t.PValue < alpha
+	return t
+}
+
+// lengthTest checks how many levels miss the tier's mean vine length band.
+func lengthTest(levels []*model.Level, band [2]int, alpha float64) SignificanceTest {
+	t := SignificanceTest{
+		Name:   "mean vine length vs avg_length_range",
+		Labels: []string{"in range", "outside"},
+		DF:     1,
+		Note:   fmt.Sprintf("range [%d, %d], %.0f%% allowed outside", band[0], band[1], LengthAllowance*100),
+	}
+	in, out := 0.0, 0.0
+	for _, l := range levels {
+		if len(l.Vines) == 0 {
+			continue
+		}
+		mean := float64(l.GetOccupiedCells()) / float64(len(l.Vines))
+		if mean >= float64(band[0]) && mean <= float64(band[1]) {
+			in++
+		} else {
+			out++
+		}
+	}
+	n := in + out
+	t.Observed = []float64{in, out}
+	t.Expected = []float64{n * (1 - LengthAllowance), n * LengthAllowance}
+	if n == 0 {
+		t.PValue, t.Note = 1, "no levels"
+		return t
+	}
+	t.Statistic = chiSquare(t.Observed, t.Expected)
+	t.PValue = ChiSquareSF(t.Statistic, t.DF)
+	// Fewer misses than allowed is no bias
+	t.Biased = out > t.Expected[1] && t.PValue < alpha
+	return t
+}
+
+// avalancheTest compares how much levels change between seeds one bit apart
+// (base[i] and flipped[i]) with how much unrelated levels differ (base[i] and
+// a sample from the other half of base).
+func avalancheTest(base, flipped []*model.Level, alpha float64) SignificanceTest {
+	var near, far []float64
+	for i, f := range flipped {
+		if base[i] != nil && f != nil {
+			near = append(near, cellDifference(base[i], f))
+		}
+	}
+	for i := range base {
+		j := (i + len(base)/2) % len(base)
+		if i != j && base[i] != nil && base[j] != nil {
+			far = append(far, cellDifference(base[i], base[j]))
+		}
+	}
+	t := SignificanceTest{
+		Name:   "seed avalanche",
+		Labels: []string{"one bit apart", "unrelated seeds"},
+		Note:   "mean share of cells whose covering head direction differs",
+	}
+	if len(near) < 2 || len(far) < 2 {
+		t.PValue, t.Note = 1, "too few generated pairs"
+		return t
+	}
+	mNear, vNear := meanVar(near)
+	mFar, vFar := meanVar(far)
+	t.Observed = []float64{mNear, mFar}
+	t.Expected = []float64{mFar, mFar}
+	se := math.Sqrt(vNear/float64(len(near)) + vFar/float64(len(far)))
+	if se == 0 {
+		t.PValue = 1
+		if mNear < mFar {
+			t.PValue = 0
+		}
+	} else {
+		t.Statistic = (mFar - mNear) / se
+		t.PValue = normalSF(t.Statistic)
+	}
+	t.Biased = t.PValue < alpha
+	return t
+}
+
+// cellDifference is the share of cells whose covering vine head direction
+// differs between a and b (empty cells count as their own value). Levels of
+// different sizes differ completely.
+func cellDifference(a, b *model.Level) float64 {
+	w, h := a.GetGridWidth(), a.GetGridHeight()
+	if w != b.GetGridWidth() || h != b.GetGridHeight() || w*h == 0 {
+		return 1
+	}
+	sa, sb := cellHeads(a), cellHeads(b)
+	diff := 0
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			p := model.Point{X: x, Y: y}
+			if sa[p] != sb[p] {
+				diff++
+			}
+		}
+	}
+	return float64(diff) / float64(w*h)
+}
+
+func cellHeads(l *model.Level) map[model.Point]string {
+	m := make(map[model.Point]string)
+	for _, v := range l.Vines {
+		for _, p := range v.OrderedPath {
+			m[p] = v.HeadDirection
+		}
+	}
+	return m
+}
+
+func chiSquare(observed, expected []float64) float64 {
+	x := 0.0
+	for i, o := range observed {
+		if expected[i] > 0 {
+			x += (o - expected[i]) * (o - expected[i]) / expected[i]
+		}
+	}
+	return x
+}
+
+func meanVar(xs []float64) (mean, variance float64) {
+	for _, x := range xs {
+		mean += x
+	}
+	mean /= float64(len(xs))
+	for _, x := range xs {
+		variance += (x - mean) * (x - mean)
+	}
+	return mean, variance / float64(len(xs)-1)
+}
+
+// normalSF is the upper tail of the standard normal distribution.
+func normalSF(z float64) float64 {
+	return 0.5 * math.Erfc(z/math.Sqrt2)
+}
+
+// ChiSquareSF returns P(X >= x) for a chi-square distribution with df degrees
+// of freedom, the p-value of a chi-square statistic.
+func ChiSquareSF(x float64, df int) float64 {
+	if x <= 0 || df <= 0 {
+		return 1
+	}
+	return gammaQ(float64(df)/2, x/2)
+}
+
+// gammaQ is the regularized upper incomplete gamma function Q(a, x), by
+// series below a+1 and by continued fraction above (Numerical Recipes 6.2).
+func gammaQ(a, x float64) float64 {
+	const (
+		maxIter = 500
+		eps     = 1e-14
+		tiny    = 1e-300
+	)
+	lg, _ := math.Lgamma(a)
+	if x < a+1 {
+		sum, term := 1/a, 1/a
+		for n := 1; n < maxIter; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*eps {
+				break
+			}
+		}
+		return 1 - sum*math.Exp(-x+a*math.Log(x)-lg)
+	}
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	f := d
+	for n := 1; n < maxIter; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		f *= delta
+		if math.Abs(delta-1) < eps {
+			break
+		}
+	}
+	return math.Exp(-x+a*math.Log(x)-lg) * f
+}
+
+// WriteText prints the report, one block per tier.
+func (r RNGReport) WriteText(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "%d samples per tier, alpha %.3g\n", r.Samples, r.Alpha)
+	for _, tier := range r.Tiers {
+		_, _ = fmt.Fprintf(w, "\n%s: %d generated, %d failed\n", tier.Difficulty, tier.Generated, tier.Failed)
+		lengths := make([]int, 0, len(tier.Lengths))
+		for l := range tier.Lengths {
+			lengths = append(lengths, l)
+		}
+		sort.Ints(lengths)
+		_, _ = fmt.Fprint(w, "  Vine lengths:")
+		for _, l := range lengths {
+			_, _ = fmt.Fprintf(w, " %d:%d", l, tier.Lengths[l])
+		}
+		_, _ = fmt.Fprintln(w)
+		for _, t := range tier.Tests {
+			mark := "✓"
+			if t.Biased {
+				mark = "⚠"
+			}
+			_, _ = fmt.Fprintf(w, "  %s %-38s p=%.4f", mark, t.Name, t.PValue)
+			for i, label := range t.Labels {
+				if i < len(t.Observed) {
+					_, _ = fmt.Fprintf(w, "  %s %.3g/%.3g", label, t.Observed[i], t.Expected[i])
+				}
+			}
+			_, _ = fmt.Fprintln(w)
+			if t.Note != "" {
+				_, _ = fmt.Fprintf(w, "      %s\n", t.Note)
+			}
+		}
+	}
+
+	_, _ = fmt.Fprintln(w)
+	if biases := r.Biases(); len(biases) > 0 {
+		_, _ = fmt.Fprintf(w, "⚠ %d significant biases:\n", len(biases))
+		for _, b := range biases {
+			_, _ = fmt.Fprintf(w, "  %s\n", b)
+		}
+		return
+	}
+	_, _ = fmt.Fprintf(w, "✓ No significant biases at alpha %.3g\n", r.Alpha)
+}
@@ -0,0 +1,72 @@
+package explore
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func TestChiSquareSF(t *testing.T) {
+	// Critical values from standard chi-square tables
+	tests := []struct {
+		x    float64
+		df   int
+		want float64
+	}{
+		{3.841, 1, 0.05},
+		{7.815, 3, 0.05},
+		{11.345, 3, 0.01},
+		{0.584, 3, 0.90},
+		{2, 2, math.Exp(-1)},
+	}
+	for _, tt := range tests {
+		if got := ChiSquareSF(tt.x, tt.df); math.Abs(got-tt.want) > 1e-3 {
+			t.Errorf("ChiSquareSF(%g, %d) = %.5f, want %.5f", tt.x, tt.df, got, tt.want)
+		}
+	}
+}
+
+func TestAvalancheFlagsSeedsThatBarelyChange(t *testing.T) {
+	levels := make([]*model.Level, 20)
+	for i := range levels {
+		// Alternate two boards so unrelated seeds differ
+		dir, path := "right", []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}
+		if i%4 >= 2 {
+			dir, path = "up", []model.Point{{X: 0, Y: 1}, {X: 0, Y: 0}}
+		}
+		levels[i] = &model.Level{ID: i, GridSize: []int{2, 2}, Vines: []model.Vine{{ID: "v1", HeadDirection: dir, OrderedPath: path}}}
+	}
+	// Flipping a bit reproduces the same board: no avalanche at all
+	if got := avalancheTest(levels, levels[:10], 0.01); !got.Biased {
+		t.Errorf("identical one-bit-apart levels not flagged: %+v", got)
+	}
+}
+
+func TestRNG(t *testing.T) {
+	report, err := RNG(context.Background(), RNGOptions{
+		Difficulties: []string{"Seedling"},
+		Samples:      12,
+		StartSeed:    100,
+		Workers:      2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Tiers) != 1 || len(report.Tiers[0].Tests) != 3 {
+		t.Fatalf("report = %+v, want one tier with three tests", report)
+	}
+	tier := report.Tiers[0]
+	if tier.Generated+tier.Failed != 12 {
+		t.Errorf("generated %d + failed %d, want 12 samples", tier.Generated, tier.Failed)
+	}
+	for _, test := range tier.Tests {
+		if test.PValue < 0 || test.PValue > 1 {
+			t.Errorf("%s p-value %g outside [0, 1]", test.Name, test.PValue)
+		}
+	}
+	if _, err := RNG(context.Background(), RNGOptions{Difficulties: []string{"Bogus"}}); err == nil {
+		t.Error("expected an unknown difficulty to be rejected")
+	}
+}
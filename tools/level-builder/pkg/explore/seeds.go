@@ -0,0 +1,192 @@
+package explore
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/metrics"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/levelgen"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// SeedSearchOptions configures a seed search.
+type SeedSearchOptions struct {
+	LevelID     int // level ID used for grid sizing and the default start seed
+	Difficulty  string
+	Strategy    string
+	MinCoverage float64 // 0 uses the generator default (full coverage)
+	StartSeed   int64   // first seed tried (0 = levelgen.DefaultSeed(LevelID))
+	Count       int     // seeds tried: StartSeed .. StartSeed+Count-1 (default 100)
+	Top         int     // candidates returned (default 10)
+	// TargetScore is the difficulty score candidates are ranked against.
+	// 0 uses the middle of the tier's score band.
+	TargetScore float64
+	MaxStates   int // solver budget for scoring (default metrics.DefaultScorerMaxStates)
+	Workers     int // concurrent generations (default runtime.NumCPU())
+	// OnProgress, if set, is called after each seed finishes with the number
+	// of seeds done. Calls are serialized.
+	OnProgress func(done, total int)
+}
+
+// SeedCandidate is one seed's generated level and how it scored.
+type SeedCandidate struct {
+	Seed     int64
+	Level    model.Level
+	Metrics  metrics.DifficultyMetrics
+	InBand   bool    // score inside the tier's band
+	Distance float64 // |score - target|
+	Coverage float64 // achieved vine coverage (0.0-1.0)
+}
+
+// SeedSearchResult summarizes a seed search.
+type SeedSearchResult struct {
+	Target     float64
+	Band       [2]float64 // tier score band; zero when the tier has none
+	Tried      int
+	Generated  int // seeds that produced a level
+	Solvable   int // generated levels the scorer could solve
+	InBand     int
+	Candidates []SeedCandidate // best first, at most Top
+}
+
+// SearchSeeds generates the configured level once per seed and ranks the
+// solvable results: levels inside the tier's score band first, then by how
+// close their difficulty score is to the target, then by coverage. Seeds that
+// fail to generate or solve are counted but not ranked. Cancelling ctx aborts
+// the search with ctx.Err().
+func SearchSeeds(ctx context.Context, opts SeedSearchOptions) (SeedSearchResult, error) {
+	spec, ok := config.DifficultySpecs[opts.Difficulty]
+	if !ok {
+		return SeedSearchResult{}, fmt.Errorf("unknown difficulty: %s", opts.Difficulty)
+	}
+	levelID := opts.LevelID
+	if levelID <= 0 {
+		levelID = 1
+	}
+	count := opts.Count
+	if count <= 0 {
+		count = 100
+	}
+	top := opts.Top
+	if top <= 0 {
+		top = 10
+	}
+	start := opts.StartSeed
+	if start == 0 {
+		start = levelgen.DefaultSeed(levelID)
+	}
+	workers := common.WorkerCount(opts.Workers)
+
+	result := SeedSearchResult{Band: spec.ScoreRange, Target: opts.TargetScore}
+	if result.Target == 0 {
+		result.Target = (spec.ScoreRange[0] + spec.ScoreRange[1]) / 2
+	}
+
+	baseCfg, err := levelgen.ConfigFor(levelgen.GenerateOptions{
+		LevelID:     levelID,
+		Difficulty:  opts.Difficulty,
+		Strategy:    opts.Strategy,
+		MinCoverage: opts.MinCoverage,
+	})
+	if err != nil {
+		return SeedSearchResult{}, err
+	}
+	scorer := metrics.DifficultyScorer{MaxStates: opts.MaxStates}
+
+	seeds := make(chan int64)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var candidates []SeedCandidate
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for seed := range seeds {
+				cfg := baseCfg
+				cfg.Seed = seed
+				level, _, genErr := generator.GenerateRobust(ctx, cfg)
+				var cand *SeedCandidate
+				scored := false
+				if genErr == nil && ctx.Err() == nil {
+					if m, err := scorer.Score(level); err == nil {
+						scored = true
+						cand = &SeedCandidate{
+							Seed:     seed,
+							Level:    level,
+							Metrics:  m,
+							InBand:   metrics.InScoreBand(opts.Difficulty, m.Score),
+							Distance: math.Abs(m.Score - result.Target),
+						}
+						if total := level.GetTotalCells(); total > 0 {
+							cand.Coverage = float64(level.GetOccupiedCells()) / float64(total)
+						}
+					}
+				}
+
+				mu.Lock()
+				result.Tried++
+				if genErr == nil {
+					result.Generated++
+				}
+				if scored {
+					result.Solvable++
+					if cand.InBand {
+						result.InBand++
+					}
+					candidates = append(candidates, *cand)
+				}
+				if opts.OnProgress != nil {
+					opts.OnProgress(result.Tried, count)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < count; i++ {
+		select {
+		case seeds <- start + int64(i):
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(seeds)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return SeedSearchResult{}, err
+	}
+
+	rankCandidates(candidates)
+	if len(candidates) > top {
+		candidates = candidates[:top]
+	}
+	result.Candidates = candidates
+	return result, nil
+}
+
+// rankCandidates orders candidates best first: in band, closest to the
+// target score, highest coverage, lowest seed.
+func rankCandidates(c []SeedCandidate) {
+	sort.Slice(c, func(i, j int) bool {
+		a, b := c[i], c[j]
+		if a.InBand != b.InBand {
+			return a.InBand
+		}
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		if a.Coverage != b.Coverage {
+			return a.Coverage > b.Coverage
+		}
+		return a.Seed < b.Seed
+	})
+}
@@ -0,0 +1,63 @@
+package explore
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRankCandidates(t *testing.T) {
+	c := []SeedCandidate{
+		{Seed: 1, InBand: false, Distance: 0.1},
+		{Seed: 2, InBand: true, Distance: 2, Coverage: 0.9},
+		{Seed: 3, InBand: true, Distance: 1},
+		{Seed: 4, InBand: true, Distance: 2, Coverage: 1.0},
+		{Seed: 5, InBand: true, Distance: 2, Coverage: 1.0},
+	}
+	rankCandidates(c)
+	want := []int64{3, 4, 5, 2, 1}
+	for i, seed := range want {
+		if c[i].Seed != seed {
+			t.Fatalf("rank %d = seed %d, want %d (order %v)", i, c[i].Seed, seed, c)
+		}
+	}
+}
+
+func TestSearchSeeds(t *testing.T) {
+	res, err := SearchSeeds(context.Background(), SeedSearchOptions{
+		Difficulty: "Seedling",
+		StartSeed:  100,
+		Count:      4,
+		Top:        2,
+		Workers:    2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Tried != 4 {
+		t.Errorf("tried = %d, want 4", res.Tried)
+	}
+	if res.Solvable > res.Generated || res.InBand > res.Solvable {
+		t.Errorf("inconsistent counts: %+v", res)
+	}
+	if len(res.Candidates) > 2 {
+		t.Fatalf("got %d candidates, want at most 2", len(res.Candidates))
+	}
+	for _, c := range res.Candidates {
+		if c.Seed < 100 || c.Seed >= 104 {
+			t.Errorf("seed %d outside searched range", c.Seed)
+		}
+		if c.Level.Seed != c.Seed {
+			t.Errorf("level seed %d, want %d", c.Level.Seed, c.Seed)
+		}
+	}
+	if len(res.Candidates) == 2 && res.Candidates[0].InBand == res.Candidates[1].InBand &&
+		res.Candidates[0].Distance > res.Candidates[1].Distance {
+		t.Errorf("candidates not ranked: %+v", res.Candidates)
+	}
+}
+
+func TestSearchSeedsUnknownDifficulty(t *testing.T) {
+	if _, err := SearchSeeds(context.Background(), SeedSearchOptions{Difficulty: "Nope"}); err == nil {
+		t.Error("expected error for unknown difficulty")
+	}
+}
@@ -0,0 +1,169 @@
+package fingerprint
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// DefaultThreshold is the similarity at or above which two distinct levels
+// are reported as near-duplicates.
+const DefaultThreshold = 0.9
+
+// Entry is a level and the file it was read from.
+type Entry struct {
+	File  string
+	Level *model.Level
+	Hash  string
+}
+
+// Group is a set of levels sharing one canonical form.
+type Group struct {
+	Hash    string
+	Entries []Entry
+}
+
+// Pair is two levels with different canonical forms whose similarity reached
+// the threshold. Symmetry is the transform of B that best matches A.
+type Pair struct {
+	A, B       Entry
+	Similarity float64
+	Symmetry   string
+}
+
+// Options tunes Find.
+type Options struct {
+	Threshold float64 // near-duplicate cutoff in (0, 1]; 0 means DefaultThreshold
+	// IncludeMirrors also reports levels that declare themselves the mirror
+	// (MirrorOf) of another scanned level. They are skipped by default because
+	// mirrors are shipped on purpose.
+	IncludeMirrors bool
+}
+
+// Report lists the duplicates found among Levels scanned levels.
+type Report struct {
+	Levels    int
+	Identical []Group
+	Near      []Pair
+}
+
+// Duplicates returns how many groups and pairs were flagged.
+func (r Report) Duplicates() int {
+	return len(r.Identical) + len(r.Near)
+}
+
+// Scan fingerprints every level_*.json in dir and looks for duplicates.
+func Scan(dir string, opts Options) (Report, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to read directory %s: %w", dir, err)
+	}
+
+	var levels []Entry
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), "level_") || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		level, err := common.ReadLevel(path)
+		if err != nil {
+			return Report{}, err
+		}
+		levels = append(levels, Entry{File: path, Level: level})
+	}
+	return Find(levels, opts), nil
+}
+
+// Find groups entries with identical canonical forms and pairs up distinct
+// levels whose Similarity meets the threshold. Entries are ordered by level ID
+// and their Hash is filled in.
+func Find(entries []Entry, opts Options) Report {
+	threshold := opts.Threshold
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+
+	entries = append([]Entry(nil), entries...)
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Level.ID < entries[j].Level.ID })
+
+	byHash := make(map[string][]Entry)
+	var hashes []string
+	for i := range entries {
+		entries[i].Hash = Hash(entries[i].Level)
+		h := entries[i].Hash
+		if _, seen := byHash[h]; !seen {
+			hashes = append(hashes, h)
+		}
+		byHash[h] = append(byHash[h], entries[i])
+	}
+
+	r := Report{Levels: len(entries)}
+	for _, h := range hashes {
+		group := byHash[h]
+		if !opts.IncludeMirrors {
+			group = dropMirrors(group)
+		}
+		if len(group) > 1 {
+			r.Identical = append(r.Identical, Group{Hash: h, Entries: group})
+		}
+	}
+
+	// Compare one representative per canonical form; identical levels would
+	// only repeat the same pairs.
+	for i := 0; i < len(hashes); i++ {
+		a := byHash[hashes[i]][0]
+		for j := i + 1; j < len(hashes); j++ {
+			b := byHash[hashes[j]][0]
+			if !opts.IncludeMirrors && mirrored(a.Level, b.Level) {
+				continue
+			}
+			if score, sym := Similarity(a.Level, b.Level); sym != "" && score >= threshold {
+				r.Near = append(r.Near, Pair{A: a, B: b, Similarity: score, Symmetry: sym})
+			}
+		}
+	}
+	sort.SliceStable(r.Near, func(i, j int) bool { return r.Near[i].Similarity > r.Near[j].Similarity })
+	return r
+}
+
+// dropMirrors removes entries whose MirrorOf source is also in the group.
+func dropMirrors(group []Entry) []Entry {
+	ids := make(map[int]bool, len(group))
+	for _, e := range group {
+		ids[e.Level.ID] = true
+	}
+	var kept []Entry
+	for _, e := range group {
+		if e.Level.MirrorOf != 0 && ids[e.Level.MirrorOf] {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	return kept
+}
+
+func mirrored(a, b *model.Level) bool {
+	return (a.MirrorOf != 0 && a.MirrorOf == b.ID) || (b.MirrorOf != 0 && b.MirrorOf == a.ID)
+}
+
+// WriteText prints identical groups and near-duplicate pairs.
+func WriteText(w io.Writer, r Report) {
+	for _, g := range r.Identical {
+		_, _ = fmt.Fprintf(w, "= identical [%s]\n", g.Hash)
+		for _, e := range g.Entries {
+			_, _ = fmt.Fprintf(w, "    level %d  %s\n", e.Level.ID, e.File)
+		}
+	}
+	for _, p := range r.Near {
+		_, _ = fmt.Fprintf(w, "~ %.0f%% similar (%s): level %d %s <-> level %d %s\n",
+			p.Similarity*100, p.Symmetry, p.A.Level.ID, p.A.File, p.B.Level.ID, p.B.File)
+	}
+	_, _ = fmt.Fprintf(w, "\n%d levels scanned, %d identical groups, %d near-duplicate pairs\n",
+		r.Levels, len(r.Identical), len(r.Near))
+}
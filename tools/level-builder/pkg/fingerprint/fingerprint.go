@@ -0,0 +1,213 @@
+// Package fingerprint reduces a level to a canonical form that ignores vine
+// order, vine IDs, colors and the grid's rotation or reflection, so levels
+// that play the same can be recognised as duplicates.
+package fingerprint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// Symmetry is one of the eight rotations and reflections of a grid. A point
+// (x, y) maps to (a*x + b*y, c*x + d*y), shifted back onto the grid.
+type Symmetry struct {
+	Name       string
+	a, b, c, d int
+}
+
+// Symmetries lists the dihedral group of the square, identity first. On a
+// non-square grid the transforms that swap axes also swap width and height.
+var Symmetries = []Symmetry{
+	{"identity", 1, 0, 0, 1},
+	{"rotate90", 0, -1, 1, 0},
+	{"rotate180", -1, 0, 0, -1},
+	{"rotate270", 0, 1, -1, 0},
+	{"mirror-horizontal", -1, 0, 0, 1},
+	{"mirror-vertical", 1, 0, 0, -1},
+	{"transpose", 0, 1, 1, 0},
+	{"anti-transpose", 0, -1, -1, 0},
+}
+
+// dims returns the grid size after applying s to a w x h grid.
+func (s Symmetry) dims(w, h int) (int, int) {
+	if s.a != 0 {
+		return w, h
+	}
+	return h, w
+}
+
+// point maps p on a w x h grid.
+func (s Symmetry) point(p model.Point, w, h int) model.Point {
+	x := s.a*p.X + s.b*p.Y
+	y := s.c*p.X + s.d*p.Y
+	if s.a < 0 {
+		x += w - 1
+	}
+	if s.b < 0 {
+		x += h - 1
+	}
+	if s.c < 0 {
+		y += w - 1
+	}
+	if s.d < 0 {
+		y += h - 1
+	}
+	return model.Point{X: x, Y: y}
+}
+
+// direction maps a head direction; unknown directions are kept as-is.
+func (s Symmetry) direction(dir string) string {
+	dx, dy := common.DeltaForDirection(dir)
+	if dx == 0 && dy == 0 {
+		return dir
+	}
+	return common.DirectionFromDelta(s.a*dx+s.b*dy, s.c*dx+s.d*dy)
+}
+
+// Canonical returns the level's canonical form: the lexicographically smallest
+// encoding of grid size, mask, vines (sorted, IDs and colors dropped) and
+// portals over all eight symmetries. Two levels with the same canonical form
+// are the same puzzle.
+func Canonical(level *model.Level) string {
+	best := ""
+	for i, s := range Symmetries {
+		if form := encode(level, s); i == 0 || form < best {
+			best = form
+		}
+	}
+	return best
+}
+
+// Hash returns a short hex digest of the level's canonical form.
+func Hash(level *model.Level) string {
+	sum := sha256.Sum256([]byte(Canonical(level)))
+	return hex.EncodeToString(sum[:8])
+}
+
+func encode(level *model.Level, s Symmetry) string {
+	w, h := level.GetGridWidth(), level.GetGridHeight()
+	tw, th := s.dims(w, h)
+
+	vines := make([]string, len(level.Vines))
+	for i, v := range level.Vines {
+		vines[i] = encodeVine(v, s, w, h)
+		if v.IsMultiHead() {
+			// Either end can be listed first; keep the smaller encoding
+			if rev := encodeVine(v.Reversed(), s, w, h); rev < vines[i] {
+				vines[i] = rev
+			}
+		}
+	}
+	sort.Strings(vines)
+
+	mask := "none"
+	if level.Mask != nil && len(level.Mask.Points) > 0 {
+		points := make([]string, len(level.Mask.Points))
+		for i, p := range level.Mask.Points {
+			points[i] = common.PointKey(s.point(p, w, h))
+		}
+		sort.Strings(points)
+		mask = level.Mask.Mode + ":" + strings.Join(points, " ")
+	}
+
+	form := fmt.Sprintf("%dx%d|%s|%s", tw, th, mask, strings.Join(vines, ";"))
+	if len(level.Portals) > 0 {
+		// Appended only when present so portal-free forms are unchanged
+		pairs := make([]string, len(level.Portals))
+		for i, pt := range level.Portals {
+			a, b := common.PointKey(s.point(pt.A, w, h)), common.PointKey(s.point(pt.B, w, h))
+			if b < a {
+				a, b = b, a
+			}
+			pairs[i] = a + "~" + b
+		}
+		sort.Strings(pairs)
+		form += "|portals:" + strings.Join(pairs, " ")
+	}
+	return form
+}
+
+// encodeVine writes v's head direction (plus tail direction on multi-head
+// vines and the lock on locked ones) and cells under s.
+func encodeVine(v model.Vine, s Symmetry, w, h int) string {
+	cells := make([]string, len(v.OrderedPath))
+	for j, p := range v.OrderedPath {
+		cells[j] = common.PointKey(s.point(p, w, h))
+	}
+	dir := s.direction(v.HeadDirection)
+	if v.IsMultiHead() {
+		dir += "+" + s.direction(v.TailDirection)
+	}
+	if v.LockedUntil > 0 {
+		dir += fmt.Sprintf("#%d", v.LockedUntil)
+	}
+	return dir + ":" + strings.Join(cells, " ")
+}
+
+// Similarity compares two levels cell by cell under every symmetry of b that
+// matches a's grid size. Each occupied cell contributes a feature recording the
+// direction of the vine covering it (and whether it is the head), and each
+// masked or portal cell contributes its own; the score is the Jaccard index of
+// the two feature sets, from 0 (nothing shared) to 1 (identical). It returns
+// the best score and the symmetry of b that achieved it.
+func Similarity(a, b *model.Level) (float64, string) {
+	fa := features(a, Symmetries[0])
+	best, bestSym := 0.0, ""
+	for _, s := range Symmetries {
+		tw, th := s.dims(b.GetGridWidth(), b.GetGridHeight())
+		if tw != a.GetGridWidth() || th != a.GetGridHeight() {
+			continue
+		}
+		if score := jaccard(fa, features(b, s)); bestSym == "" || score > best {
+			best, bestSym = score, s.Name
+		}
+	}
+	return best, bestSym
+}
+
+func features(level *model.Level, s Symmetry) map[string]bool {
+	w, h := level.GetGridWidth(), level.GetGridHeight()
+	set := make(map[string]bool)
+	for _, v := range level.Vines {
+		dir := s.direction(v.HeadDirection)
+		for j, p := range v.OrderedPath {
+			kind, cellDir := "body", dir
+			if j == 0 {
+				kind = "head"
+			} else if j == len(v.OrderedPath)-1 && v.IsMultiHead() {
+				kind, cellDir = "head", s.direction(v.TailDirection)
+			}
+			set[fmt.Sprintf("%s %s %s", common.PointKey(s.point(p, w, h)), kind, cellDir)] = true
+		}
+	}
+	if level.Mask != nil {
+		for _, p := range level.Mask.Points {
+			set[fmt.Sprintf("%s mask %s", common.PointKey(s.point(p, w, h)), level.Mask.Mode)] = true
+		}
+	}
+	for _, pt := range level.Portals {
+		for _, p := range []model.Point{pt.A, pt.B} {
+			set[common.PointKey(s.point(p, w, h))+" portal"] = true
+		}
+	}
+	return set
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	shared := 0
+	for k := range a {
+		if b[k] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
@@ -0,0 +1,200 @@
+package fingerprint
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func baseLevel(id int) *model.Level {
+	return &model.Level{
+		ID:       id,
+		GridSize: []int{5, 4},
+		Vines: []model.Vine{
+			{ID: "a", HeadDirection: "right", OrderedPath: []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}, ColorIndex: 1},
+			{ID: "b", HeadDirection: "up", OrderedPath: []model.Point{{X: 4, Y: 2}, {X: 4, Y: 1}, {X: 4, Y: 0}}},
+			{ID: "c", HeadDirection: "left", OrderedPath: []model.Point{{X: 1, Y: 3}, {X: 2, Y: 3}, {X: 3, Y: 3}}},
+			{ID: "d", HeadDirection: "down", OrderedPath: []model.Point{{X: 2, Y: 1}, {X: 2, Y: 2}}},
+		},
+		Mask: &model.Mask{Mode: model.MaskModeHide, Points: []model.Point{{X: 0, Y: 3}}},
+	}
+}
+
+// transformed applies s to every vine and mask point of level.
+func transformed(level *model.Level, s Symmetry) *model.Level {
+	w, h := level.GetGridWidth(), level.GetGridHeight()
+	tw, th := s.dims(w, h)
+	out := *level
+	out.GridSize = []int{tw, th}
+	out.Vines = make([]model.Vine, len(level.Vines))
+	for i, v := range level.Vines {
+		path := make([]model.Point, len(v.OrderedPath))
+		for j, p := range v.OrderedPath {
+			path[j] = s.point(p, w, h)
+		}
+		out.Vines[i] = model.Vine{ID: v.ID, HeadDirection: s.direction(v.HeadDirection), OrderedPath: path}
+	}
+	if level.Mask != nil {
+		mask := *level.Mask
+		mask.Points = nil
+		for _, p := range level.Mask.Points {
+			mask.Points = append(mask.Points, s.point(p, w, h))
+		}
+		out.Mask = &mask
+	}
+	return &out
+}
+
+func TestHashInvariantUnderSymmetry(t *testing.T) {
+	base := baseLevel(1)
+	want := Hash(base)
+	for _, s := range Symmetries {
+		got := transformed(base, s)
+		if got.GetGridWidth() != base.GetGridWidth() && got.GetGridWidth() != base.GetGridHeight() {
+			t.Fatalf("%s: unexpected grid %v", s.Name, got.GridSize)
+		}
+		if h := Hash(got); h != want {
+			t.Errorf("%s: hash %s, want %s", s.Name, h, want)
+		}
+	}
+
+	mirror, err := common.MirrorLevel(base, 2, common.MirrorVertical)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Hash(&mirror) != want {
+		t.Errorf("MirrorLevel output should share the source's hash")
+	}
+}
+
+func TestHashIgnoresVineOrderIDsAndColors(t *testing.T) {
+	a, b := baseLevel(1), baseLevel(2)
+	b.Vines[0], b.Vines[3] = b.Vines[3], b.Vines[0]
+	for i := range b.Vines {
+		b.Vines[i].ID = strings.ToUpper(b.Vines[i].ID)
+		b.Vines[i].ColorIndex = 7
+	}
+	if Hash(a) != Hash(b) {
+		t.Errorf("reordered/renamed vines should not change the hash")
+	}
+
+	b.Vines[0].HeadDirection = "up"
+	if Hash(a) == Hash(b) {
+		t.Errorf("a changed head direction should change the hash")
+	}
+	c := baseLevel(3)
+	c.Mask = nil
+	if Hash(a) == Hash(c) {
+		t.Errorf("removing the mask should change the hash")
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	a := baseLevel(1)
+	if score, _ := Similarity(a, transformed(a, Symmetries[2])); score != 1 {
+		t.Errorf("rotated copy similarity = %v, want 1", score)
+	}
+
+	b := baseLevel(2)
+	b.Vines[2].OrderedPath = b.Vines[2].OrderedPath[:2] // drop one tail cell
+	score, sym := Similarity(a, b)
+	if score >= 1 || score < 0.9 || sym != "identity" {
+		t.Errorf("one-cell change: similarity %v via %s", score, sym)
+	}
+
+	wide := baseLevel(3)
+	wide.GridSize = []int{6, 6}
+	if _, sym := Similarity(a, wide); sym != "" {
+		t.Errorf("levels with incompatible grids should not be compared, got %s", sym)
+	}
+}
+
+func TestFindGroupsAndPairs(t *testing.T) {
+	mirror, err := common.MirrorLevel(baseLevel(1), 4, common.MirrorHorizontal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	copyOf := transformed(baseLevel(2), Symmetries[1])
+	near := baseLevel(3)
+	near.Vines[2].OrderedPath = near.Vines[2].OrderedPath[:2]
+
+	entries := []Entry{
+		{File: "level_4.json", Level: &mirror},
+		{File: "level_3.json", Level: near},
+		{File: "level_2.json", Level: copyOf},
+		{File: "level_1.json", Level: baseLevel(1)},
+	}
+
+	r := Find(entries, Options{})
+	if r.Levels != 4 {
+		t.Fatalf("Levels = %d", r.Levels)
+	}
+	if len(r.Identical) != 1 || len(r.Identical[0].Entries) != 2 ||
+		r.Identical[0].Entries[0].Level.ID != 1 || r.Identical[0].Entries[1].Level.ID != 2 {
+		t.Fatalf("expected levels 1 and 2 grouped without the declared mirror, got %+v", r.Identical)
+	}
+	if len(r.Near) != 1 || r.Near[0].A.Level.ID != 1 || r.Near[0].B.Level.ID != 3 {
+		t.Fatalf("expected one near pair 1<->3, got %+v", r.Near)
+	}
+
+	r = Find(entries, Options{IncludeMirrors: true})
+	if len(r.Identical) != 1 || len(r.Identical[0].Entries) != 3 {
+		t.Errorf("expected the mirror in the group with IncludeMirrors, got %+v", r.Identical)
+	}
+
+	if r := Find(entries, Options{Threshold: 1}); len(r.Near) != 0 {
+		t.Errorf("threshold 1 should only report identical levels, got %+v", r.Near)
+	}
+}
+
+func TestScanAndWriteText(t *testing.T) {
+	dir := t.TempDir()
+	for _, l := range []*model.Level{baseLevel(1), transformed(baseLevel(2), Symmetries[4])} {
+		if err := common.WriteLevel(common.GetLevelFilePath(l.ID, dir), l, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := Scan(dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Duplicates() != 1 {
+		t.Fatalf("expected one duplicate group, got %+v", r)
+	}
+
+	var buf bytes.Buffer
+	WriteText(&buf, r)
+	out := buf.String()
+	if !strings.Contains(out, "= identical") || !strings.Contains(out, filepath.Join(dir, "level_2.json")) {
+		t.Errorf("unexpected report:\n%s", out)
+	}
+	if !strings.Contains(out, "2 levels scanned, 1 identical groups, 0 near-duplicate pairs") {
+		t.Errorf("missing summary:\n%s", out)
+	}
+}
+
+func TestHashMultiHeadVineOrientation(t *testing.T) {
+	level := baseLevel(1)
+	single := Hash(level)
+
+	mh, err := level.Vines[1].WithTailHead("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	level.Vines[1] = mh
+	multi := Hash(level)
+	if multi == single {
+		t.Error("adding a tail head should change the hash")
+	}
+
+	// Listing the path from the other end describes the same vine
+	level.Vines[1] = mh.Reversed()
+	if got := Hash(level); got != multi {
+		t.Errorf("reversed multi-head vine hash %s, want %s", got, multi)
+	}
+}
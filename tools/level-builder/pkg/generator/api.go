@@ -1,7 +1,7 @@
 package generator
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,27 +12,28 @@ import (
 )
 
 // GenerateLevel is now a wrapper for GenerateRobust.
-func GenerateLevel(cfg config.GenerationConfig) (model.Level, config.GenerationStats, error) {
-	level, stats, err := GenerateRobust(cfg)
+func GenerateLevel(ctx context.Context, cfg config.GenerationConfig) (model.Level, config.GenerationStats, error) {
+	level, stats, err := GenerateRobust(ctx, cfg)
 	if err != nil {
 		return level, stats, err
 	}
-	if err := writeLevelToFile(level, cfg); err != nil {
+	if err := WriteLevelFile(level, cfg); err != nil {
 		return level, stats, err
 	}
 	return level, stats, nil
 }
 
 // GenerateLevelLIFO is now a wrapper for GenerateRobust.
-func GenerateLevelLIFO(cfg config.GenerationConfig) (model.Level, config.GenerationStats, error) {
+func GenerateLevelLIFO(ctx context.Context, cfg config.GenerationConfig) (model.Level, config.GenerationStats, error) {
 	if cfg.Strategy == "" {
 		cfg.Strategy = config.StrategyCenterOut
 	}
-	return GenerateLevel(cfg) // Both use the robust pipeline now
+	return GenerateLevel(ctx, cfg) // Both use the robust pipeline now
 }
 
-// writeLevelToFile writes the level to JSON file
-func writeLevelToFile(level model.Level, cfg config.GenerationConfig) error {
+// WriteLevelFile writes the level to cfg.OutputFile (or its default path),
+// refusing to replace an existing file unless cfg.Overwrite is set.
+func WriteLevelFile(level model.Level, cfg config.GenerationConfig) error {
 	outputPath := cfg.OutputFile
 	if outputPath == "" {
 		var err error
@@ -55,18 +56,16 @@ func writeLevelToFile(level model.Level, cfg config.GenerationConfig) error {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	// Write JSON
-	file, err := os.Create(outputPath)
+	data, err := common.MarshalCanonical(level, common.LevelIndent)
 	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-	defer func() { _ = file.Close() }()
-
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(level); err != nil {
 		return fmt.Errorf("failed to encode JSON: %w", err)
 	}
+	if err := common.BackupBeforeOverwrite(outputPath); err != nil {
+		return fmt.Errorf("failed to back up %s: %w", outputPath, err)
+	}
+	if err := common.WriteFileAtomic(outputPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputPath, err)
+	}
 
 	common.Info("Wrote level file: %s", outputPath)
 	return nil
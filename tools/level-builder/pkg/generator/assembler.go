@@ -3,10 +3,18 @@ package generator
 import (
 	"fmt"
 
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/metrics"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/utils"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/naming"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
 )
 
+// solveMaxStates bounds the solver search behind ApplySolution.
+const solveMaxStates = 1000000
+
 // LevelAssembler implements LevelAssembler for all difficulty tiers
 type LevelAssembler struct{}
 
@@ -37,16 +45,15 @@ func (a *LevelAssembler) AssembleLevel(cfg config.GenerationConfig, vines []mode
 	}
 
 	for i, v := range vines {
-		modelVines[i] = model.Vine{
-			ID:            v.ID,
-			HeadDirection: v.HeadDirection,
-			OrderedPath:   convertCommonPointsToModel(v.OrderedPath),
-			ColorIndex:    i % colorCount, // 0-based, round-robin assignment
-		}
+		v.OrderedPath = convertCommonPointsToModel(v.OrderedPath)
+		modelVines[i] = v
 	}
 
-	// Generate color scheme using shared palette
+	// Generate color scheme using shared palette, then color touching vines apart
 	colorScheme := a.generateColorScheme(colorCount)
+	if conflicts := utils.AssignColors(modelVines, len(colorScheme), nil); conflicts > 0 {
+		common.Verbose("%d touching vine pairs share a color; the palette has only %d colors", conflicts, len(colorScheme))
+	}
 
 	// Create mask in model format
 	var modelMask *model.Mask
@@ -57,35 +64,83 @@ func (a *LevelAssembler) AssembleLevel(cfg config.GenerationConfig, vines []mode
 		}
 	}
 
-	// Estimate min moves (conservative)
+	// Estimate min moves until ApplySolution replaces it with the solver's count
 	minMoves := len(vines)
 	if minMoves < 1 {
 		minMoves = 1
 	}
+	maxMoves := cfg.MaxMoves
+	if maxMoves <= 0 {
+		maxMoves = config.MaxMovesFor(cfg.Difficulty, minMoves)
+	}
 
 	// Determine complexity based on difficulty tier
-	complexity := a.complexityForDifficulty(cfg.Difficulty)
+	complexity := ComplexityFor(cfg.Difficulty)
 
+	name := naming.Level(cfg.LevelID)
 	level := model.Level{
 		ID:          cfg.LevelID,
-		Name:        fmt.Sprintf("Level %d", cfg.LevelID),
+		Name:        naming.Render(name),
+		NameRef:     &name,
 		Difficulty:  cfg.Difficulty,
 		GridSize:    []int{cfg.GridWidth, cfg.GridHeight},
 		Vines:       modelVines,
-		MaxMoves:    cfg.MaxMoves,
+		MaxMoves:    maxMoves,
 		MinMoves:    minMoves,
 		Complexity:  complexity,
 		Grace:       spec.DefaultGrace,
 		ColorScheme: colorScheme,
 		Mask:        modelMask,
 		Seed:        seed,
+
+		GenerationProfile: cfg.Profile,
+		GenerationRNG:     cfg.RNG,
 	}
 
 	return level
 }
 
-// complexityForDifficulty maps difficulty tier to complexity string
-func (a *LevelAssembler) complexityForDifficulty(difficulty string) string {
+// ApplySolution solves the finished level and derives its move budget from
+// the solution: MinMoves becomes the solution length and, unless cfg.MaxMoves
+// sets one, MaxMoves comes from config.MaxMovesFor. A configured budget
+// below the solution length is kept, with a warning, and fails validation.
+// Grace scales with the decisions along the solution (see
+// metrics.GraceBasisFor), and the first cfg.HintCount vines of the solution
+// become the level's hints. The whole solution is embedded as
+// SolutionOrder, so validation and hints replay it instead of solving again.
+// It must run after portals and locks are in place, since both change which
+// orders are valid.
+func (a *LevelAssembler) ApplySolution(level *model.Level, cfg config.GenerationConfig) error {
+	ok, solution, _, err := validator.Solve(*level, solveMaxStates)
+	if err != nil {
+		return fmt.Errorf("failed to solve level %d: %w", level.ID, err)
+	}
+	if !ok {
+		return fmt.Errorf("level %d is not solvable", level.ID)
+	}
+	level.SolutionOrder = solution
+
+	level.MinMoves = len(solution)
+	level.MaxMoves = cfg.MaxMoves
+	if level.MaxMoves <= 0 {
+		level.MaxMoves = config.MaxMovesFor(level.Difficulty, level.MinMoves)
+	} else if level.MaxMoves < level.MinMoves {
+		common.Warning("Level %d: configured max_moves %d is below the %d moves its solution takes", level.ID, level.MaxMoves, level.MinMoves)
+	}
+
+	basis := metrics.GraceBasisFor(*level, solution)
+	level.Grace = basis.Base + basis.Bonus
+	level.GraceBasis = &basis
+
+	if count := min(cfg.HintCount, len(solution)); count > 0 {
+		level.Hints = append([]string(nil), solution[:count]...)
+	}
+	return nil
+}
+
+// ComplexityFor maps a difficulty tier to the complexity string generated
+// levels carry.
+func ComplexityFor(difficulty string) string {
 	switch difficulty {
 	case "Tutorial":
 		return "simple"
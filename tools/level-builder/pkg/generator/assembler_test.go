@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+func TestGenerateRobustSolution(t *testing.T) {
+	cfg := config.GenerationConfig{
+		LevelID:     1,
+		GridWidth:   6,
+		GridHeight:  8,
+		VineCount:   8,
+		Seed:        31337,
+		MinCoverage: 1.0,
+		Difficulty:  "Seedling",
+		Strategy:    config.StrategyCenterOut,
+		HintCount:   3,
+		DumpDir:     t.TempDir(),
+	}
+	level, _, err := GenerateRobust(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("GenerateRobust: %v", err)
+	}
+	if len(level.Hints) != 3 {
+		t.Fatalf("got %d hints, want 3", len(level.Hints))
+	}
+	if level.MinMoves != len(level.Vines) {
+		t.Errorf("min_moves = %d, want %d", level.MinMoves, len(level.Vines))
+	}
+	if want := config.MaxMovesFor("Seedling", level.MinMoves); level.MaxMoves != want {
+		t.Errorf("max_moves = %d, want %d", level.MaxMoves, want)
+	}
+	if len(level.SolutionOrder) != len(level.Vines) {
+		t.Errorf("solution_order has %d of %d vines", len(level.SolutionOrder), len(level.Vines))
+	}
+	if errs := validator.ValidateStructural(level); len(errs) != 0 {
+		t.Errorf("hinted level failed validation: %v", errs)
+	}
+
+	cfg.HintCount = 0
+	cfg.MaxMoves = 100
+	level, _, _ = GenerateRobust(context.Background(), cfg)
+	if level.Hints != nil {
+		t.Errorf("expected no hints without HintCount, got %v", level.Hints)
+	}
+	if level.MaxMoves != 100 {
+		t.Errorf("max_moves = %d, want the configured 100", level.MaxMoves)
+	}
+}
+
+func TestApplySolutionKeepsConfiguredBudget(t *testing.T) {
+	a, _ := model.NewVine("vine_1", []model.Point{{X: 0, Y: 0}, {X: 1, Y: 0}}, "")
+	b, _ := model.NewVine("vine_2", []model.Point{{X: 0, Y: 1}, {X: 1, Y: 1}}, "")
+	level := model.Level{ID: 1, Difficulty: "Seedling", GridSize: []int{2, 2}, Vines: []model.Vine{a, b}}
+
+	// A budget pinned below the solution length is not raised behind the
+	// user's back; validation reports it instead
+	if err := (&LevelAssembler{}).ApplySolution(&level, config.GenerationConfig{MaxMoves: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if level.MinMoves != 2 || level.MaxMoves != 1 {
+		t.Errorf("min_moves %d, max_moves %d; want 2 and the configured 1", level.MinMoves, level.MaxMoves)
+	}
+	if errs := validator.ValidateStructural(level); len(errs) == 0 {
+		t.Error("a budget below the solution length passed validation")
+	}
+}
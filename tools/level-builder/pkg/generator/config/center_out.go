@@ -0,0 +1,77 @@
+package config
+
+import "fmt"
+
+// Center-out seeding patterns (see CenterOutTuning.SeedPattern).
+const (
+	// SeedPatternCenter draws every seed from the cells nearest the center
+	SeedPatternCenter = "center"
+	// SeedPatternRoundRobin sweeps the central cells top-down, bottom-up,
+	// left-right and right-left in turn, one vine per sweep
+	SeedPatternRoundRobin = "round-robin"
+)
+
+// CenterOutTuning shapes where the center-out placer seeds vines and how
+// greedily it grows them. Zero fields fall back to the tier's tuning, then to
+// DefaultCenterOutTuning.
+type CenterOutTuning struct {
+	// CenterBias is how strongly seeds cluster at the center: each seed is
+	// drawn from the nearest 1-CenterBias share of the free cells
+	CenterBias float64 `json:"center_bias,omitempty" yaml:"center_bias"`
+	// SeedWindow is the fewest nearest cells a seed is drawn from
+	SeedWindow int `json:"seed_window,omitempty" yaml:"seed_window"`
+	// GrowthGreed is the chance a growing body takes its best-scored
+	// neighbor rather than a random one
+	GrowthGreed float64 `json:"growth_greed,omitempty" yaml:"growth_greed"`
+	// SeedPattern is SeedPatternCenter or SeedPatternRoundRobin
+	SeedPattern string `json:"seed_pattern,omitempty" yaml:"seed_pattern"`
+}
+
+// DefaultCenterOutTuning draws seeds from the nearest quarter of the free
+// cells (at least five) and takes the best growth cell 80% of the time.
+var DefaultCenterOutTuning = CenterOutTuning{
+	CenterBias:  0.75,
+	SeedWindow:  5,
+	GrowthGreed: 0.8,
+	SeedPattern: SeedPatternCenter,
+}
+
+// Or returns t with its zero fields taken from fallback.
+func (t CenterOutTuning) Or(fallback CenterOutTuning) CenterOutTuning {
+	if t.CenterBias == 0 {
+		t.CenterBias = fallback.CenterBias
+	}
+	if t.SeedWindow == 0 {
+		t.SeedWindow = fallback.SeedWindow
+	}
+	if t.GrowthGreed == 0 {
+		t.GrowthGreed = fallback.GrowthGreed
+	}
+	if t.SeedPattern == "" {
+		t.SeedPattern = fallback.SeedPattern
+	}
+	return t
+}
+
+// Validate checks that t's fields are in range.
+func (t CenterOutTuning) Validate() error {
+	switch {
+	case t.CenterBias < 0 || t.CenterBias >= 1:
+		return fmt.Errorf("center_bias %v must be in [0, 1)", t.CenterBias)
+	case t.SeedWindow < 0:
+		return fmt.Errorf("seed_window %d must not be negative", t.SeedWindow)
+	case t.GrowthGreed < 0 || t.GrowthGreed > 1:
+		return fmt.Errorf("growth_greed %v must be in [0, 1]", t.GrowthGreed)
+	}
+	switch t.SeedPattern {
+	case "", SeedPatternCenter, SeedPatternRoundRobin:
+		return nil
+	}
+	return fmt.Errorf("unknown seed_pattern %q (want %s or %s)", t.SeedPattern, SeedPatternCenter, SeedPatternRoundRobin)
+}
+
+// CenterOutFor returns the center-out tuning for cfg: its own fields, then
+// its tier's, then DefaultCenterOutTuning.
+func CenterOutFor(cfg GenerationConfig) CenterOutTuning {
+	return cfg.CenterOutTuning.Or(DifficultySpecs[cfg.Difficulty].CenterOut).Or(DefaultCenterOutTuning)
+}
@@ -0,0 +1,22 @@
+package config
+
+import "testing"
+
+func TestCenterOutForPrecedence(t *testing.T) {
+	restoreTables(t)
+	path := writeConfig(t, "config.yaml", "difficulty_specs:\n  Sprout:\n    center_out: { center_bias: 0.9, seed_pattern: round-robin }\n")
+	if err := Load(path); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	cfg := GenerationConfig{Difficulty: "Sprout"}
+	cfg.SeedPattern = SeedPatternCenter
+	got := CenterOutFor(cfg)
+	want := CenterOutTuning{CenterBias: 0.9, SeedWindow: 5, GrowthGreed: 0.8, SeedPattern: SeedPatternCenter}
+	if got != want {
+		t.Errorf("CenterOutFor = %+v, want %+v", got, want)
+	}
+	if got := CenterOutFor(GenerationConfig{Difficulty: "Seedling"}); got != DefaultCenterOutTuning {
+		t.Errorf("untuned tier = %+v, want the defaults", got)
+	}
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"fmt"
+	"math"
 	"time"
 )
 
@@ -24,89 +26,145 @@ type ValidationResult struct {
 
 // DifficultySpec defines constraints for a difficulty tier.
 type DifficultySpec struct {
-	VineCountRange   [2]int
-	AvgLengthRange   [2]int
-	MaxBlockingDepth int
-	ColorCountRange  [2]int
-	MinGridOccupancy float64
-	DefaultGrace     int
+	VineCountRange   [2]int  `yaml:"vine_count_range"`
+	AvgLengthRange   [2]int  `yaml:"avg_length_range"`
+	MaxBlockingDepth int     `yaml:"max_blocking_depth"`
+	ColorCountRange  [2]int  `yaml:"color_count_range"`
+	MinGridOccupancy float64 `yaml:"min_grid_occupancy"`
+	DefaultGrace     int     `yaml:"default_grace"`
+	// GraceDecisionsPerPoint adds one grace on top of DefaultGrace for every
+	// this many branching decisions in a level's solution (see GraceFor);
+	// zero gives every level DefaultGrace
+	GraceDecisionsPerPoint int `yaml:"grace_decisions_per_point"`
+	// MaxGraceBonus caps the grace GraceFor adds on top of DefaultGrace
+	MaxGraceBonus int `yaml:"max_grace_bonus"`
+	// MaxMovesMultiplier scales the solver's minimum move count into the
+	// level's max_moves budget (see MaxMovesFor)
+	MaxMovesMultiplier float64    `yaml:"max_moves_multiplier"`
+	ScoreRange         [2]float64 `yaml:"score_range"` // Accepted DifficultyScorer band; zero means unchecked
+	// BlockingDepthRange is the accepted span of a level's deepest blocker
+	// chain, checked on the finished level (fillers included); zero means
+	// unchecked. MaxBlockingDepth is what chain placement aims for.
+	BlockingDepthRange [2]int `yaml:"blocking_depth_range"`
+	// MultiHeadRatio is the fraction of vines given a second head at the tail
+	// (see model.Vine.TailDirection); zero keeps every vine single-headed
+	MultiHeadRatio float64 `yaml:"multi_head_ratio"`
+	// PortalPairs is the most portal pairs added when portals are enabled
+	// (GenerationConfig.Portals); zero means the tier never gets portals
+	PortalPairs int `yaml:"portal_pairs"`
+	// LockedRatio is the fraction of vines locked until other vines clear
+	// (see model.Vine.LockedUntil); zero leaves every vine unlocked
+	LockedRatio float64 `yaml:"locked_ratio"`
+	// DirBalanceTolerance is how far a head direction's share may exceed its
+	// target before placers steer new heads away from it (see
+	// utils.DirBalancer); zero leaves head directions unbalanced
+	DirBalanceTolerance float64 `yaml:"dir_balance_tolerance"`
+	// LengthDistribution shapes the target lengths placers draw for the
+	// tier's vines; with no shape each placer keeps its own length choice
+	LengthDistribution LengthDistribution `yaml:"length_distribution"`
+	// CenterOut tunes the center-out placer's seeding and growth for the
+	// tier; GenerationConfig fields override it
+	CenterOut CenterOutTuning `yaml:"center_out"`
 }
 
-// DifficultySpecs maps difficulty tier names to their specifications.
-var DifficultySpecs = map[string]DifficultySpec{
-	"Tutorial": {
-		VineCountRange:   [2]int{3, 8},
-		AvgLengthRange:   [2]int{6, 10}, // Moderate increase for longer, windier vines
-		MaxBlockingDepth: 0,
-		ColorCountRange:  [2]int{1, 5},
-		MinGridOccupancy: 0.30,
-		DefaultGrace:     3,
-	},
-	"Seedling": {
-		VineCountRange:   [2]int{4, 60},
-		AvgLengthRange:   [2]int{8, 12}, // Moderate increase
-		MaxBlockingDepth: 1,
-		ColorCountRange:  [2]int{1, 5},
-		MinGridOccupancy: 0.93,
-		DefaultGrace:     3,
-	},
-	"Sprout": {
-		VineCountRange:   [2]int{8, 80},
-		AvgLengthRange:   [2]int{8, 14}, // Moderate increase
-		MaxBlockingDepth: 2,
-		ColorCountRange:  [2]int{1, 5},
-		MinGridOccupancy: 0.93,
-		DefaultGrace:     3,
-	},
-	"Nurturing": {
-		VineCountRange:   [2]int{12, 100},
-		AvgLengthRange:   [2]int{8, 14}, // Moderate increase
-		MaxBlockingDepth: 3,
-		ColorCountRange:  [2]int{1, 6},
-		MinGridOccupancy: 0.93,
-		DefaultGrace:     3,
-	},
-	"Flourishing": {
-		VineCountRange:   [2]int{15, 150},
-		AvgLengthRange:   [2]int{10, 16}, // Moderate increase
-		MaxBlockingDepth: 4,
-		ColorCountRange:  [2]int{1, 6},
-		MinGridOccupancy: 0.93,
-		DefaultGrace:     3,
-	},
-	"Transcendent": {
-		VineCountRange:   [2]int{15, 200},
-		AvgLengthRange:   [2]int{12, 18}, // Moderate increase (still long but achievable)
-		MaxBlockingDepth: 4,
-		ColorCountRange:  [2]int{1, 6},
-		MinGridOccupancy: 0.93,
-		DefaultGrace:     4,
-	},
+// GridSizeRange bounds the grid dimensions of a difficulty tier.
+type GridSizeRange struct {
+	MinW int `yaml:"min_width"`
+	MinH int `yaml:"min_height"`
+	MaxW int `yaml:"max_width"`
+	MaxH int `yaml:"max_height"`
 }
 
-// ColorPalette defines the available vine colors.
-// Used for generating ColorScheme arrays in levels.
-var ColorPalette = []string{
-	"#888888", // default - Neutral gray
-	"#7CB342", // moss_green
-	"#FF9800", // sunset_orange
-	"#FFC107", // golden_yellow
-	"#7C4DFF", // royal_purple
-	"#29B6F6", // sky_blue
-	"#FF6E40", // coral_red
-	"#CDDC39", // lime_green
+// The tuning tables below start from the embedded difficulty_config.yaml and
+// can be overridden at run time with Load (the --config flag).
+var (
+	// DifficultySpecs maps difficulty tier names to their specifications.
+	DifficultySpecs map[string]DifficultySpec
+
+	// GridSizeRanges defines grid size ranges per difficulty tier.
+	GridSizeRanges map[string]GridSizeRange
+
+	// ColorPalette defines the available vine colors.
+	// Used for generating ColorScheme arrays in levels.
+	ColorPalette []string
+)
+
+// MinMaxMoves is the smallest max_moves budget MaxMovesFor hands out.
+const MinMaxMoves = 5
+
+// MaxMovesFor returns the max_moves budget for a level of the given difficulty
+// whose optimal solution takes minMoves moves: minMoves scaled by the tier's
+// MaxMovesMultiplier and rounded up, never below MinMaxMoves or minMoves.
+// Unknown tiers use a multiplier of 1.5.
+func MaxMovesFor(difficulty string, minMoves int) int {
+	multiplier := 1.5
+	if spec, ok := DifficultySpecs[difficulty]; ok && spec.MaxMovesMultiplier > 0 {
+		multiplier = spec.MaxMovesMultiplier
+	}
+	budget := int(math.Ceil(float64(minMoves) * multiplier))
+	return max(budget, minMoves, MinMaxMoves)
+}
+
+// GraceFor returns the grace for a level of the given difficulty whose
+// solution has decisions branching decisions: the tier's DefaultGrace plus one
+// per GraceDecisionsPerPoint decisions, at most MaxGraceBonus. bonus is the
+// part added on top of DefaultGrace. Unknown tiers get 3.
+func GraceFor(difficulty string, decisions int) (grace, bonus int) {
+	spec, ok := DifficultySpecs[difficulty]
+	if !ok {
+		return 3, 0
+	}
+	if spec.GraceDecisionsPerPoint > 0 {
+		bonus = min(decisions/spec.GraceDecisionsPerPoint, spec.MaxGraceBonus)
+	}
+	return spec.DefaultGrace + bonus, bonus
+}
+
+// DefaultCoverageTarget is the grid coverage placement aims for unless a
+// profile or an explicit override asks for less: every cell, per the design doc.
+const DefaultCoverageTarget = 1.0
+
+// Coverage is what a level must cover of its grid.
+type Coverage struct {
+	// Target is the share of cells placement aims to fill with vines
+	Target float64
+	// Minimum is the least vine occupancy the level may ship with: the floor
+	// placers that stop short of Target work to, and what validation checks
+	Minimum float64
+}
+
+// CoverageFor resolves the coverage for a level of the given difficulty. The
+// target is override when it is set (non-zero), else the profile's
+// MinCoverage, else DefaultCoverageTarget; an override outside [0, 1] is an
+// error. The minimum is the tier's MinGridOccupancy, so --config changes it
+// everywhere at once, but never above the target. Unknown tiers and profiles
+// get a 0.95 minimum and no profile target.
+func CoverageFor(difficulty, profile string, override float64) (Coverage, error) {
+	if override < 0 || override > 1 {
+		return Coverage{}, fmt.Errorf("invalid MinCoverage override: %v", override)
+	}
+	c := Coverage{Target: DefaultCoverageTarget, Minimum: 0.95}
+	if p, ok := GenerationProfiles[profile]; ok && p.MinCoverage > 0 {
+		c.Target = p.MinCoverage
+	}
+	if override > 0 {
+		c.Target = override
+	}
+	if spec, ok := DifficultySpecs[difficulty]; ok {
+		c.Minimum = spec.MinGridOccupancy
+	}
+	c.Minimum = min(c.Minimum, c.Target)
+	return c, nil
 }
 
-// GridSizeRanges defines grid size ranges per difficulty tier.
-var GridSizeRanges = map[string]struct {
-	MinW, MinH, MaxW, MaxH int
-}{
-	"Tutorial":     {MinW: 5, MinH: 8, MaxW: 9, MaxH: 12},
-	"Seedling":     {MinW: 6, MinH: 8, MaxW: 9, MaxH: 12},
-	"Sprout":       {MinW: 9, MinH: 12, MaxW: 12, MaxH: 16},
-	"Nurturing":    {MinW: 9, MinH: 16, MaxW: 12, MaxH: 20},
-	"Flourishing":  {MinW: 12, MinH: 20, MaxW: 16, MaxH: 24},
-	"Transcendent": {MinW: 16, MinH: 28, MaxW: 24, MaxH: 40},
+// BlockingDepthTarget returns the deepest blocker chain difficulty injection
+// works toward: the middle of BlockingDepthRange, or MaxBlockingDepth on tiers
+// that leave the range unchecked.
+func (s DifficultySpec) BlockingDepthTarget() int {
+	if s.BlockingDepthRange == [2]int{} {
+		return s.MaxBlockingDepth
+	}
+	return (s.BlockingDepthRange[0] + s.BlockingDepthRange[1] + 1) / 2
 }
 
 // VarietyProfile controls shape and distribution characteristics for generated levels.
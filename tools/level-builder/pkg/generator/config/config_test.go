@@ -50,3 +50,75 @@ func TestGridSizeRanges(t *testing.T) {
 		}
 	}
 }
+
+func TestMaxMovesFor(t *testing.T) {
+	tests := []struct {
+		difficulty string
+		minMoves   int
+		want       int
+	}{
+		{"Seedling", 8, 14},
+		{"Sprout", 7, 11},
+		{"Transcendent", 40, 48},
+		{"Tutorial", 2, 5},
+		{"Unknown", 10, 15},
+		{"Seedling", 0, 5},
+	}
+	for _, tt := range tests {
+		if got := MaxMovesFor(tt.difficulty, tt.minMoves); got != tt.want {
+			t.Errorf("MaxMovesFor(%q, %d) = %d, want %d", tt.difficulty, tt.minMoves, got, tt.want)
+		}
+	}
+}
+
+func TestCoverageFor(t *testing.T) {
+	tests := []struct {
+		difficulty, profile string
+		override            float64
+		want                Coverage
+	}{
+		{"Seedling", "", 0, Coverage{Target: 1, Minimum: 0.93}},
+		{"Tutorial", "", 0, Coverage{Target: 1, Minimum: 0.30}},
+		// The profile's target beats the default, and the minimum never exceeds it
+		{"Seedling", ProfileAesthetic, 0, Coverage{Target: 0.9, Minimum: 0.9}},
+		// An explicit override beats the profile
+		{"Nurturing", ProfileSpeedrun, 0.97, Coverage{Target: 0.97, Minimum: 0.93}},
+		{"Sprout", "", 0.5, Coverage{Target: 0.5, Minimum: 0.5}},
+		{"Unknown", "unknown", 0, Coverage{Target: 1, Minimum: 0.95}},
+	}
+	for _, tt := range tests {
+		got, err := CoverageFor(tt.difficulty, tt.profile, tt.override)
+		if err != nil || got != tt.want {
+			t.Errorf("CoverageFor(%q, %q, %v) = %+v, %v; want %+v", tt.difficulty, tt.profile, tt.override, got, err, tt.want)
+		}
+	}
+	for _, override := range []float64{-0.1, 1.5} {
+		if _, err := CoverageFor("Seedling", "", override); err == nil {
+			t.Errorf("CoverageFor override %v: expected an error", override)
+		}
+	}
+}
+
+func TestCoverageForFollowsConfigOverrides(t *testing.T) {
+	restoreTables(t)
+	path := writeConfig(t, "difficulty_config.yaml", `
+difficulty_specs:
+  Sprout:
+    min_grid_occupancy: 0.8
+`)
+	if err := Load(path); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got, _ := CoverageFor("Sprout", "", 0); got.Minimum != 0.8 {
+		t.Errorf("Sprout minimum = %v, want the loaded 0.8", got.Minimum)
+	}
+}
+
+func TestBlockingDepthTarget(t *testing.T) {
+	if got := (DifficultySpec{BlockingDepthRange: [2]int{4, 9}, MaxBlockingDepth: 4}).BlockingDepthTarget(); got != 7 {
+		t.Errorf("target with a range = %d, want 7", got)
+	}
+	if got := (DifficultySpec{MaxBlockingDepth: 3}).BlockingDepthTarget(); got != 3 {
+		t.Errorf("target without a range = %d, want MaxBlockingDepth 3", got)
+	}
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Length distribution shapes (see LengthDistribution.Shape).
+const (
+	LengthShapeNormal   = "normal"
+	LengthShapeBimodal  = "bimodal"
+	LengthShapePowerLaw = "power_law"
+)
+
+// LengthDistribution describes the curve vine target lengths are drawn from.
+// Every draw is clamped to [Min, Max] and to the placer's cap for the grid,
+// so a curve only has to describe the shape.
+type LengthDistribution struct {
+	// Shape is normal, bimodal or power_law; empty leaves lengths to the placer
+	Shape string `yaml:"shape"`
+	// Mean and StdDev describe the normal curve, or bimodal's short mode
+	Mean   float64 `yaml:"mean"`
+	StdDev float64 `yaml:"std_dev"`
+	// LongMean and LongStdDev describe bimodal's long mode, which LongShare
+	// of the vines are drawn from
+	LongMean   float64 `yaml:"long_mean"`
+	LongStdDev float64 `yaml:"long_std_dev"`
+	LongShare  float64 `yaml:"long_share"`
+	// Exponent is power_law's: a length's weight is length^-Exponent
+	Exponent float64 `yaml:"exponent"`
+	// Min and Max bound every length; zero means 2 and the placer's cap
+	Min int `yaml:"min"`
+	Max int `yaml:"max"`
+}
+
+// Enabled reports whether d has a shape, i.e. overrides the placer's lengths.
+func (d LengthDistribution) Enabled() bool {
+	return d.Shape != ""
+}
+
+// bounds returns the lengths d may produce under the placer cap limit.
+func (d LengthDistribution) bounds(limit int) (lo, hi int) {
+	lo, hi = max(2, d.Min), max(2, limit)
+	if d.Max > 0 {
+		hi = min(hi, d.Max)
+	}
+	return min(lo, hi), hi
+}
+
+// Sample draws one vine length from d, within its bounds and at most limit.
+func (d LengthDistribution) Sample(rng *rand.Rand, limit int) int {
+	lo, hi := d.bounds(limit)
+	var length float64
+	switch d.Shape {
+	case LengthShapeNormal:
+		length = d.Mean + rng.NormFloat64()*d.StdDev
+	case LengthShapeBimodal:
+		if rng.Float64() < d.LongShare {
+			length = d.LongMean + rng.NormFloat64()*d.LongStdDev
+		} else {
+			length = d.Mean + rng.NormFloat64()*d.StdDev
+		}
+	case LengthShapePowerLaw:
+		// Inverse transform over the discrete lengths lo..hi
+		r := rng.Float64() * d.powerLawWeight(lo, hi)
+		for l := lo; l <= hi; l++ {
+			if r -= math.Pow(float64(l), -d.Exponent); r < 0 {
+				return l
+			}
+		}
+		return hi
+	default:
+		return lo
+	}
+	return min(max(int(math.Round(length)), lo), hi)
+}
+
+// Expected returns the mean length Sample draws under limit, ignoring the
+// clamping of the normal tails. Placers size vine counts with it.
+func (d LengthDistribution) Expected(limit int) float64 {
+	lo, hi := d.bounds(limit)
+	var mean float64
+	switch d.Shape {
+	case LengthShapeNormal:
+		mean = d.Mean
+	case LengthShapeBimodal:
+		mean = (1-d.LongShare)*d.Mean + d.LongShare*d.LongMean
+	case LengthShapePowerLaw:
+		var sum float64
+		for l := lo; l <= hi; l++ {
+			sum += float64(l) * math.Pow(float64(l), -d.Exponent)
+		}
+		return sum / d.powerLawWeight(lo, hi)
+	}
+	return min(max(mean, float64(lo)), float64(hi))
+}
+
+func (d LengthDistribution) powerLawWeight(lo, hi int) float64 {
+	var total float64
+	for l := lo; l <= hi; l++ {
+		total += math.Pow(float64(l), -d.Exponent)
+	}
+	return total
+}
+
+// scaled returns d with its lengths multiplied by factor, for profiles that
+// scale a tier's vine lengths (see GenerationProfile.LengthScale).
+func (d LengthDistribution) scaled(factor float64) LengthDistribution {
+	d.Mean *= factor
+	d.StdDev *= factor
+	d.LongMean *= factor
+	d.LongStdDev *= factor
+	d.Min = int(math.Round(float64(d.Min) * factor))
+	d.Max = int(math.Round(float64(d.Max) * factor))
+	return d
+}
+
+// validate checks that d's parameters fit its shape.
+func (d LengthDistribution) validate() error {
+	switch {
+	case d.Min < 0 || d.Max < 0 || (d.Max > 0 && d.Max < max(2, d.Min)):
+		return fmt.Errorf("invalid bounds min %d, max %d", d.Min, d.Max)
+	case d.StdDev < 0 || d.LongStdDev < 0:
+		return fmt.Errorf("std_dev and long_std_dev must not be negative")
+	}
+	switch d.Shape {
+	case "":
+		return nil
+	case LengthShapeNormal:
+		if d.Mean < 2 {
+			return fmt.Errorf("normal needs a mean of at least 2, got %v", d.Mean)
+		}
+	case LengthShapeBimodal:
+		if d.Mean < 2 || d.LongMean < d.Mean {
+			return fmt.Errorf("bimodal needs 2 <= mean <= long_mean, got %v and %v", d.Mean, d.LongMean)
+		}
+		if d.LongShare <= 0 || d.LongShare >= 1 {
+			return fmt.Errorf("bimodal long_share %v must be in (0, 1)", d.LongShare)
+		}
+	case LengthShapePowerLaw:
+		if d.Exponent <= 0 {
+			return fmt.Errorf("power_law needs a positive exponent, got %v", d.Exponent)
+		}
+	default:
+		return fmt.Errorf("unknown shape %q (want %s, %s or %s)", d.Shape, LengthShapeNormal, LengthShapeBimodal, LengthShapePowerLaw)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestLengthDistributionSample(t *testing.T) {
+	tests := []struct {
+		name  string
+		dist  LengthDistribution
+		check func(counts map[int]int) bool
+	}{
+		{"normal", LengthDistribution{Shape: LengthShapeNormal, Mean: 6, StdDev: 1}, func(c map[int]int) bool {
+			return c[6] > c[3] && c[6] > c[9]
+		}},
+		{"bimodal", LengthDistribution{Shape: LengthShapeBimodal, Mean: 4, StdDev: 1, LongMean: 16, LongStdDev: 1, LongShare: 0.2}, func(c map[int]int) bool {
+			// Two peaks with a trough between them, the short one taller
+			return c[4] > c[16] && c[16] > c[10] && c[16] > 0
+		}},
+		{"power law", LengthDistribution{Shape: LengthShapePowerLaw, Exponent: 2}, func(c map[int]int) bool {
+			return c[2] > c[3] && c[3] > c[6] && c[6] > c[12]
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			counts := make(map[int]int)
+			for range 5000 {
+				l := tt.dist.Sample(rng, 20)
+				if l < 2 || l > 20 {
+					t.Fatalf("Sample = %d, want within [2, 20]", l)
+				}
+				counts[l]++
+			}
+			if !tt.check(counts) {
+				t.Errorf("unexpected shape: %v", counts)
+			}
+		})
+	}
+}
+
+func TestLengthDistributionBounds(t *testing.T) {
+	d := LengthDistribution{Shape: LengthShapeNormal, Mean: 10, StdDev: 8, Min: 4, Max: 12}
+	rng := rand.New(rand.NewSource(1))
+	for range 1000 {
+		if l := d.Sample(rng, 30); l < 4 || l > 12 {
+			t.Fatalf("Sample = %d, want within [4, 12]", l)
+		}
+		// The placer's cap wins over Max
+		if l := d.Sample(rng, 8); l > 8 {
+			t.Fatalf("Sample = %d above the cap 8", l)
+		}
+	}
+	if got := (LengthDistribution{Shape: LengthShapeBimodal, Mean: 4, LongMean: 14, LongShare: 0.25}).Expected(30); got != 6.5 {
+		t.Errorf("bimodal Expected = %v, want 6.5", got)
+	}
+}
@@ -0,0 +1,216 @@
+package config
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// DefaultDifficultyConfig is the embedded difficulty_config.yaml the tuning
+// tables start from.
+//
+//go:embed difficulty_config.yaml
+var DefaultDifficultyConfig []byte
+
+// DifficultyConfig is the layout of a difficulty config file.
+type DifficultyConfig struct {
+	DifficultySpecs map[string]DifficultySpec `yaml:"difficulty_specs"`
+	GridSizeRanges  map[string]GridSizeRange  `yaml:"grid_size_ranges"`
+	ColorPalette    []string                  `yaml:"color_palette"`
+}
+
+// difficultyOverrides holds a config file's tiers undecoded, so each can be
+// merged onto the current spec field by field.
+type difficultyOverrides struct {
+	DifficultySpecs map[string]yaml.Node `yaml:"difficulty_specs"`
+	GridSizeRanges  map[string]yaml.Node `yaml:"grid_size_ranges"`
+	ColorPalette    []string             `yaml:"color_palette"`
+}
+
+var hexColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+func init() {
+	cfg, err := mergeDifficultyConfig(DifficultyConfig{}, DefaultDifficultyConfig, true)
+	if err != nil {
+		panic(fmt.Sprintf("embedded difficulty_config.yaml: %v", err))
+	}
+	cfg.apply()
+}
+
+// Load reads a YAML or JSON difficulty config and merges it onto the current
+// tuning tables. Tiers and fields the file leaves out keep their values, and
+// a color_palette replaces the palette outright. Unknown keys and tiers are
+// errors, and nothing is changed unless the merged result is valid.
+func Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	current := DifficultyConfig{
+		DifficultySpecs: DifficultySpecs,
+		GridSizeRanges:  GridSizeRanges,
+		ColorPalette:    ColorPalette,
+	}
+	cfg, err := mergeDifficultyConfig(current, data, false)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	cfg.apply()
+	return nil
+}
+
+// mergeDifficultyConfig merges data onto a copy of base. New tiers are only
+// accepted when allowNewTiers is set, which the embedded defaults need.
+func mergeDifficultyConfig(base DifficultyConfig, data []byte, allowNewTiers bool) (DifficultyConfig, error) {
+	// YAML is a superset of JSON, so one decoder reads both. The typed pass
+	// rejects unknown keys and wrong types before anything is merged.
+	var strict DifficultyConfig
+	if err := decodeStrict(data, &strict); err != nil {
+		return base, err
+	}
+	var overrides difficultyOverrides
+	if err := decodeStrict(data, &overrides); err != nil {
+		return base, err
+	}
+
+	cfg := DifficultyConfig{
+		DifficultySpecs: maps.Clone(base.DifficultySpecs),
+		GridSizeRanges:  maps.Clone(base.GridSizeRanges),
+		ColorPalette:    slices.Clone(base.ColorPalette),
+	}
+	if cfg.DifficultySpecs == nil {
+		cfg.DifficultySpecs = make(map[string]DifficultySpec)
+	}
+	if cfg.GridSizeRanges == nil {
+		cfg.GridSizeRanges = make(map[string]GridSizeRange)
+	}
+	for tier, node := range overrides.DifficultySpecs {
+		spec, ok := cfg.DifficultySpecs[tier]
+		if !ok && !allowNewTiers {
+			return base, fmt.Errorf("difficulty_specs: unknown tier %q (want one of %s)", tier, tierList(base.DifficultySpecs))
+		}
+		if err := node.Decode(&spec); err != nil {
+			return base, fmt.Errorf("difficulty_specs.%s: %w", tier, err)
+		}
+		cfg.DifficultySpecs[tier] = spec
+	}
+	for tier, node := range overrides.GridSizeRanges {
+		sizes, ok := cfg.GridSizeRanges[tier]
+		if !ok && !allowNewTiers {
+			return base, fmt.Errorf("grid_size_ranges: unknown tier %q (want one of %s)", tier, tierList(base.DifficultySpecs))
+		}
+		if err := node.Decode(&sizes); err != nil {
+			return base, fmt.Errorf("grid_size_ranges.%s: %w", tier, err)
+		}
+		cfg.GridSizeRanges[tier] = sizes
+	}
+	if overrides.ColorPalette != nil {
+		cfg.ColorPalette = overrides.ColorPalette
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return base, err
+	}
+	return cfg, nil
+}
+
+func decodeStrict(data []byte, out any) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	// An empty file overrides nothing
+	if err := dec.Decode(out); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to parse difficulty config: %w", err)
+	}
+	return nil
+}
+
+// Validate checks that every tier has a grid size range and that ranges,
+// ratios and palette colors are usable by the generator.
+func (c DifficultyConfig) Validate() error {
+	if len(c.DifficultySpecs) == 0 {
+		return fmt.Errorf("difficulty_specs: no tiers defined")
+	}
+	for _, tier := range sortedTiers(c.DifficultySpecs) {
+		s := c.DifficultySpecs[tier]
+		switch {
+		case s.VineCountRange[0] < 1 || s.VineCountRange[0] > s.VineCountRange[1]:
+			return fmt.Errorf("difficulty_specs.%s: invalid vine_count_range %v", tier, s.VineCountRange)
+		case s.AvgLengthRange[0] < 2 || s.AvgLengthRange[0] > s.AvgLengthRange[1]:
+			return fmt.Errorf("difficulty_specs.%s: invalid avg_length_range %v", tier, s.AvgLengthRange)
+		case s.ColorCountRange[0] < 1 || s.ColorCountRange[0] > s.ColorCountRange[1]:
+			return fmt.Errorf("difficulty_specs.%s: invalid color_count_range %v", tier, s.ColorCountRange)
+		case s.ScoreRange[0] < 0 || s.ScoreRange[0] > s.ScoreRange[1]:
+			return fmt.Errorf("difficulty_specs.%s: invalid score_range %v", tier, s.ScoreRange)
+		case s.BlockingDepthRange[0] < 0 || s.BlockingDepthRange[0] > s.BlockingDepthRange[1]:
+			return fmt.Errorf("difficulty_specs.%s: invalid blocking_depth_range %v", tier, s.BlockingDepthRange)
+		case s.MinGridOccupancy <= 0 || s.MinGridOccupancy > 1:
+			return fmt.Errorf("difficulty_specs.%s: min_grid_occupancy %v must be in (0, 1]", tier, s.MinGridOccupancy)
+		case s.MaxMovesMultiplier != 0 && s.MaxMovesMultiplier < 1:
+			return fmt.Errorf("difficulty_specs.%s: max_moves_multiplier %v must be at least 1", tier, s.MaxMovesMultiplier)
+		case s.MultiHeadRatio < 0 || s.MultiHeadRatio > 1:
+			return fmt.Errorf("difficulty_specs.%s: multi_head_ratio %v must be in [0, 1]", tier, s.MultiHeadRatio)
+		case s.LockedRatio < 0 || s.LockedRatio > 1:
+			return fmt.Errorf("difficulty_specs.%s: locked_ratio %v must be in [0, 1]", tier, s.LockedRatio)
+		case s.DirBalanceTolerance < 0 || s.DirBalanceTolerance > 1:
+			return fmt.Errorf("difficulty_specs.%s: dir_balance_tolerance %v must be in [0, 1]", tier, s.DirBalanceTolerance)
+		case s.MaxBlockingDepth < 0 || s.DefaultGrace < 0 || s.PortalPairs < 0:
+			return fmt.Errorf("difficulty_specs.%s: max_blocking_depth, default_grace and portal_pairs must not be negative", tier)
+		case s.GraceDecisionsPerPoint < 0 || s.MaxGraceBonus < 0:
+			return fmt.Errorf("difficulty_specs.%s: grace_decisions_per_point and max_grace_bonus must not be negative", tier)
+		}
+		if err := s.LengthDistribution.validate(); err != nil {
+			return fmt.Errorf("difficulty_specs.%s.length_distribution: %w", tier, err)
+		}
+		if err := s.CenterOut.Validate(); err != nil {
+			return fmt.Errorf("difficulty_specs.%s.center_out: %w", tier, err)
+		}
+
+		g, ok := c.GridSizeRanges[tier]
+		if !ok {
+			return fmt.Errorf("grid_size_ranges: missing tier %s", tier)
+		}
+		if g.MinW < 1 || g.MinH < 1 || g.MinW > g.MaxW || g.MinH > g.MaxH {
+			return fmt.Errorf("grid_size_ranges.%s: invalid range %+v", tier, g)
+		}
+	}
+	for tier := range c.GridSizeRanges {
+		if _, ok := c.DifficultySpecs[tier]; !ok {
+			return fmt.Errorf("grid_size_ranges: tier %s has no difficulty spec", tier)
+		}
+	}
+
+	if len(c.ColorPalette) == 0 {
+		return fmt.Errorf("color_palette: no colors defined")
+	}
+	for i, color := range c.ColorPalette {
+		if !hexColorPattern.MatchString(color) {
+			return fmt.Errorf("color_palette[%d]: %q is not a #RRGGBB color", i, color)
+		}
+	}
+	return nil
+}
+
+func (c DifficultyConfig) apply() {
+	DifficultySpecs = c.DifficultySpecs
+	GridSizeRanges = c.GridSizeRanges
+	ColorPalette = c.ColorPalette
+}
+
+func sortedTiers(specs map[string]DifficultySpec) []string {
+	tiers := slices.Collect(maps.Keys(specs))
+	sort.Strings(tiers)
+	return tiers
+}
+
+func tierList(specs map[string]DifficultySpec) string {
+	return strings.Join(sortedTiers(specs), ", ")
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// restoreTables puts the tuning tables back after a test loads a config.
+func restoreTables(t *testing.T) {
+	specs, grids, palette := DifficultySpecs, GridSizeRanges, ColorPalette
+	t.Cleanup(func() {
+		DifficultySpecs, GridSizeRanges, ColorPalette = specs, grids, palette
+	})
+}
+
+func writeConfig(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadMergesOverrides(t *testing.T) {
+	restoreTables(t)
+	before := DifficultySpecs["Sprout"]
+
+	path := writeConfig(t, "difficulty_config.yaml", `
+difficulty_specs:
+  Sprout:
+    vine_count_range: [10, 40]
+    min_grid_occupancy: 0.8
+grid_size_ranges:
+  Seedling: { max_width: 10 }
+color_palette: ["#112233", "#445566"]
+`)
+	if err := Load(path); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	got := DifficultySpecs["Sprout"]
+	if got.VineCountRange != [2]int{10, 40} || got.MinGridOccupancy != 0.8 {
+		t.Errorf("overrides not applied: %+v", got)
+	}
+	if got.AvgLengthRange != before.AvgLengthRange || got.MaxMovesMultiplier != before.MaxMovesMultiplier {
+		t.Errorf("unset fields changed: %+v, want %+v", got, before)
+	}
+	if g := GridSizeRanges["Seedling"]; g.MaxW != 10 || g.MinW != 6 {
+		t.Errorf("Seedling grid = %+v", g)
+	}
+	if len(ColorPalette) != 2 {
+		t.Errorf("ColorPalette = %v", ColorPalette)
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	restoreTables(t)
+	path := writeConfig(t, "difficulty_config.json", `{"difficulty_specs": {"Nurturing": {"portal_pairs": 3}}}`)
+	if err := Load(path); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := DifficultySpecs["Nurturing"].PortalPairs; got != 3 {
+		t.Errorf("PortalPairs = %d, want 3", got)
+	}
+}
+
+func TestLoadRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unknown field", "difficulty_specs:\n  Sprout:\n    vine_count: [1, 2]\n", "field vine_count not found"},
+		{"unknown tier", "difficulty_specs:\n  Bloom:\n    portal_pairs: 1\n", `unknown tier "Bloom"`},
+		{"inverted range", "difficulty_specs:\n  Sprout:\n    vine_count_range: [9, 3]\n", "invalid vine_count_range"},
+		{"occupancy", "difficulty_specs:\n  Sprout:\n    min_grid_occupancy: 1.5\n", "min_grid_occupancy"},
+		{"dir balance", "difficulty_specs:\n  Sprout:\n    dir_balance_tolerance: -0.1\n", "dir_balance_tolerance"},
+		{"grid", "grid_size_ranges:\n  Sprout: { min_width: 20 }\n", "grid_size_ranges.Sprout"},
+		{"length shape", "difficulty_specs:\n  Sprout:\n    length_distribution: { shape: zipf }\n", `unknown shape "zipf"`},
+		{"bimodal share", "difficulty_specs:\n  Sprout:\n    length_distribution: { shape: bimodal, mean: 4, long_mean: 12 }\n", "long_share"},
+		{"center bias", "difficulty_specs:\n  Sprout:\n    center_out: { center_bias: 1 }\n", "center_bias"},
+		{"palette", "color_palette: [green]\n", "not a #RRGGBB color"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restoreTables(t)
+			before := DifficultySpecs["Sprout"]
+			err := Load(writeConfig(t, "config.yaml", tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Load error = %v, want %q", err, tt.want)
+			}
+			if DifficultySpecs["Sprout"] != before {
+				t.Error("failed load changed the tables")
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// GenerationProfile bundles generation settings for a style of level. Zero
+// fields keep the defaults, and explicit options (strategy, coverage,
+// aggressive backtracking) override the profile.
+type GenerationProfile struct {
+	Name        string
+	Description string
+	Strategy    string  // Primary placement strategy; the usual fallbacks still follow
+	MinCoverage float64 // Coverage target (0.0-1.0)
+	// Local backtracking settings (see GenerationConfig)
+	BacktrackWindow      int
+	MaxBacktrackAttempts int
+	// LengthScale scales the tier's AvgLengthRange, so fewer, longer vines
+	// fill the same grid when it is above 1
+	LengthScale float64
+	// LengthMix weights short/medium/long vines for placers that use a
+	// VarietyProfile (see VarietyProfile.LengthMix)
+	LengthMix map[string]float64
+}
+
+// Profile names.
+const (
+	ProfileAesthetic = "aesthetic"
+	ProfileDense     = "dense"
+	ProfileSpeedrun  = "speedrun"
+)
+
+// GenerationProfiles maps profile names to their settings.
+var GenerationProfiles = map[string]GenerationProfile{
+	ProfileAesthetic: {
+		Name:                 ProfileAesthetic,
+		Description:          "long winding vines from organic direction-first placement",
+		Strategy:             StrategyDirectionFirst,
+		MinCoverage:          0.9,
+		BacktrackWindow:      4,
+		MaxBacktrackAttempts: 3,
+		LengthScale:          1.5,
+		LengthMix:            map[string]float64{"short": 0.1, "medium": 0.3, "long": 0.6},
+	},
+	ProfileDense: {
+		Name:                 ProfileDense,
+		Description:          "maximum occupancy: full-coverage placement with no masked cells",
+		Strategy:             StrategyFullCoverage,
+		MinCoverage:          1.0,
+		BacktrackWindow:      6,
+		MaxBacktrackAttempts: 6,
+		LengthMix:            map[string]float64{"short": 0.3, "medium": 0.5, "long": 0.2},
+	},
+	ProfileSpeedrun: {
+		Name:                 ProfileSpeedrun,
+		Description:          "as few vines as the tier allows, for short solutions",
+		Strategy:             StrategyCenterOut,
+		MinCoverage:          0.85,
+		BacktrackWindow:      2,
+		MaxBacktrackAttempts: 1,
+		LengthScale:          2.0,
+		LengthMix:            map[string]float64{"short": 0, "medium": 0.2, "long": 0.8},
+	},
+}
+
+// ProfileNames returns the profile names in sorted order.
+func ProfileNames() []string {
+	names := make([]string, 0, len(GenerationProfiles))
+	for name := range GenerationProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ProfileFor returns the named profile.
+func ProfileFor(name string) (GenerationProfile, error) {
+	p, ok := GenerationProfiles[name]
+	if !ok {
+		return GenerationProfile{}, fmt.Errorf("unknown profile %q (want one of %s)", name, strings.Join(ProfileNames(), ", "))
+	}
+	return p, nil
+}
+
+// SpecFor returns the difficulty spec a generation config places vines
+// against: the tier's spec with the config's profile applied. Unknown
+// tiers report false, as a DifficultySpecs lookup would.
+func SpecFor(cfg GenerationConfig) (DifficultySpec, bool) {
+	spec, ok := DifficultySpecs[cfg.Difficulty]
+	if !ok {
+		return spec, false
+	}
+	if p, found := GenerationProfiles[cfg.Profile]; found && p.LengthScale > 0 {
+		for i, l := range spec.AvgLengthRange {
+			spec.AvgLengthRange[i] = int(math.Round(float64(l) * p.LengthScale))
+		}
+		spec.LengthDistribution = spec.LengthDistribution.scaled(p.LengthScale)
+	}
+	return spec, true
+}
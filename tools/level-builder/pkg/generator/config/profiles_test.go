@@ -0,0 +1,42 @@
+package config
+
+import "testing"
+
+func TestProfileFor(t *testing.T) {
+	for _, name := range ProfileNames() {
+		p, err := ProfileFor(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Name != name || p.Strategy == "" {
+			t.Errorf("profile %s: unexpected settings %+v", name, p)
+		}
+		if p.MinCoverage <= 0 || p.MinCoverage > 1 {
+			t.Errorf("profile %s: invalid MinCoverage %f", name, p.MinCoverage)
+		}
+	}
+	if _, err := ProfileFor("chaotic"); err == nil {
+		t.Error("expected an error for an unknown profile")
+	}
+}
+
+func TestSpecFor(t *testing.T) {
+	base := DifficultySpecs["Sprout"]
+
+	spec, ok := SpecFor(GenerationConfig{Difficulty: "Sprout"})
+	if !ok || spec != base {
+		t.Errorf("no profile should keep the tier spec, got %+v", spec)
+	}
+
+	spec, ok = SpecFor(GenerationConfig{Difficulty: "Sprout", Profile: ProfileSpeedrun})
+	if !ok || spec.AvgLengthRange != [2]int{base.AvgLengthRange[0] * 2, base.AvgLengthRange[1] * 2} {
+		t.Errorf("speedrun should double vine lengths, got %v", spec.AvgLengthRange)
+	}
+	if DifficultySpecs["Sprout"] != base {
+		t.Error("SpecFor must not modify DifficultySpecs")
+	}
+
+	if _, ok := SpecFor(GenerationConfig{Difficulty: "Impossible"}); ok {
+		t.Error("expected false for an unknown difficulty")
+	}
+}
@@ -1,11 +1,11 @@
 package config
 
 import (
+	"context"
 	math_rand "math/rand"
 	"time"
 
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
-	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
 )
 
 // BlockingAnalysis contains blocking relationship data
@@ -23,36 +23,71 @@ type BlockingAnalyzer interface {
 const (
 	StrategyDirectionFirst  = "direction-first"
 	StrategyCenterOut       = "center-out"       // LIFO
+	StrategyFullCoverage    = "full-coverage"    // 100% occupancy, no masks
 	StrategyLegacyClearable = "legacy-clearable" // Optimized ClearableFirst
+	StrategyChain           = "chain"            // Deliberate blocking chains (Transcendent)
+
+	StrategyLegacyTiling      = "legacy-tiling" // Old TileGridIntoVines
+	StrategyLegacySolverAware = "legacy-solver" // Old SolverAwarePlacement
 )
 
 // GenerationConfig holds configuration for level generation
 type GenerationConfig struct {
-	LevelID     int
-	GridWidth   int
-	GridHeight  int
-	VineCount   int
-	MaxMoves    int
-	OutputFile  string
-	Randomize   bool
-	Seed        int64
-	Overwrite   bool
-	MinCoverage float64 // Minimum grid coverage required (0.0-1.0)
-	Difficulty  string  // Difficulty tier (Seedling, Sprout, etc.)
-	Strategy    string  // Placement strategy (direction-first or center-out)
+	LevelID        int     `json:"level_id"`
+	GridWidth      int     `json:"grid_width"`
+	GridHeight     int     `json:"grid_height"`
+	VineCount      int     `json:"vine_count"`
+	MaxMoves       int     `json:"max_moves"` // Move budget override; 0 derives it from the solution (see MaxMovesFor)
+	OutputFile     string  `json:"output_file,omitempty"`
+	Randomize      bool    `json:"randomize,omitempty"`
+	Seed           int64   `json:"seed"`
+	Overwrite      bool    `json:"overwrite,omitempty"`
+	MinCoverage    float64 `json:"min_coverage"`              // Minimum grid coverage required (0.0-1.0)
+	Difficulty     string  `json:"difficulty"`                // Difficulty tier (Seedling, Sprout, etc.)
+	Strategy       string  `json:"strategy"`                  // Placement strategy (direction-first or center-out)
+	FillerStrategy string  `json:"filler_strategy,omitempty"` // Gap filler for placers that support it (default "lifo")
+	MaskMode       string  `json:"mask_mode,omitempty"`       // How unfilled cells are masked: "hide" (default) or "show"
+	Portals        bool    `json:"portals,omitempty"`         // Link empty cells with portal pairs on tiers that allow them
+	HintCount      int     `json:"hint_count,omitempty"`      // Solution moves to embed as level hints (0 = none)
+	Profile        string  `json:"profile,omitempty"`         // Generation profile shaping vine lengths (see SpecFor)
+	RNG            string  `json:"rng,omitempty"`             // Random source: math (default), pcg or splitmix (see package random)
+	// Stencil lists decorative gap cells (see model.Stencil) that no vine may
+	// occupy; the level keeps them empty and visible with a "show-all" mask
+	Stencil []model.Point `json:"stencil,omitempty"`
+	// InjectDifficulty reverses or moves placed vines to deepen blocker
+	// chains toward DifficultySpec.BlockingDepthTarget
+	InjectDifficulty bool `json:"inject_difficulty,omitempty"`
+	// OptimizeIterations runs that many simulated annealing moves over the
+	// placement (see strategies.PlacementOptimizer); 0 skips the optimizer
+	OptimizeIterations int `json:"optimize_iterations,omitempty"`
+	// CenterOutTuning overrides the tier's center-out seeding and growth
+	// (see CenterOutFor)
+	CenterOutTuning
+	// RNGTraceFile, when set, records every random draw with its call site
+	// to this file (see random.NewTracer); a debugging aid, never persisted
+	RNGTraceFile string `json:"-"`
 
 	// Local backtracking configuration
-	BacktrackWindow      int    // How many previous vines to remove when attempting local recovery (default 3)
-	MaxBacktrackAttempts int    // How many local backtrack retries to attempt per failure (default 2)
-	DumpDir              string // Directory to write deterministic failure dumps (if empty, defaults to tools/level-builder/failing_dumps)
+	BacktrackWindow      int    `json:"backtrack_window,omitempty"`       // How many previous vines to remove when attempting local recovery (default 3)
+	MaxBacktrackAttempts int    `json:"max_backtrack_attempts,omitempty"` // How many local backtrack retries to attempt per failure (default 2)
+	DumpDir              string `json:"dump_dir,omitempty"`               // Directory to write deterministic failure dumps (if empty, defaults to tools/level-builder/failing_dumps)
 }
 
 // GenerationStats tracks performance and quality metrics
 type GenerationStats struct {
 	PlacementAttempts    int
+	SolvabilityPrunes    int // candidate vines rejected because they would close a blocking cycle
+	DeadCellRejections   int // candidate vines passed over because they would strand dead cells
 	BacktracksAttempted  int // total local backtrack attempts
 	DumpsProduced        int // deterministic failure dumps written
-	SolvabilityChecks    validator.SolvabilityStats
+	Relaxations          int // coverage relaxations applied (e.g. masking unfilled cells)
+	FillersMerged        int // filler vine pairs joined into longer vines
+	DifficultyEdits      int // vines reversed or moved to deepen blocker chains
+	OptimizerMoves       int // annealing moves the placement optimizer accepted
+	MaskCellsAbsorbed    int // empty cells grown into adjacent vines instead of being masked
+	MultiHeadVines       int // vines given a second head at the tail
+	PortalPairs          int // portal pairs added to the level
+	LockedVines          int // vines locked until other vines clear
 	MaxBlockingDepth     int
 	TotalBlockingDepth   int // accumulated for averaging
 	BlockingDepthSamples int // samples counted for averaging
@@ -60,9 +95,10 @@ type GenerationStats struct {
 	GenerationTime       time.Duration
 }
 
-// VinePlacementStrategy defines the interface for vine placement algorithms
+// VinePlacementStrategy defines the interface for vine placement algorithms.
+// Implementations check ctx between vine placements and return ctx.Err() once it is done.
 type VinePlacementStrategy interface {
-	PlaceVines(config GenerationConfig, rng *math_rand.Rand, stats *GenerationStats) ([]model.Vine, map[string]string, error)
+	PlaceVines(ctx context.Context, config GenerationConfig, rng *math_rand.Rand, stats *GenerationStats) ([]model.Vine, map[string]string, error)
 }
 
 // Assembler defines the interface for assembling final level data
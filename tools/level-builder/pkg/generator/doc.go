@@ -41,15 +41,14 @@
 //     This predicate is used to decide whether placing a vine with head `pos` and
 //     head direction `dir` will satisfy the LIFO solvability guarantee.
 //
-//   - CenterOutPlacer.PlaceVines(config, rng)
+//   - CenterOutPlacer.PlaceVines(ctx, config, rng, stats)
 //     High-level placement routine. Steps:
 //     1. Compute target vine lengths from difficulty & config.
 //     2. Iteratively place vines seeded from center-biased cells using
 //     `placeVineWithExitGuarantee` (ensures each placed vine head has an
 //     unobstructed exit path at placement time).
-//     3. If coverage < MinCoverage, call `createFillerVines` which runs two
-//     phases: LIFO-guaranteed 2-cell fillers, then non-LIFO 2-cell fillers
-//     as a fallback.
+//     3. If coverage < MinCoverage, hand the remaining gaps to the configured
+//     FillerStrategy (`config.FillerStrategy`, default "lifo").
 //
 //   - placeVineWithExitGuarantee(vineID, targetLen, ...) -> (Vine, occupied)
 //     Selects a center-biased seed cell and chooses a head direction that has a
@@ -64,19 +63,103 @@
 //     which prefers growth in the 'growDir' but allows turns and scores cells by
 //     available free-neighbor count plus controlled randomness.
 //
-//   - createFillerVines
-//     Two-phase filler strategy to raise grid coverage: first try LIFO-guaranteed
-//     2-cell placements (`tryPlaceFillerVine` + `tryPlaceEdgeFillerVine`), then
-//     try `tryPlaceAnyFillerVine` for remaining gaps. The function returns a set
-//     of filler vines and the cells they occupy so the caller can merge occupancy
+//   - Cancellation: every VinePlacementStrategy takes a context.Context and
+//     checks it between vine placements; GenerateRobust and the validator's
+//     *Context solvers return ctx.Err() once it is done.
+//
+//   - FillerStrategy (strategies/filler.go)
+//     Gap filling sits behind the FillerStrategy interface so heuristics can be
+//     A/B tested without touching the placer. Fillers are registered by name
+//     (RegisterFiller/GetFiller) and selected with `--filler-strategy` on the
+//     batch command. Built-ins: "lifo" (LIFOFiller: LIFO-guaranteed 2-cell
+//     placements via `tryPlaceFillerVine` + `tryPlaceEdgeFillerVine`) and "gap"
+//     (GapGrowthFiller: multi-cell growth via GapFiller). A filler returns the
+//     new vines and the cells they occupy so the caller can merge occupancy
 //     maps.
 //
+//   - IncrementalSolver (utils/incremental_solver.go)
+//     Keeps the "waits on" graph of placed vines up to date as vines are placed,
+//     replaced or removed. CanPlace rejects a candidate that would close a
+//     blocking cycle by searching only from the vines on its exit ray.
+//     DirectionFirstPlacer checks every grown, extended and filler vine with it;
+//     CenterOutPlacer drops filler vines it rejects. Rejected vine placements
+//     (not single-cell extensions) are counted in
+//     GenerationStats.SolvabilityPrunes.
+//
+//   - IslandDetector (utils/island_detector.go)
+//     Finds dead cells: free cells walled in on every side, which no vine
+//     fits in and which would otherwise end up masked. Every placer asks
+//     WouldStrand through a `strandGuard` (strategies/dead_cells.go) before
+//     committing a vine and grows another body instead, up to
+//     `deadCellRetries` times per vine so a crowded board still fills.
+//     Passed-over bodies are counted in GenerationStats.DeadCellRejections.
+//
+//   - DifficultyInjector (strategies/difficulty_injector.go)
+//     The solver-aware placement idea as a reusable post-pass. With
+//     `config.InjectDifficulty` (`--inject-difficulty` on batch)
+//     GenerateRobust hands the merged vines to it before mask minimization.
+//     It reverses a vine or regrows it over its own and neighbouring empty
+//     cells, keeping an edit only if the deepest blocker chain grows (or
+//     the summed depth, at equal depth) without passing
+//     DifficultySpec.BlockingDepthTarget, and the IncrementalSolver finds no
+//     blocking cycle. GenerationStats.DifficultyEdits counts kept edits.
+//
+//   - PlacementOptimizer (strategies/optimizer.go)
+//     Bounded simulated annealing over the merged vines, run after
+//     difficulty injection when `config.OptimizeIterations` is set
+//     (`--optimize-iterations` on batch). Each move reverses, regrows, grows
+//     or trims one vine and is skipped if the IncrementalSolver finds a
+//     blocking cycle; the objective weighs coverage, the aesthetics score and
+//     distance from BlockingDepthTarget. GenerationStats.OptimizerMoves counts
+//     accepted moves.
+//
+//   - Legacy adapters (strategies/legacy_wrappers.go)
+//     The pre-gen2 algorithms (TileGridIntoVines, ClearableFirstPlacement,
+//     SolverAwarePlacement) live only in the strategies package and are reached
+//     through VinePlacementStrategy adapters registered as "legacy-tiling",
+//     "legacy-clearable" and "legacy-solver". The legacy Generate path drives
+//     the same adapters (tuning LegacyClearableStrategy's AnchorRatio and
+//     MinOccupancy as it relaxes), so placement fixes land once. Head
+//     directions always come from model.NewVine rather than per-placer copies.
+//
 // - Integration points
 //   - GenerateLevelLIFO(config): High-level convenience wrapper that runs the
 //     CenterOutPlacer pipeline using a deterministic RNG and returns a
 //     generated Level with `solvable: true` guaranteed by construction for
 //     placed vines.
 //   - CLI flag: `--lifo` on the `gen2` command toggles center-out LIFO mode.
+//   - Masking: GenerateRobust masks any cell left empty after filling, in the
+//     mode set by `config.MaskMode` (`--mask-mode` on batch). "hide" lists the
+//     empty cells; "show" lists the occupied cells as the playable region. Use
+//     Mask.HiddenCells rather than len(Points) when counting masked cells.
+//     With `config.Stencil` set, the stencil cells are reserved in every
+//     occupancy map instead, every other cell must be covered, and the level
+//     gets a "show-all" mask listing the gaps (model.NewGapMask).
+//     Before masking, a MaskMinimizer grows vines into empty cells next to a
+//     tail or in front of a head (step 5 of the pseudo-code below), keeping
+//     each growth only if the level stays valid and greedily solvable.
+//     GenerationStats.MaskCellsAbsorbed counts the cells it saved.
+//   - Filler merging: placers and gap fillers pad leftover space with 2- and
+//     3-cell vines. Before mask minimization a FillerMerger joins fillers whose
+//     ends touch into one vine (up to the tier's longest average length),
+//     trying the head at either end and keeping a merge only if the level
+//     stays valid and greedily solvable. GenerationStats.FillersMerged counts
+//     the merges.
+//   - Portals: with `config.Portals` (`--portals` on batch) GenerateRobust asks
+//     a PortalPlacer for up to DifficultySpec.PortalPairs pairs before
+//     masking. One cell of each pair sits on a vine's exit ray and a pair is
+//     kept only if the level stays valid and solvable. Portal cells stay
+//     visible and are never masked.
+//   - Locks: on tiers with DifficultySpec.LockedRatio, AssignLocks sets
+//     LockedUntil on vines that are free at the start. Each lock is at most
+//     the vine's position in a greedy clear order, so that order still
+//     solves the level.
+//   - Solution: the assembler solves the finished level. MinMoves is the
+//     solution length and MaxMoves is config.MaxMovesFor, which scales it by
+//     DifficultySpec.MaxMovesMultiplier. With `config.HintCount` (`--hints`
+//     on batch) the first HintCount vines of the solution go in Level.Hints.
+//     The whole solution is kept as Level.SolutionOrder for validation to
+//     replay.
 //
 // Determinism & RNG
 // ------------------
@@ -98,7 +181,7 @@
 //
 //   - Unit tests: placement logic, head/neck orientation checks, and small
 //     generator integration tests were added/updated to cover the new behaviors
-//     (see `pkg/gen2` tests and CI config).
+//     (see `pkg/generator/strategies` tests and CI config).
 //
 // Performance
 // -----------
@@ -106,6 +189,16 @@
 //     generation times are measured in single-digit milliseconds on local dev
 //     hardware for regular levels; the expensive exact solver is only invoked
 //     during validation or when fallback non-LIFO fillers require verification.
+//   - Placer hot loops (free-neighbor scans, the island check in
+//     `chooseNextGrowthCell`, seed selection) work on `common.Grid`, a
+//     bitset indexed by y*w+x, instead of "x,y" string keys. The occupancy
+//     maps returned by PlaceVines are unchanged. The island check asks
+//     `Grid.IsEdgeCut`, which finds every cut cell in one articulation-point
+//     search and caches them until the grid changes, instead of flood filling
+//     once per growth candidate. Run
+//     `go test ./pkg/common -run XXX -bench 'ReachableFromEdge|FreeNeighbors|EdgeCut'` and
+//     `go test ./pkg/generator/strategies -run XXX -bench PlacersTranscendent`
+//     to compare.
 //
 // Code quality & refactors
 // ------------------------
@@ -127,13 +220,13 @@
 //   - Coverage: pure LIFO placement cannot reliably reach 100% coverage in all
 //     grid shapes due to isolated interior cells without clear exits; the hybrid
 //     filler approach was chosen as a pragmatic balance (97.1% achieved in
-//     practice). The direction-first placer remains available as a fallback for
-//     100% coverage needs.
+//     practice). Use the full-coverage strategy (FullCoveragePlacer) when levels
+//     must reach 100% occupancy without masks.
 //
 //   - Static analysis: Semgrep reports `math/rand` as a cryptographic issue—see
 //     "Determinism & RNG" above for the rationale.
 //
-//   - File size: The `pkg/gen2/center_out_placer.go` file contains a focused
+//   - File size: The `strategies/center_out_placer.go` file contains a focused
 //     implementation; it was refactored to reduce method complexity. If the file
 //     grows substantially in future iterations, consider splitting the placer and
 //     helper types into smaller files (e.g., `placer.go`, `filler.go`,
@@ -144,15 +237,16 @@
 //   - Add more exhaustive solver-validated test vectors that simulate edge-case
 //     topologies (tight corridors, long interior caverns) so the hybrid filler
 //     logic can be stress-tested.
-//   - Consider a follow-up refactor that extracts the filler strategy behind an
-//     interface so other heuristics (e.g., flood-fill-aware fillers) can be
-//     plugged in and A/B tested without touching the core placer.
+//   - Add further FillerStrategy implementations (e.g., flood-fill-aware
+//     fillers) and compare them against "lifo" with batch stats.
 //
 // Package gen2 contains the level generation version 2 implementation.
 package generator
 
 /*
-TODO: impliment the following
+Steps 1-5 below are implemented by strategies.FullCoveragePlacer
+(`batch --strategy full-coverage`). Step 6 is handled by the shared pipeline
+and assembler.
 
 Psudo Code Explanation to acheve 100% coverage
 1. Define the grid size on difficulty tier. (no restriction on number of vines only grid size)
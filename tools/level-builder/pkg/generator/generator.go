@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/rand"
@@ -13,6 +14,7 @@ import (
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/strategies"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/utils"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/naming"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
 )
 
@@ -38,6 +40,7 @@ func Clean() error {
 	if err != nil {
 		return err
 	}
+	files = append(files, filepath.Join(levelsDir, common.GenerationMetadataFile))
 	for _, f := range files {
 		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
 			return fmt.Errorf("failed to remove %s: %w", f, err)
@@ -175,27 +178,27 @@ func generateLevels(cfg LegacyBatchConfig) error {
 }
 
 // generateModule generates a complete module with balanced difficulty progression.
+// ModuleProgression is the difficulty of each slot of a module: 5 Seedling, 5 Sprout,
+// 5 Nurturing and 5 Flourishing regular levels, then the Transcendent challenge level.
+var ModuleProgression = []string{
+	// Levels 1-5: Seedling
+	"Seedling", "Seedling", "Seedling", "Seedling", "Seedling",
+	// Levels 6-10: Sprout
+	"Sprout", "Sprout", "Sprout", "Sprout", "Sprout",
+	// Levels 11-15: Nurturing
+	"Nurturing", "Nurturing", "Nurturing", "Nurturing", "Nurturing",
+	// Levels 16-20: Flourishing
+	"Flourishing", "Flourishing", "Flourishing", "Flourishing", "Flourishing",
+	// Level 21: Transcendent (boss)
+	"Transcendent",
+}
+
 // Each module has 21 levels: 20 regular levels (5 each of Seedling, Sprout, Nurturing, Flourishing)
 // plus 1 Transcendent boss level at the end.
 func generateModule(cfg LegacyBatchConfig) error {
 	const levelsPerModule = 21
 	const regularLevels = 20
 
-	// Difficulty progression for regular levels (20 levels)
-	// 5 Seedling, 5 Sprout, 5 Nurturing, 5 Flourishing, then 1 Transcendent
-	difficultyProgression := []string{
-		// Levels 1-5: Seedling
-		"Seedling", "Seedling", "Seedling", "Seedling", "Seedling",
-		// Levels 6-10: Sprout
-		"Sprout", "Sprout", "Sprout", "Sprout", "Sprout",
-		// Levels 11-15: Nurturing
-		"Nurturing", "Nurturing", "Nurturing", "Nurturing", "Nurturing",
-		// Levels 16-20: Flourishing
-		"Flourishing", "Flourishing", "Flourishing", "Flourishing", "Flourishing",
-		// Level 21: Transcendent (boss)
-		"Transcendent",
-	}
-
 	// Calculate starting level ID for this module
 	startID := (cfg.ModuleID-1)*levelsPerModule + 1
 
@@ -219,7 +222,7 @@ func generateModule(cfg LegacyBatchConfig) error {
 		if cfg.Difficulty != "" {
 			difficultyTier = cfg.Difficulty
 		} else {
-			difficultyTier = difficultyProgression[i]
+			difficultyTier = ModuleProgression[i]
 		}
 		// Per-level retry logic
 		var level model.Level
@@ -237,10 +240,13 @@ func generateModule(cfg LegacyBatchConfig) error {
 			continue // Skip this level, continue batch
 		}
 		if isBoss {
-			level.Name = fmt.Sprintf("Module %d - Transcendent", cfg.ModuleID)
+			name := naming.Challenge(cfg.ModuleID)
+			level.Name, level.NameRef = naming.Render(name), &name
 			level.Complexity = "transcendent"
 			level.Grace++
 		}
+		theme := ThemeFor(ThemeSeedFor(cfg.ModuleID), level)
+		level.Theme = &theme
 
 		levelsDir, err := common.LevelsDir()
 		if err != nil {
@@ -290,10 +296,12 @@ func updateModuleRegistry(moduleID int, startID int) error {
 	}
 
 	// Build module entry
+	name := naming.Module(moduleID)
 	moduleEntry := model.Module{
 		ID:             moduleID,
-		Name:           fmt.Sprintf("Module %d", moduleID),
-		ThemeSeed:      getThemeSeed(moduleID),
+		Name:           naming.Render(name),
+		NameRef:        &name,
+		ThemeSeed:      ThemeSeedFor(moduleID),
 		Levels:         []string{},
 		ChallengeLevel: common.LogicalLevelID(startID + 20), // 21st level is Transcendent boss
 	}
@@ -317,7 +325,7 @@ func updateModuleRegistry(moduleID int, startID int) error {
 	}
 
 	// Write updated registry
-	jsonData, err := json.MarshalIndent(registry, "", "  ")
+	jsonData, err := common.MarshalCanonical(registry, common.LevelIndent)
 	if err != nil {
 		return fmt.Errorf("failed to marshal modules.json: %w", err)
 	}
@@ -330,8 +338,9 @@ func updateModuleRegistry(moduleID int, startID int) error {
 	return nil
 }
 
-// getThemeSeed returns a theme seed name for a module.
-func getThemeSeed(moduleID int) string {
+// ThemeSeedFor returns the default theme seed name for a module, used when
+// modules.json does not name one.
+func ThemeSeedFor(moduleID int) string {
 	themes := []string{"forest", "sunset", "ocean", "volcano", "lavender", "meadow", "twilight", "aurora"}
 	if moduleID > 0 && moduleID <= len(themes) {
 		return themes[moduleID-1]
@@ -358,9 +367,13 @@ func generateSingleLevel(id int, difficulty string, seed int64, rng *rand.Rand)
 	// Get grid size for this level
 	gridSize := utils.GridSizeForLevel(id)
 
-	// Get variety profile and generator config for this difficulty
-	profile := utils.GetPresetProfile(difficulty)
-	generatorCfg := utils.GetGeneratorConfigForDifficulty(difficulty)
+	// The placement adapters read the tier's profile and generator tuning
+	placementCfg := config.GenerationConfig{
+		LevelID:    id,
+		GridWidth:  gridSize[0],
+		GridHeight: gridSize[1],
+		Difficulty: difficulty,
+	}
 
 	originalOccupancy := spec.MinGridOccupancy
 	common.Verbose("Level %d: difficulty=%s, grid=%dx%d, target_occupancy=%.1f%%, max_attempts=%d",
@@ -406,58 +419,28 @@ func generateSingleLevel(id int, difficulty string, seed int64, rng *rand.Rand)
 				tilingFailures, greedyFailures, bfsFailures, constraintFailures,
 				successRate)
 		}
-		var vines []model.Vine
-		var mask *model.Mask
-		var err error
-
 		// Create an attempt-local RNG to diversify retries (prevents repeating same trajectories)
 		attemptRng := rand.New(rand.NewSource(seed + int64(attempts)*7919))
 
-		// Determine which algorithm to use based on grid size and difficulty
-		// ALWAYS use clearable-first to prevent circular blocking
-		useClearableFirst := true
-
-		if useClearableFirst {
-			// Choose anchor ratio adaptively based on tiling failure history to avoid stalls
-			anchorRatio := 0.3
-			if tilingFailures > 30 {
-				anchorRatio = 0.15 // use fewer anchors when we're struggling
-			}
-			if tilingFailures > 100 {
-				// Last-resort: try standard tiling as a fallback to escape pathological cases
-				common.Verbose("⚠️  Falling back to TileGridIntoVines after %d tiling failures", tilingFailures)
-				vines, mask, err = strategies.TileGridIntoVines(gridSize, spec, profile, generatorCfg, attemptRng)
-			} else {
-				// Try clearable-first with adaptive anchor ratio
-				vines, err = strategies.ClearableFirstPlacement(gridSize, spec, profile, generatorCfg, attemptRng.Int63(), anchorRatio, spec.MinGridOccupancy, true)
-			}
-			if err == nil {
-				// Generate mask for empty cells (inline mask generation)
-				occupied := make(map[string]bool)
-				for _, v := range vines {
-					for _, p := range v.OrderedPath {
-						occupied[fmt.Sprintf("%d,%d", p.X, p.Y)] = true
-					}
-				}
-				var emptyPoints []model.Point
-				for y := 0; y < gridSize[1]; y++ {
-					for x := 0; x < gridSize[0]; x++ {
-						key := fmt.Sprintf("%d,%d", x, y)
-						if !occupied[key] {
-							emptyPoints = append(emptyPoints, model.Point{X: x, Y: y})
-						}
-					}
-				}
-				if len(emptyPoints) > 0 {
-					mask = &model.Mask{Mode: "hide", Points: emptyPoints}
-				}
-			}
-		} else if difficulty == "Nurturing" || difficulty == "Flourishing" || difficulty == "Transcendent" {
-			// For higher difficulties on normal grids, use solver-aware placement
-			vines, mask, err = strategies.SolverAwarePlacement(gridSize, spec, profile, generatorCfg, attemptRng)
-		} else {
-			// Use standard tiling for easier difficulties on normal grids
-			vines, mask, err = strategies.TileGridIntoVines(gridSize, spec, profile, generatorCfg, attemptRng)
+		// Always use clearable-first to prevent circular blocking, with fewer
+		// anchors when tiling keeps failing
+		anchorRatio := 0.3
+		if tilingFailures > 30 {
+			anchorRatio = 0.15
+		}
+		var placer config.VinePlacementStrategy = &strategies.LegacyClearableStrategy{
+			AnchorRatio:  anchorRatio,
+			MinOccupancy: spec.MinGridOccupancy,
+		}
+		if tilingFailures > 100 {
+			// Last-resort: try standard tiling as a fallback to escape pathological cases
+			common.Verbose("⚠️  Falling back to legacy tiling after %d tiling failures", tilingFailures)
+			placer = &strategies.LegacyTilingStrategy{}
+		}
+		vines, _, err := placer.PlaceVines(context.Background(), placementCfg, attemptRng, nil)
+		var mask *model.Mask
+		if err == nil {
+			mask = strategies.EmptyCellMask(gridSize, vines)
 		}
 
 		if err != nil {
@@ -472,9 +455,11 @@ func generateSingleLevel(id int, difficulty string, seed int64, rng *rand.Rand)
 		assignColorIndices(vines, len(config.ColorPalette), rng)
 
 		// Build level
+		name := naming.Level(id)
 		level = model.Level{
 			ID:          id,
-			Name:        fmt.Sprintf("Level %d", id),
+			Name:        naming.Render(name),
+			NameRef:     &name,
 			Difficulty:  difficulty,
 			GridSize:    gridSize,
 			Vines:       vines,
@@ -502,7 +487,8 @@ func generateSingleLevel(id int, difficulty string, seed int64, rng *rand.Rand)
 
 		// Validate solvability using the solver
 		solver := common.NewSolver(&level)
-		if !solver.IsSolvableGreedy() {
+		order, ok := solver.GreedyClearOrder()
+		if !ok {
 			greedyFailures++
 			if attempts < 10 || (attempts > 0 && attempts%100 == 0) {
 				common.Verbose("Attempt %d: Level not solvable (greedy check)", attempts+1)
@@ -521,13 +507,10 @@ func generateSingleLevel(id int, difficulty string, seed int64, rng *rand.Rand)
 			}
 		}
 
-		// Calculate min/max moves based on vine count
-		vineCount := len(vines)
-		level.MinMoves = vineCount
-		level.MaxMoves = int(float64(vineCount) * 1.5)
-		if level.MaxMoves < 5 {
-			level.MaxMoves = 5
-		}
+		// The greedy clear order is a full solution, so its length is the
+		// minimum move count; the budget scales it by the tier multiplier
+		level.MinMoves = len(order)
+		level.MaxMoves = config.MaxMovesFor(difficulty, level.MinMoves)
 
 		// Record generation time
 		elapsed = time.Since(startTime)
@@ -590,11 +573,10 @@ func calculateLevelScore(level *model.Level, attempts int) float64 {
 	return score
 }
 
-// assignColorIndices assigns random color indices to vines from the palette.
+// assignColorIndices assigns color indices from the palette so touching vines
+// differ, with rng picking which palette colors are used.
 func assignColorIndices(vines []model.Vine, paletteSize int, rng *rand.Rand) {
-	for i := range vines {
-		vines[i].ColorIndex = rng.Intn(paletteSize)
-	}
+	utils.AssignColors(vines, paletteSize, rng)
 }
 
 func convertToModelLevel(level model.Level) model.Level {
@@ -627,12 +609,8 @@ func convertMask(mask *model.Mask) *model.Mask {
 func convertVines(vines []model.Vine) []model.Vine {
 	out := make([]model.Vine, len(vines))
 	for i, v := range vines {
-		out[i] = model.Vine{
-			ID:            v.ID,
-			HeadDirection: v.HeadDirection,
-			OrderedPath:   convertPoints(v.OrderedPath),
-			ColorIndex:    v.ColorIndex,
-		}
+		v.OrderedPath = convertPoints(v.OrderedPath)
+		out[i] = v
 	}
 	return out
 }
@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"math"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// Aesthetics tuning. A vine reads as a vine when it bends now and then; paths
+// that zig-zag every cell look like noise and long straight runs look like
+// ruled lines.
+const (
+	// TargetTurnFrequency is the turns per interior cell scored highest
+	TargetTurnFrequency = 0.35
+	// LongRunCells is the length, in cells, at which a straight run counts as long
+	LongRunCells = 5
+	// ShortVineCells is the longest vine counted as short filler
+	ShortVineCells = 3
+
+	turnWeight  = 0.35
+	runWeight   = 0.30
+	clumpWeight = 0.35
+)
+
+// AestheticsMetrics captures how a level's vines look, independent of how it plays.
+type AestheticsMetrics struct {
+	TurnFrequency float64 `json:"turn_frequency"` // turns per interior path cell
+	LongRunShare  float64 `json:"long_run_share"` // vine cells on straight runs of LongRunCells or more
+	ShortVines    int     `json:"short_vines"`    // vines of ShortVineCells or fewer cells
+	ShortClumping float64 `json:"short_clumping"` // short vines touching another short vine
+	Score         float64 `json:"score"`          // 0-100, higher looks better
+}
+
+// AestheticsAnalyzer scores levels on visual qualities: how often vines turn,
+// how much of the board is long straight runs, and how tightly short filler
+// vines clump together.
+type AestheticsAnalyzer struct{}
+
+// Analyze measures level's vines and combines the measures into a 0-100 score.
+func (AestheticsAnalyzer) Analyze(level model.Level) AestheticsMetrics {
+	var m AestheticsMetrics
+
+	interior, turns, cells, longCells := 0, 0, 0, 0
+	shortOwner := make(map[model.Point]int)
+	for i, v := range level.Vines {
+		path := v.OrderedPath
+		cells += len(path)
+		if len(path) <= ShortVineCells {
+			m.ShortVines++
+			for _, p := range path {
+				shortOwner[p] = i
+			}
+		}
+		if len(path) >= 3 {
+			interior += len(path) - 2
+			turns += countTurns(path)
+		}
+		longCells += longRunCells(path)
+	}
+
+	if interior > 0 {
+		m.TurnFrequency = float64(turns) / float64(interior)
+	}
+	if cells > 0 {
+		m.LongRunShare = float64(longCells) / float64(cells)
+	}
+	if m.ShortVines > 0 {
+		m.ShortClumping = float64(clumpedVines(shortOwner)) / float64(m.ShortVines)
+	}
+
+	turnScore := 1 - math.Min(1, math.Abs(m.TurnFrequency-TargetTurnFrequency)/TargetTurnFrequency)
+	m.Score = 100 * (turnWeight*turnScore + runWeight*(1-m.LongRunShare) + clumpWeight*(1-m.ShortClumping))
+	return m
+}
+
+// countTurns counts the cells where path changes direction.
+func countTurns(path []model.Point) int {
+	turns := 0
+	for i := 1; i+1 < len(path); i++ {
+		if step(path[i-1], path[i]) != step(path[i], path[i+1]) {
+			turns++
+		}
+	}
+	return turns
+}
+
+// longRunCells counts the cells of path that lie on a straight run of at
+// least LongRunCells cells. A corner cell ends one run and starts the next, so
+// it is counted once.
+func longRunCells(path []model.Point) int {
+	long := make([]bool, len(path))
+	start := 0
+	for i := 1; i <= len(path); i++ {
+		if i < len(path) && (i-start < 2 || step(path[i-2], path[i-1]) == step(path[i-1], path[i])) {
+			continue
+		}
+		// path[start:i] is a straight run
+		if i-start >= LongRunCells {
+			for j := start; j < i; j++ {
+				long[j] = true
+			}
+		}
+		start = i - 1
+	}
+	n := 0
+	for _, l := range long {
+		if l {
+			n++
+		}
+	}
+	return n
+}
+
+// clumpedVines counts the short vines with a cell orthogonally adjacent to a
+// cell of a different short vine.
+func clumpedVines(owner map[model.Point]int) int {
+	clumped := make(map[int]bool)
+	for p, id := range owner {
+		for _, d := range [4]model.Point{{X: 1}, {X: -1}, {Y: 1}, {Y: -1}} {
+			if other, ok := owner[model.Point{X: p.X + d.X, Y: p.Y + d.Y}]; ok && other != id {
+				clumped[id] = true
+			}
+		}
+	}
+	return len(clumped)
+}
+
+func step(a, b model.Point) model.Point {
+	return model.Point{X: b.X - a.X, Y: b.Y - a.Y}
+}
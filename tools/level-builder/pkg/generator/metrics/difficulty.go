@@ -0,0 +1,172 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/utils"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+// DefaultScorerMaxStates is the solver budget used when DifficultyScorer.MaxStates is unset.
+const DefaultScorerMaxStates = 100000
+
+// DifficultyMetrics captures how hard a level plays, as measured by the solver.
+type DifficultyMetrics struct {
+	MaxBlockingDepth  int     `json:"max_blocking_depth"`
+	MeanBlockingDepth float64 `json:"mean_blocking_depth"`
+	BlockedRatio      float64 `json:"blocked_ratio"`     // vines that cannot move at the start
+	ForcedMoveRatio   float64 `json:"forced_move_ratio"` // solution steps with exactly one clearable vine
+	StatesExplored    int     `json:"states_explored"`
+	SolutionLength    int     `json:"solution_length"`
+	Score             float64 `json:"score"`
+}
+
+// DifficultyScorer rates levels from solver metrics and checks the rating
+// against the score band of the level's difficulty tier.
+type DifficultyScorer struct {
+	MaxStates int // Solver budget (default DefaultScorerMaxStates)
+}
+
+// Score measures level and combines the metrics into a single difficulty score.
+// Higher scores mean deeper blocker chains, fewer free choices and longer solutions.
+func (s DifficultyScorer) Score(level model.Level) (DifficultyMetrics, error) {
+	maxStates := s.MaxStates
+	if maxStates <= 0 {
+		maxStates = DefaultScorerMaxStates
+	}
+
+	ok, solution, stats, err := validator.Solve(level, maxStates)
+	if err != nil {
+		return DifficultyMetrics{}, fmt.Errorf("solver error: %w", err)
+	}
+	if !ok {
+		return DifficultyMetrics{}, fmt.Errorf("level %d is not solvable", level.ID)
+	}
+
+	m := DifficultyMetrics{
+		StatesExplored: stats.StatesExplored,
+		SolutionLength: len(solution),
+	}
+
+	depths := utils.BlockingDepths(utils.BuildLevelBlockingGraph(&level))
+	total := 0
+	for _, d := range depths {
+		total += d
+		if d > m.MaxBlockingDepth {
+			m.MaxBlockingDepth = d
+		}
+	}
+	if len(depths) > 0 {
+		m.MeanBlockingDepth = float64(total) / float64(len(depths))
+	}
+
+	m.BlockedRatio, m.ForcedMoveRatio = replayChoices(level, solution)
+	m.Score = combineScore(m)
+	return m, nil
+}
+
+// Evaluate scores level and reports whether the score falls inside the band
+// for its difficulty tier. Tiers without a band always pass.
+func (s DifficultyScorer) Evaluate(level model.Level) (DifficultyMetrics, bool, error) {
+	m, err := s.Score(level)
+	if err != nil {
+		return m, false, err
+	}
+	return m, InScoreBand(level.Difficulty, m.Score), nil
+}
+
+// InScoreBand reports whether score lies within the tier's DifficultySpec.ScoreRange.
+func InScoreBand(difficulty string, score float64) bool {
+	spec, ok := config.DifficultySpecs[difficulty]
+	if !ok || spec.ScoreRange == [2]float64{} {
+		return true
+	}
+	return score >= spec.ScoreRange[0] && score <= spec.ScoreRange[1]
+}
+
+// InDepthBand reports whether depth, a level's deepest blocker chain, falls
+// inside the blocking depth band for its difficulty tier. Tiers without a band
+// always pass.
+func InDepthBand(difficulty string, depth int) bool {
+	spec, ok := config.DifficultySpecs[difficulty]
+	if !ok || spec.BlockingDepthRange == [2]int{} {
+		return true
+	}
+	return depth >= spec.BlockingDepthRange[0] && depth <= spec.BlockingDepthRange[1]
+}
+
+// combineScore weights the individual metrics into one score. Depth and
+// forced moves dominate; solution length and solver effort grow slowly.
+func combineScore(m DifficultyMetrics) float64 {
+	score := float64(m.MaxBlockingDepth) +
+		2*m.MeanBlockingDepth +
+		3*m.BlockedRatio +
+		3*m.ForcedMoveRatio +
+		math.Log2(float64(1+m.SolutionLength)) +
+		0.5*math.Log10(float64(1+m.StatesExplored))
+	return math.Round(score*100) / 100
+}
+
+// replayChoices clears vines in solution order and returns the fraction of
+// vines blocked (or locked) at the start and the fraction of steps where only
+// one vine could be cleared.
+func replayChoices(level model.Level, solution []string) (blockedRatio, forcedRatio float64) {
+	steps := solutionSteps(level, solution)
+	if len(steps) == 0 {
+		return 0, 0
+	}
+	forced := 0
+	for _, st := range steps {
+		if st.clearable == 1 {
+			forced++
+		}
+	}
+	first := steps[0]
+	return float64(first.remaining-first.clearable) / float64(first.remaining), float64(forced) / float64(len(steps))
+}
+
+// stepChoice is the state before one solution move: how many vines remain
+// and how many of them could be cleared.
+type stepChoice struct {
+	remaining, clearable int
+}
+
+// solutionSteps clears vines in solution order and records the choice the
+// player faces before each move. Locked vines count as blocked.
+func solutionSteps(level model.Level, solution []string) []stepChoice {
+	remaining := make(map[string]model.Vine, len(level.Vines))
+	occupied := make(map[string]string)
+	for _, v := range level.Vines {
+		remaining[v.ID] = v
+		for _, p := range v.OrderedPath {
+			occupied[fmt.Sprintf("%d,%d", p.X, p.Y)] = v.ID
+		}
+	}
+
+	steps := make([]stepChoice, 0, len(solution))
+	for step, id := range solution {
+		clearable := 0
+		for _, v := range remaining {
+			if v.IsLocked(step) {
+				continue
+			}
+			for _, head := range v.Heads() {
+				if common.IsLevelExitPathClear(&level, head.OrderedPath[0], head.HeadDirection, occupied) {
+					clearable++
+					break
+				}
+			}
+		}
+		steps = append(steps, stepChoice{remaining: len(remaining), clearable: clearable})
+
+		for _, p := range remaining[id].OrderedPath {
+			delete(occupied, fmt.Sprintf("%d,%d", p.X, p.Y))
+		}
+		delete(remaining, id)
+	}
+	return steps
+}
@@ -0,0 +1,173 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func TestDifficultyScorerChain(t *testing.T) {
+	// "c" blocks "b" which blocks "a": every step has exactly one clearable vine
+	level := model.Level{
+		ID:         1,
+		Difficulty: "Seedling",
+		GridSize:   []int{4, 4},
+		Vines: []model.Vine{
+			{ID: "a", HeadDirection: "right", OrderedPath: []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}},
+			{ID: "b", HeadDirection: "up", OrderedPath: []model.Point{{X: 2, Y: 1}, {X: 2, Y: 0}}},
+			{ID: "c", HeadDirection: "left", OrderedPath: []model.Point{{X: 2, Y: 2}, {X: 3, Y: 2}}},
+		},
+	}
+
+	m, err := DifficultyScorer{}.Score(level)
+	if err != nil {
+		t.Fatalf("Score: %v", err)
+	}
+	if m.MaxBlockingDepth != 2 {
+		t.Errorf("MaxBlockingDepth = %d, want 2", m.MaxBlockingDepth)
+	}
+	if m.SolutionLength != 3 {
+		t.Errorf("SolutionLength = %d, want 3", m.SolutionLength)
+	}
+	if m.ForcedMoveRatio != 1 {
+		t.Errorf("ForcedMoveRatio = %v, want 1", m.ForcedMoveRatio)
+	}
+	if want := 2.0 / 3.0; m.BlockedRatio != want {
+		t.Errorf("BlockedRatio = %v, want %v", m.BlockedRatio, want)
+	}
+
+	// Removing the chain must make the level score strictly easier
+	free := level
+	free.Vines = []model.Vine{level.Vines[0], level.Vines[2]}
+	easy, err := DifficultyScorer{}.Score(free)
+	if err != nil {
+		t.Fatalf("Score: %v", err)
+	}
+	if easy.Score >= m.Score {
+		t.Errorf("unchained score %.2f should be below chained score %.2f", easy.Score, m.Score)
+	}
+}
+
+func TestDifficultyScorerRejectsUnsolvable(t *testing.T) {
+	level := model.Level{
+		ID:       2,
+		GridSize: []int{4, 1},
+		Vines: []model.Vine{
+			{ID: "a", HeadDirection: "right", OrderedPath: []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}},
+			{ID: "b", HeadDirection: "left", OrderedPath: []model.Point{{X: 2, Y: 0}, {X: 3, Y: 0}}},
+		},
+	}
+	if _, _, err := (DifficultyScorer{}).Evaluate(level); err == nil {
+		t.Fatal("expected unsolvable level to fail scoring")
+	}
+}
+
+func TestInScoreBand(t *testing.T) {
+	if !InScoreBand("Seedling", 10) {
+		t.Error("10 should be inside the Seedling band")
+	}
+	if InScoreBand("Seedling", 40) {
+		t.Error("40 should be outside the Seedling band")
+	}
+	if !InScoreBand("Unknown", 40) {
+		t.Error("tiers without a band should always pass")
+	}
+}
+
+func TestInDepthBand(t *testing.T) {
+	if !InDepthBand("Seedling", 2) {
+		t.Error("depth 2 should be inside the Seedling band")
+	}
+	if InDepthBand("Seedling", 9) {
+		t.Error("depth 9 should be outside the Seedling band")
+	}
+	if InDepthBand("Transcendent", 1) {
+		t.Error("depth 1 should be too shallow for Transcendent")
+	}
+	if !InDepthBand("Tutorial", 9) {
+		t.Error("tiers without a band should always pass")
+	}
+}
+
+func TestGraceBasisFor(t *testing.T) {
+	spec := config.DifficultySpecs["Seedling"]
+	t.Cleanup(func() { config.DifficultySpecs["Seedling"] = spec })
+	tuned := spec
+	tuned.GraceDecisionsPerPoint, tuned.MaxGraceBonus = 1, 1
+	config.DifficultySpecs["Seedling"] = tuned
+
+	// Two free vines on top of three blocked ones: the first move is a
+	// branching decision
+	level := model.Level{
+		ID:         3,
+		Difficulty: "Seedling",
+		GridSize:   []int{2, 6},
+		Vines: []model.Vine{
+			{ID: "a", HeadDirection: "up", OrderedPath: []model.Point{{X: 0, Y: 5}, {X: 0, Y: 4}}},
+			{ID: "b", HeadDirection: "up", OrderedPath: []model.Point{{X: 1, Y: 5}, {X: 1, Y: 4}}},
+			{ID: "c", HeadDirection: "up", OrderedPath: []model.Point{{X: 0, Y: 3}, {X: 0, Y: 2}}},
+			{ID: "d", HeadDirection: "up", OrderedPath: []model.Point{{X: 1, Y: 3}, {X: 1, Y: 2}}},
+			{ID: "e", HeadDirection: "up", OrderedPath: []model.Point{{X: 0, Y: 1}, {X: 0, Y: 0}}},
+		},
+	}
+	basis := GraceBasisFor(level, []string{"a", "b", "c", "d", "e"})
+	if basis.Steps != 5 || basis.Branching != 1 || basis.Forced != 1 {
+		t.Errorf("expected 1 branching and 1 forced step in 5, got %+v", basis)
+	}
+	if basis.Base != spec.DefaultGrace || basis.Bonus != 1 || basis.Reason == "" {
+		t.Errorf("expected default grace plus one, got %+v", basis)
+	}
+
+	// The chain level only has forced steps
+	chain := model.Level{
+		ID:         1,
+		Difficulty: "Seedling",
+		GridSize:   []int{4, 4},
+		Vines: []model.Vine{
+			{ID: "a", HeadDirection: "right", OrderedPath: []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}},
+			{ID: "b", HeadDirection: "up", OrderedPath: []model.Point{{X: 2, Y: 1}, {X: 2, Y: 0}}},
+			{ID: "c", HeadDirection: "left", OrderedPath: []model.Point{{X: 2, Y: 2}, {X: 3, Y: 2}}},
+		},
+	}
+	if basis := GraceBasisFor(chain, []string{"c", "b", "a"}); basis.Branching != 0 || basis.Bonus != 0 {
+		t.Errorf("expected no bonus for a forced chain, got %+v", basis)
+	}
+}
+
+func TestAestheticsAnalyzer(t *testing.T) {
+	line := func(id string, pts ...model.Point) model.Vine {
+		return model.Vine{ID: id, HeadDirection: "right", OrderedPath: pts}
+	}
+
+	// A ruler-straight vine beside two touching fillers
+	plain := model.Level{Vines: []model.Vine{
+		line("a", model.Point{X: 5}, model.Point{X: 4}, model.Point{X: 3}, model.Point{X: 2}, model.Point{X: 1}, model.Point{X: 0}),
+		line("b", model.Point{X: 1, Y: 2}, model.Point{X: 0, Y: 2}),
+		line("c", model.Point{X: 1, Y: 3}, model.Point{X: 0, Y: 3}),
+	}}
+	m := AestheticsAnalyzer{}.Analyze(plain)
+	if m.TurnFrequency != 0 || m.LongRunShare != 0.6 || m.ShortVines != 2 || m.ShortClumping != 1 {
+		t.Errorf("plain metrics = %+v, want 0 turns, 0.6 long runs, 2 clumped short vines", m)
+	}
+
+	// A vine bending every third cell beside a lone filler
+	winding := model.Level{Vines: []model.Vine{
+		line("a", model.Point{X: 4, Y: 2}, model.Point{X: 3, Y: 2}, model.Point{X: 2, Y: 2}, model.Point{X: 2, Y: 1},
+			model.Point{X: 2, Y: 0}, model.Point{X: 1, Y: 0}, model.Point{X: 0, Y: 0}),
+		line("b", model.Point{X: 6, Y: 5}, model.Point{X: 5, Y: 5}),
+	}}
+	w := AestheticsAnalyzer{}.Analyze(winding)
+	if w.TurnFrequency != 0.4 || w.LongRunShare != 0 || w.ShortClumping != 0 {
+		t.Errorf("winding metrics = %+v, want 0.4 turns, no long runs, no clumping", w)
+	}
+	if w.Score <= m.Score {
+		t.Errorf("winding score %.1f should beat plain score %.1f", w.Score, m.Score)
+	}
+
+	// A corner cell closes a long run; the short leg after it is not long
+	if n := longRunCells(line("d", model.Point{X: 0}, model.Point{X: 1}, model.Point{X: 2}, model.Point{X: 3},
+		model.Point{X: 4}, model.Point{X: 4, Y: 1}).OrderedPath); n != 5 {
+		t.Errorf("longRunCells = %d, want 5", n)
+	}
+}
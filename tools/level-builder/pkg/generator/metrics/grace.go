@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// GraceBasisFor measures the decisions along solution and derives the level's
+// grace from them with config.GraceFor.
+//
+// A branching decision is a step where several vines could clear but blocked
+// vines outnumber them: the player has a real choice and more taps would bump
+// than clear, and bumps are what cost grace. Forced steps (one clearable vine) are recorded but
+// add no grace: they lengthen a blocker chain rather than widen the choice.
+func GraceBasisFor(level model.Level, solution []string) model.GraceBasis {
+	basis := model.GraceBasis{Steps: len(solution)}
+	for _, st := range solutionSteps(level, solution) {
+		switch {
+		case st.clearable == 1:
+			basis.Forced++
+		case st.remaining-st.clearable > st.clearable:
+			basis.Branching++
+		}
+	}
+
+	var grace int
+	grace, basis.Bonus = config.GraceFor(level.Difficulty, basis.Branching)
+	basis.Base = grace - basis.Bonus
+	basis.Reason = fmt.Sprintf("%s default %d, +%d for %d of %d moves branching",
+		level.Difficulty, basis.Base, basis.Bonus, basis.Branching, basis.Steps)
+	return basis
+}
@@ -1,14 +1,18 @@
 package generator
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/binary"
 	"fmt"
 	math_rand "math/rand"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/random"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/strategies"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
 )
@@ -17,8 +21,18 @@ import (
 // 1. Primary Placement (Center-Out LIFO)
 // 2. Recovery (Local Backtracking)
 // 3. Aggressive Gap Filling
-// 4. Mandatory Masking
-func GenerateRobust(cfg config.GenerationConfig) (model.Level, config.GenerationStats, error) {
+// 4. Filler Merging (joining touching short vines)
+// 5. Difficulty Injection (when cfg.InjectDifficulty is set)
+// 6. Placement Optimization (when cfg.OptimizeIterations is set)
+// 7. Mask Minimization (growing vines into cells left empty)
+// 8. Multi-head Tails (tiers with a MultiHeadRatio)
+// 9. Portals (when enabled, on tiers with PortalPairs)
+// 10. Locks (tiers with a LockedRatio)
+// 11. Mandatory Masking (or stencil gaps, when cfg.Stencil is set)
+// 12. Assembly (with solution hints when cfg.HintCount is set)
+//
+// Cancelling ctx stops placement between vines and returns ctx.Err().
+func GenerateRobust(ctx context.Context, cfg config.GenerationConfig) (model.Level, config.GenerationStats, error) {
 	startTime := time.Now()
 	stats := config.GenerationStats{}
 
@@ -27,22 +41,38 @@ func GenerateRobust(cfg config.GenerationConfig) (model.Level, config.Generation
 	if cfg.Randomize {
 		seed = cryptoSeedInt64()
 	}
-	rng := math_rand.New(math_rand.NewSource(seed))
+	src, err := random.NewSource(cfg.RNG, seed)
+	if err != nil {
+		return model.Level{}, stats, err
+	}
+	if cfg.RNGTraceFile != "" {
+		tracer := random.NewTracer(src)
+		src = tracer
+		// Failed attempts are traced too; they are the ones worth diffing
+		defer func() {
+			if err := tracer.Recording(cfg.RNG, seed).WriteFile(cfg.RNGTraceFile); err != nil {
+				common.Warning("Failed to write rng trace: %v", err)
+			}
+		}()
+	}
+	rng := math_rand.New(src)
 	common.Verbose("Starting Robust Generation for Level %d (Size: %dx%d, Seed: %d)",
 		cfg.LevelID, cfg.GridWidth, cfg.GridHeight, seed)
 
 	var placer config.VinePlacementStrategy
-	var err error
 
 	// Use the registry to get the requested strategy
 	if cfg.Strategy == "" {
 		// Default validation in case config is empty, but batch should handle this
-		if cfg.Difficulty == "Transcendent" {
-			cfg.Strategy = config.StrategyCenterOut
-		} else {
-			cfg.Strategy = config.StrategyDirectionFirst
+		cfg.Strategy = defaultStrategy(cfg.Difficulty)
+		if len(cfg.Stencil) > 0 {
+			cfg.Strategy = config.StrategyFullCoverage
 		}
 	}
+	if len(cfg.Stencil) > 0 && !slices.Contains(strategies.StencilStrategies, cfg.Strategy) {
+		return model.Level{}, stats, fmt.Errorf("strategy %s does not support stencils (use one of %s)",
+			cfg.Strategy, strings.Join(strategies.StencilStrategies, ", "))
+	}
 
 	placer, err = GetStrategy(cfg.Strategy)
 	if err != nil {
@@ -54,9 +84,13 @@ func GenerateRobust(cfg config.GenerationConfig) (model.Level, config.Generation
 
 	// 2. Initial Placement Phase
 	// Using "CenterOutPlacer" because it guarantees LIFO solvability by construction
-	vines, occupied, err := placer.PlaceVines(cfg, rng, &stats)
+	vines, occupied, err := placer.PlaceVines(ctx, cfg, rng, &stats)
 	// Note: PlaceVines internally handles backtracking for primary vines.
 	// If it returns error, it failed even after retries.
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		// Interrupted, not failed: no dump and no partial recovery
+		return model.Level{}, stats, ctxErr
+	}
 	if err != nil {
 		// If no failure dump was written by the placer (e.g. DirectionFirst doesn't use backtracking helper),
 		// write one now to ensure we have a deterministic record of the failure.
@@ -78,18 +112,12 @@ func GenerateRobust(cfg config.GenerationConfig) (model.Level, config.Generation
 			}
 		}
 	}
+	// Stencil gaps stay empty through every later phase
+	strategies.ReserveStencil(occupied, cfg.Stencil)
 
 	// 3. Aggressive Fill Phase
 	// Identify next available vine ID
-	nextVineID := 1
-	for _, v := range vines {
-		var id int
-		if n, _ := fmt.Sscanf(v.ID, "vine_%d", &id); n == 1 {
-			if id >= nextVineID {
-				nextVineID = id + 1
-			}
-		}
-	}
+	nextVineID := model.NextVineIndex(vines)
 
 	common.Verbose("Starting Aggressive Fill Phase...")
 	fillerVines, fillerOccupied := gapFiller.FillGaps(nextVineID, occupied)
@@ -103,10 +131,44 @@ func GenerateRobust(cfg config.GenerationConfig) (model.Level, config.Generation
 	common.Verbose("Added %d filler vines. Total coverage: %d/%d",
 		len(fillerVines), len(occupied), cfg.GridWidth*cfg.GridHeight)
 
+	// Filler Merge Phase
+	// Touching short vines, whether left by the placer or the gap filler, are
+	// joined into longer vines up to the tier's longest average length where
+	// that keeps the level solvable
+	spec, _ := config.SpecFor(cfg)
+	merger := strategies.NewFillerMerger(cfg.GridWidth, cfg.GridHeight, spec.AvgLengthRange[1])
+	if vines, stats.FillersMerged = merger.Merge(vines); stats.FillersMerged > 0 {
+		common.Verbose("Merged %d pairs of filler vines", stats.FillersMerged)
+	}
+
 	// 4. Sanitize Phase
 	// Ensure unique IDs before final assembly
 	vines = ensureUniqueVineIDs(vines)
 
+	// Difficulty Injection Phase
+	// Vines are reversed or regrown to deepen blocker chains toward the tier's
+	// target; each edit is checked for blocking cycles, so the level stays
+	// solvable
+	if cfg.InjectDifficulty {
+		injector := strategies.NewDifficultyInjector(cfg.GridWidth, cfg.GridHeight, spec.BlockingDepthTarget(), rng)
+		injector.Stencil = cfg.Stencil
+		if stats.DifficultyEdits = injector.Inject(vines); stats.DifficultyEdits > 0 {
+			common.Verbose("Edited %d vines to deepen blocker chains", stats.DifficultyEdits)
+		}
+	}
+
+	// Placement Optimization Phase
+	// Simulated annealing trades coverage, aesthetics and blocking depth
+	// against each other, visiting only solvable placements
+	if cfg.OptimizeIterations > 0 {
+		optimizer := strategies.NewPlacementOptimizer(cfg.GridWidth, cfg.GridHeight, spec, rng)
+		optimizer.Stencil = cfg.Stencil
+		res := optimizer.Optimize(vines, cfg.OptimizeIterations)
+		stats.OptimizerMoves = res.Accepted
+		common.Verbose("Optimizer accepted %d of %d moves, objective %.3f -> %.3f",
+			res.Accepted, cfg.OptimizeIterations, res.Start, res.Best)
+	}
+
 	// Rebuild fully consistent map
 	finalOccupied := make(map[string]string)
 	for _, v := range vines {
@@ -114,48 +176,102 @@ func GenerateRobust(cfg config.GenerationConfig) (model.Level, config.Generation
 			finalOccupied[fmt.Sprintf("%d,%d", p.X, p.Y)] = v.ID
 		}
 	}
+	for _, p := range cfg.Stencil {
+		if owner, ok := finalOccupied[common.PointKey(p)]; ok {
+			return model.Level{}, stats, fmt.Errorf("%s covers stencil cell (%d,%d)", owner, p.X, p.Y)
+		}
+	}
+	strategies.ReserveStencil(finalOccupied, cfg.Stencil)
+
+	// Mask Minimization Phase
+	// Cells the fillers left empty are absorbed into an adjacent vine where
+	// that keeps the level solvable, so fewer of them end up masked
+	if n := strategies.NewMaskMinimizer(cfg.GridWidth, cfg.GridHeight).Minimize(vines, finalOccupied); n > 0 {
+		stats.MaskCellsAbsorbed = n
+		common.Verbose("Absorbed %d empty cells into adjacent vines", n)
+	}
+
+	// Multi-head Phase
+	// Higher tiers give some vines a second head; this only adds exits, so it
+	// runs after placement and keeps every earlier solvability guarantee
+	if ratio := config.DifficultySpecs[cfg.Difficulty].MultiHeadRatio; ratio > 0 {
+		stats.MultiHeadVines = strategies.AssignTailHeads(vines, cfg.GridWidth, cfg.GridHeight, ratio, rng)
+		common.Verbose("Gave %d vines a second head", stats.MultiHeadVines)
+	}
+
+	// Portal Phase
+	// Portals sit on empty cells, so they are linked before masking hides them
+	var portals []model.Portal
+	if pairs := config.DifficultySpecs[cfg.Difficulty].PortalPairs; cfg.Portals && pairs > 0 {
+		portals = strategies.NewPortalPlacer(cfg.GridWidth, cfg.GridHeight, rng).PlacePortals(vines, finalOccupied, pairs)
+		stats.PortalPairs = len(portals)
+		common.Verbose("Linked %d portal pairs", stats.PortalPairs)
+	}
+
+	// Lock Phase
+	// Locks are taken from a clear order of the finished geometry, portals
+	// included, so they run last and cannot break solvability
+	if ratio := config.DifficultySpecs[cfg.Difficulty].LockedRatio; ratio > 0 {
+		stats.LockedVines = strategies.AssignLocks(vines, portals, cfg.GridWidth, cfg.GridHeight, ratio, rng)
+		common.Verbose("Locked %d vines until earlier clears", stats.LockedVines)
+	}
 
-	// 5. Mandatory Masking Phase
-	// Any cell not in finalOccupied MUST be masked to ensure 100% playable coverage
+	// 7. Mandatory Masking Phase
+	// Any cell not in finalOccupied (or a portal) MUST be masked to ensure 100% playable coverage
+	// In "show" mode the mask lists the occupied cells as an explicit playable region
+	portalCells := make(map[model.Point]bool)
+	for _, pt := range portals {
+		portalCells[pt.A], portalCells[pt.B] = true, true
+	}
+	covered := func(x, y int) bool {
+		_, occ := finalOccupied[fmt.Sprintf("%d,%d", x, y)]
+		return occ || portalCells[model.Point{X: x, Y: y}]
+	}
 	var mask *model.Mask
-	emptyCells := findEmptyCells(cfg.GridWidth, cfg.GridHeight, finalOccupied)
-	if len(emptyCells) > 0 {
-		common.Verbose("Masking %d empty cells to guarantee 100%% coverage", len(emptyCells))
-		mask = &model.Mask{Mode: "hide", Points: emptyCells}
+	if len(cfg.Stencil) > 0 {
+		// One mask cannot both hide empty cells and show the gaps, so a
+		// stencil level must cover every cell outside its pattern
+		if empty := model.NewOccupancyMask(model.MaskModeHide, cfg.GridWidth, cfg.GridHeight, covered); empty != nil {
+			return model.Level{}, stats, fmt.Errorf("stencil levels need every other cell covered, %d left empty",
+				len(empty.Points))
+		}
+		mask = model.NewGapMask(cfg.Stencil)
+		common.Verbose("Leaving %d stencil cells empty as decorative gaps", len(mask.Points))
+	} else if mask = model.NewOccupancyMask(cfg.MaskMode, cfg.GridWidth, cfg.GridHeight, covered); mask != nil {
+		common.Verbose("Masking %d empty cells (%s mode) to guarantee 100%% coverage",
+			mask.HiddenCells(cfg.GridWidth, cfg.GridHeight), mask.Mode)
+		stats.Relaxations++
 	}
 
-	// 6. Assembly
+	// 8. Assembly
 	level := assembler.AssembleLevel(cfg, vines, mask, seed)
+	level.Portals = portals
+	// An unsolvable level keeps its estimated move budget and gets no hints;
+	// validation rejects it later
+	if err := assembler.ApplySolution(&level, cfg); err != nil {
+		common.Verbose("Skipping solution-derived moves and hints: %v", err)
+	}
 
-	stats.GenerationTime = time.Since(startTime)
 	stats.GenerationTime = time.Since(startTime)
 
 	return level, stats, nil
 }
 
-func findEmptyCells(w, h int, occupied map[string]string) []model.Point {
-	var empty []model.Point
-	for y := 0; y < h; y++ {
-		for x := 0; x < w; x++ {
-			if _, occ := occupied[fmt.Sprintf("%d,%d", x, y)]; !occ {
-				empty = append(empty, model.Point{X: x, Y: y})
-			}
-		}
+// defaultStrategy is the placer used when a config names none.
+func defaultStrategy(difficulty string) string {
+	if difficulty == "Transcendent" {
+		return config.StrategyCenterOut
 	}
-	return empty
+	return config.StrategyDirectionFirst
 }
 
-// ensureUniqueVineIDs renames vines to have sequential IDs vine_1, vine_2, ...
-// preserving their original relative order.
+// ensureUniqueVineIDs renames vines to canonical sequential IDs vine_1,
+// vine_2, ... (see model.VineID) preserving their original relative order.
 func ensureUniqueVineIDs(vines []model.Vine) []model.Vine {
-	// Stable sort or keep order? Just keep order and renumber.
-	// But we might want to preserve IDs if possible to help debugging?
-	// Actually, strictly sequential IDs are cleaner for the game engine.
-
 	cleanVines := make([]model.Vine, len(vines))
 	for i, v := range vines {
 		cleanVines[i] = v
-		cleanVines[i].ID = fmt.Sprintf("vine_%d", i+1)
+		cleanVines[i].ID = model.VineID(i + 1)
 	}
 	return cleanVines
 }
@@ -0,0 +1,126 @@
+// Package random provides the random sources level generation draws from.
+//
+// Generation code takes a *math/rand.Rand; this package chooses the source
+// behind it. Every source is deterministic for a seed, so a level is
+// reproduced from its seed and source name:
+//
+//   - "math" (default): Go's math/rand source, which existing levels and
+//     golden files were generated with
+//   - "pcg": PCG-DXSM with 128 bits of state (math/rand/v2's PCG)
+//   - "splitmix": SplitMix64, a small and fast 64-bit generator
+//
+// A Recorder captures the values a source produces and a Replayer plays them
+// back, so an RNG stream can be saved and re-run while debugging. A tracing
+// Recorder (NewTracer) also notes the call site of every draw, and Diverge
+// finds the first draw at which two traced runs of the same seed part ways.
+package random
+
+import (
+	"fmt"
+	"math/rand"
+	randv2 "math/rand/v2"
+	"sort"
+	"strings"
+)
+
+// Source names accepted by New.
+const (
+	Math     = "math"
+	PCG      = "pcg"
+	SplitMix = "splitmix"
+)
+
+// Default is the source used when none is named.
+const Default = Math
+
+var sources = map[string]func(seed int64) rand.Source64{
+	Math: func(seed int64) rand.Source64 {
+		return rand.NewSource(seed).(rand.Source64)
+	},
+	PCG: func(seed int64) rand.Source64 {
+		s := &pcgSource{pcg: &randv2.PCG{}}
+		s.Seed(seed)
+		return s
+	},
+	SplitMix: func(seed int64) rand.Source64 {
+		return &SplitMix64{state: uint64(seed)}
+	},
+}
+
+// Names returns the accepted source names in sorted order.
+func Names() []string {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Check reports whether name is a known source. Empty selects Default.
+func Check(name string) error {
+	if name == "" {
+		return nil
+	}
+	if _, ok := sources[name]; !ok {
+		return fmt.Errorf("unknown rng %q (want one of %s)", name, strings.Join(Names(), ", "))
+	}
+	return nil
+}
+
+// NewSource returns the named source seeded with seed. Empty selects Default.
+func NewSource(name string, seed int64) (rand.Source64, error) {
+	if err := Check(name); err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = Default
+	}
+	return sources[name](seed), nil
+}
+
+// New returns a *rand.Rand drawing from the named source seeded with seed.
+func New(name string, seed int64) (*rand.Rand, error) {
+	src, err := NewSource(name, seed)
+	if err != nil {
+		return nil, err
+	}
+	return rand.New(src), nil
+}
+
+// pcgSource adapts math/rand/v2's PCG to a math/rand source.
+type pcgSource struct {
+	pcg *randv2.PCG
+}
+
+// Seed expands seed into PCG's two state words with SplitMix64.
+func (s *pcgSource) Seed(seed int64) {
+	sm := SplitMix64{state: uint64(seed)}
+	s.pcg.Seed(sm.Uint64(), sm.Uint64())
+}
+
+// Uint64 returns the next value in the sequence.
+func (s *pcgSource) Uint64() uint64 { return s.pcg.Uint64() }
+
+// Int63 returns the next value with the top bit cleared.
+func (s *pcgSource) Int63() int64 { return int64(s.pcg.Uint64() >> 1) }
+
+// SplitMix64 is Steele, Lea and Flood's SplitMix64 generator.
+type SplitMix64 struct {
+	state uint64
+}
+
+// Seed resets the generator to seed.
+func (s *SplitMix64) Seed(seed int64) { s.state = uint64(seed) }
+
+// Uint64 returns the next value in the sequence.
+func (s *SplitMix64) Uint64() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Int63 returns the next value with the top bit cleared.
+func (s *SplitMix64) Int63() int64 { return int64(s.Uint64() >> 1) }
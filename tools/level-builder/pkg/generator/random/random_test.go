@@ -0,0 +1,99 @@
+package random
+
+import (
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func draw(r *rand.Rand) []int {
+	out := []int{r.Intn(1000), r.Intn(1000), int(r.Uint64() % 1000)}
+	perm := r.Perm(5)
+	return append(out, perm...)
+}
+
+func TestSourcesAreDeterministic(t *testing.T) {
+	seen := make(map[string]string)
+	for _, name := range Names() {
+		a, err := New(name, 42)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := New(name, 42)
+		first, second := draw(a), draw(b)
+		if !reflect.DeepEqual(first, second) {
+			t.Errorf("%s: same seed gave %v and %v", name, first, second)
+		}
+		key := fmt.Sprint(first)
+		if other, dup := seen[key]; dup {
+			t.Errorf("%s and %s produced the same stream", name, other)
+		}
+		seen[key] = name
+	}
+
+	if _, err := New("mersenne", 1); err == nil {
+		t.Error("expected an error for an unknown source")
+	}
+	def, _ := New("", 7)
+	math := rand.New(rand.NewSource(7))
+	if !reflect.DeepEqual(draw(def), draw(math)) {
+		t.Error("the default source should match math/rand")
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	src, _ := NewSource(PCG, 99)
+	rec := NewRecorder(src)
+	want := draw(rand.New(rec))
+
+	path := filepath.Join(t.TempDir(), "rng.json")
+	if err := (Recording{Source: PCG, Seed: 99, Draws: rec.Draws()}).WriteFile(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadRecording(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replay := NewReplayer(loaded.Draws)
+	if got := draw(rand.New(replay)); !reflect.DeepEqual(got, want) {
+		t.Errorf("replay gave %v, want %v", got, want)
+	}
+	if replay.Remaining() != 0 || replay.Err() != nil {
+		t.Errorf("expected the recording to be used up exactly, remaining %d err %v", replay.Remaining(), replay.Err())
+	}
+	replay.Uint64()
+	if replay.Err() == nil {
+		t.Error("expected an error after drawing past the recording")
+	}
+}
+
+func TestTraceAndDiverge(t *testing.T) {
+	run := func(extra bool) Recording {
+		src, _ := NewSource(SplitMix, 5)
+		tracer := NewTracer(src)
+		r := rand.New(tracer)
+		r.Intn(10)
+		if extra {
+			r.Float64()
+		}
+		r.Intn(10)
+		return tracer.Recording(SplitMix, 5)
+	}
+
+	a, b := run(false), run(true)
+	if len(a.Sites) != len(a.Draws) || !strings.Contains(a.Sites[0], "random_test.go") {
+		t.Fatalf("expected call sites in this file, got %v", a.Sites)
+	}
+	if d := Diverge(a, run(false)); d != nil {
+		t.Errorf("identical runs should not diverge, got %+v", d)
+	}
+
+	d := Diverge(a, b)
+	if d == nil || d.Index != 1 || d.Shared != a.Sites[0] || d.B.Site == d.A.Site {
+		t.Fatalf("expected divergence at the extra draw, got %+v", d)
+	}
+}
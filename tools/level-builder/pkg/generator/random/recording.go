@@ -0,0 +1,231 @@
+package random
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Recording is a saved RNG stream: the source and seed that produced it and
+// every value drawn, in order. Traced recordings also name the call site of
+// each draw.
+type Recording struct {
+	Source string   `json:"source"`
+	Seed   int64    `json:"seed"`
+	Draws  []uint64 `json:"draws"`
+	Sites  []string `json:"sites,omitempty"`
+}
+
+// LoadRecording reads a recording written by Recording.WriteFile.
+func LoadRecording(path string) (Recording, error) {
+	var rec Recording
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return rec, fmt.Errorf("failed to read rng recording: %w", err)
+	}
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return rec, fmt.Errorf("failed to parse rng recording %s: %w", path, err)
+	}
+	return rec, nil
+}
+
+// WriteFile writes the recording to path as JSON.
+func (r Recording) WriteFile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create recording dir: %w", err)
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal rng recording: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Recorder is a source that passes through another source's values and keeps
+// a copy of each.
+type Recorder struct {
+	src   rand.Source64
+	draws []uint64
+	sites []string // nil unless tracing
+	trace bool
+}
+
+// NewRecorder records the values drawn from src.
+func NewRecorder(src rand.Source64) *Recorder {
+	return &Recorder{src: src}
+}
+
+// NewTracer records the values drawn from src along with the generation code
+// that drew each one. Finding the caller costs a stack walk per draw, so it is
+// meant for debugging runs.
+func NewTracer(src rand.Source64) *Recorder {
+	return &Recorder{src: src, trace: true}
+}
+
+// Int63 draws from the wrapped source and records the value.
+func (r *Recorder) Int63() int64 {
+	v := r.src.Int63()
+	r.record(uint64(v))
+	return v
+}
+
+// Uint64 draws from the wrapped source and records the value.
+func (r *Recorder) Uint64() uint64 {
+	v := r.src.Uint64()
+	r.record(v)
+	return v
+}
+
+// Seed reseeds the wrapped source and starts a new recording.
+func (r *Recorder) Seed(seed int64) {
+	r.src.Seed(seed)
+	r.draws, r.sites = nil, nil
+}
+
+// Draws returns the values recorded so far.
+func (r *Recorder) Draws() []uint64 {
+	return append([]uint64(nil), r.draws...)
+}
+
+// Recording returns the draws so far as a recording of source seeded with seed.
+func (r *Recorder) Recording(source string, seed int64) Recording {
+	if source == "" {
+		source = Default
+	}
+	return Recording{
+		Source: source,
+		Seed:   seed,
+		Draws:  r.Draws(),
+		Sites:  append([]string(nil), r.sites...),
+	}
+}
+
+func (r *Recorder) record(v uint64) {
+	r.draws = append(r.draws, v)
+	if r.trace {
+		r.sites = append(r.sites, callSite())
+	}
+}
+
+// Replayer is a source that plays back recorded values in order. Draws past
+// the end of the recording return 0 and are counted, so a replay that ran
+// longer than its recording can be detected with Err.
+type Replayer struct {
+	draws    []uint64
+	next     int
+	overruns int
+}
+
+// NewReplayer plays back draws.
+func NewReplayer(draws []uint64) *Replayer {
+	return &Replayer{draws: draws}
+}
+
+// Int63 returns the next recorded value with the top bit cleared.
+func (r *Replayer) Int63() int64 {
+	return int64(r.pop() & (1<<63 - 1))
+}
+
+// Uint64 returns the next recorded value.
+func (r *Replayer) Uint64() uint64 {
+	return r.pop()
+}
+
+// Seed rewinds to the start of the recording; the seed itself is ignored.
+func (r *Replayer) Seed(int64) {
+	r.next, r.overruns = 0, 0
+}
+
+// Remaining returns how many recorded values have not been drawn yet.
+func (r *Replayer) Remaining() int {
+	return len(r.draws) - r.next
+}
+
+// Err reports draws made after the recording ran out.
+func (r *Replayer) Err() error {
+	if r.overruns == 0 {
+		return nil
+	}
+	return fmt.Errorf("replay drew %d values past the end of a %d-value recording", r.overruns, len(r.draws))
+}
+
+func (r *Replayer) pop() uint64 {
+	if r.next >= len(r.draws) {
+		r.overruns++
+		return 0
+	}
+	v := r.draws[r.next]
+	r.next++
+	return v
+}
+
+// callSite names the first caller outside math/rand and the Recorder, as
+// "file.go:line package.Function".
+func callSite() string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		fn := frame.Function
+		if !strings.HasPrefix(fn, "math/rand.") && !strings.Contains(fn, "/generator/random.(*Recorder)") {
+			return fmt.Sprintf("%s:%d %s", filepath.Base(frame.File), frame.Line, fn[strings.LastIndex(fn, "/")+1:])
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// Divergence is the first point at which two recordings differ.
+type Divergence struct {
+	Index int // Index of the first differing draw
+	// Shared is the call site of the last draw both recordings agree on
+	// (empty when they differ from the first draw)
+	Shared string
+	A, B   Draw
+}
+
+// Draw is one value in a recording; Missing is set past the recording's end.
+type Draw struct {
+	Value   uint64
+	Site    string
+	Missing bool
+}
+
+// Diverge returns where b first departs from a, comparing values and, when
+// both recordings are traced, call sites. It returns nil for identical streams.
+func Diverge(a, b Recording) *Divergence {
+	traced := len(a.Sites) == len(a.Draws) && len(b.Sites) == len(b.Draws) && len(a.Sites) > 0 && len(b.Sites) > 0
+	n := len(a.Draws)
+	if len(b.Draws) > n {
+		n = len(b.Draws)
+	}
+	for i := 0; i < n; i++ {
+		da, db := a.draw(i), b.draw(i)
+		if da.Missing == db.Missing && da.Value == db.Value && (!traced || da.Site == db.Site) {
+			continue
+		}
+		d := &Divergence{Index: i, A: da, B: db}
+		if i > 0 {
+			d.Shared = a.draw(i - 1).Site
+		}
+		return d
+	}
+	return nil
+}
+
+func (r Recording) draw(i int) Draw {
+	if i >= len(r.Draws) {
+		return Draw{Missing: true}
+	}
+	d := Draw{Value: r.Draws[i]}
+	if i < len(r.Sites) {
+		d.Site = r.Sites[i]
+	}
+	return d
+}
@@ -76,19 +76,27 @@ func init() {
 		return &strategies.DirectionFirstPlacer{}
 	})
 
+	RegisterStrategy(config.StrategyFullCoverage, "Full-coverage backtracking strategy (100% occupancy, no masks)", func() config.VinePlacementStrategy {
+		return &strategies.FullCoveragePlacer{}
+	})
+
+	RegisterStrategy(config.StrategyChain, "Blocking chains built to the tier's max blocking depth (cascading locks)", func() config.VinePlacementStrategy {
+		return &strategies.ChainPlacer{}
+	})
+
 	// CircuitBoard is experimental/legacy but preserved
 	RegisterStrategy("circuit-board", "Circuit-board aesthetic (experimental)", func() config.VinePlacementStrategy {
 		return &strategies.CircuitBoardPlacer{}
 	})
 
 	// Legacy strategies
-	RegisterStrategy(strategies.StrategyLegacyTiling, "Legacy Tiling (Standard)", func() config.VinePlacementStrategy {
+	RegisterStrategy(config.StrategyLegacyTiling, "Legacy Tiling (Standard)", func() config.VinePlacementStrategy {
 		return &strategies.LegacyTilingStrategy{}
 	})
-	RegisterStrategy(strategies.StrategyLegacyClearable, "Legacy Clearable-First", func() config.VinePlacementStrategy {
+	RegisterStrategy(config.StrategyLegacyClearable, "Legacy Clearable-First", func() config.VinePlacementStrategy {
 		return &strategies.LegacyClearableStrategy{}
 	})
-	RegisterStrategy(strategies.StrategyLegacySolverAware, "Legacy Solver-Aware", func() config.VinePlacementStrategy {
+	RegisterStrategy(config.StrategyLegacySolverAware, "Legacy Solver-Aware", func() config.VinePlacementStrategy {
 		return &strategies.LegacySolverAwareStrategy{}
 	})
 }
@@ -1,10 +1,16 @@
 package generator
 
 import (
+	"context"
+	"errors"
+	"math/rand"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/strategies"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
 )
 
 func TestRegisterStrategy(t *testing.T) {
@@ -48,7 +54,11 @@ func TestGetUnknownStrategy(t *testing.T) {
 
 func TestCoreStrategiesRegistered(t *testing.T) {
 	// Verify core strategies are present
-	expected := []string{config.StrategyCenterOut, config.StrategyDirectionFirst, "circuit-board"}
+	expected := []string{
+		config.StrategyCenterOut, config.StrategyDirectionFirst, config.StrategyFullCoverage, "circuit-board",
+		config.StrategyLegacyTiling, config.StrategyLegacyClearable, config.StrategyLegacySolverAware,
+		config.StrategyChain,
+	}
 
 	for _, name := range expected {
 		_, err := GetStrategy(name)
@@ -57,3 +67,68 @@ func TestCoreStrategiesRegistered(t *testing.T) {
 		}
 	}
 }
+
+func TestStrategiesStopWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cfg := config.GenerationConfig{
+		LevelID:     1,
+		GridWidth:   7,
+		GridHeight:  10,
+		VineCount:   8,
+		MinCoverage: 1.0,
+		Difficulty:  "Seedling",
+		DumpDir:     t.TempDir(),
+	}
+	for _, info := range ListStrategies() {
+		placer, err := GetStrategy(info.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _, err = placer.PlaceVines(ctx, cfg, rand.New(rand.NewSource(1)), &config.GenerationStats{})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected context.Canceled, got %v", info.Name, err)
+		}
+	}
+
+	if _, _, err := GenerateRobust(ctx, cfg); !errors.Is(err, context.Canceled) {
+		t.Errorf("GenerateRobust: expected context.Canceled, got %v", err)
+	}
+}
+
+func TestStencilPlacersKeepOutOfGaps(t *testing.T) {
+	gaps := []model.Point{{X: 3, Y: 4}, {X: 3, Y: 5}, {X: 4, Y: 5}}
+	cfg := config.GenerationConfig{
+		LevelID:     1,
+		GridWidth:   7,
+		GridHeight:  10,
+		VineCount:   8,
+		MinCoverage: 0.9,
+		Difficulty:  "Sprout",
+		DumpDir:     t.TempDir(),
+		Stencil:     gaps,
+	}
+	for _, name := range strategies.StencilStrategies {
+		placer, err := GetStrategy(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		vines, _, err := placer.PlaceVines(context.Background(), cfg, rand.New(rand.NewSource(1)), &config.GenerationStats{})
+		if err != nil || len(vines) == 0 {
+			t.Fatalf("%s: placed %d vines: %v", name, len(vines), err)
+		}
+		for _, v := range vines {
+			for _, p := range v.OrderedPath {
+				if slices.Contains(gaps, p) {
+					t.Errorf("%s: vine %s occupies stencil cell (%d,%d)", name, v.ID, p.X, p.Y)
+				}
+			}
+		}
+	}
+
+	cfg.Strategy = config.StrategyLegacyClearable
+	if _, _, err := GenerateRobust(context.Background(), cfg); err == nil || !strings.Contains(err.Error(), "stencil") {
+		t.Errorf("GenerateRobust with %s: %v, want a stencil error", cfg.Strategy, err)
+	}
+}
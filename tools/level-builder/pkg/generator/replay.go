@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/random"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/strategies"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// ReplayResult is the outcome of replaying a failure dump.
+type ReplayResult struct {
+	Vines []model.Vine // vines the placer returned
+	Stats config.GenerationStats
+	Err   error // placement error; nil when the failure no longer occurs
+	// Dumps are the failure dumps the replay wrote, in the order written.
+	Dumps []strategies.FailureDump
+	// Reproduced reports whether one of Dumps matches the original exactly.
+	Reproduced bool
+}
+
+// Replay re-runs the placement phase that produced original, seeding the
+// placer exactly as GenerateRobust does. cfg is the config to replay, usually
+// original.Config. Placement is deterministic for a fixed seed, so until the
+// placer changes the replay fails the same way and writes the same dump.
+//
+// The replay's own dumps go to a temporary directory and are returned rather
+// than kept.
+func Replay(ctx context.Context, cfg config.GenerationConfig, original strategies.FailureDump) (ReplayResult, error) {
+	dir, err := os.MkdirTemp("", "replay_dumps_")
+	if err != nil {
+		return ReplayResult{}, err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	cfg.Seed = original.Seed
+	cfg.Randomize = false
+	cfg.DumpDir = dir
+	if cfg.Strategy == "" {
+		cfg.Strategy = defaultStrategy(cfg.Difficulty)
+	}
+	placer, err := GetStrategy(cfg.Strategy)
+	if err != nil {
+		return ReplayResult{}, fmt.Errorf("failed to get strategy %s: %w", cfg.Strategy, err)
+	}
+
+	rng, err := random.New(cfg.RNG, cfg.Seed)
+	if err != nil {
+		return ReplayResult{}, err
+	}
+	var result ReplayResult
+	vines, occupied, err := placer.PlaceVines(ctx, cfg, rng, &result.Stats)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ReplayResult{}, ctxErr
+	}
+	result.Vines = vines
+	result.Err = err
+	if err != nil && result.Stats.DumpsProduced == 0 {
+		// Mirror GenerateRobust, which dumps for placers that don't
+		_ = strategies.WriteFailureDump(cfg, cfg.Seed, 0, err.Error(), vines, occupied, &result.Stats)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return ReplayResult{}, err
+	}
+	// Dump names only differ by their timestamp suffix
+	sort.Strings(paths)
+	for _, path := range paths {
+		dump, err := strategies.LoadFailureDump(path)
+		if err != nil {
+			return ReplayResult{}, err
+		}
+		result.Dumps = append(result.Dumps, dump)
+		if dump.Same(original) {
+			result.Reproduced = true
+		}
+	}
+	return result, nil
+}
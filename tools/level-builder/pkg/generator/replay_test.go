@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/strategies"
+)
+
+func TestReplayReproducesFailureDump(t *testing.T) {
+	// Too many vines for the grid: direction-first falls short of full coverage
+	dir := t.TempDir()
+	cfg := config.GenerationConfig{
+		LevelID:     9,
+		GridWidth:   5,
+		GridHeight:  5,
+		VineCount:   40,
+		MaxMoves:    80,
+		Seed:        11,
+		MinCoverage: 1.0,
+		Difficulty:  "Seedling",
+		Strategy:    config.StrategyDirectionFirst,
+		DumpDir:     dir,
+	}
+	if _, _, err := GenerateRobust(context.Background(), cfg); err == nil {
+		t.Fatal("expected generation to fail")
+	}
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(paths) != 1 {
+		t.Fatalf("expected 1 failure dump, got %d", len(paths))
+	}
+	dump, err := strategies.LoadFailureDump(paths[0])
+	if err != nil {
+		t.Fatalf("LoadFailureDump: %v", err)
+	}
+	if dump.Config == nil || dump.Config.Strategy != cfg.Strategy || dump.Config.VineCount != cfg.VineCount {
+		t.Fatalf("dump did not record the config: %+v", dump.Config)
+	}
+
+	result, err := Replay(context.Background(), *dump.Config, dump)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if result.Err == nil || !result.Reproduced {
+		t.Fatalf("replay did not reproduce the failure: err=%v dumps=%d", result.Err, len(result.Dumps))
+	}
+
+	// A dump the replay cannot match is reported as not reproduced
+	dump.Message = "some other failure"
+	if result, _ := Replay(context.Background(), *dump.Config, dump); result.Reproduced {
+		t.Error("replay should not match a different failure")
+	}
+}
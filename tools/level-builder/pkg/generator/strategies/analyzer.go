@@ -30,6 +30,22 @@ func (a *DFSBlockingAnalyzer) AnalyzeBlocking(vines []model.Vine, occupied map[s
 	return analysis, nil
 }
 
+// BlockingGraph returns the graph AnalyzeBlocking inspects: graph[A] lists the
+// vines A blocks, in vine order.
+func (a *DFSBlockingAnalyzer) BlockingGraph(vines []model.Vine, occupied map[string]string) map[string][]string {
+	return a.buildBlockingGraph(vines, occupied)
+}
+
+// BlockingDepths returns, for each vine, the length of the longest blocking
+// chain that starts at it (0 when it blocks nothing).
+func (a *DFSBlockingAnalyzer) BlockingDepths(graph map[string][]string, vines []model.Vine) map[string]int {
+	cache := make(map[string]int)
+	for _, vine := range vines {
+		a.findMaxDepthFromVine(vine.ID, graph, make(map[string]bool), cache)
+	}
+	return cache
+}
+
 // buildBlockingGraph creates a graph where A -> B means "A blocks B"
 func (a *DFSBlockingAnalyzer) buildBlockingGraph(vines []model.Vine, occupied map[string]string) map[string][]string {
 	graph := make(map[string][]string)
@@ -60,12 +76,7 @@ func (a *DFSBlockingAnalyzer) buildBlockingGraph(vines []model.Vine, occupied ma
 // Uses memoization to avoid repeated DFS work and to keep runtime bounded.
 func (a *DFSBlockingAnalyzer) calculateMaxBlockingDepth(graph map[string][]string, vines []model.Vine) int {
 	maxDepth := 0
-	// cache stores computed depths for nodes
-	cache := make(map[string]int)
-
-	// For each vine, find the longest path starting from it
-	for _, vine := range vines {
-		depth := a.findMaxDepthFromVine(vine.ID, graph, make(map[string]bool), cache)
+	for _, depth := range a.BlockingDepths(graph, vines) {
 		if depth > maxDepth {
 			maxDepth = depth
 		}
@@ -191,7 +202,5 @@ func (a *DFSBlockingAnalyzer) vineBlocksVine(blocker, blocked model.Vine, occupi
 	}
 
 	key := fmt.Sprintf("%d,%d", targetX, targetY)
-	blockingVine, targetOccupied := occupied[key]
-	headVine, headOccupied := occupied[fmt.Sprintf("%d,%d", head.X, head.Y)]
-	return targetOccupied && headOccupied && blockingVine != headVine
+	return occupied[key] == blocker.ID && blocker.ID != blocked.ID
 }
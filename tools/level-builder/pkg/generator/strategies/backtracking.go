@@ -2,6 +2,7 @@ package strategies
 
 import (
 	"fmt"
+	"maps"
 	"math/rand"
 	"sort"
 
@@ -68,7 +69,8 @@ func AttemptLocalBacktrack(
 				}
 			}
 
-			vineAttempt, newOcc, err := p.placeVineWithExitGuarantee(vineID, targetLen, w, h, occCopy, rng, stats)
+			// Recovery placements only need a clear exit, so they skip head balancing
+			vineAttempt, newOcc, err := p.placeVineWithExitGuarantee(vineID, targetLen, w, h, occCopy, nil, rng, stats)
 			if err == nil {
 				// successful placement after removing candidate
 				for k, v := range newOcc {
@@ -80,7 +82,9 @@ func AttemptLocalBacktrack(
 		}
 	}
 
-	// Fallback: original last-N-window approach
+	// Fallback: original last-N-window approach. backtrackVines deletes from the map it is
+	// given, so work on a copy: a failed recovery must leave the caller's occupancy intact.
+	occupied = maps.Clone(occupied)
 	for ba := 0; ba < maxBack; ba++ {
 		if stats != nil {
 			stats.BacktracksAttempted++
@@ -91,7 +95,7 @@ func AttemptLocalBacktrack(
 		common.Verbose("AttemptLocalBacktrack: removing %d vines (attempt %d/%d) to recover %s", backtrackWindow, ba+1, maxBack, vineID)
 		vines, occupied = backtrackVines(vines, occupied, backtrackWindow)
 
-		vine, newOcc, err := p.placeVineWithExitGuarantee(vineID, targetLen, w, h, occupied, rng, stats)
+		vine, newOcc, err := p.placeVineWithExitGuarantee(vineID, targetLen, w, h, occupied, nil, rng, stats)
 		if err == nil {
 			// Successful recovery
 			for k, v := range newOcc {
@@ -264,7 +268,7 @@ func tryRemoveCandidatesAndPlace(
 		}
 	}
 
-	vineAttempt, newOcc, err := p.placeVineWithExitGuarantee(vineID, targetLen, w, h, oCopy, rng, stats)
+	vineAttempt, newOcc, err := p.placeVineWithExitGuarantee(vineID, targetLen, w, h, oCopy, nil, rng, stats)
 	if err != nil {
 		return struct {
 			vine    model.Vine
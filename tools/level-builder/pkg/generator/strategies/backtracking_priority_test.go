@@ -1,6 +1,7 @@
 package strategies
 
 import (
+	"context"
 	"math/rand"
 	"testing"
 
@@ -31,7 +32,7 @@ func TestPlaceVinesWithStatsSanity(t *testing.T) {
 	cfg := config.GenerationConfig{LevelID: 1, GridWidth: 7, GridHeight: 10, VineCount: 8, MinCoverage: 0.8, Seed: 31337}
 	rng := rand.New(rand.NewSource(cfg.Seed))
 	p := &CenterOutPlacer{}
-	_, occ, err := p.PlaceVines(cfg, rng, &config.GenerationStats{})
+	_, occ, err := p.PlaceVines(context.Background(), cfg, rng, &config.GenerationStats{})
 	if err != nil {
 		t.Fatalf("PlaceVines with stats should not error unexpectedly: %v", err)
 	}
@@ -1,6 +1,7 @@
 package strategies_test
 
 import (
+	"context"
 	"math/rand"
 	"path/filepath"
 	"testing"
@@ -32,7 +33,7 @@ func TestLIFOBacktrackingProducesDumpsAndSucceeds(t *testing.T) {
 		OutputFile:           filepath.Join(tmpDir, "level_1.json"),
 	}
 
-	level, stats, err := generator.GenerateLevelLIFO(genCfg)
+	level, stats, err := generator.GenerateLevelLIFO(context.Background(), genCfg)
 	if err != nil {
 		t.Fatalf("expected generation to succeed, got error: %v", err)
 	}
@@ -66,7 +67,7 @@ func TestAttemptLocalBacktrackRecovers(t *testing.T) {
 
 	rng := rand.New(rand.NewSource(genCfg.Seed))
 	placer := &strategies.CenterOutPlacer{}
-	vines, _, err := placer.PlaceVines(genCfg, rng, &config.GenerationStats{})
+	vines, _, err := placer.PlaceVines(context.Background(), genCfg, rng, &config.GenerationStats{})
 	if err != nil {
 		t.Fatalf("expected PlaceVines to succeed with aggressive backtracking, got: %v", err)
 	}
@@ -95,7 +96,7 @@ func TestCycleBreakerRepairRecovers(t *testing.T) {
 		OutputFile:           filepath.Join(t.TempDir(), "level_28.json"),
 	}
 
-	level, stats, err := generator.GenerateLevelLIFO(genCfg)
+	level, stats, err := generator.GenerateLevelLIFO(context.Background(), genCfg)
 	if err != nil {
 		t.Fatalf("expected cycle-breaker to recover and succeed for seed %d, got: %v", genCfg.Seed, err)
 	}
@@ -127,7 +128,7 @@ func TestCycleBreakerMultiRemovalRecovers(t *testing.T) {
 		OutputFile:           filepath.Join(t.TempDir(), "level_28.json"),
 	}
 
-	level, stats, err := generator.GenerateLevelLIFO(genCfg)
+	level, stats, err := generator.GenerateLevelLIFO(context.Background(), genCfg)
 	if err != nil {
 		t.Fatalf("expected multi-removal cycle-breaker to recover and succeed for seed %d, got: %v", genCfg.Seed, err)
 	}
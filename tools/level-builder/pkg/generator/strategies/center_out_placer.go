@@ -1,6 +1,7 @@
 package strategies
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"math/rand"
@@ -8,20 +9,32 @@ import (
 
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/utils"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
 )
 
 // CenterOutPlacer implements a center-out vine placement strategy with LIFO solvability guarantee.
 // Key insight: if each vine has a clear exit path when placed, solving in reverse order is always valid.
 // This eliminates expensive A* solver checks entirely.
-type CenterOutPlacer struct{}
+type CenterOutPlacer struct {
+	// Filler overrides the gap-filling phase. When nil, the filler named by
+	// GenerationConfig.FillerStrategy (default DefaultFiller) is used.
+	Filler FillerStrategy
+
+	tuning config.CenterOutTuning // Resolved by PlaceVines (see config.CenterOutFor)
+	sweep  int                    // Round-robin sweep of the next seed
+}
 
 // PlaceVines places vines from center outward, guaranteeing each has a clear exit at placement time.
 // Returns vines that can be solved in LIFO order (last placed = first cleared).
-func (p *CenterOutPlacer) PlaceVines(config config.GenerationConfig, rng *rand.Rand, stats *config.GenerationStats) ([]model.Vine, map[string]string, error) {
+func (p *CenterOutPlacer) PlaceVines(ctx context.Context, config config.GenerationConfig, rng *rand.Rand, stats *config.GenerationStats) ([]model.Vine, map[string]string, error) {
 	w, h := config.GridWidth, config.GridHeight
 	totalCells := w * h
-	occupied := make(map[string]string)
+	occupied := ReserveStencil(make(map[string]string), config.Stencil)
+
+	if err := p.resolveTuning(config); err != nil {
+		return nil, nil, err
+	}
 
 	// Calculate target lengths based on difficulty
 	targetLengths := p.calculateVineLengths(config, rng)
@@ -36,11 +49,16 @@ func (p *CenterOutPlacer) PlaceVines(config config.GenerationConfig, rng *rand.R
 
 	// Phase 1: Place vines from center outward with LIFO guarantee
 	for _, targetLen := range targetLengths {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
 		// Use dynamic ID based on current placed vines to avoid duplicates
-		vineID := fmt.Sprintf("vine_%d", len(vines)+1)
+		vineID := model.VineID(len(vines) + 1)
+		p.sweep = len(vines)
 
 		vine, newOccupied, err := p.placeVineWithExitGuarantee(
-			vineID, targetLen, w, h, occupied, rng, stats,
+			vineID, targetLen, w, h, occupied, headBalancer(config, vines), rng, stats,
 		)
 		if err != nil {
 			common.Verbose("Could not place vine %s: %v", vineID, err)
@@ -87,10 +105,27 @@ func (p *CenterOutPlacer) PlaceVines(config config.GenerationConfig, rng *rand.R
 	coverage = float64(len(occupied)) / float64(totalCells)
 	if coverage < config.MinCoverage {
 		common.Verbose("Coverage %.1f%% below target %.1f%%, adding filler vines...", coverage*100, config.MinCoverage*100)
-		fillerVines, fillerOccupied := p.createFillerVines(vines, occupied, w, h, config.MinCoverage, rng)
-		vines = append(vines, fillerVines...)
+		filler, err := p.fillerStrategy(config)
+		if err != nil {
+			return nil, nil, err
+		}
+		fillerVines, fillerOccupied := filler.FillGaps(vines, occupied, w, h, config.MinCoverage, rng)
+
+		// Drop fillers that would close a blocking cycle with the vines already placed
+		solver := utils.NewIncrementalSolver(w, h)
+		solver.Reset(vines)
+		for _, fv := range fillerVines {
+			if !solver.CanPlace(fv) {
+				stats.SolvabilityPrunes++
+				continue
+			}
+			solver.Place(fv)
+			vines = append(vines, fv)
+		}
 		for k, v := range fillerOccupied {
-			occupied[k] = v
+			if solver.Has(v) {
+				occupied[k] = v
+			}
 		}
 		coverage = float64(len(occupied)) / float64(totalCells)
 	}
@@ -105,42 +140,48 @@ func (p *CenterOutPlacer) PlaceVines(config config.GenerationConfig, rng *rand.R
 	return vines, occupied, nil
 }
 
-// placeVineWithExitGuarantee places a single vine with guaranteed clear exit path (LIFO principle)
+// placeVineWithExitGuarantee places a single vine with guaranteed clear exit path (LIFO principle).
+// balance ranks head directions; nil ranks them by edge distance alone.
+// Vines that would strand dead cells are passed over while retries last.
 func (p *CenterOutPlacer) placeVineWithExitGuarantee(
 	vineID string,
 	targetLen int,
 	w, h int,
 	occupied map[string]string,
+	balance *utils.DirBalancer,
 	rng *rand.Rand,
 	stats *config.GenerationStats,
 ) (model.Vine, map[string]string, error) {
 	const maxAttempts = 100
+	grid := common.GridFromOccupancy(w, h, occupied)
+	guard := strandGuard{islands: utils.NewIslandDetectorFor(grid), stats: stats}
 
 	for attempt := 0; attempt < maxAttempts; attempt++ {
 		if stats != nil {
 			stats.PlacementAttempts++
 		}
 		// Choose seed cell (center-biased)
-		seed := p.chooseCenterSeed(w, h, occupied, rng)
+		seed := p.chooseCenterSeed(grid, rng)
 		if seed == nil {
 			continue
 		}
 
-		// Choose head direction toward nearest edge
-		headDir := common.ChooseExitDirection(*seed, w, h)
-
-		// CRITICAL: Verify exit path is clear BEFORE growing
-		if !common.IsExitPathClear(*seed, headDir, w, h, occupied) {
-			// Try other directions
-			headDir = p.findClearExitDirection(*seed, w, h, occupied)
-			if headDir == "" {
-				continue // No clear exit from this seed
+		// Choose head direction toward nearest edge, skipping over-represented
+		// directions. CRITICAL: Verify exit path is clear BEFORE growing
+		headDir := ""
+		for _, dir := range balance.RankExits(*seed, w, h) {
+			if grid.IsExitPathClear(*seed, dir) {
+				headDir = dir
+				break
 			}
 		}
+		if headDir == "" {
+			continue // No clear exit from this seed
+		}
 
 		// Grow body opposite to head direction (toward center)
-		vine, localOccupied := p.growVineBody(vineID, *seed, headDir, targetLen, w, h, occupied, rng)
-		if vine.ID != "" && len(vine.OrderedPath) >= 2 {
+		vine, localOccupied := p.growVineBody(vineID, *seed, headDir, targetLen, grid, rng)
+		if vine.ID != "" && len(vine.OrderedPath) >= 2 && !guard.reject(vine.OrderedPath) {
 			return vine, localOccupied, nil
 		}
 	}
@@ -148,19 +189,28 @@ func (p *CenterOutPlacer) placeVineWithExitGuarantee(
 	return model.Vine{}, nil, fmt.Errorf("could not place vine with clear exit after %d attempts", maxAttempts)
 }
 
-// chooseCenterSeed selects a seed cell biased toward the grid center
-func (p *CenterOutPlacer) chooseCenterSeed(w, h int, occupied map[string]string, rng *rand.Rand) *model.Point {
+// resolveTuning sets the seeding and growth tuning for cfg.
+func (p *CenterOutPlacer) resolveTuning(cfg config.GenerationConfig) error {
+	p.tuning = config.CenterOutFor(cfg)
+	if err := p.tuning.Validate(); err != nil {
+		return fmt.Errorf("center-out tuning: %w", err)
+	}
+	return nil
+}
+
+// chooseCenterSeed selects a seed cell biased toward the grid center. With
+// the round-robin pattern the window is swept in the current direction and
+// the seed comes from its leading cells.
+func (p *CenterOutPlacer) chooseCenterSeed(grid *common.Grid, rng *rand.Rand) *model.Point {
+	tuning := p.tuning.Or(config.DefaultCenterOutTuning)
+	w, h := grid.Width(), grid.Height()
 	centerX, centerY := float64(w)/2.0, float64(h)/2.0
 
 	// Collect all empty cells with at least one free neighbor
 	var candidates []model.Point
 	for y := 0; y < h; y++ {
 		for x := 0; x < w; x++ {
-			key := fmt.Sprintf("%d,%d", x, y)
-			if _, occ := occupied[key]; occ {
-				continue
-			}
-			if !p.hasFreeNeighbor(x, y, w, h, occupied) {
+			if grid.Has(x, y) || grid.CountFreeNeighbors(x, y) == 0 {
 				continue
 			}
 			candidates = append(candidates, model.Point{X: x, Y: y})
@@ -179,112 +229,93 @@ func (p *CenterOutPlacer) chooseCenterSeed(w, h int, occupied map[string]string,
 	})
 
 	// Pick from closest N candidates with some randomness (prevents deterministic patterns)
-	topN := len(candidates) / 4
-	if topN < 5 {
-		topN = 5
+	topN := int(float64(len(candidates)) * (1 - tuning.CenterBias))
+	if topN < tuning.SeedWindow {
+		topN = tuning.SeedWindow
 	}
 	if topN > len(candidates) {
 		topN = len(candidates)
 	}
-
-	return &candidates[rng.Intn(topN)]
-}
-
-// findClearExitDirection finds any direction with a clear exit path
-func (p *CenterOutPlacer) findClearExitDirection(pos model.Point, w, h int, occupied map[string]string) string {
-	// Try directions in order of shortest path to edge
-	type dirDist struct {
-		dir  string
-		dist int
-	}
-
-	dirs := []dirDist{
-		{common.DirLeft, pos.X},
-		{common.DirRight, w - 1 - pos.X},
-		{common.DirDown, pos.Y},
-		{common.DirUp, h - 1 - pos.Y},
-	}
-
-	sort.Slice(dirs, func(i, j int) bool {
-		return dirs[i].dist < dirs[j].dist
-	})
-
-	for _, d := range dirs {
-		if common.IsExitPathClear(pos, d.dir, w, h, occupied) {
-			return d.dir
-		}
+	window := candidates[:topN]
+
+	if tuning.SeedPattern == config.SeedPatternRoundRobin {
+		sort.SliceStable(window, func(i, j int) bool {
+			a, c := window[i], window[j]
+			switch p.sweep % 4 {
+			case 0: // top-down
+				return a.Y > c.Y
+			case 1: // bottom-up
+				return a.Y < c.Y
+			case 2: // left-right
+				return a.X < c.X
+			default: // right-left
+				return a.X > c.X
+			}
+		})
+		window = window[:min(len(window), max(tuning.SeedWindow, 1))]
 	}
 
-	return "" // No clear exit
+	return &window[rng.Intn(len(window))]
 }
 
-// growVineBody grows the vine body opposite to head direction
+// growVineBody grows the vine body opposite to head direction.
+// occupied is left untouched; growth works on a copy.
 func (p *CenterOutPlacer) growVineBody(
 	vineID string,
 	head model.Point,
 	headDir string,
 	targetLen int,
-	w, h int,
-	globalOccupied map[string]string,
+	occupied *common.Grid,
 	rng *rand.Rand,
 ) (model.Vine, map[string]string) {
+	grid := occupied.Clone()
 	localOccupied := make(map[string]string)
 	path := []model.Point{head}
 	localOccupied[fmt.Sprintf("%d,%d", head.X, head.Y)] = vineID
+	grid.Set(head.X, head.Y)
 
 	// Place neck (must be opposite to head direction)
 	growDir := common.OppositeDirection(headDir)
-	neck, neckValid := p.placeNeck(head, growDir, w, h, globalOccupied)
+	neck, neckValid := p.placeNeck(head, growDir, grid)
 	if !neckValid {
 		return model.Vine{}, nil
 	}
 
 	path = append(path, neck)
 	localOccupied[fmt.Sprintf("%d,%d", neck.X, neck.Y)] = vineID
+	grid.Set(neck.X, neck.Y)
 
 	// Grow remaining body segments
 	// Calculate forbidden cells (Head's exit path)
-	forbidden := make(map[string]bool)
+	forbidden := common.NewGrid(grid.Width(), grid.Height())
 	dx, dy := common.DeltaForDirection(headDir)
-	ex, ey := head.X+dx, head.Y+dy
-	for ex >= 0 && ex < w && ey >= 0 && ey < h {
-		forbidden[fmt.Sprintf("%d,%d", ex, ey)] = true
-		ex += dx
-		ey += dy
+	for ex, ey := head.X+dx, head.Y+dy; forbidden.InBounds(ex, ey); ex, ey = ex+dx, ey+dy {
+		forbidden.Set(ex, ey)
 	}
 
 	ctx := &growContext{
-		w: w, h: h,
-		globalOccupied: globalOccupied,
-		localOccupied:  localOccupied,
-		vineID:         vineID,
-		rng:            rng,
-		forbidden:      forbidden,
+		grid:          grid,
+		localOccupied: localOccupied,
+		vineID:        vineID,
+		rng:           rng,
+		forbidden:     forbidden,
+		greed:         p.tuning.Or(config.DefaultCenterOutTuning).GrowthGreed,
 	}
 	path = p.growRemainingBody(path, neck, growDir, targetLen, ctx)
 
-	if len(path) < 2 {
+	vine, err := model.NewVine(vineID, path, headDir)
+	if err != nil {
 		return model.Vine{}, nil
 	}
-
-	return model.Vine{
-		ID:            vineID,
-		HeadDirection: headDir,
-		OrderedPath:   path,
-	}, localOccupied
+	return vine, localOccupied
 }
 
 // placeNeck places the neck segment opposite to head direction
-func (p *CenterOutPlacer) placeNeck(head model.Point, growDir string, w, h int, globalOccupied map[string]string) (model.Point, bool) {
+func (p *CenterOutPlacer) placeNeck(head model.Point, growDir string, grid *common.Grid) (model.Point, bool) {
 	dx, dy := common.DeltaForDirection(growDir)
 	neck := model.Point{X: head.X + dx, Y: head.Y + dy}
 
-	if neck.X < 0 || neck.X >= w || neck.Y < 0 || neck.Y >= h {
-		return model.Point{}, false
-	}
-
-	neckKey := fmt.Sprintf("%d,%d", neck.X, neck.Y)
-	if _, occupied := globalOccupied[neckKey]; occupied {
+	if !grid.IsFree(neck.X, neck.Y) {
 		return model.Point{}, false
 	}
 
@@ -293,12 +324,12 @@ func (p *CenterOutPlacer) placeNeck(head model.Point, growDir string, w, h int,
 
 // growContext holds state for vine body growth
 type growContext struct {
-	w, h           int
-	globalOccupied map[string]string
-	localOccupied  map[string]string
-	vineID         string
-	rng            *rand.Rand
-	forbidden      map[string]bool
+	grid          *common.Grid // placed vines plus the vine being grown
+	localOccupied map[string]string
+	vineID        string
+	rng           *rand.Rand
+	forbidden     *common.Grid // the head's exit path
+	greed         float64      // chance of taking the best-scored cell
 }
 
 // growRemainingBody continues vine growth after head and neck are placed
@@ -316,6 +347,7 @@ func (p *CenterOutPlacer) growRemainingBody(
 		}
 		path = append(path, *next)
 		ctx.localOccupied[fmt.Sprintf("%d,%d", next.X, next.Y)] = ctx.vineID
+		ctx.grid.Set(next.X, next.Y)
 		current = *next
 	}
 	return path
@@ -327,12 +359,12 @@ func (p *CenterOutPlacer) chooseNextGrowthCell(
 	preferredDir string,
 	ctx *growContext,
 ) *model.Point {
-	neighbors := p.getAvailableNeighbors(current, ctx.w, ctx.h, ctx.globalOccupied, ctx.localOccupied)
+	neighbors := ctx.grid.FreeNeighbors(current, nil)
 
 	// Pre-filter neighbors to avoid forbidden cells (exit path)
 	var validNeighbors []model.Point
 	for _, n := range neighbors {
-		if !ctx.forbidden[fmt.Sprintf("%d,%d", n.X, n.Y)] {
+		if !ctx.forbidden.Has(n.X, n.Y) {
 			validNeighbors = append(validNeighbors, n)
 		}
 	}
@@ -350,20 +382,11 @@ func (p *CenterOutPlacer) chooseNextGrowthCell(
 
 	var scoredNeighbors []scored
 
-	// Baseline verification: Count reachable cells before move
-	// This is expensive but necessary for high coverage
-	baselineReachable := p.countReachableEmptyCells(ctx.w, ctx.h, ctx.globalOccupied, ctx.localOccupied)
-
 	for _, n := range neighbors {
-		// Verify this move doesn't disconnect the grid
-		// Temporarily mark n as occupied
-		ctx.localOccupied[fmt.Sprintf("%d,%d", n.X, n.Y)] = ctx.vineID
-		newReachable := p.countReachableEmptyCells(ctx.w, ctx.h, ctx.globalOccupied, ctx.localOccupied)
-		delete(ctx.localOccupied, fmt.Sprintf("%d,%d", n.X, n.Y))
-
-		// If we lose more than 1 reachable cell (the one we just took), we caused a disconnect
-		if newReachable < baselineReachable-1 {
-			continue // Skip this move, it creates an island
+		// Skip moves that would cut free cells off from the edge (create an
+		// island). The grid caches its cut cells, so this is one search per step.
+		if ctx.grid.IsEdgeCut(n.X, n.Y) {
+			continue
 		}
 
 		dir := common.DirectionFromPoints(current, n)
@@ -380,8 +403,7 @@ func (p *CenterOutPlacer) chooseNextGrowthCell(
 		}
 
 		// Check immediate neighbor availability (freeCount)
-		nNeighbors := p.getAvailableNeighbors(n, ctx.w, ctx.h, ctx.globalOccupied, ctx.localOccupied)
-		freeCount := len(nNeighbors)
+		freeCount := ctx.grid.CountFreeNeighbors(n.X, n.Y)
 		score += float64(freeCount) * 0.8
 
 		score += ctx.rng.Float64() * 0.5 // Randomness
@@ -394,7 +416,7 @@ func (p *CenterOutPlacer) chooseNextGrowthCell(
 	})
 
 	// Weighted selection
-	if len(scoredNeighbors) > 1 && ctx.rng.Float64() < 0.8 {
+	if len(scoredNeighbors) > 1 && ctx.rng.Float64() < ctx.greed {
 		return &scoredNeighbors[0].pt
 	}
 	if len(scoredNeighbors) > 0 {
@@ -403,99 +425,6 @@ func (p *CenterOutPlacer) chooseNextGrowthCell(
 	return nil
 }
 
-// countReachableEmptyCells returns the number of empty cells reachable from the edge
-func (p *CenterOutPlacer) countReachableEmptyCells(w, h int, globalOccupied, localOccupied map[string]string) int {
-	queue := []model.Point{}
-	visited := make(map[string]bool)
-
-	// Add all empty edge cells to queue
-	for x := 0; x < w; x++ {
-		p1, p2 := model.Point{X: x, Y: 0}, model.Point{X: x, Y: h - 1}
-		if !p.isOccupied(p1, globalOccupied, localOccupied) {
-			queue = append(queue, p1)
-			visited[fmt.Sprintf("%d,%d", x, 0)] = true
-		}
-		if !p.isOccupied(p2, globalOccupied, localOccupied) {
-			queue = append(queue, p2)
-			visited[fmt.Sprintf("%d,%d", x, h-1)] = true
-		}
-	}
-	for y := 1; y < h-1; y++ {
-		p1, p2 := model.Point{X: 0, Y: y}, model.Point{X: w - 1, Y: y}
-		if !p.isOccupied(p1, globalOccupied, localOccupied) {
-			queue = append(queue, p1)
-			visited[fmt.Sprintf("0,%d", y)] = true
-		}
-		if !p.isOccupied(p2, globalOccupied, localOccupied) {
-			queue = append(queue, p2)
-			visited[fmt.Sprintf("%d,%d", w-1, y)] = true
-		}
-	}
-
-	count := 0
-	deltas := []struct{ dx, dy int }{{0, 1}, {0, -1}, {1, 0}, {-1, 0}}
-
-	for len(queue) > 0 {
-		curr := queue[0]
-		queue = queue[1:]
-		count++
-
-		for _, d := range deltas {
-			nx, ny := curr.X+d.dx, curr.Y+d.dy
-			if nx >= 0 && nx < w && ny >= 0 && ny < h {
-				key := fmt.Sprintf("%d,%d", nx, ny)
-				if !visited[key] && !p.isOccupied(model.Point{X: nx, Y: ny}, globalOccupied, localOccupied) {
-					visited[key] = true
-					queue = append(queue, model.Point{X: nx, Y: ny})
-				}
-			}
-		}
-	}
-	return count
-}
-
-func (p *CenterOutPlacer) isOccupied(pt model.Point, global, local map[string]string) bool {
-	key := fmt.Sprintf("%d,%d", pt.X, pt.Y)
-	_, g := global[key]
-	_, l := local[key]
-	return g || l
-}
-
-// getAvailableNeighbors returns unoccupied orthogonal neighbors
-func (p *CenterOutPlacer) getAvailableNeighbors(pos model.Point, w, h int, globalOccupied, localOccupied map[string]string) []model.Point {
-	deltas := []struct{ dx, dy int }{{0, 1}, {0, -1}, {1, 0}, {-1, 0}}
-	var neighbors []model.Point
-
-	for _, d := range deltas {
-		nx, ny := pos.X+d.dx, pos.Y+d.dy
-		if nx >= 0 && nx < w && ny >= 0 && ny < h {
-			key := fmt.Sprintf("%d,%d", nx, ny)
-			_, globallyOcc := globalOccupied[key]
-			_, locallyOcc := localOccupied[key]
-			if !globallyOcc && !locallyOcc {
-				neighbors = append(neighbors, model.Point{X: nx, Y: ny})
-			}
-		}
-	}
-
-	return neighbors
-}
-
-// hasFreeNeighbor checks if a cell has at least one unoccupied neighbor
-func (p *CenterOutPlacer) hasFreeNeighbor(x, y, w, h int, occupied map[string]string) bool {
-	deltas := []struct{ dx, dy int }{{0, 1}, {0, -1}, {1, 0}, {-1, 0}}
-	for _, d := range deltas {
-		nx, ny := x+d.dx, y+d.dy
-		if nx >= 0 && nx < w && ny >= 0 && ny < h {
-			key := fmt.Sprintf("%d,%d", nx, ny)
-			if _, occ := occupied[key]; !occ {
-				return true
-			}
-		}
-	}
-	return false
-}
-
 // calculateVineLengths computes target lengths based on difficulty
 func (p *CenterOutPlacer) calculateVineLengths(genConfig config.GenerationConfig, rng *rand.Rand) []int {
 	totalCells := genConfig.GridWidth * genConfig.GridHeight
@@ -503,11 +432,19 @@ func (p *CenterOutPlacer) calculateVineLengths(genConfig config.GenerationConfig
 	// Target to fill most of the grid
 	targetFill := int(float64(totalCells) * genConfig.MinCoverage)
 
+	// Cap length to prevent overly long vines
+	maxLen := (genConfig.GridWidth + genConfig.GridHeight) / 2
+
 	// Get average length from difficulty specs
 	avgLen := 5 // Default
-	if spec, ok := config.DifficultySpecs[genConfig.Difficulty]; ok {
+	spec, ok := config.SpecFor(genConfig)
+	if ok {
 		avgLen = (spec.AvgLengthRange[0] + spec.AvgLengthRange[1]) / 2
 	}
+	dist := spec.LengthDistribution
+	if dist.Enabled() {
+		avgLen = max(2, int(math.Round(dist.Expected(maxLen))))
+	}
 
 	// Calculate how many vines we need
 	vineCount := targetFill / avgLen
@@ -518,13 +455,15 @@ func (p *CenterOutPlacer) calculateVineLengths(genConfig config.GenerationConfig
 	// Generate lengths with variance
 	lengths := make([]int, vineCount)
 	for i := range lengths {
+		if dist.Enabled() {
+			lengths[i] = dist.Sample(rng, maxLen)
+			continue
+		}
 		variance := rng.Intn(3) - 1 // -1, 0, or +1
 		length := avgLen + variance
 		if length < 2 {
 			length = 2
 		}
-		// Cap length to prevent overly long vines
-		maxLen := (genConfig.GridWidth + genConfig.GridHeight) / 2
 		if length > maxLen {
 			length = maxLen
 		}
@@ -534,243 +473,14 @@ func (p *CenterOutPlacer) calculateVineLengths(genConfig config.GenerationConfig
 	return lengths
 }
 
-// createFillerVines creates 2-cell filler vines for remaining gaps
-// Uses LIFO-guaranteed placement first, then falls back to non-LIFO placement
-func (p *CenterOutPlacer) createFillerVines(
-	existingVines []model.Vine,
-	occupied map[string]string,
-	w, h int,
-	targetCoverage float64,
-	rng *rand.Rand,
-) ([]model.Vine, map[string]string) {
-	targetCells := int(float64(w*h) * targetCoverage)
-	fillerVines := []model.Vine{}
-	fillerOccupied := make(map[string]string)
-	// Compute next filler ID by scanning existing vine IDs to avoid collisions
-	fillerID := 1
-	for _, ev := range existingVines {
-		var idx int
-		if n, err := fmt.Sscanf(ev.ID, "vine_%d", &idx); n == 1 && err == nil {
-			if idx >= fillerID {
-				fillerID = idx + 1
-			}
-		}
-	}
-
-	// Phase 1: LIFO-guaranteed fillers (heads with clear exit)
-	vines1, occ1, _ := p.fillWithLIFOGuarantee(fillerID, occupied, w, h, targetCells, rng)
-	fillerVines = append(fillerVines, vines1...)
-	for k, v := range occ1 {
-		fillerOccupied[k] = v
-	}
-
-	return fillerVines, fillerOccupied
-}
-
-// fillWithLIFOGuarantee places filler vines with guaranteed clear exit paths
-func (p *CenterOutPlacer) fillWithLIFOGuarantee(
-	startID int,
-	occupied map[string]string,
-	w, h int,
-	targetCells int,
-	rng *rand.Rand,
-) ([]model.Vine, map[string]string, int) {
-	vines := []model.Vine{}
-	fillerOccupied := make(map[string]string)
-	fillerID := startID
-	maxIterations := w * h * 3
-	lastCoverage := len(occupied)
-
-	for i := 0; i < maxIterations; i++ {
-		combined := mergeOccupied(occupied, fillerOccupied)
-		currentCoverage := len(combined)
-
-		if currentCoverage >= targetCells {
-			break
-		}
-		if i > 10 && currentCoverage == lastCoverage {
-			break
-		}
-		lastCoverage = currentCoverage
-
-		vine, vineOccupied := p.tryPlaceFillerVine(fmt.Sprintf("vine_%d", fillerID), w, h, combined, rng)
-		if vine.ID == "" {
-			vine, vineOccupied = p.tryPlaceEdgeFillerVine(fmt.Sprintf("vine_%d", fillerID), w, h, combined, rng)
-		}
-		if vine.ID == "" {
-			break
-		}
-
-		vines = append(vines, vine)
-		for k, v := range vineOccupied {
-			fillerOccupied[k] = v
-		}
-		fillerID++
-	}
-
-	return vines, fillerOccupied, fillerID
-}
-
-// edgeCandidate represents an edge cell with its exit direction
-type edgeCandidate struct {
-	pt  model.Point
-	dir string
-}
-
-// collectEdgeCells gathers all empty edge cells with their exit directions
-func (p *CenterOutPlacer) collectEdgeCells(w, h int, occupied map[string]string) []edgeCandidate {
-	var edgeCells []edgeCandidate
-
-	// Top and bottom edges
-	for x := 0; x < w; x++ {
-		topKey := fmt.Sprintf("%d,%d", x, h-1)
-		if _, occ := occupied[topKey]; !occ {
-			edgeCells = append(edgeCells, edgeCandidate{model.Point{X: x, Y: h - 1}, "up"})
-		}
-		bottomKey := fmt.Sprintf("%d,%d", x, 0)
-		if _, occ := occupied[bottomKey]; !occ {
-			edgeCells = append(edgeCells, edgeCandidate{model.Point{X: x, Y: 0}, "down"})
-		}
-	}
-
-	// Left and right edges
-	for y := 0; y < h; y++ {
-		leftKey := fmt.Sprintf("%d,%d", 0, y)
-		if _, occ := occupied[leftKey]; !occ {
-			edgeCells = append(edgeCells, edgeCandidate{model.Point{X: 0, Y: y}, "left"})
-		}
-		rightKey := fmt.Sprintf("%d,%d", w-1, y)
-		if _, occ := occupied[rightKey]; !occ {
-			edgeCells = append(edgeCells, edgeCandidate{model.Point{X: w - 1, Y: y}, "right"})
-		}
-	}
-
-	return edgeCells
-}
-
-// tryPlaceEdgeFillerVine tries to place a filler vine with head at an edge
-func (p *CenterOutPlacer) tryPlaceEdgeFillerVine(
-	vineID string,
-	w, h int,
-	occupied map[string]string,
-	rng *rand.Rand,
-) (model.Vine, map[string]string) {
-	edgeCells := p.collectEdgeCells(w, h, occupied)
-	if len(edgeCells) == 0 {
-		return model.Vine{}, nil
-	}
-
-	rng.Shuffle(len(edgeCells), func(i, j int) {
-		edgeCells[i], edgeCells[j] = edgeCells[j], edgeCells[i]
-	})
-
-	for _, ec := range edgeCells {
-		vine, vineOccupied := p.tryCreateEdgeVine(vineID, ec.pt, ec.dir, w, h, occupied)
-		if vine.ID != "" {
-			return vine, vineOccupied
-		}
-	}
-
-	return model.Vine{}, nil
-}
-
-// tryCreateEdgeVine attempts to create a 2-cell vine from an edge cell
-func (p *CenterOutPlacer) tryCreateEdgeVine(
-	vineID string,
-	head model.Point,
-	headDir string,
-	w, h int,
-	occupied map[string]string,
-) (model.Vine, map[string]string) {
-	neckDir := common.OppositeDirection(headDir)
-	dx, dy := common.DeltaForDirection(neckDir)
-	neck := model.Point{X: head.X + dx, Y: head.Y + dy}
-
-	if neck.X < 0 || neck.X >= w || neck.Y < 0 || neck.Y >= h {
-		return model.Vine{}, nil
-	}
-
-	neckKey := fmt.Sprintf("%d,%d", neck.X, neck.Y)
-	if _, occ := occupied[neckKey]; occ {
-		return model.Vine{}, nil
-	}
-
-	headKey := fmt.Sprintf("%d,%d", head.X, head.Y)
-	vineOccupied := map[string]string{
-		headKey: vineID,
-		neckKey: vineID,
-	}
-
-	return model.Vine{
-		ID:            vineID,
-		HeadDirection: headDir,
-		OrderedPath:   []model.Point{head, neck},
-	}, vineOccupied
-}
-
-// tryPlaceFillerVine attempts to place a single 2-cell filler vine with valid orientation
-func (p *CenterOutPlacer) tryPlaceFillerVine(
-	vineID string,
-	w, h int,
-	occupied map[string]string,
-	rng *rand.Rand,
-) (model.Vine, map[string]string) {
-	// Find all empty cells
-	var emptyCells []model.Point
-	for y := 0; y < h; y++ {
-		for x := 0; x < w; x++ {
-			key := fmt.Sprintf("%d,%d", x, y)
-			if _, occ := occupied[key]; !occ {
-				emptyCells = append(emptyCells, model.Point{X: x, Y: y})
-			}
-		}
+// fillerStrategy resolves the filler used for the gap-filling phase.
+func (p *CenterOutPlacer) fillerStrategy(cfg config.GenerationConfig) (FillerStrategy, error) {
+	if p.Filler != nil {
+		return p.Filler, nil
 	}
-
-	if len(emptyCells) < 2 {
-		return model.Vine{}, nil
+	name := cfg.FillerStrategy
+	if name == "" {
+		name = DefaultFiller
 	}
-
-	// Shuffle for randomness
-	rng.Shuffle(len(emptyCells), func(i, j int) {
-		emptyCells[i], emptyCells[j] = emptyCells[j], emptyCells[i]
-	})
-
-	// Try each empty cell as potential head
-	for _, head := range emptyCells {
-		// Find a free neighbor for neck
-		neighbors := p.getAvailableNeighbors(head, w, h, occupied, nil)
-		if len(neighbors) == 0 {
-			continue
-		}
-
-		// Try each neighbor as potential neck
-		for _, neck := range neighbors {
-			// Calculate head direction based on head→neck vector
-			// neck is at opposite of headDir
-			neckDir := common.DirectionFromPoints(head, neck)
-			headDir := common.OppositeDirection(neckDir)
-
-			// Verify the head has a clear exit path
-			if !common.IsExitPathClear(head, headDir, w, h, occupied) {
-				continue
-			}
-
-			// Valid placement found
-			headKey := fmt.Sprintf("%d,%d", head.X, head.Y)
-			neckKey := fmt.Sprintf("%d,%d", neck.X, neck.Y)
-
-			vineOccupied := map[string]string{
-				headKey: vineID,
-				neckKey: vineID,
-			}
-
-			return model.Vine{
-				ID:            vineID,
-				HeadDirection: headDir,
-				OrderedPath:   []model.Point{head, neck},
-			}, vineOccupied
-		}
-	}
-
-	return model.Vine{}, nil
+	return GetFiller(name)
 }
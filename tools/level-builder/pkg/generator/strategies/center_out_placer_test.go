@@ -0,0 +1,42 @@
+package strategies
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func TestCenterOutRoundRobinSweepsSeeds(t *testing.T) {
+	p := &CenterOutPlacer{tuning: config.CenterOutTuning{SeedWindow: 1, SeedPattern: config.SeedPatternRoundRobin}}
+	grid := common.NewGrid(9, 9)
+	rng := rand.New(rand.NewSource(1))
+
+	// With a one-cell window each sweep takes the extreme cell of the central quarter
+	var seeds []model.Point
+	for sweep := range 4 {
+		p.sweep = sweep
+		seeds = append(seeds, *p.chooseCenterSeed(grid, rng))
+	}
+	top, bottom, left, right := seeds[0], seeds[1], seeds[2], seeds[3]
+	if top.Y <= bottom.Y || left.X >= right.X {
+		t.Errorf("sweeps picked top %v, bottom %v, left %v, right %v", top, bottom, left, right)
+	}
+}
+
+func TestCenterOutTuningIsValidated(t *testing.T) {
+	cfg := config.GenerationConfig{GridWidth: 8, GridHeight: 8, VineCount: 3, MinCoverage: 0.9, Difficulty: "Seedling"}
+	cfg.SeedPattern = "spiral"
+	if _, _, err := (&CenterOutPlacer{}).PlaceVines(context.Background(), cfg, rand.New(rand.NewSource(1)), &config.GenerationStats{}); err == nil {
+		t.Fatal("expected an unknown seed pattern to fail placement")
+	}
+
+	cfg.SeedPattern = config.SeedPatternRoundRobin
+	cfg.GrowthGreed = 1
+	if _, _, err := (&CenterOutPlacer{}).PlaceVines(context.Background(), cfg, rand.New(rand.NewSource(1)), &config.GenerationStats{}); err != nil {
+		t.Fatalf("PlaceVines: %v", err)
+	}
+}
@@ -0,0 +1,237 @@
+package strategies
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/utils"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+// chainLinkTries bounds the heads tried when adding one vine to a chain.
+const chainLinkTries = 40
+
+// ChainPlacer builds deliberate blocking chains, the cascading locks of
+// Transcendent levels: each vine's head faces a cell of the vine placed before
+// it, so clearing one vine frees the next. Chains are grown to the tier's
+// DifficultySpec.MaxBlockingDepth and then a new chain starts, until the vine
+// budget or the free space runs out; the pipeline's gap filler covers the rest.
+//
+// Every vine is checked with a utils.IncrementalSolver before it is kept, so
+// the waits-on graph stays acyclic and the placement solvable, and no vine may
+// push any blocking chain past the target depth.
+type ChainPlacer struct{}
+
+// chainBoard tracks the placement while chains are built.
+type chainBoard struct {
+	w, h    int
+	depth   int // Deepest blocking chain allowed
+	vines   []model.Vine
+	grid    *common.Grid   // occupied cells, for fast free-cell tests
+	owners  map[int]string // cell index (y*w+x) -> owning vine ID
+	solver  *utils.IncrementalSolver
+	lengths config.LengthDistribution // Tier's length curve, if any
+	strands strandGuard               // Passes over links that strand dead cells
+}
+
+func (b *chainBoard) free(p model.Point) bool {
+	return b.grid.IsFree(p.X, p.Y)
+}
+
+// claim marks p taken by owner.
+func (b *chainBoard) claim(p model.Point, owner string) {
+	b.grid.Set(p.X, p.Y)
+	b.owners[b.grid.Index(p.X, p.Y)] = owner
+}
+
+// occupancy returns the cell owners keyed like common.PointKey, the form
+// PlaceVines reports them in.
+func (b *chainBoard) occupancy() map[string]string {
+	occupied := make(map[string]string, len(b.owners))
+	for i, owner := range b.owners {
+		occupied[common.PointKey(model.Point{X: i % b.w, Y: i / b.w})] = owner
+	}
+	return occupied
+}
+
+func (b *chainBoard) place(v model.Vine) {
+	b.vines = append(b.vines, v)
+	b.solver.Place(v)
+	b.strands.islands.Place(v)
+	b.strands.reset()
+	for _, p := range v.OrderedPath {
+		b.claim(p, v.ID)
+	}
+}
+
+// PlaceVines places chains of vines and reports the deepest chain in
+// stats.MaxBlockingDepth. It fails only when no vine could be placed.
+func (p *ChainPlacer) PlaceVines(ctx context.Context, cfg config.GenerationConfig, rng *rand.Rand, stats *config.GenerationStats) ([]model.Vine, map[string]string, error) {
+	spec, _ := config.SpecFor(cfg)
+	depth := spec.MaxBlockingDepth
+	minLen, maxLen := (&FullCoveragePlacer{}).lengthRange(cfg)
+
+	b := &chainBoard{
+		w: cfg.GridWidth, h: cfg.GridHeight, depth: depth, lengths: spec.LengthDistribution,
+		grid:    common.NewGrid(cfg.GridWidth, cfg.GridHeight),
+		owners:  make(map[int]string),
+		solver:  utils.NewIncrementalSolver(cfg.GridWidth, cfg.GridHeight),
+		strands: strandGuard{islands: utils.NewIslandDetector(cfg.GridWidth, cfg.GridHeight), stats: stats},
+	}
+
+	for _, p := range cfg.Stencil {
+		b.claim(p, StencilOwner)
+	}
+
+	seeds := make([]model.Point, 0, b.w*b.h)
+	for y := 0; y < b.h; y++ {
+		for x := 0; x < b.w; x++ {
+			seeds = append(seeds, model.Point{X: x, Y: y})
+		}
+	}
+	rng.Shuffle(len(seeds), func(i, j int) { seeds[i], seeds[j] = seeds[j], seeds[i] })
+
+	for _, seed := range seeds {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		if len(b.vines) >= cfg.VineCount {
+			break
+		}
+		if !b.free(seed) {
+			continue
+		}
+
+		// The chain's root: any head direction the solver accepts
+		root, ok := p.growLink(b, seed, "", minLen, maxLen, rng, stats)
+		if !ok {
+			continue
+		}
+		b.place(root)
+		prev := root
+		for link := 0; link < depth && len(b.vines) < cfg.VineCount; link++ {
+			next, ok := p.nextLink(b, prev, minLen, maxLen, rng, stats)
+			if !ok {
+				break
+			}
+			b.place(next)
+			prev = next
+		}
+	}
+
+	if len(b.vines) == 0 {
+		return nil, nil, fmt.Errorf("chain placement placed no vines on a %dx%d grid", b.w, b.h)
+	}
+
+	reached := utils.MaxBlockingDepth(utils.BuildBlockingGraph(b.vines))
+	if stats != nil && reached > stats.MaxBlockingDepth {
+		stats.MaxBlockingDepth = reached
+	}
+	common.Verbose("Chain placement: %d vines, deepest chain %d (target %d)", len(b.vines), reached, depth)
+	return b.vines, b.occupancy(), nil
+}
+
+// nextLink places a vine whose head faces a cell of prev, so prev blocks it.
+func (p *ChainPlacer) nextLink(b *chainBoard, prev model.Vine, minLen, maxLen int, rng *rand.Rand, stats *config.GenerationStats) (model.Vine, bool) {
+	type approach struct {
+		head model.Point
+		dir  string
+	}
+	var approaches []approach
+	for _, cell := range prev.OrderedPath {
+		for _, dir := range []string{"up", "down", "left", "right"} {
+			dx, dy := utils.DeltaForDirection(dir)
+			// A head at cell - delta facing dir moves into cell
+			head := model.Point{X: cell.X - dx, Y: cell.Y - dy}
+			if b.free(head) {
+				approaches = append(approaches, approach{head: head, dir: dir})
+			}
+		}
+	}
+	rng.Shuffle(len(approaches), func(i, j int) { approaches[i], approaches[j] = approaches[j], approaches[i] })
+
+	for i, a := range approaches {
+		if i >= chainLinkTries {
+			break
+		}
+		if v, ok := p.growLink(b, a.head, a.dir, minLen, maxLen, rng, stats); ok {
+			return v, true
+		}
+	}
+	return model.Vine{}, false
+}
+
+// growLink grows a vine of minLen to maxLen cells, or of a length drawn from
+// the tier's curve, from head through free cells. With dir set the head must
+// face dir, so the neck sits behind it. The vine is returned only if the
+// solver accepts it.
+func (p *ChainPlacer) growLink(b *chainBoard, head model.Point, dir string, minLen, maxLen int, rng *rand.Rand, stats *config.GenerationStats) (model.Vine, bool) {
+	id := model.VineID(len(b.vines) + 1)
+	var target int
+	if b.lengths.Enabled() {
+		target = b.lengths.Sample(rng, maxLen)
+	} else {
+		target = minLen + rng.Intn(maxLen-minLen+1)
+	}
+	if stats != nil {
+		stats.PlacementAttempts++
+	}
+
+	path := []model.Point{head}
+	used := map[model.Point]bool{head: true}
+	if dir != "" {
+		dx, dy := utils.DeltaForDirection(dir)
+		neck := model.Point{X: head.X - dx, Y: head.Y - dy}
+		if !b.free(neck) {
+			return model.Vine{}, false
+		}
+		path = append(path, neck)
+		used[neck] = true
+	}
+
+	for len(path) < target {
+		end := path[len(path)-1]
+		var options []model.Point
+		for _, d := range [4]model.Point{{X: 1}, {X: -1}, {Y: 1}, {Y: -1}} {
+			c := model.Point{X: end.X + d.X, Y: end.Y + d.Y}
+			if b.free(c) && !used[c] {
+				options = append(options, c)
+			}
+		}
+		if len(options) == 0 {
+			break
+		}
+		next := options[rng.Intn(len(options))]
+		path = append(path, next)
+		used[next] = true
+	}
+	if len(path) < 2 {
+		return model.Vine{}, false
+	}
+
+	v, err := model.NewVine(id, path, dir)
+	if err != nil {
+		return model.Vine{}, false
+	}
+	// The solver only sees other vines; a body across its own exit never clears
+	alone := model.Level{GridSize: []int{b.w, b.h}, Vines: []model.Vine{v}}
+	if len(validator.ValidateSelfBlocking(alone)) > 0 || !b.solver.CanPlace(v) {
+		if stats != nil {
+			stats.SolvabilityPrunes++
+		}
+		return model.Vine{}, false
+	}
+	// A vine can also land in front of another chain's head; keep every
+	// chain within the target depth
+	if utils.MaxBlockingDepth(utils.BuildBlockingGraph(append(b.vines[:len(b.vines):len(b.vines)], v))) > b.depth {
+		return model.Vine{}, false
+	}
+	if b.strands.reject(v.OrderedPath) {
+		return model.Vine{}, false
+	}
+	return v, true
+}
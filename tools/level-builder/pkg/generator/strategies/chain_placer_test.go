@@ -0,0 +1,53 @@
+package strategies
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/utils"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+func TestChainPlacerHitsTargetDepth(t *testing.T) {
+	for _, difficulty := range []string{"Nurturing", "Transcendent"} {
+		cfg := config.GenerationConfig{
+			LevelID:     1,
+			GridWidth:   12,
+			GridHeight:  16,
+			VineCount:   24,
+			MinCoverage: 1.0,
+			Difficulty:  difficulty,
+		}
+		var stats config.GenerationStats
+		vines, occupied, err := (&ChainPlacer{}).PlaceVines(context.Background(), cfg, rand.New(rand.NewSource(7)), &stats)
+		if err != nil {
+			t.Fatalf("%s: PlaceVines: %v", difficulty, err)
+		}
+
+		want := config.DifficultySpecs[difficulty].MaxBlockingDepth
+		if got := utils.MaxBlockingDepth(utils.BuildBlockingGraph(vines)); got != want {
+			t.Errorf("%s: deepest chain %d, want %d", difficulty, got, want)
+		}
+		if stats.MaxBlockingDepth != want {
+			t.Errorf("%s: stats.MaxBlockingDepth = %d, want %d", difficulty, stats.MaxBlockingDepth, want)
+		}
+		cells := 0
+		for _, v := range vines {
+			cells += len(v.OrderedPath)
+		}
+		if cells != len(occupied) {
+			t.Errorf("%s: %d vine cells but %d occupied", difficulty, cells, len(occupied))
+		}
+		level := model.Level{GridSize: []int{cfg.GridWidth, cfg.GridHeight}, Vines: vines}
+		if errs := validator.ValidateStructural(level); len(errs) > 0 {
+			t.Errorf("%s: chain placement invalid: %v", difficulty, errs)
+		}
+		if !common.NewSolver(&level).IsSolvableGreedy() {
+			t.Errorf("%s: chain placement not solvable", difficulty)
+		}
+	}
+}
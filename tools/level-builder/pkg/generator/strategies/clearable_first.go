@@ -1,6 +1,7 @@
 package strategies
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 
@@ -16,14 +17,16 @@ import (
 //
 // This approach dramatically improves solvability on large grids by ensuring
 // a base set of vines can always clear, preventing total blocking scenarios.
+// Both phases stop with ctx.Err() once ctx is done.
 func ClearableFirstPlacement(
+	ctx context.Context,
 	gridSize []int,
 	constraints config.DifficultySpec,
 	profile config.VarietyProfile,
 	cfg config.GeneratorConfig,
 	seed int64,
 	anchorRatio float64, // e.g., 0.3 for 30% anchor vines
-	minCoverage float64, // target coverage (e.g. 0.95)
+	minCoverage float64, // target coverage (0 uses the tier's MinGridOccupancy)
 	greedy bool, // if true, check solvability after each vine placement
 ) ([]model.Vine, error) {
 	rng := rand.New(rand.NewSource(seed))
@@ -32,7 +35,7 @@ func ClearableFirstPlacement(
 		anchorRatio = 0.3 // default 30%
 	}
 	if minCoverage <= 0 {
-		minCoverage = common.MinGridCoverage
+		minCoverage = constraints.MinGridOccupancy
 	}
 
 	// GREEDY-FILL ALGORITHM: Place vines until target coverage
@@ -49,10 +52,15 @@ func ClearableFirstPlacement(
 
 	occupied := make(map[string]bool)
 	var vines []model.Vine
+	balance := utils.NewDirBalancer(profile.DirBalance, constraints.DirBalanceTolerance)
+	guard := strandGuard{islands: utils.NewIslandDetector(gridSize[0], gridSize[1])}
 
 	// Track vine length distribution to ensure variety
 	lengthCounts := make(map[int]int)
 	maxShortVines := gridArea / 50 // limit 2-3 cell vines to ~2% of grid
+	if constraints.LengthDistribution.Enabled() {
+		maxShortVines = gridArea // the tier's curve decides how many vines are short
+	}
 
 	// Phase 1: Place anchor vines near edges (30% of estimated total)
 	edgeBuffer := 3
@@ -68,6 +76,9 @@ func ClearableFirstPlacement(
 	anchorFailures := 0
 
 	for len(vines) < targetAnchorCount && anchorAttempts < maxAnchorAttempts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		anchorAttempts++
 		anchorFailures++
 
@@ -89,9 +100,9 @@ func ClearableFirstPlacement(
 		}
 
 		// Choose vine length with variety (skewed toward longer vines)
-		vineLen := chooseVineLengthSkewed(minVineLen, maxVineLen, remainingCells, lengthCounts, maxShortVines, rng)
+		vineLen := chooseVineLength(constraints.LengthDistribution, minVineLen, maxVineLen, remainingCells, lengthCounts, maxShortVines, rng)
 
-		vine, newOcc, err := GrowFromSeed(seedPoint, occupied, gridSize, vineLen, profile, cfg, rng)
+		vine, newOcc, err := GrowFromSeed(seedPoint, occupied, gridSize, vineLen, balancedProfile(profile, balance), cfg, rng)
 		if err != nil || len(vine.OrderedPath) < minVineLen {
 			continue
 		}
@@ -105,6 +116,10 @@ func ClearableFirstPlacement(
 			}
 		}
 
+		if guard.reject(vine.OrderedPath) {
+			continue // would strand dead cells
+		}
+
 		// Verify solvability
 		if greedy {
 			testLevel := &model.Level{
@@ -118,8 +133,11 @@ func ClearableFirstPlacement(
 		}
 
 		// Accept vine - assign ID before appending
-		vine.ID = fmt.Sprintf("v%d", len(vines)+1)
+		vine.ID = model.VineID(len(vines) + 1)
 		vines = append(vines, vine)
+		balance.RecordVine(vine)
+		guard.islands.Place(vine)
+		guard.reset()
 		occupied = newOcc
 		lengthCounts[len(vine.OrderedPath)]++
 	}
@@ -132,6 +150,9 @@ func ClearableFirstPlacement(
 	maxConsecutiveFillFails := 50 // Give up after 50 straight failures
 
 	for fillAttempts < maxFillAttempts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		fillAttempts++
 		fillFailures++
 
@@ -182,9 +203,9 @@ func ClearableFirstPlacement(
 		}
 
 		// Choose vine length with variety
-		vineLen := chooseVineLengthSkewed(minVineLen, maxVineLen, remainingCells, lengthCounts, effectiveMaxShort, rng)
+		vineLen := chooseVineLength(constraints.LengthDistribution, minVineLen, maxVineLen, remainingCells, lengthCounts, effectiveMaxShort, rng)
 
-		vine, newOcc, err := GrowFromSeed(seedPoint, occupied, gridSize, vineLen, profile, cfg, rng)
+		vine, newOcc, err := GrowFromSeed(seedPoint, occupied, gridSize, vineLen, balancedProfile(profile, balance), cfg, rng)
 		if err != nil || len(vine.OrderedPath) < minVineLen {
 			continue
 		}
@@ -198,6 +219,10 @@ func ClearableFirstPlacement(
 			}
 		}
 
+		if guard.reject(vine.OrderedPath) {
+			continue // would strand dead cells
+		}
+
 		// Check solvability
 		if greedy {
 			testLevel := &model.Level{
@@ -211,8 +236,11 @@ func ClearableFirstPlacement(
 		}
 
 		// Accept vine - assign ID before appending
-		vine.ID = fmt.Sprintf("v%d", len(vines)+1)
+		vine.ID = model.VineID(len(vines) + 1)
 		vines = append(vines, vine)
+		balance.RecordVine(vine)
+		guard.islands.Place(vine)
+		guard.reset()
 		occupied = newOcc
 		lengthCounts[len(vine.OrderedPath)]++
 		fillFailures = 0 // Reset consecutive failure counter on success
@@ -411,6 +439,15 @@ func pickRandomSeedWithPreference(gridSize []int, occupied map[string]bool, rng
 	return pickRandomSeed(gridSize, occupied, rng)
 }
 
+// chooseVineLength draws a length from dist when the tier has one, capped by
+// the remaining space, and otherwise uses chooseVineLengthSkewed.
+func chooseVineLength(dist config.LengthDistribution, minLen, maxLen, remainingCells int, lengthCounts map[int]int, maxShortVines int, rng *rand.Rand) int {
+	if dist.Enabled() {
+		return max(minLen, dist.Sample(rng, min(maxLen, remainingCells)))
+	}
+	return chooseVineLengthSkewed(minLen, maxLen, remainingCells, lengthCounts, maxShortVines, rng)
+}
+
 // chooseVineLengthSkewed picks a vine length with variety and a skew toward longer vines.
 // Avoids too many short vines while respecting remaining space.
 func chooseVineLengthSkewed(minLen, maxLen, remainingCells int, lengthCounts map[int]int, maxShortVines int, rng *rand.Rand) int {
@@ -1,6 +1,7 @@
 package strategies_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -43,7 +44,7 @@ func TestClearableFirstPlacement_LargeGridReliability(t *testing.T) {
 		seed := int64(1000 + i)
 		start := time.Now()
 
-		vines, err := strategies.ClearableFirstPlacement(gridSize, constraints, profile, cfg, seed, 0.3, 0.95, true)
+		vines, err := strategies.ClearableFirstPlacement(context.Background(), gridSize, constraints, profile, cfg, seed, 0.3, 0.95, true)
 		elapsed := time.Since(start)
 		totalTime += elapsed
 
@@ -123,7 +124,7 @@ func TestClearableFirstPlacement_GridSizeComparison(t *testing.T) {
 				seed := int64(2000 + i)
 				start := time.Now()
 
-				vines, err := strategies.ClearableFirstPlacement(tc.gridSize, constraints, profile, cfg, seed, 0.3, 0.95, true)
+				vines, err := strategies.ClearableFirstPlacement(context.Background(), tc.gridSize, constraints, profile, cfg, seed, 0.3, 0.95, true)
 				elapsed := time.Since(start)
 				totalTime += elapsed
 
@@ -179,7 +180,7 @@ func BenchmarkClearableFirstPlacement(b *testing.B) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		seed := int64(3000 + i)
-		_, err := strategies.ClearableFirstPlacement(gridSize, constraints, profile, cfg, seed, 0.3, 0.95, true)
+		_, err := strategies.ClearableFirstPlacement(context.Background(), gridSize, constraints, profile, cfg, seed, 0.3, 0.95, true)
 		if err != nil {
 			b.Fatalf("Generation failed: %v", err)
 		}
@@ -225,7 +226,7 @@ func TestClearableFirstPlacement_FullCoverage(t *testing.T) {
 				seed := int64(5000 + i)
 				start := time.Now()
 
-				vines, err := strategies.ClearableFirstPlacement(tc.gridSize, constraints, profile, cfg, seed, 0.3, 0.95, true)
+				vines, err := strategies.ClearableFirstPlacement(context.Background(), tc.gridSize, constraints, profile, cfg, seed, 0.3, 0.95, true)
 				elapsed := time.Since(start)
 				totalTime += elapsed
 
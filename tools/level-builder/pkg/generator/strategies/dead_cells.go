@@ -0,0 +1,39 @@
+package strategies
+
+import (
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/utils"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// deadCellRetries is how many candidates for one vine a placer passes over
+// because they would strand dead cells (see utils.IslandDetector) before it
+// takes one that does. Late in a dense grid every candidate may strand one,
+// and a masked cell beats a vine that was never placed.
+const deadCellRetries = 5
+
+// strandGuard asks an IslandDetector about each candidate vine before a
+// placer commits it. Call reset after committing a vine.
+type strandGuard struct {
+	islands *utils.IslandDetector
+	stats   *config.GenerationStats
+	retries int
+}
+
+// reject reports whether the candidate over path should be passed over
+// because it would strand dead cells, and counts the rejection.
+func (g *strandGuard) reject(path []model.Point) bool {
+	if g.retries >= deadCellRetries || g.islands.WouldStrand(path) == 0 {
+		return false
+	}
+	g.retries++
+	if g.stats != nil {
+		g.stats.DeadCellRejections++
+	}
+	return true
+}
+
+// reset gives the next vine a fresh set of retries.
+func (g *strandGuard) reset() {
+	g.retries = 0
+}
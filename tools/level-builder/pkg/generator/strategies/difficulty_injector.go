@@ -0,0 +1,195 @@
+package strategies
+
+import (
+	"math/rand"
+	"slices"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/utils"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// relocateTries is how many new bodies DifficultyInjector grows for a vine
+// when trying to move it.
+const relocateTries = 4
+
+// DifficultyInjector edits a finished placement so its deepest blocker chain
+// (utils.MaxBlockingDepth) grows toward a target, the solver-aware placement
+// idea run as a post-pass over any placer's output. Each edit reverses one
+// vine, so its head leaves from the other end, or regrows it elsewhere over
+// its own cells and the empty cells around it. An edit is kept only if it
+// deepens the chains without passing the target and the IncrementalSolver
+// finds no blocking cycle, so every kept edit leaves the level solvable.
+type DifficultyInjector struct {
+	w, h   int
+	target int
+	rng    *rand.Rand
+
+	// Stencil lists decorative gap cells no edit may grow into.
+	Stencil []model.Point
+}
+
+// NewDifficultyInjector creates a DifficultyInjector that works toward a
+// deepest blocker chain of target vines.
+func NewDifficultyInjector(w, h, target int, rng *rand.Rand) *DifficultyInjector {
+	return &DifficultyInjector{w: w, h: h, target: target, rng: rng}
+}
+
+// blockingScore ranks placements: the deepest chain first, then the depth
+// summed over every vine.
+type blockingScore struct {
+	max, total int
+}
+
+func (s blockingScore) beats(o blockingScore) bool {
+	return s.max > o.max || (s.max == o.max && s.total > o.total)
+}
+
+// Inject edits vines in place, at most one edit per vine, and returns how
+// many edits it kept. Vines keep their IDs and lengths. Nothing changes when
+// the vines already reach the target or are not solvable to begin with; run
+// it before tail heads, portals and locks are assigned.
+func (d *DifficultyInjector) Inject(vines []model.Vine) int {
+	solver := utils.NewIncrementalSolver(d.w, d.h)
+	for _, v := range vines {
+		if !solver.CanPlace(v) {
+			return 0
+		}
+		solver.Place(v)
+	}
+
+	occupied := make(map[string]bool)
+	for _, v := range vines {
+		for _, p := range v.OrderedPath {
+			occupied[common.PointKey(p)] = true
+		}
+	}
+	reserveStencilCells(occupied, d.Stencil)
+	score := d.score(vines)
+	edited := make([]bool, len(vines))
+	edits := 0
+	for score.max < d.target {
+		i, v, next, ok := d.firstEdit(vines, edited, solver, occupied, score)
+		if !ok {
+			break
+		}
+		for _, p := range vines[i].OrderedPath {
+			delete(occupied, common.PointKey(p))
+		}
+		for _, p := range v.OrderedPath {
+			occupied[common.PointKey(p)] = true
+		}
+		solver.Place(v)
+		vines[i] = v
+		edited[i] = true
+		score = next
+		edits++
+	}
+	return edits
+}
+
+// firstEdit tries the unedited vines in random order and returns the first
+// edit that improves on score without passing the target: the vine index,
+// the new vine and the new score.
+func (d *DifficultyInjector) firstEdit(vines []model.Vine, edited []bool, solver *utils.IncrementalSolver, occupied map[string]bool, score blockingScore) (int, model.Vine, blockingScore, bool) {
+	try := func(i int, v model.Vine) (blockingScore, bool) {
+		if selfBlocks(v, d.w, d.h) || !solver.CanPlace(v) {
+			return score, false
+		}
+		old := vines[i]
+		vines[i] = v
+		next := d.score(vines)
+		vines[i] = old
+		return next, next.max <= d.target && next.beats(score)
+	}
+
+	for _, i := range d.rng.Perm(len(vines)) {
+		if edited[i] {
+			continue
+		}
+		v := vines[i]
+		if r, err := model.NewVine(v.ID, v.Reversed().OrderedPath, ""); err == nil {
+			r.ColorIndex, r.LockedUntil = v.ColorIndex, v.LockedUntil
+			if next, ok := try(i, r); ok {
+				return i, r, next, true
+			}
+		}
+		for _, moved := range d.relocations(v, occupied) {
+			if next, ok := try(i, moved); ok {
+				return i, moved, next, true
+			}
+		}
+	}
+	return 0, model.Vine{}, score, false
+}
+
+// relocations returns up to relocateTries bodies as long as v, regrown from
+// random cells of v or the empty cells next to it.
+func (d *DifficultyInjector) relocations(v model.Vine, occupied map[string]bool) []model.Vine {
+	return regrowVine(v, occupied, d.w, d.h, relocateTries, d.rng)
+}
+
+// regrowVine returns up to tries bodies as long as v, each grown with
+// GrowFromSeed from a random cell of v or an empty cell next to it. The
+// bodies keep v's ID, color and lock. occupied holds every vine's cells,
+// v's included; it is restored before regrowVine returns.
+func regrowVine(v model.Vine, occupied map[string]bool, w, h, tries int, rng *rand.Rand) []model.Vine {
+	for _, p := range v.OrderedPath {
+		delete(occupied, common.PointKey(p))
+	}
+	defer func() {
+		for _, p := range v.OrderedPath {
+			occupied[common.PointKey(p)] = true
+		}
+	}()
+
+	seeds := append([]model.Point(nil), v.OrderedPath...)
+	for _, p := range v.OrderedPath {
+		for _, n := range getUnoccupiedNeighbors(p, w, h, occupied) {
+			if !slices.Contains(seeds, n) {
+				seeds = append(seeds, n)
+			}
+		}
+	}
+
+	var out []model.Vine
+	gridSize := []int{w, h}
+	for t := 0; t < tries; t++ {
+		seed := seeds[rng.Intn(len(seeds))]
+		grown, _, err := GrowFromSeed(seed, occupied, gridSize, len(v.OrderedPath), config.VarietyProfile{}, config.GeneratorConfig{}, rng)
+		if err != nil || len(grown.OrderedPath) != len(v.OrderedPath) {
+			continue
+		}
+		grown.ID, grown.ColorIndex, grown.LockedUntil = v.ID, v.ColorIndex, v.LockedUntil
+		out = append(out, grown)
+	}
+	return out
+}
+
+// selfBlocks reports whether one of v's own cells lies on its head's exit path.
+func selfBlocks(v model.Vine, w, h int) bool {
+	ray := exitPath(v.OrderedPath[0], v.HeadDirection, w, h)
+	for _, p := range v.OrderedPath[1:] {
+		if ray[p] {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *DifficultyInjector) score(vines []model.Vine) blockingScore {
+	return scoreBlocking(vines)
+}
+
+// scoreBlocking scores vines by the blocker chain depths of
+// utils.BlockingDepths, so the injector aims for the same depth the
+// generator's depth band check measures.
+func scoreBlocking(vines []model.Vine) blockingScore {
+	var s blockingScore
+	for _, d := range utils.BlockingDepths(utils.BuildBlockingGraph(vines)) {
+		s.max = max(s.max, d)
+		s.total += d
+	}
+	return s
+}
@@ -0,0 +1,97 @@
+package strategies
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/utils"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+func TestDifficultyInjectorDeepensChains(t *testing.T) {
+	spec := config.DifficultySpecs["Sprout"]
+	gridSize := []int{9, 12}
+	target := spec.BlockingDepthRange[1] // deeper than placement alone reaches
+
+	improved := 0
+	for seed := int64(1); seed <= 5; seed++ {
+		vines, err := ClearableFirstPlacement(context.Background(), gridSize, spec, config.VarietyProfile{}, config.GeneratorConfig{}, seed, 0.3, 0.9, true)
+		if err != nil {
+			t.Fatalf("seed %d: placement failed: %v", seed, err)
+		}
+		before := utils.MaxBlockingDepth(utils.BuildBlockingGraph(vines))
+		lengths := make(map[string]int)
+		for _, v := range vines {
+			lengths[v.ID] = len(v.OrderedPath)
+		}
+
+		edits := NewDifficultyInjector(gridSize[0], gridSize[1], target, rand.New(rand.NewSource(seed))).Inject(vines)
+		after := utils.MaxBlockingDepth(utils.BuildBlockingGraph(vines))
+		if edits > 0 && after < before {
+			t.Errorf("seed %d: %d edits lowered depth %d -> %d", seed, edits, before, after)
+		}
+		if after > max(before, target) {
+			t.Errorf("seed %d: depth %d passed target %d", seed, after, target)
+		}
+		t.Logf("seed %d: %d vines, depth %d -> %d, %d edits, target %d", seed, len(vines), before, after, edits, target)
+		if after > before {
+			improved++
+		}
+
+		for _, v := range vines {
+			if lengths[v.ID] != len(v.OrderedPath) {
+				t.Errorf("seed %d: vine %s changed length %d -> %d", seed, v.ID, lengths[v.ID], len(v.OrderedPath))
+			}
+		}
+		level := model.Level{GridSize: gridSize, Vines: vines}
+		if errs := validator.ValidateStructural(level); len(errs) > 0 {
+			t.Errorf("seed %d: injected level invalid: %v", seed, errs)
+		}
+		if !common.NewSolver(&level).IsSolvableGreedy() {
+			t.Errorf("seed %d: injected level not solvable", seed)
+		}
+	}
+	if improved == 0 {
+		t.Error("injection never deepened a blocker chain")
+	}
+}
+
+func TestDifficultyInjectorLeavesUnsolvableAlone(t *testing.T) {
+	// Two vines facing each other wait on each other
+	a, _ := model.NewVine("a", []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}, "")
+	b, _ := model.NewVine("b", []model.Point{{X: 2, Y: 0}, {X: 3, Y: 0}}, "")
+	vines := []model.Vine{a, b}
+	if n := NewDifficultyInjector(4, 2, 3, rand.New(rand.NewSource(1))).Inject(vines); n != 0 {
+		t.Errorf("Inject made %d edits to an unsolvable placement", n)
+	}
+	if vines[0].HeadDirection != "right" || vines[1].HeadDirection != "left" {
+		t.Error("unsolvable placement was changed")
+	}
+}
+
+func TestScoreBlockingMatchesBlockingDepths(t *testing.T) {
+	vine := func(id string, path ...model.Point) model.Vine {
+		v, err := model.NewVine(id, path, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	// A's head faces B's tail and B's head faces A's tail: a two-vine cycle
+	vines := []model.Vine{
+		vine("A", model.Point{X: 1, Y: 1}, model.Point{X: 0, Y: 1}),
+		vine("B", model.Point{X: 0, Y: 2}, model.Point{X: 0, Y: 3}, model.Point{X: 1, Y: 3},
+			model.Point{X: 2, Y: 3}, model.Point{X: 2, Y: 2}, model.Point{X: 2, Y: 1}),
+	}
+	got := scoreBlocking(vines)
+	if want := utils.MaxBlockingDepth(utils.BuildBlockingGraph(vines)); got.max != want || want != 1 {
+		t.Fatalf("scoreBlocking max = %d, utils.MaxBlockingDepth = %d, want both 1", got.max, want)
+	}
+	if got.total != 2 {
+		t.Errorf("scoreBlocking total = %d, want 2", got.total)
+	}
+}
@@ -0,0 +1,25 @@
+package strategies
+
+import (
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/utils"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// headBalancer returns a DirBalancer for cfg's tier that aims for the preset
+// profile's head distribution, with the heads of vines already counted.
+func headBalancer(cfg config.GenerationConfig, vines []model.Vine) *utils.DirBalancer {
+	spec, _ := config.SpecFor(cfg)
+	b := utils.NewDirBalancer(utils.GetPresetProfile(cfg.Difficulty).DirBalance, spec.DirBalanceTolerance)
+	for _, v := range vines {
+		b.RecordVine(v)
+	}
+	return b
+}
+
+// balancedProfile returns profile with its DirBalance scaled by b's weights,
+// so ChooseExitDirection steers away from over-represented head directions.
+func balancedProfile(profile config.VarietyProfile, b *utils.DirBalancer) config.VarietyProfile {
+	profile.DirBalance = b.Adjust(profile.DirBalance)
+	return profile
+}
@@ -1,18 +1,23 @@
 package strategies
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"sort"
 
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/utils"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
 )
 
 // DirectionFirstPlacer implements VinePlacementStrategy using direction-first growth.
 // This approach picks the exit direction first (toward nearest edge), then grows
 // the vine body backward from the head, ensuring solvability-friendly layouts.
+// Nearest-edge heads can still end up blocking each other in a cycle, so every
+// placement, extension and filler is checked with a utils.IncrementalSolver and
+// rejected if it would make the level unsolvable.
 type DirectionFirstPlacer struct{}
 
 // PlacementResult contains the result of a placement operation
@@ -31,12 +36,14 @@ type VinePlacementStats struct {
 }
 
 // PlaceVines places vines using direction-first strategy with extension passes
-func (p *DirectionFirstPlacer) PlaceVines(config config.GenerationConfig, rng *rand.Rand, stats *config.GenerationStats) ([]model.Vine, map[string]string, error) {
+func (p *DirectionFirstPlacer) PlaceVines(ctx context.Context, config config.GenerationConfig, rng *rand.Rand, stats *config.GenerationStats) ([]model.Vine, map[string]string, error) {
 	w, h := config.GridWidth, config.GridHeight
 	totalCells := w * h
 
-	occupied := make(map[string]string)
+	occupied := ReserveStencil(make(map[string]string), config.Stencil)
 	vines := make([]model.Vine, 0, config.VineCount)
+	solver := utils.NewIncrementalSolver(w, h)
+	balance := headBalancer(config, nil)
 
 	// Calculate target lengths based on difficulty
 	lengths := p.calculateVineLengths(config, rng)
@@ -45,11 +52,15 @@ func (p *DirectionFirstPlacer) PlaceVines(config config.GenerationConfig, rng *r
 
 	// Phase 1: Place initial vines using direction-first growth
 	for _, targetLen := range lengths {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
 		// Use dynamic ID based on current placed vines to avoid duplicates
-		vineID := fmt.Sprintf("vine_%d", len(vines)+1)
+		vineID := model.VineID(len(vines) + 1)
 
 		vine, newOccupied, err := p.growDirectionFirstVine(
-			vineID, targetLen, w, h, occupied, rng,
+			vineID, targetLen, w, h, occupied, solver, balance, rng, stats,
 		)
 		if err != nil {
 			// If we can't place a vine, log and continue
@@ -58,6 +69,8 @@ func (p *DirectionFirstPlacer) PlaceVines(config config.GenerationConfig, rng *r
 		}
 
 		vines = append(vines, vine)
+		solver.Place(vine)
+		balance.RecordVine(vine)
 		for k, v := range newOccupied {
 			occupied[k] = v
 		}
@@ -69,14 +82,14 @@ func (p *DirectionFirstPlacer) PlaceVines(config config.GenerationConfig, rng *r
 	coverage := float64(len(occupied)) / float64(totalCells)
 	if coverage < config.MinCoverage {
 		common.Verbose("Coverage %.1f%% below target %.1f%%, attempting extensions...", coverage*100, config.MinCoverage*100)
-		vines, occupied = p.extendVines(vines, occupied, w, h, config.MinCoverage, rng)
+		vines, occupied = p.extendVines(vines, occupied, w, h, config.MinCoverage, solver, rng)
 		coverage = float64(len(occupied)) / float64(totalCells)
 	}
 
 	// Phase 3: Fill gaps with small filler vines (minimum 2 cells)
 	if coverage < config.MinCoverage {
 		common.Verbose("Coverage %.1f%% still below target, adding filler vines...", coverage*100)
-		fillerVines, fillerOccupied := p.createFillerVines(vines, occupied, w, h, config.MinCoverage, rng)
+		fillerVines, fillerOccupied := p.createFillerVines(vines, occupied, w, h, config.MinCoverage, solver, rng)
 		vines = append(vines, fillerVines...)
 		for k, v := range fillerOccupied {
 			occupied[k] = v
@@ -96,14 +109,14 @@ func (p *DirectionFirstPlacer) PlaceVines(config config.GenerationConfig, rng *r
 }
 
 // calculateVineLengths computes target lengths based on difficulty specs
-func (p *DirectionFirstPlacer) calculateVineLengths(config config.GenerationConfig, rng *rand.Rand) []int {
-	totalCells := config.GridWidth * config.GridHeight
+func (p *DirectionFirstPlacer) calculateVineLengths(cfg config.GenerationConfig, rng *rand.Rand) []int {
+	totalCells := cfg.GridWidth * cfg.GridHeight
 
 	// Target total cells to fill based on coverage
-	targetCells := int(float64(totalCells) * config.MinCoverage)
+	targetCells := int(float64(totalCells) * cfg.MinCoverage)
 
 	// Average length per vine
-	avgLength := targetCells / config.VineCount
+	avgLength := targetCells / cfg.VineCount
 	if avgLength < 2 {
 		avgLength = 2 // Minimum length
 	}
@@ -118,7 +131,15 @@ func (p *DirectionFirstPlacer) calculateVineLengths(config config.GenerationConf
 		maxLength = 3
 	}
 
-	lengths := make([]int, config.VineCount)
+	lengths := make([]int, cfg.VineCount)
+	if spec, ok := config.SpecFor(cfg); ok && spec.LengthDistribution.Enabled() {
+		// The tier's curve sets the lengths; extensions and fillers make up coverage
+		limit := (cfg.GridWidth + cfg.GridHeight) / 2
+		for i := range lengths {
+			lengths[i] = spec.LengthDistribution.Sample(rng, limit)
+		}
+		return lengths
+	}
 	totalPlanned := 0
 
 	for i := range lengths {
@@ -142,25 +163,33 @@ func (p *DirectionFirstPlacer) calculateVineLengths(config config.GenerationConf
 
 // growDirectionFirstVine grows a vine using direction-first strategy:
 // 1. Pick a seed cell
-// 2. Choose head direction toward nearest edge (guarantees exit path)
+// 2. Choose head direction toward nearest edge (guarantees exit path),
+// letting balance steer away from over-represented directions
 // 3. Grow body backward from head
+// 4. Reject the vine if solver reports it would close a blocking cycle
+// 5. Pass over a vine that would strand dead cells while retries last
 func (p *DirectionFirstPlacer) growDirectionFirstVine(
 	vineID string,
 	targetLen int,
 	w, h int,
 	occupied map[string]string,
+	solver *utils.IncrementalSolver,
+	balance *utils.DirBalancer,
 	rng *rand.Rand,
+	stats *config.GenerationStats,
 ) (model.Vine, map[string]string, error) {
 	// Try multiple seeds to find one that works
 	maxSeedAttempts := 20
+	grid := common.GridFromOccupancy(w, h, occupied)
+	guard := strandGuard{islands: utils.NewIslandDetectorFor(grid), stats: stats}
 	for attempt := 0; attempt < maxSeedAttempts; attempt++ {
-		seed := p.chooseSeed(w, h, occupied, rng)
+		seed := p.chooseSeed(grid, rng)
 		if seed == nil {
 			continue
 		}
 
 		// Determine head direction toward nearest edge
-		headDirection := common.ChooseExitDirection(*seed, w, h)
+		headDirection := balance.RankExits(*seed, w, h)[0]
 
 		// The head is at the seed position
 		// We grow the body BACKWARD (opposite to head direction)
@@ -170,6 +199,8 @@ func (p *DirectionFirstPlacer) growDirectionFirstVine(
 		path := []model.Point{*seed}
 		localOccupied := make(map[string]string)
 		localOccupied[fmt.Sprintf("%d,%d", seed.X, seed.Y)] = vineID
+		local := grid.Clone()
+		local.Set(seed.X, seed.Y)
 
 		// Grow the vine body
 		for len(path) < targetLen {
@@ -181,15 +212,12 @@ func (p *DirectionFirstPlacer) growDirectionFirstVine(
 				// to match the chosen headDirection.
 				dx, dy := common.DeltaForDirection(growDir)
 				cand := model.Point{X: current.X + dx, Y: current.Y + dy}
-				if cand.X >= 0 && cand.X < w && cand.Y >= 0 && cand.Y < h {
-					key := fmt.Sprintf("%d,%d", cand.X, cand.Y)
-					if _, globallyOcc := occupied[key]; !globallyOcc {
-						next = &cand
-					}
+				if grid.IsFree(cand.X, cand.Y) {
+					next = &cand
 				}
 			} else {
 				// Subsequent segments can turn
-				next = p.chooseNextCell(current, growDir, path, w, h, occupied, localOccupied, rng)
+				next = p.chooseNextCell(current, growDir, path, local, rng)
 			}
 
 			if next == nil {
@@ -198,17 +226,22 @@ func (p *DirectionFirstPlacer) growDirectionFirstVine(
 
 			path = append(path, *next)
 			localOccupied[fmt.Sprintf("%d,%d", next.X, next.Y)] = vineID
+			local.Set(next.X, next.Y)
 		}
 
-		// Validate minimum length (2 cells)
-		if len(path) < 2 {
+		// Validate minimum length and head/neck orientation
+		vine, err := model.NewVine(vineID, path, headDirection)
+		if err != nil {
 			continue // Try another seed
 		}
-
-		vine := model.Vine{
-			ID:            vineID,
-			HeadDirection: headDirection,
-			OrderedPath:   path,
+		if !solver.CanPlace(vine) {
+			if stats != nil {
+				stats.SolvabilityPrunes++
+			}
+			continue // Would deadlock; try another seed
+		}
+		if guard.reject(vine.OrderedPath) {
+			continue // Would strand dead cells; try another seed
 		}
 
 		return vine, localOccupied, nil
@@ -223,19 +256,16 @@ func isEdgeCell(x, y, w, h int) bool {
 }
 
 // isCandidateCell checks if a cell is available and has a free neighbor
-func (p *DirectionFirstPlacer) isCandidateCell(x, y, w, h int, occupied map[string]string) bool {
-	key := fmt.Sprintf("%d,%d", x, y)
-	if _, isOccupied := occupied[key]; isOccupied {
-		return false
-	}
-	return p.hasFreeNeighbor(x, y, w, h, occupied)
+func (p *DirectionFirstPlacer) isCandidateCell(x, y int, grid *common.Grid) bool {
+	return !grid.Has(x, y) && grid.CountFreeNeighbors(x, y) > 0
 }
 
 // collectCandidateCells collects empty cells with free neighbors, categorized as corner, edge or interior
-func (p *DirectionFirstPlacer) collectCandidateCells(w, h int, occupied map[string]string) (corner, edge, interior []model.Point) {
+func (p *DirectionFirstPlacer) collectCandidateCells(grid *common.Grid) (corner, edge, interior []model.Point) {
+	w, h := grid.Width(), grid.Height()
 	for y := 0; y < h; y++ {
 		for x := 0; x < w; x++ {
-			if !p.isCandidateCell(x, y, w, h, occupied) {
+			if !p.isCandidateCell(x, y, grid) {
 				continue
 			}
 			pt := model.Point{X: x, Y: y}
@@ -252,8 +282,8 @@ func (p *DirectionFirstPlacer) collectCandidateCells(w, h int, occupied map[stri
 }
 
 // chooseSeed finds a suitable starting cell for a new vine
-func (p *DirectionFirstPlacer) chooseSeed(w, h int, occupied map[string]string, rng *rand.Rand) *model.Point {
-	cornerCandidates, edgeCandidates, interiorCandidates := p.collectCandidateCells(w, h, occupied)
+func (p *DirectionFirstPlacer) chooseSeed(grid *common.Grid, rng *rand.Rand) *model.Point {
+	cornerCandidates, edgeCandidates, interiorCandidates := p.collectCandidateCells(grid)
 
 	// Prefer corners (90% chance if available)
 	if len(cornerCandidates) > 0 && rng.Float64() < 0.9 {
@@ -281,21 +311,6 @@ func (p *DirectionFirstPlacer) isCornerCell(x, y, w, h int) bool {
 	return (x == 0 || x == w-1) && (y == 0 || y == h-1)
 }
 
-// hasFreeNeighbor checks if a cell has at least one unoccupied orthogonal neighbor
-func (p *DirectionFirstPlacer) hasFreeNeighbor(x, y, w, h int, occupied map[string]string) bool {
-	deltas := []struct{ dx, dy int }{{0, 1}, {0, -1}, {1, 0}, {-1, 0}}
-	for _, d := range deltas {
-		nx, ny := x+d.dx, y+d.dy
-		if nx >= 0 && nx < w && ny >= 0 && ny < h {
-			key := fmt.Sprintf("%d,%d", nx, ny)
-			if _, isOccupied := occupied[key]; !isOccupied {
-				return true
-			}
-		}
-	}
-	return false
-}
-
 // scoredPoint pairs a point with its selection score
 type scoredPoint struct {
 	point model.Point
@@ -306,8 +321,7 @@ type scoredPoint struct {
 func (p *DirectionFirstPlacer) scoreNeighbor(
 	current, neighbor model.Point,
 	preferredDir string,
-	w, h int,
-	globalOccupied, localOccupied map[string]string,
+	grid *common.Grid,
 	rng *rand.Rand,
 ) float64 {
 	score := 0.0
@@ -327,7 +341,7 @@ func (p *DirectionFirstPlacer) scoreNeighbor(
 	}
 
 	// Slight preference for cells with more free neighbors (avoids dead ends)
-	freeCount := p.countFreeNeighbors(neighbor, w, h, globalOccupied, localOccupied)
+	freeCount := grid.CountFreeNeighbors(neighbor.X, neighbor.Y)
 	score += float64(freeCount) * 0.3
 
 	// Add randomness to prevent deterministic patterns
@@ -340,11 +354,10 @@ func (p *DirectionFirstPlacer) chooseNextCell(
 	current model.Point,
 	preferredDir string,
 	_ []model.Point, // path kept for interface compatibility
-	w, h int,
-	globalOccupied, localOccupied map[string]string,
+	grid *common.Grid,
 	rng *rand.Rand,
 ) *model.Point {
-	neighbors := p.getAvailableNeighbors(current, w, h, globalOccupied, localOccupied)
+	neighbors := grid.FreeNeighbors(current, nil)
 	if len(neighbors) == 0 {
 		return nil
 	}
@@ -353,7 +366,7 @@ func (p *DirectionFirstPlacer) chooseNextCell(
 	for i, neighbor := range neighbors {
 		scoredNeighbors[i] = scoredPoint{
 			point: neighbor,
-			score: p.scoreNeighbor(current, neighbor, preferredDir, w, h, globalOccupied, localOccupied, rng),
+			score: p.scoreNeighbor(current, neighbor, preferredDir, grid, rng),
 		}
 	}
 
@@ -371,37 +384,13 @@ func (p *DirectionFirstPlacer) chooseNextCell(
 	return &scoredNeighbors[0].point
 }
 
-// getAvailableNeighbors returns unoccupied orthogonal neighbors
-func (p *DirectionFirstPlacer) getAvailableNeighbors(pos model.Point, w, h int, globalOccupied, localOccupied map[string]string) []model.Point {
-	deltas := []struct{ dx, dy int }{{0, 1}, {0, -1}, {1, 0}, {-1, 0}}
-	var neighbors []model.Point
-
-	for _, d := range deltas {
-		nx, ny := pos.X+d.dx, pos.Y+d.dy
-		if nx >= 0 && nx < w && ny >= 0 && ny < h {
-			key := fmt.Sprintf("%d,%d", nx, ny)
-			_, globallyOccupied := globalOccupied[key]
-			_, locallyOccupied := localOccupied[key]
-			if !globallyOccupied && !locallyOccupied {
-				neighbors = append(neighbors, model.Point{X: nx, Y: ny})
-			}
-		}
-	}
-
-	return neighbors
-}
-
-// countFreeNeighbors counts how many free neighbors a cell has
-func (p *DirectionFirstPlacer) countFreeNeighbors(pos model.Point, w, h int, globalOccupied, localOccupied map[string]string) int {
-	return len(p.getAvailableNeighbors(pos, w, h, globalOccupied, localOccupied))
-}
-
 // extendVines tries to extend existing vines from their tails
 func (p *DirectionFirstPlacer) extendVines(
 	vines []model.Vine,
 	occupied map[string]string,
 	w, h int,
 	targetCoverage float64,
+	solver *utils.IncrementalSolver,
 	rng *rand.Rand,
 ) ([]model.Vine, map[string]string) {
 	totalCells := w * h
@@ -410,6 +399,7 @@ func (p *DirectionFirstPlacer) extendVines(
 	// Make a mutable copy
 	result := make([]model.Vine, len(vines))
 	copy(result, vines)
+	grid := common.GridFromOccupancy(w, h, occupied)
 
 	maxPasses := 3
 	for pass := 0; pass < maxPasses; pass++ {
@@ -429,7 +419,7 @@ func (p *DirectionFirstPlacer) extendVines(
 			tail := vine.OrderedPath[len(vine.OrderedPath)-1]
 
 			// Find available neighbors for the tail
-			neighbors := p.getAvailableNeighbors(tail, w, h, occupied, nil)
+			neighbors := grid.FreeNeighbors(tail, nil)
 			if len(neighbors) == 0 {
 				continue
 			}
@@ -437,10 +427,19 @@ func (p *DirectionFirstPlacer) extendVines(
 			// Pick a random neighbor
 			next := neighbors[rng.Intn(len(neighbors))]
 
+			// Skip extensions that would land on a vine's exit ray and close a cycle
+			grown := *vine
+			grown.OrderedPath = append(append([]model.Point(nil), vine.OrderedPath...), next)
+			if !solver.CanPlace(grown) {
+				continue
+			}
+			solver.Place(grown)
+
 			// Add to vine path
-			vine.OrderedPath = append(vine.OrderedPath, next)
+			vine.OrderedPath = grown.OrderedPath
 			key := fmt.Sprintf("%d,%d", next.X, next.Y)
 			occupied[key] = vine.ID
+			grid.Set(next.X, next.Y)
 			extended = true
 		}
 
@@ -452,58 +451,48 @@ func (p *DirectionFirstPlacer) extendVines(
 	return result, occupied
 }
 
-// mergeOccupied combines two occupied maps into a new map
-func mergeOccupied(a, b map[string]string) map[string]string {
-	merged := make(map[string]string, len(a)+len(b))
-	for k, v := range a {
-		merged[k] = v
-	}
-	for k, v := range b {
-		merged[k] = v
-	}
-	return merged
-}
-
 // createFillerVines creates small vines to fill remaining gaps (minimum 2 cells)
 func (p *DirectionFirstPlacer) createFillerVines(
 	existingVines []model.Vine,
 	occupied map[string]string,
 	w, h int,
 	targetCoverage float64,
+	solver *utils.IncrementalSolver,
 	rng *rand.Rand,
 ) ([]model.Vine, map[string]string) {
 	targetCells := int(float64(w*h) * targetCoverage)
 	fillerVines := []model.Vine{}
 	fillerOccupied := make(map[string]string)
-	// Compute next filler ID by scanning existing vine IDs to avoid collisions
-	fillerID := 1
-	for _, ev := range existingVines {
-		var idx int
-		if n, err := fmt.Sscanf(ev.ID, "vine_%d", &idx); n == 1 && err == nil {
-			if idx >= fillerID {
-				fillerID = idx + 1
-			}
-		}
-	}
+	// Number fillers after the existing vines to avoid ID collisions
+	fillerID := model.NextVineIndex(existingVines)
 
+	// grid mirrors occupied plus fillerOccupied, including skip markers
+	grid := common.GridFromOccupancy(w, h, occupied)
 	for len(occupied)+len(fillerOccupied) < targetCells {
-		seed := p.findFillerSeed(w, h, occupied, fillerOccupied, rng)
+		seed := p.findFillerSeed(grid, rng)
 		if seed == nil {
 			break
 		}
 
-		combined := mergeOccupied(occupied, fillerOccupied)
-		neighbors := p.getAvailableNeighbors(*seed, w, h, combined, nil)
+		neighbors := grid.FreeNeighbors(*seed, nil)
 		if len(neighbors) == 0 {
 			fillerOccupied[fmt.Sprintf("%d,%d", seed.X, seed.Y)] = "skip"
+			grid.Set(seed.X, seed.Y)
 			continue
 		}
 
-		vine, newOccupied := p.buildFillerVine(seed, neighbors, fillerID, rng)
+		vine, newOccupied, err := p.buildFillerVine(seed, neighbors, fillerID, solver, rng)
+		if err != nil {
+			fillerOccupied[fmt.Sprintf("%d,%d", seed.X, seed.Y)] = "skip"
+			grid.Set(seed.X, seed.Y)
+			continue
+		}
 		fillerVines = append(fillerVines, vine)
+		solver.Place(vine)
 		for k, v := range newOccupied {
 			fillerOccupied[k] = v
 		}
+		grid.SetPath(vine.OrderedPath)
 		fillerID++
 	}
 
@@ -516,37 +505,46 @@ func (p *DirectionFirstPlacer) createFillerVines(
 	return fillerVines, fillerOccupied
 }
 
-// buildFillerVine creates a 2-cell filler vine from a seed and its neighbors
+// buildFillerVine creates a 2-cell filler vine from a seed and one of its neighbors,
+// starting at a random neighbor and skipping pairings that would close a blocking cycle
 func (p *DirectionFirstPlacer) buildFillerVine(
 	seed *model.Point,
 	neighbors []model.Point,
 	fillerID int,
+	solver *utils.IncrementalSolver,
 	rng *rand.Rand,
-) (model.Vine, map[string]string) {
-	neighbor := neighbors[rng.Intn(len(neighbors))]
-	headDir := common.DirectionFromPoints(neighbor, *seed)
-	if headDir == "" {
-		headDir = common.DirUp
+) (model.Vine, map[string]string, error) {
+	vineID := model.VineID(fillerID)
+	start := rng.Intn(len(neighbors))
+
+	var vine model.Vine
+	var neighbor model.Point
+	var err error
+	for i := range neighbors {
+		neighbor = neighbors[(start+i)%len(neighbors)]
+		// Head direction is derived from the seed→neighbor geometry
+		vine, err = model.NewVine(vineID, []model.Point{*seed, neighbor}, "")
+		if err == nil && !solver.CanPlace(vine) {
+			err = fmt.Errorf("filler at (%d,%d) would deadlock", seed.X, seed.Y)
+		}
+		if err == nil {
+			break
+		}
 	}
-
-	vineID := fmt.Sprintf("vine_%d", fillerID)
-	vine := model.Vine{
-		ID:            vineID,
-		HeadDirection: headDir,
-		OrderedPath:   []model.Point{*seed, neighbor},
+	if err != nil {
+		return model.Vine{}, nil, err
 	}
 
 	newOccupied := map[string]string{
 		fmt.Sprintf("%d,%d", seed.X, seed.Y):         vineID,
 		fmt.Sprintf("%d,%d", neighbor.X, neighbor.Y): vineID,
 	}
-	return vine, newOccupied
+	return vine, newOccupied, nil
 }
 
 // findFillerSeed finds an empty cell that can form a 2-cell filler vine
-func (p *DirectionFirstPlacer) findFillerSeed(w, h int, occupied, fillerOccupied map[string]string, rng *rand.Rand) *model.Point {
-	combined := mergeOccupied(occupied, fillerOccupied)
-	candidates := p.collectFillerCandidates(w, h, combined)
+func (p *DirectionFirstPlacer) findFillerSeed(grid *common.Grid, rng *rand.Rand) *model.Point {
+	candidates := p.collectFillerCandidates(grid)
 	if len(candidates) == 0 {
 		return nil
 	}
@@ -554,16 +552,11 @@ func (p *DirectionFirstPlacer) findFillerSeed(w, h int, occupied, fillerOccupied
 }
 
 // collectFillerCandidates gathers all empty cells that have at least one free neighbor
-func (p *DirectionFirstPlacer) collectFillerCandidates(w, h int, combined map[string]string) []model.Point {
+func (p *DirectionFirstPlacer) collectFillerCandidates(grid *common.Grid) []model.Point {
 	var candidates []model.Point
-	for y := 0; y < h; y++ {
-		for x := 0; x < w; x++ {
-			key := fmt.Sprintf("%d,%d", x, y)
-			if _, occ := combined[key]; occ {
-				continue
-			}
-			neighbors := p.getAvailableNeighbors(model.Point{X: x, Y: y}, w, h, combined, nil)
-			if len(neighbors) > 0 {
+	for y := 0; y < grid.Height(); y++ {
+		for x := 0; x < grid.Width(); x++ {
+			if !grid.Has(x, y) && grid.CountFreeNeighbors(x, y) > 0 {
 				candidates = append(candidates, model.Point{X: x, Y: y})
 			}
 		}
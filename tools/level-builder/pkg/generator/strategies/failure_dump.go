@@ -0,0 +1,62 @@
+package strategies
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// FailureDump is the JSON snapshot WriteFailureDump records when placement
+// fails: the config and seed that produced the failure and the vines placed
+// so far, in placement order.
+type FailureDump struct {
+	LevelID  int     `json:"level_id"`
+	Grid     []int   `json:"grid"`
+	Seed     int64   `json:"seed"`
+	Attempt  int     `json:"attempt"`
+	Message  string  `json:"message"`
+	Coverage float64 `json:"coverage"`
+	// Config is nil in dumps written before configs were recorded.
+	Config   *config.GenerationConfig `json:"config,omitempty"`
+	Vines    []model.Vine             `json:"vines"`
+	Occupied map[string]string        `json:"occupied"`
+}
+
+// LoadFailureDump reads a dump written by WriteFailureDump.
+func LoadFailureDump(path string) (FailureDump, error) {
+	var dump FailureDump
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return FailureDump{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &dump); err != nil {
+		return FailureDump{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(dump.Grid) != 2 {
+		return FailureDump{}, fmt.Errorf("%s: grid must have 2 entries, got %d", path, len(dump.Grid))
+	}
+	return dump, nil
+}
+
+// Same reports whether other failed with the same message and placed vines,
+// i.e. whether a replay reproduced this dump.
+func (d FailureDump) Same(other FailureDump) bool {
+	if d.Message != other.Message || len(d.Vines) != len(other.Vines) {
+		return false
+	}
+	for i, v := range d.Vines {
+		o := other.Vines[i]
+		if v.ID != o.ID || v.HeadDirection != o.HeadDirection || len(v.OrderedPath) != len(o.OrderedPath) {
+			return false
+		}
+		for j, p := range v.OrderedPath {
+			if p != o.OrderedPath[j] {
+				return false
+			}
+		}
+	}
+	return true
+}
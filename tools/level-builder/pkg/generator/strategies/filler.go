@@ -0,0 +1,332 @@
+package strategies
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// FillerStrategy fills the gaps left after primary vine placement. Implementations
+// return only the new filler vines and the cells they occupy; occupied must not
+// be modified. New heuristics are added with RegisterFiller and selected by name
+// (GenerationConfig.FillerStrategy / --filler-strategy) for A/B testing.
+type FillerStrategy interface {
+	FillGaps(
+		existing []model.Vine,
+		occupied map[string]string,
+		w, h int,
+		targetCoverage float64,
+		rng *rand.Rand,
+	) ([]model.Vine, map[string]string)
+}
+
+// Built-in filler strategy names.
+const (
+	FillerLIFO = "lifo"
+	FillerGap  = "gap"
+)
+
+// DefaultFiller is used when no filler strategy is configured.
+const DefaultFiller = FillerLIFO
+
+// FillerFactory creates a new FillerStrategy instance.
+type FillerFactory func() FillerStrategy
+
+// FillerInfo contains metadata about a registered filler strategy.
+type FillerInfo struct {
+	Name        string
+	Description string
+	Factory     FillerFactory
+}
+
+var (
+	fillerMap  = make(map[string]FillerInfo)
+	fillerLock sync.RWMutex
+)
+
+// RegisterFiller registers a filler strategy under name.
+func RegisterFiller(name, description string, factory FillerFactory) {
+	fillerLock.Lock()
+	defer fillerLock.Unlock()
+
+	fillerMap[name] = FillerInfo{Name: name, Description: description, Factory: factory}
+}
+
+// GetFiller returns a new instance of the named filler strategy.
+func GetFiller(name string) (FillerStrategy, error) {
+	fillerLock.RLock()
+	defer fillerLock.RUnlock()
+
+	info, ok := fillerMap[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown filler strategy: %s", name)
+	}
+	return info.Factory(), nil
+}
+
+// ListFillers returns all registered filler strategies sorted by name.
+func ListFillers() []FillerInfo {
+	fillerLock.RLock()
+	defer fillerLock.RUnlock()
+
+	list := make([]FillerInfo, 0, len(fillerMap))
+	for _, info := range fillerMap {
+		list = append(list, info)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+func init() {
+	RegisterFiller(FillerLIFO, "2-cell fillers with clear exits, interior first then edges (LIFO safe)", func() FillerStrategy {
+		return &LIFOFiller{}
+	})
+	RegisterFiller(FillerGap, "Multi-cell gap growth (3-5 cells) with 2-cell fallback", func() FillerStrategy {
+		return &GapGrowthFiller{}
+	})
+}
+
+// GapGrowthFiller adapts GapFiller to the FillerStrategy interface.
+type GapGrowthFiller struct{}
+
+// FillGaps grows multi-cell fillers until targetCoverage is reached or no gap can be filled.
+func (f *GapGrowthFiller) FillGaps(
+	existing []model.Vine,
+	occupied map[string]string,
+	w, h int,
+	targetCoverage float64,
+	rng *rand.Rand,
+) ([]model.Vine, map[string]string) {
+	vines, all := NewGapFiller(w, h, rng).FillGaps(model.NextVineIndex(existing), occupied)
+
+	targetCells := int(float64(w*h) * targetCoverage)
+	fillerOccupied := make(map[string]string)
+	kept := []model.Vine{}
+	for _, v := range vines {
+		if len(occupied)+len(fillerOccupied) >= targetCells {
+			break
+		}
+		kept = append(kept, v)
+		for _, p := range v.OrderedPath {
+			key := fmt.Sprintf("%d,%d", p.X, p.Y)
+			fillerOccupied[key] = all[key]
+		}
+	}
+	return kept, fillerOccupied
+}
+
+// LIFOFiller places 2-cell filler vines whose heads have a clear exit at
+// placement time, trying interior cells first and edge cells as a fallback.
+type LIFOFiller struct{}
+
+// FillGaps places LIFO-guaranteed fillers until targetCoverage is reached.
+func (f *LIFOFiller) FillGaps(
+	existing []model.Vine,
+	occupied map[string]string,
+	w, h int,
+	targetCoverage float64,
+	rng *rand.Rand,
+) ([]model.Vine, map[string]string) {
+	targetCells := int(float64(w*h) * targetCoverage)
+	vines, fillerOccupied, _ := f.fillWithLIFOGuarantee(model.NextVineIndex(existing), occupied, w, h, targetCells, rng)
+	return vines, fillerOccupied
+}
+
+// fillWithLIFOGuarantee places filler vines with guaranteed clear exit paths
+func (f *LIFOFiller) fillWithLIFOGuarantee(
+	startID int,
+	occupied map[string]string,
+	w, h int,
+	targetCells int,
+	rng *rand.Rand,
+) ([]model.Vine, map[string]string, int) {
+	vines := []model.Vine{}
+	fillerOccupied := make(map[string]string)
+	fillerID := startID
+	maxIterations := w * h * 3
+	lastCoverage := len(occupied)
+	grid := common.GridFromOccupancy(w, h, occupied)
+
+	for i := 0; i < maxIterations; i++ {
+		currentCoverage := len(occupied) + len(fillerOccupied)
+
+		if currentCoverage >= targetCells {
+			break
+		}
+		if i > 10 && currentCoverage == lastCoverage {
+			break
+		}
+		lastCoverage = currentCoverage
+
+		vine, vineOccupied := f.tryPlaceFillerVine(model.VineID(fillerID), grid, rng)
+		if vine.ID == "" {
+			vine, vineOccupied = f.tryPlaceEdgeFillerVine(model.VineID(fillerID), grid, rng)
+		}
+		if vine.ID == "" {
+			break
+		}
+
+		vines = append(vines, vine)
+		for k, v := range vineOccupied {
+			fillerOccupied[k] = v
+		}
+		grid.SetPath(vine.OrderedPath)
+		fillerID++
+	}
+
+	return vines, fillerOccupied, fillerID
+}
+
+// edgeCandidate represents an edge cell with its exit direction
+type edgeCandidate struct {
+	pt  model.Point
+	dir string
+}
+
+// collectEdgeCells gathers all empty edge cells with their exit directions
+func (f *LIFOFiller) collectEdgeCells(grid *common.Grid) []edgeCandidate {
+	var edgeCells []edgeCandidate
+	w, h := grid.Width(), grid.Height()
+
+	// Top and bottom edges
+	for x := 0; x < w; x++ {
+		if !grid.Has(x, h-1) {
+			edgeCells = append(edgeCells, edgeCandidate{model.Point{X: x, Y: h - 1}, "up"})
+		}
+		if !grid.Has(x, 0) {
+			edgeCells = append(edgeCells, edgeCandidate{model.Point{X: x, Y: 0}, "down"})
+		}
+	}
+
+	// Left and right edges
+	for y := 0; y < h; y++ {
+		if !grid.Has(0, y) {
+			edgeCells = append(edgeCells, edgeCandidate{model.Point{X: 0, Y: y}, "left"})
+		}
+		if !grid.Has(w-1, y) {
+			edgeCells = append(edgeCells, edgeCandidate{model.Point{X: w - 1, Y: y}, "right"})
+		}
+	}
+
+	return edgeCells
+}
+
+// tryPlaceEdgeFillerVine tries to place a filler vine with head at an edge
+func (f *LIFOFiller) tryPlaceEdgeFillerVine(
+	vineID string,
+	grid *common.Grid,
+	rng *rand.Rand,
+) (model.Vine, map[string]string) {
+	edgeCells := f.collectEdgeCells(grid)
+	if len(edgeCells) == 0 {
+		return model.Vine{}, nil
+	}
+
+	rng.Shuffle(len(edgeCells), func(i, j int) {
+		edgeCells[i], edgeCells[j] = edgeCells[j], edgeCells[i]
+	})
+
+	for _, ec := range edgeCells {
+		vine, vineOccupied := f.tryCreateEdgeVine(vineID, ec.pt, ec.dir, grid)
+		if vine.ID != "" {
+			return vine, vineOccupied
+		}
+	}
+
+	return model.Vine{}, nil
+}
+
+// tryCreateEdgeVine attempts to create a 2-cell vine from an edge cell
+func (f *LIFOFiller) tryCreateEdgeVine(
+	vineID string,
+	head model.Point,
+	headDir string,
+	grid *common.Grid,
+) (model.Vine, map[string]string) {
+	neckDir := common.OppositeDirection(headDir)
+	dx, dy := common.DeltaForDirection(neckDir)
+	neck := model.Point{X: head.X + dx, Y: head.Y + dy}
+
+	if !grid.IsFree(neck.X, neck.Y) {
+		return model.Vine{}, nil
+	}
+
+	neckKey := fmt.Sprintf("%d,%d", neck.X, neck.Y)
+	headKey := fmt.Sprintf("%d,%d", head.X, head.Y)
+	vineOccupied := map[string]string{
+		headKey: vineID,
+		neckKey: vineID,
+	}
+
+	vine, err := model.NewVine(vineID, []model.Point{head, neck}, headDir)
+	if err != nil {
+		return model.Vine{}, nil
+	}
+	return vine, vineOccupied
+}
+
+// tryPlaceFillerVine attempts to place a single 2-cell filler vine with valid orientation
+func (f *LIFOFiller) tryPlaceFillerVine(
+	vineID string,
+	grid *common.Grid,
+	rng *rand.Rand,
+) (model.Vine, map[string]string) {
+	// Find all empty cells
+	var emptyCells []model.Point
+	for y := 0; y < grid.Height(); y++ {
+		for x := 0; x < grid.Width(); x++ {
+			if !grid.Has(x, y) {
+				emptyCells = append(emptyCells, model.Point{X: x, Y: y})
+			}
+		}
+	}
+
+	if len(emptyCells) < 2 {
+		return model.Vine{}, nil
+	}
+
+	// Shuffle for randomness
+	rng.Shuffle(len(emptyCells), func(i, j int) {
+		emptyCells[i], emptyCells[j] = emptyCells[j], emptyCells[i]
+	})
+
+	// Try each empty cell as potential head
+	for _, head := range emptyCells {
+		// Find a free neighbor for neck
+		neighbors := grid.FreeNeighbors(head, nil)
+		if len(neighbors) == 0 {
+			continue
+		}
+
+		// Try each neighbor as potential neck
+		for _, neck := range neighbors {
+			// Head direction is derived from the head→neck geometry
+			vine, err := model.NewVine(vineID, []model.Point{head, neck}, "")
+			if err != nil {
+				continue
+			}
+
+			// Verify the head has a clear exit path
+			if !grid.IsExitPathClear(head, vine.HeadDirection) {
+				continue
+			}
+
+			// Valid placement found
+			headKey := fmt.Sprintf("%d,%d", head.X, head.Y)
+			neckKey := fmt.Sprintf("%d,%d", neck.X, neck.Y)
+
+			vineOccupied := map[string]string{
+				headKey: vineID,
+				neckKey: vineID,
+			}
+
+			return vine, vineOccupied
+		}
+	}
+
+	return model.Vine{}, nil
+}
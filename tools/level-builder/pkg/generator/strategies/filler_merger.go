@@ -0,0 +1,121 @@
+package strategies
+
+import (
+	"sort"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+// FillerMaxCells is the longest vine FillerMerger treats as a filler. Placers
+// and gap fillers fill leftover space with 2- and 3-cell vines.
+const FillerMaxCells = 3
+
+// FillerMerger joins neighbouring filler vines into longer vines, so gap
+// filling leaves fewer 2-cell vines cluttering the board.
+type FillerMerger struct {
+	w, h   int
+	maxLen int // Longest merged vine; 0 means no limit
+}
+
+// NewFillerMerger creates a FillerMerger that builds vines of at most maxLen
+// cells (0 = no limit).
+func NewFillerMerger(w, h, maxLen int) *FillerMerger {
+	return &FillerMerger{w: w, h: h, maxLen: maxLen}
+}
+
+// Merge joins pairs of filler vines (at most FillerMaxCells cells) whose ends
+// touch, shortest vines first. The merged path runs through both vines and may
+// take its head at either end; a merge is kept only if the level stays
+// structurally valid and greedily solvable, so nothing changes when the vines
+// are not solvable to begin with. It returns the remaining vines and how many
+// merges it made. The merged vine keeps the first vine's ID and place in the
+// slice; run it before vine IDs are renumbered.
+func (m *FillerMerger) Merge(vines []model.Vine) ([]model.Vine, int) {
+	level := model.Level{GridSize: []int{m.w, m.h}, Vines: vines}
+	if !common.NewSolver(&level).IsSolvableGreedy() {
+		return vines, 0
+	}
+
+	merged := 0
+	for m.mergeOne(&level) {
+		merged++
+	}
+	return level.Vines, merged
+}
+
+// mergeOne makes the first merge that keeps level valid and reports whether
+// it found one.
+func (m *FillerMerger) mergeOne(level *model.Level) bool {
+	var fillers []int
+	for i, v := range level.Vines {
+		if len(v.OrderedPath) <= FillerMaxCells {
+			fillers = append(fillers, i)
+		}
+	}
+	sort.SliceStable(fillers, func(a, b int) bool {
+		return len(level.Vines[fillers[a]].OrderedPath) < len(level.Vines[fillers[b]].OrderedPath)
+	})
+
+	for _, i := range fillers {
+		for _, j := range fillers {
+			a, b := level.Vines[i], level.Vines[j]
+			if i == j || (m.maxLen > 0 && len(a.OrderedPath)+len(b.OrderedPath) > m.maxLen) {
+				continue
+			}
+			for _, path := range joinedPaths(a.OrderedPath, b.OrderedPath) {
+				v, err := model.NewVine(a.ID, path, "")
+				if err != nil {
+					continue
+				}
+				candidate := *level
+				candidate.Vines = withMerged(level.Vines, i, j, v)
+				if len(validator.ValidateStructural(candidate)) == 0 && common.NewSolver(&candidate).IsSolvableGreedy() {
+					level.Vines = candidate.Vines
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// joinedPaths returns the paths through both a and b that join an end of one
+// to a touching end of the other, each read from either end.
+func joinedPaths(a, b []model.Point) [][]model.Point {
+	var paths [][]model.Point
+	for _, first := range [][]model.Point{a, reversedPath(a)} {
+		for _, second := range [][]model.Point{b, reversedPath(b)} {
+			if !touches(first[len(first)-1], second[0]) {
+				continue
+			}
+			path := append(append([]model.Point(nil), first...), second...)
+			paths = append(paths, path, reversedPath(path))
+		}
+	}
+	return paths
+}
+
+// withMerged returns vines with vines[i] replaced by v and vines[j] removed.
+func withMerged(vines []model.Vine, i, j int, v model.Vine) []model.Vine {
+	out := make([]model.Vine, 0, len(vines)-1)
+	for k, existing := range vines {
+		switch k {
+		case i:
+			out = append(out, v)
+		case j:
+		default:
+			out = append(out, existing)
+		}
+	}
+	return out
+}
+
+func reversedPath(path []model.Point) []model.Point {
+	out := make([]model.Point, len(path))
+	for i, p := range path {
+		out[len(path)-1-i] = p
+	}
+	return out
+}
@@ -0,0 +1,49 @@
+package strategies
+
+import (
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+func TestFillerMerger(t *testing.T) {
+	mustVine := func(id string, path []model.Point) model.Vine {
+		v, err := model.NewVine(id, path, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	// Two 2-cell fillers end to end along the top row
+	vines := []model.Vine{
+		mustVine("a", []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}),
+		mustVine("b", []model.Point{{X: 3, Y: 0}, {X: 2, Y: 0}}),
+		mustVine("c", []model.Point{{X: 0, Y: 2}, {X: 0, Y: 3}, {X: 1, Y: 3}, {X: 2, Y: 3}, {X: 3, Y: 3}}),
+	}
+	merged, n := NewFillerMerger(4, 4, 5).Merge(vines)
+	if n != 1 || len(merged) != 2 {
+		t.Fatalf("made %d merges leaving %d vines, want 1 merge leaving 2", n, len(merged))
+	}
+	if got := merged[0]; got.ID != "a" || len(got.OrderedPath) != 4 {
+		t.Errorf("merged vine = %s with %d cells, want a with 4", got.ID, len(got.OrderedPath))
+	}
+	level := model.Level{GridSize: []int{4, 4}, Vines: merged}
+	if errs := validator.ValidateStructural(level); len(errs) > 0 {
+		t.Errorf("merged level invalid: %v", errs)
+	}
+	if !common.NewSolver(&level).IsSolvableGreedy() {
+		t.Error("merged level not solvable")
+	}
+
+	// The length cap and vines longer than a filler keep vines apart
+	if _, n := NewFillerMerger(4, 4, 3).Merge(vines); n != 0 {
+		t.Errorf("made %d merges past the length cap", n)
+	}
+	long := []model.Vine{vines[0], mustVine("d", []model.Point{{X: 2, Y: 1}, {X: 2, Y: 0}, {X: 3, Y: 0}, {X: 3, Y: 1}})}
+	if _, n := NewFillerMerger(4, 4, 0).Merge(long); n != 0 {
+		t.Errorf("merged a %d-cell vine as a filler", len(long[1].OrderedPath))
+	}
+}
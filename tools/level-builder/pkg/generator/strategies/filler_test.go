@@ -0,0 +1,91 @@
+package strategies
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// countingFiller records calls and delegates to the default LIFO filler.
+type countingFiller struct {
+	calls int
+}
+
+func (c *countingFiller) FillGaps(existing []model.Vine, occupied map[string]string, w, h int, target float64, rng *rand.Rand) ([]model.Vine, map[string]string) {
+	c.calls++
+	return (&LIFOFiller{}).FillGaps(existing, occupied, w, h, target, rng)
+}
+
+func TestFillerRegistryHasBuiltins(t *testing.T) {
+	for _, name := range []string{FillerLIFO, FillerGap} {
+		if _, err := GetFiller(name); err != nil {
+			t.Fatalf("expected built-in filler %q: %v", name, err)
+		}
+	}
+	if _, err := GetFiller("does-not-exist"); err == nil {
+		t.Fatal("expected error for unknown filler")
+	}
+}
+
+func TestFillersProduceDisjointValidVines(t *testing.T) {
+	for _, info := range ListFillers() {
+		t.Run(info.Name, func(t *testing.T) {
+			// A single vertical vine in a 6x6 grid leaves plenty of gaps
+			seed, err := model.NewVine("vine_1", []model.Point{{X: 2, Y: 5}, {X: 2, Y: 4}, {X: 2, Y: 3}}, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			occupied := map[string]string{"2,5": "vine_1", "2,4": "vine_1", "2,3": "vine_1"}
+
+			vines, fillerOcc := info.Factory().FillGaps([]model.Vine{seed}, occupied, 6, 6, 0.8, rand.New(rand.NewSource(7)))
+			if len(vines) == 0 {
+				t.Fatal("expected filler vines")
+			}
+			if len(occupied) != 3 {
+				t.Fatal("filler must not modify the input occupancy map")
+			}
+
+			seen := map[string]bool{}
+			for _, v := range vines {
+				if v.ID == "vine_1" {
+					t.Fatalf("filler reused existing vine ID")
+				}
+				if err := v.Validate(); err != nil {
+					t.Fatalf("invalid filler vine: %v", err)
+				}
+				for _, p := range v.OrderedPath {
+					key := fmt.Sprintf("%d,%d", p.X, p.Y)
+					if _, taken := occupied[key]; taken || seen[key] {
+						t.Fatalf("filler vine %s overlaps at %s", v.ID, key)
+					}
+					if fillerOcc[key] != v.ID {
+						t.Fatalf("occupancy for %s = %q, want %s", key, fillerOcc[key], v.ID)
+					}
+					seen[key] = true
+				}
+			}
+		})
+	}
+}
+
+func TestCenterOutPlacerUsesInjectedFiller(t *testing.T) {
+	filler := &countingFiller{}
+	placer := &CenterOutPlacer{Filler: filler}
+	cfg := config.GenerationConfig{GridWidth: 8, GridHeight: 8, VineCount: 3, MinCoverage: 1.0, Difficulty: "Seedling"}
+
+	if _, _, err := placer.PlaceVines(context.Background(), cfg, rand.New(rand.NewSource(1)), &config.GenerationStats{}); err != nil {
+		t.Fatalf("PlaceVines: %v", err)
+	}
+	if filler.calls != 1 {
+		t.Fatalf("expected injected filler to be called once, got %d", filler.calls)
+	}
+
+	cfg.FillerStrategy = "does-not-exist"
+	if _, _, err := (&CenterOutPlacer{}).PlaceVines(context.Background(), cfg, rand.New(rand.NewSource(1)), &config.GenerationStats{}); err == nil {
+		t.Fatal("expected unknown filler strategy to fail placement")
+	}
+}
@@ -0,0 +1,523 @@
+package strategies
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/utils"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+const (
+	// fullCoverageMaxRounds bounds the rip-up/re-place rounds spent on leftover cells.
+	fullCoverageMaxRounds = 60
+	// fullCoverageGrowthBudget bounds the backtracking search for a single vine body.
+	fullCoverageGrowthBudget = 500
+)
+
+// FullCoveragePlacer fills every grid cell with vines, so levels never need masks.
+//
+// It follows the pseudo-code in the generator package docs:
+//  1. Seed heads from the center outward, sweeping each ring top-down,
+//     bottom-up, left-right and right-left in round-robin order.
+//  2. Give every head a clear exit at placement time and grow its body with
+//     backtracking, preferring cells that are about to become isolated.
+//  3. Extend vines into leftover empty cells through their tail, or through
+//     their head when the cell lies directly on the head's exit path.
+//  4. If cells are still empty, rip up the vines around them and re-place,
+//     widening the rip-up area as rounds go by.
+//
+// New vines always have a clear exit against everything already on the board,
+// so clearing them first keeps the level solvable. Extensions are only kept
+// when the greedy solver still clears the board.
+type FullCoveragePlacer struct{}
+
+// coverageBoard tracks vines and cell ownership while the placer works.
+type coverageBoard struct {
+	w, h   int
+	vines  []model.Vine
+	grid   *common.Grid
+	owners map[int]string // cell index (y*w+x) -> owning vine ID
+	// Stencil gaps no vine may take. They stay off grid, which is also
+	// read for exits: heads may aim through a gap, which is empty in play
+	gaps    map[model.Point]bool
+	islands *utils.IslandDetector
+	balance *utils.DirBalancer
+	nextID  int
+}
+
+func newCoverageBoard(w, h int, stencil []model.Point) *coverageBoard {
+	grid := common.NewGrid(w, h)
+	gaps := make(map[model.Point]bool, len(stencil))
+	for _, p := range stencil {
+		gaps[p] = true
+	}
+	return &coverageBoard{
+		w: w, h: h, grid: grid, owners: make(map[int]string), gaps: gaps,
+		islands: utils.NewIslandDetectorFor(grid), nextID: 1,
+	}
+}
+
+// PlaceVines covers the whole grid or returns an error so the caller can retry with another seed.
+func (p *FullCoveragePlacer) PlaceVines(ctx context.Context, cfg config.GenerationConfig, rng *rand.Rand, stats *config.GenerationStats) ([]model.Vine, map[string]string, error) {
+	w, h := cfg.GridWidth, cfg.GridHeight
+	if w*h < 2 {
+		return nil, nil, fmt.Errorf("grid %dx%d is too small for full coverage", w, h)
+	}
+
+	b := newCoverageBoard(w, h, cfg.Stencil)
+	b.balance = headBalancer(cfg, nil)
+	minLen, maxLen := p.lengthRange(cfg)
+	spec, _ := config.SpecFor(cfg)
+
+	// Primary placement with difficulty-sized vines, then short vines for what is left
+	p.seedAndGrow(b, minLen, maxLen, spec.LengthDistribution, rng, stats)
+	p.seedAndGrow(b, 2, maxLen, config.LengthDistribution{}, rng, stats)
+
+	for round := 0; ; round++ {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		p.extendIntoGaps(b)
+		empty := b.emptyCells()
+		if len(empty) == 0 {
+			break
+		}
+		if round >= fullCoverageMaxRounds {
+			return nil, nil, fmt.Errorf("full coverage not reached: %d empty cells after %d rounds", len(empty), round)
+		}
+
+		if stats != nil {
+			stats.BacktracksAttempted++
+		}
+		common.Verbose("Full coverage round %d: %d empty cells, ripping up neighbors", round+1, len(empty))
+		b.ripUp(empty, 1+round/10)
+		p.seedAndGrow(b, 2, maxLen, config.LengthDistribution{}, rng, stats)
+	}
+
+	if !b.solvable() {
+		return nil, nil, fmt.Errorf("full coverage placement is not solvable")
+	}
+
+	common.Verbose("Full coverage reached with %d vines", len(b.vines))
+	return b.vines, b.occupancy(), nil
+}
+
+// lengthRange returns the vine length bounds for the configured difficulty.
+// A tier with a length distribution spans everything the curve may draw.
+func (p *FullCoveragePlacer) lengthRange(cfg config.GenerationConfig) (int, int) {
+	minLen, maxLen := 6, 10
+	if spec, ok := config.SpecFor(cfg); ok {
+		minLen, maxLen = spec.AvgLengthRange[0], spec.AvgLengthRange[1]
+		if dist := spec.LengthDistribution; dist.Enabled() {
+			minLen, maxLen = dist.Min, cfg.GridWidth+cfg.GridHeight
+			if dist.Max > 0 {
+				maxLen = dist.Max
+			}
+		}
+	}
+
+	// Cap length to prevent overly long vines on small grids
+	if limit := (cfg.GridWidth + cfg.GridHeight) / 2; maxLen > limit {
+		maxLen = limit
+	}
+	if maxLen < 2 {
+		maxLen = 2
+	}
+	if minLen > maxLen {
+		minLen = maxLen
+	}
+	if minLen < 2 {
+		minLen = 2
+	}
+	return minLen, maxLen
+}
+
+// seedAndGrow walks the seed queue and places a vine at every free cell that
+// accepts one. Target lengths are drawn from dist when it is enabled, else
+// uniformly from minLen to maxLen.
+func (p *FullCoveragePlacer) seedAndGrow(b *coverageBoard, minLen, maxLen int, dist config.LengthDistribution, rng *rand.Rand, stats *config.GenerationStats) {
+	for _, seed := range p.seedQueue(b, rng) {
+		if b.isOccupied(seed) {
+			continue
+		}
+		if stats != nil {
+			stats.PlacementAttempts++
+		}
+
+		targetLen := minLen
+		if dist.Enabled() {
+			targetLen = dist.Sample(rng, maxLen)
+		} else if maxLen > minLen {
+			targetLen += rng.Intn(maxLen - minLen + 1)
+		}
+		if vine, ok := p.placeVine(b, seed, minLen, targetLen, rng); ok {
+			b.add(vine)
+		}
+	}
+}
+
+// seedQueue orders free cells from the center outward. Each ring around the
+// center is swept in a different direction (top-down, bottom-up, left-right,
+// right-left) in round-robin order, starting from a randomly jittered center.
+func (p *FullCoveragePlacer) seedQueue(b *coverageBoard, rng *rand.Rand) []model.Point {
+	cx := b.w/2 + rng.Intn(3) - 1
+	cy := b.h/2 + rng.Intn(3) - 1
+	sweep := rng.Intn(4)
+
+	ring := func(pt model.Point) int {
+		return max(abs(pt.X-cx), abs(pt.Y-cy))
+	}
+
+	var queue []model.Point
+	for y := 0; y < b.h; y++ {
+		for x := 0; x < b.w; x++ {
+			if pt := (model.Point{X: x, Y: y}); !b.isOccupied(pt) {
+				queue = append(queue, pt)
+			}
+		}
+	}
+
+	sort.SliceStable(queue, func(i, j int) bool {
+		a, c := queue[i], queue[j]
+		if ra, rc := ring(a), ring(c); ra != rc {
+			return ra < rc
+		}
+		switch (sweep + ring(a)) % 4 {
+		case 0: // top-down
+			return a.Y > c.Y || (a.Y == c.Y && a.X < c.X)
+		case 1: // bottom-up
+			return a.Y < c.Y || (a.Y == c.Y && a.X < c.X)
+		case 2: // left-right
+			return a.X < c.X || (a.X == c.X && a.Y < c.Y)
+		default: // right-left
+			return a.X > c.X || (a.X == c.X && a.Y < c.Y)
+		}
+	})
+	return queue
+}
+
+// placeVine tries every clear exit direction for a head at seed and grows a body
+// of at least minLen cells. The direction toward the nearest edge, as ranked by
+// the head balancer, is tried first.
+func (p *FullCoveragePlacer) placeVine(b *coverageBoard, seed model.Point, minLen, targetLen int, rng *rand.Rand) (model.Vine, bool) {
+	dirs := append([]string(nil), common.AllDirections...)
+	rng.Shuffle(len(dirs), func(i, j int) { dirs[i], dirs[j] = dirs[j], dirs[i] })
+	preferred := b.balance.RankExits(seed, b.w, b.h)[0]
+	sort.SliceStable(dirs, func(i, j int) bool { return dirs[i] == preferred && dirs[j] != preferred })
+
+	// Among bodies long enough, one that strands no dead cell wins over a longer one that does
+	var best []model.Point
+	var bestDir string
+	bestStrands := 0
+	for _, dir := range dirs {
+		if !b.grid.IsExitPathClear(seed, dir) {
+			continue
+		}
+		neck, ok := b.neckFor(seed, dir)
+		if !ok {
+			continue
+		}
+
+		path := p.growBody(b, []model.Point{seed, neck}, exitPath(seed, dir, b.w, b.h), targetLen, rng)
+		strands := 0
+		if len(path) >= minLen {
+			strands = b.islands.WouldStrand(path)
+		}
+		switch {
+		case len(path) >= minLen && len(best) < minLen,
+			len(path) >= minLen && strands < bestStrands,
+			strands == bestStrands && len(path) > len(best):
+			best, bestDir, bestStrands = path, dir, strands
+		}
+		if len(best) >= targetLen && bestStrands == 0 {
+			break
+		}
+	}
+
+	if len(best) < minLen {
+		return model.Vine{}, false
+	}
+	vine, err := model.NewVine(model.VineID(b.nextID), best, bestDir)
+	if err != nil {
+		return model.Vine{}, false
+	}
+	return vine, true
+}
+
+// growBody extends path from its last cell toward targetLen using a bounded
+// depth-first search. When the target cannot be reached it backtracks and
+// returns the longest body it found.
+func (p *FullCoveragePlacer) growBody(b *coverageBoard, path []model.Point, forbidden map[model.Point]bool, targetLen int, rng *rand.Rand) []model.Point {
+	inPath := make(map[model.Point]bool, targetLen)
+	for _, pt := range path {
+		inPath[pt] = true
+	}
+
+	best := append([]model.Point(nil), path...)
+	budget := fullCoverageGrowthBudget
+
+	var dfs func() bool
+	dfs = func() bool {
+		if len(path) > len(best) {
+			best = append(best[:0], path...)
+		}
+		if len(path) >= targetLen {
+			return true
+		}
+		if budget--; budget <= 0 {
+			return false
+		}
+
+		for _, next := range p.growthCandidates(b, path[len(path)-1], inPath, forbidden, rng) {
+			path = append(path, next)
+			inPath[next] = true
+			if dfs() {
+				return true
+			}
+			// Revert to the last valid state and try the next candidate
+			delete(inPath, next)
+			path = path[:len(path)-1]
+		}
+		return false
+	}
+	dfs()
+
+	return best
+}
+
+// growthCandidates lists free neighbors of current, preferring cells with the
+// fewest free neighbors of their own so corners and pockets get filled before
+// they become isolated.
+func (p *FullCoveragePlacer) growthCandidates(b *coverageBoard, current model.Point, inPath, forbidden map[model.Point]bool, rng *rand.Rand) []model.Point {
+	free := func(pt model.Point) bool {
+		return b.inBounds(pt) && !b.isOccupied(pt) && !inPath[pt] && !forbidden[pt]
+	}
+
+	var candidates []model.Point
+	for _, n := range b.neighbors(current) {
+		if free(n) {
+			candidates = append(candidates, n)
+		}
+	}
+	rng.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+
+	degree := make(map[model.Point]int, len(candidates))
+	for _, c := range candidates {
+		for _, n := range b.neighbors(c) {
+			if free(n) {
+				degree[c]++
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return degree[candidates[i]] < degree[candidates[j]] })
+	return candidates
+}
+
+// extendIntoGaps grows existing vines into empty cells until no more progress
+// is possible. A vine can take an empty cell next to its tail (unless the
+// cell is on its own exit path), or the empty cell directly in front of its
+// head. Extensions that leave the board
+// unsolvable are reverted.
+func (p *FullCoveragePlacer) extendIntoGaps(b *coverageBoard) {
+	for progress := true; progress; {
+		progress = false
+		for _, cell := range b.emptyCells() {
+			if p.extendInto(b, cell) {
+				progress = true
+			}
+		}
+	}
+}
+
+func (p *FullCoveragePlacer) extendInto(b *coverageBoard, cell model.Point) bool {
+	for _, n := range b.neighbors(cell) {
+		idx := b.vineIndex(b.owners[b.grid.Index(n.X, n.Y)])
+		if idx < 0 {
+			continue
+		}
+		vine := b.vines[idx]
+		head, tail := vine.OrderedPath[0], vine.OrderedPath[len(vine.OrderedPath)-1]
+
+		var path []model.Point
+		switch {
+		case n == tail && !exitPath(head, vine.HeadDirection, b.w, b.h)[cell]:
+			// The body must never sit on its own exit path
+			path = append(append([]model.Point(nil), vine.OrderedPath...), cell)
+		case n == head && stepToward(head, vine.HeadDirection) == cell:
+			path = append([]model.Point{cell}, vine.OrderedPath...)
+		default:
+			continue
+		}
+
+		extended, err := model.NewVine(vine.ID, path, vine.HeadDirection)
+		if err != nil {
+			continue
+		}
+		extended.ColorIndex = vine.ColorIndex
+
+		b.replace(idx, extended)
+		if b.solvable() {
+			return true
+		}
+		b.replace(idx, vine)
+	}
+	return false
+}
+
+// add places vine on the board and reserves the next vine ID.
+func (b *coverageBoard) add(vine model.Vine) {
+	b.vines = append(b.vines, vine)
+	b.claim(vine)
+	b.balance.RecordVine(vine)
+	b.nextID++
+}
+
+// replace swaps the vine at idx for vine, updating cell ownership.
+func (b *coverageBoard) replace(idx int, vine model.Vine) {
+	b.release(b.vines[idx])
+	b.vines[idx] = vine
+	b.claim(vine)
+}
+
+// claim marks vine's cells occupied and owned by it.
+func (b *coverageBoard) claim(vine model.Vine) {
+	for _, pt := range vine.OrderedPath {
+		b.grid.Set(pt.X, pt.Y)
+		b.owners[b.grid.Index(pt.X, pt.Y)] = vine.ID
+	}
+}
+
+// release frees vine's cells.
+func (b *coverageBoard) release(vine model.Vine) {
+	for _, pt := range vine.OrderedPath {
+		b.grid.Unset(pt.X, pt.Y)
+		delete(b.owners, b.grid.Index(pt.X, pt.Y))
+	}
+}
+
+// occupancy returns the cell owners keyed like common.PointKey, the form
+// PlaceVines reports them in.
+func (b *coverageBoard) occupancy() map[string]string {
+	occupied := make(map[string]string, len(b.owners))
+	for i, owner := range b.owners {
+		occupied[common.PointKey(model.Point{X: i % b.w, Y: i / b.w})] = owner
+	}
+	return occupied
+}
+
+// ripUp removes every vine owning a cell within radius (Manhattan) of an empty cell.
+func (b *coverageBoard) ripUp(empty []model.Point, radius int) {
+	doomed := make(map[string]bool)
+	for _, e := range empty {
+		for dy := -radius; dy <= radius; dy++ {
+			for dx := -radius; dx <= radius; dx++ {
+				if abs(dx)+abs(dy) > radius {
+					continue
+				}
+				x, y := e.X+dx, e.Y+dy
+				if !b.grid.InBounds(x, y) {
+					continue
+				}
+				if id, ok := b.owners[b.grid.Index(x, y)]; ok {
+					doomed[id] = true
+				}
+			}
+		}
+	}
+
+	kept := b.vines[:0]
+	for _, v := range b.vines {
+		if !doomed[v.ID] {
+			kept = append(kept, v)
+			continue
+		}
+		b.release(v)
+	}
+	b.vines = kept
+
+	b.balance.Reset()
+	for _, v := range b.vines {
+		b.balance.RecordVine(v)
+	}
+}
+
+// solvable reports whether the current vines can all be cleared.
+func (b *coverageBoard) solvable() bool {
+	level := model.Level{GridSize: []int{b.w, b.h}, Vines: b.vines}
+	return common.NewSolver(&level).IsSolvableGreedy()
+}
+
+func (b *coverageBoard) emptyCells() []model.Point {
+	var empty []model.Point
+	for y := 0; y < b.h; y++ {
+		for x := 0; x < b.w; x++ {
+			if pt := (model.Point{X: x, Y: y}); !b.isOccupied(pt) {
+				empty = append(empty, pt)
+			}
+		}
+	}
+	return empty
+}
+
+func (b *coverageBoard) vineIndex(id string) int {
+	for i, v := range b.vines {
+		if v.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// neckFor returns the cell opposite the head direction, which must be free
+// for the head and neck to form a valid vine.
+func (b *coverageBoard) neckFor(head model.Point, headDir string) (model.Point, bool) {
+	neck := stepToward(head, common.OppositeDirection(headDir))
+	if !b.inBounds(neck) || b.isOccupied(neck) {
+		return model.Point{}, false
+	}
+	return neck, true
+}
+
+func (b *coverageBoard) neighbors(pt model.Point) []model.Point {
+	var out []model.Point
+	for _, dir := range common.AllDirections {
+		if n := stepToward(pt, dir); b.inBounds(n) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+func (b *coverageBoard) inBounds(pt model.Point) bool {
+	return pt.X >= 0 && pt.X < b.w && pt.Y >= 0 && pt.Y < b.h
+}
+
+func (b *coverageBoard) isOccupied(pt model.Point) bool {
+	return b.grid.Has(pt.X, pt.Y) || b.gaps[pt]
+}
+
+// exitPath returns the cells between pos and the grid edge in direction dir.
+func exitPath(pos model.Point, dir string, w, h int) map[model.Point]bool {
+	cells := make(map[model.Point]bool)
+	for pt := stepToward(pos, dir); pt.X >= 0 && pt.X < w && pt.Y >= 0 && pt.Y < h; pt = stepToward(pt, dir) {
+		cells[pt] = true
+	}
+	return cells
+}
+
+func stepToward(pt model.Point, dir string) model.Point {
+	dx, dy := common.DeltaForDirection(dir)
+	return model.Point{X: pt.X + dx, Y: pt.Y + dy}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,83 @@
+package strategies
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+func TestFullCoveragePlacerFillsEveryCell(t *testing.T) {
+	for _, difficulty := range []string{"Seedling", "Nurturing", "Transcendent"} {
+		grid := config.GridSizeRanges[difficulty]
+		w, h := (grid.MinW+grid.MaxW)/2, (grid.MinH+grid.MaxH)/2
+
+		for seed := int64(1); seed <= 10; seed++ {
+			cfg := config.GenerationConfig{GridWidth: w, GridHeight: h, Difficulty: difficulty, MinCoverage: 1.0}
+			vines, occupied, err := (&FullCoveragePlacer{}).PlaceVines(context.Background(), cfg, rand.New(rand.NewSource(seed)), &config.GenerationStats{})
+			if err != nil {
+				t.Fatalf("%s seed %d: %v", difficulty, seed, err)
+			}
+			if len(occupied) != w*h {
+				t.Fatalf("%s seed %d: covered %d/%d cells", difficulty, seed, len(occupied), w*h)
+			}
+
+			cells := 0
+			for _, v := range vines {
+				if err := v.Validate(); err != nil {
+					t.Fatalf("%s seed %d: %v", difficulty, seed, err)
+				}
+				cells += len(v.OrderedPath)
+			}
+			if cells != w*h {
+				t.Fatalf("%s seed %d: vines overlap (%d cells for %d grid cells)", difficulty, seed, cells, w*h)
+			}
+
+			level := model.Level{GridSize: []int{w, h}, Vines: vines}
+			if errs := validator.ValidateStructural(level); len(errs) > 0 {
+				t.Fatalf("%s seed %d: structural errors: %v", difficulty, seed, errs)
+			}
+			if !common.NewSolver(&level).IsSolvableGreedy() {
+				t.Fatalf("%s seed %d: level not solvable", difficulty, seed)
+			}
+		}
+	}
+}
+
+func TestFullCoveragePlacerSmallGrids(t *testing.T) {
+	for _, size := range [][2]int{{2, 1}, {3, 3}, {4, 5}} {
+		cfg := config.GenerationConfig{GridWidth: size[0], GridHeight: size[1], Difficulty: "Seedling"}
+		_, occupied, err := (&FullCoveragePlacer{}).PlaceVines(context.Background(), cfg, rand.New(rand.NewSource(3)), nil)
+		if err != nil {
+			t.Fatalf("%dx%d: %v", size[0], size[1], err)
+		}
+		if len(occupied) != size[0]*size[1] {
+			t.Fatalf("%dx%d: covered %d cells", size[0], size[1], len(occupied))
+		}
+	}
+
+	if _, _, err := (&FullCoveragePlacer{}).PlaceVines(context.Background(), config.GenerationConfig{GridWidth: 1, GridHeight: 1}, rand.New(rand.NewSource(1)), nil); err == nil {
+		t.Fatal("expected 1x1 grid to be rejected")
+	}
+}
+
+func TestFullCoveragePlacerDeterministic(t *testing.T) {
+	cfg := config.GenerationConfig{GridWidth: 10, GridHeight: 14, Difficulty: "Sprout"}
+	a, _, errA := (&FullCoveragePlacer{}).PlaceVines(context.Background(), cfg, rand.New(rand.NewSource(42)), nil)
+	b, _, errB := (&FullCoveragePlacer{}).PlaceVines(context.Background(), cfg, rand.New(rand.NewSource(42)), nil)
+	if errA != nil || errB != nil {
+		t.Fatalf("placement failed: %v / %v", errA, errB)
+	}
+	if len(a) != len(b) {
+		t.Fatalf("same seed produced %d and %d vines", len(a), len(b))
+	}
+	for i := range a {
+		if a[i].HeadDirection != b[i].HeadDirection || len(a[i].OrderedPath) != len(b[i].OrderedPath) || a[i].OrderedPath[0] != b[i].OrderedPath[0] {
+			t.Fatalf("same seed produced different vine %d", i)
+		}
+	}
+}
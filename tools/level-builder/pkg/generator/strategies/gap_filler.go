@@ -126,7 +126,7 @@ func (f *GapFiller) findEmptyCells(occupied map[string]string) []model.Point {
 // growMultiCellFiller attempts to grow a filler vine of target length.
 // Must have a clear exit path for the head to preserve LIFO solvability.
 func (f *GapFiller) growMultiCellFiller(head model.Point, targetLen int, occupied map[string]string, id int) (model.Vine, bool) {
-	vineID := fmt.Sprintf("vine_%d", id)
+	vineID := model.VineID(id)
 
 	// Check all valid exit directions for head
 	candidates := []string{}
@@ -214,11 +214,9 @@ func (f *GapFiller) growMultiCellFiller(head model.Point, targetLen int, occupie
 		}
 
 		if valid {
-			return model.Vine{
-				ID:            vineID,
-				HeadDirection: headDir,
-				OrderedPath:   path,
-			}, true
+			if vine, err := model.NewVine(vineID, path, headDir); err == nil {
+				return vine, true
+			}
 		}
 	}
 
@@ -226,7 +224,7 @@ func (f *GapFiller) growMultiCellFiller(head model.Point, targetLen int, occupie
 }
 
 func (f *GapFiller) tryCreateFiller(head model.Point, occupied map[string]string, id int) (model.Vine, bool) {
-	vineID := fmt.Sprintf("vine_%d", id)
+	vineID := model.VineID(id)
 
 	// Get available neighbors
 	neighbors := f.getFreeNeighbors(head, occupied)
@@ -235,17 +233,15 @@ func (f *GapFiller) tryCreateFiller(head model.Point, occupied map[string]string
 	})
 
 	for _, neck := range neighbors {
-		// Calculate potential vine
-		neckDir := common.DirectionFromPoints(head, neck)
-		headDir := common.OppositeDirection(neckDir)
+		// Calculate potential vine; head direction follows the head→neck geometry
+		vine, err := model.NewVine(vineID, []model.Point{head, neck}, "")
+		if err != nil {
+			continue
+		}
 
 		// Preference: Has clear exit path (LIFO safe)
-		if common.IsExitPathClear(head, headDir, f.w, f.h, occupied) {
-			return model.Vine{
-				ID:            vineID,
-				HeadDirection: headDir,
-				OrderedPath:   []model.Point{head, neck},
-			}, true
+		if common.IsExitPathClear(head, vine.HeadDirection, f.w, f.h, occupied) {
+			return vine, true
 		}
 	}
 
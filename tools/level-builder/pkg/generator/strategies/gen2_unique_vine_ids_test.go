@@ -1,6 +1,7 @@
 package strategies_test
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"testing"
@@ -21,7 +22,7 @@ func TestGeneratedLevelsHaveUniqueVineIDs(t *testing.T) {
 	for _, cfg := range tests {
 		tmpDir := t.TempDir()
 		cfg.OutputFile = filepath.Join(tmpDir, fmt.Sprintf("level_%d.json", cfg.LevelID))
-		level, _, err := generator.GenerateLevelLIFO(cfg)
+		level, _, err := generator.GenerateLevelLIFO(context.Background(), cfg)
 		if err != nil {
 			t.Fatalf("generation failed for seed %d: %v", cfg.Seed, err)
 		}
@@ -56,29 +56,22 @@ func WriteFailureDump(config config.GenerationConfig, seed int64, attempt int, m
 	jsonPath := filepath.Join(dumpDir, base+".json")
 	txtPath := filepath.Join(dumpDir, base+".txt")
 
-	// Prepare dump object
-	dump := map[string]interface{}{
-		"level_id": config.LevelID,
-		"grid":     []int{config.GridWidth, config.GridHeight},
-		"seed":     seed,
-		"attempt":  attempt,
-		"message":  message,
-		"coverage": calculateGridCoverage(config, occupied),
+	// Record the full config so the attempt can be replayed exactly
+	dumpConfig := config
+	dumpConfig.Seed = seed
+	dumpConfig.Randomize = false
+	dump := FailureDump{
+		LevelID:  config.LevelID,
+		Grid:     []int{config.GridWidth, config.GridHeight},
+		Seed:     seed,
+		Attempt:  attempt,
+		Message:  message,
+		Coverage: calculateGridCoverage(config, occupied),
+		Config:   &dumpConfig,
+		Vines:    vines,
+		Occupied: occupied,
 	}
 
-	// Vines
-	var simpleVines []map[string]interface{}
-	for _, v := range vines {
-		simple := map[string]interface{}{
-			"id":             v.ID,
-			"head_direction": v.HeadDirection,
-			"ordered_path":   v.OrderedPath,
-		}
-		simpleVines = append(simpleVines, simple)
-	}
-	dump["vines"] = simpleVines
-	dump["occupied"] = occupied
-
 	// Write JSON
 	f, err := os.Create(jsonPath)
 	if err == nil {
@@ -123,11 +116,9 @@ func convertVinesToModel(vines []model.Vine) []model.Vine {
 	for i, v := range vines {
 		path := make([]model.Point, len(v.OrderedPath))
 		copy(path, v.OrderedPath)
-		result[i] = model.Vine{
-			ID:            v.ID,
-			HeadDirection: v.HeadDirection,
-			OrderedPath:   path,
-		}
+		v.OrderedPath = path
+		v.ColorIndex = 0
+		result[i] = v
 	}
 	return result
 }
@@ -1,6 +1,7 @@
 package strategies
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 
@@ -10,100 +11,124 @@ import (
 )
 
 // info: This file adapts legacy generation functions to the new VinePlacementStrategy interface.
+// Every caller, including the legacy Generate path, places vines through these
+// adapters so fixes to the underlying algorithms land in one place.
 
-// StrategyLegacyTiling wraps the old TileGridIntoVines algorithm
-const StrategyLegacyTiling = "legacy-tiling"
+// defaultAnchorRatio is the share of clearable-first vines placed as anchors.
+const defaultAnchorRatio = 0.3
 
+// LegacyTilingStrategy wraps the old TileGridIntoVines algorithm.
 type LegacyTilingStrategy struct{}
 
-func (s *LegacyTilingStrategy) PlaceVines(cfg config.GenerationConfig, rng *rand.Rand, stats *config.GenerationStats) ([]model.Vine, map[string]string, error) {
-	spec, ok := config.DifficultySpecs[cfg.Difficulty]
-	if !ok {
-		// Fallback
-		spec = config.DifficultySpecs["Seedling"]
-	}
-	profile := utils.GetPresetProfile(cfg.Difficulty)
-	genCfg := utils.GetGeneratorConfigForDifficulty(cfg.Difficulty)
-	gridSize := []int{cfg.GridWidth, cfg.GridHeight}
+func (s *LegacyTilingStrategy) PlaceVines(ctx context.Context, cfg config.GenerationConfig, rng *rand.Rand, stats *config.GenerationStats) ([]model.Vine, map[string]string, error) {
+	spec, profile, genCfg, gridSize := legacyInputs(cfg)
 
+	// The legacy algorithms run to completion; honour ctx on either side of them
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
 	vines, _, err := TileGridIntoVines(gridSize, spec, profile, genCfg, rng)
 	if err != nil {
 		return nil, nil, err
 	}
-
-	occupied := make(map[string]string)
-	for _, v := range vines {
-		for _, p := range v.OrderedPath {
-			key := fmt.Sprintf("%d,%d", p.X, p.Y)
-			occupied[key] = v.ID
-		}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
 	}
-	return vines, occupied, nil
+	return vines, occupancyByVine(vines), nil
 }
 
-// StrategyLegacyClearable wraps the old ClearableFirstPlacement algorithm
-const StrategyLegacyClearable = "legacy-clearable"
+// LegacyClearableStrategy wraps the old ClearableFirstPlacement algorithm.
+type LegacyClearableStrategy struct {
+	// AnchorRatio is the share of vines placed as anchors; zero uses 0.3
+	AnchorRatio float64
+	// MinOccupancy overrides the coverage minimum (config.CoverageFor) when set
+	MinOccupancy float64
+}
 
-type LegacyClearableStrategy struct{}
+func (s *LegacyClearableStrategy) PlaceVines(ctx context.Context, cfg config.GenerationConfig, rng *rand.Rand, stats *config.GenerationStats) ([]model.Vine, map[string]string, error) {
+	spec, profile, genCfg, gridSize := legacyInputs(cfg)
 
-func (s *LegacyClearableStrategy) PlaceVines(cfg config.GenerationConfig, rng *rand.Rand, stats *config.GenerationStats) ([]model.Vine, map[string]string, error) {
-	spec, ok := config.DifficultySpecs[cfg.Difficulty]
-	if !ok {
-		// Fallback
-		spec = config.DifficultySpecs["Seedling"]
+	anchorRatio := s.AnchorRatio
+	if anchorRatio <= 0 {
+		anchorRatio = defaultAnchorRatio
 	}
-	profile := utils.GetPresetProfile(cfg.Difficulty)
-	genCfg := utils.GetGeneratorConfigForDifficulty(cfg.Difficulty)
-	gridSize := []int{cfg.GridWidth, cfg.GridHeight}
-
-	// Legacy ClearableFirstPlacement took a seed (int64).
-	// We can use cfg.Seed or just rng.Int63()
-	seed := rng.Int63()
-
-	// Anchor ratio - usually 0.3 in legacy code, but adaptive. Use default 0.3 for now.
-	anchorRatio := 0.3
-
-	vines, err := ClearableFirstPlacement(gridSize, spec, profile, genCfg, seed, anchorRatio, spec.MinGridOccupancy, true)
+	coverage, err := config.CoverageFor(cfg.Difficulty, cfg.Profile, cfg.MinCoverage)
 	if err != nil {
 		return nil, nil, err
 	}
+	occupancy := coverage.Minimum
+	if s.MinOccupancy > 0 {
+		occupancy = s.MinOccupancy
+	}
 
-	occupied := make(map[string]string)
-	for _, v := range vines {
-		for _, p := range v.OrderedPath {
-			key := fmt.Sprintf("%d,%d", p.X, p.Y)
-			occupied[key] = v.ID
-		}
+	// Legacy ClearableFirstPlacement took a seed (int64); draw it from rng
+	vines, err := ClearableFirstPlacement(ctx, gridSize, spec, profile, genCfg, rng.Int63(), anchorRatio, occupancy, true)
+	if err != nil {
+		return nil, nil, err
 	}
-	return vines, occupied, nil
+	return vines, occupancyByVine(vines), nil
 }
 
-// StrategyLegacySolverAware wraps the old SolverAwarePlacement algorithm
-const StrategyLegacySolverAware = "legacy-solver"
-
+// LegacySolverAwareStrategy wraps the old SolverAwarePlacement algorithm.
 type LegacySolverAwareStrategy struct{}
 
-func (s *LegacySolverAwareStrategy) PlaceVines(cfg config.GenerationConfig, rng *rand.Rand, stats *config.GenerationStats) ([]model.Vine, map[string]string, error) {
-	spec, ok := config.DifficultySpecs[cfg.Difficulty]
-	if !ok {
-		// Fallback
-		spec = config.DifficultySpecs["Seedling"]
-	}
-	profile := utils.GetPresetProfile(cfg.Difficulty)
-	genCfg := utils.GetGeneratorConfigForDifficulty(cfg.Difficulty)
-	gridSize := []int{cfg.GridWidth, cfg.GridHeight}
+func (s *LegacySolverAwareStrategy) PlaceVines(ctx context.Context, cfg config.GenerationConfig, rng *rand.Rand, stats *config.GenerationStats) ([]model.Vine, map[string]string, error) {
+	spec, profile, genCfg, gridSize := legacyInputs(cfg)
 
+	// The legacy algorithms run to completion; honour ctx on either side of them
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
 	vines, _, err := SolverAwarePlacement(gridSize, spec, profile, genCfg, rng)
 	if err != nil {
 		return nil, nil, err
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	return vines, occupancyByVine(vines), nil
+}
+
+// legacyInputs derives the legacy algorithms' arguments from cfg and its
+// generation profile, falling back to the Seedling spec for unknown tiers.
+func legacyInputs(cfg config.GenerationConfig) (config.DifficultySpec, config.VarietyProfile, config.GeneratorConfig, []int) {
+	spec, ok := config.SpecFor(cfg)
+	if !ok {
+		spec = config.DifficultySpecs["Seedling"]
+	}
+	profile := utils.GetPresetProfile(cfg.Difficulty)
+	if p, found := config.GenerationProfiles[cfg.Profile]; found && p.LengthMix != nil {
+		profile.LengthMix = p.LengthMix
+	}
+	genCfg := utils.GetGeneratorConfigForDifficulty(cfg.Difficulty)
+	return spec, profile, genCfg, []int{cfg.GridWidth, cfg.GridHeight}
+}
 
+// occupancyByVine maps every occupied "x,y" cell to the ID of its vine.
+func occupancyByVine(vines []model.Vine) map[string]string {
 	occupied := make(map[string]string)
 	for _, v := range vines {
 		for _, p := range v.OrderedPath {
-			key := fmt.Sprintf("%d,%d", p.X, p.Y)
-			occupied[key] = v.ID
+			occupied[fmt.Sprintf("%d,%d", p.X, p.Y)] = v.ID
+		}
+	}
+	return occupied
+}
+
+// EmptyCellMask returns a hide mask over the cells no vine covers, or nil when
+// the vines fill the grid.
+func EmptyCellMask(gridSize []int, vines []model.Vine) *model.Mask {
+	occupied := occupancyByVine(vines)
+	var emptyPoints []model.Point
+	for y := 0; y < gridSize[1]; y++ {
+		for x := 0; x < gridSize[0]; x++ {
+			if _, ok := occupied[fmt.Sprintf("%d,%d", x, y)]; !ok {
+				emptyPoints = append(emptyPoints, model.Point{X: x, Y: y})
+			}
 		}
 	}
-	return vines, occupied, nil
+	if len(emptyPoints) == 0 {
+		return nil
+	}
+	return &model.Mask{Mode: "hide", Points: emptyPoints}
 }
@@ -0,0 +1,62 @@
+package strategies
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// AssignLocks locks up to ratio*len(vines) placed vines until a number of
+// other vines have cleared and returns how many were locked. portals are the
+// level's portal pairs, which decide where exit rays lead.
+//
+// Locks are read off one greedy clear order: the vine cleared k-th gets a
+// lock of at most k, so that order still solves the level with every lock
+// in place. Candidates are limited to vines that could leave at the start,
+// where the lock is the only thing holding them back.
+func AssignLocks(vines []model.Vine, portals []model.Portal, w, h int, ratio float64, rng *rand.Rand) int {
+	want := int(math.Round(ratio * float64(len(vines))))
+	if want <= 0 {
+		return 0
+	}
+
+	level := model.Level{GridSize: []int{w, h}, Vines: vines, Portals: portals}
+	order, ok := common.NewSolver(&level).GreedyClearOrder()
+	if !ok {
+		return 0
+	}
+
+	grid := common.NewGrid(w, h)
+	for _, v := range vines {
+		grid.SetPath(v.OrderedPath)
+	}
+	free := func(p model.Point) bool { return !grid.Has(p.X, p.Y) }
+
+	var candidates []int
+	position := make(map[int]int, len(order))
+	for k, i := range order {
+		position[i] = k
+		if k == 0 || vines[i].LockedUntil > 0 {
+			continue
+		}
+		for _, head := range vines[i].Heads() {
+			if _, ok := level.ExitRay(head.OrderedPath[0], head.HeadDirection, free); ok {
+				candidates = append(candidates, i)
+				break
+			}
+		}
+	}
+
+	rng.Shuffle(len(candidates), func(a, b int) {
+		candidates[a], candidates[b] = candidates[b], candidates[a]
+	})
+	if len(candidates) > want {
+		candidates = candidates[:want]
+	}
+	for _, i := range candidates {
+		vines[i].LockedUntil = 1 + rng.Intn(position[i])
+	}
+	return len(candidates)
+}
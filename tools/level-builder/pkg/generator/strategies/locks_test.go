@@ -0,0 +1,63 @@
+package strategies
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+func TestAssignLocks(t *testing.T) {
+	mustVine := func(id string, path []model.Point) model.Vine {
+		v, err := model.NewVine(id, path, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	// 5x3 grid. "a", "b" and "c" can all leave at the start; "d" faces left
+	// into "b". "a" clears first in the greedy order, so only "b" and "c"
+	// can take a lock.
+	newVines := func() []model.Vine {
+		return []model.Vine{
+			mustVine("a", []model.Point{{X: 0, Y: 1}, {X: 0, Y: 0}}),
+			mustVine("b", []model.Point{{X: 1, Y: 1}, {X: 1, Y: 0}}),
+			mustVine("c", []model.Point{{X: 2, Y: 2}, {X: 3, Y: 2}}),
+			mustVine("d", []model.Point{{X: 3, Y: 1}, {X: 4, Y: 1}}),
+		}
+	}
+
+	if n := AssignLocks(newVines(), nil, 5, 3, 0, rand.New(rand.NewSource(1))); n != 0 {
+		t.Errorf("ratio 0 locked %d vines", n)
+	}
+
+	for seed := int64(1); seed <= 20; seed++ {
+		vines := newVines()
+		got := AssignLocks(vines, nil, 5, 3, 1.0, rand.New(rand.NewSource(seed)))
+		if got == 0 || got > 2 {
+			t.Fatalf("seed %d: locked %d vines, want 1-2", seed, got)
+		}
+		locked := 0
+		for _, v := range vines {
+			if v.LockedUntil > 0 {
+				locked++
+			}
+		}
+		if vines[0].LockedUntil > 0 || vines[3].LockedUntil > 0 {
+			t.Errorf("seed %d: only b and c should be locked: %+v", seed, vines)
+		}
+		if locked != got {
+			t.Errorf("seed %d: reported %d locks, found %d", seed, got, locked)
+		}
+
+		level := model.Level{GridSize: []int{5, 3}, Vines: vines}
+		if !common.NewSolver(&level).IsSolvableGreedy() {
+			t.Errorf("seed %d: locks left the level unsolvable: %+v", seed, vines)
+		}
+		if errs := validator.ValidateStructural(level); len(errs) > 0 {
+			t.Errorf("seed %d: locks left the level invalid: %v", seed, errs)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package strategies
+
+import (
+	"fmt"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+// MaskMinimizer grows placed vines into cells that would otherwise be masked,
+// following step 5 of the full-coverage pseudo-code in the generator docs.
+type MaskMinimizer struct {
+	w, h int
+}
+
+// NewMaskMinimizer creates a new MaskMinimizer.
+func NewMaskMinimizer(w, h int) *MaskMinimizer {
+	return &MaskMinimizer{w: w, h: h}
+}
+
+// Minimize absorbs empty cells into adjacent vines and returns how many it
+// absorbed. A cell is absorbed by a vine whose tail touches it (the tail grows
+// into it) or whose head faces it (the head moves into it, keeping its
+// direction). Each absorption is kept only if the level stays structurally
+// valid and greedily solvable, so nothing changes when the vines are not
+// solvable to begin with. vines and occupied are updated in place; run it
+// before tail heads, portals and locks are assigned.
+func (m *MaskMinimizer) Minimize(vines []model.Vine, occupied map[string]string) int {
+	level := model.Level{GridSize: []int{m.w, m.h}, Vines: vines}
+	if !common.NewSolver(&level).IsSolvableGreedy() {
+		return 0
+	}
+
+	absorbed := 0
+	for changed := true; changed; {
+		changed = false
+		for y := 0; y < m.h; y++ {
+			for x := 0; x < m.w; x++ {
+				key := fmt.Sprintf("%d,%d", x, y)
+				if _, occ := occupied[key]; occ {
+					continue
+				}
+				if i, ok := m.absorb(&level, model.Point{X: x, Y: y}); ok {
+					occupied[key] = vines[i].ID
+					absorbed++
+					changed = true
+				}
+			}
+		}
+	}
+	return absorbed
+}
+
+// absorb grows the first vine that can take c without breaking the level and
+// returns its index.
+func (m *MaskMinimizer) absorb(level *model.Level, c model.Point) (int, bool) {
+	for i, v := range level.Vines {
+		if len(v.OrderedPath) < 2 {
+			continue
+		}
+		var candidates [][]model.Point
+		if tail := v.OrderedPath[len(v.OrderedPath)-1]; touches(tail, c) {
+			candidates = append(candidates, append(append([]model.Point(nil), v.OrderedPath...), c))
+		}
+		if next, inGrid, _ := level.NextCell(v.OrderedPath[0], v.HeadDirection); inGrid && next == c {
+			candidates = append(candidates, append([]model.Point{c}, v.OrderedPath...))
+		}
+
+		for _, path := range candidates {
+			level.Vines[i].OrderedPath = path
+			if len(validator.ValidateStructural(*level)) == 0 && common.NewSolver(level).IsSolvableGreedy() {
+				return i, true
+			}
+			level.Vines[i] = v
+		}
+	}
+	return 0, false
+}
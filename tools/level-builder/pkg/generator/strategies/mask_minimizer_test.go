@@ -0,0 +1,61 @@
+package strategies
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+func TestMaskMinimizer(t *testing.T) {
+	mustVine := func(id string, path []model.Point) model.Vine {
+		v, err := model.NewVine(id, path, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	occupiedBy := func(vines []model.Vine) map[string]string {
+		occ := make(map[string]string)
+		for _, v := range vines {
+			for _, p := range v.OrderedPath {
+				occ[fmt.Sprintf("%d,%d", p.X, p.Y)] = v.ID
+			}
+		}
+		return occ
+	}
+
+	// Each row has a free cell behind the tail and one in front of the head
+	vines := []model.Vine{
+		mustVine("a", []model.Point{{X: 2, Y: 0}, {X: 1, Y: 0}}),
+		mustVine("b", []model.Point{{X: 2, Y: 1}, {X: 1, Y: 1}}),
+	}
+	occupied := occupiedBy(vines)
+	if n := NewMaskMinimizer(4, 2).Minimize(vines, occupied); n != 4 {
+		t.Fatalf("absorbed %d cells, want 4", n)
+	}
+	if len(occupied) != 8 {
+		t.Errorf("occupied %d of 8 cells", len(occupied))
+	}
+	level := model.Level{GridSize: []int{4, 2}, Vines: vines}
+	if errs := validator.ValidateStructural(level); len(errs) > 0 {
+		t.Errorf("minimized level invalid: %v", errs)
+	}
+	if !common.NewSolver(&level).IsSolvableGreedy() {
+		t.Error("minimized level not solvable")
+	}
+	if head := vines[0].OrderedPath[0]; head != (model.Point{X: 3, Y: 0}) || vines[0].HeadDirection != "right" {
+		t.Errorf("vine a head = %v heading %s, want (3,0) heading right", head, vines[0].HeadDirection)
+	}
+
+	// Vines that block each other are left alone
+	stuck := []model.Vine{
+		mustVine("a", []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}),
+		mustVine("b", []model.Point{{X: 2, Y: 0}, {X: 3, Y: 0}}),
+	}
+	if n := NewMaskMinimizer(4, 2).Minimize(stuck, occupiedBy(stuck)); n != 0 {
+		t.Errorf("absorbed %d cells into an unsolvable level", n)
+	}
+}
@@ -0,0 +1,69 @@
+package strategies
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// AssignTailHeads turns up to ratio*len(vines) placed vines into multi-head
+// vines and returns how many were converted.
+//
+// A second head only adds a way out, so solvability is preserved. To keep it
+// from trivializing the vine, candidates are limited to vines whose tail exit
+// ray is blocked by another vine and not by the vine itself: the tail becomes
+// an alternative route the player has to open, not a free exit.
+func AssignTailHeads(vines []model.Vine, w, h int, ratio float64, rng *rand.Rand) int {
+	want := int(math.Round(ratio * float64(len(vines))))
+	if want <= 0 {
+		return 0
+	}
+
+	// Cell index (y*w+x) -> owning vine ID
+	owners := make(map[int]string)
+	for _, v := range vines {
+		for _, p := range v.OrderedPath {
+			owners[p.Y*w+p.X] = v.ID
+		}
+	}
+
+	var candidates []int
+	for i, v := range vines {
+		if v.IsMultiHead() || len(v.OrderedPath) < 2 {
+			continue
+		}
+		withTail, err := v.WithTailHead("")
+		if err != nil {
+			continue
+		}
+		if tailRayBlockedByOthers(withTail.Reversed(), w, h, owners) {
+			candidates = append(candidates, i)
+		}
+	}
+
+	rng.Shuffle(len(candidates), func(a, b int) {
+		candidates[a], candidates[b] = candidates[b], candidates[a]
+	})
+	if len(candidates) > want {
+		candidates = candidates[:want]
+	}
+	for _, i := range candidates {
+		vines[i], _ = vines[i].WithTailHead("")
+	}
+	return len(candidates)
+}
+
+// tailRayBlockedByOthers reports whether the exit ray of v's leading head
+// (the tail of the original vine) meets another vine before any cell of v.
+func tailRayBlockedByOthers(v model.Vine, w, h int, owners map[int]string) bool {
+	dx, dy := common.DeltaForDirection(v.HeadDirection)
+	head := v.OrderedPath[0]
+	for x, y := head.X+dx, head.Y+dy; x >= 0 && x < w && y >= 0 && y < h; x, y = x+dx, y+dy {
+		if owner, ok := owners[y*w+x]; ok {
+			return owner != v.ID
+		}
+	}
+	return false
+}
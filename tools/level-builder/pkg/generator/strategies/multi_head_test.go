@@ -0,0 +1,51 @@
+package strategies
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func TestAssignTailHeads(t *testing.T) {
+	mustVine := func(id string, path []model.Point) model.Vine {
+		v, err := model.NewVine(id, path, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	// 4x3 grid. Only "c" has a tail ray blocked by another vine: its tail at
+	// (1,1) faces left into "b". The tails of "a" and "b" face off the grid.
+	vines := []model.Vine{
+		mustVine("a", []model.Point{{X: 1, Y: 0}, {X: 2, Y: 0}, {X: 3, Y: 0}}),
+		mustVine("b", []model.Point{{X: 0, Y: 2}, {X: 0, Y: 1}, {X: 0, Y: 0}}),
+		mustVine("c", []model.Point{{X: 2, Y: 1}, {X: 1, Y: 1}}),
+	}
+
+	if n := AssignTailHeads(vines, 4, 3, 0, rand.New(rand.NewSource(1))); n != 0 {
+		t.Errorf("ratio 0 converted %d vines", n)
+	}
+
+	got := AssignTailHeads(vines, 4, 3, 1.0, rand.New(rand.NewSource(1)))
+	if got != 1 {
+		t.Fatalf("converted %d vines, want 1", got)
+	}
+	if vines[0].IsMultiHead() || vines[1].IsMultiHead() {
+		t.Error("vines with a free tail exit should stay single-headed")
+	}
+	if vines[2].TailDirection != "left" {
+		t.Errorf("c tail direction = %q, want left", vines[2].TailDirection)
+	}
+	for _, v := range vines {
+		if err := v.Validate(); err != nil {
+			t.Errorf("invalid vine after assignment: %v", err)
+		}
+	}
+
+	level := model.Level{GridSize: []int{4, 3}, Vines: vines}
+	if !common.NewSolver(&level).IsSolvableGreedy() {
+		t.Error("tail heads must keep the level solvable")
+	}
+}
@@ -0,0 +1,172 @@
+package strategies
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/metrics"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/utils"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// PlacementOptimizer objective weights. Coverage, aesthetics and closeness to
+// the tier's blocking depth target are each scored 0-1.
+const (
+	optimizeCoverageWeight   = 0.4
+	optimizeAestheticsWeight = 0.3
+	optimizeDifficultyWeight = 0.3
+)
+
+// Annealing temperatures, in objective units, at the first and the last
+// iteration. At the start a move costing 0.02 is accepted about one time in
+// three; by the end only improvements are.
+const (
+	annealStartTemp = 0.02
+	annealEndTemp   = 0.0005
+)
+
+// PlacementOptimizer improves a finished placement by bounded simulated
+// annealing over vine positions. It jointly scores coverage, the
+// metrics.AestheticsAnalyzer score and how close the deepest blocker chain is
+// to DifficultySpec.BlockingDepthTarget. Each iteration proposes one move on
+// a random vine: reverse it, regrow it elsewhere, grow its tail into an empty
+// cell or trim its tail. A move that would leave the vine blocking its own
+// exit or close a blocking cycle is never made, so every state visited is
+// solvable. Worse states are accepted with a
+// probability that falls as the temperature cools, and the best state seen
+// is kept.
+type PlacementOptimizer struct {
+	w, h   int
+	target int // blocking depth aimed for
+	maxLen int // longest vine tail growth may make; 0 means no limit
+	rng    *rand.Rand
+
+	// Stencil lists decorative gap cells no move may grow into.
+	Stencil []model.Point
+}
+
+// NewPlacementOptimizer creates a PlacementOptimizer for a w×h grid working
+// toward spec's blocking depth target and growing vines to at most its
+// longest average length.
+func NewPlacementOptimizer(w, h int, spec config.DifficultySpec, rng *rand.Rand) *PlacementOptimizer {
+	return &PlacementOptimizer{w: w, h: h, target: spec.BlockingDepthTarget(), maxLen: spec.AvgLengthRange[1], rng: rng}
+}
+
+// OptimizeResult reports what an optimization run did.
+type OptimizeResult struct {
+	Accepted int     // moves accepted, better or worse
+	Start    float64 // objective (0-1) of the placement passed in
+	Best     float64 // objective of the placement returned
+}
+
+// Optimize runs iterations annealing moves over vines and leaves the best
+// placement seen in vines. Vines keep their IDs and order. Nothing changes
+// when the vines are not solvable to begin with; run it before tail heads,
+// portals and locks are assigned.
+func (o *PlacementOptimizer) Optimize(vines []model.Vine, iterations int) OptimizeResult {
+	solver := utils.NewIncrementalSolver(o.w, o.h)
+	for _, v := range vines {
+		if !solver.CanPlace(v) {
+			return OptimizeResult{}
+		}
+		solver.Place(v)
+	}
+
+	occupied := make(map[string]bool)
+	for _, v := range vines {
+		for _, p := range v.OrderedPath {
+			occupied[common.PointKey(p)] = true
+		}
+	}
+	reserveStencilCells(occupied, o.Stencil)
+	current := o.objective(vines)
+	result := OptimizeResult{Start: current, Best: current}
+	best := append([]model.Vine(nil), vines...)
+	if len(vines) == 0 {
+		return result
+	}
+
+	for k := 0; k < iterations; k++ {
+		temp := annealStartTemp * math.Pow(annealEndTemp/annealStartTemp, float64(k)/float64(iterations))
+		i := o.rng.Intn(len(vines))
+		old := vines[i]
+		moved, ok := o.move(old, occupied)
+		if !ok || selfBlocks(moved, o.w, o.h) || !solver.CanPlace(moved) {
+			continue
+		}
+
+		vines[i] = moved
+		next := o.objective(vines)
+		if delta := next - current; delta < 0 && o.rng.Float64() >= math.Exp(delta/temp) {
+			vines[i] = old
+			continue
+		}
+		for _, p := range old.OrderedPath {
+			delete(occupied, common.PointKey(p))
+		}
+		for _, p := range moved.OrderedPath {
+			occupied[common.PointKey(p)] = true
+		}
+		solver.Place(moved)
+		current = next
+		result.Accepted++
+		if current > result.Best {
+			result.Best = current
+			copy(best, vines)
+		}
+	}
+	copy(vines, best)
+	return result
+}
+
+// move proposes one random change to v: reverse it, regrow it, grow its tail
+// into an empty neighbouring cell or trim its tail.
+func (o *PlacementOptimizer) move(v model.Vine, occupied map[string]bool) (model.Vine, bool) {
+	path := v.OrderedPath
+	var next []model.Point
+	switch o.rng.Intn(4) {
+	case 0:
+		next = v.Reversed().OrderedPath
+	case 1:
+		grown := regrowVine(v, occupied, o.w, o.h, 1, o.rng)
+		if len(grown) == 0 {
+			return model.Vine{}, false
+		}
+		return grown[0], true
+	case 2:
+		if o.maxLen > 0 && len(path) >= o.maxLen {
+			return model.Vine{}, false
+		}
+		free := getUnoccupiedNeighbors(path[len(path)-1], o.w, o.h, occupied)
+		if len(free) == 0 {
+			return model.Vine{}, false
+		}
+		next = append(append([]model.Point(nil), path...), free[o.rng.Intn(len(free))])
+	default:
+		if len(path) <= 2 {
+			return model.Vine{}, false
+		}
+		next = append([]model.Point(nil), path[:len(path)-1]...)
+	}
+	moved, err := model.NewVine(v.ID, next, "")
+	if err != nil {
+		return model.Vine{}, false
+	}
+	moved.ColorIndex, moved.LockedUntil = v.ColorIndex, v.LockedUntil
+	return moved, true
+}
+
+// objective scores a placement 0-1; higher is better.
+func (o *PlacementOptimizer) objective(vines []model.Vine) float64 {
+	cells := 0
+	for _, v := range vines {
+		cells += len(v.OrderedPath)
+	}
+	coverage := float64(cells) / float64(o.w*o.h)
+	aesthetics := metrics.AestheticsAnalyzer{}.Analyze(model.Level{GridSize: []int{o.w, o.h}, Vines: vines}).Score / 100
+	depth := scoreBlocking(vines).max
+	difficulty := 1 - math.Min(1, math.Abs(float64(depth-o.target))/float64(max(o.target, 1)))
+	return optimizeCoverageWeight*coverage + optimizeAestheticsWeight*aesthetics + optimizeDifficultyWeight*difficulty
+}
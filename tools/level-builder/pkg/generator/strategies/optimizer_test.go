@@ -0,0 +1,61 @@
+package strategies
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+func TestPlacementOptimizerKeepsLevelsSolvable(t *testing.T) {
+	spec := config.DifficultySpecs["Sprout"]
+	gridSize := []int{9, 12}
+
+	for seed := int64(1); seed <= 3; seed++ {
+		vines, err := ClearableFirstPlacement(context.Background(), gridSize, spec, config.VarietyProfile{}, config.GeneratorConfig{}, seed, 0.3, 0.9, true)
+		if err != nil {
+			t.Fatalf("seed %d: placement failed: %v", seed, err)
+		}
+		ids := make([]string, len(vines))
+		for i, v := range vines {
+			ids[i] = v.ID
+		}
+
+		res := NewPlacementOptimizer(gridSize[0], gridSize[1], spec, rand.New(rand.NewSource(seed))).Optimize(vines, 300)
+		if res.Best < res.Start {
+			t.Errorf("seed %d: objective fell %.3f -> %.3f", seed, res.Start, res.Best)
+		}
+		t.Logf("seed %d: objective %.3f -> %.3f, %d moves accepted", seed, res.Start, res.Best, res.Accepted)
+
+		for i, v := range vines {
+			if v.ID != ids[i] {
+				t.Errorf("seed %d: vine %d is %s, want %s", seed, i, v.ID, ids[i])
+			}
+		}
+		level := model.Level{GridSize: gridSize, Vines: vines}
+		if errs := validator.ValidateStructural(level); len(errs) > 0 {
+			t.Errorf("seed %d: optimized level invalid: %v", seed, errs)
+		}
+		if !common.NewSolver(&level).IsSolvableGreedy() {
+			t.Errorf("seed %d: optimized level not solvable", seed)
+		}
+	}
+}
+
+func TestPlacementOptimizerLeavesUnsolvableAlone(t *testing.T) {
+	// Two vines facing each other wait on each other
+	a, _ := model.NewVine("a", []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}, "")
+	b, _ := model.NewVine("b", []model.Point{{X: 2, Y: 0}, {X: 3, Y: 0}}, "")
+	vines := []model.Vine{a, b}
+	spec := config.DifficultySpecs["Seedling"]
+	if res := NewPlacementOptimizer(4, 2, spec, rand.New(rand.NewSource(1))).Optimize(vines, 50); res.Accepted != 0 {
+		t.Errorf("Optimize accepted %d moves on an unsolvable placement", res.Accepted)
+	}
+	if vines[0].HeadDirection != "right" || vines[1].HeadDirection != "left" {
+		t.Error("unsolvable placement was changed")
+	}
+}
@@ -1,6 +1,7 @@
 package strategies
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"math/rand"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/utils"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
 )
 
@@ -15,13 +17,14 @@ import (
 type CircuitBoardPlacer struct{}
 
 // PlaceVines places vines with circuit-board-like winding patterns
-func (p *CircuitBoardPlacer) PlaceVines(config config.GenerationConfig, rng *rand.Rand, stats *config.GenerationStats) ([]model.Vine, map[string]string, error) {
+func (p *CircuitBoardPlacer) PlaceVines(ctx context.Context, config config.GenerationConfig, rng *rand.Rand, stats *config.GenerationStats) ([]model.Vine, map[string]string, error) {
 	w, h := config.GridWidth, config.GridHeight
 	totalCells := w * h
 	targetCells := int(float64(totalCells) * config.MinCoverage) // Use configurable coverage target
 
 	occupied := make(map[string]string)
 	vines := make([]model.Vine, 0, config.VineCount)
+	balance := headBalancer(config, nil)
 
 	// Calculate target lengths for each vine
 	totalLength := 0
@@ -34,11 +37,14 @@ func (p *CircuitBoardPlacer) PlaceVines(config config.GenerationConfig, rng *ran
 
 	// Place vines one by one
 	for i, targetLen := range lengths {
-		vineID := fmt.Sprintf("v%d", i+1)
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		vineID := model.VineID(i + 1)
 
 		// Try to place vine with circuit-board growth
 		vine, newOccupied, err := p.growCircuitVine(
-			vineID, targetLen, w, h, occupied, config, rng,
+			vineID, targetLen, w, h, occupied, config, balance, rng,
 		)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to place vine %s: %w", vineID, err)
@@ -46,6 +52,7 @@ func (p *CircuitBoardPlacer) PlaceVines(config config.GenerationConfig, rng *ran
 
 		// Add to collections
 		vines = append(vines, vine)
+		balance.RecordVine(vine)
 		for k, v := range newOccupied {
 			occupied[k] = v
 		}
@@ -117,33 +124,60 @@ func (p *CircuitBoardPlacer) calculateVineLengths(config config.GenerationConfig
 	return lengths
 }
 
-// growCircuitVine grows a single vine with circuit-board aesthetics
+// growCircuitVine grows a single vine with circuit-board aesthetics. The neck
+// fixes the head direction, so it is only chosen among the neighbors whose
+// head direction balance weighs highest.
 func (p *CircuitBoardPlacer) growCircuitVine(
 	vineID string,
 	targetLen int,
 	w, h int,
 	occupied map[string]string,
 	config config.GenerationConfig,
+	balance *utils.DirBalancer,
+	rng *rand.Rand,
+) (model.Vine, map[string]string, error) {
+	// Regrow from a new seed while the vine would strand dead cells
+	placed := common.GridFromOccupancy(w, h, occupied)
+	guard := strandGuard{islands: utils.NewIslandDetectorFor(placed)}
+	for {
+		vine, localOccupied, err := p.growCircuitPath(vineID, targetLen, w, h, placed.Clone(), balance, rng)
+		if err != nil || !guard.reject(vine.OrderedPath) {
+			return vine, localOccupied, err
+		}
+	}
+}
+
+// growCircuitPath grows one circuit-board vine on grid, which it marks.
+func (p *CircuitBoardPlacer) growCircuitPath(
+	vineID string,
+	targetLen int,
+	w, h int,
+	grid *common.Grid,
+	balance *utils.DirBalancer,
 	rng *rand.Rand,
 ) (model.Vine, map[string]string, error) {
 	// Choose starting position biased toward edges (circuit board style)
-	seed := p.chooseCircuitSeed(w, h, occupied, rng)
+	seed := p.chooseCircuitSeed(grid, rng)
 
 	// Start the vine
 	path := []model.Point{seed}
 	localOccupied := make(map[string]string)
 	localOccupied[fmt.Sprintf("%d,%d", seed.X, seed.Y)] = vineID
+	grid.Set(seed.X, seed.Y)
 
 	// Grow the vine with circuit-board logic
 	for len(path) < targetLen {
 		current := path[len(path)-1]
 
 		// Get available neighbors
-		neighbors := p.getAvailableNeighbors(current, w, h, occupied, localOccupied)
+		neighbors := p.getAvailableNeighbors(current, grid)
 		if len(neighbors) == 0 {
 			// Stuck - this is normal for circuit boards, just return what we have
 			break
 		}
+		if len(path) == 1 {
+			neighbors = p.balancedNecks(current, neighbors, balance)
+		}
 
 		// Choose next segment with circuit-board preferences
 		next := p.chooseCircuitSegment(current, path, neighbors, w, h, rng)
@@ -151,6 +185,7 @@ func (p *CircuitBoardPlacer) growCircuitVine(
 		// Add to path
 		path = append(path, next)
 		localOccupied[fmt.Sprintf("%d,%d", next.X, next.Y)] = vineID
+		grid.Set(next.X, next.Y)
 	}
 
 	// Validate minimum length
@@ -158,64 +193,60 @@ func (p *CircuitBoardPlacer) growCircuitVine(
 		return model.Vine{}, nil, fmt.Errorf("vine too short: %d segments", len(path))
 	}
 
-	// Determine final head direction based on first two segments
-	headDirection := p.calculateHeadDirection(path)
-
-	vine := model.Vine{
-		ID:            vineID,
-		HeadDirection: headDirection,
-		OrderedPath:   path,
+	// NewVine derives the head direction from the first two segments
+	vine, err := model.NewVine(vineID, path, "")
+	if err != nil {
+		return model.Vine{}, nil, err
 	}
 
 	return vine, localOccupied, nil
 }
 
+// balancedNecks keeps the neck candidates whose head direction (from the neck
+// back to head) balance weighs highest.
+func (p *CircuitBoardPlacer) balancedNecks(head model.Point, necks []model.Point, balance *utils.DirBalancer) []model.Point {
+	best := 0.0
+	var kept []model.Point
+	for _, neck := range necks {
+		weight := balance.Weight(p.getDirection(neck, head))
+		switch {
+		case weight > best:
+			best, kept = weight, []model.Point{neck}
+		case weight == best:
+			kept = append(kept, neck)
+		}
+	}
+	return kept
+}
+
 // chooseCircuitSeed chooses a starting position biased toward grid edges
-func (p *CircuitBoardPlacer) chooseCircuitSeed(w, h int, occupied map[string]string, rng *rand.Rand) model.Point {
+func (p *CircuitBoardPlacer) chooseCircuitSeed(grid *common.Grid, rng *rand.Rand) model.Point {
+	w, h := grid.Width(), grid.Height()
+
 	// Try edge positions first (circuit board style)
 	edgeCandidates := []model.Point{}
 
-	// Helper to check if a position has at least one unoccupied neighbor
-	hasUnoccupiedNeighbor := func(x, y int) bool {
-		deltas := []model.Point{
-			{X: 0, Y: -1}, {X: 0, Y: 1}, {X: -1, Y: 0}, {X: 1, Y: 0},
-		}
-		for _, d := range deltas {
-			nx, ny := x+d.X, y+d.Y
-			if nx >= 0 && nx < w && ny >= 0 && ny < h {
-				_, occupied := occupied[fmt.Sprintf("%d,%d", nx, ny)]
-				if !occupied {
-					return true
-				}
-			}
-		}
-		return false
+	// Helper to check if a position is free and has at least one unoccupied neighbor
+	isCandidate := func(x, y int) bool {
+		return !grid.Has(x, y) && grid.CountFreeNeighbors(x, y) > 0
 	}
 
 	// Top and bottom edges
 	for x := 0; x < w; x++ {
-		key0 := fmt.Sprintf("%d,%d", x, 0)
-		_, occupied0 := occupied[key0]
-		if !occupied0 && hasUnoccupiedNeighbor(x, 0) {
+		if isCandidate(x, 0) {
 			edgeCandidates = append(edgeCandidates, model.Point{X: x, Y: 0})
 		}
-		keyH := fmt.Sprintf("%d,%d", x, h-1)
-		_, occupiedH := occupied[keyH]
-		if !occupiedH && hasUnoccupiedNeighbor(x, h-1) {
+		if isCandidate(x, h-1) {
 			edgeCandidates = append(edgeCandidates, model.Point{X: x, Y: h - 1})
 		}
 	}
 
 	// Left and right edges (excluding corners already added)
 	for y := 1; y < h-1; y++ {
-		keyL := fmt.Sprintf("%d,%d", 0, y)
-		_, occupiedL := occupied[keyL]
-		if !occupiedL && hasUnoccupiedNeighbor(0, y) {
+		if isCandidate(0, y) {
 			edgeCandidates = append(edgeCandidates, model.Point{X: 0, Y: y})
 		}
-		keyR := fmt.Sprintf("%d,%d", w-1, y)
-		_, occupiedR := occupied[keyR]
-		if !occupiedR && hasUnoccupiedNeighbor(w-1, y) {
+		if isCandidate(w-1, y) {
 			edgeCandidates = append(edgeCandidates, model.Point{X: w - 1, Y: y})
 		}
 	}
@@ -228,9 +259,7 @@ func (p *CircuitBoardPlacer) chooseCircuitSeed(w, h int, occupied map[string]str
 	// Fallback: any empty cell with unoccupied neighbors
 	for y := 0; y < h; y++ {
 		for x := 0; x < w; x++ {
-			key := fmt.Sprintf("%d,%d", x, y)
-			_, isOccupied := occupied[key]
-			if !isOccupied && hasUnoccupiedNeighbor(x, y) {
+			if isCandidate(x, y) {
 				return model.Point{X: x, Y: y}
 			}
 		}
@@ -240,7 +269,7 @@ func (p *CircuitBoardPlacer) chooseCircuitSeed(w, h int, occupied map[string]str
 }
 
 // getAvailableNeighbors returns unoccupied neighboring cells
-func (p *CircuitBoardPlacer) getAvailableNeighbors(pos model.Point, w, h int, globalOccupied, localOccupied map[string]string) []model.Point {
+func (p *CircuitBoardPlacer) getAvailableNeighbors(pos model.Point, grid *common.Grid) []model.Point {
 	deltas := []model.Point{
 		{X: 0, Y: -1}, // up
 		{X: 0, Y: 1},  // down
@@ -250,14 +279,8 @@ func (p *CircuitBoardPlacer) getAvailableNeighbors(pos model.Point, w, h int, gl
 
 	var neighbors []model.Point
 	for _, d := range deltas {
-		nx, ny := pos.X+d.X, pos.Y+d.Y
-		if nx >= 0 && nx < w && ny >= 0 && ny < h {
-			key := fmt.Sprintf("%d,%d", nx, ny)
-			_, globallyOccupied := globalOccupied[key]
-			_, locallyOccupied := localOccupied[key]
-			if !globallyOccupied && !locallyOccupied {
-				neighbors = append(neighbors, model.Point{X: nx, Y: ny})
-			}
+		if nx, ny := pos.X+d.X, pos.Y+d.Y; grid.IsFree(nx, ny) {
+			neighbors = append(neighbors, model.Point{X: nx, Y: ny})
 		}
 	}
 
@@ -374,28 +397,3 @@ func (p *CircuitBoardPlacer) getEdgeDistance(pos model.Point, w, h int) float64
 
 	return minDist
 }
-
-// calculateHeadDirection determines head direction from the first two segments
-func (p *CircuitBoardPlacer) calculateHeadDirection(path []model.Point) string {
-	if len(path) < 2 {
-		return "up" // fallback
-	}
-
-	head := path[0]
-	neck := path[1]
-
-	dx := head.X - neck.X
-	dy := head.Y - neck.Y
-
-	if dx == 1 && dy == 0 {
-		return "right"
-	} else if dx == -1 && dy == 0 {
-		return "left"
-	} else if dx == 0 && dy == 1 {
-		return "up"
-	} else if dx == 0 && dy == -1 {
-		return "down"
-	}
-
-	return "up" // fallback
-}
@@ -0,0 +1,33 @@
+package strategies
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+)
+
+// BenchmarkPlacersTranscendent measures vine placement on the largest
+// Transcendent grid (24x40), where occupancy lookups dominate run time.
+func BenchmarkPlacersTranscendent(b *testing.B) {
+	placers := []struct {
+		name   string
+		placer config.VinePlacementStrategy
+	}{
+		{"center-out", &CenterOutPlacer{}},
+		{"direction-first", &DirectionFirstPlacer{}},
+		{"full-coverage", &FullCoveragePlacer{}},
+	}
+	cfg := config.GenerationConfig{GridWidth: 24, GridHeight: 40, Difficulty: "Transcendent", VineCount: 60, MinCoverage: 0.93}
+
+	for _, tc := range placers {
+		b.Run(tc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				seed := int64(i + 1)
+				cfg.Seed = seed
+				_, _, _ = tc.placer.PlaceVines(context.Background(), cfg, rand.New(rand.NewSource(seed)), &config.GenerationStats{})
+			}
+		})
+	}
+}
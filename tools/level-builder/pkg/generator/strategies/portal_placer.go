@@ -0,0 +1,121 @@
+package strategies
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+// portalAttemptsPerPair bounds the candidate pairs tried for each portal.
+const portalAttemptsPerPair = 40
+
+// PortalPlacer links pairs of empty cells with portals once vines are placed.
+type PortalPlacer struct {
+	w, h int
+	rng  *rand.Rand
+}
+
+// NewPortalPlacer creates a new PortalPlacer.
+func NewPortalPlacer(w, h int, rng *rand.Rand) *PortalPlacer {
+	return &PortalPlacer{
+		w:   w,
+		h:   h,
+		rng: rng,
+	}
+}
+
+// PlacePortals returns up to pairs portals for the placed vines.
+//
+// One cell of each pair lies on some vine's exit ray, so the portal changes
+// where that vine goes; the other is any empty cell. A pair is kept only if
+// the level stays structurally valid and solvable, so nothing is added when
+// the vines are not solvable to begin with or the grid has no room.
+func (p *PortalPlacer) PlacePortals(vines []model.Vine, occupied map[string]string, pairs int) []model.Portal {
+	level := model.Level{GridSize: []int{p.w, p.h}, Vines: vines}
+	if pairs <= 0 || !common.NewSolver(&level).IsSolvableGreedy() {
+		return nil
+	}
+
+	for len(level.Portals) < pairs {
+		empty := p.freeCells(&level, occupied)
+		onRay := p.rayCells(&level, occupied)
+		if len(empty) < 2 || len(onRay) == 0 {
+			break
+		}
+
+		placed := false
+		for attempt := 0; attempt < portalAttemptsPerPair && !placed; attempt++ {
+			a := onRay[p.rng.Intn(len(onRay))]
+			b := empty[p.rng.Intn(len(empty))]
+			if a == b || touches(a, b) {
+				continue
+			}
+			candidate := level
+			candidate.Portals = append(append([]model.Portal(nil), level.Portals...), model.Portal{A: a, B: b})
+			if len(validator.ValidateStructural(candidate)) > 0 || !common.NewSolver(&candidate).IsSolvableGreedy() {
+				continue
+			}
+			level.Portals = candidate.Portals
+			placed = true
+		}
+		if !placed {
+			break
+		}
+	}
+	return level.Portals
+}
+
+// freeCells lists the empty cells that are not portals and not next to one.
+func (p *PortalPlacer) freeCells(level *model.Level, occupied map[string]string) []model.Point {
+	var cells []model.Point
+	for y := 0; y < p.h; y++ {
+		for x := 0; x < p.w; x++ {
+			if _, occ := occupied[fmt.Sprintf("%d,%d", x, y)]; occ {
+				continue
+			}
+			c := model.Point{X: x, Y: y}
+			nearPortal := false
+			for _, pt := range level.Portals {
+				if c == pt.A || c == pt.B || touches(c, pt.A) || touches(c, pt.B) {
+					nearPortal = true
+					break
+				}
+			}
+			if !nearPortal {
+				cells = append(cells, c)
+			}
+		}
+	}
+	return cells
+}
+
+// rayCells lists the free cells on some vine head's exit ray.
+func (p *PortalPlacer) rayCells(level *model.Level, occupied map[string]string) []model.Point {
+	free := make(map[model.Point]bool)
+	for _, c := range p.freeCells(level, occupied) {
+		free[c] = true
+	}
+	seen := make(map[model.Point]bool)
+	var cells []model.Point
+	for _, v := range level.Vines {
+		for _, head := range v.Heads() {
+			level.ExitRay(head.OrderedPath[0], head.HeadDirection, func(c model.Point) bool {
+				if free[c] && !seen[c] {
+					seen[c] = true
+					cells = append(cells, c)
+				}
+				return true
+			})
+		}
+	}
+	return cells
+}
+
+// touches reports whether a and b are orthogonal neighbours.
+func touches(a, b model.Point) bool {
+	dx, dy := a.X-b.X, a.Y-b.Y
+	return dx*dx+dy*dy == 1
+}
@@ -0,0 +1,60 @@
+package strategies
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+func TestPlacePortals(t *testing.T) {
+	mustVine := func(id string, path []model.Point) model.Vine {
+		v, err := model.NewVine(id, path, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	occupiedBy := func(vines []model.Vine) map[string]string {
+		occ := make(map[string]string)
+		for _, v := range vines {
+			for _, p := range v.OrderedPath {
+				occ[fmt.Sprintf("%d,%d", p.X, p.Y)] = v.ID
+			}
+		}
+		return occ
+	}
+
+	vines := []model.Vine{
+		mustVine("a", []model.Point{{X: 1, Y: 1}, {X: 0, Y: 1}}),
+		mustVine("b", []model.Point{{X: 3, Y: 2}, {X: 3, Y: 1}}),
+	}
+	placer := NewPortalPlacer(6, 4, rand.New(rand.NewSource(3)))
+	if got := placer.PlacePortals(vines, occupiedBy(vines), 0); got != nil {
+		t.Errorf("0 pairs placed %v", got)
+	}
+
+	portals := placer.PlacePortals(vines, occupiedBy(vines), 2)
+	if len(portals) == 0 || len(portals) > 2 {
+		t.Fatalf("placed %d portal pairs, want 1-2", len(portals))
+	}
+	level := model.Level{GridSize: []int{6, 4}, Vines: vines, Portals: portals}
+	if errs := validator.ValidateStructural(level); len(errs) > 0 {
+		t.Errorf("portals left the level invalid: %v", errs)
+	}
+	if !common.NewSolver(&level).IsSolvableGreedy() {
+		t.Error("portals left the level unsolvable")
+	}
+
+	// Facing heads are already stuck, so there is nothing to keep solvable
+	stuck := []model.Vine{
+		mustVine("a", []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}),
+		mustVine("b", []model.Point{{X: 2, Y: 0}, {X: 3, Y: 0}}),
+	}
+	if got := NewPortalPlacer(4, 3, rand.New(rand.NewSource(1))).PlacePortals(stuck, occupiedBy(stuck), 1); got != nil {
+		t.Errorf("placed portals on an unsolvable level: %v", got)
+	}
+}
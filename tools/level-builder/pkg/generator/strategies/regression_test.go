@@ -1,6 +1,7 @@
 package strategies_test
 
 import (
+	"context"
 	"math/rand"
 	"testing"
 
@@ -28,6 +29,7 @@ func TestProblematicSeed313385(t *testing.T) {
 	profile := utils.GetPresetProfile(diff)
 
 	vines, err := strategies.ClearableFirstPlacement(
+		context.Background(),
 		[]int{genCfg.GridWidth, genCfg.GridHeight},
 		spec,
 		profile,
@@ -75,7 +77,7 @@ func TestRegressionSeed569780(t *testing.T) {
 	rng := rand.New(rand.NewSource(attemptSeed))
 	seed := rng.Int63()
 
-	vines, err := strategies.ClearableFirstPlacement(gridSize, spec, profile, genCfg, seed, 0.3, spec.MinGridOccupancy, true)
+	vines, err := strategies.ClearableFirstPlacement(context.Background(), gridSize, spec, profile, genCfg, seed, 0.3, spec.MinGridOccupancy, true)
 	if err != nil {
 		t.Fatalf("Generation failed for seed %d (derived from %d): %v", seed, attemptSeed, err)
 	}
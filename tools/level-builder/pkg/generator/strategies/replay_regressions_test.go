@@ -1,6 +1,7 @@
 package strategies_test
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -61,7 +62,7 @@ func TestReplayRegressionSeeds(t *testing.T) {
 				OutputFile:           filepath.Join(tmpDir, "level.json"),
 			}
 
-			level, _, err := generator.GenerateLevelLIFO(config)
+			level, _, err := generator.GenerateLevelLIFO(context.Background(), config)
 			if err == nil {
 				if level.ID != levelID {
 					t.Fatalf("expected generated level ID %d got %d", levelID, level.ID)
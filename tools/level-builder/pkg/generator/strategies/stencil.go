@@ -0,0 +1,38 @@
+package strategies
+
+import (
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// StencilOwner owns a stencil's decorative gap cells in occupancy maps, so
+// placers and fillers treat them as taken and never grow a vine into them.
+// Placers that read exits from the same map also keep heads from aiming
+// through a gap; that only forgoes exits the finished level would allow.
+const StencilOwner = "stencil"
+
+// StencilStrategies lists the placers that keep out of config.Stencil cells.
+var StencilStrategies = []string{
+	config.StrategyFullCoverage,
+	config.StrategyDirectionFirst,
+	config.StrategyCenterOut,
+	config.StrategyChain,
+}
+
+// ReserveStencil marks cells as owned by StencilOwner in occupied and
+// returns it.
+func ReserveStencil(occupied map[string]string, cells []model.Point) map[string]string {
+	for _, p := range cells {
+		occupied[common.PointKey(p)] = StencilOwner
+	}
+	return occupied
+}
+
+// reserveStencilCells marks cells as taken in a set keyed like
+// common.PointKey, for the phases that track occupancy without owners.
+func reserveStencilCells(occupied map[string]bool, cells []model.Point) {
+	for _, p := range cells {
+		occupied[common.PointKey(p)] = true
+	}
+}
@@ -30,6 +30,10 @@ func calculateVineLengths(
 	if avgLen <= 0 {
 		avgLen = 3
 	}
+	dist := constraints.LengthDistribution
+	if dist.Enabled() {
+		avgLen = maxInt(2, int(math.Round(dist.Expected((w+h)/2))))
+	}
 
 	// Initial vine count (rounded)
 	vineCount := total / avgLen
@@ -41,9 +45,14 @@ func calculateVineLengths(
 	}
 
 	// Distribute lengths to exactly fill the grid, but consider profile.LengthMix
+	// (or the tier's length distribution, which replaces it)
 	// IMPORTANT: Minimum vine length is 2 (head + neck)
 	lengths := make([]int, vineCount)
 	for i := 0; i < vineCount; i++ {
+		if dist.Enabled() {
+			lengths[i] = dist.Sample(rng, (w+h)/2)
+			continue
+		}
 		bucket := chooseLengthBucket(profile, rng)
 		switch bucket {
 		case "short":
@@ -78,11 +87,13 @@ func calculateVineLengths(
 }
 
 // growVines attempts to grow vines based on the given lengths, returning the vines and occupied map.
+// Head directions are steered by balance as vines are added.
 func growVines(
 	gridSize []int,
 	lengths []int,
 	profile config.VarietyProfile,
 	cfg config.GeneratorConfig,
+	balance *utils.DirBalancer,
 	rng *rand.Rand,
 ) ([]model.Vine, map[string]bool, error) {
 	w := gridSize[0]
@@ -90,6 +101,7 @@ func growVines(
 
 	occupied := make(map[string]bool)
 	vines := make([]model.Vine, 0, len(lengths))
+	guard := strandGuard{islands: utils.NewIslandDetector(w, h)}
 
 	for i := 0; i < len(lengths); i++ {
 		target := lengths[i]
@@ -101,7 +113,10 @@ func growVines(
 			if seed == nil {
 				break
 			}
-			v, newOcc, e := GrowFromSeed(*seed, occupied, gridSize, target, profile, cfg, rng)
+			v, newOcc, e := GrowFromSeed(*seed, occupied, gridSize, target, balancedProfile(profile, balance), cfg, rng)
+			if e == nil && attempt < cfg.MaxSeedRetries-1 && guard.reject(v.OrderedPath) {
+				continue // would strand dead cells; try another seed
+			}
 			if e == nil {
 				grown = v
 				for k := range newOcc {
@@ -118,14 +133,19 @@ func growVines(
 			if s == nil {
 				return nil, nil, fmt.Errorf("unable to find empty cell for fallback: %w", err)
 			}
-			id := fmt.Sprintf("v%d", len(vines)+1)
+			id := model.VineID(len(vines) + 1)
+			// vine-literal: single-cell fallback is intentionally below NewVine's minimum
 			v := model.Vine{ID: id, HeadDirection: "up", OrderedPath: []model.Point{*s}}
 			vines = append(vines, v)
 			occupied[fmt.Sprintf("%d,%d", s.X, s.Y)] = true
+			guard.islands.Place(v)
 		} else {
-			grown.ID = fmt.Sprintf("v%d", len(vines)+1)
+			grown.ID = model.VineID(len(vines) + 1)
 			vines = append(vines, grown)
+			balance.RecordVine(grown)
+			guard.islands.Place(grown)
 		}
+		guard.reset()
 	}
 
 	return vines, occupied, nil
@@ -142,29 +162,12 @@ func TileGridIntoVines(
 ) ([]model.Vine, *model.Mask, error) {
 	_, lengths := calculateVineLengths(gridSize, constraints, profile, rng)
 
-	vines, occupied, err := growVines(gridSize, lengths, profile, cfg, rng)
+	balance := utils.NewDirBalancer(profile.DirBalance, constraints.DirBalanceTolerance)
+	vines, _, err := growVines(gridSize, lengths, profile, cfg, balance, rng)
 	if err != nil {
 		return nil, nil, err
 	}
-
-	// Collect empty cells for masking
-	var emptyPoints []model.Point
-	w := gridSize[0]
-	h := gridSize[1]
-	for y := 0; y < h; y++ {
-		for x := 0; x < w; x++ {
-			key := fmt.Sprintf("%d,%d", x, y)
-			if !occupied[key] {
-				emptyPoints = append(emptyPoints, model.Point{X: x, Y: y})
-			}
-		}
-	}
-	var mask *model.Mask
-	if len(emptyPoints) > 0 {
-		mask = &model.Mask{Mode: "hide", Points: emptyPoints}
-	}
-
-	return vines, mask, nil
+	return vines, EmptyCellMask(gridSize, vines), nil
 }
 
 // GrowFromSeed attempts to grow a vine starting from seed, avoiding occupied cells.
@@ -216,27 +219,13 @@ func GrowFromSeed(
 			if len(path) < 2 {
 				return model.Vine{}, nil, fmt.Errorf("cannot grow vine: stuck after %d cells (need at least 2)", len(path))
 			}
-			// Return what we have so far (at least 2 cells)
-			// CRITICAL: Calculate correct head direction based on actual head/neck positions
-			head := path[0]
-			neck := path[1]
-			dx := head.X - neck.X
-			dy := head.Y - neck.Y
-
-			actualHeadDir := ""
-			if dx == 1 && dy == 0 {
-				actualHeadDir = "right"
-			} else if dx == -1 && dy == 0 {
-				actualHeadDir = "left"
-			} else if dx == 0 && dy == 1 {
-				actualHeadDir = "up"
-			} else if dx == 0 && dy == -1 {
-				actualHeadDir = "down"
-			} else {
-				actualHeadDir = desiredHeadDir
+			// Return what we have so far (at least 2 cells); NewVine derives the
+			// head direction from the actual head/neck positions
+			v, err := model.NewVine("", path, "")
+			if err != nil {
+				return model.Vine{}, nil, fmt.Errorf("cannot grow vine: %w", err)
 			}
-
-			return model.Vine{HeadDirection: actualHeadDir, OrderedPath: path}, occ, nil
+			return v, occ, nil
 		}
 
 		var chosen model.Point
@@ -340,41 +329,16 @@ func GrowFromSeed(
 		occ[k] = true
 	}
 
-	// CRITICAL VALIDATION: Vines must be at least 2 cells (head + neck)
-	if len(path) < 2 {
-		return model.Vine{}, nil, fmt.Errorf("vine too short: got %d cells, need at least 2", len(path))
-	}
-
-	// CRITICAL FIX: Calculate the correct head direction based on actual head/neck positions
-	// The head is at path[0], the neck is at path[1]
-	// The direction is: vector from neck to head
-	head := path[0]
-	neck := path[1]
-	dx := head.X - neck.X
-	dy := head.Y - neck.Y
-
-	// Convert delta to direction string
-	actualHeadDir := ""
-	if dx == 1 && dy == 0 {
-		actualHeadDir = "right"
-	} else if dx == -1 && dy == 0 {
-		actualHeadDir = "left"
-	} else if dx == 0 && dy == 1 {
-		actualHeadDir = "up"
-	} else if dx == 0 && dy == -1 {
-		actualHeadDir = "down"
-	} else {
-		// Unexpected: head and neck not adjacent (shouldn't happen with proper growth)
-		actualHeadDir = desiredHeadDir
+	// CRITICAL: Vines must be at least 2 cells (head + neck) and the head
+	// direction must match the vector from neck to head. NewVine enforces both
+	// and derives the direction from the actual path geometry.
+	v, err := model.NewVine("", path, "")
+	if err != nil {
+		return model.Vine{}, nil, fmt.Errorf("vine too short or malformed: %w", err)
 	}
+	common.Verbose("DEBUG: GrowFromSeed created: head=%v neck=%v dir=%s", path[0], path[1], v.HeadDirection)
 
-	// Check for mismatch (DEBUG)
-	// if actualHeadDir == "down" && dy == 1 {
-	common.Verbose("DEBUG: GrowFromSeed created: head=%v neck=%v dx=%d dy=%d dir=%s", head, neck, dx, dy, actualHeadDir)
-	// }
-
-	// Return vine with CORRECT head direction based on actual path geometry
-	return model.Vine{HeadDirection: actualHeadDir, OrderedPath: path}, occ, nil
+	return v, occ, nil
 }
 
 // calculateDensityScore evaluates how well a neighbor fills gaps and creates density.
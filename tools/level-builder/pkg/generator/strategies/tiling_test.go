@@ -3,6 +3,7 @@ package strategies_test
 import (
 	"fmt"
 	"math/rand"
+	"reflect"
 	"testing"
 
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
@@ -444,3 +445,19 @@ func BenchmarkSolverBFS(b *testing.B) {
 		})
 	}
 }
+
+func TestEmptyCellMask(t *testing.T) {
+	vines := []model.Vine{
+		{ID: "a", OrderedPath: []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}},
+		{ID: "b", OrderedPath: []model.Point{{X: 1, Y: 1}, {X: 0, Y: 1}}},
+	}
+	if mask := strategies.EmptyCellMask([]int{2, 2}, vines); mask != nil {
+		t.Errorf("full grid mask = %+v, want nil", mask)
+	}
+
+	mask := strategies.EmptyCellMask([]int{3, 2}, vines)
+	want := []model.Point{{X: 2, Y: 0}, {X: 2, Y: 1}}
+	if mask == nil || mask.Mode != "hide" || !reflect.DeepEqual(mask.Points, want) {
+		t.Errorf("mask = %+v, want hide %v", mask, want)
+	}
+}
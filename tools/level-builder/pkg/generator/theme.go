@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"sort"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// maxAccentCells caps the accent decorations on one board.
+const maxAccentCells = 6
+
+// biomeDensities is the base decoration density of each known theme seed.
+// Unknown seeds get defaultDecorationDensity.
+var biomeDensities = map[string]float64{
+	"forest":    0.35,
+	"meadow":    0.20,
+	"garden":    0.30,
+	"orchard":   0.25,
+	"abundance": 0.40,
+	"sunset":    0.15,
+	"ocean":     0.20,
+	"volcano":   0.10,
+	"lavender":  0.25,
+	"twilight":  0.15,
+	"aurora":    0.20,
+}
+
+const defaultDecorationDensity = 0.20
+
+// IsThemeSeed reports whether seed names a biome the game has art for.
+func IsThemeSeed(seed string) bool {
+	_, ok := biomeDensities[seed]
+	return ok
+}
+
+// ThemeFor derives a level's theme from its module's theme seed. The result
+// depends only on the seed and the level, so regenerating a level with the
+// same seed themes it the same way. The biome is the seed itself; accent cells
+// are picked among the cells no vine or portal covers, so fully covered boards
+// have none.
+func ThemeFor(themeSeed string, level model.Level) model.LevelTheme {
+	density, ok := biomeDensities[themeSeed]
+	if !ok {
+		density = defaultDecorationDensity
+	}
+	theme := model.LevelTheme{Biome: themeSeed, DecorationDensity: density}
+
+	taken := make(map[model.Point]bool)
+	for _, v := range level.Vines {
+		for _, p := range v.OrderedPath {
+			taken[p] = true
+		}
+	}
+	for _, pt := range level.Portals {
+		taken[pt.A] = true
+		taken[pt.B] = true
+	}
+	var free []model.Point
+	for y := 0; y < level.GetGridHeight(); y++ {
+		for x := 0; x < level.GetGridWidth(); x++ {
+			if p := (model.Point{X: x, Y: y}); !taken[p] {
+				free = append(free, p)
+			}
+		}
+	}
+	if len(free) == 0 {
+		return theme
+	}
+
+	count := int(float64(len(free))*density + 0.5)
+	count = max(1, min(count, maxAccentCells))
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(themeSeed))
+	rng := rand.New(rand.NewSource(int64(h.Sum64()) ^ int64(level.ID)))
+	rng.Shuffle(len(free), func(i, j int) { free[i], free[j] = free[j], free[i] })
+	theme.AccentCells = free[:count]
+	sort.Slice(theme.AccentCells, func(i, j int) bool {
+		a, b := theme.AccentCells[i], theme.AccentCells[j]
+		return a.Y < b.Y || (a.Y == b.Y && a.X < b.X)
+	})
+	return theme
+}
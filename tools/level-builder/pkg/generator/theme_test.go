@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func TestThemeFor(t *testing.T) {
+	level := model.Level{
+		ID:       12,
+		GridSize: []int{4, 4},
+		Vines: []model.Vine{
+			{ID: "v1", HeadDirection: "right", OrderedPath: []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}},
+			{ID: "v2", HeadDirection: "up", OrderedPath: []model.Point{{X: 2, Y: 3}, {X: 2, Y: 2}, {X: 2, Y: 1}}},
+		},
+		Portals: []model.Portal{{A: model.Point{X: 0, Y: 3}, B: model.Point{X: 3, Y: 0}}},
+	}
+
+	theme := ThemeFor("forest", level)
+	if theme.Biome != "forest" || theme.DecorationDensity != biomeDensities["forest"] {
+		t.Errorf("theme = %+v, want forest at density %.2f", theme, biomeDensities["forest"])
+	}
+	// 16 cells - 5 vine cells - 2 portal cells = 9 free; 9 * 0.35 rounds to 3
+	if len(theme.AccentCells) != 3 {
+		t.Fatalf("accent cells = %v, want 3", theme.AccentCells)
+	}
+	for _, p := range theme.AccentCells {
+		if p == (model.Point{X: 0, Y: 3}) || p == (model.Point{X: 3, Y: 0}) || p.X == 2 && p.Y > 0 || p.Y == 0 && p.X < 2 {
+			t.Errorf("accent cell %v is covered", p)
+		}
+	}
+	if again := ThemeFor("forest", level); !reflect.DeepEqual(again, theme) {
+		t.Errorf("theme not deterministic: %+v then %+v", theme, again)
+	}
+	if other := ThemeFor("unknown", level); other.DecorationDensity != defaultDecorationDensity {
+		t.Errorf("unknown seed density = %.2f, want %.2f", other.DecorationDensity, defaultDecorationDensity)
+	}
+
+	// Mirrors carry the theme with reflected accent cells
+	level.Theme = &theme
+	mirror, err := common.MirrorLevel(&level, 112, common.MirrorHorizontal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range mirror.Theme.AccentCells {
+		if want := (model.Point{X: 3 - theme.AccentCells[i].X, Y: theme.AccentCells[i].Y}); p != want {
+			t.Errorf("mirrored accent cell %d = %v, want %v", i, p, want)
+		}
+	}
+
+	// A fully covered board has nothing to decorate
+	full := model.Level{ID: 1, GridSize: []int{2, 1}, Vines: []model.Vine{
+		{ID: "v1", HeadDirection: "right", OrderedPath: []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}},
+	}}
+	if got := ThemeFor("meadow", full); len(got.AccentCells) != 0 {
+		t.Errorf("accent cells on a full board = %v", got.AccentCells)
+	}
+}
@@ -1,7 +1,6 @@
 package utils
 
 import (
-	"fmt"
 	"sort"
 
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
@@ -9,13 +8,23 @@ import (
 
 // BuildBlockingGraph returns a map where graph[a][b] == true means vine a blocks vine b.
 // A vine A is considered to block vine B if any cell of A occupies the target head cell
-// that B would move into on its next move (head cell + headDirection delta).
+// that B would move into on its next move (head cell + headDirection delta). A
+// multi-head vine is blocked by A only when A is in front of both of its heads.
 func BuildBlockingGraph(vines []model.Vine) map[string]map[string]bool {
-	occ := make(map[string]string)
+	return buildBlockingGraph(vines, nil)
+}
+
+// BuildLevelBlockingGraph is BuildBlockingGraph for a complete level: a head
+// facing one of the level's portals is blocked by the vine past its twin.
+func BuildLevelBlockingGraph(level *model.Level) map[string]map[string]bool {
+	return buildBlockingGraph(level.Vines, level)
+}
+
+func buildBlockingGraph(vines []model.Vine, level *model.Level) map[string]map[string]bool {
+	occ := make(map[model.Point]string)
 	for _, v := range vines {
 		for _, p := range v.OrderedPath {
-			key := fmt.Sprintf("%d,%d", p.X, p.Y)
-			occ[key] = v.ID
+			occ[p] = v.ID
 		}
 	}
 
@@ -25,23 +34,43 @@ func BuildBlockingGraph(vines []model.Vine) map[string]map[string]bool {
 	}
 
 	for _, b := range vines {
-		// compute the cell b's head would move to
 		if len(b.OrderedPath) == 0 {
 			continue
 		}
-		head := b.OrderedPath[0]
-		dx, dy := DeltaForDirection(b.HeadDirection)
-		tx := head.X + dx
-		ty := head.Y + dy
-		key := fmt.Sprintf("%d,%d", tx, ty)
-		if blocker, ok := occ[key]; ok && blocker != b.ID {
-			graph[blocker][b.ID] = true
+		blocker := headBlocker(b, occ, level)
+		if blocker == "" {
+			continue
 		}
+		// A multi-head vine is only held up by a vine in front of both heads
+		if b.IsMultiHead() && headBlocker(b.Reversed(), occ, level) != blocker {
+			continue
+		}
+		graph[blocker][b.ID] = true
 	}
 
 	return graph
 }
 
+// headBlocker returns the other vine occupying the cell v's head would move
+// into, or "" when that cell is free. When level is set the move follows its
+// portals.
+func headBlocker(v model.Vine, occ map[model.Point]string, level *model.Level) string {
+	head := v.OrderedPath[0]
+	dx, dy := DeltaForDirection(v.HeadDirection)
+	next := model.Point{X: head.X + dx, Y: head.Y + dy}
+	if level != nil {
+		// A head trapped in a portal loop is held up by no vine either
+		var inGrid bool
+		if next, inGrid, _ = level.NextCell(head, v.HeadDirection); !inGrid {
+			return ""
+		}
+	}
+	if blocker, ok := occ[next]; ok && blocker != v.ID {
+		return blocker
+	}
+	return ""
+}
+
 // PickBacktrackCandidates returns up to 'window' vine IDs which are good candidates
 // to remove when attempting to recover a failing vine. Preference is given to vines
 // that block the failing vine or that block many other vines (higher out-degree).
@@ -80,3 +109,106 @@ func PickBacktrackCandidates(graph map[string]map[string]bool, failingVine strin
 	}
 	return out
 }
+
+// MaxBlockingDepth returns the length of the longest blocker chain in graph
+// (a vine blocked by nothing has depth 0). Each cycle is collapsed into one
+// link of the chain that counts every vine on it once, so circular blocking
+// cannot inflate the result indefinitely: two vines blocking each other have
+// depth 1.
+func MaxBlockingDepth(graph map[string]map[string]bool) int {
+	maxDepth := 0
+	for _, d := range BlockingDepths(graph) {
+		if d > maxDepth {
+			maxDepth = d
+		}
+	}
+	return maxDepth
+}
+
+// BlockingDepths returns the blocker chain length starting at every vine in
+// graph, using the same rules as MaxBlockingDepth. Vines on the same cycle
+// share a depth, and the result does not depend on map iteration order.
+func BlockingDepths(graph map[string]map[string]bool) map[string]int {
+	// Every vine named in graph, keys and targets alike, in sorted order
+	seen := make(map[string]bool)
+	var ids []string
+	add := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for id, blocked := range graph {
+		add(id)
+		for b := range blocked {
+			add(b)
+		}
+	}
+	sort.Strings(ids)
+
+	// Tarjan's strongly connected components. A component is finished only
+	// after every component it reaches, so comps comes out in an order where
+	// each component's successors precede it.
+	index := make(map[string]int, len(ids))
+	low := make(map[string]int, len(ids))
+	onStack := make(map[string]bool, len(ids))
+	comp := make(map[string]int, len(ids))
+	var stack []string
+	var comps [][]string
+
+	var connect func(id string)
+	connect = func(id string) {
+		index[id] = len(index)
+		low[id] = index[id]
+		stack = append(stack, id)
+		onStack[id] = true
+		for next := range graph[id] {
+			if _, ok := index[next]; !ok {
+				connect(next)
+				low[id] = min(low[id], low[next])
+			} else if onStack[next] {
+				low[id] = min(low[id], index[next])
+			}
+		}
+		if low[id] != index[id] {
+			return
+		}
+		var members []string
+		for {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[n] = false
+			comp[n] = len(comps)
+			members = append(members, n)
+			if n == id {
+				break
+			}
+		}
+		comps = append(comps, members)
+	}
+	for _, id := range ids {
+		if _, ok := index[id]; !ok {
+			connect(id)
+		}
+	}
+
+	// A component of n vines is a chain of n-1 links followed by the deepest
+	// component it blocks on
+	depths := make([]int, len(comps))
+	out := make(map[string]int, len(ids))
+	for c, members := range comps {
+		best := 0
+		for _, id := range members {
+			for next := range graph[id] {
+				if nc := comp[next]; nc != c {
+					best = max(best, depths[nc]+1)
+				}
+			}
+		}
+		depths[c] = len(members) - 1 + best
+		for _, id := range members {
+			out[id] = depths[c]
+		}
+	}
+	return out
+}
@@ -41,3 +41,44 @@ func TestPickBacktrackCandidatesPrioritizesDirectBlockers(t *testing.T) {
 		t.Fatalf("expected A first, got %v", cands)
 	}
 }
+
+func TestMaxBlockingDepthChainAndCycle(t *testing.T) {
+	chain := map[string]map[string]bool{
+		"A": {"B": true},
+		"B": {"C": true},
+		"C": {},
+		"D": {"C": true},
+	}
+	if got := MaxBlockingDepth(chain); got != 2 {
+		t.Fatalf("expected depth 2 for A->B->C, got %d", got)
+	}
+
+	cycle := map[string]map[string]bool{
+		"A": {"B": true},
+		"B": {"A": true},
+	}
+	if got := MaxBlockingDepth(cycle); got != 1 {
+		t.Fatalf("expected depth 1 for A<->B, got %d", got)
+	}
+
+	// A three-vine cycle behind a blocker, leading into a chain: E->A, the
+	// cycle A->B->C->A, and C->D. Every vine is counted once.
+	mixed := map[string]map[string]bool{
+		"E": {"A": true},
+		"A": {"B": true},
+		"B": {"C": true},
+		"C": {"A": true, "D": true},
+	}
+	want := map[string]int{"E": 4, "A": 3, "B": 3, "C": 3, "D": 0}
+	for i := 0; i < 20; i++ {
+		got := BlockingDepths(mixed)
+		if len(got) != len(want) {
+			t.Fatalf("BlockingDepths = %v, want %v", got, want)
+		}
+		for id, d := range want {
+			if got[id] != d {
+				t.Fatalf("BlockingDepths = %v, want %v", got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"math/rand"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// VineAdjacency returns, for each vine index, the indices of the vines that
+// touch it: a cell of one is orthogonally next to a cell of the other.
+func VineAdjacency(vines []model.Vine) [][]int {
+	owner := make(map[model.Point]int)
+	for i, v := range vines {
+		for _, p := range v.OrderedPath {
+			owner[p] = i
+		}
+	}
+
+	adj := make([][]int, len(vines))
+	seen := make([]map[int]bool, len(vines))
+	for i := range vines {
+		seen[i] = make(map[int]bool)
+	}
+	for i, v := range vines {
+		for _, p := range v.OrderedPath {
+			for _, n := range []model.Point{{X: p.X + 1, Y: p.Y}, {X: p.X - 1, Y: p.Y}, {X: p.X, Y: p.Y + 1}, {X: p.X, Y: p.Y - 1}} {
+				j, ok := owner[n]
+				if !ok || j == i || seen[i][j] {
+					continue
+				}
+				seen[i][j] = true
+				adj[i] = append(adj[i], j)
+			}
+		}
+	}
+	return adj
+}
+
+// AssignColors sets each vine's ColorIndex in [0, colors) so that touching
+// vines get different colors whenever the palette allows. Vines are colored
+// in DSatur order (most distinct neighbor colors first, then most neighbors),
+// each taking the least used color its neighbors leave free. When every color
+// is taken by a neighbor, the vine takes the color fewest neighbors share.
+// A non-nil rng shuffles which palette entry each color lands on, keeping the
+// coloring valid. It returns the number of touching pairs that share a color.
+func AssignColors(vines []model.Vine, colors int, rng *rand.Rand) int {
+	if colors < 1 {
+		colors = 1
+	}
+	adj := VineAdjacency(vines)
+	color := make([]int, len(vines))
+	for i := range color {
+		color[i] = -1
+	}
+	used := make([]int, colors)
+
+	for range vines {
+		// Pick the uncolored vine whose neighbors use the most distinct colors
+		next, bestSat, bestDeg := -1, -1, -1
+		for i := range vines {
+			if color[i] >= 0 {
+				continue
+			}
+			sat := make(map[int]bool)
+			for _, j := range adj[i] {
+				if color[j] >= 0 {
+					sat[color[j]] = true
+				}
+			}
+			if len(sat) > bestSat || (len(sat) == bestSat && len(adj[i]) > bestDeg) {
+				next, bestSat, bestDeg = i, len(sat), len(adj[i])
+			}
+		}
+
+		clashes := make([]int, colors)
+		for _, j := range adj[next] {
+			if color[j] >= 0 {
+				clashes[color[j]]++
+			}
+		}
+		pick := 0
+		for c := 1; c < colors; c++ {
+			if clashes[c] < clashes[pick] || (clashes[c] == clashes[pick] && used[c] < used[pick]) {
+				pick = c
+			}
+		}
+		color[next] = pick
+		used[pick]++
+	}
+
+	perm := make([]int, colors)
+	for c := range perm {
+		perm[c] = c
+	}
+	if rng != nil {
+		rng.Shuffle(colors, func(i, j int) { perm[i], perm[j] = perm[j], perm[i] })
+	}
+
+	conflicts := 0
+	for i := range vines {
+		vines[i].ColorIndex = perm[color[i]]
+		for _, j := range adj[i] {
+			if j > i && color[j] == color[i] {
+				conflicts++
+			}
+		}
+	}
+	return conflicts
+}
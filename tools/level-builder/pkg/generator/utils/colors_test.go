@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// stripes returns n vertical 2-cell vines side by side, each touching the next.
+func stripes(n int) []model.Vine {
+	vines := make([]model.Vine, n)
+	for i := range vines {
+		vines[i] = makeVine(string(rune('a'+i)), "up", []model.Point{{X: i, Y: 1}, {X: i, Y: 0}})
+	}
+	return vines
+}
+
+func TestVineAdjacency(t *testing.T) {
+	vines := stripes(3)
+	vines = append(vines, makeVine("far", "up", []model.Point{{X: 6, Y: 1}, {X: 6, Y: 0}}))
+	adj := VineAdjacency(vines)
+	if len(adj[0]) != 1 || len(adj[1]) != 2 || len(adj[2]) != 1 || len(adj[3]) != 0 {
+		t.Errorf("adjacency = %v", adj)
+	}
+}
+
+func TestAssignColorsSeparatesNeighbors(t *testing.T) {
+	for _, rng := range []*rand.Rand{nil, rand.New(rand.NewSource(7))} {
+		vines := stripes(6)
+		if conflicts := AssignColors(vines, 2, rng); conflicts != 0 {
+			t.Errorf("%d conflicts coloring a path with 2 colors", conflicts)
+		}
+		for i := 1; i < len(vines); i++ {
+			if vines[i].ColorIndex == vines[i-1].ColorIndex {
+				t.Errorf("%s and %s share color %d", vines[i-1].ID, vines[i].ID, vines[i].ColorIndex)
+			}
+		}
+	}
+
+	// Spare colors spread out instead of reusing the first ones
+	vines := stripes(4)
+	AssignColors(vines, 4, nil)
+	seen := make(map[int]bool)
+	for _, v := range vines {
+		seen[v.ColorIndex] = true
+	}
+	if len(seen) != 4 {
+		t.Errorf("4 vines used %d of 4 colors", len(seen))
+	}
+}
+
+func TestAssignColorsPaletteTooSmall(t *testing.T) {
+	// Three vines that all touch around (1,1)
+	vines := []model.Vine{
+		makeVine("a", "left", []model.Point{{X: 0, Y: 0}, {X: 1, Y: 0}}),
+		makeVine("b", "left", []model.Point{{X: 0, Y: 1}, {X: 1, Y: 1}}),
+		makeVine("c", "down", []model.Point{{X: 2, Y: 0}, {X: 2, Y: 1}}),
+	}
+	if conflicts := AssignColors(vines, 2, nil); conflicts != 1 {
+		t.Errorf("conflicts = %d, want 1 for a triangle with 2 colors", conflicts)
+	}
+	if conflicts := AssignColors(vines, 0, nil); conflicts != 3 || vines[0].ColorIndex != 0 {
+		t.Errorf("conflicts = %d with an empty palette, want 3 all on color 0", conflicts)
+	}
+}
@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"math"
+	"sort"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+const (
+	// dirBalanceMinSample is how many heads are placed before balancing
+	// starts; earlier shares are too noisy to judge
+	dirBalanceMinSample = 4
+	// dirBalancePenalty scales how fast a direction's weight falls once its
+	// share passes the tolerance: an excess of 0.1 weighs it at about 0.45
+	dirBalancePenalty = 8.0
+)
+
+// balanceDirections fixes the order directions are weighed and ranked in, so
+// ties resolve the same way as common.ChooseExitDirection.
+var balanceDirections = []string{"left", "right", "down", "up"}
+
+// DirBalancer tracks the head directions a placer has used in a level and
+// penalizes directions whose share would exceed their target by more than
+// the tier's tolerance (DifficultySpec.DirBalanceTolerance), so heads spread
+// across the grid edges instead of piling toward one. A nil balancer, or one
+// with zero tolerance, never penalizes.
+type DirBalancer struct {
+	target    map[string]float64 // share per direction, summing to 1
+	tolerance float64
+	counts    map[string]int
+	total     int
+}
+
+// NewDirBalancer returns a balancer aiming for target, a relative weight per
+// direction such as VarietyProfile.DirBalance; nil or all-zero targets aim
+// for equal shares.
+func NewDirBalancer(target map[string]float64, tolerance float64) *DirBalancer {
+	sum := 0.0
+	for _, dir := range balanceDirections {
+		sum += math.Max(target[dir], 0)
+	}
+	shares := make(map[string]float64, len(balanceDirections))
+	for _, dir := range balanceDirections {
+		if sum > 0 {
+			shares[dir] = math.Max(target[dir], 0) / sum
+		} else {
+			shares[dir] = 1 / float64(len(balanceDirections))
+		}
+	}
+	return &DirBalancer{target: shares, tolerance: tolerance, counts: make(map[string]int)}
+}
+
+// Record counts a placed head.
+func (b *DirBalancer) Record(dir string) {
+	if b == nil {
+		return
+	}
+	b.counts[dir]++
+	b.total++
+}
+
+// RecordVine counts every head of v, including a multi-head vine's tail head.
+func (b *DirBalancer) RecordVine(v model.Vine) {
+	for _, h := range v.Heads() {
+		b.Record(h.HeadDirection)
+	}
+}
+
+// Reset forgets every recorded head, for placers that rip vines back out.
+func (b *DirBalancer) Reset() {
+	if b == nil {
+		return
+	}
+	b.counts = make(map[string]int)
+	b.total = 0
+}
+
+// Counts returns the heads recorded per direction.
+func (b *DirBalancer) Counts() map[string]int {
+	counts := make(map[string]int, len(balanceDirections))
+	if b != nil {
+		for dir, n := range b.counts {
+			counts[dir] = n
+		}
+	}
+	return counts
+}
+
+// Weight returns a factor in (0, 1] for placing another head in dir: 1 while
+// dir's share, counting the new head, stays within tolerance of its target,
+// falling off exponentially beyond it.
+func (b *DirBalancer) Weight(dir string) float64 {
+	if b == nil || b.tolerance <= 0 || b.total < dirBalanceMinSample {
+		return 1
+	}
+	share := float64(b.counts[dir]+1) / float64(b.total+1)
+	excess := share - b.target[dir] - b.tolerance
+	if excess <= 0 {
+		return 1
+	}
+	return math.Exp(-dirBalancePenalty * excess)
+}
+
+// Adjust returns dirBalance with each direction's weight scaled by Weight,
+// for selectors such as ChooseExitDirection that take a dirBalance map.
+// Directions missing from dirBalance count as weight 1.
+func (b *DirBalancer) Adjust(dirBalance map[string]float64) map[string]float64 {
+	adjusted := make(map[string]float64, len(balanceDirections))
+	for _, dir := range balanceDirections {
+		w, ok := dirBalance[dir]
+		if !ok {
+			w = 1
+		}
+		adjusted[dir] = w * b.Weight(dir)
+	}
+	return adjusted
+}
+
+// RankExits orders the four directions for a head at pos, nearest grid edge
+// first, with each distance divided by the direction's Weight so
+// over-represented directions drop back. Without penalties the first
+// direction matches common.ChooseExitDirection.
+func (b *DirBalancer) RankExits(pos model.Point, w, h int) []string {
+	dist := map[string]int{
+		"left":  pos.X,
+		"right": w - 1 - pos.X,
+		"down":  pos.Y,
+		"up":    h - 1 - pos.Y,
+	}
+	score := make(map[string]float64, len(dist))
+	for dir, d := range dist {
+		score[dir] = float64(d+1) / b.Weight(dir)
+	}
+	ranked := append([]string(nil), balanceDirections...)
+	sort.SliceStable(ranked, func(i, j int) bool { return score[ranked[i]] < score[ranked[j]] })
+	return ranked
+}
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func TestDirBalancerWeight(t *testing.T) {
+	b := NewDirBalancer(nil, 0.1)
+	for i := 0; i < 3; i++ {
+		b.Record("up")
+	}
+	if w := b.Weight("up"); w != 1 {
+		t.Errorf("Weight(up) after 3 heads = %v, want 1 below the minimum sample", w)
+	}
+
+	b.Record("left")
+	if w := b.Weight("up"); w >= 1 {
+		t.Errorf("Weight(up) with 3 of 4 heads up = %v, want a penalty", w)
+	}
+	if w := b.Weight("down"); w != 1 {
+		t.Errorf("Weight(down) = %v, want 1", w)
+	}
+
+	// Zero tolerance and nil balancers never penalize
+	off := NewDirBalancer(nil, 0)
+	var none *DirBalancer
+	for i := 0; i < 8; i++ {
+		off.Record("up")
+		none.Record("up")
+	}
+	if off.Weight("up") != 1 || none.Weight("up") != 1 {
+		t.Error("balancing without tolerance penalized a direction")
+	}
+
+	b.Reset()
+	if w := b.Weight("up"); w != 1 || len(b.Counts()) != 0 {
+		t.Errorf("after Reset Weight(up) = %v, counts %v", w, b.Counts())
+	}
+}
+
+func TestDirBalancerTargetAndAdjust(t *testing.T) {
+	// A profile that wants mostly upward heads tolerates them
+	b := NewDirBalancer(map[string]float64{"up": 3, "left": 1}, 0.1)
+	b.RecordVine(model.Vine{HeadDirection: "up", TailDirection: "down"})
+	b.Record("up")
+	b.Record("up")
+	if w := b.Weight("up"); w != 1 {
+		t.Errorf("Weight(up) = %v, want 1 within a 75%% target", w)
+	}
+	if w := b.Weight("down"); w >= 1 {
+		t.Errorf("Weight(down) = %v, want a penalty against a 0%% target", w)
+	}
+
+	adjusted := b.Adjust(map[string]float64{"left": 2})
+	if adjusted["left"] != 2 || adjusted["up"] != 1 || adjusted["down"] >= 1 {
+		t.Errorf("Adjust = %v", adjusted)
+	}
+}
+
+func TestDirBalancerRankExits(t *testing.T) {
+	w, h := 7, 9
+	var b *DirBalancer
+	for _, pos := range []model.Point{{X: 0, Y: 0}, {X: 3, Y: 4}, {X: 6, Y: 1}, {X: 2, Y: 8}} {
+		if got, want := b.RankExits(pos, w, h)[0], common.ChooseExitDirection(pos, w, h); got != want {
+			t.Errorf("RankExits(%v)[0] = %s, want %s", pos, got, want)
+		}
+	}
+
+	// Heads crowding the left edge push the next one elsewhere
+	b = NewDirBalancer(nil, 0.1)
+	for i := 0; i < 6; i++ {
+		b.Record("left")
+	}
+	if got := b.RankExits(model.Point{X: 1, Y: 4}, w, h)[0]; got == "left" {
+		t.Error("RankExits kept an over-represented direction first")
+	}
+}
@@ -52,8 +52,10 @@ func ChooseExitDirection(seed model.Point, gridSize []int, dirBalance map[string
 	roll := rng.Float64() * totalWeight
 	cumulative := 0.0
 
-	for dir, weight := range weights {
-		cumulative += weight
+	// Walk the directions in a fixed order: map order would make the pick
+	// differ between runs with the same seed
+	for _, dir := range []string{"left", "right", "down", "up"} {
+		cumulative += weights[dir]
 		if roll < cumulative {
 			return dir
 		}
@@ -89,3 +89,225 @@ func fmtPoint(p model.Point) string {
 func fmtPointXY(x, y int) string {
 	return fmt.Sprintf("%d,%d", x, y)
 }
+
+// IncrementalSolver tracks which placed vines block each other as vines are
+// added, replaced or removed, so placers can reject a candidate that would make
+// the level unsolvable without re-solving the whole placement.
+//
+// A vine clears by sliding along its exit ray (head to grid edge) and can do so
+// once no other vine has a cell on that ray. Clearing only frees cells, so a
+// placement is solvable exactly when the "waits on" graph (A waits on B when B
+// has a cell on A's exit ray) has no cycle. CanPlace keeps that invariant: any
+// new cycle must pass through the candidate, so it only searches from the
+// vines the candidate waits on, touching the vines reachable from them rather
+// than the whole level.
+//
+// Only the main head is modelled. Tail heads are added after placement and
+// only give vines another way out, so an acyclic placement stays solvable.
+type IncrementalSolver struct {
+	w, h  int
+	owner []int   // cell -> slot of the vine occupying it, -1 when free
+	rays  [][]int // cell -> slots whose exit ray crosses the cell
+	slots []incrementalVine
+	byID  map[string]int
+	free  []int // released slots
+
+	// scratch for CanPlace
+	mark  []int
+	epoch int
+}
+
+type incrementalVine struct {
+	id    string
+	cells []int
+	ray   []int
+}
+
+// NewIncrementalSolver returns an empty solver for a w x h grid.
+func NewIncrementalSolver(w, h int) *IncrementalSolver {
+	s := &IncrementalSolver{
+		w:     w,
+		h:     h,
+		owner: make([]int, w*h),
+		rays:  make([][]int, w*h),
+		byID:  make(map[string]int),
+	}
+	for i := range s.owner {
+		s.owner[i] = -1
+	}
+	return s
+}
+
+// Reset replaces the tracked placement with vines.
+func (s *IncrementalSolver) Reset(vines []model.Vine) {
+	for i := range s.owner {
+		s.owner[i] = -1
+		s.rays[i] = s.rays[i][:0]
+	}
+	s.slots = s.slots[:0]
+	s.free = s.free[:0]
+	s.byID = make(map[string]int, len(vines))
+	for _, v := range vines {
+		s.Place(v)
+	}
+}
+
+// Len returns the number of placed vines.
+func (s *IncrementalSolver) Len() int {
+	return len(s.byID)
+}
+
+// Has reports whether a vine with the given ID is placed.
+func (s *IncrementalSolver) Has(id string) bool {
+	_, ok := s.byID[id]
+	return ok
+}
+
+// CanPlace reports whether v can be placed, replacing any placed vine with the
+// same ID, without creating a blocking cycle. It also returns false when v
+// leaves the grid or overlaps another vine. The solver is not modified.
+func (s *IncrementalSolver) CanPlace(v model.Vine) bool {
+	skip := -1
+	if slot, ok := s.byID[v.ID]; ok {
+		skip = slot
+	}
+
+	cells, ok := s.cellIndices(v)
+	if !ok {
+		return false
+	}
+	for _, c := range cells {
+		if o := s.owner[c]; o >= 0 && o != skip {
+			return false
+		}
+	}
+
+	// Vines whose exit ray crosses v wait on v
+	s.nextEpoch()
+	waiting := 0
+	for _, c := range cells {
+		for _, slot := range s.rays[c] {
+			if slot != skip && s.mark[slot] != s.epoch {
+				s.mark[slot] = s.epoch
+				waiting++
+			}
+		}
+	}
+	if waiting == 0 {
+		return true // nothing waits on v, so v cannot be on a cycle
+	}
+	target := s.epoch
+
+	// Search the vines v waits on for one that waits on v
+	s.nextEpoch()
+	var stack []int
+	for _, c := range s.exitRay(v) {
+		if o := s.owner[c]; o >= 0 && o != skip {
+			stack = append(stack, o)
+		}
+	}
+	for len(stack) > 0 {
+		slot := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		switch s.mark[slot] {
+		case target:
+			return false
+		case s.epoch:
+			continue
+		}
+		s.mark[slot] = s.epoch
+		for _, c := range s.slots[slot].ray {
+			if o := s.owner[c]; o >= 0 && o != slot && o != skip {
+				stack = append(stack, o)
+			}
+		}
+	}
+	return true
+}
+
+// Place records v, replacing any placed vine with the same ID. Callers keep the
+// placement solvable by checking CanPlace first.
+func (s *IncrementalSolver) Place(v model.Vine) {
+	s.Remove(v.ID)
+	cells, _ := s.cellIndices(v)
+
+	var slot int
+	if n := len(s.free); n > 0 {
+		slot = s.free[n-1]
+		s.free = s.free[:n-1]
+	} else {
+		slot = len(s.slots)
+		s.slots = append(s.slots, incrementalVine{})
+		s.mark = append(s.mark, 0)
+	}
+	s.slots[slot] = incrementalVine{id: v.ID, cells: cells, ray: s.exitRay(v)}
+	s.byID[v.ID] = slot
+
+	for _, c := range cells {
+		s.owner[c] = slot
+	}
+	for _, c := range s.slots[slot].ray {
+		s.rays[c] = append(s.rays[c], slot)
+	}
+}
+
+// Remove forgets the vine with the given ID, if placed.
+func (s *IncrementalSolver) Remove(id string) {
+	slot, ok := s.byID[id]
+	if !ok {
+		return
+	}
+	for _, c := range s.slots[slot].cells {
+		if s.owner[c] == slot {
+			s.owner[c] = -1
+		}
+	}
+	for _, c := range s.slots[slot].ray {
+		list := s.rays[c]
+		for i, o := range list {
+			if o == slot {
+				s.rays[c] = append(list[:i], list[i+1:]...)
+				break
+			}
+		}
+	}
+	s.slots[slot] = incrementalVine{}
+	delete(s.byID, id)
+	s.free = append(s.free, slot)
+}
+
+// cellIndices converts v's path to cell indices; ok is false if a cell is off the grid.
+func (s *IncrementalSolver) cellIndices(v model.Vine) ([]int, bool) {
+	cells := make([]int, 0, len(v.OrderedPath))
+	for _, p := range v.OrderedPath {
+		if p.X < 0 || p.X >= s.w || p.Y < 0 || p.Y >= s.h {
+			return nil, false
+		}
+		cells = append(cells, p.Y*s.w+p.X)
+	}
+	return cells, true
+}
+
+// exitRay returns the cells from just past v's head to the grid edge.
+func (s *IncrementalSolver) exitRay(v model.Vine) []int {
+	if len(v.OrderedPath) == 0 {
+		return nil
+	}
+	dx, dy := DeltaForDirection(v.HeadDirection)
+	if dx == 0 && dy == 0 {
+		return nil
+	}
+	var ray []int
+	head := v.OrderedPath[0]
+	for x, y := head.X+dx, head.Y+dy; x >= 0 && x < s.w && y >= 0 && y < s.h; x, y = x+dx, y+dy {
+		ray = append(ray, y*s.w+x)
+	}
+	return ray
+}
+
+func (s *IncrementalSolver) nextEpoch() {
+	if len(s.mark) < len(s.slots) {
+		s.mark = append(s.mark, make([]int, len(s.slots)-len(s.mark))...)
+	}
+	s.epoch++
+}
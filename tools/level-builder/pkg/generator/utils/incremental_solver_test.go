@@ -1,8 +1,11 @@
 package utils
 
 import (
+	"fmt"
+	"math/rand"
 	"testing"
 
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
 )
 
@@ -27,3 +30,113 @@ func TestIsLikelySolvablePartialBlocked(t *testing.T) {
 		t.Fatalf("expected circular blocking to be not likely solvable")
 	}
 }
+
+func TestIncrementalSolverRejectsFacingHeads(t *testing.T) {
+	s := NewIncrementalSolver(6, 1)
+	a := makeVine("A", "right", []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}})
+	b := makeVine("B", "left", []model.Point{{X: 4, Y: 0}, {X: 5, Y: 0}})
+	if !s.CanPlace(a) {
+		t.Fatalf("expected first vine to be placeable")
+	}
+	s.Place(a)
+	if s.CanPlace(b) {
+		t.Fatalf("expected facing heads to be rejected")
+	}
+	if s.Len() != 1 {
+		t.Fatalf("CanPlace must not modify the solver, got %d vines", s.Len())
+	}
+
+	// Overlaps and off-grid cells are rejected too
+	if s.CanPlace(makeVine("C", "right", []model.Point{{X: 1, Y: 0}})) {
+		t.Fatalf("expected overlapping vine to be rejected")
+	}
+	if s.CanPlace(makeVine("D", "right", []model.Point{{X: 6, Y: 0}})) {
+		t.Fatalf("expected off-grid vine to be rejected")
+	}
+}
+
+func TestIncrementalSolverReplaceAndRemove(t *testing.T) {
+	s := NewIncrementalSolver(6, 1)
+	a := makeVine("A", "right", []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}})
+	b := makeVine("B", "left", []model.Point{{X: 4, Y: 0}, {X: 5, Y: 0}})
+	s.Place(a)
+
+	// Replacing A with a vine that exits left breaks the cycle
+	turned := makeVine("A", "left", []model.Point{{X: 0, Y: 0}, {X: 1, Y: 0}})
+	if !s.CanPlace(turned) {
+		t.Fatalf("expected replacement of A to be placeable")
+	}
+	s.Place(turned)
+	if !s.CanPlace(b) {
+		t.Fatalf("expected B to be placeable once A exits left")
+	}
+
+	s.Place(a)
+	if s.CanPlace(b) {
+		t.Fatalf("expected B to be rejected after A turned back")
+	}
+	s.Remove("A")
+	if s.Has("A") || s.Len() != 0 {
+		t.Fatalf("expected A to be removed")
+	}
+	if !s.CanPlace(b) {
+		t.Fatalf("expected B to be placeable after removing A")
+	}
+}
+
+func TestIncrementalSolverMatchesGreedy(t *testing.T) {
+	const w, h = 7, 7
+	dirs := []model.Point{{X: 1, Y: 0}, {X: -1, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: -1}}
+	for seed := int64(1); seed <= 50; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		s := NewIncrementalSolver(w, h)
+		var placed []model.Vine
+		occ := make(map[model.Point]bool)
+
+		for attempt := 0; attempt < 60; attempt++ {
+			// Random walk of 2-4 free cells; the head direction follows the first segment
+			path := []model.Point{{X: rng.Intn(w), Y: rng.Intn(h)}}
+			if occ[path[0]] {
+				continue
+			}
+			for n := 2 + rng.Intn(3); len(path) < n; {
+				d := dirs[rng.Intn(len(dirs))]
+				next := model.Point{X: path[len(path)-1].X + d.X, Y: path[len(path)-1].Y + d.Y}
+				if next.X < 0 || next.X >= w || next.Y < 0 || next.Y >= h || occ[next] || containsPoint(path, next) {
+					break
+				}
+				path = append(path, next)
+			}
+			if len(path) < 2 {
+				continue
+			}
+			v, err := model.NewVine(fmt.Sprintf("v%d", attempt), path, "")
+			if err != nil {
+				continue
+			}
+
+			candidate := append(append([]model.Vine(nil), placed...), v)
+			lvl := model.Level{GridSize: []int{w, h}, Vines: candidate}
+			want := common.NewSolver(&lvl).IsSolvableGreedy()
+			if got := s.CanPlace(v); got != want {
+				t.Fatalf("seed %d: CanPlace(%s)=%v, greedy solver says %v", seed, v.ID, got, want)
+			}
+			if want {
+				s.Place(v)
+				placed = candidate
+				for _, p := range path {
+					occ[p] = true
+				}
+			}
+		}
+	}
+}
+
+func containsPoint(path []model.Point, p model.Point) bool {
+	for _, q := range path {
+		if q == p {
+			return true
+		}
+	}
+	return false
+}
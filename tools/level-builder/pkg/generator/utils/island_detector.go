@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"slices"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// IslandDetector tracks the cells of placed vines and finds dead cells: free
+// cells walled in on every side by vines or the grid border. A vine needs a
+// head and a neck, so no vine fits in a dead cell and no head can ever exit
+// through it; it can only be masked, or absorbed by stretching a neighbor.
+// Larger enclosed pockets still take filler vines, which exit once the vines
+// around them clear, so only single-cell pockets count.
+//
+// Placers ask WouldStrand before committing a vine, so they avoid making dead
+// cells instead of patching them over afterwards. A nil detector never
+// objects.
+type IslandDetector struct {
+	grid *common.Grid
+}
+
+// NewIslandDetector returns a detector for an empty w×h grid.
+func NewIslandDetector(w, h int) *IslandDetector {
+	return &IslandDetector{grid: common.NewGrid(w, h)}
+}
+
+// NewIslandDetectorFor returns a detector reading grid, which the caller
+// keeps up to date; Place and Remove write to it.
+func NewIslandDetectorFor(grid *common.Grid) *IslandDetector {
+	return &IslandDetector{grid: grid}
+}
+
+// NewIslandDetectorFromOccupancy returns a detector for a w×h grid whose
+// occupied cells are the keys of occupied ("x,y").
+func NewIslandDetectorFromOccupancy(w, h int, occupied map[string]string) *IslandDetector {
+	return &IslandDetector{grid: common.GridFromOccupancy(w, h, occupied)}
+}
+
+// Place marks the cells of v occupied.
+func (d *IslandDetector) Place(v model.Vine) {
+	if d != nil {
+		d.grid.SetPath(v.OrderedPath)
+	}
+}
+
+// Remove frees the cells of v.
+func (d *IslandDetector) Remove(v model.Vine) {
+	if d == nil {
+		return
+	}
+	for _, p := range v.OrderedPath {
+		d.grid.Unset(p.X, p.Y)
+	}
+}
+
+// WouldStrand returns how many free cells placing a vine over path would
+// turn into dead cells. Cells that are dead already are not counted.
+func (d *IslandDetector) WouldStrand(path []model.Point) int {
+	if d == nil {
+		return 0
+	}
+	stranded := 0
+	var checked []model.Point
+	for _, p := range path {
+		for _, n := range d.grid.FreeNeighbors(p, nil) {
+			if slices.Contains(path, n) || slices.Contains(checked, n) {
+				continue
+			}
+			checked = append(checked, n)
+			// n is free with a free neighbor in path, so it is not dead yet;
+			// it becomes dead when every free neighbor lies in path
+			dead := true
+			for _, m := range d.grid.FreeNeighbors(n, nil) {
+				if !slices.Contains(path, m) {
+					dead = false
+					break
+				}
+			}
+			if dead {
+				stranded++
+			}
+		}
+	}
+	return stranded
+}
+
+// DeadCells returns the free cells no vine fits in, row by row.
+func (d *IslandDetector) DeadCells() []model.Point {
+	if d == nil {
+		return nil
+	}
+	var dead []model.Point
+	for y := 0; y < d.grid.Height(); y++ {
+		for x := 0; x < d.grid.Width(); x++ {
+			if d.grid.IsFree(x, y) && d.grid.CountFreeNeighbors(x, y) == 0 {
+				dead = append(dead, model.Point{X: x, Y: y})
+			}
+		}
+	}
+	return dead
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func TestIslandDetectorWouldStrand(t *testing.T) {
+	d := NewIslandDetector(3, 3)
+	// A vine along the bottom row and up the right side leaves (0,1) with
+	// (1,1) and (0,2) free
+	d.Place(model.Vine{OrderedPath: []model.Point{{X: 2, Y: 2}, {X: 2, Y: 1}, {X: 2, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 0}}})
+	if dead := d.DeadCells(); len(dead) != 0 {
+		t.Fatalf("DeadCells() = %v, want none", dead)
+	}
+
+	// Covering (1,1) and (1,2) walls (0,1) and (0,2) into a two-cell pocket,
+	// which still takes a vine
+	if n := d.WouldStrand([]model.Point{{X: 1, Y: 2}, {X: 1, Y: 1}}); n != 0 {
+		t.Errorf("WouldStrand(two-cell pocket) = %d, want 0", n)
+	}
+	// Covering (1,1) and (0,1) leaves (0,2) with only (1,2) free, and that
+	// is not in the path
+	if n := d.WouldStrand([]model.Point{{X: 1, Y: 1}, {X: 0, Y: 1}}); n != 0 {
+		t.Errorf("WouldStrand(corner still open) = %d, want 0", n)
+	}
+	// Covering (1,2), (1,1) and (0,1) strands the (0,2) corner
+	if n := d.WouldStrand([]model.Point{{X: 1, Y: 2}, {X: 1, Y: 1}, {X: 0, Y: 1}}); n != 1 {
+		t.Errorf("WouldStrand(corner) = %d, want 1", n)
+	}
+
+	corner := model.Vine{OrderedPath: []model.Point{{X: 1, Y: 2}, {X: 1, Y: 1}, {X: 0, Y: 1}}}
+	d.Place(corner)
+	if dead := d.DeadCells(); len(dead) != 1 || dead[0] != (model.Point{X: 0, Y: 2}) {
+		t.Errorf("DeadCells() = %v, want [(0,2)]", dead)
+	}
+	d.Remove(corner)
+	if dead := d.DeadCells(); len(dead) != 0 {
+		t.Errorf("DeadCells() after Remove = %v, want none", dead)
+	}
+
+	// A nil detector never objects
+	var none *IslandDetector
+	none.Place(corner)
+	if n := none.WouldStrand(corner.OrderedPath); n != 0 || none.DeadCells() != nil {
+		t.Errorf("nil detector: WouldStrand = %d, DeadCells = %v", n, none.DeadCells())
+	}
+}
+
+func TestIslandDetectorFromOccupancy(t *testing.T) {
+	d := NewIslandDetectorFromOccupancy(3, 1, map[string]string{"0,0": "a", "2,0": "b"})
+	if dead := d.DeadCells(); len(dead) != 1 || dead[0] != (model.Point{X: 1, Y: 0}) {
+		t.Errorf("DeadCells() = %v, want [(1,0)]", dead)
+	}
+}
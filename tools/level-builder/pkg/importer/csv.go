@@ -0,0 +1,81 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// ParseCSV reads a spreadsheet grid with one cell per field.
+func ParseCSV(r io.Reader) (*Sketch, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	// Trailing blank lines come through as single empty fields
+	for len(rows) > 0 && len(rows[len(rows)-1]) == 1 && strings.TrimSpace(rows[len(rows)-1][0]) == "" {
+		rows = rows[:len(rows)-1]
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("the CSV has no rows")
+	}
+
+	s := newSketch(len(rows[0]), len(rows))
+	for row, fields := range rows {
+		y := s.Height - 1 - row
+		for x, field := range fields {
+			if err := s.addCSVCell(strings.TrimSpace(field), model.Point{X: x, Y: y}); err != nil {
+				return nil, fmt.Errorf("row %d, column %d: %w", row+1, x+1, err)
+			}
+		}
+	}
+	return s, nil
+}
+
+// addCSVCell records one field: "." or empty, or an ID with optional "*"
+// (head), "+" (tail head) and ":n" (position) markers.
+func (s *Sketch) addCSVCell(field string, p model.Point) error {
+	if field == "" || field == "." {
+		return nil
+	}
+
+	id, head, tail := field, false, false
+	for {
+		switch {
+		case strings.HasSuffix(id, "*"):
+			id, head = strings.TrimSuffix(id, "*"), true
+			continue
+		case strings.HasSuffix(id, "+"):
+			id, tail = strings.TrimSuffix(id, "+"), true
+			continue
+		}
+		break
+	}
+	if base, num, ok := strings.Cut(id, ":"); ok {
+		n, err := strconv.Atoi(num)
+		if err != nil || n < 0 {
+			return fmt.Errorf("%q: position must be a number from 0", field)
+		}
+		id = base
+		s.Order[p] = n
+		head = head || n == 0
+	}
+	if id == "" {
+		return fmt.Errorf("%q has no vine ID", field)
+	}
+	if head && tail {
+		return fmt.Errorf("%q marks both a head and a tail head", field)
+	}
+
+	s.add(id, p)
+	if head || tail {
+		return s.mark(id, p, tail)
+	}
+	return nil
+}
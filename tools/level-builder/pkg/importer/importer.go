@@ -0,0 +1,263 @@
+// Package importer converts levels drawn in other tools into level JSON.
+//
+// Every format is read into a Sketch: the grid size, the cells each vine
+// covers and which cell holds each vine's head. Build turns a sketch into a
+// finished level: it orders each vine's cells from its head, derives head
+// directions from the geometry, colors vines so touching vines differ, masks
+// the empty cells and sets the move budget from the solver's solution.
+//
+// Supported formats (rows run top to bottom, so the first row is the top of
+// the grid, y = height-1):
+//
+//   - csv: one cell per field. An empty field or "." is an empty cell; any
+//     other text is the ID of the vine covering it. A trailing "*" marks the
+//     vine's head and a trailing "+" its tail head, making it multi-head. A
+//     ":n" suffix pins the cell to position n along the vine (0 is the head).
+//   - tiled: a Tiled TMX map with CSV-encoded tile layers. Cells of the
+//     "vines" layer with the same tile belong to one vine (vine_<tile>); any
+//     tile on the "heads" layer marks a head, and on the optional "tails"
+//     layer a tail head. On the optional "order" layer, tile n of the map's
+//     first tileset pins the cell to position n.
+//
+// A vine's cells must allow exactly one path from its head. Cells folded
+// tightly enough to allow several (a U of four cells, say) need numbering.
+package importer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/metrics"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/utils"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/naming"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+// Formats lists the accepted format names.
+var Formats = []string{"csv", "tiled"}
+
+// Sketch is a level as drawn: which cells each vine covers and where its
+// heads are, before paths are ordered.
+type Sketch struct {
+	Width, Height int
+	// IDs lists vine IDs in the order they were first seen
+	IDs   []string
+	Cells map[string][]model.Point
+	Heads map[string]model.Point
+	// Tails marks the tail heads of multi-head vines
+	Tails map[string]model.Point
+	// Order pins cells to a position along their vine (0 is the head), for
+	// shapes that more than one path could run through
+	Order map[model.Point]int
+}
+
+func newSketch(w, h int) *Sketch {
+	return &Sketch{
+		Width:  w,
+		Height: h,
+		Cells:  make(map[string][]model.Point),
+		Heads:  make(map[string]model.Point),
+		Tails:  make(map[string]model.Point),
+		Order:  make(map[model.Point]int),
+	}
+}
+
+// add records that vine id covers p.
+func (s *Sketch) add(id string, p model.Point) {
+	if _, ok := s.Cells[id]; !ok {
+		s.IDs = append(s.IDs, id)
+	}
+	s.Cells[id] = append(s.Cells[id], p)
+}
+
+// mark records p as the head (or tail head) of vine id.
+func (s *Sketch) mark(id string, p model.Point, tail bool) error {
+	marks, what := s.Heads, "head"
+	if tail {
+		marks, what = s.Tails, "tail head"
+	}
+	if prev, ok := marks[id]; ok {
+		return fmt.Errorf("vine %s has two %ss, at (%d,%d) and (%d,%d)", id, what, prev.X, prev.Y, p.X, p.Y)
+	}
+	marks[id] = p
+	return nil
+}
+
+// Parse reads a sketch in the named format.
+func Parse(format string, r io.Reader) (*Sketch, error) {
+	switch format {
+	case "csv":
+		return ParseCSV(r)
+	case "tiled":
+		return ParseTMX(r)
+	}
+	return nil, fmt.Errorf("unknown format %q (want one of %s)", format, strings.Join(Formats, ", "))
+}
+
+// Options fill in the level fields a sketch does not carry.
+type Options struct {
+	ID         int
+	Name       string // default "Level <ID>"
+	Difficulty string // default Seedling
+	// MaxStates bounds the solver search that sets the move budget
+	MaxStates int
+}
+
+// Build converts s into a level, failing when a vine's cells do not form a
+// single path from its head, when the level breaks a structural rule or when
+// the solver cannot clear it.
+func Build(ctx context.Context, s *Sketch, opts Options) (*model.Level, error) {
+	if s.Width < 2 || s.Height < 2 {
+		return nil, fmt.Errorf("grid %dx%d is too small", s.Width, s.Height)
+	}
+	if len(s.IDs) == 0 {
+		return nil, fmt.Errorf("the grid has no vines")
+	}
+	if opts.Difficulty == "" {
+		opts.Difficulty = "Seedling"
+	}
+	name := naming.Level(opts.ID)
+	if opts.Name == "" {
+		opts.Name = naming.Render(name)
+	} else {
+		name = model.NameRef{Key: naming.CustomLevelKey(opts.ID)}
+	}
+
+	level := &model.Level{
+		ID:          opts.ID,
+		Name:        opts.Name,
+		NameRef:     &name,
+		Difficulty:  opts.Difficulty,
+		GridSize:    []int{s.Width, s.Height},
+		Complexity:  common.ComplexityForDifficulty(opts.Difficulty),
+		Grace:       utils.GraceForDifficulty(opts.Difficulty),
+		ColorScheme: slices.Clone(config.ColorPalette),
+	}
+	occupied := make(map[model.Point]bool)
+	for _, id := range s.IDs {
+		v, err := s.vine(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range v.OrderedPath {
+			occupied[p] = true
+		}
+		level.Vines = append(level.Vines, v)
+	}
+	utils.AssignColors(level.Vines, len(level.ColorScheme), nil)
+	level.Mask = model.NewOccupancyMask(model.MaskModeHide, s.Width, s.Height, func(x, y int) bool {
+		return occupied[model.Point{X: x, Y: y}]
+	})
+
+	if errs := append(validator.ValidateStructural(*level), validator.ValidateSelfBlocking(*level)...); len(errs) > 0 {
+		return nil, errs[0]
+	}
+	ok, solution, _, err := validator.SolveContext(ctx, *level, opts.MaxStates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to solve level: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("level is not solvable")
+	}
+	level.MinMoves = len(solution)
+	level.MaxMoves = max(config.MaxMovesFor(level.Difficulty, level.MinMoves), level.MinMoves)
+	basis := metrics.GraceBasisFor(*level, solution)
+	level.Grace = basis.Base + basis.Bonus
+	level.GraceBasis = &basis
+	return level, nil
+}
+
+// pathSearchBudget caps the cells the path search visits per vine.
+const pathSearchBudget = 1000000
+
+// vine orders vine id's cells into the one path that starts at its head,
+// honors any numbered cells and ends at its tail head when one is marked.
+func (s *Sketch) vine(id string) (model.Vine, error) {
+	cells := s.Cells[id]
+	head, ok := s.Heads[id]
+	if !ok {
+		return model.Vine{}, fmt.Errorf("vine %s has no head marked", id)
+	}
+	if len(cells) < 2 {
+		return model.Vine{}, fmt.Errorf("vine %s covers %d cell, need at least 2", id, len(cells))
+	}
+
+	set := make(map[model.Point]bool, len(cells))
+	at := make(map[int]model.Point) // numbered cells by position
+	for _, p := range cells {
+		set[p] = true
+		if i, ok := s.Order[p]; ok {
+			if i >= len(cells) {
+				return model.Vine{}, fmt.Errorf("vine %s: cell (%d,%d) is numbered %d but the vine has %d cells", id, p.X, p.Y, i, len(cells))
+			}
+			if q, dup := at[i]; dup {
+				return model.Vine{}, fmt.Errorf("vine %s: cells (%d,%d) and (%d,%d) are both numbered %d", id, q.X, q.Y, p.X, p.Y, i)
+			}
+			at[i] = p
+		}
+	}
+	if q, ok := at[0]; ok && q != head {
+		return model.Vine{}, fmt.Errorf("vine %s: cell (%d,%d) is numbered 0 but the head is at (%d,%d)", id, q.X, q.Y, head.X, head.Y)
+	}
+	tail, hasTail := s.Tails[id]
+	if hasTail {
+		at[len(cells)-1] = tail
+	}
+
+	// Depth-first search for every path through the cells, stopping at two
+	var found []model.Point
+	paths, steps := 0, 0
+	path := []model.Point{head}
+	seen := map[model.Point]bool{head: true}
+	var search func()
+	search = func() {
+		steps++
+		if paths > 1 || steps > pathSearchBudget {
+			return
+		}
+		if len(path) == len(cells) {
+			paths++
+			if found == nil {
+				found = slices.Clone(path)
+			}
+			return
+		}
+		tip := path[len(path)-1]
+		want, pinned := at[len(path)]
+		for _, n := range []model.Point{{X: tip.X + 1, Y: tip.Y}, {X: tip.X - 1, Y: tip.Y}, {X: tip.X, Y: tip.Y + 1}, {X: tip.X, Y: tip.Y - 1}} {
+			if !set[n] || seen[n] || (pinned && n != want) {
+				continue
+			}
+			if i, ok := s.Order[n]; ok && i != len(path) {
+				continue
+			}
+			seen[n] = true
+			path = append(path, n)
+			search()
+			path = path[:len(path)-1]
+			delete(seen, n)
+		}
+	}
+	search()
+
+	switch {
+	case steps > pathSearchBudget:
+		return model.Vine{}, fmt.Errorf("vine %s: too many ways through its cells; number them to fix the order", id)
+	case paths == 0:
+		return model.Vine{}, fmt.Errorf("vine %s: its cells do not form one path from the head at (%d,%d)", id, head.X, head.Y)
+	case paths > 1:
+		return model.Vine{}, fmt.Errorf("vine %s: more than one path runs through its cells from the head at (%d,%d); number them to fix the order", id, head.X, head.Y)
+	}
+
+	v, err := model.NewVine(id, found, "")
+	if err != nil || !hasTail {
+		return v, err
+	}
+	return v.WithTailHead(common.DirectionFromPoints(found[len(found)-2], tail))
+}
@@ -0,0 +1,124 @@
+package importer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func build(t *testing.T, format, src string) (*model.Level, error) {
+	t.Helper()
+	s, err := Parse(format, strings.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+	return Build(context.Background(), s, Options{ID: 7, MaxStates: 10000})
+}
+
+func vineByID(lvl *model.Level, id string) model.Vine {
+	for _, v := range lvl.Vines {
+		if v.ID == id {
+			return v
+		}
+	}
+	return model.Vine{}
+}
+
+func TestImportCSV(t *testing.T) {
+	lvl, err := build(t, "csv", "A*,A,A,B\nC,C,.,B*\nC*,D+,D,D*\n")
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if lvl.GridSize[0] != 4 || lvl.GridSize[1] != 3 || len(lvl.Vines) != 4 {
+		t.Fatalf("grid %v with %d vines", lvl.GridSize, len(lvl.Vines))
+	}
+
+	// The first row is the top of the grid
+	if a := vineByID(lvl, "A"); a.HeadDirection != "left" || a.OrderedPath[0] != (model.Point{X: 0, Y: 2}) {
+		t.Errorf("A = %+v", a)
+	}
+	if c := vineByID(lvl, "C"); c.HeadDirection != "down" || len(c.OrderedPath) != 3 {
+		t.Errorf("C = %+v", c)
+	}
+	if d := vineByID(lvl, "D"); d.HeadDirection != "right" || d.TailDirection != "left" {
+		t.Errorf("D = %+v, want head right and tail head left", d)
+	}
+	if lvl.Mask == nil || len(lvl.Mask.Points) != 1 || lvl.Mask.Points[0] != (model.Point{X: 2, Y: 1}) {
+		t.Errorf("mask = %+v, want only (2,1) hidden", lvl.Mask)
+	}
+	if lvl.MinMoves != 4 || lvl.MaxMoves < lvl.MinMoves {
+		t.Errorf("moves %d/%d", lvl.MinMoves, lvl.MaxMoves)
+	}
+	if vineByID(lvl, "A").ColorIndex == vineByID(lvl, "B").ColorIndex {
+		t.Error("touching vines A and B share a color")
+	}
+}
+
+func TestImportOrdering(t *testing.T) {
+	if _, err := build(t, "csv", "A*,A\n.,A\n.,A\nB*,B\n"); err != nil {
+		t.Errorf("unambiguous vine: %v", err)
+	}
+	// A U of four cells runs either way from its head
+	_, err := build(t, "csv", "A*,A\nA,A\nB*,B\n")
+	if err == nil || !strings.Contains(err.Error(), "more than one path") {
+		t.Fatalf("U-shaped vine: %v", err)
+	}
+	lvl, err := build(t, "csv", "A*,A\nA:1,A\nB*,B\n")
+	if err != nil {
+		t.Fatalf("numbered U-shaped vine: %v", err)
+	}
+	if a := vineByID(lvl, "A"); a.HeadDirection != "up" {
+		t.Errorf("numbered A = %+v, want the neck below the head", a)
+	}
+
+	for src, want := range map[string]string{
+		"A,A\nB*,B\n":       "no head",
+		"A*,A*\nB*,B\n":     "two heads",
+		"A*,.,A\nB*,B,B\n":  "do not form one path",
+		"A*,A,A\n.,A,.\n":   "do not form one path",
+		"A,A*,A\nB*,B,B\n":  "do not form one path",
+		"A*,A,A+\nB+,B,B\n": "no head",
+	} {
+		if _, err := build(t, "csv", src); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: error %v, want %q", src, err, want)
+		}
+	}
+}
+
+const tmx = `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="3" height="2" tilewidth="32" tileheight="32">
+ <tileset firstgid="1" source="vines.tsx"/>
+ <layer id="1" name="Vines" width="3" height="2">
+  <data encoding="csv">
+1,1,1,
+2,2,2147483650
+</data>
+ </layer>
+ <layer id="2" name="Heads" width="3" height="2">
+  <data encoding="csv">
+0,0,5,
+5,0,0
+</data>
+ </layer>
+</map>`
+
+func TestImportTiled(t *testing.T) {
+	lvl, err := build(t, "tiled", tmx)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if v := vineByID(lvl, "vine_1"); v.HeadDirection != "right" || v.OrderedPath[0] != (model.Point{X: 2, Y: 1}) {
+		t.Errorf("vine_1 = %+v", v)
+	}
+	// The flipped tile still belongs to vine_2
+	if v := vineByID(lvl, "vine_2"); v.HeadDirection != "left" || len(v.OrderedPath) != 3 {
+		t.Errorf("vine_2 = %+v", v)
+	}
+
+	base64 := strings.Replace(tmx, `encoding="csv"`, `encoding="base64"`, 1)
+	if _, err := build(t, "tiled", base64); err == nil {
+		t.Error("Parse accepted a base64 layer")
+	}
+}
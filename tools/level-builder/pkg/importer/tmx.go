@@ -0,0 +1,123 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// tiledFlipMask clears the flip and rotation flags Tiled stores in a GID's
+// high bits.
+const tiledFlipMask = 0x0FFFFFFF
+
+type tmxMap struct {
+	Width    int          `xml:"width,attr"`
+	Height   int          `xml:"height,attr"`
+	Tilesets []tmxTileset `xml:"tileset"`
+	Layers   []tmxLayer   `xml:"layer"`
+}
+
+type tmxTileset struct {
+	FirstGID int `xml:"firstgid,attr"`
+}
+
+type tmxLayer struct {
+	Name string `xml:"name,attr"`
+	Data struct {
+		Encoding string `xml:"encoding,attr"`
+		Text     string `xml:",chardata"`
+	} `xml:"data"`
+}
+
+// ParseTMX reads a Tiled map: the "vines" layer (or the only layer) gives
+// each cell's vine, "heads" and "tails" mark heads and "order" numbers cells.
+func ParseTMX(r io.Reader) (*Sketch, error) {
+	var m tmxMap
+	if err := xml.NewDecoder(r).Decode(&m); err != nil {
+		return nil, fmt.Errorf("failed to parse TMX: %w", err)
+	}
+	if m.Width < 1 || m.Height < 1 {
+		return nil, fmt.Errorf("TMX map has no size")
+	}
+
+	layers := make(map[string][]int)
+	for _, l := range m.Layers {
+		gids, err := l.gids(m.Width, m.Height)
+		if err != nil {
+			return nil, err
+		}
+		layers[strings.ToLower(l.Name)] = gids
+	}
+	vines, ok := layers["vines"]
+	if !ok && len(m.Layers) == 1 {
+		vines, ok = layers[strings.ToLower(m.Layers[0].Name)]
+	}
+	if !ok {
+		return nil, fmt.Errorf(`TMX map has no "vines" layer`)
+	}
+	firstGID := 1
+	if len(m.Tilesets) > 0 {
+		firstGID = m.Tilesets[0].FirstGID
+	}
+
+	s := newSketch(m.Width, m.Height)
+	for i, gid := range vines {
+		if gid == 0 {
+			continue
+		}
+		p := model.Point{X: i % m.Width, Y: m.Height - 1 - i/m.Width}
+		id := model.VineID(gid)
+		s.add(id, p)
+
+		if order := layers["order"]; order != nil && order[i] != 0 {
+			n := order[i] - firstGID
+			if n < 0 {
+				return nil, fmt.Errorf("order layer at (%d,%d): tile %d is not in the first tileset", p.X, p.Y, order[i])
+			}
+			s.Order[p] = n
+		}
+		if heads := layers["heads"]; heads != nil && heads[i] != 0 {
+			if err := s.mark(id, p, false); err != nil {
+				return nil, err
+			}
+		}
+		if tails := layers["tails"]; tails != nil && tails[i] != 0 {
+			if err := s.mark(id, p, true); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, layer := range []string{"heads", "tails", "order"} {
+		for i, gid := range layers[layer] {
+			if gid != 0 && vines[i] == 0 {
+				return nil, fmt.Errorf("%s layer marks (%d,%d), which has no vine", layer, i%m.Width, m.Height-1-i/m.Width)
+			}
+		}
+	}
+	return s, nil
+}
+
+// gids decodes a CSV-encoded layer into width*height GIDs, row by row from
+// the top.
+func (l tmxLayer) gids(w, h int) ([]int, error) {
+	if l.Data.Encoding != "csv" {
+		return nil, fmt.Errorf("layer %q uses %q encoding; save the map with CSV layer format", l.Name, l.Data.Encoding)
+	}
+	fields := strings.FieldsFunc(l.Data.Text, func(r rune) bool { return r == ',' || r == '\n' || r == '\r' || r == ' ' || r == '\t' })
+	if len(fields) != w*h {
+		return nil, fmt.Errorf("layer %q has %d tiles, want %d", l.Name, len(fields), w*h)
+	}
+	gids := make([]int, len(fields))
+	for i, f := range fields {
+		n, err := strconv.ParseUint(f, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("layer %q: bad tile %q", l.Name, f)
+		}
+		gids[i] = int(n & tiledFlipMask)
+	}
+	return gids, nil
+}
@@ -0,0 +1,162 @@
+// Package lessons builds tutorial lessons from fixed teaching patterns. Each
+// pattern lays out a handful of vines on a small square grid (the game reads
+// lesson grid_size as [rows, cols], so square grids read the same either way)
+// and carries the instructional text the game shows alongside it.
+package lessons
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+// UnlimitedMoves is the max_moves written to lessons; the game does not
+// enforce a move budget while teaching.
+const UnlimitedMoves = 999
+
+// solveMaxStates bounds the solvability check; lessons are a few vines.
+const solveMaxStates = 10000
+
+// Pattern is a templated teaching layout.
+type Pattern struct {
+	Name           string
+	Title          string // shown after "Lesson N: "
+	Objective      string
+	Instructions   string
+	LearningPoints []string
+	GridSize       int
+	// Vines are given as head-first paths; head directions are derived
+	Vines [][]model.Point
+	// Order is the clear order the lesson teaches, by vine index
+	Order []int
+}
+
+// Patterns lists the teaching patterns; lesson N defaults to Patterns[N-1].
+var Patterns = []Pattern{
+	{
+		Name:           "straight",
+		Title:          "Clear the Vine",
+		Objective:      "Learn to select and clear a single vine",
+		Instructions:   "Tap the vine to slide it out the way its head points.",
+		LearningPoints: []string{"Vines leave head first", "Clear every vine to finish"},
+		GridSize:       4,
+		Vines: [][]model.Point{
+			{{X: 3, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 1}, {X: 0, Y: 1}},
+		},
+		Order: []int{0},
+	},
+	{
+		Name:           "block",
+		Title:          "One Vine Blocks Another",
+		Objective:      "Learn that a vine in the way must be cleared first",
+		Instructions:   "One vine's head points into another vine. Clear the blocker first.",
+		LearningPoints: []string{"Look where each head points", "Clear the blocker first"},
+		GridSize:       5,
+		Vines: [][]model.Point{
+			{{X: 3, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 1}, {X: 0, Y: 1}},
+			{{X: 4, Y: 0}, {X: 4, Y: 1}, {X: 4, Y: 2}},
+		},
+		Order: []int{1, 0},
+	},
+	{
+		Name:           "lifo-pair",
+		Title:          "Stacked Vines",
+		Objective:      "Learn that the vine in front leaves before the one behind it",
+		Instructions:   "Two vines share a row. Clear the front vine, then the one behind it.",
+		LearningPoints: []string{"The front vine leaves first", "Last in, first out"},
+		GridSize:       5,
+		Vines: [][]model.Point{
+			{{X: 1, Y: 2}, {X: 0, Y: 2}},
+			{{X: 4, Y: 2}, {X: 3, Y: 2}, {X: 2, Y: 2}},
+		},
+		Order: []int{1, 0},
+	},
+}
+
+// PatternNames returns the pattern names in lesson order.
+func PatternNames() []string {
+	names := make([]string, len(Patterns))
+	for i, p := range Patterns {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// Find returns the named pattern.
+func Find(name string) (Pattern, error) {
+	for _, p := range Patterns {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return Pattern{}, fmt.Errorf("unknown pattern %q (want one of %s)", name, strings.Join(PatternNames(), ", "))
+}
+
+// ForLesson returns the default pattern for lesson id.
+func ForLesson(id int) (Pattern, error) {
+	if id < 1 || id > len(Patterns) {
+		return Pattern{}, fmt.Errorf("no default pattern for lesson %d; pick one with --pattern: %s", id, strings.Join(PatternNames(), ", "))
+	}
+	return Patterns[id-1], nil
+}
+
+// Generate builds lesson id from the pattern as a guided lesson, one step per
+// move of the taught order, and checks it: the lesson rules from
+// validator.ValidateLesson (which also require the taught order to be the only
+// one), the structural rules and solvability.
+func Generate(id int, p Pattern) (model.Lesson, error) {
+	key := fmt.Sprintf("lesson%d", id)
+	lesson := model.Lesson{
+		ID:             id,
+		Title:          fmt.Sprintf("Lesson %d: %s", id, p.Title),
+		Objective:      p.Objective,
+		Instructions:   p.Instructions,
+		LearningPoints: append([]string(nil), p.LearningPoints...),
+		TextKeys: model.LessonTextKeys{
+			Title:        key + "Title",
+			Objective:    key + "Objective",
+			Instructions: key + "Instructions",
+		},
+		GridSize:            []int{p.GridSize, p.GridSize},
+		MaxMoves:            UnlimitedMoves,
+		AllowedInteractions: []string{model.InteractionTap, model.InteractionRestart},
+	}
+	for i, path := range p.Vines {
+		v, err := model.NewVine(fmt.Sprintf("vine_%d", i+1), path, "")
+		if err != nil {
+			return model.Lesson{}, fmt.Errorf("pattern %s: %w", p.Name, err)
+		}
+		lesson.Vines = append(lesson.Vines, v)
+	}
+	for n, idx := range p.Order {
+		if idx < 0 || idx >= len(lesson.Vines) {
+			return model.Lesson{}, fmt.Errorf("pattern %s: taught order names vine %d of %d", p.Name, idx, len(lesson.Vines))
+		}
+		lesson.Steps = append(lesson.Steps, model.LessonStep{
+			Vine:    lesson.Vines[idx].ID,
+			TextKey: fmt.Sprintf("%sStep%d", key, n+1),
+		})
+	}
+	// The first move is the one the player needs pointing at
+	if len(lesson.Steps) > 0 {
+		lesson.HighlightVines = []string{lesson.Steps[0].Vine}
+	}
+
+	if err := validator.ValidateLesson(lesson); err != nil {
+		return model.Lesson{}, fmt.Errorf("pattern %s: %w", p.Name, err)
+	}
+	lvl := lesson.Level()
+	if errs := validator.ValidateStructural(lvl); len(errs) > 0 {
+		return model.Lesson{}, fmt.Errorf("pattern %s: %w", p.Name, errs[0])
+	}
+	ok, _, _, err := validator.Solve(lvl, solveMaxStates)
+	if err != nil {
+		return model.Lesson{}, fmt.Errorf("pattern %s: %w", p.Name, err)
+	}
+	if !ok {
+		return model.Lesson{}, fmt.Errorf("pattern %s is not solvable", p.Name)
+	}
+	return lesson, nil
+}
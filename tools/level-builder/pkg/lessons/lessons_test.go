@@ -0,0 +1,59 @@
+package lessons
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+func TestGeneratePatterns(t *testing.T) {
+	for i, p := range Patterns {
+		lesson, err := Generate(i+1, p)
+		if err != nil {
+			t.Errorf("%s: %v", p.Name, err)
+			continue
+		}
+		if lesson.MaxMoves != UnlimitedMoves || len(lesson.Vines) != len(p.Vines) {
+			t.Errorf("%s: got %d vines, max_moves %d", p.Name, len(lesson.Vines), lesson.MaxMoves)
+		}
+		if !lesson.Guided() || len(lesson.Steps) != len(p.Order) || lesson.TextKeys.Title == "" {
+			t.Errorf("%s: got %d steps, text keys %+v", p.Name, len(lesson.Steps), lesson.TextKeys)
+		}
+		if !strings.HasPrefix(lesson.Title, "Lesson ") {
+			t.Errorf("%s: title %q", p.Name, lesson.Title)
+		}
+		if ok, _, _, _ := validator.Solve(lesson.Level(), solveMaxStates); !ok {
+			t.Errorf("%s: not solvable", p.Name)
+		}
+	}
+}
+
+func TestGenerateRejectsWrongOrder(t *testing.T) {
+	p, err := Find("block")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Order = []int{0, 1} // the blocked vine cannot go first
+	if _, err := Generate(2, p); err == nil {
+		t.Error("expected the blocked-first order to be rejected")
+	}
+
+	p, _ = Find("straight")
+	p.Title = strings.Repeat("x", validator.MaxLessonTitle)
+	if _, err := Generate(1, p); err == nil || !strings.Contains(err.Error(), "title") {
+		t.Errorf("expected an over-long title to be rejected, got %v", err)
+	}
+}
+
+func TestForLesson(t *testing.T) {
+	if p, err := ForLesson(3); err != nil || p.Name != "lifo-pair" {
+		t.Errorf("ForLesson(3) = %s, %v", p.Name, err)
+	}
+	if _, err := ForLesson(len(Patterns) + 1); err == nil {
+		t.Error("expected no default pattern past the last one")
+	}
+	if _, err := Find("spiral"); err == nil {
+		t.Error("expected an unknown pattern to be rejected")
+	}
+}
@@ -0,0 +1,336 @@
+// Package leveldiff compares two versions of a level, or two directories of
+// generated levels, so designers can see what a regeneration actually changed.
+package leveldiff
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/utils"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+// FieldChange is a level-wide property that differs between A and B.
+type FieldChange struct {
+	Field string
+	A, B  string
+}
+
+// VineChange lists what differs for a vine present in both levels.
+type VineChange struct {
+	ID      string
+	Changes []string // human-readable, e.g. "path 4 -> 6 cells"
+}
+
+// SolverSummary captures solver behaviour for one side of a diff.
+type SolverSummary struct {
+	Solvable         bool
+	Solver           string
+	StatesExplored   int
+	SolutionLength   int
+	MaxBlockingDepth int
+	Error            string
+}
+
+// LevelDiff describes how level B differs from level A.
+type LevelDiff struct {
+	A, B         *model.Level
+	Fields       []FieldChange
+	AddedVines   []string // in B only
+	RemovedVines []string // in A only
+	ChangedVines []VineChange
+	MaskAdded    []model.Point // masked in B only
+	MaskRemoved  []model.Point // masked in A only
+	SolverA      SolverSummary
+	SolverB      SolverSummary
+}
+
+// Identical reports whether the two levels have no structural differences.
+// Solver stats are derived from structure and are not compared.
+func (d LevelDiff) Identical() bool {
+	return len(d.Fields) == 0 && len(d.AddedVines) == 0 && len(d.RemovedVines) == 0 &&
+		len(d.ChangedVines) == 0 && len(d.MaskAdded) == 0 && len(d.MaskRemoved) == 0
+}
+
+// Compare diffs two levels. Vines are matched by ID. Each side is solved with
+// a budget of maxStates so solver effort can be compared.
+func Compare(a, b *model.Level, maxStates int) LevelDiff {
+	d := LevelDiff{A: a, B: b}
+
+	field := func(name string, va, vb interface{}) {
+		sa, sb := fmt.Sprint(va), fmt.Sprint(vb)
+		if sa != sb {
+			d.Fields = append(d.Fields, FieldChange{Field: name, A: sa, B: sb})
+		}
+	}
+	field("id", a.ID, b.ID)
+	field("difficulty", a.Difficulty, b.Difficulty)
+	field("grid_size", gridSize(a), gridSize(b))
+	field("vine_count", len(a.Vines), len(b.Vines))
+	field("occupied_cells", a.GetOccupiedCells(), b.GetOccupiedCells())
+	field("max_moves", a.MaxMoves, b.MaxMoves)
+	field("min_moves", a.MinMoves, b.MinMoves)
+	field("grace", a.Grace, b.Grace)
+	field("color_scheme", a.ColorScheme, b.ColorScheme)
+	field("mask_mode", maskMode(a), maskMode(b))
+	field("portals", portalList(a), portalList(b))
+	field("hints", a.Hints, b.Hints)
+	field("solution_order", a.SolutionOrder, b.SolutionOrder)
+
+	d.compareVines(a, b)
+	d.MaskAdded, d.MaskRemoved = pointSetDiff(maskPoints(a), maskPoints(b))
+	d.SolverA = summarize(a, maxStates)
+	d.SolverB = summarize(b, maxStates)
+	return d
+}
+
+func (d *LevelDiff) compareVines(a, b *model.Level) {
+	inA := make(map[string]model.Vine, len(a.Vines))
+	for _, v := range a.Vines {
+		inA[v.ID] = v
+	}
+	inB := make(map[string]model.Vine, len(b.Vines))
+	for _, v := range b.Vines {
+		inB[v.ID] = v
+		if _, ok := inA[v.ID]; !ok {
+			d.AddedVines = append(d.AddedVines, v.ID)
+		}
+	}
+
+	for _, va := range a.Vines {
+		vb, ok := inB[va.ID]
+		if !ok {
+			d.RemovedVines = append(d.RemovedVines, va.ID)
+			continue
+		}
+
+		var changes []string
+		if va.HeadDirection != vb.HeadDirection {
+			changes = append(changes, fmt.Sprintf("head %s -> %s", va.HeadDirection, vb.HeadDirection))
+		}
+		if va.TailDirection != vb.TailDirection {
+			changes = append(changes, fmt.Sprintf("tail head %s -> %s", orNone(va.TailDirection), orNone(vb.TailDirection)))
+		}
+		if va.LockedUntil != vb.LockedUntil {
+			changes = append(changes, fmt.Sprintf("locked until %d -> %d clears", va.LockedUntil, vb.LockedUntil))
+		}
+		if !samePath(va.OrderedPath, vb.OrderedPath) {
+			change := fmt.Sprintf("path %d -> %d cells", len(va.OrderedPath), len(vb.OrderedPath))
+			if len(va.OrderedPath) > 0 && len(vb.OrderedPath) > 0 && va.OrderedPath[0] != vb.OrderedPath[0] {
+				change += fmt.Sprintf(", head at %s -> %s", common.PointKey(va.OrderedPath[0]), common.PointKey(vb.OrderedPath[0]))
+			}
+			changes = append(changes, change)
+		}
+		if va.ColorIndex != vb.ColorIndex {
+			changes = append(changes, fmt.Sprintf("color %d -> %d", va.ColorIndex, vb.ColorIndex))
+		}
+		if len(changes) > 0 {
+			d.ChangedVines = append(d.ChangedVines, VineChange{ID: va.ID, Changes: changes})
+		}
+	}
+}
+
+func summarize(level *model.Level, maxStates int) SolverSummary {
+	ok, solution, stats, err := validator.Solve(*level, maxStates)
+	s := SolverSummary{
+		Solvable:         ok,
+		Solver:           stats.Solver,
+		StatesExplored:   stats.StatesExplored,
+		SolutionLength:   len(solution),
+		MaxBlockingDepth: utils.MaxBlockingDepth(utils.BuildLevelBlockingGraph(level)),
+	}
+	if err != nil {
+		s.Error = err.Error()
+	}
+	return s
+}
+
+// WriteText prints the diff in a compact, line-oriented form.
+func WriteText(w io.Writer, d LevelDiff) {
+	if d.Identical() {
+		_, _ = fmt.Fprintln(w, "No structural differences.")
+	}
+
+	for _, f := range d.Fields {
+		_, _ = fmt.Fprintf(w, "~ %-15s %s -> %s\n", f.Field, f.A, f.B)
+	}
+	for _, id := range d.RemovedVines {
+		_, _ = fmt.Fprintf(w, "- vine %s\n", id)
+	}
+	for _, id := range d.AddedVines {
+		_, _ = fmt.Fprintf(w, "+ vine %s\n", id)
+	}
+	for _, c := range d.ChangedVines {
+		_, _ = fmt.Fprintf(w, "~ vine %s: %s\n", c.ID, strings.Join(c.Changes, "; "))
+	}
+	if len(d.MaskAdded) > 0 || len(d.MaskRemoved) > 0 {
+		_, _ = fmt.Fprintf(w, "~ mask            -%d +%d points\n", len(d.MaskRemoved), len(d.MaskAdded))
+	}
+
+	_, _ = fmt.Fprintf(w, "\n%-3s %-9s %-14s %-8s %-9s %s\n", "", "Solvable", "Solver", "States", "Solution", "MaxDepth")
+	for _, row := range []struct {
+		label string
+		s     SolverSummary
+	}{{"A", d.SolverA}, {"B", d.SolverB}} {
+		_, _ = fmt.Fprintf(w, "%-3s %-9v %-14s %-8d %-9d %d\n",
+			row.label, row.s.Solvable, row.s.Solver, row.s.StatesExplored, row.s.SolutionLength, row.s.MaxBlockingDepth)
+	}
+}
+
+// RunDiff pairs level files from two generation output directories by file name.
+type RunDiff struct {
+	OnlyInA []string
+	OnlyInB []string
+	Levels  []NamedDiff // levels present in both, in file-name order
+}
+
+// NamedDiff is a LevelDiff tagged with the file it came from.
+type NamedDiff struct {
+	Name string
+	LevelDiff
+}
+
+// CompareDirs diffs every level_*.json present in both directories and lists
+// files found on only one side.
+func CompareDirs(dirA, dirB string, maxStates int) (RunDiff, error) {
+	filesA, err := levelFiles(dirA)
+	if err != nil {
+		return RunDiff{}, err
+	}
+	filesB, err := levelFiles(dirB)
+	if err != nil {
+		return RunDiff{}, err
+	}
+
+	var run RunDiff
+	for _, name := range sortedKeys(filesA) {
+		if !filesB[name] {
+			run.OnlyInA = append(run.OnlyInA, name)
+			continue
+		}
+		a, err := common.ReadLevel(filepath.Join(dirA, name))
+		if err != nil {
+			return RunDiff{}, err
+		}
+		b, err := common.ReadLevel(filepath.Join(dirB, name))
+		if err != nil {
+			return RunDiff{}, err
+		}
+		run.Levels = append(run.Levels, NamedDiff{Name: name, LevelDiff: Compare(a, b, maxStates)})
+	}
+	for _, name := range sortedKeys(filesB) {
+		if !filesA[name] {
+			run.OnlyInB = append(run.OnlyInB, name)
+		}
+	}
+	return run, nil
+}
+
+func levelFiles(dir string) (map[string]bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", dir, err)
+	}
+	files := make(map[string]bool)
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), "level_") && filepath.Ext(e.Name()) == ".json" {
+			files[e.Name()] = true
+		}
+	}
+	return files, nil
+}
+
+// sortedKeys orders level file names by numeric level ID where possible.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		var a, b int
+		_, errA := fmt.Sscanf(keys[i], "level_%d.json", &a)
+		_, errB := fmt.Sscanf(keys[j], "level_%d.json", &b)
+		if errA == nil && errB == nil && a != b {
+			return a < b
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+func gridSize(l *model.Level) string {
+	return fmt.Sprintf("%dx%d", l.GetGridWidth(), l.GetGridHeight())
+}
+
+// portalList renders a level's portal pairs as "a<->b" in file order.
+func portalList(l *model.Level) string {
+	if len(l.Portals) == 0 {
+		return "none"
+	}
+	pairs := make([]string, len(l.Portals))
+	for i, pt := range l.Portals {
+		pairs[i] = common.PointKey(pt.A) + "<->" + common.PointKey(pt.B)
+	}
+	return strings.Join(pairs, " ")
+}
+
+func maskMode(l *model.Level) string {
+	if l.Mask == nil {
+		return "none"
+	}
+	return l.Mask.Mode
+}
+
+func maskPoints(l *model.Level) []model.Point {
+	if l.Mask == nil {
+		return nil
+	}
+	return l.Mask.Points
+}
+
+func samePath(a, b []model.Point) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// pointSetDiff returns the points only in b (added) and only in a (removed).
+func pointSetDiff(a, b []model.Point) (added, removed []model.Point) {
+	inA := make(map[model.Point]bool, len(a))
+	for _, p := range a {
+		inA[p] = true
+	}
+	inB := make(map[model.Point]bool, len(b))
+	for _, p := range b {
+		inB[p] = true
+		if !inA[p] {
+			added = append(added, p)
+		}
+	}
+	for _, p := range a {
+		if !inB[p] {
+			removed = append(removed, p)
+		}
+	}
+	return added, removed
+}
+
+// orNone returns s, or "none" when it is empty.
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
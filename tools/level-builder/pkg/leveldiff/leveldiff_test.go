@@ -0,0 +1,102 @@
+package leveldiff
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func baseLevel() *model.Level {
+	return &model.Level{
+		ID:       5,
+		GridSize: []int{4, 3},
+		Grace:    3,
+		Vines: []model.Vine{
+			{ID: "a", HeadDirection: "right", OrderedPath: []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}},
+			{ID: "b", HeadDirection: "up", OrderedPath: []model.Point{{X: 3, Y: 1}, {X: 3, Y: 0}}},
+		},
+		Mask: &model.Mask{Mode: "hide", Points: []model.Point{{X: 0, Y: 2}}},
+	}
+}
+
+func TestCompareIdentical(t *testing.T) {
+	d := Compare(baseLevel(), baseLevel(), 1000)
+	if !d.Identical() {
+		t.Fatalf("expected identical levels, got %+v", d)
+	}
+	if !d.SolverA.Solvable || d.SolverA.SolutionLength != 2 {
+		t.Errorf("unexpected solver summary %+v", d.SolverA)
+	}
+}
+
+func TestCompareReportsChanges(t *testing.T) {
+	a, b := baseLevel(), baseLevel()
+	b.GridSize = []int{5, 3}
+	b.Vines[0].OrderedPath = []model.Point{{X: 2, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 0}}
+	b.Vines[1] = model.Vine{ID: "c", HeadDirection: "down", OrderedPath: []model.Point{{X: 4, Y: 1}, {X: 4, Y: 2}}}
+	b.Mask.Points = []model.Point{{X: 1, Y: 2}}
+
+	d := Compare(a, b, 1000)
+	if d.Identical() {
+		t.Fatal("expected differences")
+	}
+	if len(d.Fields) == 0 || d.Fields[0].Field != "grid_size" || d.Fields[0].A != "4x3" || d.Fields[0].B != "5x3" {
+		t.Errorf("expected grid_size change first, got %+v", d.Fields)
+	}
+	if len(d.AddedVines) != 1 || d.AddedVines[0] != "c" || len(d.RemovedVines) != 1 || d.RemovedVines[0] != "b" {
+		t.Errorf("added %v removed %v", d.AddedVines, d.RemovedVines)
+	}
+	if len(d.ChangedVines) != 1 || !strings.Contains(d.ChangedVines[0].Changes[0], "path 2 -> 3 cells") {
+		t.Errorf("changed vines %+v", d.ChangedVines)
+	}
+	if len(d.MaskAdded) != 1 || len(d.MaskRemoved) != 1 {
+		t.Errorf("mask added %v removed %v", d.MaskAdded, d.MaskRemoved)
+	}
+
+	var buf bytes.Buffer
+	WriteText(&buf, d)
+	for _, want := range []string{"grid_size", "+ vine c", "- vine b", "~ vine a", "mask"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("text output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestCompareDirs(t *testing.T) {
+	dirA, dirB := t.TempDir(), t.TempDir()
+	write := func(dir, name string, level *model.Level) {
+		if err := common.WriteLevel(filepath.Join(dir, name), level, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	changed := baseLevel()
+	changed.Grace = 4
+	write(dirA, "level_5.json", baseLevel())
+	write(dirB, "level_5.json", changed)
+	write(dirA, "level_10.json", baseLevel())
+	write(dirA, "level_9.json", baseLevel())
+	write(dirB, "level_9.json", baseLevel())
+	write(dirB, "level_11.json", baseLevel())
+	if err := os.WriteFile(filepath.Join(dirB, "generation_metadata.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	run, err := CompareDirs(dirA, dirB, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(run.Levels) != 2 || run.Levels[0].Name != "level_5.json" || run.Levels[1].Name != "level_9.json" {
+		t.Fatalf("expected level_5 then level_9 compared, got %+v", run.Levels)
+	}
+	if run.Levels[0].Identical() || !run.Levels[1].Identical() {
+		t.Error("only level_5 should differ")
+	}
+	if len(run.OnlyInA) != 1 || run.OnlyInA[0] != "level_10.json" || len(run.OnlyInB) != 1 || run.OnlyInB[0] != "level_11.json" {
+		t.Errorf("only in A %v, only in B %v", run.OnlyInA, run.OnlyInB)
+	}
+}
@@ -0,0 +1,87 @@
+package levelgen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/leveldiff"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden levels in testdata/golden from the current generator")
+
+// goldenCases pin the generator's output for fixed (seed, difficulty) pairs across the
+// placement strategies, so a change to any placer shows up as a golden diff.
+var goldenCases = []struct {
+	name string
+	opts GenerateOptions
+}{
+	{"seedling_default", GenerateOptions{LevelID: 1, Difficulty: "Seedling"}},
+	{"seedling_center_out", GenerateOptions{LevelID: 2, Difficulty: "Seedling", Seed: 42, Strategy: config.StrategyCenterOut}},
+	{"seedling_direction_first", GenerateOptions{LevelID: 3, Difficulty: "Seedling", Seed: 7, Strategy: config.StrategyDirectionFirst}},
+	{"sprout_full_coverage", GenerateOptions{LevelID: 4, Difficulty: "Sprout", Seed: 9, Strategy: config.StrategyFullCoverage}},
+	{"sprout_legacy_tiling", GenerateOptions{LevelID: 5, Difficulty: "Sprout", Seed: 19, Strategy: config.StrategyLegacyTiling}},
+	{"sprout_legacy_solver", GenerateOptions{LevelID: 6, Difficulty: "Sprout", Seed: 23, Strategy: config.StrategyLegacySolverAware}},
+	{"nurturing_portals_hints", GenerateOptions{LevelID: 7, Difficulty: "Nurturing", Seed: 11, Portals: true, Hints: 3}},
+	{"flourishing_default", GenerateOptions{LevelID: 8, Difficulty: "Flourishing", Seed: 13}},
+	{"flourishing_inject_difficulty", GenerateOptions{LevelID: 8, Difficulty: "Flourishing", Seed: 13, InjectDifficulty: true}},
+	{"transcendent_default", GenerateOptions{LevelID: 9, Difficulty: "Transcendent", Seed: 17}},
+}
+
+// TestGolden regenerates each golden case and compares it with the committed level.
+// After an intended generator change, rewrite the goldens with
+//
+//	go test ./pkg/levelgen -run TestGolden -update
+//
+// and review the JSON diff before committing it.
+func TestGolden(t *testing.T) {
+	for _, tc := range goldenCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := tc.opts
+			opts.DumpDir = t.TempDir()
+			level, _, err := Generate(context.Background(), opts)
+			if err != nil {
+				t.Fatalf("Generate: %v", err)
+			}
+			// Wall-clock time is the only field that varies between runs
+			level.GenerationElapsedMS = 0
+			got, err := json.MarshalIndent(level, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			path := filepath.Join("testdata", "golden", tc.name+".json")
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("missing golden level (run with -update to create it): %v", err)
+			}
+			if bytes.Equal(got, want) {
+				return
+			}
+			var golden model.Level
+			if err := json.Unmarshal(want, &golden); err != nil {
+				t.Fatalf("unreadable golden level %s: %v", path, err)
+			}
+			var diff bytes.Buffer
+			leveldiff.WriteText(&diff, leveldiff.Compare(&golden, &level, 10000))
+			t.Errorf("generated level differs from %s (A = golden, B = generated); rerun with -update if the change is intended:\n%s", path, diff.String())
+		})
+	}
+}
@@ -0,0 +1,593 @@
+// Package levelgen is the stable Go API for generating a single level. It runs
+// the same strategy chain, validation and difficulty calibration as the batch
+// command, but never writes files or prints, so other Go services can embed the
+// generator without shelling out to the CLI.
+//
+//	level, stats, err := levelgen.Generate(ctx, levelgen.GenerateOptions{
+//		LevelID:    7,
+//		Difficulty: "Sprout",
+//		OnProgress: func(e levelgen.Event) { log.Println(e.Message) },
+//	})
+package levelgen
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/constraints"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/metrics"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/random"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/utils"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+// DefaultMaxRetriesPerStrategy is the attempt budget for each strategy in the chain.
+const DefaultMaxRetriesPerStrategy = 20
+
+// constraintSolveBudget bounds the solve behind constraints on solution length.
+const constraintSolveBudget = 1000000
+
+// raceSeedStride separates the base seeds of racers, well clear of the seeds
+// one racer's retries derive from its base.
+const raceSeedStride = 1000003
+
+// DefaultSeed returns the base seed used for levelID when GenerateOptions.Seed is unset.
+func DefaultSeed(levelID int) int64 {
+	return int64(levelID) * 31337
+}
+
+// GenerateOptions configures Generate.
+type GenerateOptions struct {
+	LevelID    int
+	Difficulty string // Difficulty tier (Seedling, Sprout, ..., Transcendent)
+	Seed       int64  // Base seed (0 = DefaultSeed(LevelID))
+	// Strategy is tried first; center-out is appended as a fallback when it differs.
+	// Empty uses legacy-clearable.
+	Strategy       string
+	FillerStrategy string  // Gap filler for placers that support it (default "lifo")
+	MaskMode       string  // Mask mode for unfilled cells: "hide" (default) or "show"
+	Portals        bool    // Add portal pairs on tiers that allow them (Nurturing and up)
+	Hints          int     // Solution moves to embed as level hints (0 = none)
+	MinCoverage    float64 // Minimum coverage (0.0-1.0); 0 = 1.0
+	Aggressive     bool    // Wider local backtracking
+	DumpDir        string  // Where placers write failure dumps (default failing_dumps)
+	// Profile names a config.GenerationProfile whose strategy, coverage,
+	// backtracking and vine lengths apply unless set explicitly here
+	Profile string
+	// RNG names the random source seeds are expanded with: random.Math
+	// (default), random.PCG or random.SplitMix
+	RNG string
+	// CenterOut overrides the tier's center-out seeding and growth tuning
+	// field by field (see config.CenterOutFor)
+	CenterOut config.CenterOutTuning
+	// InjectDifficulty reverses or moves placed vines to deepen blocker
+	// chains toward the tier's target (see strategies.DifficultyInjector)
+	InjectDifficulty bool
+	// OptimizeIterations runs that many simulated annealing moves over each
+	// placement (see strategies.PlacementOptimizer); 0 skips the optimizer
+	OptimizeIterations int
+	// RNGTraceDir, when set, receives a trace of every random draw per
+	// attempt (see RNGTracePath) for diffing runs with the rngdiff command
+	RNGTraceDir string
+	// SkipDifficultyCheck accepts levels regardless of their blocking depth and
+	// difficulty score bands
+	SkipDifficultyCheck bool
+	// MinAesthetics rejects levels whose aesthetics score (0-100, see
+	// metrics.AestheticsAnalyzer) falls below it; 0 disables the check
+	MinAesthetics float64
+	// Stencil, when set, is centered on the grid and its cells are left
+	// empty but visible as decorative gaps; every other cell is covered.
+	// Without a Strategy or profile strategy it generates with full-coverage
+	Stencil *model.Stencil
+	// Constraints are hand-authored requirements every accepted level must meet;
+	// attempts that miss one are regenerated like out-of-band levels
+	Constraints constraints.Set
+	// MaxRetriesPerStrategy bounds attempts per strategy (default DefaultMaxRetriesPerStrategy)
+	MaxRetriesPerStrategy int
+	// Race runs this many generators on different base seeds at once and
+	// keeps the first level produced, cancelling the rest. It cuts the slow
+	// tail of hard tiers at the cost of a nondeterministic result, so it is
+	// ignored when Seed is set. 0 or 1 generates on one goroutine.
+	Race int
+	// OnProgress, if set, receives an event for every attempt outcome. It is
+	// called synchronously from the generating goroutine; racers' calls are
+	// serialized.
+	OnProgress func(Event)
+}
+
+// EventKind identifies what an Event reports.
+type EventKind string
+
+const (
+	EventAttempt  EventKind = "attempt"  // A generation attempt is starting
+	EventRejected EventKind = "rejected" // An attempt failed generation, validation or the score band
+	EventFallback EventKind = "fallback" // A strategy exhausted its retries; moving to the next
+	EventAccepted EventKind = "accepted" // A level passed every check
+)
+
+// Event is a progress notification from Generate.
+type Event struct {
+	Kind     EventKind
+	LevelID  int
+	Strategy string
+	Attempt  int // 1-based attempt number within Strategy
+	Message  string
+	// Attempts and Relaxations total the finished attempts across strategies
+	Attempts    int
+	Relaxations int
+}
+
+// Stats summarizes the work Generate did, summed over every attempt.
+type Stats struct {
+	Strategy             string // Strategy that produced the level (empty on failure)
+	Attempts             int    // Generation attempts across all strategies
+	Fallbacks            int    // Strategies abandoned before success
+	PlacementAttempts    int
+	Backtracks           int
+	Dumps                int
+	Relaxations          int
+	DifficultyRejections int     // Valid levels regenerated because their score was out of band
+	DepthRejections      int     // Valid levels regenerated because their blocking depth was out of band
+	ConstraintRejections int     // Valid levels regenerated because they missed a constraint
+	AestheticRejections  int     // Valid levels regenerated because they scored below MinAesthetics
+	Coverage             float64 // Vine coverage of the accepted level (0-100)
+	// Difficulty holds the accepted level's metrics (zero when SkipDifficultyCheck is set)
+	Difficulty metrics.DifficultyMetrics
+	// Aesthetics holds the accepted level's visual metrics
+	Aesthetics metrics.AestheticsMetrics
+	// Config and Generation describe the accepted attempt
+	Config     config.GenerationConfig
+	Generation config.GenerationStats
+	Duration   time.Duration
+	// Strategies breaks the attempts down by strategy, in the order tried
+	Strategies []StrategyStats
+	// Racers is how many generators raced for the level (0 without
+	// GenerateOptions.Race); the other fields describe the winner's run
+	Racers int
+}
+
+// StrategyStats records how one strategy in the chain fared for a level.
+type StrategyStats struct {
+	Strategy   string
+	Attempts   int
+	Rejections int  // Attempts that failed generation, validation or a quality check
+	Accepted   bool // The level came from this strategy
+	Duration   time.Duration
+}
+
+// Generate produces one validated level. Strategies are tried in order, each
+// with up to MaxRetriesPerStrategy seeds; an attempt is accepted once it passes
+// structural and solvability validation, falls inside the blocking depth and
+// difficulty score bands unless that check is skipped, scores at least
+// MinAesthetics, and meets every constraint. Cancelling ctx interrupts
+// placement and solving and returns ctx.Err().
+//
+// With opts.Race above 1 and no explicit Seed, that many runs race from
+// different base seeds and the first to produce a level wins.
+func Generate(ctx context.Context, opts GenerateOptions) (model.Level, Stats, error) {
+	if opts.Race > 1 && opts.Seed == 0 {
+		return race(ctx, opts)
+	}
+	return generate(ctx, opts)
+}
+
+func generate(ctx context.Context, opts GenerateOptions) (model.Level, Stats, error) {
+	startTime := time.Now()
+	stats := Stats{}
+
+	baseCfg, err := ConfigFor(opts)
+	if err != nil {
+		return model.Level{}, stats, err
+	}
+
+	retries := opts.MaxRetriesPerStrategy
+	if retries <= 0 {
+		retries = DefaultMaxRetriesPerStrategy
+	}
+	notify := func(e Event) {
+		if opts.OnProgress != nil {
+			e.LevelID = opts.LevelID
+			e.Attempts, e.Relaxations = stats.Attempts, stats.Relaxations
+			opts.OnProgress(e)
+		}
+	}
+	scorer := metrics.DifficultyScorer{}
+
+	var stratStart time.Time
+	// done stamps the elapsed time on the run and on the current strategy
+	done := func() {
+		stats.Duration = time.Since(startTime)
+		if n := len(stats.Strategies); n > 0 {
+			stats.Strategies[n-1].Duration = time.Since(stratStart)
+		}
+	}
+
+	for stratIdx, strat := range StrategyChain(baseCfg.Strategy) {
+		stats.Fallbacks = stratIdx
+		stratStart = time.Now()
+		stats.Strategies = append(stats.Strategies, StrategyStats{Strategy: strat})
+		current := &stats.Strategies[len(stats.Strategies)-1]
+		for retry := 0; retry < retries; retry++ {
+			if err := ctx.Err(); err != nil {
+				done()
+				return model.Level{}, stats, err
+			}
+
+			// Vary seed for each attempt
+			genCfg := baseCfg
+			genCfg.Seed = baseCfg.Seed + int64(retry*12345) + int64(len(strat))
+			genCfg.Strategy = strat
+			if opts.RNGTraceDir != "" {
+				genCfg.RNGTraceFile = RNGTracePath(opts.RNGTraceDir, opts.LevelID, stats.Attempts+1)
+			}
+			notify(Event{Kind: EventAttempt, Strategy: strat, Attempt: retry + 1,
+				Message: fmt.Sprintf("Level %d: attempt %d with %s (seed %d)", opts.LevelID, retry+1, strat, genCfg.Seed)})
+
+			level, genStats, err := generator.GenerateRobust(ctx, genCfg)
+			stats.Attempts++
+			current.Attempts++
+			stats.PlacementAttempts += genStats.PlacementAttempts
+			stats.Backtracks += genStats.BacktracksAttempted
+			stats.Dumps += genStats.DumpsProduced
+			stats.Relaxations += genStats.Relaxations
+
+			reject := func(format string, args ...interface{}) {
+				current.Rejections++
+				notify(Event{Kind: EventRejected, Strategy: strat, Attempt: retry + 1, Message: fmt.Sprintf(format, args...)})
+			}
+
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				done()
+				return model.Level{}, stats, ctxErr
+			}
+			if err != nil {
+				reject("Generation failed for level %d (%s): %v", opts.LevelID, strat, err)
+				continue
+			}
+			coverage, err := validate(ctx, level)
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				done()
+				return model.Level{}, stats, ctxErr
+			}
+			if err != nil {
+				reject("Validation failed for level %d (%s): %v", opts.LevelID, strat, err)
+				continue
+			}
+
+			var difficulty metrics.DifficultyMetrics
+			if !opts.SkipDifficultyCheck {
+				// The depth band is cheap to check, so it goes before the solver
+				if depth := utils.MaxBlockingDepth(utils.BuildLevelBlockingGraph(&level)); !metrics.InDepthBand(opts.Difficulty, depth) {
+					stats.DepthRejections++
+					band := config.DifficultySpecs[opts.Difficulty].BlockingDepthRange
+					reject("Level %d (%s): blocking depth %d outside %s band [%d, %d], regenerating",
+						opts.LevelID, strat, depth, opts.Difficulty, band[0], band[1])
+					continue
+				}
+				scored, inBand, err := scorer.Evaluate(level)
+				if err != nil {
+					reject("Difficulty scoring failed for level %d (%s): %v", opts.LevelID, strat, err)
+					continue
+				}
+				if !inBand {
+					stats.DifficultyRejections++
+					band := config.DifficultySpecs[opts.Difficulty].ScoreRange
+					reject("Level %d (%s): difficulty score %.2f outside %s band [%.1f, %.1f], regenerating",
+						opts.LevelID, strat, scored.Score, opts.Difficulty, band[0], band[1])
+					continue
+				}
+				difficulty = scored
+			}
+
+			aesthetics := metrics.AestheticsAnalyzer{}.Analyze(level)
+			if aesthetics.Score < opts.MinAesthetics {
+				stats.AestheticRejections++
+				reject("Level %d (%s): aesthetics score %.1f below %.1f, regenerating",
+					opts.LevelID, strat, aesthetics.Score, opts.MinAesthetics)
+				continue
+			}
+
+			if len(opts.Constraints) > 0 {
+				results, err := opts.Constraints.Evaluate(ctx, level, constraintSolveBudget)
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					done()
+					return model.Level{}, stats, ctxErr
+				}
+				if err != nil {
+					reject("Constraint check failed for level %d (%s): %v", opts.LevelID, strat, err)
+					continue
+				}
+				if unmet := constraints.Unmet(results); len(unmet) > 0 {
+					stats.ConstraintRejections++
+					reject("Level %d (%s): %d constraints not met (first: %s), regenerating",
+						opts.LevelID, strat, len(unmet), unmet[0])
+					continue
+				}
+			}
+
+			stats.Strategy = strat
+			stats.Coverage = coverage
+			stats.Difficulty = difficulty
+			stats.Aesthetics = aesthetics
+			stats.Config = genCfg
+			stats.Generation = genStats
+			current.Accepted = true
+			done()
+
+			// Persist the retry budget with the level so `stats levels` can
+			// aggregate it without the run's stats files
+			level.GenerationStrategy = strat
+			level.GenerationAttempts = stats.Attempts
+			level.GenerationRelaxations = stats.Relaxations
+			level.GenerationBacktracks = stats.Backtracks
+			level.GenerationElapsedMS = stats.Duration.Milliseconds()
+			notify(Event{Kind: EventAccepted, Strategy: strat, Attempt: retry + 1,
+				Message: fmt.Sprintf("Level %d generated using %s (Attempt %d)", opts.LevelID, strat, retry+1)})
+			return level, stats, nil
+		}
+
+		done()
+		notify(Event{Kind: EventFallback, Strategy: strat, Attempt: retries,
+			Message: fmt.Sprintf("Level %d: Strategy %s failed after %d attempts. Trying next fallback...", opts.LevelID, strat, retries)})
+	}
+
+	stats.Duration = time.Since(startTime)
+	return model.Level{}, stats, fmt.Errorf("failed to generate solvable level after exhausting all strategies")
+}
+
+// race runs opts.Race generators, racer k from base seed
+// DefaultSeed(LevelID) + k*raceSeedStride, and returns the first level
+// produced. Losers are cancelled and waited for. When every racer fails, the
+// first racer's error is returned.
+func race(ctx context.Context, opts GenerateOptions) (model.Level, Stats, error) {
+	if _, err := ConfigFor(opts); err != nil {
+		return model.Level{}, Stats{}, err
+	}
+	startTime := time.Now()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type outcome struct {
+		racer int
+		level model.Level
+		stats Stats
+		err   error
+	}
+	outcomes := make(chan outcome, opts.Race)
+	var progressMu sync.Mutex
+	for k := 0; k < opts.Race; k++ {
+		racerOpts := opts
+		racerOpts.Seed = DefaultSeed(opts.LevelID) + int64(k)*raceSeedStride
+		if opts.RNGTraceDir != "" {
+			racerOpts.RNGTraceDir = filepath.Join(opts.RNGTraceDir, fmt.Sprintf("racer_%d", k))
+		}
+		if opts.OnProgress != nil {
+			racerOpts.OnProgress = func(e Event) {
+				progressMu.Lock()
+				defer progressMu.Unlock()
+				opts.OnProgress(e)
+			}
+		}
+		go func(k int) {
+			level, stats, err := generate(ctx, racerOpts)
+			outcomes <- outcome{racer: k, level: level, stats: stats, err: err}
+		}(k)
+	}
+
+	var winner, first *outcome
+	for i := 0; i < opts.Race; i++ {
+		o := <-outcomes
+		if o.err == nil && winner == nil {
+			winner = &o
+			cancel()
+		}
+		if o.racer == 0 {
+			first = &o
+		}
+	}
+
+	if winner == nil {
+		winner = first
+	}
+	winner.stats.Racers = opts.Race
+	winner.stats.Duration = time.Since(startTime)
+	return winner.level, winner.stats, winner.err
+}
+
+// RNGTracePath returns where the RNG trace of a level's attempt (1-based,
+// across strategies) is written under dir.
+func RNGTracePath(dir string, levelID, attempt int) string {
+	return filepath.Join(dir, fmt.Sprintf("level_%d_attempt_%d.rng.json", levelID, attempt))
+}
+
+// StrategyChain returns the strategies Generate tries, in order, for a requested strategy.
+func StrategyChain(requested string) []string {
+	primary := requested
+	if primary == "" {
+		// ClearableFirst (optimized) reaches >95% coverage
+		primary = config.StrategyLegacyClearable
+	}
+	chain := []string{primary}
+	if primary != config.StrategyCenterOut {
+		// Center-Out (LIFO) has the strongest solvability guarantee
+		chain = append(chain, config.StrategyCenterOut)
+	}
+	return chain
+}
+
+// ResolveStrategy returns the primary strategy for opts: Strategy when set,
+// otherwise the profile's, otherwise full-coverage for a stencil, otherwise
+// the StrategyChain default.
+func ResolveStrategy(opts GenerateOptions) string {
+	strategy := opts.Strategy
+	if strategy == "" {
+		strategy = config.GenerationProfiles[opts.Profile].Strategy
+	}
+	if strategy == "" && opts.Stencil != nil {
+		strategy = config.StrategyFullCoverage
+	}
+	return StrategyChain(strategy)[0]
+}
+
+// ConfigFor derives the generation config for opts using the tier's grid size,
+// vine count and backtracking defaults, adjusted by opts.Profile. The config
+// has no OutputFile.
+func ConfigFor(opts GenerateOptions) (config.GenerationConfig, error) {
+	if _, ok := config.DifficultySpecs[opts.Difficulty]; !ok {
+		return config.GenerationConfig{}, fmt.Errorf("unknown difficulty: %s", opts.Difficulty)
+	}
+	var profile config.GenerationProfile
+	if opts.Profile != "" {
+		var err error
+		if profile, err = config.ProfileFor(opts.Profile); err != nil {
+			return config.GenerationConfig{}, err
+		}
+	}
+	// Vine counts follow the profile's vine lengths
+	spec, _ := config.SpecFor(config.GenerationConfig{Difficulty: opts.Difficulty, Profile: opts.Profile})
+
+	gridRange, ok := config.GridSizeRanges[opts.Difficulty]
+	if !ok {
+		return config.GenerationConfig{}, fmt.Errorf("no grid size config for difficulty: %s", opts.Difficulty)
+	}
+
+	gridWidth := (gridRange.MinW + gridRange.MaxW) / 2
+	gridHeight := (gridRange.MinH + gridRange.MaxH) / 2
+	if gridWidth < 2 || gridHeight < 2 {
+		return config.GenerationConfig{}, fmt.Errorf("invalid grid size computed for %s", opts.Difficulty)
+	}
+
+	if err := model.CheckMaskMode(opts.MaskMode); err != nil {
+		return config.GenerationConfig{}, err
+	}
+
+	coverage, err := config.CoverageFor(opts.Difficulty, opts.Profile, opts.MinCoverage)
+	if err != nil {
+		return config.GenerationConfig{}, err
+	}
+
+	if opts.Hints < 0 {
+		return config.GenerationConfig{}, fmt.Errorf("invalid Hints: %d", opts.Hints)
+	}
+	if err := random.Check(opts.RNG); err != nil {
+		return config.GenerationConfig{}, err
+	}
+	if opts.Race < 0 {
+		return config.GenerationConfig{}, fmt.Errorf("invalid Race: %d", opts.Race)
+	}
+	if opts.MinAesthetics < 0 || opts.MinAesthetics > 100 {
+		return config.GenerationConfig{}, fmt.Errorf("invalid MinAesthetics: %v (want 0-100)", opts.MinAesthetics)
+	}
+	if opts.OptimizeIterations < 0 {
+		return config.GenerationConfig{}, fmt.Errorf("invalid OptimizeIterations: %d", opts.OptimizeIterations)
+	}
+
+	var stencil []model.Point
+	if opts.Stencil != nil {
+		if stencil, err = opts.Stencil.Place(gridWidth, gridHeight); err != nil {
+			return config.GenerationConfig{}, err
+		}
+	}
+
+	vineCount := computeVineCount(spec, gridWidth*gridHeight-len(stencil), 1.0)
+
+	// Default backtracking settings
+	backtrackWindow := 3
+	maxBackAttempts := 2
+	if profile.BacktrackWindow > 0 {
+		backtrackWindow = profile.BacktrackWindow
+	}
+	if profile.MaxBacktrackAttempts > 0 {
+		maxBackAttempts = profile.MaxBacktrackAttempts
+	}
+	if opts.Aggressive {
+		backtrackWindow = 6
+		maxBackAttempts = 6
+	}
+
+	seed := opts.Seed
+	if seed == 0 {
+		seed = DefaultSeed(opts.LevelID)
+	}
+
+	return config.GenerationConfig{
+		LevelID:              opts.LevelID,
+		GridWidth:            gridWidth,
+		GridHeight:           gridHeight,
+		VineCount:            vineCount,
+		Randomize:            false,
+		Seed:                 seed,
+		MinCoverage:          coverage.Target,
+		Difficulty:           opts.Difficulty,
+		Strategy:             ResolveStrategy(opts),
+		FillerStrategy:       opts.FillerStrategy,
+		MaskMode:             opts.MaskMode,
+		Portals:              opts.Portals,
+		HintCount:            opts.Hints,
+		Profile:              opts.Profile,
+		RNG:                  opts.RNG,
+		CenterOutTuning:      opts.CenterOut,
+		InjectDifficulty:     opts.InjectDifficulty,
+		OptimizeIterations:   opts.OptimizeIterations,
+		BacktrackWindow:      backtrackWindow,
+		MaxBacktrackAttempts: maxBackAttempts,
+		DumpDir:              opts.DumpDir,
+		Stencil:              stencil,
+	}, nil
+}
+
+func computeVineCount(spec config.DifficultySpec, totalCells int, targetCoverage float64) int {
+	avgLength := (spec.AvgLengthRange[0] + spec.AvgLengthRange[1]) / 2
+	if avgLength < 2 {
+		avgLength = 2
+	}
+
+	targetOccupiedCells := int(float64(totalCells) * targetCoverage)
+	vineCount := targetOccupiedCells / avgLength
+
+	if vineCount < spec.VineCountRange[0] {
+		vineCount = spec.VineCountRange[0]
+	}
+	if vineCount > spec.VineCountRange[1] {
+		vineCount = spec.VineCountRange[1]
+	}
+
+	if vineCount > totalCells/4 {
+		vineCount = totalCells / 4
+	}
+	if vineCount < 3 {
+		vineCount = 3
+	}
+
+	return vineCount
+}
+
+// Validate runs the structural and solvability checks Generate applies to
+// every attempt and returns the level's vine coverage as a percentage.
+func Validate(level model.Level) (float64, error) {
+	return validate(context.Background(), level)
+}
+
+func validate(ctx context.Context, level model.Level) (float64, error) {
+	if structErrors := validator.ValidateStructural(level); len(structErrors) > 0 {
+		return 0, fmt.Errorf("structural validation failed: %d errors (first: %v)", len(structErrors), structErrors[0])
+	}
+
+	solvable, _, err := validator.IsSolvableContext(ctx, level, 1000000)
+	if err != nil {
+		return 0, fmt.Errorf("solvability check error: %v", err)
+	}
+
+	if !solvable {
+		return 0, fmt.Errorf("level not solvable")
+	}
+
+	coverage := (float64(level.GetOccupiedCells()) / float64(level.GetTotalCells())) * 100.0
+	return coverage, nil
+}
@@ -0,0 +1,274 @@
+package levelgen
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/constraints"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/random"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func TestGenerateSeedling(t *testing.T) {
+	var events []Event
+	opts := GenerateOptions{
+		LevelID:    1,
+		Difficulty: "Seedling",
+		Strategy:   config.StrategyCenterOut,
+		DumpDir:    t.TempDir(),
+		OnProgress: func(e Event) { events = append(events, e) },
+	}
+
+	level, stats, err := Generate(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if level.ID != 1 || len(level.Vines) == 0 {
+		t.Fatalf("unexpected level: id %d, %d vines", level.ID, len(level.Vines))
+	}
+	if stats.Strategy != config.StrategyCenterOut || stats.Attempts < 1 || stats.Config.OutputFile != "" {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if level.GenerationStrategy != stats.Strategy || level.GenerationAttempts != stats.Attempts || level.GenerationBacktracks != stats.Backtracks {
+		t.Errorf("level should record its retry budget, got strategy %q attempts %d backtracks %d",
+			level.GenerationStrategy, level.GenerationAttempts, level.GenerationBacktracks)
+	}
+	if len(events) == 0 || events[0].Kind != EventAttempt || events[len(events)-1].Kind != EventAccepted {
+		t.Errorf("expected attempt ... accepted events, got %+v", events)
+	}
+
+	again, _, err := Generate(context.Background(), GenerateOptions{LevelID: 1, Difficulty: "Seedling", Strategy: config.StrategyCenterOut, DumpDir: opts.DumpDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(level.Vines, again.Vines) {
+		t.Error("same options should generate the same level")
+	}
+}
+
+func TestGenerateShowMask(t *testing.T) {
+	level, _, err := Generate(context.Background(), GenerateOptions{
+		LevelID:    2,
+		Difficulty: "Seedling",
+		MaskMode:   model.MaskModeShow,
+		DumpDir:    t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if level.Mask == nil {
+		t.Skip("level filled every cell; no mask to check")
+	}
+	if level.Mask.Mode != model.MaskModeShow {
+		t.Fatalf("expected a show mask, got %q", level.Mask.Mode)
+	}
+	if got, want := level.GetVisibleCells(), level.GetOccupiedCells(); got != want {
+		t.Errorf("playable region has %d cells, want the %d occupied cells", got, want)
+	}
+	for _, v := range level.Vines {
+		for _, p := range v.OrderedPath {
+			if !level.IsCellVisible(p.X, p.Y) {
+				t.Fatalf("vine %s cell (%d,%d) is outside the playable region", v.ID, p.X, p.Y)
+			}
+		}
+	}
+
+	if _, err := ConfigFor(GenerateOptions{LevelID: 2, Difficulty: "Seedling", MaskMode: "invert"}); err == nil {
+		t.Error("expected an unknown mask mode to be rejected")
+	}
+}
+
+func TestGenerateStencil(t *testing.T) {
+	stencil, err := model.ParseStencil("#.#\n###\n#.#")
+	if err != nil {
+		t.Fatal(err)
+	}
+	level, stats, err := Generate(context.Background(), GenerateOptions{
+		LevelID: 7, Difficulty: "Sprout", Stencil: stencil, DumpDir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if stats.Strategy != config.StrategyFullCoverage {
+		t.Errorf("strategy = %s, want full-coverage by default", stats.Strategy)
+	}
+	if level.Mask == nil || level.Mask.Mode != model.MaskModeShowAll || len(level.Mask.Points) != len(stencil.Cells) {
+		t.Fatalf("mask = %+v, want the %d stencil gaps", level.Mask, len(stencil.Cells))
+	}
+	w, h := level.GetGridWidth(), level.GetGridHeight()
+	if got := level.GetOccupiedCells() + len(level.Portals)*2; got != w*h-len(stencil.Cells) {
+		t.Errorf("vines and portals cover %d cells, want every cell but the gaps", got)
+	}
+	for _, v := range level.Vines {
+		for _, p := range v.OrderedPath {
+			if level.Mask.IsGap(p.X, p.Y) {
+				t.Fatalf("vine %s occupies gap (%d,%d)", v.ID, p.X, p.Y)
+			}
+		}
+	}
+
+	if _, err := ConfigFor(GenerateOptions{LevelID: 7, Difficulty: "Seedling", Stencil: &model.Stencil{Width: 40, Height: 1}}); err == nil {
+		t.Error("expected a stencil wider than the grid to be rejected")
+	}
+}
+
+func TestGenerateConstraints(t *testing.T) {
+	met, err := constraints.ParseConstraint("solution length >= 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	level, stats, err := Generate(context.Background(), GenerateOptions{
+		LevelID: 1, Difficulty: "Seedling", DumpDir: t.TempDir(), Constraints: constraints.Set{met},
+	})
+	if err != nil || len(level.Vines) < 2 || stats.ConstraintRejections != 0 {
+		t.Fatalf("Generate with a met constraint: %v (%d vines, %d rejections)", err, len(level.Vines), stats.ConstraintRejections)
+	}
+
+	// No Seedling level has 500 vines, so every valid attempt is rejected
+	unmet, err := constraints.ParseConstraint("at least 500 vines")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, stats, err = Generate(context.Background(), GenerateOptions{
+		LevelID: 1, Difficulty: "Seedling", Strategy: config.StrategyCenterOut, DumpDir: t.TempDir(),
+		MaxRetriesPerStrategy: 2, Constraints: constraints.Set{unmet},
+	})
+	if err == nil || stats.ConstraintRejections == 0 {
+		t.Errorf("Generate with an unmet constraint: err %v, %d rejections", err, stats.ConstraintRejections)
+	}
+}
+
+func TestGenerateCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, stats, err := Generate(ctx, GenerateOptions{LevelID: 1, Difficulty: "Seedling"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if stats.Attempts != 0 {
+		t.Errorf("no attempts should run after cancellation, got %d", stats.Attempts)
+	}
+}
+
+func TestConfigFor(t *testing.T) {
+	cfg, err := ConfigFor(GenerateOptions{LevelID: 3, Difficulty: "Sprout", MinCoverage: 0.9, Aggressive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Seed != DefaultSeed(3) || cfg.MinCoverage != 0.9 || cfg.BacktrackWindow != 6 || cfg.Strategy != config.StrategyLegacyClearable {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+
+	if _, err := ConfigFor(GenerateOptions{Difficulty: "Impossible"}); err == nil {
+		t.Error("expected an error for an unknown difficulty")
+	}
+}
+
+func TestGenerateWithRNG(t *testing.T) {
+	opts := GenerateOptions{LevelID: 2, Difficulty: "Seedling", Strategy: config.StrategyCenterOut, RNG: random.PCG, DumpDir: t.TempDir()}
+	level, _, err := Generate(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if level.GenerationRNG != random.PCG {
+		t.Errorf("expected the level to record its rng, got %q", level.GenerationRNG)
+	}
+	again, _, err := Generate(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(level.Vines, again.Vines) {
+		t.Error("same seed and rng should generate the same level")
+	}
+
+	if _, err := ConfigFor(GenerateOptions{LevelID: 2, Difficulty: "Seedling", RNG: "mersenne"}); err == nil {
+		t.Error("expected an error for an unknown rng")
+	}
+}
+
+func TestGenerateRace(t *testing.T) {
+	opts := GenerateOptions{LevelID: 3, Difficulty: "Seedling", Race: 3, DumpDir: t.TempDir()}
+	level, stats, err := Generate(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if stats.Racers != 3 {
+		t.Errorf("Racers = %d, want 3", stats.Racers)
+	}
+	if _, err := Validate(level); err != nil {
+		t.Errorf("raced level invalid: %v", err)
+	}
+
+	// An explicit seed keeps generation deterministic
+	opts.Seed = DefaultSeed(3)
+	raced, stats, err := Generate(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.Race = 0
+	plain, _, err := Generate(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Racers != 0 || !reflect.DeepEqual(raced.Vines, plain.Vines) {
+		t.Error("Race should be ignored when Seed is set")
+	}
+}
+
+func TestGenerateRNGTrace(t *testing.T) {
+	dir := t.TempDir()
+	opts := GenerateOptions{LevelID: 1, Difficulty: "Seedling", Strategy: config.StrategyCenterOut, RNGTraceDir: dir, DumpDir: t.TempDir()}
+	if _, _, err := Generate(context.Background(), opts); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	trace, err := random.LoadRecording(RNGTracePath(dir, 1, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trace.Draws) == 0 || len(trace.Sites) != len(trace.Draws) || trace.Seed != DefaultSeed(1)+int64(len(config.StrategyCenterOut)) {
+		t.Errorf("expected a traced first attempt, got %d draws, %d sites, seed %d", len(trace.Draws), len(trace.Sites), trace.Seed)
+	}
+}
+
+func TestConfigForProfile(t *testing.T) {
+	plain, err := ConfigFor(GenerateOptions{LevelID: 3, Difficulty: "Sprout"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := ConfigFor(GenerateOptions{LevelID: 3, Difficulty: "Sprout", Profile: config.ProfileSpeedrun})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Profile != config.ProfileSpeedrun || cfg.Strategy != config.StrategyCenterOut || cfg.MinCoverage != 0.85 || cfg.BacktrackWindow != 2 {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if cfg.VineCount >= plain.VineCount {
+		t.Errorf("speedrun should place fewer vines: %d vs %d", cfg.VineCount, plain.VineCount)
+	}
+
+	// Explicit options override the profile
+	cfg, err = ConfigFor(GenerateOptions{Difficulty: "Sprout", Profile: config.ProfileSpeedrun, Strategy: config.StrategyDirectionFirst, MinCoverage: 0.7, Aggressive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Strategy != config.StrategyDirectionFirst || cfg.MinCoverage != 0.7 || cfg.BacktrackWindow != 6 {
+		t.Errorf("options should override the profile: %+v", cfg)
+	}
+
+	if _, err := ConfigFor(GenerateOptions{Difficulty: "Sprout", Profile: "chaotic"}); err == nil {
+		t.Error("expected an error for an unknown profile")
+	}
+}
+
+func TestStrategyChain(t *testing.T) {
+	if got := StrategyChain(config.StrategyCenterOut); !reflect.DeepEqual(got, []string{config.StrategyCenterOut}) {
+		t.Errorf("center-out chain = %v", got)
+	}
+	if got := StrategyChain(""); !reflect.DeepEqual(got, []string{config.StrategyLegacyClearable, config.StrategyCenterOut}) {
+		t.Errorf("default chain = %v", got)
+	}
+}
@@ -0,0 +1,12 @@
+package model
+
+// GraceBasis records how a generated level's grace was derived from its
+// solution, so the value can be audited rather than taken as a tier constant.
+type GraceBasis struct {
+	Base      int    `json:"base"`      // Tier default grace
+	Steps     int    `json:"steps"`     // Moves in the solution
+	Branching int    `json:"branching"` // Steps with several clearable vines but more blocked ones
+	Forced    int    `json:"forced"`    // Steps with exactly one clearable vine
+	Bonus     int    `json:"bonus"`     // Grace added on top of Base for the branching steps
+	Reason    string `json:"reason"`
+}
@@ -0,0 +1,64 @@
+package model
+
+// Player interactions a lesson can allow.
+const (
+	InteractionTap     = "tap"     // Tap a vine to clear it
+	InteractionHint    = "hint"    // Ask for a hint
+	InteractionRestart = "restart" // Reset the board
+)
+
+// LessonInteractions lists the interactions a lesson may allow.
+var LessonInteractions = []string{InteractionTap, InteractionHint, InteractionRestart}
+
+// Lesson represents a tutorial lesson file (assets/lessons/lesson_N.json).
+// Lessons are small levels with instructional text and none of the
+// difficulty, grace or generation metadata of regular levels.
+type Lesson struct {
+	ID             int      `json:"id"`
+	Title          string   `json:"title"`
+	Objective      string   `json:"objective"`
+	Instructions   string   `json:"instructions"`
+	LearningPoints []string `json:"learning_points"`
+	// TextKeys names the localized strings behind the instructional text
+	TextKeys LessonTextKeys `json:"text_keys"`
+	GridSize []int          `json:"grid_size"` // [width, height]
+	MaxMoves int            `json:"max_moves"`
+	Vines    []Vine         `json:"vines"`
+	// HighlightVines are vine IDs the game draws the player's eye to
+	HighlightVines []string `json:"highlight_vines,omitempty"`
+	// AllowedInteractions limits what the player can do (see LessonInteractions)
+	AllowedInteractions []string `json:"allowed_interactions"`
+	// Steps gate a guided lesson: each step unlocks once the step before it
+	// clears its vine. Lessons without steps are free play.
+	Steps       []LessonStep `json:"steps,omitempty"`
+	ColorScheme []string     `json:"color_scheme,omitempty"`
+}
+
+// LessonTextKeys are localization keys for a lesson's instructional text.
+type LessonTextKeys struct {
+	Title        string `json:"title"`
+	Objective    string `json:"objective"`
+	Instructions string `json:"instructions"`
+}
+
+// LessonStep is one gated move of a guided lesson.
+type LessonStep struct {
+	Vine    string `json:"vine"`               // Vine the player clears in this step
+	TextKey string `json:"text_key,omitempty"` // Prompt shown while the step is open
+}
+
+// Level returns the lesson as a level so it can go through the solvers.
+func (l Lesson) Level() Level {
+	return Level{
+		ID:          l.ID,
+		Name:        l.Title,
+		Difficulty:  "Tutorial",
+		GridSize:    l.GridSize,
+		Vines:       l.Vines,
+		MaxMoves:    l.MaxMoves,
+		ColorScheme: l.ColorScheme,
+	}
+}
+
+// Guided reports whether the lesson gates play step by step.
+func (l Lesson) Guided() bool { return len(l.Steps) > 0 }
@@ -7,25 +7,49 @@ type Level struct {
 	// Core fields
 	ID          int      `json:"id"`
 	Name        string   `json:"name,omitempty"`
+	NameRef     *NameRef `json:"name_ref,omitempty"`   // Localizable form of Name
 	Difficulty  string   `json:"difficulty,omitempty"` // "Tutorial", "Seedling", "Sprout", "Nurturing", "Flourishing", "Transcendent"
 	GridSize    []int    `json:"grid_size"`            // [width, height]
 	Mask        *Mask    `json:"mask,omitempty"`
+	Portals     []Portal `json:"portals,omitempty"` // Linked cell pairs vine heads pass through
 	Vines       []Vine   `json:"vines"`
 	MaxMoves    int      `json:"max_moves"`
 	MinMoves    int      `json:"min_moves,omitempty"`
+	Hints       []string `json:"hints,omitempty"`      // Vine IDs opening a valid clear order, for in-game hints
 	Complexity  string   `json:"complexity,omitempty"` // "tutorial", "low", "medium", "high", "extreme"
-	Grace       int      `json:"grace"`                // 3 or 4
+	Grace       int      `json:"grace"`                // Tier default, raised for levels with many decisions
 	ColorScheme []string `json:"color_scheme"`         // Color codes for this level
+	// Theme, when set, themes the board; see LevelTheme
+	Theme *LevelTheme `json:"theme,omitempty"`
 
 	// Generation metadata persisted for reproducibility & diagnostics
 	GenerationSeed      int64   `json:"generation_seed,omitempty"`
 	GenerationAttempts  int     `json:"generation_attempts,omitempty"`
 	GenerationElapsedMS int64   `json:"generation_elapsed_ms,omitempty"`
 	GenerationScore     float64 `json:"generation_score,omitempty"`
+	GenerationProfile   string  `json:"generation_profile,omitempty"`
+	GenerationRNG       string  `json:"generation_rng,omitempty"` // Random source behind GenerationSeed (empty = math)
+	// How Grace was derived from the solution
+	GraceBasis *GraceBasis `json:"grace_basis,omitempty"`
+	// Vine IDs clearing every vine, in order, known by construction; the
+	// validator replays it instead of searching, and hints can be taken from it
+	SolutionOrder []string `json:"solution_order,omitempty"`
+	// Retry budget spent on the level, for `stats levels`
+	GenerationStrategy    string `json:"generation_strategy,omitempty"`
+	GenerationRelaxations int    `json:"generation_relaxations,omitempty"`
+	GenerationBacktracks  int    `json:"generation_backtracks,omitempty"`
 
 	// Seed for reproducible generation (gen2 transcendent levels)
 	Seed int64 `json:"seed,omitempty"`
 
+	// Mirror metadata: set on levels produced as the reflection of another level
+	MirrorOf   int    `json:"mirror_of,omitempty"`   // Source level ID
+	MirrorAxis string `json:"mirror_axis,omitempty"` // "horizontal" or "vertical"
+
+	// Variant metadata: set on levels remixed from another level by `variant`
+	VariantOf        int      `json:"variant_of,omitempty"`        // Source level ID
+	VariantMutations []string `json:"variant_mutations,omitempty"` // Mutations applied, in order
+
 	// These are populated during validation but not persisted
 	OccupancyPercent  float64             `json:"-"`
 	ColorDistribution map[string]float64  `json:"-"`
@@ -62,6 +86,11 @@ func (l *Level) GetOccupiedCells() int {
 	return total
 }
 
+// GetVisibleCells returns the number of cells the mask leaves playable.
+func (l *Level) GetVisibleCells() int {
+	return l.GetTotalCells() - l.Mask.HiddenCells(l.GetGridWidth(), l.GetGridHeight())
+}
+
 // IsCellVisible returns true if the cell at (x, y) is visible (not masked).
 func (l *Level) IsCellVisible(x, y int) bool {
 	if l.Mask == nil {
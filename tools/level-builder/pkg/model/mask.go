@@ -1,11 +1,78 @@
 package model
 
+import "fmt"
+
+// Mask modes understood by the game and the validator.
+const (
+	MaskModeHide    = "hide"     // Points are hidden; everything else is playable
+	MaskModeShow    = "show"     // Points are the playable region; everything else is hidden
+	MaskModeShowAll = "show-all" // Nothing is hidden; Points are decorative gaps left empty
+)
+
 // Mask defines the visibility of the grid
 type Mask struct {
 	Mode   string  `json:"mode"`   // "hide", "show", "show-all"
 	Points []Point `json:"points"` // Coordinates affected by the mask
 }
 
+// NewGapMask builds a "show-all" mask whose points are decorative gaps:
+// visible cells a level leaves empty on purpose, such as a stencil's letters.
+// It returns nil when there are no gaps.
+func NewGapMask(gaps []Point) *Mask {
+	if len(gaps) == 0 {
+		return nil
+	}
+	return &Mask{Mode: MaskModeShowAll, Points: append([]Point(nil), gaps...)}
+}
+
+// IsGap returns true if (x, y) is a decorative gap of a "show-all" mask.
+func (m *Mask) IsGap(x, y int) bool {
+	if m == nil || m.Mode != MaskModeShowAll {
+		return false
+	}
+	for _, pt := range m.Points {
+		if pt.X == x && pt.Y == y {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckMaskMode returns an error unless mode is a mode generators can emit.
+// An empty mode is accepted and means MaskModeHide.
+func CheckMaskMode(mode string) error {
+	switch mode {
+	case "", MaskModeHide, MaskModeShow:
+		return nil
+	default:
+		return fmt.Errorf("unknown mask mode %q (want %s or %s)", mode, MaskModeHide, MaskModeShow)
+	}
+}
+
+// NewOccupancyMask builds a mask that hides every cell of a width x height grid
+// for which occupied returns false. In "hide" mode (or when mode is empty) the
+// points are the empty cells; in "show" mode they are the occupied cells, an
+// explicit playable region. It returns nil when every cell is occupied.
+func NewOccupancyMask(mode string, width, height int, occupied func(x, y int) bool) *Mask {
+	var empty, filled []Point
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if occupied(x, y) {
+				filled = append(filled, Point{X: x, Y: y})
+			} else {
+				empty = append(empty, Point{X: x, Y: y})
+			}
+		}
+	}
+	if len(empty) == 0 {
+		return nil
+	}
+	if mode == MaskModeShow {
+		return &Mask{Mode: MaskModeShow, Points: filled}
+	}
+	return &Mask{Mode: MaskModeHide, Points: empty}
+}
+
 // IsMasked returns true if the given point should be masked (hidden) based on the mask mode.
 func (m *Mask) IsMasked(x, y int) bool {
 	if m == nil {
@@ -19,13 +86,35 @@ func (m *Mask) IsMasked(x, y int) bool {
 		}
 	}
 	switch m.Mode {
-	case "hide":
+	case MaskModeHide:
 		return inMask
-	case "show":
+	case MaskModeShow:
 		return !inMask
-	case "show-all":
+	case MaskModeShowAll:
 		return false
 	default:
 		return false
 	}
 }
+
+// HiddenCells counts the cells of a width x height grid the mask hides. Unlike
+// len(Points) it does not depend on the mask mode.
+func (m *Mask) HiddenCells(width, height int) int {
+	if m == nil {
+		return 0
+	}
+	listed := make(map[Point]bool, len(m.Points))
+	for _, p := range m.Points {
+		if p.X >= 0 && p.X < width && p.Y >= 0 && p.Y < height {
+			listed[p] = true
+		}
+	}
+	switch m.Mode {
+	case MaskModeHide:
+		return len(listed)
+	case MaskModeShow:
+		return width*height - len(listed)
+	default:
+		return 0
+	}
+}
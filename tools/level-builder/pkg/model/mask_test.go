@@ -0,0 +1,99 @@
+package model
+
+import "testing"
+
+func TestNewOccupancyMask(t *testing.T) {
+	// 3x2 grid with the bottom-right cell empty
+	occupied := func(x, y int) bool { return !(x == 2 && y == 1) }
+
+	hide := NewOccupancyMask(MaskModeHide, 3, 2, occupied)
+	if hide == nil || hide.Mode != MaskModeHide || len(hide.Points) != 1 {
+		t.Fatalf("hide mask = %+v, want the single empty cell", hide)
+	}
+	show := NewOccupancyMask(MaskModeShow, 3, 2, occupied)
+	if show == nil || show.Mode != MaskModeShow || len(show.Points) != 5 {
+		t.Fatalf("show mask = %+v, want the five occupied cells", show)
+	}
+
+	for _, m := range []*Mask{hide, show} {
+		if got := m.HiddenCells(3, 2); got != 1 {
+			t.Errorf("%s: HiddenCells = %d, want 1", m.Mode, got)
+		}
+		if !m.IsMasked(2, 1) || m.IsMasked(0, 0) {
+			t.Errorf("%s: expected only (2,1) to be masked", m.Mode)
+		}
+	}
+
+	if m := NewOccupancyMask(MaskModeShow, 3, 2, func(x, y int) bool { return true }); m != nil {
+		t.Errorf("full grid should need no mask, got %+v", m)
+	}
+}
+
+func TestCheckMaskMode(t *testing.T) {
+	for _, mode := range []string{"", MaskModeHide, MaskModeShow} {
+		if err := CheckMaskMode(mode); err != nil {
+			t.Errorf("CheckMaskMode(%q) = %v", mode, err)
+		}
+	}
+	if err := CheckMaskMode("invert"); err == nil {
+		t.Error("expected unknown mode to be rejected")
+	}
+}
+
+func TestGapMask(t *testing.T) {
+	if m := NewGapMask(nil); m != nil {
+		t.Errorf("no gaps should need no mask, got %+v", m)
+	}
+	m := NewGapMask([]Point{{X: 1, Y: 0}})
+	if m.Mode != MaskModeShowAll || !m.IsGap(1, 0) || m.IsGap(0, 0) {
+		t.Fatalf("gap mask = %+v, want only (1,0) as a gap", m)
+	}
+	if m.IsMasked(1, 0) || m.HiddenCells(2, 2) != 0 {
+		t.Error("gaps must stay visible")
+	}
+	if (&Mask{Mode: MaskModeHide, Points: []Point{{X: 1, Y: 0}}}).IsGap(1, 0) {
+		t.Error("hidden cells are not gaps")
+	}
+}
+
+func TestParseStencil(t *testing.T) {
+	s, err := ParseStencil("\n#.#\n .\n##\n\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The top row has the highest Y
+	want := []Point{{X: 0, Y: 2}, {X: 2, Y: 2}, {X: 0, Y: 0}, {X: 1, Y: 0}}
+	if s.Width != 3 || s.Height != 3 || len(s.Cells) != len(want) {
+		t.Fatalf("stencil = %+v, want 3x3 with cells %v", s, want)
+	}
+	for i, p := range want {
+		if s.Cells[i] != p {
+			t.Errorf("cell %d = %v, want %v", i, s.Cells[i], p)
+		}
+	}
+
+	for _, text := range []string{"...\n. .", "#\t#", ""} {
+		if _, err := ParseStencil(text); err == nil {
+			t.Errorf("ParseStencil(%q) succeeded", text)
+		}
+	}
+}
+
+func TestStencilPlace(t *testing.T) {
+	s, _ := ParseStencil("##\n#.")
+	cells, err := s.Place(6, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Centered two cells right and one up
+	if cells[0] != (Point{X: 2, Y: 2}) || cells[2] != (Point{X: 2, Y: 1}) {
+		t.Errorf("placed cells = %v", cells)
+	}
+
+	if _, err := s.Place(1, 4); err == nil {
+		t.Error("a stencil wider than the grid should not fit")
+	}
+	if _, err := s.Place(2, 2); err == nil {
+		t.Error("a stencil leaving 75% of the grid empty should be rejected")
+	}
+}
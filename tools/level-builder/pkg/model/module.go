@@ -4,11 +4,16 @@ package model
 type Module struct {
 	ID             int      `json:"id"`
 	Name           string   `json:"name"`
+	NameRef        *NameRef `json:"name_ref,omitempty"` // Localizable form of Name
 	ThemeSeed      string   `json:"theme_seed"`
 	Levels         []string `json:"levels"`
 	ChallengeLevel string   `json:"challenge_level"`
 	Parable        Parable  `json:"parable"`
 	UnlockMessage  string   `json:"unlock_message"`
+	// Scriptures are the journal entries unlocked as the module is played
+	Scriptures []Scripture `json:"scriptures,omitempty"`
+	// Mirrors pairs source logical level keys with their mirrored companions
+	Mirrors map[string]string `json:"mirrors,omitempty"`
 }
 
 // ModuleRegistry represents the contents of modules.json
@@ -0,0 +1,11 @@
+package model
+
+// NameRef is a localizable name: a message key in the names catalog
+// (data/names.json) and the values of its {placeholders}. Of is the name a
+// wrapping message such as "{of} (Mirror)" is built around. The English Name
+// stays alongside it for tools and older app builds.
+type NameRef struct {
+	Key    string         `json:"key"`
+	Params map[string]int `json:"params,omitempty"`
+	Of     *NameRef       `json:"of,omitempty"`
+}
@@ -8,3 +8,12 @@ type Parable struct {
 	Reflection      string `json:"reflection"`
 	BackgroundImage string `json:"background_image"`
 }
+
+// Scripture is a journal entry unlocked when its trigger level is completed
+type Scripture struct {
+	ID           string `json:"id"`
+	TriggerLevel string `json:"trigger_level"` // Logical level key, e.g. "lvl_seed_05" or "lesson_5"
+	Reference    string `json:"reference"`
+	Title        string `json:"title"`
+	Type         string `json:"type"` // "starter" or "supporting"
+}
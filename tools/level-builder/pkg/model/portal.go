@@ -0,0 +1,77 @@
+package model
+
+// Portal links two empty cells. A vine head moving into either cell comes out
+// of the other and keeps going in the same direction, so exit rays and slides
+// continue from the twin. Portal cells never hold vine segments.
+type Portal struct {
+	A Point `json:"a"`
+	B Point `json:"b"`
+}
+
+// PortalTwin returns the cell linked to p and whether p is a portal cell.
+func (l *Level) PortalTwin(p Point) (Point, bool) {
+	for _, pt := range l.Portals {
+		switch p {
+		case pt.A:
+			return pt.B, true
+		case pt.B:
+			return pt.A, true
+		}
+	}
+	return Point{}, false
+}
+
+// IsPortal reports whether (x, y) is a portal cell.
+func (l *Level) IsPortal(x, y int) bool {
+	_, ok := l.PortalTwin(Point{X: x, Y: y})
+	return ok
+}
+
+// ExitRay walks the cells a head at start moves through when it leaves the
+// grid in direction dir, passing through portals, and calls visit for each one
+// (portal cells themselves are skipped). Walking stops when visit returns
+// false. ok reports whether the ray reached the grid edge; exit is then the
+// first cell beyond it. A ray that portals send around in a loop never
+// reaches the edge.
+func (l *Level) ExitRay(start Point, dir string, visit func(Point) bool) (exit Point, ok bool) {
+	d, known := headDeltas[dir]
+	if !known {
+		return Point{}, false
+	}
+	w, h := l.GetGridWidth(), l.GetGridHeight()
+
+	// The direction never changes, so entering any portal cell twice is a loop
+	jumps := 0
+	p := start
+	for {
+		p = Point{X: p.X + d.X, Y: p.Y + d.Y}
+		if p.X < 0 || p.X >= w || p.Y < 0 || p.Y >= h {
+			return p, true
+		}
+		if twin, isPortal := l.PortalTwin(p); isPortal {
+			if jumps++; jumps > 2*len(l.Portals) {
+				return Point{}, false
+			}
+			p = twin
+			continue
+		}
+		if !visit(p) {
+			return Point{}, false
+		}
+	}
+}
+
+// NextCell returns the first cell ExitRay visits from p in direction dir: the
+// cell a head at p moves into next. inGrid is false when the head would leave
+// the grid instead, or when loops is set: portals send the head around a loop
+// before it reaches any cell or the edge, so it can never move.
+func (l *Level) NextCell(p Point, dir string) (next Point, inGrid, loops bool) {
+	if _, known := headDeltas[dir]; !known {
+		return Point{}, false, false
+	}
+	_, exits := l.ExitRay(p, dir, func(q Point) bool {
+		next, inGrid = q, true
+		return false
+	})
+	return next, inGrid, !inGrid && !exits
+}
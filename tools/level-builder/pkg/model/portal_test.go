@@ -0,0 +1,67 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExitRay(t *testing.T) {
+	// 5x3 grid; (1,0) links to (3,2)
+	lvl := Level{GridSize: []int{5, 3}, Portals: []Portal{{A: Point{X: 1, Y: 0}, B: Point{X: 3, Y: 2}}}}
+	collect := func(start Point, dir string) ([]Point, Point, bool) {
+		var cells []Point
+		exit, ok := lvl.ExitRay(start, dir, func(p Point) bool {
+			cells = append(cells, p)
+			return true
+		})
+		return cells, exit, ok
+	}
+
+	cells, exit, ok := collect(Point{X: 0, Y: 1}, "right")
+	if want := []Point{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1}, {X: 4, Y: 1}}; !ok || !reflect.DeepEqual(cells, want) || exit != (Point{X: 5, Y: 1}) {
+		t.Errorf("plain ray = %v exit %v ok %v, want %v exit (5,1)", cells, exit, ok, want)
+	}
+
+	// Entering (1,0) from the left continues from (3,2)
+	cells, exit, ok = collect(Point{X: 0, Y: 0}, "right")
+	if want := []Point{{X: 4, Y: 2}}; !ok || !reflect.DeepEqual(cells, want) || exit != (Point{X: 5, Y: 2}) {
+		t.Errorf("portal ray = %v exit %v ok %v, want %v exit (5,2)", cells, exit, ok, want)
+	}
+
+	if _, ok := lvl.ExitRay(Point{X: 0, Y: 0}, "right", func(Point) bool { return false }); ok {
+		t.Error("ray stopped by visit should not reach the edge")
+	}
+
+	// (0,1) and (4,1) send a horizontal ray around the row forever
+	loop := Level{GridSize: []int{5, 3}, Portals: []Portal{{A: Point{X: 0, Y: 1}, B: Point{X: 4, Y: 1}}}}
+	if _, ok := loop.ExitRay(Point{X: 2, Y: 1}, "right", func(Point) bool { return true }); ok {
+		t.Error("looping ray should not reach the edge")
+	}
+}
+
+func TestNextCell(t *testing.T) {
+	lvl := Level{GridSize: []int{5, 3}, Portals: []Portal{{A: Point{X: 1, Y: 0}, B: Point{X: 3, Y: 2}}}}
+	if next, inGrid, loops := lvl.NextCell(Point{X: 0, Y: 0}, "right"); !inGrid || loops || next != (Point{X: 4, Y: 2}) {
+		t.Errorf("NextCell through a portal = %v, %v, %v; want (4,2) in grid", next, inGrid, loops)
+	}
+	if _, inGrid, loops := lvl.NextCell(Point{X: 4, Y: 1}, "right"); inGrid || loops {
+		t.Errorf("NextCell at the edge = %v, %v; want an exit", inGrid, loops)
+	}
+
+	// Two facing portals: moving right from (0,0) enters (1,0), comes back out
+	// of (0,0) and enters (1,0) again without ever reaching a cell or the edge
+	trap := Level{GridSize: []int{4, 1}, Portals: []Portal{{A: Point{X: 0, Y: 0}, B: Point{X: 1, Y: 0}}}}
+	if _, inGrid, loops := trap.NextCell(Point{X: 0, Y: 0}, "right"); inGrid || !loops {
+		t.Errorf("NextCell into a portal loop = %v, %v; want a loop, not an exit", inGrid, loops)
+	}
+}
+
+func TestPortalTwin(t *testing.T) {
+	lvl := Level{Portals: []Portal{{A: Point{X: 1, Y: 0}, B: Point{X: 3, Y: 2}}}}
+	if twin, ok := lvl.PortalTwin(Point{X: 3, Y: 2}); !ok || twin != (Point{X: 1, Y: 0}) {
+		t.Errorf("PortalTwin(3,2) = %v, %v", twin, ok)
+	}
+	if lvl.IsPortal(2, 2) {
+		t.Error("(2,2) is not a portal")
+	}
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxStencilShare is the largest share of a grid a stencil may leave empty.
+const MaxStencilShare = 0.5
+
+// Stencil is a pattern of decorative gaps, such as letters or an icon, that
+// generation leaves empty but visible. Cells use grid orientation: X grows to
+// the right and Y grows upward, so the pattern's top row has the highest Y.
+type Stencil struct {
+	Width  int
+	Height int
+	Cells  []Point
+}
+
+// ParseStencil reads a stencil drawn as text, one grid row per line with the
+// top row first. '.' and ' ' are playable cells and any other character is a
+// gap. Blank lines before and after the drawing are ignored.
+func ParseStencil(text string) (*Stencil, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " \t\r")
+	}
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	s := &Stencil{Height: len(lines)}
+	for row, line := range lines {
+		cols := []rune(line)
+		s.Width = max(s.Width, len(cols))
+		for x, c := range cols {
+			if c == '\t' {
+				return nil, fmt.Errorf("stencil row %d: tabs are ambiguous, use '.' or spaces", row+1)
+			}
+			if c != '.' && c != ' ' {
+				s.Cells = append(s.Cells, Point{X: x, Y: len(lines) - 1 - row})
+			}
+		}
+	}
+	if len(s.Cells) == 0 {
+		return nil, fmt.Errorf("stencil has no gap cells")
+	}
+	return s, nil
+}
+
+// Place centers the stencil on a width x height grid and returns its gap
+// cells there. It fails when the stencil does not fit or would leave more
+// than MaxStencilShare of the grid empty.
+func (s *Stencil) Place(width, height int) ([]Point, error) {
+	if s.Width > width || s.Height > height {
+		return nil, fmt.Errorf("stencil is %dx%d, larger than the %dx%d grid", s.Width, s.Height, width, height)
+	}
+	if share := float64(len(s.Cells)) / float64(width*height); share > MaxStencilShare {
+		return nil, fmt.Errorf("stencil leaves %.0f%% of the %dx%d grid empty (at most %.0f%%)",
+			share*100, width, height, MaxStencilShare*100)
+	}
+	dx, dy := (width-s.Width)/2, (height-s.Height)/2
+	cells := make([]Point, len(s.Cells))
+	for i, p := range s.Cells {
+		cells[i] = Point{X: p.X + dx, Y: p.Y + dy}
+	}
+	return cells, nil
+}
@@ -0,0 +1,13 @@
+package model
+
+// LevelTheme is optional presentation metadata the game uses to theme a board.
+// Module generation derives it from the module's theme_seed.
+type LevelTheme struct {
+	Biome string `json:"biome"` // Board art set, e.g. "forest" or "meadow"
+	// DecorationDensity is the share (0-1) of empty and masked cells the game
+	// fills with decorations
+	DecorationDensity float64 `json:"decoration_density"`
+	// AccentCells are empty or masked cells drawn with the biome's accent
+	// decoration; never a vine or portal cell
+	AccentCells []Point `json:"accent_cells,omitempty"`
+}
@@ -1,14 +1,168 @@
 package model
 
-// Vine represents a single game entity
+import "fmt"
+
+// Vine represents a single game entity.
+//
+// Construct vines with NewVine rather than a struct literal so the path and
+// head direction are validated together. Literal construction outside this
+// package is flagged by the vine literal guard test at the module root.
+//
+// A multi-head vine also has a head at the end of its path: TailDirection is
+// set and the vine may leave either head first, sliding along whichever exit
+// ray is clear. Use WithTailHead to add the second head.
+//
+// A locked vine (LockedUntil > 0) cannot move until that many other vines
+// have cleared, whatever its exit rays look like.
 type Vine struct {
 	ID            string  `json:"id"`
 	HeadDirection string  `json:"head_direction"` // "up", "down", "left", "right"
 	OrderedPath   []Point `json:"ordered_path"`
-	ColorIndex    int     `json:"color_index,omitempty"` // Index into Level.ColorScheme
+	ColorIndex    int     `json:"color_index,omitempty"`    // Index into Level.ColorScheme
+	TailDirection string  `json:"tail_direction,omitempty"` // Exit direction of the tail head on multi-head vines
+	LockedUntil   int     `json:"locked_until,omitempty"`   // Clears needed before the vine may move
+}
+
+// headDeltas maps head directions to their (dx, dy) unit vector.
+// Origin is lower-left, so "up" increases Y.
+var headDeltas = map[string]Point{
+	"up":    {X: 0, Y: 1},
+	"down":  {X: 0, Y: -1},
+	"left":  {X: -1, Y: 0},
+	"right": {X: 1, Y: 0},
+}
+
+// NewVine builds a vine from an ordered path (head first) and validates it.
+// When headDir is empty it is derived from the head and neck positions;
+// otherwise it must agree with them.
+func NewVine(id string, path []Point, headDir string) (Vine, error) {
+	derived, err := validatePath(id, path)
+	if err != nil {
+		return Vine{}, err
+	}
+
+	if headDir == "" {
+		headDir = derived
+	} else if _, ok := headDeltas[headDir]; !ok {
+		return Vine{}, fmt.Errorf("vine %s: invalid head direction %q", id, headDir)
+	} else if headDir != derived {
+		return Vine{}, fmt.Errorf("vine %s: head direction %q does not match path geometry (%s)", id, headDir, derived)
+	}
+
+	return Vine{ID: id, HeadDirection: headDir, OrderedPath: path}, nil
+}
+
+// Validate checks that the vine's path and head directions are consistent
+// and that its lock is not negative.
+func (v Vine) Validate() error {
+	if v.LockedUntil < 0 {
+		return fmt.Errorf("vine %s: locked_until must not be negative, got %d", v.ID, v.LockedUntil)
+	}
+	nv, err := NewVine(v.ID, v.OrderedPath, v.HeadDirection)
+	if err != nil || v.TailDirection == "" {
+		return err
+	}
+	_, err = nv.WithTailHead(v.TailDirection)
+	return err
+}
+
+// WithTailHead returns a copy of v with a second head at the end of its path.
+// When tailDir is empty it is derived from the last two cells; otherwise it
+// must agree with them.
+func (v Vine) WithTailHead(tailDir string) (Vine, error) {
+	n := len(v.OrderedPath)
+	if n < 2 {
+		return Vine{}, fmt.Errorf("vine %s: path needs at least 2 cells, got %d", v.ID, n)
+	}
+	derived, ok := directionOf(v.OrderedPath[n-1], v.OrderedPath[n-2])
+	if !ok {
+		return Vine{}, fmt.Errorf("vine %s: tail and the cell before it are not adjacent", v.ID)
+	}
+	if tailDir == "" {
+		tailDir = derived
+	} else if _, ok := headDeltas[tailDir]; !ok {
+		return Vine{}, fmt.Errorf("vine %s: invalid tail direction %q", v.ID, tailDir)
+	} else if tailDir != derived {
+		return Vine{}, fmt.Errorf("vine %s: tail direction %q does not match path geometry (%s)", v.ID, tailDir, derived)
+	}
+	v.TailDirection = tailDir
+	return v, nil
+}
+
+// IsMultiHead reports whether the vine can also leave tail first.
+func (v Vine) IsMultiHead() bool {
+	return v.TailDirection != ""
+}
+
+// IsLocked reports whether the vine is still locked after cleared other
+// vines have left the grid.
+func (v Vine) IsLocked(cleared int) bool {
+	return cleared < v.LockedUntil
+}
+
+// Reversed returns the vine as seen when it leaves tail first: the path runs
+// from the tail and the head directions are swapped. Only meaningful for
+// multi-head vines.
+func (v Vine) Reversed() Vine {
+	path := make([]Point, len(v.OrderedPath))
+	for i, p := range v.OrderedPath {
+		path[len(path)-1-i] = p
+	}
+	v.OrderedPath = path
+	v.HeadDirection, v.TailDirection = v.TailDirection, v.HeadDirection
+	return v
+}
+
+// Heads returns the ways the vine can leave: itself, plus its reversal when
+// it is multi-head.
+func (v Vine) Heads() []Vine {
+	if !v.IsMultiHead() {
+		return []Vine{v}
+	}
+	return []Vine{v, v.Reversed()}
 }
 
 // Length returns the number of segments in the vine's path.
 func (v Vine) Length() int {
 	return len(v.OrderedPath)
 }
+
+// validatePath checks path length, contiguity and self-overlap and returns
+// the head direction implied by the head and neck.
+func validatePath(id string, path []Point) (string, error) {
+	if len(path) < 2 {
+		return "", fmt.Errorf("vine %s: path needs at least 2 cells, got %d", id, len(path))
+	}
+
+	seen := make(map[Point]bool, len(path))
+	for i, p := range path {
+		if seen[p] {
+			return "", fmt.Errorf("vine %s: path revisits (%d,%d)", id, p.X, p.Y)
+		}
+		seen[p] = true
+		if i > 0 && !adjacent(path[i-1], p) {
+			return "", fmt.Errorf("vine %s: segments %d and %d are not adjacent", id, i-1, i)
+		}
+	}
+
+	if dir, ok := directionOf(path[0], path[1]); ok {
+		return dir, nil
+	}
+	return "", fmt.Errorf("vine %s: head and neck are not adjacent", id)
+}
+
+// directionOf returns the direction a head at end faces away from next.
+func directionOf(end, next Point) (string, bool) {
+	delta := Point{X: end.X - next.X, Y: end.Y - next.Y}
+	for dir, d := range headDeltas {
+		if d == delta {
+			return dir, true
+		}
+	}
+	return "", false
+}
+
+func adjacent(a, b Point) bool {
+	dx, dy := a.X-b.X, a.Y-b.Y
+	return dx*dx+dy*dy == 1
+}
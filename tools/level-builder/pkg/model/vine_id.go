@@ -0,0 +1,81 @@
+package model
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// VineIDPrefix starts every canonical vine ID: vine_1, vine_2, ...
+const VineIDPrefix = "vine_"
+
+// VineID returns the canonical ID of the n-th vine (1-based).
+func VineID(n int) string {
+	return VineIDPrefix + strconv.Itoa(n)
+}
+
+// VineIndex returns n for a canonical vine ID vine_n, and false for any
+// other ID.
+func VineIndex(id string) (int, bool) {
+	digits, ok := strings.CutPrefix(id, VineIDPrefix)
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(digits)
+	if err != nil || n < 1 || VineID(n) != id {
+		return 0, false
+	}
+	return n, true
+}
+
+// NextVineIndex returns the first canonical index above every canonical ID
+// in vines, so a placer adding vines to them can number on without
+// colliding.
+func NextVineIndex(vines []Vine) int {
+	next := 1
+	for _, v := range vines {
+		if n, ok := VineIndex(v.ID); ok && n >= next {
+			next = n + 1
+		}
+	}
+	return next
+}
+
+// RenumberVines sorts the level's vines by head position in raster order
+// (top row first, since "up" increases Y, then left to right) and gives
+// them canonical IDs in that order. Colors, locks and directions stay with
+// their vines and Hints and SolutionOrder follow the rename. It returns the old ID of each
+// vine whose ID changed, keyed by its new ID.
+func (l *Level) RenumberVines() map[string]string {
+	sort.SliceStable(l.Vines, func(i, j int) bool {
+		a, b := l.Vines[i].OrderedPath, l.Vines[j].OrderedPath
+		if len(a) == 0 || len(b) == 0 {
+			return len(a) > len(b) // vines without a head go last
+		}
+		if a[0].Y != b[0].Y {
+			return a[0].Y > b[0].Y
+		}
+		return a[0].X < b[0].X
+	})
+
+	renamed := make(map[string]string)
+	newIDs := make(map[string]string, len(l.Vines))
+	for i := range l.Vines {
+		id := VineID(i + 1)
+		if old := l.Vines[i].ID; old != id {
+			renamed[id] = old
+			if _, dup := newIDs[old]; !dup {
+				newIDs[old] = id
+			}
+		}
+		l.Vines[i].ID = id
+	}
+	for _, order := range [][]string{l.Hints, l.SolutionOrder} {
+		for i, old := range order {
+			if id, ok := newIDs[old]; ok {
+				order[i] = id
+			}
+		}
+	}
+	return renamed
+}
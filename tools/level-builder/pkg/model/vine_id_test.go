@@ -0,0 +1,56 @@
+package model
+
+import "testing"
+
+func TestVineIndex(t *testing.T) {
+	for id, want := range map[string]int{"vine_1": 1, "vine_12": 12, "v7": 0, "vine_07": 0, "vine_0": 0, "vine_x": 0} {
+		got, ok := VineIndex(id)
+		if got != want || ok != (want > 0) {
+			t.Errorf("VineIndex(%q) = %d, %v; want %d", id, got, ok, want)
+		}
+	}
+	vines := []Vine{{ID: "vine_3"}, {ID: "v9"}, {ID: "vine_1"}}
+	if n := NextVineIndex(vines); n != 4 {
+		t.Errorf("NextVineIndex = %d, want 4", n)
+	}
+}
+
+func TestRenumberVines(t *testing.T) {
+	l := Level{
+		Vines: []Vine{
+			{ID: "v1", OrderedPath: []Point{{X: 2, Y: 0}, {X: 1, Y: 0}}, ColorIndex: 1},
+			{ID: "vine_7", OrderedPath: []Point{{X: 0, Y: 2}, {X: 0, Y: 1}}, ColorIndex: 2},
+			{ID: "vine_2", OrderedPath: []Point{{X: 2, Y: 2}, {X: 2, Y: 1}}, ColorIndex: 3, LockedUntil: 1},
+		},
+		Hints:         []string{"v1", "vine_2"},
+		SolutionOrder: []string{"vine_2", "v1", "vine_7"},
+	}
+	renamed := l.RenumberVines()
+
+	want := []struct {
+		id    string
+		color int
+	}{{"vine_1", 2}, {"vine_2", 3}, {"vine_3", 1}}
+	for i, w := range want {
+		if v := l.Vines[i]; v.ID != w.id || v.ColorIndex != w.color {
+			t.Errorf("vine %d = %s color %d, want %s color %d", i, v.ID, v.ColorIndex, w.id, w.color)
+		}
+	}
+	if l.Vines[1].LockedUntil != 1 {
+		t.Error("lock did not stay with its vine")
+	}
+	if l.Hints[0] != "vine_3" || l.Hints[1] != "vine_2" {
+		t.Errorf("Hints = %v, want [vine_3 vine_2]", l.Hints)
+	}
+	if o := l.SolutionOrder; o[0] != "vine_2" || o[1] != "vine_3" || o[2] != "vine_1" {
+		t.Errorf("SolutionOrder = %v, want [vine_2 vine_3 vine_1]", l.SolutionOrder)
+	}
+	if len(renamed) != 2 || renamed["vine_1"] != "vine_7" || renamed["vine_3"] != "v1" {
+		t.Errorf("renamed = %v", renamed)
+	}
+
+	// A second pass finds nothing to do
+	if again := l.RenumberVines(); len(again) != 0 {
+		t.Errorf("second RenumberVines renamed %v", again)
+	}
+}
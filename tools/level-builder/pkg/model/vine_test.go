@@ -0,0 +1,146 @@
+package model
+
+import "testing"
+
+func TestNewVine(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    []Point
+		headDir string
+		wantDir string
+		wantErr bool
+	}{
+		{
+			name:    "derives direction when omitted",
+			path:    []Point{{X: 2, Y: 3}, {X: 2, Y: 2}, {X: 1, Y: 2}},
+			wantDir: "up",
+		},
+		{
+			name:    "accepts matching direction",
+			path:    []Point{{X: 0, Y: 0}, {X: 1, Y: 0}},
+			headDir: "left",
+			wantDir: "left",
+		},
+		{
+			name:    "rejects mismatched direction",
+			path:    []Point{{X: 0, Y: 0}, {X: 1, Y: 0}},
+			headDir: "right",
+			wantErr: true,
+		},
+		{
+			name:    "rejects unknown direction",
+			path:    []Point{{X: 0, Y: 1}, {X: 0, Y: 0}},
+			headDir: "north",
+			wantErr: true,
+		},
+		{
+			name:    "rejects empty path",
+			wantErr: true,
+		},
+		{
+			name:    "rejects single cell",
+			path:    []Point{{X: 0, Y: 0}},
+			wantErr: true,
+		},
+		{
+			name:    "rejects gaps",
+			path:    []Point{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 3}},
+			wantErr: true,
+		},
+		{
+			name:    "rejects revisited cells",
+			path:    []Point{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 0}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewVine("v1", tt.path, tt.headDir)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got vine %+v", v)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if v.HeadDirection != tt.wantDir {
+				t.Errorf("HeadDirection = %q, want %q", v.HeadDirection, tt.wantDir)
+			}
+			if err := v.Validate(); err != nil {
+				t.Errorf("Validate() on constructed vine: %v", err)
+			}
+		})
+	}
+}
+
+func TestWithTailHead(t *testing.T) {
+	v, err := NewVine("v1", []Point{{X: 2, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 0}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.IsMultiHead() {
+		t.Fatal("NewVine should build a single-head vine")
+	}
+
+	mh, err := v.WithTailHead("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mh.TailDirection != "down" || !mh.IsMultiHead() {
+		t.Fatalf("TailDirection = %q, want down", mh.TailDirection)
+	}
+	if err := mh.Validate(); err != nil {
+		t.Errorf("Validate() on multi-head vine: %v", err)
+	}
+	if _, err := v.WithTailHead("up"); err == nil {
+		t.Error("expected mismatched tail direction to be rejected")
+	}
+	bad := mh
+	bad.TailDirection = "left"
+	if err := bad.Validate(); err == nil {
+		t.Error("expected Validate to reject a mismatched tail direction")
+	}
+
+	rev := mh.Reversed()
+	if rev.HeadDirection != "down" || rev.TailDirection != "right" || rev.OrderedPath[0] != (Point{X: 1, Y: 0}) {
+		t.Errorf("Reversed() = %+v", rev)
+	}
+	if err := rev.Validate(); err != nil {
+		t.Errorf("Validate() on reversed vine: %v", err)
+	}
+	if got := len(mh.Heads()); got != 2 {
+		t.Errorf("Heads() = %d views, want 2", got)
+	}
+	if got := len(v.Heads()); got != 1 {
+		t.Errorf("single-head Heads() = %d views, want 1", got)
+	}
+}
+
+func TestLockedUntil(t *testing.T) {
+	v, err := NewVine("v1", []Point{{X: 1, Y: 0}, {X: 0, Y: 0}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.IsLocked(0) {
+		t.Error("a vine without a lock should never be locked")
+	}
+
+	v.LockedUntil = 2
+	if !v.IsLocked(1) || v.IsLocked(2) {
+		t.Errorf("IsLocked(1), IsLocked(2) = %v, %v; want true, false", v.IsLocked(1), v.IsLocked(2))
+	}
+	if err := v.Validate(); err != nil {
+		t.Errorf("Validate() on locked vine: %v", err)
+	}
+	if mh, err := v.WithTailHead(""); err != nil || mh.Reversed().LockedUntil != 2 {
+		t.Errorf("reversed multi-head view lost its lock: %v", err)
+	}
+
+	v.LockedUntil = -1
+	if err := v.Validate(); err == nil {
+		t.Error("expected Validate to reject a negative lock")
+	}
+}
@@ -0,0 +1,273 @@
+// Package modules checks and edits the module registry (assets/data/modules.json)
+// against the level files it refers to.
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// Issue kinds reported by Check.
+const (
+	KindMissingLevel = "missing-level" // a module names a level with no file
+	KindDuplicate    = "duplicate"     // a level is named more than once
+	KindDifficulty   = "difficulty"    // a level's difficulty does not match its slot
+	KindChallenge    = "challenge"     // a challenge level that is not Transcendent
+	KindThemeSeed    = "theme-seed"    // a missing or unknown theme_seed
+	KindStaleMapping = "stale-mapping" // a level_mappings entry pointing at no file
+	KindOrphan       = "orphan"        // a level file no module, mirror or mapping reaches
+)
+
+// Issue is one problem Check found in the registry.
+type Issue struct {
+	Kind    string `json:"kind"`
+	Module  int    `json:"module,omitempty"` // 0 for registry-wide issues
+	Key     string `json:"key,omitempty"`    // logical level key, or the file name of an orphan
+	Message string `json:"message"`
+	Fixable bool   `json:"fixable"` // Fix repairs it
+}
+
+func (i Issue) String() string {
+	where := "registry"
+	if i.Module != 0 {
+		where = fmt.Sprintf("module %d", i.Module)
+	}
+	return fmt.Sprintf("[%s] %s: %s", i.Kind, where, i.Message)
+}
+
+// Paths locates the files a registry refers to.
+type Paths struct {
+	Assets string // level_mappings entries are relative to it
+	Levels string // holds level_<id>.json
+}
+
+// DefaultPaths returns the workspace's assets and levels directories.
+func DefaultPaths() (Paths, error) {
+	assets, err := common.AssetsDir()
+	if err != nil {
+		return Paths{}, fmt.Errorf("failed to resolve assets directory: %w", err)
+	}
+	levels, err := common.LevelsDir()
+	if err != nil {
+		return Paths{}, fmt.Errorf("failed to resolve levels directory: %w", err)
+	}
+	return Paths{Assets: assets, Levels: levels}, nil
+}
+
+// LevelFile returns the file behind a logical level key: its level_mappings
+// entry, or level_<id>.json by common.PhysicalLevelID when it has none. ok is
+// false when neither names a file.
+func (p Paths) LevelFile(reg *model.ModuleRegistry, key string) (path string, ok bool) {
+	if rel, found := reg.LevelMappings[key]; found {
+		return filepath.Join(p.Assets, rel), true
+	}
+	if id, found := common.PhysicalLevelID(key); found {
+		return common.GetLevelFilePath(id, p.Levels), true
+	}
+	return "", false
+}
+
+// Check cross-checks reg against the level files under paths:
+//
+//   - every level and challenge level a module names has a file, and no level is
+//     named twice
+//   - each level's difficulty matches its slot in generator.ModuleProgression,
+//     and every challenge level is Transcendent
+//   - every module's theme_seed is a known biome (generator.IsThemeSeed)
+//   - every level_mappings entry points at a file, and every level file is
+//     reached by a module, a mirror or a mapping
+//
+// Issues come back in module order, then registry-wide ones.
+func Check(reg *model.ModuleRegistry, paths Paths) ([]Issue, error) {
+	var issues []Issue
+	reached := make(map[string]bool) // cleaned paths of level files referenced
+	named := make(map[string]int)    // key -> module that named it first
+
+	for _, m := range reg.Modules {
+		if !generator.IsThemeSeed(m.ThemeSeed) {
+			issues = append(issues, Issue{
+				Kind: KindThemeSeed, Module: m.ID, Fixable: generator.IsThemeSeed(generator.ThemeSeedFor(m.ID)),
+				Message: fmt.Sprintf("theme_seed %q is not a known biome", m.ThemeSeed),
+			})
+		}
+
+		slots := append(append([]string{}, m.Levels...), m.ChallengeLevel)
+		for i, key := range slots {
+			challenge := i == len(slots)-1
+			if challenge && key == "" {
+				issues = append(issues, Issue{Kind: KindChallenge, Module: m.ID, Message: "no challenge level"})
+				continue
+			}
+			if first, dup := named[key]; dup {
+				issues = append(issues, Issue{
+					Kind: KindDuplicate, Module: m.ID, Key: key, Fixable: !challenge,
+					Message: fmt.Sprintf("%s is already named by module %d", key, first),
+				})
+				continue
+			}
+			named[key] = m.ID
+
+			lvl, err := loadLevel(reg, paths, key, reached)
+			if err != nil {
+				issues = append(issues, Issue{Kind: KindMissingLevel, Module: m.ID, Key: key, Message: err.Error()})
+				continue
+			}
+			want := "Transcendent"
+			if !challenge {
+				if i >= len(generator.ModuleProgression)-1 {
+					continue // past the progression; nothing to compare against
+				}
+				want = generator.ModuleProgression[i]
+			}
+			if lvl.Difficulty != want {
+				kind, what := KindDifficulty, fmt.Sprintf("slot %d", i+1)
+				if challenge {
+					kind, what = KindChallenge, "challenge level"
+				}
+				issues = append(issues, Issue{
+					Kind: kind, Module: m.ID, Key: key,
+					Message: fmt.Sprintf("%s %s is %s, want %s", what, key, lvl.Difficulty, want),
+				})
+			}
+		}
+		for _, key := range sortedValues(m.Mirrors) {
+			if path, ok := paths.LevelFile(reg, key); ok {
+				reached[filepath.Clean(path)] = true
+			}
+		}
+	}
+
+	for _, key := range sortedKeys(reg.LevelMappings) {
+		path := filepath.Join(paths.Assets, reg.LevelMappings[key])
+		if _, err := os.Stat(path); err != nil {
+			issues = append(issues, Issue{
+				Kind: KindStaleMapping, Key: key, Fixable: true,
+				Message: fmt.Sprintf("level_mappings[%s] = %s has no file", key, reg.LevelMappings[key]),
+			})
+			continue
+		}
+		reached[filepath.Clean(path)] = true
+	}
+
+	files, err := filepath.Glob(filepath.Join(paths.Levels, "level_*.json"))
+	if err != nil {
+		return issues, err
+	}
+	sort.Slice(files, func(i, j int) bool { return levelFileLess(files[i], files[j]) })
+	for _, f := range files {
+		if !reached[filepath.Clean(f)] {
+			issues = append(issues, Issue{
+				Kind: KindOrphan, Key: filepath.Base(f),
+				Message: fmt.Sprintf("%s is not part of any module", filepath.Base(f)),
+			})
+		}
+	}
+	return issues, nil
+}
+
+// Fix repairs the fixable issues in reg and returns what it changed:
+//
+//   - an unknown theme_seed is reset to generator.ThemeSeedFor the module, when
+//     that is a known biome
+//   - a regular level named a second time is dropped from the later module
+//   - a stale level_mappings entry is pointed at level_<id>.json when that file
+//     exists, and removed otherwise
+//
+// Check reg again afterwards for the issues that need a person: missing files,
+// difficulties and orphans.
+func Fix(reg *model.ModuleRegistry, paths Paths) []string {
+	var fixed []string
+	named := make(map[string]bool)
+	for i := range reg.Modules {
+		m := &reg.Modules[i]
+		if seed := generator.ThemeSeedFor(m.ID); !generator.IsThemeSeed(m.ThemeSeed) && generator.IsThemeSeed(seed) {
+			fixed = append(fixed, fmt.Sprintf("module %d: theme_seed %q -> %q", m.ID, m.ThemeSeed, seed))
+			m.ThemeSeed = seed
+		}
+		kept := m.Levels[:0]
+		for _, key := range m.Levels {
+			if named[key] {
+				fixed = append(fixed, fmt.Sprintf("module %d: dropped duplicate %s", m.ID, key))
+				continue
+			}
+			named[key] = true
+			kept = append(kept, key)
+		}
+		m.Levels = kept
+		named[m.ChallengeLevel] = true
+	}
+
+	for _, key := range sortedKeys(reg.LevelMappings) {
+		rel := reg.LevelMappings[key]
+		if _, err := os.Stat(filepath.Join(paths.Assets, rel)); err == nil {
+			continue
+		}
+		if id, ok := common.PhysicalLevelID(key); ok {
+			path := common.GetLevelFilePath(id, paths.Levels)
+			if _, err := os.Stat(path); err == nil {
+				if next, err := filepath.Rel(paths.Assets, path); err == nil {
+					next = filepath.ToSlash(next)
+					reg.LevelMappings[key] = next
+					fixed = append(fixed, fmt.Sprintf("level_mappings[%s]: %s -> %s", key, rel, next))
+					continue
+				}
+			}
+		}
+		delete(reg.LevelMappings, key)
+		fixed = append(fixed, fmt.Sprintf("level_mappings[%s]: removed %s", key, rel))
+	}
+	return fixed
+}
+
+// loadLevel reads the level behind key and marks its file reached.
+func loadLevel(reg *model.ModuleRegistry, paths Paths, key string, reached map[string]bool) (*model.Level, error) {
+	path, ok := paths.LevelFile(reg, key)
+	if !ok {
+		return nil, fmt.Errorf("%s has no level_mappings entry", key)
+	}
+	lvl, err := common.ReadLevel(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%s: %s does not exist", key, filepath.Base(path))
+		}
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+	reached[filepath.Clean(path)] = true
+	return lvl, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedValues(m map[string]string) []string {
+	var values []string
+	for _, k := range sortedKeys(m) {
+		values = append(values, m[k])
+	}
+	return values
+}
+
+// levelFileLess orders level_N.json names by N, falling back to plain string order.
+func levelFileLess(a, b string) bool {
+	var na, nb int
+	_, errA := fmt.Sscanf(filepath.Base(a), "level_%d.json", &na)
+	_, errB := fmt.Sscanf(filepath.Base(b), "level_%d.json", &nb)
+	if errA == nil && errB == nil && na != nb {
+		return na < nb
+	}
+	return a < b
+}
@@ -0,0 +1,87 @@
+package modules
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// testPaths writes a level file for each id with the given difficulty.
+func testPaths(t *testing.T, difficulties map[int]string) Paths {
+	t.Helper()
+	assets := t.TempDir()
+	paths := Paths{Assets: assets, Levels: filepath.Join(assets, "levels")}
+	for id, difficulty := range difficulties {
+		lvl := model.Level{ID: id, Name: "test", Difficulty: difficulty, GridSize: []int{3, 3}}
+		if err := common.WriteLevel(common.GetLevelFilePath(id, paths.Levels), &lvl, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return paths
+}
+
+func kinds(issues []Issue) []string {
+	var out []string
+	for _, i := range issues {
+		out = append(out, i.Kind+" "+i.Key)
+	}
+	return out
+}
+
+func TestCheckFindsRegistryProblems(t *testing.T) {
+	paths := testPaths(t, map[int]string{1: "Seedling", 2: "Sprout", 21: "Flourishing", 50: "Seedling"})
+	reg := &model.ModuleRegistry{
+		LevelMappings: map[string]string{"lvl_seed_02": "levels/old_2.json"},
+		Modules: []model.Module{
+			{ID: 1, ThemeSeed: "nowhere", Levels: []string{"lvl_seed_01", "lvl_seed_02", "lvl_seed_03"}, ChallengeLevel: "lvl_seed_challenge"},
+			{ID: 2, ThemeSeed: "meadow", Levels: []string{"lvl_seed_01"}, ChallengeLevel: "lvl_sprout_challenge"},
+		},
+	}
+
+	issues, err := Check(reg, paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"theme-seed ",
+		"missing-level lvl_seed_02", // its mapping points at a file that is gone
+		"missing-level lvl_seed_03",
+		"challenge lvl_seed_challenge",
+		"duplicate lvl_seed_01",
+		"missing-level lvl_sprout_challenge",
+		"stale-mapping lvl_seed_02",
+		"orphan level_2.json",
+		"orphan level_50.json",
+	}
+	if got := kinds(issues); !slices.Equal(got, want) {
+		t.Errorf("Check =\n%v\nwant\n%v", got, want)
+	}
+
+	fixed := Fix(reg, paths)
+	if len(fixed) != 3 {
+		t.Errorf("Fix made %d repairs, want 3: %v", len(fixed), fixed)
+	}
+	if reg.Modules[0].ThemeSeed != "forest" || len(reg.Modules[1].Levels) != 0 || reg.LevelMappings["lvl_seed_02"] != "levels/level_2.json" {
+		t.Errorf("Fix left %+v", reg)
+	}
+
+	issues, _ = Check(reg, paths)
+	want = []string{
+		"difficulty lvl_seed_02",
+		"missing-level lvl_seed_03",
+		"challenge lvl_seed_challenge",
+		"missing-level lvl_sprout_challenge",
+		"orphan level_50.json",
+	}
+	if got := kinds(issues); !slices.Equal(got, want) {
+		t.Errorf("Check after Fix =\n%v\nwant\n%v", got, want)
+	}
+	for _, i := range issues {
+		if i.Fixable {
+			t.Errorf("%v still marked fixable", i)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package modules
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// Positions in a module's level list are 1-based. End appends a level and
+// Challenge names the module's challenge level instead of a regular slot.
+const (
+	End       = 0
+	Challenge = -1
+)
+
+// AddModule appends a new module. Its ID and name must not be taken, and none
+// of its levels may belong to another module.
+func AddModule(reg *model.ModuleRegistry, m model.Module) error {
+	if m.ID <= 0 {
+		return fmt.Errorf("module ID must be positive, got %d", m.ID)
+	}
+	for _, other := range reg.Modules {
+		if other.ID == m.ID {
+			return fmt.Errorf("module %d already exists", m.ID)
+		}
+		if m.Name != "" && other.Name == m.Name {
+			return fmt.Errorf("module %d is already named %q", other.ID, m.Name)
+		}
+	}
+	for _, key := range append(slices.Clone(m.Levels), m.ChallengeLevel) {
+		if key == "" {
+			continue
+		}
+		if owner, _ := find(reg, key); owner != nil {
+			return fmt.Errorf("%s already belongs to module %d", key, owner.ID)
+		}
+	}
+	reg.Modules = append(reg.Modules, m)
+	return nil
+}
+
+// RemoveModule deletes a module. A module that still has levels is only
+// removed with force, so levels are not dropped from the game by accident.
+func RemoveModule(reg *model.ModuleRegistry, moduleID int, force bool) error {
+	i := slices.IndexFunc(reg.Modules, func(m model.Module) bool { return m.ID == moduleID })
+	if i < 0 {
+		return fmt.Errorf("module %d not found", moduleID)
+	}
+	if m := reg.Modules[i]; !force && (len(m.Levels) > 0 || m.ChallengeLevel != "") {
+		return fmt.Errorf("module %d still has %d levels; move them or force the removal", moduleID, len(m.Levels)+boolInt(m.ChallengeLevel != ""))
+	}
+	reg.Modules = slices.Delete(reg.Modules, i, i+1)
+	return nil
+}
+
+// RenameModule changes a module's name, which must not be another module's.
+func RenameModule(reg *model.ModuleRegistry, moduleID int, name string) error {
+	if name == "" {
+		return fmt.Errorf("module name must not be empty")
+	}
+	for _, m := range reg.Modules {
+		if m.Name == name && m.ID != moduleID {
+			return fmt.Errorf("module %d is already named %q", m.ID, name)
+		}
+	}
+	m := module(reg, moduleID)
+	if m == nil {
+		return fmt.Errorf("module %d not found", moduleID)
+	}
+	// A hand-picked name replaces the standard one
+	m.Name, m.NameRef = name, nil
+	return nil
+}
+
+// MoveModule moves a module to position pos (1-based) in the module list,
+// which is the order the game presents modules in.
+func MoveModule(reg *model.ModuleRegistry, moduleID, pos int) error {
+	i := slices.IndexFunc(reg.Modules, func(m model.Module) bool { return m.ID == moduleID })
+	if i < 0 {
+		return fmt.Errorf("module %d not found", moduleID)
+	}
+	if pos < 1 || pos > len(reg.Modules) {
+		return fmt.Errorf("position %d outside 1-%d", pos, len(reg.Modules))
+	}
+	m := reg.Modules[i]
+	reg.Modules = slices.Insert(slices.Delete(reg.Modules, i, i+1), pos-1, m)
+	return nil
+}
+
+// AddLevel puts key into a module at pos: a 1-based slot among its levels,
+// End or Challenge. key must not already belong to any module, and a module
+// takes a new challenge level only once its old one is removed.
+func AddLevel(reg *model.ModuleRegistry, moduleID int, key string, pos int) error {
+	if key == "" {
+		return fmt.Errorf("level key must not be empty")
+	}
+	if owner, _ := find(reg, key); owner != nil {
+		return fmt.Errorf("%s already belongs to module %d", key, owner.ID)
+	}
+	m := module(reg, moduleID)
+	if m == nil {
+		return fmt.Errorf("module %d not found", moduleID)
+	}
+	return insert(m, key, pos)
+}
+
+// RemoveLevel takes key out of whichever module holds it, along with its
+// mirror pairing, and returns that module's ID.
+func RemoveLevel(reg *model.ModuleRegistry, key string) (int, error) {
+	m, i := find(reg, key)
+	if m == nil {
+		return 0, fmt.Errorf("%s is not in any module", key)
+	}
+	take(m, i)
+	delete(m.Mirrors, key)
+	return m.ID, nil
+}
+
+// MoveLevel moves key to pos in a module, which may be the module it is
+// already in; that reorders it. Its mirror pairing moves with it.
+func MoveLevel(reg *model.ModuleRegistry, key string, moduleID, pos int) error {
+	from, i := find(reg, key)
+	if from == nil {
+		return fmt.Errorf("%s is not in any module", key)
+	}
+	to := module(reg, moduleID)
+	if to == nil {
+		return fmt.Errorf("module %d not found", moduleID)
+	}
+	if pos == Challenge && to.ChallengeLevel != "" && to.ChallengeLevel != key {
+		return fmt.Errorf("module %d already has challenge level %s", to.ID, to.ChallengeLevel)
+	}
+	if last := len(to.Levels) + boolInt(from != to || i == Challenge); pos > last || pos < Challenge {
+		return fmt.Errorf("position %d outside 1-%d", pos, last)
+	}
+
+	take(from, i)
+	if err := insert(to, key, pos); err != nil {
+		return err
+	}
+	if mirror, ok := from.Mirrors[key]; ok && from != to {
+		delete(from.Mirrors, key)
+		if to.Mirrors == nil {
+			to.Mirrors = make(map[string]string)
+		}
+		to.Mirrors[key] = mirror
+	}
+	return nil
+}
+
+// ModuleOf returns the ID of the module holding key as a level or its
+// challenge level.
+func ModuleOf(reg *model.ModuleRegistry, key string) (int, bool) {
+	if m, _ := find(reg, key); m != nil {
+		return m.ID, true
+	}
+	return 0, false
+}
+
+// module returns the module with the given ID, or nil.
+func module(reg *model.ModuleRegistry, moduleID int) *model.Module {
+	for i := range reg.Modules {
+		if reg.Modules[i].ID == moduleID {
+			return &reg.Modules[i]
+		}
+	}
+	return nil
+}
+
+// find returns the module holding key and its index among the module's
+// levels, or Challenge for its challenge level. The module is nil when no
+// module holds key.
+func find(reg *model.ModuleRegistry, key string) (*model.Module, int) {
+	for mi := range reg.Modules {
+		m := &reg.Modules[mi]
+		if i := slices.Index(m.Levels, key); i >= 0 {
+			return m, i
+		}
+		if m.ChallengeLevel == key {
+			return m, Challenge
+		}
+	}
+	return nil, 0
+}
+
+// insert puts key into m at pos; see AddLevel.
+func insert(m *model.Module, key string, pos int) error {
+	switch {
+	case pos == Challenge:
+		if m.ChallengeLevel != "" {
+			return fmt.Errorf("module %d already has challenge level %s", m.ID, m.ChallengeLevel)
+		}
+		m.ChallengeLevel = key
+	case pos == End:
+		m.Levels = append(m.Levels, key)
+	case pos < 1 || pos > len(m.Levels)+1:
+		return fmt.Errorf("position %d outside 1-%d", pos, len(m.Levels)+1)
+	default:
+		m.Levels = slices.Insert(m.Levels, pos-1, key)
+	}
+	return nil
+}
+
+// take removes the level at index i (or Challenge) from m.
+func take(m *model.Module, i int) {
+	if i == Challenge {
+		m.ChallengeLevel = ""
+		return
+	}
+	m.Levels = slices.Delete(m.Levels, i, i+1)
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,92 @@
+package modules
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func testRegistry() *model.ModuleRegistry {
+	return &model.ModuleRegistry{Modules: []model.Module{
+		{ID: 1, Name: "One", Levels: []string{"a1", "a2", "a3"}, ChallengeLevel: "ac", Mirrors: map[string]string{"a2": "a2_mirror"}},
+		{ID: 2, Name: "Two", Levels: []string{"b1"}},
+	}}
+}
+
+func TestLevelEdits(t *testing.T) {
+	reg := testRegistry()
+	if err := AddLevel(reg, 2, "a1", End); err == nil {
+		t.Error("AddLevel accepted a level another module holds")
+	}
+	if err := AddLevel(reg, 1, "ax", Challenge); err == nil {
+		t.Error("AddLevel replaced an existing challenge level")
+	}
+	if err := AddLevel(reg, 2, "b0", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddLevel(reg, 2, "bc", Challenge); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reorder within a module, then move across with the mirror pairing
+	if err := MoveLevel(reg, "a3", 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := MoveLevel(reg, "a2", 2, End); err != nil {
+		t.Fatal(err)
+	}
+	if err := MoveLevel(reg, "a1", 1, 3); err == nil {
+		t.Error("MoveLevel accepted a position past the end")
+	}
+	if one, two := reg.Modules[0], reg.Modules[1]; !slices.Equal(one.Levels, []string{"a3", "a1"}) ||
+		!slices.Equal(two.Levels, []string{"b0", "b1", "a2"}) || two.ChallengeLevel != "bc" ||
+		len(one.Mirrors) != 0 || two.Mirrors["a2"] != "a2_mirror" {
+		t.Errorf("after moves: %+v", reg.Modules)
+	}
+
+	if from, err := RemoveLevel(reg, "ac"); err != nil || from != 1 || reg.Modules[0].ChallengeLevel != "" {
+		t.Errorf("RemoveLevel(ac) = %d, %v; challenge %q", from, err, reg.Modules[0].ChallengeLevel)
+	}
+	if err := MoveLevel(reg, "bc", 1, Challenge); err != nil || reg.Modules[0].ChallengeLevel != "bc" || reg.Modules[1].ChallengeLevel != "" {
+		t.Errorf("MoveLevel(bc) = %v; modules %+v", err, reg.Modules)
+	}
+	if id, ok := ModuleOf(reg, "bc"); !ok || id != 1 {
+		t.Errorf("ModuleOf(bc) = %d, %v", id, ok)
+	}
+}
+
+func TestModuleEdits(t *testing.T) {
+	reg := testRegistry()
+	if err := AddModule(reg, model.Module{ID: 2, Name: "Dup"}); err == nil {
+		t.Error("AddModule reused a module ID")
+	}
+	if err := AddModule(reg, model.Module{ID: 3, Name: "Three", Levels: []string{"b1"}}); err == nil {
+		t.Error("AddModule took a level another module holds")
+	}
+	if err := AddModule(reg, model.Module{ID: 3, Name: "Three"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RenameModule(reg, 3, "One"); err == nil {
+		t.Error("RenameModule reused a module name")
+	}
+	if err := RenameModule(reg, 3, "Bonus"); err != nil {
+		t.Fatal(err)
+	}
+	if err := MoveModule(reg, 3, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveModule(reg, 2, false); err == nil {
+		t.Error("RemoveModule dropped a module with levels")
+	}
+	if err := RemoveModule(reg, 2, true); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, m := range reg.Modules {
+		got = append(got, m.Name)
+	}
+	if !slices.Equal(got, []string{"Bonus", "One"}) {
+		t.Errorf("modules = %v, want [Bonus One]", got)
+	}
+}
@@ -0,0 +1,82 @@
+package naming
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// CatalogFile is the names catalog's file name in the assets data directory.
+const CatalogFile = "names.json"
+
+// Catalog is data/names.json: the English text of every key in use and each
+// level's and module's name reference, keyed by ID. A translation supplies
+// its own Messages for the same keys; the references stay as they are.
+type Catalog struct {
+	Locale   string                   `json:"locale"`
+	Messages map[string]string        `json:"messages"`
+	Levels   map[string]model.NameRef `json:"levels"`
+	Modules  map[string]model.NameRef `json:"modules"`
+}
+
+// BuildCatalog collects the names of levels and of the registry's modules,
+// through ForLevel and ForModule. A custom key's message is the English name
+// it was read from. Unnamed levels are left out.
+func BuildCatalog(registry *model.ModuleRegistry, levels []*model.Level) (*Catalog, error) {
+	c := &Catalog{
+		Locale:   "en",
+		Messages: maps.Clone(English),
+		Levels:   make(map[string]model.NameRef),
+		Modules:  make(map[string]model.NameRef),
+	}
+	addName := func(ref *model.NameRef, name, owner string) error {
+		if _, standard := English[ref.Key]; standard {
+			if got := Format(c.Messages, *ref); ref.Of == nil && got != name {
+				return fmt.Errorf("%s: name %q does not match its name_ref %q; update or remove name_ref", owner, name, got)
+			}
+			return nil
+		}
+		if prev, ok := c.Messages[ref.Key]; ok && prev != name {
+			return fmt.Errorf("%s: key %s is both %q and %q", owner, ref.Key, prev, name)
+		}
+		c.Messages[ref.Key] = name
+		return nil
+	}
+
+	for _, lvl := range levels {
+		ref := ForLevel(lvl)
+		if ref == nil {
+			continue
+		}
+		if err := addName(ref, lvl.Name, fmt.Sprintf("level %d", lvl.ID)); err != nil {
+			return nil, err
+		}
+		c.Levels[strconv.Itoa(lvl.ID)] = *ref
+	}
+	if registry != nil {
+		for i := range registry.Modules {
+			m := &registry.Modules[i]
+			ref := ForModule(m)
+			if ref == nil {
+				continue
+			}
+			if err := addName(ref, m.Name, fmt.Sprintf("module %d", m.ID)); err != nil {
+				return nil, err
+			}
+			c.Modules[strconv.Itoa(m.ID)] = *ref
+		}
+	}
+
+	// Every wrapped custom name must resolve, or the app would show a blank
+	for _, lvl := range levels {
+		ref, ok := c.Levels[strconv.Itoa(lvl.ID)]
+		for of := ref.Of; ok && of != nil; of = of.Of {
+			if _, defined := c.Messages[of.Key]; !defined {
+				return nil, fmt.Errorf("level %d: %s names %s, which no level defines", lvl.ID, ref.Key, of.Key)
+			}
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,145 @@
+// Package naming gives level and module names as message keys with
+// parameters, so the app can localize titles instead of parsing English
+// strings. Generators attach a model.NameRef to every name they write,
+// Render turns a reference back into English, and BuildCatalog collects the
+// names of every level and module into the names catalog, data/names.json,
+// which translators and the app work from.
+package naming
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// Message keys of the standard names.
+const (
+	KeyLevel     = "level.name"           // Level {number}
+	KeyChallenge = "level.name.challenge" // Module {module} - Transcendent
+	KeyMirror    = "level.name.mirror"    // {of} (Mirror)
+	KeyVariant   = "level.name.variant"   // {of} (Variant {variant})
+	KeyModule    = "module.name"          // Module {module}
+)
+
+// English holds the English text of the standard keys. Placeholders are
+// ICU-style: {name} is the named parameter and {of} the wrapped name.
+var English = map[string]string{
+	KeyLevel:     "Level {number}",
+	KeyChallenge: "Module {module} - Transcendent",
+	KeyMirror:    "{of} (Mirror)",
+	KeyVariant:   "{of} (Variant {variant})",
+	KeyModule:    "Module {module}",
+}
+
+// Level names a regular level by its number.
+func Level(id int) model.NameRef {
+	return model.NameRef{Key: KeyLevel, Params: map[string]int{"number": id}}
+}
+
+// Challenge names a module's Transcendent challenge level.
+func Challenge(moduleID int) model.NameRef {
+	return model.NameRef{Key: KeyChallenge, Params: map[string]int{"module": moduleID}}
+}
+
+// Mirror names the mirrored copy of a level named of.
+func Mirror(of model.NameRef) model.NameRef {
+	return model.NameRef{Key: KeyMirror, Of: &of}
+}
+
+// Variant names the n-th gameplay-preserving variant of a level named of.
+func Variant(of model.NameRef, n int) model.NameRef {
+	return model.NameRef{Key: KeyVariant, Params: map[string]int{"variant": n}, Of: &of}
+}
+
+// Module names a module by its number.
+func Module(id int) model.NameRef {
+	return model.NameRef{Key: KeyModule, Params: map[string]int{"module": id}}
+}
+
+// CustomLevelKey and CustomModuleKey are the keys of hand-picked names, whose
+// catalog message is the English name itself.
+func CustomLevelKey(id int) string  { return fmt.Sprintf("level.%d.name", id) }
+func CustomModuleKey(id int) string { return fmt.Sprintf("module.%d.name", id) }
+
+// Render returns ref in English, or "" when it uses a custom key.
+func Render(ref model.NameRef) string {
+	return Format(English, ref)
+}
+
+// Format fills ref's message from messages with its parameters. A key
+// missing from messages gives "".
+func Format(messages map[string]string, ref model.NameRef) string {
+	text, ok := messages[ref.Key]
+	if !ok {
+		return ""
+	}
+	if ref.Of != nil {
+		text = strings.ReplaceAll(text, "{of}", Format(messages, *ref.Of))
+	}
+	for name, v := range ref.Params {
+		text = strings.ReplaceAll(text, "{"+name+"}", strconv.Itoa(v))
+	}
+	return text
+}
+
+var (
+	levelName     = regexp.MustCompile(`^Level (\d+)$`)
+	challengeName = regexp.MustCompile(`^Module (\d+) - Transcendent$`)
+	moduleName    = regexp.MustCompile(`^Module (\d+)$`)
+	variantName   = regexp.MustCompile(`^(.*) \(Variant (\d+)\)$`)
+)
+
+// ForLevel returns lvl.NameRef or, for a level written before names had
+// references, one read from its English name: the standard level, challenge,
+// mirror and variant names map to their keys and any other name to
+// CustomLevelKey(lvl.ID). A level without a name gets nil.
+func ForLevel(lvl *model.Level) *model.NameRef {
+	if lvl.NameRef != nil {
+		return lvl.NameRef
+	}
+	if lvl.Name == "" {
+		return nil
+	}
+	ref := inferLevel(lvl.Name, lvl.ID, lvl.MirrorOf, lvl.VariantOf)
+	return &ref
+}
+
+func inferLevel(name string, id, mirrorOf, variantOf int) model.NameRef {
+	if m := levelName.FindStringSubmatch(name); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return Level(n)
+	}
+	if m := challengeName.FindStringSubmatch(name); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return Challenge(n)
+	}
+	// A mirror of a hand-named level points at its source's custom name
+	if inner, ok := strings.CutSuffix(name, " (Mirror)"); ok && mirrorOf > 0 {
+		return Mirror(inferLevel(inner, mirrorOf, 0, 0))
+	}
+	if m := variantName.FindStringSubmatch(name); m != nil && variantOf > 0 {
+		n, _ := strconv.Atoi(m[2])
+		return Variant(inferLevel(m[1], variantOf, 0, 0), n)
+	}
+	return model.NameRef{Key: CustomLevelKey(id)}
+}
+
+// ForModule returns m.NameRef or one read from its English name, like
+// ForLevel: "Module N" is standard and any other name is custom.
+func ForModule(m *model.Module) *model.NameRef {
+	if m.NameRef != nil {
+		return m.NameRef
+	}
+	if m.Name == "" {
+		return nil
+	}
+	if match := moduleName.FindStringSubmatch(m.Name); match != nil {
+		n, _ := strconv.Atoi(match[1])
+		ref := Module(n)
+		return &ref
+	}
+	return &model.NameRef{Key: CustomModuleKey(m.ID)}
+}
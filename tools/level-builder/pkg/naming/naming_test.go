@@ -0,0 +1,89 @@
+package naming
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func TestRender(t *testing.T) {
+	for _, tt := range []struct {
+		ref  model.NameRef
+		want string
+	}{
+		{Level(22), "Level 22"},
+		{Challenge(3), "Module 3 - Transcendent"},
+		{Mirror(Level(4)), "Level 4 (Mirror)"},
+		{Variant(Level(12), 2), "Level 12 (Variant 2)"},
+		{Module(6), "Module 6"},
+		{model.NameRef{Key: CustomLevelKey(7)}, ""},
+	} {
+		if got := Render(tt.ref); got != tt.want {
+			t.Errorf("Render(%+v) = %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestForLevelReadsEnglishNames(t *testing.T) {
+	for _, tt := range []struct {
+		lvl  model.Level
+		want string
+	}{
+		{model.Level{ID: 30, Name: "Level 30"}, "Level 30"},
+		{model.Level{ID: 21, Name: "Module 1 - Transcendent"}, "Module 1 - Transcendent"},
+		{model.Level{ID: 130, Name: "Level 9 (Mirror)", MirrorOf: 9}, "Level 9 (Mirror)"},
+		{model.Level{ID: 140, Name: "Level 12 (Variant 1)", VariantOf: 12}, "Level 12 (Variant 1)"},
+	} {
+		ref := ForLevel(&tt.lvl)
+		if ref == nil || Render(*ref) != tt.want {
+			t.Errorf("ForLevel(%q) = %+v", tt.lvl.Name, ref)
+		}
+	}
+
+	custom := ForLevel(&model.Level{ID: 131, Name: "Sunrise (Mirror)", MirrorOf: 40})
+	if custom.Key != KeyMirror || custom.Of.Key != CustomLevelKey(40) {
+		t.Errorf("mirror of a custom name = %+v, want it to wrap level 40's key", custom)
+	}
+	if ref := ForLevel(&model.Level{ID: 5}); ref != nil {
+		t.Errorf("unnamed level got %+v", ref)
+	}
+}
+
+func TestBuildCatalog(t *testing.T) {
+	challenge := Challenge(2)
+	registry := &model.ModuleRegistry{Modules: []model.Module{
+		{ID: 1, Name: "Seedling"},
+		{ID: 2, Name: "Module 2"},
+	}}
+	levels := []*model.Level{
+		{ID: 40, Name: "Sunrise"},
+		{ID: 41, Name: "Module 2 - Transcendent", NameRef: &challenge},
+		{ID: 131, Name: "Sunrise (Mirror)", MirrorOf: 40},
+		{ID: 132},
+	}
+	c, err := BuildCatalog(registry, levels)
+	if err != nil {
+		t.Fatalf("BuildCatalog: %v", err)
+	}
+	if len(c.Levels) != 3 || len(c.Modules) != 2 {
+		t.Fatalf("catalog = %+v", c)
+	}
+	if got := Format(c.Messages, c.Levels["131"]); got != "Sunrise (Mirror)" {
+		t.Errorf("mirror renders as %q", got)
+	}
+	if got := Format(c.Messages, c.Modules["1"]); got != "Seedling" || c.Modules["2"].Key != KeyModule {
+		t.Errorf("modules = %+v", c.Modules)
+	}
+
+	// A hand-edited name no longer matches its reference
+	levels[1].Name = "The Last Garden"
+	if _, err := BuildCatalog(registry, levels); err == nil || !strings.Contains(err.Error(), "level 41") {
+		t.Errorf("BuildCatalog with a stale name_ref: %v", err)
+	}
+
+	// A mirror whose source is missing cannot be named
+	if _, err := BuildCatalog(nil, levels[2:3]); err == nil {
+		t.Error("BuildCatalog named a mirror of a missing level")
+	}
+}
@@ -0,0 +1,320 @@
+package pack
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/schema"
+)
+
+// A level library (.pbpack) holds a whole levels directory in one file, for
+// level sets too large to ship or diff as loose files. Each level is its own
+// gzip member, so a reader inflates only the levels it asks for:
+//
+//	"PBPK" format (uint16)                     header
+//	one gzip member per level, by ID          the level files, byte for byte
+//	gzip member                               the index, JSON LibraryIndex
+//	index offset, index length (uint64) "PBPK" trailer
+//
+// Integers are big-endian. Opening a library reads the trailer and the index
+// only. Like zip packs, libraries are reproducible.
+
+// LibraryExt is the file extension of a level library.
+const LibraryExt = ".pbpack"
+
+// LibraryFormat is the library layout version in the header and index.
+const LibraryFormat = 1
+
+const (
+	libraryMagic   = "PBPK"
+	libraryHeader  = len(libraryMagic) + 2
+	libraryTrailer = 8 + 8 + len(libraryMagic)
+	// maxIndexSize bounds how far a corrupt index can inflate
+	maxIndexSize = 64 << 20
+)
+
+// LibraryIndex lists a library's levels.
+type LibraryIndex struct {
+	Format int `json:"format"`
+	// Levels are sorted by ID
+	Levels []LibraryEntry `json:"levels"`
+	// Checksum is the SHA-256 of "<sha256>  <file>\n" for each level in order
+	Checksum string `json:"checksum"`
+}
+
+// LibraryEntry is one level in a library. Offset and Length locate its gzip
+// member; Size and SHA256 describe the inflated level file.
+type LibraryEntry struct {
+	ID         int    `json:"id"`
+	File       string `json:"file"`
+	Name       string `json:"name,omitempty"`
+	Difficulty string `json:"difficulty,omitempty"`
+	Offset     int64  `json:"offset"`
+	Length     int64  `json:"length"`
+	Size       int    `json:"size"`
+	SHA256     string `json:"sha256"`
+}
+
+// WriteLibrary writes every level_*.json in dir to w as a level library,
+// checking each against the level schema first, and returns its index.
+func WriteLibrary(w io.Writer, dir string) (*LibraryIndex, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "level_*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no level files in %s", dir)
+	}
+
+	index := &LibraryIndex{Format: LibraryFormat}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if errs := schema.Level().Validate(data); len(errs) > 0 {
+			return nil, fmt.Errorf("%s: %v", filepath.Base(file), errs[0])
+		}
+		var head struct {
+			ID         int    `json:"id"`
+			Name       string `json:"name"`
+			Difficulty string `json:"difficulty"`
+		}
+		if err := json.Unmarshal(data, &head); err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(file), err)
+		}
+		if want := common.GetLevelFilePath(head.ID, ""); filepath.Base(file) != want {
+			return nil, fmt.Errorf("%s holds level %d; it should be named %s", filepath.Base(file), head.ID, want)
+		}
+		sum := sha256.Sum256(data)
+		index.Levels = append(index.Levels, LibraryEntry{
+			ID:         head.ID,
+			File:       filepath.Base(file),
+			Name:       head.Name,
+			Difficulty: head.Difficulty,
+			Size:       len(data),
+			SHA256:     hex.EncodeToString(sum[:]),
+		})
+	}
+	slices.SortFunc(index.Levels, func(a, b LibraryEntry) int { return a.ID - b.ID })
+	index.Checksum = libraryChecksum(index.Levels)
+
+	var buf bytes.Buffer
+	buf.WriteString(libraryMagic)
+	_ = binary.Write(&buf, binary.BigEndian, uint16(LibraryFormat))
+	for i := range index.Levels {
+		e := &index.Levels[i]
+		data, err := os.ReadFile(filepath.Join(dir, e.File))
+		if err != nil {
+			return nil, err
+		}
+		e.Offset = int64(buf.Len())
+		if err := gzipTo(&buf, data); err != nil {
+			return nil, err
+		}
+		e.Length = int64(buf.Len()) - e.Offset
+	}
+
+	indexData, err := json.Marshal(index)
+	if err != nil {
+		return nil, err
+	}
+	indexOffset := int64(buf.Len())
+	if err := gzipTo(&buf, indexData); err != nil {
+		return nil, err
+	}
+	_ = binary.Write(&buf, binary.BigEndian, uint64(indexOffset))
+	_ = binary.Write(&buf, binary.BigEndian, uint64(int64(buf.Len())-indexOffset-8))
+	buf.WriteString(libraryMagic)
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// WriteLibraryFile writes the levels in dir to path as a level library.
+func WriteLibraryFile(path, dir string) (*LibraryIndex, error) {
+	var buf bytes.Buffer
+	index, err := WriteLibrary(&buf, dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := common.WriteFileAtomic(path, buf.Bytes(), 0o644); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// Library is an open level library. Its levels are read on demand.
+type Library struct {
+	Index  LibraryIndex
+	r      io.ReaderAt
+	closer io.Closer
+}
+
+// OpenLibrary reads a library's header, trailer and index from r.
+func OpenLibrary(r io.ReaderAt, size int64) (*Library, error) {
+	if size < int64(libraryHeader+libraryTrailer) {
+		return nil, fmt.Errorf("not a level library: %d bytes", size)
+	}
+	header := make([]byte, libraryHeader)
+	trailer := make([]byte, libraryTrailer)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, err
+	}
+	if _, err := r.ReadAt(trailer, size-int64(libraryTrailer)); err != nil {
+		return nil, err
+	}
+	if string(header[:len(libraryMagic)]) != libraryMagic || string(trailer[16:]) != libraryMagic {
+		return nil, fmt.Errorf("not a level library")
+	}
+	if format := binary.BigEndian.Uint16(header[len(libraryMagic):]); format != LibraryFormat {
+		return nil, fmt.Errorf("library format %d, want %d", format, LibraryFormat)
+	}
+
+	body := size - int64(libraryTrailer)
+	offset, length := binary.BigEndian.Uint64(trailer[:8]), binary.BigEndian.Uint64(trailer[8:16])
+	if offset < uint64(libraryHeader) || offset > uint64(body) || length != uint64(body)-offset {
+		return nil, fmt.Errorf("library index at %d+%d is outside the file", offset, length)
+	}
+	data, err := gunzipSection(r, int64(offset), int64(length), maxIndexSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the library index: %w", err)
+	}
+
+	l := &Library{r: r}
+	if err := json.Unmarshal(data, &l.Index); err != nil {
+		return nil, fmt.Errorf("failed to parse the library index: %w", err)
+	}
+	if l.Index.Format != LibraryFormat {
+		return nil, fmt.Errorf("library index format %d, want %d", l.Index.Format, LibraryFormat)
+	}
+	if got := libraryChecksum(l.Index.Levels); got != l.Index.Checksum {
+		return nil, fmt.Errorf("library checksum %s does not match its levels (%s)", l.Index.Checksum, got)
+	}
+	for i, e := range l.Index.Levels {
+		if i > 0 && e.ID <= l.Index.Levels[i-1].ID {
+			return nil, fmt.Errorf("library index is not sorted by level ID at %s", e.File)
+		}
+		// Extract writes entries by name, so the name must be the plain one
+		if e.File != common.GetLevelFilePath(e.ID, "") {
+			return nil, fmt.Errorf("library entry %q does not name level %d", e.File, e.ID)
+		}
+		if e.Offset < int64(libraryHeader) || e.Length <= 0 || e.Length > int64(offset)-e.Offset {
+			return nil, fmt.Errorf("%s at %d+%d is outside the level data", e.File, e.Offset, e.Length)
+		}
+	}
+	return l, nil
+}
+
+// OpenLibraryFile opens the library at path. Close releases the file.
+func OpenLibraryFile(path string) (*Library, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	l, err := OpenLibrary(f, info.Size())
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	l.closer = f
+	return l, nil
+}
+
+// Close closes the file a library was opened from, if any.
+func (l *Library) Close() error {
+	if l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
+}
+
+// Entry returns the index entry for level id.
+func (l *Library) Entry(id int) (LibraryEntry, bool) {
+	i, found := slices.BinarySearchFunc(l.Index.Levels, id, func(e LibraryEntry, id int) int { return e.ID - id })
+	if !found {
+		return LibraryEntry{}, false
+	}
+	return l.Index.Levels[i], true
+}
+
+// ReadFile inflates one level file and checks it against the index.
+func (l *Library) ReadFile(e LibraryEntry) ([]byte, error) {
+	data, err := gunzipSection(l.r, e.Offset, e.Length, int64(e.Size))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", e.File, err)
+	}
+	sum := sha256.Sum256(data)
+	if len(data) != e.Size || hex.EncodeToString(sum[:]) != e.SHA256 {
+		return nil, fmt.Errorf("%s does not match the library index", e.File)
+	}
+	return data, nil
+}
+
+// Verify reads every level in the library, so a corrupt member is found
+// before the library ships.
+func (l *Library) Verify() error {
+	for _, e := range l.Index.Levels {
+		if _, err := l.ReadFile(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func gzipTo(w io.Writer, data []byte) error {
+	// No name or timestamp in the header keeps libraries reproducible
+	zw, err := gzip.NewWriterLevel(w, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// gunzipSection inflates the gzip member at offset, reading at most limit+1
+// bytes so an oversized member fails its size check instead of filling memory.
+func gunzipSection(r io.ReaderAt, offset, length, limit int64) ([]byte, error) {
+	zr, err := gzip.NewReader(io.NewSectionReader(r, offset, length))
+	if err != nil {
+		return nil, err
+	}
+	// One member per section; a second would belong to the next level
+	zr.Multistream(false)
+	defer func() { _ = zr.Close() }()
+	data, err := io.ReadAll(io.LimitReader(zr, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("inflates past %d bytes", limit)
+	}
+	return data, nil
+}
+
+func libraryChecksum(levels []LibraryEntry) string {
+	h := sha256.New()
+	for _, e := range levels {
+		_, _ = fmt.Fprintf(h, "%s  %s\n", e.SHA256, e.File)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,101 @@
+package pack
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLibraryRoundTrip(t *testing.T) {
+	_, dir := testAssets(t)
+	levels := filepath.Join(dir, "levels")
+
+	var buf bytes.Buffer
+	index, err := WriteLibrary(&buf, levels)
+	if err != nil {
+		t.Fatalf("WriteLibrary: %v", err)
+	}
+	if len(index.Levels) != 2 || index.Levels[0].ID != 1 || index.Levels[1].Name != "Level" {
+		t.Fatalf("index = %+v", index)
+	}
+
+	lib, err := OpenLibrary(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("OpenLibrary: %v", err)
+	}
+	if lib.Index.Checksum != index.Checksum {
+		t.Errorf("opened checksum %s, wrote %s", lib.Index.Checksum, index.Checksum)
+	}
+	e, ok := lib.Entry(2)
+	if !ok {
+		t.Fatal("no entry for level 2")
+	}
+	got, err := lib.ReadFile(e)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	want, _ := os.ReadFile(filepath.Join(levels, "level_2.json"))
+	if !bytes.Equal(got, want) {
+		t.Error("level_2.json changed in the round trip")
+	}
+	if _, ok := lib.Entry(3); ok {
+		t.Error("found a level the library does not hold")
+	}
+
+	// Rewriting gives the same bytes
+	var again bytes.Buffer
+	if _, err := WriteLibrary(&again, levels); err != nil || !bytes.Equal(again.Bytes(), buf.Bytes()) {
+		t.Errorf("rewritten library differs (%v)", err)
+	}
+}
+
+func TestLibraryRejectsCorruption(t *testing.T) {
+	_, dir := testAssets(t)
+	var buf bytes.Buffer
+	index, err := WriteLibrary(&buf, filepath.Join(dir, "levels"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	// A flipped byte in one level fails that level only
+	bad := bytes.Clone(data)
+	e := index.Levels[1]
+	bad[e.Offset+e.Length/2] ^= 0xff
+	lib, err := OpenLibrary(bytes.NewReader(bad), int64(len(bad)))
+	if err != nil {
+		t.Fatalf("OpenLibrary: %v", err)
+	}
+	if _, err := lib.ReadFile(index.Levels[0]); err != nil {
+		t.Errorf("intact level: %v", err)
+	}
+	if err := lib.Verify(); err == nil || !strings.Contains(err.Error(), "level_2") {
+		t.Errorf("Verify of a corrupt level: %v", err)
+	}
+
+	for name, b := range map[string][]byte{
+		"truncated":   data[:len(data)-1],
+		"zip":         []byte("PK\x03\x04 not a library at all"),
+		"bad trailer": append(bytes.Clone(data[:len(data)-20]), make([]byte, 16)...),
+	} {
+		if _, err := OpenLibrary(bytes.NewReader(b), int64(len(b))); err == nil {
+			t.Errorf("%s: OpenLibrary succeeded", name)
+		}
+	}
+}
+
+func TestWriteLibraryChecksLevels(t *testing.T) {
+	_, dir := testAssets(t)
+	levels := filepath.Join(dir, "levels")
+	if err := os.Rename(filepath.Join(levels, "level_2.json"), filepath.Join(levels, "level_9.json")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WriteLibrary(&bytes.Buffer{}, levels); err == nil || !strings.Contains(err.Error(), "level_9.json") {
+		t.Errorf("WriteLibrary with a misnamed level: %v", err)
+	}
+	if _, err := WriteLibrary(&bytes.Buffer{}, t.TempDir()); err == nil {
+		t.Error("WriteLibrary of an empty directory succeeded")
+	}
+}
@@ -0,0 +1,304 @@
+// Package pack bundles modules into a single zip the Flutter app ships as one
+// verified artifact. A pack mirrors the app's assets layout (data/modules.json,
+// levels/level_N.json, lessons/lesson_N.json) and adds manifest.json, which
+// lists every file with its SHA-256 and a pack-level checksum over that list.
+//
+// Packs are reproducible: the same inputs give byte-identical zips, so a
+// release can be rebuilt and compared.
+//
+// The package also writes level libraries (.pbpack), which hold a whole
+// levels directory in one compressed file with an index that lets readers
+// inflate single levels; see WriteLibrary and OpenLibrary.
+package pack
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/schema"
+)
+
+// Format is the pack layout version recorded in the manifest. Bump it when
+// the layout or manifest fields change incompatibly.
+const Format = 1
+
+// ManifestFile is the manifest's path inside the zip.
+const ManifestFile = "manifest.json"
+
+// modTime stamps every zip entry so packs do not depend on when they were built.
+var modTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ModulesFile is the filtered registry's path inside the zip.
+const ModulesFile = "data/modules.json"
+
+// File kinds recorded in the manifest.
+const (
+	KindModules = "modules"
+	KindLevel   = "level"
+	KindLesson  = "lesson"
+)
+
+// Manifest describes a pack's contents.
+type Manifest struct {
+	Format int `json:"format"`
+	// SchemaVersion is the modules.json version the content targets
+	SchemaVersion string `json:"schema_version"`
+	Modules       []int  `json:"modules"`
+	Levels        int    `json:"levels"`
+	Lessons       int    `json:"lessons"`
+	// Files are sorted by path and exclude the manifest itself
+	Files []File `json:"files"`
+	// Checksum is the SHA-256 of "<sha256>  <path>\n" for each file in order
+	Checksum string `json:"checksum"`
+}
+
+// File is one manifest entry.
+type File struct {
+	Path   string `json:"path"`
+	Kind   string `json:"kind"`
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Pack is a built pack ready to write.
+type Pack struct {
+	Manifest Manifest
+	data     map[string][]byte
+}
+
+// Build collects the given modules from registry, reading the files its
+// level_mappings point to under assetsDir. Every tutorial lesson is included,
+// along with each module's levels, challenge level and mirrored levels. Files
+// are checked against their JSON Schemas, and the bundled modules.json keeps
+// only the chosen modules and the mappings the pack contains.
+func Build(registry *model.ModuleRegistry, assetsDir string, moduleIDs []int) (*Pack, error) {
+	if len(moduleIDs) == 0 {
+		return nil, fmt.Errorf("no modules selected")
+	}
+
+	p := &Pack{data: make(map[string][]byte)}
+	sub := model.ModuleRegistry{
+		Version:       registry.Version,
+		Tutorials:     registry.Tutorials,
+		LevelMappings: make(map[string]string),
+	}
+	kinds := make(map[string]string)
+
+	add := func(key, kind string) error {
+		rel, ok := registry.LevelMappings[key]
+		if !ok {
+			return fmt.Errorf("no level_mappings entry for %s", key)
+		}
+		rel = path.Clean(filepath.ToSlash(rel))
+		sub.LevelMappings[key] = rel
+		if _, done := p.data[rel]; done {
+			return nil
+		}
+		data, err := os.ReadFile(filepath.Join(assetsDir, filepath.FromSlash(rel)))
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		s := schema.Level()
+		if kind == KindLesson {
+			s = schema.Lesson()
+		}
+		if errs := s.Validate(data); len(errs) > 0 {
+			return fmt.Errorf("%s (%s): %v", key, rel, errs[0])
+		}
+		p.data[rel] = data
+		kinds[rel] = kind
+		return nil
+	}
+
+	for _, key := range registry.Tutorials {
+		if err := add(key, KindLesson); err != nil {
+			return nil, err
+		}
+	}
+	for i, id := range moduleIDs {
+		if slices.Contains(moduleIDs[:i], id) {
+			return nil, fmt.Errorf("module %d selected twice", id)
+		}
+		mod, err := common.GetModuleByID(registry, id)
+		if err != nil {
+			return nil, err
+		}
+		keys := append([]string(nil), mod.Levels...)
+		if mod.ChallengeLevel != "" {
+			keys = append(keys, mod.ChallengeLevel)
+		}
+		for _, src := range sortedKeys(mod.Mirrors) {
+			keys = append(keys, mod.Mirrors[src])
+		}
+		for _, key := range keys {
+			if err := add(key, KindLevel); err != nil {
+				return nil, fmt.Errorf("module %d: %w", id, err)
+			}
+		}
+		sub.Modules = append(sub.Modules, *mod)
+	}
+
+	modules, err := common.MarshalCanonical(sub, common.LevelIndent)
+	if err != nil {
+		return nil, err
+	}
+	p.data[ModulesFile] = modules
+	kinds[ModulesFile] = KindModules
+
+	p.Manifest = Manifest{
+		Format:        Format,
+		SchemaVersion: registry.Version,
+		Modules:       slices.Clone(moduleIDs),
+	}
+	for _, name := range sortedKeys(kinds) {
+		switch kinds[name] {
+		case KindLevel:
+			p.Manifest.Levels++
+		case KindLesson:
+			p.Manifest.Lessons++
+		}
+		sum := sha256.Sum256(p.data[name])
+		p.Manifest.Files = append(p.Manifest.Files, File{
+			Path:   name,
+			Kind:   kinds[name],
+			Size:   len(p.data[name]),
+			SHA256: hex.EncodeToString(sum[:]),
+		})
+	}
+	p.Manifest.Checksum = checksum(p.Manifest.Files)
+	return p, nil
+}
+
+// Write writes the pack as a zip: the manifest first, then the files in
+// manifest order, with fixed timestamps so identical packs match byte for byte.
+func (p *Pack) Write(w io.Writer) error {
+	manifest, err := json.MarshalIndent(p.Manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	put := func(name string, data []byte) error {
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modTime})
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		return err
+	}
+	if err := put(ManifestFile, append(manifest, '\n')); err != nil {
+		return err
+	}
+	for _, f := range p.Manifest.Files {
+		if err := put(f.Path, p.data[f.Path]); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.Path, err)
+		}
+	}
+	return zw.Close()
+}
+
+// WriteFile writes the pack to path.
+func (p *Pack) WriteFile(path string) error {
+	var buf bytes.Buffer
+	if err := p.Write(&buf); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// Verify checks a pack zip against its manifest: the checksum must match the
+// file list, every listed file must be present with the listed size and
+// SHA-256, and the zip may hold nothing else.
+func Verify(r io.ReaderAt, size int64) (*Manifest, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("not a pack: %w", err)
+	}
+
+	entries := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		entries[f.Name] = f
+	}
+	mf, ok := entries[ManifestFile]
+	if !ok {
+		return nil, fmt.Errorf("pack has no %s", ManifestFile)
+	}
+	data, err := readEntry(mf)
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ManifestFile, err)
+	}
+	if m.Format != Format {
+		return nil, fmt.Errorf("pack format %d, want %d", m.Format, Format)
+	}
+	if got := checksum(m.Files); got != m.Checksum {
+		return nil, fmt.Errorf("manifest checksum %s does not match its files (%s)", m.Checksum, got)
+	}
+
+	for _, f := range m.Files {
+		entry, ok := entries[f.Path]
+		if !ok {
+			return nil, fmt.Errorf("%s is missing", f.Path)
+		}
+		data, err := readEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(data)
+		if len(data) != f.Size || hex.EncodeToString(sum[:]) != f.SHA256 {
+			return nil, fmt.Errorf("%s does not match the manifest", f.Path)
+		}
+		delete(entries, f.Path)
+	}
+	delete(entries, ManifestFile)
+	if len(entries) > 0 {
+		return nil, fmt.Errorf("%s is not in the manifest", strings.Join(sortedKeys(entries), ", "))
+	}
+	return &m, nil
+}
+
+func checksum(files []File) string {
+	h := sha256.New()
+	for _, f := range files {
+		_, _ = fmt.Fprintf(h, "%s  %s\n", f.SHA256, f.Path)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func readEntry(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", f.Name, err)
+	}
+	defer func() { _ = rc.Close() }()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	return data, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,147 @@
+package pack
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func writeJSON(t *testing.T, path string, v any) {
+	t.Helper()
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testAssets lays out two modules of one level each plus a lesson.
+func testAssets(t *testing.T) (*model.ModuleRegistry, string) {
+	dir := t.TempDir()
+	vines := []model.Vine{{ID: "v1", HeadDirection: "right", OrderedPath: []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}}}
+	for id := 1; id <= 2; id++ {
+		writeJSON(t, filepath.Join(dir, "levels", fmt.Sprintf("level_%d.json", id)), model.Level{
+			ID: id, Name: "Level", Difficulty: "Seedling", GridSize: []int{2, 2}, Vines: vines,
+			MaxMoves: 5, MinMoves: 1, Grace: 3, ColorScheme: []string{"#7CB342"},
+		})
+	}
+	writeJSON(t, filepath.Join(dir, "lessons", "lesson_1.json"), model.Lesson{
+		ID: 1, Title: "Lesson 1", LearningPoints: []string{"Tap"}, GridSize: []int{2, 2}, MaxMoves: 999, Vines: vines,
+		AllowedInteractions: []string{model.InteractionTap},
+	})
+	registry := &model.ModuleRegistry{
+		Version:   "3.0",
+		Tutorials: []string{"lesson_1"},
+		LevelMappings: map[string]string{
+			"lesson_1": "lessons/lesson_1.json",
+			"lvl_a":    "levels/level_1.json",
+			"lvl_b":    "levels/level_2.json",
+		},
+		Modules: []model.Module{
+			{ID: 1, Name: "One", Levels: []string{"lvl_a"}},
+			{ID: 2, Name: "Two", Levels: []string{"lvl_b"}},
+		},
+	}
+	return registry, dir
+}
+
+func TestBuildAndVerify(t *testing.T) {
+	registry, dir := testAssets(t)
+	p, err := Build(registry, dir, []int{1})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	m := p.Manifest
+	if m.Format != Format || m.SchemaVersion != "3.0" || m.Levels != 1 || m.Lessons != 1 || len(m.Files) != 3 {
+		t.Fatalf("manifest = %+v", m)
+	}
+
+	var buf bytes.Buffer
+	if err := p.Write(&buf); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	got, err := Verify(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if got.Checksum != m.Checksum {
+		t.Errorf("verified checksum %s, built %s", got.Checksum, m.Checksum)
+	}
+
+	// The bundled registry keeps only module 1 and the files in the pack
+	var sub model.ModuleRegistry
+	if err := json.Unmarshal(p.data[ModulesFile], &sub); err != nil {
+		t.Fatal(err)
+	}
+	if len(sub.Modules) != 1 || sub.Modules[0].ID != 1 || len(sub.LevelMappings) != 2 || sub.LevelMappings["lvl_b"] != "" {
+		t.Errorf("bundled registry = %+v", sub)
+	}
+
+	// Rebuilding gives the same bytes
+	again, _ := Build(registry, dir, []int{1})
+	var buf2 bytes.Buffer
+	_ = again.Write(&buf2)
+	if !bytes.Equal(buf.Bytes(), buf2.Bytes()) {
+		t.Error("rebuilt pack differs")
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	registry, dir := testAssets(t)
+	for name, ids := range map[string][]int{"unknown module": {3}, "duplicate": {1, 1}, "none": nil} {
+		if _, err := Build(registry, dir, ids); err == nil {
+			t.Errorf("%s: Build succeeded", name)
+		}
+	}
+
+	registry.Modules[1].Levels = append(registry.Modules[1].Levels, "lvl_missing")
+	if _, err := Build(registry, dir, []int{2}); err == nil || !strings.Contains(err.Error(), "lvl_missing") {
+		t.Errorf("Build with an unmapped level: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "levels", "level_1.json"), []byte(`{"id": 1, "colour": "red"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Build(registry, dir, []int{1}); err == nil {
+		t.Error("Build accepted a level that fails its schema")
+	}
+}
+
+func TestVerifyRejectsTampering(t *testing.T) {
+	registry, dir := testAssets(t)
+	p, err := Build(registry, dir, []int{1, 2})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	// Swap one level's bytes behind the manifest's back
+	p.data["levels/level_2.json"] = []byte("{}")
+	var buf bytes.Buffer
+	if err := p.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(bytes.NewReader(buf.Bytes()), int64(buf.Len())); err == nil || !strings.Contains(err.Error(), "level_2") {
+		t.Errorf("Verify of a tampered pack: %v", err)
+	}
+
+	// A zip without a manifest is not a pack
+	buf.Reset()
+	zw := zip.NewWriter(&buf)
+	_, _ = zw.Create("levels/level_1.json")
+	_ = zw.Close()
+	if _, err := Verify(bytes.NewReader(buf.Bytes()), int64(buf.Len())); err == nil {
+		t.Error("Verify accepted a zip without a manifest")
+	}
+}
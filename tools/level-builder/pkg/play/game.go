@@ -0,0 +1,216 @@
+// Package play runs a level under the game's rules so designers can try it
+// without launching the Flutter client.
+package play
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// DefaultGrace is the grace the game gives a level that does not set one.
+const DefaultGrace = 3
+
+// Status is where a game stands after the last tap.
+type Status string
+
+const (
+	Playing    Status = "playing"
+	Won        Status = "won"
+	OutOfGrace Status = "out of grace"
+	OutOfMoves Status = "out of moves"
+)
+
+// Tap is the outcome of tapping one vine.
+type Tap struct {
+	VineID    string
+	Cleared   bool
+	Locked    bool        // the vine needs more clears before it may move
+	BlockedBy string      // vine that stopped the head; empty when portals loop it
+	At        model.Point // cell where the head was stopped
+	GraceLost bool        // first failed tap on this vine
+}
+
+// String describes the tap the way the game would show it.
+func (t Tap) String() string {
+	switch {
+	case t.Cleared:
+		return fmt.Sprintf("%s cleared", t.VineID)
+	case t.Locked:
+		return fmt.Sprintf("%s is locked", t.VineID)
+	case t.BlockedBy != "":
+		return fmt.Sprintf("%s is blocked by %s at (%d,%d)", t.VineID, t.BlockedBy, t.At.X, t.At.Y)
+	default:
+		return fmt.Sprintf("%s loops through portals and cannot leave", t.VineID)
+	}
+}
+
+// Game is one playthrough of a level. As in the game, every tap on a vine is
+// a move; a tap that cannot clear bumps the vine and costs one grace the
+// first time that vine fails. Tutorials never drop below one grace. Unlike
+// the client, max_moves is enforced: the game is lost once the moves run out
+// with vines left.
+type Game struct {
+	level     *model.Level
+	cleared   map[string]bool
+	attempted map[string]bool
+	order     []string
+	grace     int
+	maxGrace  int
+	moves     int
+}
+
+// New starts a game on level, which is not modified.
+func New(level *model.Level) *Game {
+	grace := level.Grace
+	if grace <= 0 {
+		grace = DefaultGrace
+	}
+	return &Game{
+		level:     level,
+		cleared:   make(map[string]bool),
+		attempted: make(map[string]bool),
+		grace:     grace,
+		maxGrace:  grace,
+	}
+}
+
+// Grace returns the grace left and the grace the game started with.
+func (g *Game) Grace() (left, total int) { return g.grace, g.maxGrace }
+
+// Moves returns the moves taken so far.
+func (g *Game) Moves() int { return g.moves }
+
+// Cleared returns the vines cleared so far, in order.
+func (g *Game) Cleared() []string { return append([]string(nil), g.order...) }
+
+// Status reports whether the game is still running, won or lost.
+func (g *Game) Status() Status {
+	switch {
+	case len(g.order) == len(g.level.Vines):
+		return Won
+	case g.grace <= 0:
+		return OutOfGrace
+	case g.level.MaxMoves > 0 && g.moves >= g.level.MaxMoves:
+		return OutOfMoves
+	}
+	return Playing
+}
+
+// Board returns a copy of the level holding only the vines still in play.
+func (g *Game) Board() *model.Level {
+	board := *g.level
+	board.Vines = g.Remaining()
+	return &board
+}
+
+// Remaining returns the vines still in play, in level order.
+func (g *Game) Remaining() []model.Vine {
+	var vines []model.Vine
+	for _, v := range g.level.Vines {
+		if !g.cleared[v.ID] {
+			vines = append(vines, v)
+		}
+	}
+	return vines
+}
+
+// VineAt returns the ID of the vine in play covering p.
+func (g *Game) VineAt(p model.Point) (string, bool) {
+	for _, v := range g.Remaining() {
+		for _, c := range v.OrderedPath {
+			if c == p {
+				return v.ID, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Tap tries to clear the vine with the given ID.
+func (g *Game) Tap(id string) (Tap, error) {
+	if s := g.Status(); s != Playing {
+		return Tap{}, fmt.Errorf("game is over: %s", s)
+	}
+	vine, ok := g.vine(id)
+	if !ok {
+		return Tap{}, fmt.Errorf("no vine %q in play", id)
+	}
+
+	g.moves++
+	tap := g.slide(vine)
+	if tap.Cleared {
+		g.cleared[id] = true
+		g.order = append(g.order, id)
+		return tap, nil
+	}
+	if !g.attempted[id] {
+		g.attempted[id] = true
+		if g.grace > 1 || !strings.EqualFold(g.level.Difficulty, "Tutorial") {
+			g.grace--
+			tap.GraceLost = true
+		}
+	}
+	return tap, nil
+}
+
+func (g *Game) vine(id string) (model.Vine, bool) {
+	for _, v := range g.level.Vines {
+		if v.ID == id && !g.cleared[id] {
+			return v, true
+		}
+	}
+	return model.Vine{}, false
+}
+
+// slide moves vine out of the grid by whichever head can leave, following
+// the solver's rule: the body follows the head, so the vine never blocks
+// itself, and portals carry the head across the grid. A failed tap reports
+// the first head's blocker.
+func (g *Game) slide(vine model.Vine) Tap {
+	tap := Tap{VineID: vine.ID}
+	if vine.IsLocked(len(g.order)) {
+		tap.Locked = true
+		return tap
+	}
+
+	owner := make(map[model.Point]string)
+	for _, v := range g.Remaining() {
+		for _, p := range v.OrderedPath {
+			owner[p] = v.ID
+		}
+	}
+
+	for i, head := range vine.Heads() {
+		positions := append([]model.Point(nil), head.OrderedPath...)
+		var blocker string
+		var at model.Point
+		_, ok := g.level.ExitRay(positions[0], head.HeadDirection, func(p model.Point) bool {
+			if id, taken := owner[p]; taken && !containsPoint(positions, p) {
+				blocker, at = id, p
+				return false
+			}
+			copy(positions[1:], positions[:len(positions)-1])
+			positions[0] = p
+			return true
+		})
+		if ok {
+			tap.Cleared = true
+			return tap
+		}
+		if i == 0 {
+			tap.BlockedBy, tap.At = blocker, at
+		}
+	}
+	return tap
+}
+
+func containsPoint(list []model.Point, p model.Point) bool {
+	for _, q := range list {
+		if q == p {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package play
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// a faces right into b; b leaves upward
+func twoVines() *model.Level {
+	return &model.Level{
+		ID:       1,
+		GridSize: []int{4, 4},
+		MaxMoves: 4,
+		Vines: []model.Vine{
+			{ID: "a", HeadDirection: "right", OrderedPath: []model.Point{{X: 1, Y: 1}, {X: 0, Y: 1}}},
+			{ID: "b", HeadDirection: "up", OrderedPath: []model.Point{{X: 2, Y: 1}, {X: 2, Y: 0}}},
+		},
+	}
+}
+
+func mustTap(t *testing.T, g *Game, id string) Tap {
+	t.Helper()
+	tap, err := g.Tap(id)
+	if err != nil {
+		t.Fatalf("Tap(%s): %v", id, err)
+	}
+	return tap
+}
+
+func TestGameBlockedTapCostsGraceOnce(t *testing.T) {
+	g := New(twoVines())
+
+	tap := mustTap(t, g, "a")
+	if tap.Cleared || tap.BlockedBy != "b" || tap.At != (model.Point{X: 2, Y: 1}) || !tap.GraceLost {
+		t.Errorf("first tap on a = %+v, want blocked by b at (2,1) costing grace", tap)
+	}
+	if tap := mustTap(t, g, "a"); tap.GraceLost {
+		t.Error("second failed tap on a cost grace again")
+	}
+	if left, total := g.Grace(); left != 2 || total != DefaultGrace {
+		t.Errorf("grace = %d/%d, want 2/%d", left, total, DefaultGrace)
+	}
+
+	if tap := mustTap(t, g, "b"); !tap.Cleared {
+		t.Errorf("tap on b = %+v, want cleared", tap)
+	}
+	if tap := mustTap(t, g, "a"); !tap.Cleared {
+		t.Errorf("tap on a after b = %+v, want cleared", tap)
+	}
+	if g.Status() != Won || g.Moves() != 4 || !reflect.DeepEqual(g.Cleared(), []string{"b", "a"}) {
+		t.Errorf("status %s after %d moves clearing %v, want won in 4 clearing [b a]", g.Status(), g.Moves(), g.Cleared())
+	}
+	if _, err := g.Tap("a"); err == nil {
+		t.Error("tapping after the game ended succeeded")
+	}
+}
+
+func TestGameEnforcesGraceAndMoves(t *testing.T) {
+	lvl := twoVines()
+	lvl.Grace = 1
+	g := New(lvl)
+	mustTap(t, g, "a")
+	if g.Status() != OutOfGrace {
+		t.Errorf("status = %s, want out of grace", g.Status())
+	}
+
+	lvl = twoVines()
+	lvl.MaxMoves = 2
+	g = New(lvl)
+	mustTap(t, g, "a")
+	mustTap(t, g, "b")
+	if g.Status() != OutOfMoves {
+		t.Errorf("status = %s, want out of moves", g.Status())
+	}
+
+	// Tutorials keep the last grace
+	lvl = twoVines()
+	lvl.Grace, lvl.Difficulty = 1, "Tutorial"
+	g = New(lvl)
+	if tap := mustTap(t, g, "a"); tap.GraceLost || g.Status() != Playing {
+		t.Errorf("tutorial tap = %+v, status %s, want no grace lost", tap, g.Status())
+	}
+}
+
+func TestGameLockedVineAndBoard(t *testing.T) {
+	lvl := twoVines()
+	lvl.Vines[1].LockedUntil = 1
+	g := New(lvl)
+
+	if tap := mustTap(t, g, "b"); !tap.Locked || tap.String() != "b is locked" {
+		t.Errorf("tap on locked b = %+v", tap)
+	}
+	if id, ok := g.VineAt(model.Point{X: 2, Y: 0}); !ok || id != "b" {
+		t.Errorf("VineAt(2,0) = %q, %v, want b", id, ok)
+	}
+	if _, err := g.Tap("c"); err == nil {
+		t.Error("tapping an unknown vine succeeded")
+	}
+	if n := len(g.Board().Vines); n != 2 || len(lvl.Vines) != 2 {
+		t.Errorf("board has %d vines, level %d; want 2 and 2", n, len(lvl.Vines))
+	}
+}
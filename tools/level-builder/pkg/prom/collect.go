@@ -0,0 +1,102 @@
+package prom
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/batch"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+// Namespace prefixes every metric the level builder writes.
+const Namespace = "parable_bloom"
+
+// Histogram buckets for the recorded distributions.
+var (
+	DurationBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+	AttemptBuckets  = []float64{1, 2, 3, 5, 10, 20, 50}
+	CoverageBuckets = []float64{0.8, 0.85, 0.9, 0.93, 0.95, 0.97, 0.99, 1}
+	StateBuckets    = []float64{10, 100, 1e3, 1e4, 1e5, 1e6}
+)
+
+// ObserveBatch records a module batch: levels by result, attempts and
+// fallbacks, and per generated level its attempts, generation time and
+// coverage, labelled by difficulty. Levels resumed from an earlier run are
+// counted but not timed.
+func (r *Registry) ObserveBatch(b *batch.ModuleBatch) {
+	module := strconv.Itoa(b.ModuleID)
+	for _, l := range b.Levels {
+		result := "failure"
+		switch {
+		case l.Resumed:
+			result = "resumed"
+		case l.Success:
+			result = "success"
+		}
+		r.Add(Namespace+"_generation_levels_total", "Levels a batch run finished, by result.",
+			Labels{"module": module, "difficulty": l.Difficulty, "result": result}, 1)
+		if l.Resumed {
+			continue
+		}
+
+		tier := Labels{"difficulty": l.Difficulty}
+		r.Add(Namespace+"_generation_attempts_total", "Generation attempts across all strategies.", tier, float64(l.Attempts))
+		r.Add(Namespace+"_generation_fallbacks_total", "Strategies abandoned before a level succeeded.", tier, float64(l.Fallbacks))
+		r.Observe(Namespace+"_generation_level_attempts", "Generation attempts per level.",
+			AttemptBuckets, tier, float64(l.Attempts))
+		r.Observe(Namespace+"_generation_duration_seconds", "Time spent generating each level.",
+			DurationBuckets, tier, float64(l.GenerationMS)/1000)
+		if l.Success {
+			r.Observe(Namespace+"_generation_coverage_ratio", "Grid coverage of each generated level.",
+				CoverageBuckets, tier, l.Coverage)
+		}
+	}
+	r.Set(Namespace+"_generation_batch_duration_seconds", "Wall-clock time of the last batch run.",
+		Labels{"module": module}, b.TotalTime.Seconds())
+}
+
+// ObserveValidation records a validation report: levels by outcome, timed
+// out and skipped solvability checks, and per solved level the solver's time
+// and states explored, labelled by solver. Cached verdicts are counted but
+// not timed.
+func (r *Registry) ObserveValidation(report validator.Report) {
+	outcomes := []struct {
+		result string
+		n      int
+	}{
+		{"passed", report.Passed},
+		{"failed_structural", report.Structural},
+		{"failed_solvability", report.Unsolvable},
+		{"skipped", report.Skipped},
+	}
+	for _, o := range outcomes {
+		r.Add(Namespace+"_validation_levels_total", "Levels validated, by outcome.", Labels{"result": o.result}, float64(o.n))
+	}
+	r.Add(Namespace+"_validation_timeouts_total", "Solvability checks stopped by a timeout.", nil, float64(report.TimedOut))
+	r.Add(Namespace+"_validation_warned_levels_total", "Levels with warnings, failed or not.", nil, float64(report.Warned))
+
+	for _, l := range report.Levels {
+		s := l.Solvability
+		if s == nil || s.Solver == "cached" {
+			continue
+		}
+		solver := Labels{"solver": s.Solver}
+		r.Observe(Namespace+"_validation_solve_duration_seconds", "Time spent on each level's solvability check.",
+			DurationBuckets, solver, float64(s.TimeMs)/1000)
+		r.Observe(Namespace+"_validation_states_explored", "Solver states explored per level.",
+			StateBuckets, solver, float64(s.StatesExplored))
+	}
+}
+
+// MarkRun records when job last ran and whether it succeeded, so a dashboard
+// can alert on a nightly job that stopped running as well as one that fails.
+func (r *Registry) MarkRun(job string, at time.Time, ok bool) {
+	success := 0.0
+	if ok {
+		success = 1
+	}
+	r.Set(Namespace+"_last_run_timestamp_seconds", "Unix time the job last finished.",
+		Labels{"job": job}, float64(at.UnixNano())/1e9)
+	r.Set(Namespace+"_last_run_success", "Whether the job's last run succeeded (1) or failed (0).",
+		Labels{"job": job}, success)
+}
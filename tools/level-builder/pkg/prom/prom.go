@@ -0,0 +1,212 @@
+// Package prom collects generation and validation metrics and writes them in
+// the Prometheus text exposition format, as a file node_exporter's textfile
+// collector picks up, so nightly regeneration jobs can be watched on
+// dashboards without running a server.
+package prom
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+)
+
+// Labels are the label pairs of one series.
+type Labels map[string]string
+
+// Metric kinds, as written on a family's TYPE line.
+const (
+	kindCounter   = "counter"
+	kindGauge     = "gauge"
+	kindHistogram = "histogram"
+)
+
+// Registry holds metric families. It is safe for concurrent use; the zero
+// value is not, use NewRegistry.
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+type family struct {
+	name, help, kind string
+	buckets          []float64 // histogram upper bounds, ascending, without +Inf
+	series           map[string]*series
+}
+
+// series is one label set's value: value for counters and gauges, bucket
+// counts (not cumulative), sum and count for histograms.
+type series struct {
+	value  float64
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// Add adds v to the counter name for labels. Counter names end in _total.
+func (r *Registry) Add(name, help string, labels Labels, v float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.series(name, help, kindCounter, nil, labels).value += v
+}
+
+// Set sets the gauge name for labels to v.
+func (r *Registry) Set(name, help string, labels Labels, v float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.series(name, help, kindGauge, nil, labels).value = v
+}
+
+// Observe records v in the histogram name for labels. buckets are the upper
+// bounds, ascending; the +Inf bucket is implied. A family keeps the buckets
+// it was first observed with.
+func (r *Registry) Observe(name, help string, buckets []float64, labels Labels, v float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.series(name, help, kindHistogram, buckets, labels)
+	f := r.families[name]
+	i := sort.SearchFloat64s(f.buckets, v) // first bound >= v
+	s.counts[i]++
+	s.sum += v
+	s.count++
+}
+
+// series returns the series for labels in family name, creating both as
+// needed. Reusing a name with another kind is a programming error.
+func (r *Registry) series(name, help, kind string, buckets []float64, labels Labels) *series {
+	f, ok := r.families[name]
+	if !ok {
+		f = &family{name: name, help: help, kind: kind, buckets: buckets, series: make(map[string]*series)}
+		r.families[name] = f
+	}
+	if f.kind != kind {
+		panic(fmt.Sprintf("prom: %s is a %s, not a %s", name, f.kind, kind))
+	}
+	key := formatLabels(labels)
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{}
+		if kind == kindHistogram {
+			s.counts = make([]uint64, len(f.buckets)+1)
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// WriteTo writes every family in the text exposition format, families by
+// name and series by label set, so the same metrics always read the same.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var buf bytes.Buffer
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := r.families[name]
+		fmt.Fprintf(&buf, "# HELP %s %s\n", f.name, helpEscaper.Replace(f.help))
+		fmt.Fprintf(&buf, "# TYPE %s %s\n", f.name, f.kind)
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			f.write(&buf, key, f.series[key])
+		}
+	}
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+func (f *family) write(buf *bytes.Buffer, key string, s *series) {
+	if f.kind != kindHistogram {
+		fmt.Fprintf(buf, "%s%s %s\n", f.name, key, formatValue(s.value))
+		return
+	}
+	var cumulative uint64
+	for i, n := range s.counts {
+		bound := math.Inf(1)
+		if i < len(f.buckets) {
+			bound = f.buckets[i]
+		}
+		cumulative += n
+		fmt.Fprintf(buf, "%s_bucket%s %d\n", f.name, withLabel(key, "le", formatValue(bound)), cumulative)
+	}
+	fmt.Fprintf(buf, "%s_sum%s %s\n", f.name, key, formatValue(s.sum))
+	fmt.Fprintf(buf, "%s_count%s %d\n", f.name, key, s.count)
+}
+
+// WriteFile writes the metrics to path atomically, so a collector never
+// reads a half-written file. Textfile collectors only read *.prom files.
+func (r *Registry) WriteFile(path string) error {
+	var buf bytes.Buffer
+	if _, err := r.WriteTo(&buf); err != nil {
+		return err
+	}
+	if err := common.WriteFileAtomic(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write metrics %s: %w", path, err)
+	}
+	return nil
+}
+
+// formatLabels renders labels as {a="1",b="2"} with keys sorted, or "" when
+// there are none.
+func formatLabels(labels Labels) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + `="` + labelEscaper.Replace(labels[k]) + `"`
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// withLabel adds one more label to a rendered label set.
+func withLabel(key, name, value string) string {
+	pair := name + `="` + labelEscaper.Replace(value) + `"`
+	if key == "" {
+		return "{" + pair + "}"
+	}
+	return key[:len(key)-1] + "," + pair + "}"
+}
+
+// The format escapes backslashes and newlines in HELP text, and double quotes
+// as well in label values.
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
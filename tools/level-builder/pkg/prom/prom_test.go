@@ -0,0 +1,136 @@
+package prom
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/batch"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+func exposition(t *testing.T, r *Registry) string {
+	t.Helper()
+	var b strings.Builder
+	if _, err := r.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestRegistryWritesTextFormat(t *testing.T) {
+	r := NewRegistry()
+	r.Add("jobs_total", "Jobs run.", Labels{"kind": "batch"}, 2)
+	r.Add("jobs_total", "Jobs run.", Labels{"kind": "batch"}, 1)
+	r.Add("jobs_total", "Jobs run.", Labels{"kind": `say "hi"`}, 1)
+	r.Set("up", "Whether it runs.\nLine two.", nil, 1)
+	for _, v := range []float64{0.5, 1, 3} {
+		r.Observe("wait_seconds", "Wait time.", []float64{1, 2}, Labels{"q": "a"}, v)
+	}
+
+	want := `# HELP jobs_total Jobs run.
+# TYPE jobs_total counter
+jobs_total{kind="batch"} 3
+jobs_total{kind="say \"hi\""} 1
+# HELP up Whether it runs.\nLine two.
+# TYPE up gauge
+up 1
+# HELP wait_seconds Wait time.
+# TYPE wait_seconds histogram
+wait_seconds_bucket{q="a",le="1"} 2
+wait_seconds_bucket{q="a",le="2"} 2
+wait_seconds_bucket{q="a",le="+Inf"} 3
+wait_seconds_sum{q="a"} 4.5
+wait_seconds_count{q="a"} 3
+`
+	if got := exposition(t, r); got != want {
+		t.Errorf("exposition:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRegistryRejectsKindChange(t *testing.T) {
+	r := NewRegistry()
+	r.Add("x_total", "", nil, 1)
+	defer func() {
+		if recover() == nil {
+			t.Error("expected reusing a counter as a gauge to panic")
+		}
+	}()
+	r.Set("x_total", "", nil, 1)
+}
+
+func TestObserveBatch(t *testing.T) {
+	r := NewRegistry()
+	r.ObserveBatch(&batch.ModuleBatch{
+		ModuleID:  2,
+		TotalTime: 90 * time.Second,
+		Levels: []batch.Result{
+			{LevelID: 22, Difficulty: "Seedling", Success: true, Attempts: 3, GenerationMS: 1500, Coverage: 0.96},
+			{LevelID: 23, Difficulty: "Seedling", Attempts: 7, Fallbacks: 2, GenerationMS: 40000},
+			{LevelID: 24, Difficulty: "Sprout", Success: true, Resumed: true},
+		},
+	})
+	got := exposition(t, r)
+	for _, line := range []string{
+		`parable_bloom_generation_levels_total{difficulty="Seedling",module="2",result="success"} 1`,
+		`parable_bloom_generation_levels_total{difficulty="Seedling",module="2",result="failure"} 1`,
+		`parable_bloom_generation_levels_total{difficulty="Sprout",module="2",result="resumed"} 1`,
+		`parable_bloom_generation_attempts_total{difficulty="Seedling"} 10`,
+		`parable_bloom_generation_fallbacks_total{difficulty="Seedling"} 2`,
+		`parable_bloom_generation_duration_seconds_sum{difficulty="Seedling"} 41.5`,
+		`parable_bloom_generation_coverage_ratio_count{difficulty="Seedling"} 1`,
+		`parable_bloom_generation_batch_duration_seconds{module="2"} 90`,
+	} {
+		if !strings.Contains(got, line+"\n") {
+			t.Errorf("missing %s in:\n%s", line, got)
+		}
+	}
+	if strings.Contains(got, `_attempts_total{difficulty="Sprout"}`) {
+		t.Error("a resumed level should not count attempts")
+	}
+}
+
+func TestObserveValidation(t *testing.T) {
+	r := NewRegistry()
+	r.ObserveValidation(validator.Report{
+		Passed: 2, Unsolvable: 1, TimedOut: 1,
+		Levels: []validator.LevelResult{
+			{File: "level_1.json", Solvability: &validator.LevelStat{Solver: "greedy-fast", StatesExplored: 0, TimeMs: 2}},
+			{File: "level_2.json", Solvability: &validator.LevelStat{Solver: "astar", StatesExplored: 5000, TimeMs: 1200}},
+			{File: "level_3.json", Solvability: &validator.LevelStat{Solver: "cached"}},
+		},
+	})
+	r.MarkRun("validate", time.Unix(1700000000, 0), false)
+	got := exposition(t, r)
+	for _, line := range []string{
+		`parable_bloom_validation_levels_total{result="passed"} 2`,
+		`parable_bloom_validation_levels_total{result="failed_solvability"} 1`,
+		`parable_bloom_validation_timeouts_total 1`,
+		`parable_bloom_validation_states_explored_bucket{solver="astar",le="10000"} 1`,
+		`parable_bloom_validation_solve_duration_seconds_sum{solver="astar"} 1.2`,
+		`parable_bloom_last_run_timestamp_seconds{job="validate"} 1.7e+09`,
+		`parable_bloom_last_run_success{job="validate"} 0`,
+	} {
+		if !strings.Contains(got, line+"\n") {
+			t.Errorf("missing %s in:\n%s", line, got)
+		}
+	}
+	if strings.Contains(got, `solver="cached"`) {
+		t.Error("cached verdicts should not be timed")
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	r := NewRegistry()
+	r.Set("up", "Up.", nil, 1)
+	path := filepath.Join(t.TempDir(), "level_builder.prom")
+	if err := r.WriteFile(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "# HELP up Up.\n# TYPE up gauge\nup 1\n" {
+		t.Errorf("file = %q, %v", data, err)
+	}
+}
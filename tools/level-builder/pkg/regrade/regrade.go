@@ -0,0 +1,215 @@
+// Package regrade re-scores existing levels with the current
+// metrics.DifficultyScorer and finds those whose stored difficulty tier no
+// longer matches how hard they measure, so the library can follow scorer and
+// band changes without regenerating levels.
+package regrade
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/metrics"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// Tiers lists the tiers levels are graded into, easiest first. Levels of
+// other tiers, such as tutorials, are skipped.
+var Tiers = []string{"Seedling", "Sprout", "Nurturing", "Flourishing", "Transcendent"}
+
+// Options tunes Grade and Scan.
+type Options struct {
+	MaxStates int // Solver budget per level (default metrics.DefaultScorerMaxStates)
+}
+
+// Result is one level's grade.
+type Result struct {
+	File    string
+	Level   *model.Level
+	Metrics metrics.DifficultyMetrics
+	// Measured is the tier the metrics fit best; empty when the level was
+	// skipped or could not be scored
+	Measured string
+	Err      error
+}
+
+// Skipped reports whether the level's tier is not graded.
+func (r Result) Skipped() bool {
+	return r.Err == nil && r.Measured == ""
+}
+
+// Regraded reports whether the level measures as a different tier than the
+// one it stores.
+func (r Result) Regraded() bool {
+	return r.Measured != "" && r.Measured != r.Level.Difficulty
+}
+
+// Stale reports whether Apply would change the level: it is regraded, or
+// its complexity does not match its tier.
+func (r Result) Stale() bool {
+	return r.Measured != "" && (r.Regraded() || r.Level.Complexity != generator.ComplexityFor(r.Measured))
+}
+
+// Apply rewrites the level's Difficulty and Complexity to the measured tier
+// and reports whether anything changed.
+func (r Result) Apply() bool {
+	if !r.Stale() {
+		return false
+	}
+	r.Level.Difficulty = r.Measured
+	r.Level.Complexity = generator.ComplexityFor(r.Measured)
+	return true
+}
+
+// Grade scores level and picks the tier its metrics fit best.
+func Grade(level *model.Level, opts Options) Result {
+	r := Result{Level: level}
+	if !slices.Contains(Tiers, level.Difficulty) {
+		return r
+	}
+	m, err := metrics.DifficultyScorer{MaxStates: opts.MaxStates}.Score(*level)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	r.Metrics = m
+	r.Measured = MeasuredTier(level.Difficulty, m)
+	return r
+}
+
+// MeasuredTier returns the tier whose score and blocking depth bands m fits
+// best. A level inside both bands of its stored tier keeps it, as the
+// generator would accept it there. Otherwise the tier missed by the least
+// wins, band misses measured in score points plus depth levels; ties go to
+// the tier nearest the stored one, then to the harder tier when the score
+// lies above the stored band's middle.
+func MeasuredTier(stored string, m metrics.DifficultyMetrics) string {
+	from := slices.Index(Tiers, stored)
+	harder := false
+	if spec, ok := config.DifficultySpecs[stored]; ok {
+		harder = m.Score > (spec.ScoreRange[0]+spec.ScoreRange[1])/2
+	}
+
+	type candidate struct {
+		tier string
+		miss float64
+		dist int
+	}
+	var cands []candidate
+	for i, tier := range Tiers {
+		spec := config.DifficultySpecs[tier]
+		miss := 0.0
+		if spec.ScoreRange != [2]float64{} {
+			miss += bandMiss(m.Score, spec.ScoreRange[0], spec.ScoreRange[1])
+		}
+		if spec.BlockingDepthRange != [2]int{} {
+			miss += bandMiss(float64(m.MaxBlockingDepth), float64(spec.BlockingDepthRange[0]), float64(spec.BlockingDepthRange[1]))
+		}
+		dist := i - from
+		if from < 0 {
+			dist = i
+		}
+		cands = append(cands, candidate{tier: tier, miss: miss, dist: dist})
+	}
+	sort.SliceStable(cands, func(i, j int) bool {
+		a, b := cands[i], cands[j]
+		if a.miss != b.miss {
+			return a.miss < b.miss
+		}
+		if abs(a.dist) != abs(b.dist) {
+			return abs(a.dist) < abs(b.dist)
+		}
+		return (a.dist > 0) == harder
+	})
+	return cands[0].tier
+}
+
+// bandMiss returns how far v lies outside [lo, hi], 0 inside.
+func bandMiss(v, lo, hi float64) float64 {
+	return math.Max(0, math.Max(lo-v, v-hi))
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Report lists the graded levels in ID order.
+type Report struct {
+	Results []Result
+}
+
+// Counts returns how many levels were regraded, how many only had a stale
+// complexity, how many were skipped and how many failed to score.
+func (r Report) Counts() (regraded, stale, skipped, failed int) {
+	for _, res := range r.Results {
+		switch {
+		case res.Err != nil:
+			failed++
+		case res.Skipped():
+			skipped++
+		case res.Regraded():
+			regraded++
+		case res.Stale():
+			stale++
+		}
+	}
+	return regraded, stale, skipped, failed
+}
+
+// Scan grades every level_*.json in dir.
+func Scan(dir string, opts Options) (Report, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to read directory %s: %w", dir, err)
+	}
+
+	var r Report
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), "level_") || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		level, err := common.ReadLevel(path)
+		if err != nil {
+			return Report{}, err
+		}
+		common.Verbose("Grading %s", path)
+		res := Grade(level, opts)
+		res.File = path
+		r.Results = append(r.Results, res)
+	}
+	sort.SliceStable(r.Results, func(i, j int) bool { return r.Results[i].Level.ID < r.Results[j].Level.ID })
+	return r, nil
+}
+
+// WriteText prints the levels whose tier or complexity is stale, the levels
+// that could not be scored, and a summary.
+func WriteText(w io.Writer, r Report) {
+	for _, res := range r.Results {
+		lvl := res.Level
+		switch {
+		case res.Err != nil:
+			_, _ = fmt.Fprintf(w, "! level %d  %s: %v\n", lvl.ID, res.File, res.Err)
+		case res.Regraded():
+			_, _ = fmt.Fprintf(w, "~ level %d  %s -> %s  (score %.2f, depth %d)  %s\n",
+				lvl.ID, lvl.Difficulty, res.Measured, res.Metrics.Score, res.Metrics.MaxBlockingDepth, res.File)
+		case res.Stale():
+			_, _ = fmt.Fprintf(w, "~ level %d  %s, complexity %q -> %q  %s\n",
+				lvl.ID, lvl.Difficulty, lvl.Complexity, generator.ComplexityFor(res.Measured), res.File)
+		}
+	}
+	regraded, stale, skipped, failed := r.Counts()
+	_, _ = fmt.Fprintf(w, "\n%d levels scanned, %d regraded, %d with stale complexity, %d skipped, %d failed to score\n",
+		len(r.Results), regraded, stale, skipped, failed)
+}
@@ -0,0 +1,94 @@
+package regrade
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/metrics"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func TestMeasuredTier(t *testing.T) {
+	for _, tc := range []struct {
+		stored string
+		score  float64
+		depth  int
+		want   string
+	}{
+		{"Seedling", 10, 2, "Seedling"},          // inside both bands
+		{"Seedling", 15.8, 6, "Sprout"},          // Sprout and Nurturing fit; Sprout is nearer
+		{"Flourishing", 11.7, 3, "Nurturing"},    // Sprout and Nurturing fit; Nurturing is nearer
+		{"Seedling", 30, 12, "Transcendent"},     // nothing fits; Transcendent misses least
+		{"Transcendent", 4, 0, "Seedling"},       // nothing fits; Seedling misses least
+		{"Tutorial", 10, 2, "Seedling"},          // unknown tiers start from the easiest
+		{"Nurturing", 18.5, 5, "Nurturing"},      // Flourishing fits too, but Nurturing is kept
+		{"Flourishing", 9.5, 4, "Nurturing"},     // Sprout and Nurturing fit; Nurturing is nearer
+		{"Sprout", 12, 7, "Nurturing"},           // only Nurturing and Flourishing fit
+		{"Transcendent", 10.5, 5, "Flourishing"}, // below Transcendent's score band
+	} {
+		got := MeasuredTier(tc.stored, metrics.DifficultyMetrics{Score: tc.score, MaxBlockingDepth: tc.depth})
+		if got != tc.want {
+			t.Errorf("MeasuredTier(%s, score %.1f, depth %d) = %s, want %s", tc.stored, tc.score, tc.depth, got, tc.want)
+		}
+	}
+}
+
+func testLevel(t *testing.T, id int, difficulty string) *model.Level {
+	t.Helper()
+	vine := func(id string, path ...model.Point) model.Vine {
+		v, err := model.NewVine(id, path, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	// Two free vines: about as easy as a level gets
+	return &model.Level{
+		ID:         id,
+		Name:       "Level",
+		Difficulty: difficulty,
+		Complexity: "extreme",
+		GridSize:   []int{3, 2},
+		Vines: []model.Vine{
+			vine("vine_1", model.Point{X: 2, Y: 1}, model.Point{X: 1, Y: 1}, model.Point{X: 0, Y: 1}),
+			vine("vine_2", model.Point{X: 2, Y: 0}, model.Point{X: 1, Y: 0}, model.Point{X: 0, Y: 0}),
+		},
+		MaxMoves:    4,
+		MinMoves:    2,
+		Grace:       2,
+		ColorScheme: []string{"#ff0000"},
+	}
+}
+
+func TestScanAndApply(t *testing.T) {
+	dir := t.TempDir()
+	for _, lvl := range []*model.Level{testLevel(t, 1, "Transcendent"), testLevel(t, 2, "Tutorial")} {
+		if err := common.WriteLevel(common.GetLevelFilePath(lvl.ID, dir), lvl, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := Scan(dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	regraded, stale, skipped, failed := report.Counts()
+	if len(report.Results) != 2 || regraded != 1 || stale != 0 || skipped != 1 || failed != 0 {
+		t.Fatalf("counts = %d regraded, %d stale, %d skipped, %d failed of %d", regraded, stale, skipped, failed, len(report.Results))
+	}
+	res := report.Results[0]
+	if res.Level.ID != 1 || res.Measured != "Seedling" || res.File != filepath.Join(dir, "level_1.json") {
+		t.Fatalf("result = %+v", res)
+	}
+
+	if !res.Apply() || res.Level.Difficulty != "Seedling" || res.Level.Complexity != "low" {
+		t.Fatalf("after Apply: %s/%s", res.Level.Difficulty, res.Level.Complexity)
+	}
+	if res.Apply() {
+		t.Error("a second Apply changed the level again")
+	}
+	if report.Results[1].Apply() {
+		t.Error("Apply changed a skipped level")
+	}
+}
@@ -0,0 +1,526 @@
+// Package repair fixes broken levels with the smallest edits it can find, so a
+// hand-edited or partially corrupted design keeps every vine that is still
+// sound instead of being regenerated from its seed.
+package repair
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+// regrowBudget caps the path search when regrowing one vine.
+const regrowBudget = 200000
+
+var directions = []string{"up", "down", "left", "right"}
+
+// Fix is one edit Level made.
+type Fix struct {
+	VineID  string `json:"vine_id,omitempty"`
+	Message string `json:"message"`
+}
+
+func (f Fix) String() string {
+	if f.VineID != "" {
+		return fmt.Sprintf("vine %s: %s", f.VineID, f.Message)
+	}
+	return f.Message
+}
+
+type repairer struct {
+	ctx       context.Context
+	lvl       *model.Level
+	maxStates int
+	fixes     []Fix
+}
+
+// Level repairs lvl in place and returns the edits it made, in order:
+//
+//   - cells out of bounds, on portals or already taken by an earlier vine are dropped
+//   - paths whose cells are out of order are reordered into a connected chain
+//   - head_direction and tail_direction are recomputed from the path geometry
+//   - a vine blocking its own exit is reversed, or regrown over its own cells
+//   - vines that deadlock each other (see validator.ExplainUnsolvable) are broken up by
+//     reversing or regrowing one of them, and locks that can never open are removed
+//   - min_moves, max_moves, hints and solution_order are brought in line with the new solution
+//   - the mask is rebuilt to hide exactly the cells no vine or portal uses
+//
+// A vine is only regrown, and as a last resort removed, when no smaller edit fixes it;
+// regrown vines keep their ID, color and lock. maxStates bounds each solver run.
+func Level(ctx context.Context, lvl *model.Level, maxStates int) ([]Fix, error) {
+	if len(lvl.GridSize) != 2 || lvl.GridSize[0] <= 0 || lvl.GridSize[1] <= 0 {
+		return nil, fmt.Errorf("level %d has invalid grid size %v", lvl.ID, lvl.GridSize)
+	}
+	r := &repairer{ctx: ctx, lvl: lvl, maxStates: maxStates}
+
+	r.dropBadCells()
+	for i := 0; i < len(lvl.Vines); {
+		if r.reshape(i) {
+			i++
+		}
+	}
+	if err := r.breakDeadlocks(); err != nil {
+		return r.fixes, err
+	}
+	if err := r.fixMoves(); err != nil {
+		return r.fixes, err
+	}
+	r.rebuildMask()
+	return r.fixes, nil
+}
+
+func (r *repairer) fix(vineID, format string, args ...any) {
+	r.fixes = append(r.fixes, Fix{VineID: vineID, Message: fmt.Sprintf(format, args...)})
+}
+
+func (r *repairer) inGrid(p model.Point) bool {
+	return p.X >= 0 && p.X < r.lvl.GridSize[0] && p.Y >= 0 && p.Y < r.lvl.GridSize[1]
+}
+
+// dropBadCells removes cells that no vine may hold and duplicates of earlier cells.
+func (r *repairer) dropBadCells() {
+	taken := make(map[model.Point]bool)
+	for i := range r.lvl.Vines {
+		v := &r.lvl.Vines[i]
+		kept := v.OrderedPath[:0:0]
+		for _, p := range v.OrderedPath {
+			if !r.inGrid(p) || r.lvl.IsPortal(p.X, p.Y) || taken[p] {
+				continue
+			}
+			taken[p] = true
+			kept = append(kept, p)
+		}
+		if dropped := len(v.OrderedPath) - len(kept); dropped > 0 {
+			r.fix(v.ID, "dropped %d cells that were out of bounds, on a portal or already taken", dropped)
+			v.OrderedPath = kept
+		}
+	}
+}
+
+// reshape makes vine i a connected chain with matching head directions that does not
+// block itself. It reports false when it removed the vine.
+func (r *repairer) reshape(i int) bool {
+	v := &r.lvl.Vines[i]
+	if v.LockedUntil < 0 {
+		r.fix(v.ID, "locked_until %d -> 0", v.LockedUntil)
+		v.LockedUntil = 0
+	}
+	if !isChain(v.OrderedPath) {
+		if ordered, ok := chainOrder(v.OrderedPath); ok {
+			v.OrderedPath = ordered
+			r.fix(v.ID, "reordered path into a connected chain")
+		} else {
+			return r.regrow(i, "its cells do not form a chain")
+		}
+	}
+	if len(v.OrderedPath) < 2 {
+		return r.regrow(i, "it has fewer than 2 cells")
+	}
+
+	derived, _ := model.NewVine(v.ID, v.OrderedPath, "")
+	if v.HeadDirection != derived.HeadDirection {
+		r.fix(v.ID, "head_direction %q -> %q to match the path", v.HeadDirection, derived.HeadDirection)
+		v.HeadDirection = derived.HeadDirection
+	}
+	if v.TailDirection != "" {
+		if tail, _ := derived.WithTailHead(""); tail.TailDirection != v.TailDirection {
+			r.fix(v.ID, "tail_direction %q -> %q to match the path", v.TailDirection, tail.TailDirection)
+			v.TailDirection = tail.TailDirection
+		}
+	}
+
+	if !r.selfBlocked(*v) {
+		return true
+	}
+	if !v.IsMultiHead() {
+		if rev := reversed(*v); !r.selfBlocked(rev) {
+			*v = rev
+			r.fix(v.ID, "reversed so it no longer blocks its own exit")
+			return true
+		}
+	}
+	return r.regrow(i, "it blocks its own exit")
+}
+
+// selfBlocked reports whether any head of v has one of v's own cells on its exit ray.
+func (r *repairer) selfBlocked(v model.Vine) bool {
+	body := make(map[model.Point]bool, len(v.OrderedPath))
+	for _, p := range v.OrderedPath {
+		body[p] = true
+	}
+	for _, h := range v.Heads() {
+		if !r.rayClear(h.OrderedPath[0], h.HeadDirection, body) {
+			return true
+		}
+	}
+	return false
+}
+
+// rayClear reports whether the exit ray from head in dir leaves the grid without
+// touching a blocked cell.
+func (r *repairer) rayClear(head model.Point, dir string, blocked map[model.Point]bool) bool {
+	_, ok := r.lvl.ExitRay(head, dir, func(p model.Point) bool { return !blocked[p] })
+	return ok
+}
+
+// occupiedExcept returns the cells of every vine other than vine i.
+func (r *repairer) occupiedExcept(i int) map[model.Point]bool {
+	occ := make(map[model.Point]bool)
+	for j, v := range r.lvl.Vines {
+		if j == i {
+			continue
+		}
+		for _, p := range v.OrderedPath {
+			occ[p] = true
+		}
+	}
+	return occ
+}
+
+// regrow replaces vine i with the longest chain over its own cells whose exit is clear
+// of every vine, reaching into empty cells only when its own are not enough. It removes
+// the vine when no chain of 2 cells fits and reports whether the vine was kept.
+func (r *repairer) regrow(i int, reason string) bool {
+	v := &r.lvl.Vines[i]
+	others := r.occupiedExcept(i)
+	path, dir := r.longestChain(v.OrderedPath, others, max(len(v.OrderedPath), 2))
+	if len(path) < 2 {
+		var empty []model.Point
+		for y := 0; y < r.lvl.GridSize[1]; y++ {
+			for x := 0; x < r.lvl.GridSize[0]; x++ {
+				if p := (model.Point{X: x, Y: y}); !others[p] && !r.lvl.IsPortal(x, y) {
+					empty = append(empty, p)
+				}
+			}
+		}
+		path, dir = r.longestChain(append(append([]model.Point{}, v.OrderedPath...), empty...), others, max(len(v.OrderedPath), 2))
+	}
+	if len(path) < 2 {
+		r.fix(v.ID, "removed: %s and no chain with a clear exit fits", reason)
+		r.lvl.Vines = append(r.lvl.Vines[:i], r.lvl.Vines[i+1:]...)
+		return false
+	}
+
+	own := 0
+	was := make(map[model.Point]bool, len(v.OrderedPath))
+	for _, p := range v.OrderedPath {
+		was[p] = true
+	}
+	for _, p := range path {
+		if was[p] {
+			own++
+		}
+	}
+	r.fix(v.ID, "regrown because %s: %d cells facing %s, %d of them its own", reason, len(path), dir, own)
+	v.OrderedPath, v.HeadDirection, v.TailDirection = path, dir, ""
+	return true
+}
+
+// longestChain returns the longest chain of at most maxLen pool cells, preferring heads
+// earlier in pool, whose exit ray is clear of blocked and of the chain itself.
+func (r *repairer) longestChain(pool []model.Point, blocked map[model.Point]bool, maxLen int) ([]model.Point, string) {
+	inPool := make(map[model.Point]bool, len(pool))
+	for _, p := range pool {
+		inPool[p] = true
+	}
+
+	var best []model.Point
+	bestDir := ""
+	budget := regrowBudget
+	for _, head := range pool {
+		for _, dir := range directions {
+			ray := make(map[model.Point]bool)
+			if !r.rayClear(head, dir, blocked) {
+				continue
+			}
+			r.lvl.ExitRay(head, dir, func(p model.Point) bool {
+				ray[p] = true
+				return true
+			})
+			neck := stepBack(head, dir)
+			if !inPool[neck] || ray[neck] {
+				continue
+			}
+
+			used := map[model.Point]bool{head: true, neck: true}
+			path := []model.Point{head, neck}
+			var grow func()
+			grow = func() {
+				if len(path) > len(best) {
+					best, bestDir = append([]model.Point{}, path...), dir
+				}
+				if len(path) >= maxLen || budget <= 0 {
+					return
+				}
+				budget--
+				tip := path[len(path)-1]
+				for _, next := range neighbors(tip) {
+					if !inPool[next] || used[next] || ray[next] {
+						continue
+					}
+					used[next] = true
+					path = append(path, next)
+					grow()
+					path = path[:len(path)-1]
+					delete(used, next)
+				}
+			}
+			grow()
+			if len(best) >= maxLen {
+				return best, bestDir
+			}
+		}
+	}
+	return best, bestDir
+}
+
+// breakDeadlocks edits one vine of each deadlock the solver proves until the level can be
+// cleared, the solver gives up, or every vine has been tried.
+func (r *repairer) breakDeadlocks() error {
+	for attempt := 0; attempt <= len(r.lvl.Vines); attempt++ {
+		d, err := validator.ExplainUnsolvable(r.ctx, *r.lvl, r.maxStates)
+		if err != nil || d == nil {
+			return err
+		}
+
+		if d.Locked {
+			for i := range r.lvl.Vines {
+				if v := &r.lvl.Vines[i]; contains(d.Vines, v.ID) && v.LockedUntil > 0 {
+					r.fix(v.ID, "removed locked_until %d: the lock could never open", v.LockedUntil)
+					v.LockedUntil = 0
+				}
+			}
+			continue
+		}
+
+		// Prefer turning a vine around; otherwise regrow the shortest one
+		candidate := -1
+		for i, v := range r.lvl.Vines {
+			if !contains(d.Vines, v.ID) {
+				continue
+			}
+			if !v.IsMultiHead() {
+				rev := reversed(v)
+				others := r.occupiedExcept(i)
+				if !r.selfBlocked(rev) && r.rayClear(rev.OrderedPath[0], rev.HeadDirection, others) {
+					r.lvl.Vines[i] = rev
+					r.fix(v.ID, "reversed to break circular blocking (%s)", strings.Join(d.Cycle, " -> "))
+					candidate = -2
+					break
+				}
+			}
+			if candidate == -1 || len(v.OrderedPath) < len(r.lvl.Vines[candidate].OrderedPath) {
+				candidate = i
+			}
+		}
+		if candidate >= 0 {
+			r.regrow(candidate, fmt.Sprintf("it deadlocks with %s", strings.Join(d.Vines, ", ")))
+		}
+	}
+	return fmt.Errorf("level %d is still unsolvable after editing every vine", r.lvl.ID)
+}
+
+// fixMoves brings min_moves, max_moves, hints and solution_order in line with the repaired vines.
+func (r *repairer) fixMoves() error {
+	n := len(r.lvl.Vines)
+	if r.lvl.MinMoves != 0 && r.lvl.MinMoves != n {
+		r.fix("", "min_moves %d -> %d", r.lvl.MinMoves, n)
+		r.lvl.MinMoves = n
+	}
+	if r.lvl.MaxMoves > 0 && r.lvl.MaxMoves < n {
+		r.fix("", "max_moves %d -> %d", r.lvl.MaxMoves, n)
+		r.lvl.MaxMoves = n
+	}
+
+	staleHints := len(r.lvl.Hints) > 0 && !hintsValid(*r.lvl)
+	staleOrder := len(r.lvl.SolutionOrder) > 0 && validator.VerifySolutionOrder(*r.lvl, r.lvl.SolutionOrder) != nil
+	if !staleHints && !staleOrder {
+		return nil
+	}
+	// A solution_order that still replays is reused rather than searched for
+	ok, solution, _, err := validator.SolveContext(r.ctx, *r.lvl, r.maxStates)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if staleHints {
+			r.fix("", "dropped %d hints that no longer replay", len(r.lvl.Hints))
+			r.lvl.Hints = nil
+		}
+		if staleOrder {
+			r.fix("", "dropped a solution_order that no longer replays")
+			r.lvl.SolutionOrder = nil
+		}
+		return nil
+	}
+	if staleHints {
+		count := min(len(r.lvl.Hints), len(solution))
+		r.fix("", "recomputed %d hints from the solution", count)
+		r.lvl.Hints = append([]string(nil), solution[:count]...)
+	}
+	if staleOrder {
+		r.fix("", "recomputed solution_order from the solution")
+		r.lvl.SolutionOrder = solution
+	}
+	return nil
+}
+
+// hintsValid reports whether structural validation accepts lvl's hints.
+func hintsValid(lvl model.Level) bool {
+	for _, err := range validator.ValidateStructural(lvl) {
+		if se, ok := err.(validator.StructuralError); ok && strings.Contains(se.Message, "hint") {
+			return false
+		}
+	}
+	return true
+}
+
+// rebuildMask recomputes the mask, in its current mode, to hide exactly the cells that
+// no vine or portal uses. Untouched levels keep their mask unless it hides a used cell.
+// A "show-all" mask hides nothing and lists decorative gaps, so it is left alone.
+func (r *repairer) rebuildMask() {
+	if r.lvl.Mask != nil && r.lvl.Mask.Mode == model.MaskModeShowAll {
+		return
+	}
+	w, h := r.lvl.GridSize[0], r.lvl.GridSize[1]
+	occupied := r.occupiedExcept(-1)
+	used := func(x, y int) bool { return occupied[model.Point{X: x, Y: y}] || r.lvl.IsPortal(x, y) }
+
+	stale := false
+	for y := 0; y < h && !stale; y++ {
+		for x := 0; x < w; x++ {
+			if r.lvl.Mask.IsMasked(x, y) && used(x, y) {
+				stale = true
+				break
+			}
+		}
+	}
+	if !stale && len(r.fixes) == 0 {
+		return
+	}
+
+	mode := ""
+	if r.lvl.Mask != nil {
+		mode = r.lvl.Mask.Mode
+	}
+	mask := model.NewOccupancyMask(mode, w, h, used)
+	if mask.HiddenCells(w, h) == r.lvl.Mask.HiddenCells(w, h) && !stale {
+		return
+	}
+	r.lvl.Mask = mask
+	r.fix("", "rebuilt the mask to hide the %d unused cells", mask.HiddenCells(w, h))
+}
+
+// isChain reports whether consecutive cells of path are adjacent.
+func isChain(path []model.Point) bool {
+	for i := 1; i < len(path); i++ {
+		if abs(path[i].X-path[i-1].X)+abs(path[i].Y-path[i-1].Y) != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// chainOrder orders cells into a chain of adjacent cells, starting from the end nearest
+// the current head (cells[0]), when the cells form a single unbranched path.
+func chainOrder(cells []model.Point) ([]model.Point, bool) {
+	if len(cells) < 2 {
+		return cells, true
+	}
+	set := make(map[model.Point]bool, len(cells))
+	for _, p := range cells {
+		set[p] = true
+	}
+	var ends []model.Point
+	for _, p := range cells {
+		degree := 0
+		for _, n := range neighbors(p) {
+			if set[n] {
+				degree++
+			}
+		}
+		switch degree {
+		case 0:
+			return nil, false
+		case 1:
+			ends = append(ends, p)
+		case 2:
+		default:
+			return nil, false
+		}
+	}
+	if len(ends) != 2 {
+		return nil, false
+	}
+	sort.SliceStable(ends, func(a, b int) bool { return distance(ends[a], cells[0]) < distance(ends[b], cells[0]) })
+
+	ordered := []model.Point{ends[0]}
+	seen := map[model.Point]bool{ends[0]: true}
+	for len(ordered) < len(cells) {
+		tip := ordered[len(ordered)-1]
+		next, found := model.Point{}, false
+		for _, n := range neighbors(tip) {
+			if set[n] && !seen[n] {
+				next, found = n, true
+				break
+			}
+		}
+		if !found {
+			// Disconnected: the cells hold more than one chain
+			return nil, false
+		}
+		seen[next] = true
+		ordered = append(ordered, next)
+	}
+	return ordered, true
+}
+
+// reversed returns single-head vine v with its tail as the head.
+func reversed(v model.Vine) model.Vine {
+	rev := v.Reversed()
+	derived, _ := model.NewVine(v.ID, rev.OrderedPath, "")
+	rev.HeadDirection, rev.TailDirection = derived.HeadDirection, ""
+	return rev
+}
+
+// stepBack returns the neck position for a head at p facing dir.
+func stepBack(p model.Point, dir string) model.Point {
+	switch dir {
+	case "up":
+		return model.Point{X: p.X, Y: p.Y - 1}
+	case "down":
+		return model.Point{X: p.X, Y: p.Y + 1}
+	case "left":
+		return model.Point{X: p.X + 1, Y: p.Y}
+	default:
+		return model.Point{X: p.X - 1, Y: p.Y}
+	}
+}
+
+func neighbors(p model.Point) []model.Point {
+	return []model.Point{{X: p.X, Y: p.Y + 1}, {X: p.X, Y: p.Y - 1}, {X: p.X - 1, Y: p.Y}, {X: p.X + 1, Y: p.Y}}
+}
+
+func distance(a, b model.Point) int {
+	return abs(a.X-b.X) + abs(a.Y-b.Y)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func contains(ids []string, id string) bool {
+	for _, s := range ids {
+		if s == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package repair
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+func repaired(t *testing.T, lvl *model.Level) []Fix {
+	t.Helper()
+	fixes, err := Level(context.Background(), lvl, 10000)
+	if err != nil {
+		t.Fatalf("Level: %v", err)
+	}
+	ok, _, _, err := validator.Solve(*lvl, 10000)
+	if err != nil || !ok {
+		t.Fatalf("repaired level is not solvable (%v): %+v\nfixes: %v", err, lvl.Vines, fixes)
+	}
+	return fixes
+}
+
+func fixesFor(fixes []Fix, id string) string {
+	var msgs []string
+	for _, f := range fixes {
+		if f.VineID == id {
+			msgs = append(msgs, f.Message)
+		}
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func TestLevelFixesGeometryInPlace(t *testing.T) {
+	lvl := model.Level{
+		ID:       1,
+		GridSize: []int{4, 4},
+		Vines: []model.Vine{
+			// Shuffled, with a cell off the grid and a head_direction that matches nothing
+			{ID: "s", HeadDirection: "right", OrderedPath: []model.Point{{X: 0, Y: 1}, {X: 0, Y: 3}, {X: 5, Y: 5}, {X: 0, Y: 2}}},
+			{ID: "t", HeadDirection: "right", OrderedPath: []model.Point{{X: 3, Y: 0}, {X: 2, Y: 0}}},
+		},
+		// Hides one of t's cells
+		Mask: &model.Mask{Mode: model.MaskModeHide, Points: []model.Point{{X: 3, Y: 0}}},
+	}
+	fixes := repaired(t, &lvl)
+
+	s := lvl.Vines[0]
+	if want := []model.Point{{X: 0, Y: 1}, {X: 0, Y: 2}, {X: 0, Y: 3}}; !reflect.DeepEqual(s.OrderedPath, want) || s.HeadDirection != "down" {
+		t.Errorf("s = %s %v, want down %v", s.HeadDirection, s.OrderedPath, want)
+	}
+	if got := fixesFor(fixes, "s"); !strings.Contains(got, "dropped 1 cells") || !strings.Contains(got, "reordered") ||
+		!strings.Contains(got, `head_direction "right" -> "down"`) {
+		t.Errorf("fixes for s = %q", got)
+	}
+	if got := fixesFor(fixes, "t"); got != "" {
+		t.Errorf("t is sound but was edited: %q", got)
+	}
+	if want := (model.Vine{ID: "t", HeadDirection: "right", OrderedPath: []model.Point{{X: 3, Y: 0}, {X: 2, Y: 0}}}); !reflect.DeepEqual(lvl.Vines[1], want) {
+		t.Errorf("t = %+v, want it unchanged", lvl.Vines[1])
+	}
+	if lvl.Mask.IsMasked(3, 0) || !lvl.Mask.IsMasked(1, 1) || lvl.Mask.HiddenCells(4, 4) != 11 {
+		t.Errorf("mask not rebuilt over the 11 empty cells: %+v", lvl.Mask)
+	}
+}
+
+func TestLevelReversesSelfBlockingVine(t *testing.T) {
+	// u's head at (1,2) faces down onto its own tail at (1,0)
+	path := []model.Point{{X: 1, Y: 2}, {X: 1, Y: 3}, {X: 2, Y: 3}, {X: 2, Y: 2}, {X: 2, Y: 1}, {X: 2, Y: 0}, {X: 1, Y: 0}}
+	lvl := model.Level{
+		ID:       2,
+		GridSize: []int{4, 4},
+		Vines:    []model.Vine{{ID: "u", HeadDirection: "down", OrderedPath: path}},
+	}
+	fixes := repaired(t, &lvl)
+
+	u := lvl.Vines[0]
+	if u.HeadDirection != "left" || u.OrderedPath[0] != (model.Point{X: 1, Y: 0}) || len(u.OrderedPath) != len(path) {
+		t.Errorf("u = %s %v, want it reversed to face left", u.HeadDirection, u.OrderedPath)
+	}
+	if got := fixesFor(fixes, "u"); !strings.Contains(got, "reversed") {
+		t.Errorf("fixes for u = %q", got)
+	}
+}
+
+func TestLevelBreaksDeadlockWithOneEdit(t *testing.T) {
+	// a and b face each other; c waits on a, d is free
+	lvl := model.Level{
+		ID:       3,
+		GridSize: []int{4, 4},
+		MinMoves: 2,
+		MaxMoves: 3,
+		// Stale: a and b cannot clear while they face each other
+		SolutionOrder: []string{"d", "a", "b", "c"},
+		Vines: []model.Vine{
+			{ID: "c", HeadDirection: "up", OrderedPath: []model.Point{{X: 1, Y: 1}, {X: 1, Y: 0}}},
+			{ID: "a", HeadDirection: "right", OrderedPath: []model.Point{{X: 1, Y: 2}, {X: 0, Y: 2}}},
+			{ID: "d", HeadDirection: "down", OrderedPath: []model.Point{{X: 3, Y: 0}, {X: 3, Y: 1}}},
+			{ID: "b", HeadDirection: "left", OrderedPath: []model.Point{{X: 2, Y: 2}, {X: 3, Y: 2}}},
+		},
+	}
+	fixes := repaired(t, &lvl)
+
+	if got := fixesFor(fixes, "a"); !strings.Contains(got, "reversed to break circular blocking (a -> b -> a)") {
+		t.Errorf("fixes for a = %q, all fixes %v", got, fixes)
+	}
+	for _, id := range []string{"b", "c", "d"} {
+		if got := fixesFor(fixes, id); got != "" {
+			t.Errorf("%s was edited: %q", id, got)
+		}
+	}
+	if lvl.MinMoves != 4 || lvl.MaxMoves != 4 {
+		t.Errorf("moves = %d..%d, want 4..4", lvl.MinMoves, lvl.MaxMoves)
+	}
+	if err := validator.VerifySolutionOrder(lvl, lvl.SolutionOrder); err != nil {
+		t.Errorf("solution_order %v was not recomputed: %v", lvl.SolutionOrder, err)
+	}
+}
+
+func TestChainOrder(t *testing.T) {
+	got, ok := chainOrder([]model.Point{{X: 1, Y: 1}, {X: 0, Y: 0}, {X: 1, Y: 0}})
+	if want := []model.Point{{X: 1, Y: 1}, {X: 1, Y: 0}, {X: 0, Y: 0}}; !ok || !reflect.DeepEqual(got, want) {
+		t.Errorf("chainOrder = %v, %v, want %v", got, ok, want)
+	}
+	// Branching or split cells are not a single chain
+	if _, ok := chainOrder([]model.Point{{X: 1, Y: 1}, {X: 0, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 0}}); ok {
+		t.Error("a T shape was ordered")
+	}
+	if _, ok := chainOrder([]model.Point{{X: 0, Y: 0}, {X: 2, Y: 0}}); ok {
+		t.Error("split cells were ordered")
+	}
+}
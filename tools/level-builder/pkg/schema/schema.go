@@ -0,0 +1,269 @@
+// Package schema derives JSON Schemas for level, lesson and module files from
+// the Go model types, and validates raw JSON against them. The validator runs
+// it before decoding, since encoding/json silently drops fields the model does
+// not know; a misspelled or stale field shows up as an unknown-field error
+// instead of a default value.
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// Draft is the JSON Schema dialect of generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the subset of JSON Schema the model types need.
+type Schema struct {
+	Schema     string             `json:"$schema,omitempty"`
+	ID         string             `json:"$id,omitempty"`
+	Title      string             `json:"title,omitempty"`
+	Ref        string             `json:"$ref,omitempty"`
+	Type       string             `json:"type,omitempty"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	// AdditionalProperties is false for structs and the value schema for maps
+	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+var (
+	levelSchema = sync.OnceValue(func() *Schema {
+		return For(model.Level{}, "level.schema.json", "Parable Bloom level")
+	})
+	lessonSchema = sync.OnceValue(func() *Schema {
+		return For(model.Lesson{}, "lesson.schema.json", "Parable Bloom tutorial lesson")
+	})
+	modulesSchema = sync.OnceValue(func() *Schema {
+		return For(model.ModuleRegistry{}, "modules.schema.json", "Parable Bloom module registry")
+	})
+)
+
+// Level returns the schema of assets/levels/level_N.json files.
+func Level() *Schema { return levelSchema() }
+
+// Lesson returns the schema of assets/lessons/lesson_N.json files.
+func Lesson() *Schema { return lessonSchema() }
+
+// Modules returns the schema of assets/data/modules.json.
+func Modules() *Schema { return modulesSchema() }
+
+// Files maps the schema names used on the command line to their schemas.
+var Files = map[string]func() *Schema{
+	"level":   Level,
+	"lesson":  Lesson,
+	"modules": Modules,
+}
+
+// Names returns the keys of Files in sorted order.
+func Names() []string {
+	names := make([]string, 0, len(Files))
+	for name := range Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// For derives a schema from v's type, which must be a struct. Properties come
+// from json tags: fields tagged omitempty are optional, "-" fields are left
+// out and every object rejects unknown fields. Nested struct types become
+// $defs entries named after the Go type.
+func For(v any, id, title string) *Schema {
+	g := generator{defs: make(map[string]*Schema)}
+	root := g.object(reflect.TypeOf(v))
+	root.Schema, root.ID, root.Title = Draft, id, title
+	if len(g.defs) > 0 {
+		root.Defs = g.defs
+	}
+	return root
+}
+
+type generator struct {
+	defs map[string]*Schema
+}
+
+func (g *generator) schemaFor(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		name := t.Name()
+		if _, ok := g.defs[name]; !ok {
+			g.defs[name] = nil // reserve the name so recursive types terminate
+			g.defs[name] = g.object(t)
+		}
+		return &Schema{Ref: "#/$defs/" + name}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	default:
+		return &Schema{}
+	}
+}
+
+func (g *generator) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema), AdditionalProperties: false}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s.Properties[name] = g.schemaFor(f.Type)
+		if !strings.Contains(","+opts+",", ",omitempty,") {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return s
+}
+
+// Error is a schema violation at a JSON path such as $.vines[2].head_direction.
+type Error struct {
+	Path    string
+	Message string
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Validate checks data against the schema and returns every violation:
+// malformed JSON, wrong types, missing required fields and unknown fields.
+func (s *Schema) Validate(data []byte) []error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return []error{fmt.Errorf("invalid JSON: %w", err)}
+	}
+	c := checker{root: s}
+	c.check(s, doc, "$")
+	return c.errs
+}
+
+type checker struct {
+	root *Schema
+	errs []error
+}
+
+func (c *checker) fail(path, format string, args ...any) {
+	c.errs = append(c.errs, Error{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) check(s *Schema, value any, path string) {
+	if s.Ref != "" {
+		def, ok := c.root.Defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+		if !ok {
+			c.fail(path, "unresolved $ref %s", s.Ref)
+			return
+		}
+		s = def
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			c.fail(path, "expected object, got %s", kind(value))
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				c.fail(path, "missing required field %q", name)
+			}
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if prop, ok := s.Properties[k]; ok {
+				c.check(prop, obj[k], path+"."+k)
+				continue
+			}
+			switch extra := s.AdditionalProperties.(type) {
+			case bool:
+				if !extra {
+					c.fail(path+"."+k, "unknown field")
+				}
+			case *Schema:
+				c.check(extra, obj[k], path+"."+k)
+			}
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			c.fail(path, "expected array, got %s", kind(value))
+			return
+		}
+		if s.Items != nil {
+			for i, item := range items {
+				c.check(s.Items, item, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	case "integer":
+		if n, ok := value.(json.Number); !ok {
+			c.fail(path, "expected integer, got %s", kind(value))
+		} else if _, err := n.Int64(); err != nil {
+			c.fail(path, "expected integer, got %s", n)
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			c.fail(path, "expected number, got %s", kind(value))
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			c.fail(path, "expected string, got %s", kind(value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			c.fail(path, "expected boolean, got %s", kind(value))
+		}
+	}
+}
+
+func kind(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
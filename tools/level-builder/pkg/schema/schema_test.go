@@ -0,0 +1,96 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func TestLevelSchema(t *testing.T) {
+	s := Level()
+	want := []string{"id", "grid_size", "vines", "max_moves", "grace", "color_scheme"}
+	if !reflect.DeepEqual(s.Required, want) {
+		t.Errorf("required = %v, want %v", s.Required, want)
+	}
+	if _, ok := s.Properties["occupancy_percent"]; ok {
+		t.Error(`json:"-" field exported`)
+	}
+	if s.Properties["vines"].Items.Ref != "#/$defs/Vine" || s.Defs["Point"] == nil {
+		t.Errorf("expected vines to reference the Vine and Point defs, got %+v", s.Properties["vines"].Items)
+	}
+	if s.AdditionalProperties != false {
+		t.Errorf("additionalProperties = %v, want false", s.AdditionalProperties)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	level := model.Level{
+		ID:       3,
+		GridSize: []int{2, 2},
+		Mask:     &model.Mask{Mode: "hide", Points: []model.Point{{X: 1, Y: 1}}},
+		Vines: []model.Vine{
+			{ID: "v1", HeadDirection: "right", OrderedPath: []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}},
+		},
+		MaxMoves:    5,
+		Grace:       3,
+		ColorScheme: []string{"#7CB342"},
+	}
+	valid, err := json.Marshal(level)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := Level().Validate(valid); len(errs) != 0 {
+		t.Fatalf("marshalled level failed validation: %v", errs)
+	}
+
+	tests := []struct {
+		name string
+		edit func(doc map[string]any)
+		want []string
+	}{
+		{"unknown top-level field", func(doc map[string]any) { doc["max_move"] = 5 },
+			[]string{"$.max_move: unknown field"}},
+		{"unknown nested field", func(doc map[string]any) {
+			doc["vines"].([]any)[0].(map[string]any)["colour"] = "red"
+		}, []string{"$.vines[0].colour: unknown field"}},
+		{"missing required", func(doc map[string]any) { delete(doc, "grace") },
+			[]string{`$: missing required field "grace"`}},
+		{"wrong types", func(doc map[string]any) {
+			doc["id"] = "3"
+			doc["mask"].(map[string]any)["points"].([]any)[0].(map[string]any)["x"] = 1.5
+		}, []string{"$.id: expected integer, got string", "$.mask.points[0].x: expected integer, got 1.5"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc map[string]any
+			if err := json.Unmarshal(valid, &doc); err != nil {
+				t.Fatal(err)
+			}
+			tt.edit(doc)
+			data, _ := json.Marshal(doc)
+
+			var got []string
+			for _, err := range Level().Validate(data) {
+				got = append(got, err.Error())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("errors = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if errs := Level().Validate([]byte("{")); len(errs) != 1 || !strings.Contains(errs[0].Error(), "invalid JSON") {
+		t.Errorf("malformed JSON: got %v", errs)
+	}
+}
+
+func TestModulesSchemaMaps(t *testing.T) {
+	data := []byte(`{"version": "1", "tutorials": [], "modules": [], "level_mappings": {"lesson_1": 7}}`)
+	errs := Modules().Validate(data)
+	if len(errs) != 1 || errs[0].Error() != "$.level_mappings.lesson_1: expected string, got number" {
+		t.Errorf("errors = %v", errs)
+	}
+}
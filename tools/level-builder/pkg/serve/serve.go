@@ -0,0 +1,314 @@
+// Package serve is the HTTP preview server behind the serve command. It
+// serves a single page that browses the level library, and JSON endpoints the
+// page is built on:
+//
+//	GET  /api/levels                    summaries of every level file
+//	GET  /api/levels/{id}               the level JSON
+//	GET  /api/levels/{id}/svg           a static SVG render
+//	GET  /api/levels/{id}/solution.svg  the solution animated as SVG
+//	GET  /api/levels/{id}/validate      the validate --check-solvable result
+//	POST /api/validate                  validate a level JSON body
+//	POST /api/generate                  generate a level without saving it
+//
+// Level files are read on every request, so edits show up on reload.
+package serve
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/levelgen"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+//go:embed index.html
+var indexHTML []byte
+
+// maxBodyBytes bounds request bodies; a large level is well under 1MB.
+const maxBodyBytes = 4 << 20
+
+// Options configures the handler.
+type Options struct {
+	LevelsDir string
+	// MaxStates is the solver budget for validation and solution animations
+	MaxStates int
+	// CellSize is the pixels per grid cell of SVG renders
+	CellSize int
+	// GenerateTimeout bounds each generate request (0 = no limit beyond the client's)
+	GenerateTimeout time.Duration
+}
+
+// Summary describes one level in the library listing.
+type Summary struct {
+	ID         int    `json:"id"`
+	File       string `json:"file"`
+	Name       string `json:"name,omitempty"`
+	Difficulty string `json:"difficulty,omitempty"`
+	GridSize   []int  `json:"grid_size"`
+	Vines      int    `json:"vines"`
+	MinMoves   int    `json:"min_moves,omitempty"`
+	MaxMoves   int    `json:"max_moves"`
+	Error      string `json:"error,omitempty"` // set when the file does not load
+}
+
+// GenerateRequest is the body of POST /api/generate.
+type GenerateRequest struct {
+	ID         int    `json:"id"`
+	Difficulty string `json:"difficulty"`
+	Seed       int64  `json:"seed,omitempty"`
+	Strategy   string `json:"strategy,omitempty"`
+}
+
+// GenerateResponse is a generated level with its renders inlined, since it
+// has no file for the level endpoints to serve.
+type GenerateResponse struct {
+	Level       model.Level `json:"level"`
+	Strategy    string      `json:"strategy"`
+	Attempts    int         `json:"attempts"`
+	DurationMs  int64       `json:"duration_ms"`
+	SVG         string      `json:"svg"`
+	SolutionSVG string      `json:"solution_svg,omitempty"`
+}
+
+type server struct {
+	opts Options
+}
+
+// NewHandler returns the preview server's routes.
+func NewHandler(opts Options) http.Handler {
+	if opts.MaxStates <= 0 {
+		opts.MaxStates = 1000000
+	}
+	if opts.CellSize <= 0 {
+		opts.CellSize = common.DefaultImageCellSize
+	}
+	s := &server{opts: opts}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.index)
+	mux.HandleFunc("GET /api/levels", s.listLevels)
+	mux.HandleFunc("GET /api/levels/{id}", s.getLevel)
+	mux.HandleFunc("GET /api/levels/{id}/svg", s.levelSVG)
+	mux.HandleFunc("GET /api/levels/{id}/solution.svg", s.solutionSVG)
+	mux.HandleFunc("GET /api/levels/{id}/validate", s.validateLevel)
+	mux.HandleFunc("POST /api/validate", s.validateBody)
+	mux.HandleFunc("POST /api/generate", s.generate)
+	return mux
+}
+
+func (s *server) index(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(indexHTML)
+}
+
+func (s *server) listLevels(w http.ResponseWriter, r *http.Request) {
+	entries, err := os.ReadDir(s.opts.LevelsDir)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to read levels: %w", err))
+		return
+	}
+	summaries := []Summary{}
+	for _, e := range entries {
+		var id int
+		if _, err := fmt.Sscanf(e.Name(), "level_%d.json", &id); err != nil || e.Name() != fmt.Sprintf("level_%d.json", id) {
+			continue
+		}
+		sum := Summary{ID: id, File: e.Name()}
+		if lvl, err := common.ReadLevel(filepath.Join(s.opts.LevelsDir, e.Name())); err != nil {
+			sum.Error = err.Error()
+		} else {
+			sum.Name, sum.Difficulty, sum.GridSize = lvl.Name, lvl.Difficulty, lvl.GridSize
+			sum.Vines, sum.MinMoves, sum.MaxMoves = len(lvl.Vines), lvl.MinMoves, lvl.MaxMoves
+		}
+		summaries = append(summaries, sum)
+	}
+	slices.SortFunc(summaries, func(a, b Summary) int { return a.ID - b.ID })
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+// level loads the level named by the {id} path value, writing the error
+// response itself when it cannot.
+func (s *server) level(w http.ResponseWriter, r *http.Request) (*model.Level, string, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid level id %q", r.PathValue("id")))
+		return nil, "", false
+	}
+	path := common.GetLevelFilePath(id, s.opts.LevelsDir)
+	if !common.FileExists(path) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("level %d not found", id))
+		return nil, "", false
+	}
+	lvl, err := common.ReadLevel(path)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return nil, "", false
+	}
+	return lvl, path, true
+}
+
+func (s *server) getLevel(w http.ResponseWriter, r *http.Request) {
+	if lvl, _, ok := s.level(w, r); ok {
+		writeJSON(w, http.StatusOK, lvl)
+	}
+}
+
+func (s *server) levelSVG(w http.ResponseWriter, r *http.Request) {
+	lvl, _, ok := s.level(w, r)
+	if !ok {
+		return
+	}
+	var buf bytes.Buffer
+	if err := common.RenderLevelImage(&buf, lvl, common.ImageSVG, s.opts.CellSize); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeSVG(w, buf.Bytes())
+}
+
+func (s *server) solutionSVG(w http.ResponseWriter, r *http.Request) {
+	lvl, _, ok := s.level(w, r)
+	if !ok {
+		return
+	}
+	svg, err := s.animate(r.Context(), lvl)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeSVG(w, svg)
+}
+
+// animate solves lvl and renders the clearing sequence.
+func (s *server) animate(ctx context.Context, lvl *model.Level) ([]byte, error) {
+	ok, solution, stats, err := validator.SolveContext(ctx, *lvl, s.opts.MaxStates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to solve level %d: %w", lvl.ID, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("level %d is not solvable (solver %s, %d states)", lvl.ID, stats.Solver, stats.StatesExplored)
+	}
+	var buf bytes.Buffer
+	opts := common.AnimationOptions{CellSize: s.opts.CellSize}
+	if err := common.WriteSolutionAnimation(&buf, lvl, solution, common.AnimationSVG, opts); err != nil {
+		return nil, fmt.Errorf("failed to animate level %d: %w", lvl.ID, err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *server) validateLevel(w http.ResponseWriter, r *http.Request) {
+	if _, path, ok := s.level(w, r); ok {
+		result, ok := validator.ValidateFile(r.Context(), path, r.URL.Query().Get("solvable") != "false", s.opts.MaxStates, false, 0, false)
+		s.writeValidation(w, result, ok, nil)
+	}
+}
+
+// validateBody runs the file checks on a posted level.
+func (s *server) validateBody(w http.ResponseWriter, r *http.Request) {
+	var lvl model.Level
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(&lvl); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid level JSON: %w", err))
+		return
+	}
+	result, ok, err := validator.ValidateLevel(r.Context(), &lvl, r.URL.Query().Get("solvable") != "false", s.opts.MaxStates, false)
+	s.writeValidation(w, result, ok, err)
+}
+
+func (s *server) writeValidation(w http.ResponseWriter, result validator.LevelResult, ok bool, err error) {
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !ok {
+		writeError(w, http.StatusServiceUnavailable, errors.New("validation was cancelled"))
+		return
+	}
+	writeJSON(w, http.StatusOK, struct {
+		validator.LevelResult
+		Passed bool `json:"passed"`
+	}{result, result.Passed()})
+}
+
+func (s *server) generate(w http.ResponseWriter, r *http.Request) {
+	var req GenerateRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid generate request: %w", err))
+		return
+	}
+	if req.ID <= 0 {
+		writeError(w, http.StatusBadRequest, errors.New("id must be a positive level ID"))
+		return
+	}
+	if req.Difficulty == "" {
+		req.Difficulty = common.DifficultyForLevel(req.ID)
+	}
+
+	ctx := r.Context()
+	if s.opts.GenerateTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.opts.GenerateTimeout)
+		defer cancel()
+	}
+	lvl, stats, err := levelgen.Generate(ctx, levelgen.GenerateOptions{
+		LevelID:    req.ID,
+		Difficulty: req.Difficulty,
+		Seed:       req.Seed,
+		Strategy:   req.Strategy,
+	})
+	if err != nil {
+		status := http.StatusUnprocessableEntity
+		if errors.Is(err, context.DeadlineExceeded) {
+			status = http.StatusGatewayTimeout
+		}
+		writeError(w, status, fmt.Errorf("failed to generate level %d: %w", req.ID, err))
+		return
+	}
+
+	resp := GenerateResponse{
+		Level:      lvl,
+		Strategy:   stats.Strategy,
+		Attempts:   stats.Attempts,
+		DurationMs: stats.Duration.Milliseconds(),
+	}
+	var buf bytes.Buffer
+	if err := common.RenderLevelImage(&buf, &lvl, common.ImageSVG, s.opts.CellSize); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	resp.SVG = buf.String()
+	// Generated levels are always solvable, so a failure here is only a
+	// cancelled request and the static render still goes back
+	if svg, err := s.animate(ctx, &lvl); err == nil {
+		resp.SolutionSVG = string(svg)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func writeSVG(w http.ResponseWriter, svg []byte) {
+	w.Header().Set("Content-Type", "image/svg+xml")
+	_, _ = w.Write(svg)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
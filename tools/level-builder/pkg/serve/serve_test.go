@@ -0,0 +1,131 @@
+package serve
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// testServer serves a levels directory holding one two-vine level and one
+// file that does not parse.
+func testServer(t *testing.T) *httptest.Server {
+	dir := t.TempDir()
+	lvl := model.Level{
+		ID: 3, Name: "Pair", Difficulty: "Seedling", GridSize: []int{3, 2},
+		Vines: []model.Vine{
+			{ID: "a", HeadDirection: "right", OrderedPath: []model.Point{{X: 2, Y: 1}, {X: 1, Y: 1}, {X: 0, Y: 1}}},
+			{ID: "b", HeadDirection: "left", OrderedPath: []model.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}}},
+		},
+		MaxMoves: 5, MinMoves: 2, Grace: 3, ColorScheme: []string{"#7CB342", "#FFB300"},
+	}
+	data, err := json.Marshal(lvl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string][]byte{"level_3.json": data, "level_4.json": []byte("{"), "notes.txt": []byte("x")} {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv := httptest.NewServer(NewHandler(Options{LevelsDir: dir, MaxStates: 10000}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func get(t *testing.T, url string) (*http.Response, string) {
+	t.Helper()
+	res, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res, string(body)
+}
+
+func TestLevelEndpoints(t *testing.T) {
+	srv := testServer(t)
+
+	res, body := get(t, srv.URL+"/")
+	if res.StatusCode != http.StatusOK || !strings.Contains(body, "<html") {
+		t.Fatalf("index: %d", res.StatusCode)
+	}
+
+	_, body = get(t, srv.URL+"/api/levels")
+	var list []Summary
+	if err := json.Unmarshal([]byte(body), &list); err != nil {
+		t.Fatalf("levels: %v in %s", err, body)
+	}
+	if len(list) != 2 || list[0].ID != 3 || list[0].Vines != 2 || list[1].ID != 4 || list[1].Error == "" {
+		t.Errorf("levels = %+v, want level 3 and broken level 4", list)
+	}
+
+	for path, want := range map[string]string{
+		"/api/levels/3/svg":          "<svg",
+		"/api/levels/3/solution.svg": "<animate",
+		"/api/levels/3":              `"name": "Pair"`,
+	} {
+		if res, body := get(t, srv.URL+path); res.StatusCode != http.StatusOK || !strings.Contains(body, want) {
+			t.Errorf("%s: %d %q", path, res.StatusCode, body)
+		}
+	}
+	for path, want := range map[string]int{
+		"/api/levels/9":   http.StatusNotFound,
+		"/api/levels/x":   http.StatusBadRequest,
+		"/api/levels/4":   http.StatusUnprocessableEntity,
+		"/api/levels/3/y": http.StatusNotFound,
+	} {
+		if res, _ := get(t, srv.URL+path); res.StatusCode != want {
+			t.Errorf("%s: status %d, want %d", path, res.StatusCode, want)
+		}
+	}
+}
+
+func TestValidateEndpoints(t *testing.T) {
+	srv := testServer(t)
+
+	_, body := get(t, srv.URL+"/api/levels/3/validate")
+	if !strings.Contains(body, `"passed": true`) || !strings.Contains(body, `"solvable": true`) {
+		t.Errorf("validate level 3: %s", body)
+	}
+
+	// Vines pointing into each other can never clear
+	stuck := `{"id": 5, "grid_size": [2, 2], "max_moves": 5, "grace": 3, "color_scheme": ["#7CB342"], "vines": [
+		{"id": "a", "head_direction": "right", "ordered_path": [{"x": 0, "y": 1}, {"x": 0, "y": 0}]},
+		{"id": "b", "head_direction": "left", "ordered_path": [{"x": 1, "y": 0}, {"x": 1, "y": 1}]}]}`
+	res, err := http.Post(srv.URL+"/api/validate", "application/json", strings.NewReader(stuck))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Passed bool   `json:"passed"`
+		Error  string `json:"error"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&got)
+	_ = res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusOK || got.Passed {
+		t.Errorf("posted level: status %d, %+v, want a failed result", res.StatusCode, got)
+	}
+
+	res, err = http.Post(srv.URL+"/api/generate", "application/json", strings.NewReader(`{"difficulty": "Seedling"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = res.Body.Close()
+	if res.StatusCode != http.StatusBadRequest {
+		t.Errorf("generate without id: status %d", res.StatusCode)
+	}
+}
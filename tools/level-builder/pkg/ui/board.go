@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+const (
+	boardBarWidth  = 20
+	boardStatusMax = 48 // longer statuses are cut so rows never wrap
+	boardRedrawGap = 100 * time.Millisecond
+)
+
+// Board is a live terminal display with one progress bar per row and a footer.
+// Every redraw moves the cursor back over the previous frame, so log lines
+// printed with Println stay above the bars instead of scrolling them away.
+type Board struct {
+	mu       sync.Mutex
+	w        io.Writer
+	rows     map[int]boardRow
+	footer   string
+	lines    int // lines drawn by the last frame
+	lastDraw time.Time
+}
+
+type boardRow struct {
+	label    string
+	fraction float64
+	status   string
+}
+
+// NewBoard returns a board drawing to w.
+func NewBoard(w io.Writer) *Board {
+	return &Board{w: w, rows: make(map[int]boardRow)}
+}
+
+// LiveDisplay reports whether a Board can draw: stdout must be a terminal,
+// and live output would interleave with verbose output and JSON log lines.
+func LiveDisplay() bool {
+	return interactive() && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// SetRow sets row id, shown in id order, with its bar fraction (0-1) and status.
+func (b *Board) SetRow(id int, label string, fraction float64, status string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if r := []rune(status); len(r) > boardStatusMax {
+		status = string(r[:boardStatusMax-1]) + "…"
+	}
+	b.rows[id] = boardRow{label: label, fraction: min(max(fraction, 0), 1), status: status}
+	b.redraw(false)
+}
+
+// SetFooter sets the summary line under the rows.
+func (b *Board) SetFooter(format string, args ...interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.footer = fmt.Sprintf(format, args...)
+	b.redraw(false)
+}
+
+// Println prints a line above the board.
+func (b *Board) Println(line string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.clear()
+	_, _ = fmt.Fprintln(b.w, line)
+	b.redraw(true)
+}
+
+// Close draws the final frame and leaves it on screen.
+func (b *Board) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.redraw(true)
+	b.lines = 0
+}
+
+// clear erases the previous frame and leaves the cursor where it began.
+func (b *Board) clear() {
+	if b.lines == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(b.w, "\x1b[%dA\x1b[J", b.lines)
+	b.lines = 0
+}
+
+// redraw replaces the previous frame, at most every boardRedrawGap unless forced.
+func (b *Board) redraw(force bool) {
+	if !force && time.Since(b.lastDraw) < boardRedrawGap {
+		return
+	}
+	b.lastDraw = time.Now()
+	b.clear()
+
+	ids := make([]int, 0, len(b.rows))
+	for id := range b.rows {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	var frame strings.Builder
+	for _, id := range ids {
+		row := b.rows[id]
+		filled := int(row.fraction*boardBarWidth + 0.5)
+		fmt.Fprintf(&frame, "%-24s %s%s %s\n", row.label,
+			strings.Repeat("█", filled), strings.Repeat("░", boardBarWidth-filled), row.status)
+	}
+	if b.footer != "" {
+		fmt.Fprintln(&frame, b.footer)
+	}
+	_, _ = io.WriteString(b.w, frame.String())
+	b.lines = strings.Count(frame.String(), "\n")
+}
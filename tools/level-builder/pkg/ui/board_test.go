@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBoard(t *testing.T) {
+	var out bytes.Buffer
+	b := NewBoard(&out)
+	b.SetRow(2, "Level 2", 1, "done")
+	b.SetRow(1, "Level 1", 0.5, strings.Repeat("x", 100))
+	b.Println("log line")
+	b.Close()
+
+	// The last frame follows the cursor-up that erased the one before it
+	frames := strings.Split(out.String(), "\x1b[")
+	frame := frames[len(frames)-1]
+	lines := strings.Split(strings.TrimSpace(frame), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("final frame has %d lines:\n%s", len(lines), frame)
+	}
+	if !strings.Contains(lines[0], "Level 1") || !strings.Contains(lines[0], strings.Repeat("█", 10)+strings.Repeat("░", 10)) {
+		t.Errorf("row 1 = %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[0], "…") || !strings.Contains(lines[1], "Level 2") {
+		t.Errorf("rows = %q", lines)
+	}
+	if !strings.Contains(out.String(), "log line\n") {
+		t.Error("Println output missing")
+	}
+}
@@ -23,11 +23,11 @@ func NewSpinner(msg string) *Spinner {
 	return &Spinner{s: s}
 }
 
-// Start starts the spinner if verbose mode is disabled.
+// Start starts the spinner unless verbose mode or JSON logging is enabled.
 func (s *Spinner) Start() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if !common.VerboseEnabled {
+	if interactive() {
 		s.s.Start()
 	}
 }
@@ -56,7 +56,7 @@ func (s *Spinner) LogInfo(format string, args ...interface{}) {
 		s.s.Stop()
 	}
 	common.Info(format, args...)
-	if wasRunning && !common.VerboseEnabled {
+	if wasRunning && interactive() {
 		s.s.Start()
 	}
 }
@@ -71,7 +71,13 @@ func (s *Spinner) LogWarning(format string, args ...interface{}) {
 		s.s.Stop()
 	}
 	common.Warning(format, args...)
-	if wasRunning && !common.VerboseEnabled {
+	if wasRunning && interactive() {
 		s.s.Start()
 	}
 }
+
+// interactive reports whether the spinner may draw: it would interleave with
+// verbose output and corrupt JSON log lines.
+func interactive() bool {
+	return !common.VerboseEnabled && !common.JSONLogging()
+}
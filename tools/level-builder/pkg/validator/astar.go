@@ -2,71 +2,74 @@ package validator
 
 import (
 	"container/heap"
+	"context"
 
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
 )
 
-// isSolvableExactAStarWithStats runs an A* search over mask states using a simple heuristic
-// that prefers states with fewer blocked vines. Returns solvable flag and states explored.
-func isSolvableExactAStarWithStats(lvl model.Level, maxStates int, astarWeight int) (bool, int) {
-	vines := lvl.Vines
-	vineCount := len(vines)
+// isSolvableExactAStarWithStats runs an A* search over mask states guided by the longest
+// remaining blocking chain (see blockingChains). Returns solvable flag, states explored and,
+// when solvable, the vine indices in clear order. Masked cells are passable, as in the
+// exact BFS and greedy solvers.
+func isSolvableExactAStarWithStats(ctx context.Context, lvl model.Level, maxStates int, astarWeight int) (bool, int, []int) {
+	switch n := len(lvl.Vines); {
+	case n <= 64:
+		return astarSearch[[1]uint64](ctx, lvl, maxStates, astarWeight)
+	case n <= 128:
+		return astarSearch[[2]uint64](ctx, lvl, maxStates, astarWeight)
+	case n <= 256:
+		return astarSearch[[4]uint64](ctx, lvl, maxStates, astarWeight)
+	default:
+		return astarSearch[[8]uint64](ctx, lvl, maxStates, astarWeight)
+	}
+}
+
+// astarSearch is the A* search behind isSolvableExactAStarWithStats.
+func astarSearch[M vineMask](ctx context.Context, lvl model.Level, maxStates int, astarWeight int) (bool, int, []int) {
+	vineCount := len(lvl.Vines)
 	w, h := lvl.GridSize[0], lvl.GridSize[1]
 	gridArea := w * h
 
-	// Precompute vine indices
-	vineIndices := make([][]int, vineCount)
-	for i, v := range vines {
-		indices := make([]int, len(v.OrderedPath))
-		for j, p := range v.OrderedPath {
-			indices[j] = p.Y*w + p.X
-		}
-		vineIndices[i] = indices
-	}
-
-	var fullMask uint64
-	if vineCount >= 64 {
-		fullMask = ^uint64(0)
-	} else {
-		fullMask = (uint64(1) << uint(vineCount)) - 1
-	}
+	vineIndices := vineCellIndices(lvl)
 
-	// Precompute mask visibility
-	maskBitset := make([]bool, gridArea)
-	for i := 0; i < gridArea; i++ {
-		maskBitset[i] = !lvl.IsCellVisible(i%w, i/w)
+	full := fullMask[M](vineCount)
+	limit := searchTableCapacity[M](ctx)
+	chains := newBlockingChains[M](lvl, vineIndices)
+	startPriority, ok := chains.priority(full, astarWeight)
+	if !ok {
+		// The starting vines block each other in a cycle; no order can clear them
+		return false, 1, nil
 	}
 
 	// A* structures
-	visited := make(map[uint64]bool)
-	pq := &priorityQueueMask{}
+	parents := make(map[M]M)
+	pq := &priorityQueueMask[M]{}
 	heap.Init(pq)
 
-	heap.Push(pq, &maskItem{
-		mask:     fullMask,
-		priority: heuristicPriorityFast(fullMask, lvl, vineIndices, astarWeight, maskBitset),
-	})
-	visited[fullMask] = true
+	heap.Push(pq, &maskItem[M]{mask: full, priority: startPriority})
+	parents[full] = full
 
 	states := 0
 	occupied := make([]bool, gridArea)
 
 	for pq.Len() > 0 {
-		if states >= maxStates {
-			return false, states
+		if states >= maxStates || cancelled(ctx, states) {
+			return false, states, nil
 		}
 
-		item := heap.Pop(pq).(*maskItem)
+		item := heap.Pop(pq).(*maskItem[M])
 		mask := item.mask
 		states++
-		if mask == 0 {
-			return true, states
+		if maskEmpty(mask) {
+			return true, states, clearOrder(parents, full)
 		}
 
-		// Update occupancy with precomputed mask and active vines
-		copy(occupied, maskBitset)
+		// Update occupancy with active vines (masked cells are passable)
+		for i := range occupied {
+			occupied[i] = false
+		}
 		for i := 0; i < vineCount; i++ {
-			if (mask & (uint64(1) << uint(i))) != 0 {
+			if maskHas(mask, i) {
 				for _, idx := range vineIndices[i] {
 					occupied[idx] = true
 				}
@@ -74,67 +77,158 @@ func isSolvableExactAStarWithStats(lvl model.Level, maxStates int, astarWeight i
 		}
 
 		// movable vines
+		cleared := vineCount - maskCount(mask)
 		for i := 0; i < vineCount; i++ {
-			if (mask & (uint64(1) << uint(i))) == 0 {
+			if !maskHas(mask, i) || lvl.Vines[i].IsLocked(cleared) {
 				continue
 			}
 			if canVineClearFast(lvl, i, occupied, vineIndices[i]) {
-				next := mask & ^(uint64(1) << uint(i))
-				if !visited[next] {
-					visited[next] = true
-					priority := heuristicPriorityFast(next, lvl, vineIndices, astarWeight, maskBitset)
-					heap.Push(pq, &maskItem{mask: next, priority: priority})
+				next := maskWithout(mask, i)
+				if _, seen := parents[next]; !seen {
+					parents[next] = mask
+					if limit > 0 && len(parents) >= limit {
+						return continueBounded[M](ctx, lvl, maxStates, states)
+					}
+					if priority, ok := chains.priority(next, astarWeight); ok {
+						heap.Push(pq, &maskItem[M]{mask: next, priority: priority})
+					}
 				}
 			}
 		}
 	}
 
-	return false, states
+	return false, states, nil
 }
 
-// heuristicPriorityFast computes a simple heuristic: blockedCount*weight + remainingVines
-func heuristicPriorityFast(mask uint64, lvl model.Level, vineIndices [][]int, weight int, maskBitset []bool) int {
-	w, h := lvl.GridSize[0], lvl.GridSize[1]
-	remaining := 0
-	blocked := 0
-
-	for i := 0; i < len(lvl.Vines); i++ {
-		if (mask & (uint64(1) << uint(i))) == 0 {
-			continue
+// vineCellIndices returns each vine's cells as y*w+x grid indices.
+func vineCellIndices(lvl model.Level) [][]int {
+	w := lvl.GridSize[0]
+	vineIndices := make([][]int, len(lvl.Vines))
+	for i, v := range lvl.Vines {
+		indices := make([]int, len(v.OrderedPath))
+		for j, p := range v.OrderedPath {
+			indices[j] = p.Y*w + p.X
 		}
-		remaining++
+		vineIndices[i] = indices
+	}
+	return vineIndices
+}
 
-		// count blocked vines
-		v := lvl.Vines[i]
-		head := v.OrderedPath[0]
-		dx, dy := directionDelta(v.HeadDirection)
-		nx, ny := head.X+dx, head.Y+dy
+// blockingChains guides the mask searches. A vine leaves by sliding along its exit ray, the
+// straight line from its head to the grid edge, so it can clear exactly when no other
+// remaining vine occupies a cell on that ray. A multi-head vine has two rays and only the
+// vines on both must clear before it. Those ray blockers form a graph; the longest
+// chain in it is the number of vines that must clear one after another before the most
+// deeply blocked vine can move.
+//
+// Every solution clears each remaining vine once, so the true cost-to-go is the remaining
+// vine count. The chain depth never exceeds remaining-1 and ranks states with equal counts,
+// preferring those whose blockers are shallow. A cycle among the remaining vines can never
+// be broken, so such states are dead ends and are pruned.
+//
+// A locked vine also waits for its remaining lock count of clears, so its depth is at least
+// that. A state whose vines cannot supply enough clears to open some lock is pruned too.
+type blockingChains[M vineMask] struct {
+	blockers []M     // blockers[i]: vines with a cell on vine i's exit ray
+	locks    []int   // locks[i]: clears vine i waits for (its LockedUntil)
+	depth    []int   // scratch: chain depth per vine for the mask being scored
+	visit    []uint8 // scratch: 0 unvisited, 1 on stack, 2 done
+}
 
-		if nx < 0 || nx >= w || ny < 0 || ny >= h {
-			continue // can exit
+func newBlockingChains[M vineMask](lvl model.Level, vineIndices [][]int) *blockingChains[M] {
+	w, h := lvl.GridSize[0], lvl.GridSize[1]
+	owner := make([]int, w*h)
+	for i := range owner {
+		owner[i] = -1
+	}
+	for i, cells := range vineIndices {
+		for _, idx := range cells {
+			owner[idx] = i
 		}
+	}
 
-		nextIdx := ny*w + nx
-		// Blocked by grid mask?
-		if maskBitset[nextIdx] {
-			blocked++
+	n := len(lvl.Vines)
+	c := &blockingChains[M]{
+		blockers: make([]M, n),
+		locks:    make([]int, n),
+		depth:    make([]int, n),
+		visit:    make([]uint8, n),
+	}
+	for i, v := range lvl.Vines {
+		c.locks[i] = v.LockedUntil
+		if len(v.OrderedPath) == 0 {
 			continue
 		}
+		heads := v.Heads()
+		c.blockers[i] = exitRayBlockers[M](&lvl, heads[0], i, owner)
+		for _, rev := range heads[1:] {
+			// Either head may leave, so only vines on both rays must clear first
+			c.blockers[i] = maskAnd(c.blockers[i], exitRayBlockers[M](&lvl, rev, i, owner))
+		}
+	}
+	return c
+}
 
-		// Blocked by other vines?
-		for j := 0; j < len(lvl.Vines); j++ {
-			if (mask&(uint64(1)<<uint(j))) == 0 || i == j {
-				continue
-			}
-			for _, idx := range vineIndices[j] {
-				if idx == nextIdx {
-					blocked++
-					goto nextVine
-				}
-			}
+// exitRayBlockers returns the vines, other than self, with a cell on v's exit ray
+// (which follows lvl's portals).
+func exitRayBlockers[M vineMask](lvl *model.Level, v model.Vine, self int, owner []int) M {
+	w := lvl.GridSize[0]
+	var blockers M
+	lvl.ExitRay(v.OrderedPath[0], v.HeadDirection, func(p model.Point) bool {
+		if j := owner[p.Y*w+p.X]; j >= 0 && j != self {
+			blockers = maskWith(blockers, j)
 		}
-	nextVine:
+		return true
+	})
+	return blockers
+}
+
+// priority scores mask as weight*longest chain + remaining vines. It returns false when
+// the remaining vines block each other in a cycle or can never open a lock.
+func (c *blockingChains[M]) priority(mask M, weight int) (int, bool) {
+	for i := range c.visit {
+		c.visit[i] = 0
+	}
+
+	remaining := maskCount(mask)
+	cleared := len(c.locks) - remaining
+	longest := 0
+	ok := maskEach(mask, func(i int) bool {
+		if c.locks[i]-cleared > remaining-1 {
+			return false
+		}
+		d, ok := c.chain(i, mask, cleared)
+		longest = max(longest, d)
+		return ok
+	})
+	if !ok {
+		return 0, false
+	}
+	return weight*longest + remaining, true
+}
+
+// chain returns the blocker chain depth of vine i among the vines in mask, once cleared
+// vines have left.
+func (c *blockingChains[M]) chain(i int, mask M, cleared int) (int, bool) {
+	switch c.visit[i] {
+	case 1:
+		return 0, false
+	case 2:
+		return c.depth[i], true
+	}
+	c.visit[i] = 1
+
+	best := max(c.locks[i]-cleared, 0)
+	ok := maskEach(maskAnd(c.blockers[i], mask), func(j int) bool {
+		d, ok := c.chain(j, mask, cleared)
+		best = max(best, d+1)
+		return ok
+	})
+	if !ok {
+		return 0, false
 	}
 
-	return blocked*weight + remaining
+	c.visit[i] = 2
+	c.depth[i] = best
+	return best, true
 }
@@ -0,0 +1,284 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// Solver names used in audit reports.
+const (
+	AuditGreedy = "greedy"
+	AuditBFS    = "bfs"
+	AuditAStar  = "astar"
+)
+
+// AuditSolvers lists the audited solvers in report order.
+var AuditSolvers = []string{AuditGreedy, AuditBFS, AuditAStar}
+
+// SolverRun is one solver's verdict on one level.
+type SolverRun struct {
+	Solver    string  `json:"solver"`
+	Solvable  bool    `json:"solvable"`
+	GaveUp    bool    `json:"gave_up,omitempty"` // search budget ran out; the verdict is inconclusive
+	Skipped   string  `json:"skipped,omitempty"` // why the solver could not run
+	Invalid   string  `json:"invalid,omitempty"` // the returned clear order fails replay
+	States    int     `json:"states"`            // states expanded (greedy: vines cleared)
+	ElapsedMS float64 `json:"elapsed_ms"`
+}
+
+// Conclusive reports whether the run produced a verdict to compare.
+func (r SolverRun) Conclusive() bool {
+	return r.Skipped == "" && !r.GaveUp
+}
+
+// AuditResult compares the solvers on one level file.
+type AuditResult struct {
+	File     string      `json:"file"`
+	LevelID  int         `json:"level_id,omitempty"`
+	Vines    int         `json:"vines"`
+	Error    string      `json:"error,omitempty"` // the file could not be read
+	Runs     []SolverRun `json:"runs,omitempty"`
+	Disagree bool        `json:"disagree"`
+}
+
+// Run returns the result's run for solver.
+func (r AuditResult) Run(solver string) (SolverRun, bool) {
+	for _, run := range r.Runs {
+		if run.Solver == solver {
+			return run, true
+		}
+	}
+	return SolverRun{}, false
+}
+
+// SolverTotals sums one solver's runs across an audit.
+type SolverTotals struct {
+	Solvable   int     `json:"solvable"`
+	Unsolvable int     `json:"unsolvable"`
+	GaveUp     int     `json:"gave_up"`
+	Skipped    int     `json:"skipped"`
+	Invalid    int     `json:"invalid"`
+	States     int     `json:"states"`
+	ElapsedMS  float64 `json:"elapsed_ms"`
+}
+
+// SolverAudit is the result of AuditLevels, saved as solver_audit.json.
+type SolverAudit struct {
+	CreatedAt     time.Time               `json:"created_at"`
+	MaxStates     int                     `json:"max_states"`
+	AStarWeight   int                     `json:"astar_weight"`
+	Disagreements int                     `json:"disagreements"`
+	Totals        map[string]SolverTotals `json:"totals"`
+	Levels        []AuditResult           `json:"levels"`
+}
+
+// AuditLevels runs the greedy, exact BFS and A* solvers on every level in
+// levelsDir and records where their verdicts disagree. Levels run one at a
+// time so the timings are comparable. Every search gets the same maxStates
+// budget; a search that exhausts it is inconclusive and never counts as a
+// disagreement. A clear order that fails replay counts as one, since it
+// means the solver accepted an unsolvable level.
+func AuditLevels(ctx context.Context, levelsDir string, maxStates, astarWeight int) (SolverAudit, error) {
+	audit := SolverAudit{
+		CreatedAt:   time.Now().UTC(),
+		MaxStates:   maxStates,
+		AStarWeight: astarWeight,
+		Totals:      make(map[string]SolverTotals),
+	}
+	files, err := filepath.Glob(filepath.Join(levelsDir, "level_*.json"))
+	if err != nil {
+		return audit, err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return levelFileLess(filepath.Base(files[i]), filepath.Base(files[j]))
+	})
+
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return audit, err
+		}
+		result := AuditResult{File: filepath.Base(f)}
+		lvl, err := common.ReadLevel(f)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result = AuditLevel(ctx, *lvl, maxStates, astarWeight)
+			result.File = filepath.Base(f)
+		}
+		common.Verbose("%s: disagree=%v", result.File, result.Disagree)
+		audit.add(result)
+	}
+	return audit, ctx.Err()
+}
+
+func (a *SolverAudit) add(result AuditResult) {
+	a.Levels = append(a.Levels, result)
+	if result.Disagree {
+		a.Disagreements++
+	}
+	for _, run := range result.Runs {
+		t := a.Totals[run.Solver]
+		switch {
+		case run.Skipped != "":
+			t.Skipped++
+		case run.GaveUp:
+			t.GaveUp++
+		case run.Solvable:
+			t.Solvable++
+		default:
+			t.Unsolvable++
+		}
+		if run.Invalid != "" {
+			t.Invalid++
+		}
+		t.States += run.States
+		t.ElapsedMS += run.ElapsedMS
+		a.Totals[run.Solver] = t
+	}
+}
+
+// AuditLevel runs every audited solver on lvl and compares their verdicts.
+func AuditLevel(ctx context.Context, lvl model.Level, maxStates, astarWeight int) AuditResult {
+	result := AuditResult{LevelID: lvl.ID, Vines: len(lvl.Vines)}
+
+	start := time.Now()
+	order, ok := common.NewSolver(&lvl).GreedyClearOrder()
+	greedy := SolverRun{Solver: AuditGreedy, Solvable: ok, States: len(order), ElapsedMS: sinceMS(start)}
+	// Greedy stops at the first dead end, so only its successes are verdicts
+	greedy.GaveUp = !ok
+	result.Runs = append(result.Runs, checkOrder(lvl, greedy, order))
+
+	searches := []struct {
+		solver string
+		search func() (bool, int, []int)
+	}{
+		{AuditBFS, func() (bool, int, []int) { return isSolvableExactWithStats(ctx, lvl, maxStates) }},
+		{AuditAStar, func() (bool, int, []int) { return isSolvableExactAStarWithStats(ctx, lvl, maxStates, astarWeight) }},
+	}
+	for _, s := range searches {
+		run := SolverRun{Solver: s.solver}
+		if len(lvl.Vines) > maxMaskVines {
+			run.Skipped = fmt.Sprintf("%d vines exceed the %d-vine state mask", len(lvl.Vines), maxMaskVines)
+			result.Runs = append(result.Runs, run)
+			continue
+		}
+		start := time.Now()
+		ok, states, order := s.search()
+		run.Solvable, run.States, run.ElapsedMS = ok, states, sinceMS(start)
+		run.GaveUp = !ok && (states >= maxStates || ctx.Err() != nil)
+		result.Runs = append(result.Runs, checkOrder(lvl, run, order))
+	}
+
+	verdicts := make(map[bool]bool)
+	for _, run := range result.Runs {
+		if run.Invalid != "" {
+			result.Disagree = true
+		}
+		if run.Conclusive() {
+			verdicts[run.Solvable] = true
+		}
+	}
+	if len(verdicts) > 1 {
+		result.Disagree = true
+	}
+	return result
+}
+
+// checkOrder replays a solvable run's clear order with the validator's move
+// rules and records the first illegal move in run.Invalid.
+func checkOrder(lvl model.Level, run SolverRun, order []int) SolverRun {
+	if !run.Solvable {
+		return run
+	}
+	if len(order) != len(lvl.Vines) {
+		run.Invalid = fmt.Sprintf("clear order has %d moves for %d vines", len(order), len(lvl.Vines))
+		return run
+	}
+	w := lvl.GridSize[0]
+	vineIndices := vineCellIndices(lvl)
+	occupied := make([]bool, w*lvl.GridSize[1])
+	for _, cells := range vineIndices {
+		for _, idx := range cells {
+			occupied[idx] = true
+		}
+	}
+	for step, idx := range order {
+		if lvl.Vines[idx].IsLocked(step) || !canVineClearFast(lvl, idx, occupied, vineIndices[idx]) {
+			run.Invalid = fmt.Sprintf("move %d: vine %s cannot clear", step+1, lvl.Vines[idx].ID)
+			return run
+		}
+		for _, cell := range vineIndices[idx] {
+			occupied[cell] = false
+		}
+	}
+	return run
+}
+
+func sinceMS(start time.Time) float64 {
+	return float64(time.Since(start).Microseconds()) / 1000
+}
+
+// Save writes the audit as indented JSON.
+func (a SolverAudit) Save(path string) error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// WriteText prints per-solver totals followed by every level the solvers
+// disagree on, with each solver's verdict, states and time.
+func (a SolverAudit) WriteText(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "Solver audit: %d levels, max states %d\n\n", len(a.Levels), a.MaxStates)
+	_, _ = fmt.Fprintf(w, "  %-7s %8s %10s %7s %7s %7s %12s %10s\n",
+		"solver", "solvable", "unsolvable", "gaveup", "skipped", "invalid", "states", "time")
+	for _, solver := range AuditSolvers {
+		t := a.Totals[solver]
+		_, _ = fmt.Fprintf(w, "  %-7s %8d %10d %7d %7d %7d %12d %8.1fms\n",
+			solver, t.Solvable, t.Unsolvable, t.GaveUp, t.Skipped, t.Invalid, t.States, t.ElapsedMS)
+	}
+
+	for _, l := range a.Levels {
+		if l.Error != "" {
+			_, _ = fmt.Fprintf(w, "\n%s: %s\n", l.File, l.Error)
+		}
+	}
+	if a.Disagreements == 0 {
+		_, _ = fmt.Fprintln(w, "\n✓ All conclusive solver verdicts agree.")
+		return
+	}
+	_, _ = fmt.Fprintf(w, "\n❌ Solvers disagree on %d levels:\n", a.Disagreements)
+	for _, l := range a.Levels {
+		if !l.Disagree {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "\n%s (level %d, %d vines)\n", l.File, l.LevelID, l.Vines)
+		for _, run := range l.Runs {
+			_, _ = fmt.Fprintf(w, "  %-7s %-12s %10d states %8.1fms %s\n",
+				run.Solver, run.verdict(), run.States, run.ElapsedMS, run.Invalid)
+		}
+	}
+}
+
+func (r SolverRun) verdict() string {
+	switch {
+	case r.Skipped != "":
+		return "skipped"
+	case r.GaveUp:
+		return "gave up"
+	case r.Solvable:
+		return "solvable"
+	default:
+		return "unsolvable"
+	}
+}
@@ -0,0 +1,74 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuditLevel(t *testing.T) {
+	ctx := context.Background()
+
+	solvable := AuditLevel(ctx, blockedChainLevel(), 1000, DefaultAStarWeight)
+	if solvable.Disagree || len(solvable.Runs) != len(AuditSolvers) {
+		t.Fatalf("solvable level: %+v", solvable)
+	}
+	for _, run := range solvable.Runs {
+		if !run.Solvable || !run.Conclusive() || run.Invalid != "" {
+			t.Errorf("%s: %+v", run.Solver, run)
+		}
+	}
+
+	// Greedy gets stuck on facing heads; that is inconclusive, not a disagreement
+	stuck := AuditLevel(ctx, facingHeadsLevel(), 1000, DefaultAStarWeight)
+	if greedy, _ := stuck.Run(AuditGreedy); !greedy.GaveUp {
+		t.Errorf("greedy = %+v, want gave up", greedy)
+	}
+	if bfs, _ := stuck.Run(AuditBFS); bfs.Solvable || !bfs.Conclusive() {
+		t.Errorf("bfs = %+v, want conclusive unsolvable", bfs)
+	}
+	if stuck.Disagree {
+		t.Errorf("unexpected disagreement: %+v", stuck.Runs)
+	}
+
+	// A solver that accepts a level with an order that does not replay disagrees
+	run := checkOrder(blockedChainLevel(), SolverRun{Solver: "bad", Solvable: true}, []int{0, 1})
+	if run.Invalid != "move 1: vine blocked cannot clear" {
+		t.Errorf("invalid = %q", run.Invalid)
+	}
+}
+
+func TestAuditLevels(t *testing.T) {
+	dir := t.TempDir()
+	data, err := json.Marshal(blockedChainLevel())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "level_1.json"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "level_2.json"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	audit, err := AuditLevels(context.Background(), dir, 1000, DefaultAStarWeight)
+	if err != nil {
+		t.Fatalf("AuditLevels: %v", err)
+	}
+	if len(audit.Levels) != 2 || audit.Levels[1].Error == "" || audit.Disagreements != 0 {
+		t.Fatalf("audit = %+v", audit)
+	}
+	if got := audit.Totals[AuditAStar]; got.Solvable != 1 || got.States == 0 {
+		t.Errorf("astar totals = %+v", got)
+	}
+
+	var out bytes.Buffer
+	audit.WriteText(&out)
+	if !strings.Contains(out.String(), "All conclusive solver verdicts agree") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
@@ -0,0 +1,163 @@
+package validator
+
+import (
+	"container/list"
+	"context"
+	"sort"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// Approximate per-state memory beyond the masks themselves: map buckets plus a queue or heap
+// slot for the mask searches, and a list element for the dead-state table.
+const (
+	searchStateOverhead = 64
+	deadStateOverhead   = 96
+)
+
+type maxMemoryKey struct{}
+
+// WithMaxMemory returns a context under which each solvability search keeps its state tables
+// within about mb megabytes. A search whose table fills up continues as a depth-first search
+// that only remembers a bounded, least-recently-used set of dead-end states. mb <= 0 leaves
+// the searches unbounded.
+func WithMaxMemory(ctx context.Context, mb int) context.Context {
+	return context.WithValue(ctx, maxMemoryKey{}, mb<<20)
+}
+
+// tableCapacity returns how many states of stateBytes each fit in ctx's memory budget, or 0
+// when ctx sets no budget.
+func tableCapacity(ctx context.Context, stateBytes int) int {
+	limit, _ := ctx.Value(maxMemoryKey{}).(int)
+	if limit <= 0 {
+		return 0
+	}
+	return max(limit/stateBytes, 1)
+}
+
+// maskBytes returns the size of one M.
+func maskBytes[M vineMask]() int {
+	var m M
+	return len(m) * 8
+}
+
+// searchTableCapacity is how many states a mask search may store under ctx's budget
+// (0 = unbounded). Each state holds a parent-map key and value plus a queue entry.
+func searchTableCapacity[M vineMask](ctx context.Context) int {
+	return tableCapacity(ctx, 3*maskBytes[M]()+searchStateOverhead)
+}
+
+// continueBounded restarts a search whose state table reached its memory budget after
+// states expansions as a boundedSearch with the rest of the states budget.
+func continueBounded[M vineMask](ctx context.Context, lvl model.Level, maxStates, states int) (bool, int, []int) {
+	common.Verbose("Level %d: solver state table reached its memory budget after %d states; continuing depth-first", lvl.ID, states)
+	ok, more, order := boundedSearch[M](ctx, lvl, maxStates-states, tableCapacity(ctx, 2*maskBytes[M]()+deadStateOverhead))
+	return ok, states + more, order
+}
+
+// boundedSearch is a depth-first search in blocking-chain priority order whose memory is its
+// path plus a dead-state table of at most capacity states (0 = unbounded). Evicting a dead
+// state only costs re-exploring it, so the verdict stays exact within maxStates.
+func boundedSearch[M vineMask](ctx context.Context, lvl model.Level, maxStates, capacity int) (bool, int, []int) {
+	vineCount := len(lvl.Vines)
+	vineIndices := vineCellIndices(lvl)
+	chains := newBlockingChains[M](lvl, vineIndices)
+	dead := newDeadTable[M](capacity)
+	occupied := make([]bool, lvl.GridSize[0]*lvl.GridSize[1])
+
+	type move struct{ vine, priority int }
+	var path []int
+	states := 0
+	stop := false
+
+	var visit func(mask M) bool
+	visit = func(mask M) bool {
+		if maskEmpty(mask) {
+			return true
+		}
+		if states >= maxStates || cancelled(ctx, states) {
+			stop = true
+			return false
+		}
+		states++
+		if dead.has(mask) {
+			return false
+		}
+
+		for i := range occupied {
+			occupied[i] = false
+		}
+		for i := 0; i < vineCount; i++ {
+			if maskHas(mask, i) {
+				for _, idx := range vineIndices[i] {
+					occupied[idx] = true
+				}
+			}
+		}
+
+		cleared := vineCount - maskCount(mask)
+		var moves []move
+		for i := 0; i < vineCount; i++ {
+			if !maskHas(mask, i) || lvl.Vines[i].IsLocked(cleared) || !canVineClearFast(lvl, i, occupied, vineIndices[i]) {
+				continue
+			}
+			if priority, ok := chains.priority(maskWithout(mask, i), 1); ok {
+				moves = append(moves, move{i, priority})
+			}
+		}
+		sort.SliceStable(moves, func(a, b int) bool { return moves[a].priority < moves[b].priority })
+
+		for _, m := range moves {
+			path = append(path, m.vine)
+			if visit(maskWithout(mask, m.vine)) {
+				return true
+			}
+			path = path[:len(path)-1]
+			if stop {
+				return false
+			}
+		}
+		dead.add(mask)
+		return false
+	}
+
+	full := fullMask[M](vineCount)
+	if _, ok := chains.priority(full, 1); !ok {
+		// The starting vines block each other in a cycle; no order can clear them
+		return false, 1, nil
+	}
+	if !visit(full) {
+		return false, states, nil
+	}
+	return true, states, path
+}
+
+// deadTable remembers states with no way to clear every vine, evicting the least recently
+// used once it holds capacity states (0 = unbounded).
+type deadTable[M vineMask] struct {
+	capacity int
+	items    map[M]*list.Element
+	order    *list.List // front: most recently used
+}
+
+func newDeadTable[M vineMask](capacity int) *deadTable[M] {
+	return &deadTable[M]{capacity: capacity, items: make(map[M]*list.Element), order: list.New()}
+}
+
+func (d *deadTable[M]) has(m M) bool {
+	e, ok := d.items[m]
+	if ok {
+		d.order.MoveToFront(e)
+	}
+	return ok
+}
+
+func (d *deadTable[M]) add(m M) {
+	d.items[m] = d.order.PushFront(m)
+	if d.capacity > 0 && d.order.Len() > d.capacity {
+		oldest := d.order.Back()
+		d.order.Remove(oldest)
+		delete(d.items, oldest.Value.(M))
+	}
+}
@@ -0,0 +1,189 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// Deadlock explains why a level cannot be cleared.
+type Deadlock struct {
+	// Vines is a minimal set of vines that cannot all be cleared even with every other
+	// vine gone: dropping any one of them makes the rest clearable.
+	Vines []string `json:"vines"`
+	// Cycle lists vines that each have the next one on their exit ray, so each waits for
+	// the next to clear; the first is repeated at the end. Empty for lock deadlocks.
+	Cycle []string `json:"cycle,omitempty"`
+	// Locked is set when the vines would clear but for their lock counts
+	Locked bool `json:"locked,omitempty"`
+	// Render is an ASCII board with Vines highlighted
+	Render string `json:"render"`
+}
+
+// ExplainUnsolvable looks for the reason lvl cannot be cleared. It shrinks the level one
+// vine at a time, keeping every removal after which the remaining vines still cannot be
+// cleared, until only an irreducible deadlocked set is left (an unsat core of the level).
+// Removing vines only frees cells, so that set is stuck whatever the other vines do.
+//
+// It returns nil when lvl is solvable or the solver gives up within maxStates states on
+// the level or a candidate set, since no deadlock can then be proven.
+func ExplainUnsolvable(ctx context.Context, lvl model.Level, maxStates int) (*Deadlock, error) {
+	stuck, err := provenUnsolvable(ctx, lvl, maxStates)
+	if err != nil || !stuck {
+		return nil, err
+	}
+
+	// Lock counts depend on how many vines clear first, so they would turn every small set
+	// into a deadlock; shrink with locks lifted and blame the locks if that clears the level.
+	unlocked := withVines(lvl, allIndices(len(lvl.Vines)))
+	stuck, err = provenUnsolvable(ctx, unlocked, maxStates)
+	if err != nil {
+		return nil, err
+	}
+	if !stuck {
+		var locked []string
+		for _, v := range lvl.Vines {
+			if v.LockedUntil > 0 {
+				locked = append(locked, v.ID)
+			}
+		}
+		return newDeadlock(lvl, locked, nil, true), nil
+	}
+
+	core := allIndices(len(lvl.Vines))
+	for i := 0; i < len(core); {
+		candidate := append(append([]int{}, core[:i]...), core[i+1:]...)
+		stuck, err := provenUnsolvable(ctx, withVines(lvl, candidate), maxStates)
+		if err != nil {
+			return nil, err
+		}
+		if stuck {
+			core = candidate
+			continue
+		}
+		i++
+	}
+
+	sub := withVines(lvl, core)
+	ids := make([]string, len(sub.Vines))
+	for i, v := range sub.Vines {
+		ids[i] = v.ID
+	}
+	return newDeadlock(lvl, ids, blockingCycle(sub), false), nil
+}
+
+// provenUnsolvable reports whether the solver proves lvl cannot be cleared.
+func provenUnsolvable(ctx context.Context, lvl model.Level, maxStates int) (bool, error) {
+	ok, _, stats, err := SolveWithOptionsContext(ctx, lvl, maxStates, true, DefaultAStarWeight)
+	if err != nil {
+		return false, err
+	}
+	return !ok && !stats.GaveUp, nil
+}
+
+// withVines returns lvl reduced to the vines at indices, with their locks lifted.
+func withVines(lvl model.Level, indices []int) model.Level {
+	vines := make([]model.Vine, len(indices))
+	for i, idx := range indices {
+		vines[i] = lvl.Vines[idx]
+		vines[i].LockedUntil = 0
+	}
+	lvl.Vines = vines
+	return lvl
+}
+
+func allIndices(n int) []int {
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = i
+	}
+	return indices
+}
+
+// blockingCycle returns vine IDs that each have the next on their exit ray (both rays for
+// multi-head vines), closed by repeating the first, or nil when no vines of lvl do.
+func blockingCycle(lvl model.Level) []string {
+	chains := newBlockingChains[[8]uint64](lvl, vineCellIndices(lvl))
+	state := make([]uint8, len(lvl.Vines)) // 0 unvisited, 1 on stack, 2 done
+	var stack []int
+
+	var visit func(i int) []int
+	visit = func(i int) []int {
+		state[i] = 1
+		stack = append(stack, i)
+		var cycle []int
+		maskEach(chains.blockers[i], func(j int) bool {
+			switch state[j] {
+			case 0:
+				cycle = visit(j)
+			case 1:
+				for k, v := range stack {
+					if v == j {
+						cycle = append(append([]int{}, stack[k:]...), j)
+					}
+				}
+			}
+			return cycle == nil
+		})
+		stack = stack[:len(stack)-1]
+		state[i] = 2
+		return cycle
+	}
+
+	for i := range lvl.Vines {
+		if state[i] != 0 {
+			continue
+		}
+		if cycle := visit(i); cycle != nil {
+			ids := make([]string, len(cycle))
+			for k, v := range cycle {
+				ids[k] = lvl.Vines[v].ID
+			}
+			return ids
+		}
+	}
+	return nil
+}
+
+func newDeadlock(lvl model.Level, vines, cycle []string, locked bool) *Deadlock {
+	var buf bytes.Buffer
+	common.RenderHighlightedToWriter(&buf, &lvl, "ascii", true, vines)
+	return &Deadlock{Vines: vines, Cycle: cycle, Locked: locked, Render: buf.String()}
+}
+
+// summary describes the deadlock in one line.
+func (d *Deadlock) summary() string {
+	switch {
+	case d.Locked:
+		return "locks can never open: " + strings.Join(d.Vines, ", ")
+	case len(d.Cycle) > 0:
+		return "circular blocking: " + strings.Join(d.Cycle, " -> ")
+	default:
+		return "deadlocked vines: " + strings.Join(d.Vines, ", ")
+	}
+}
+
+// writeText prints the deadlocked vines, their cycle and the highlighted board, indented
+// to sit under a report line.
+func (d *Deadlock) writeText(w io.Writer) {
+	if d.Locked {
+		_, _ = fmt.Fprintf(w, "      Locked vines that can never open: %s\n", strings.Join(d.Vines, ", "))
+	} else {
+		_, _ = fmt.Fprintf(w, "      Deadlocked vines: %s\n", strings.Join(d.Vines, ", "))
+	}
+	if len(d.Cycle) > 0 {
+		_, _ = fmt.Fprintf(w, "      Cycle (each waits for the next to clear): %s\n", strings.Join(d.Cycle, " -> "))
+	}
+	for _, line := range strings.Split(strings.TrimRight(d.Render, "\n"), "\n") {
+		if line == "" {
+			_, _ = fmt.Fprintln(w)
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "      %s\n", line)
+	}
+}
@@ -0,0 +1,58 @@
+package validator
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func TestExplainUnsolvable(t *testing.T) {
+	ctx := context.Background()
+	// a and b face each other; c waits on a, d is free
+	lvl := model.Level{
+		ID:       9,
+		GridSize: []int{4, 4},
+		Vines: []model.Vine{
+			{ID: "c", HeadDirection: "down", OrderedPath: []model.Point{{X: 1, Y: 1}, {X: 1, Y: 2}}},
+			{ID: "a", HeadDirection: "right", OrderedPath: []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}},
+			{ID: "d", HeadDirection: "up", OrderedPath: []model.Point{{X: 3, Y: 2}, {X: 3, Y: 1}}},
+			{ID: "b", HeadDirection: "left", OrderedPath: []model.Point{{X: 2, Y: 0}, {X: 3, Y: 0}}},
+		},
+	}
+
+	d, err := ExplainUnsolvable(ctx, lvl, 10000)
+	if err != nil || d == nil {
+		t.Fatalf("ExplainUnsolvable = %v, %v", d, err)
+	}
+	if !reflect.DeepEqual(d.Vines, []string{"a", "b"}) || d.Locked {
+		t.Errorf("deadlocked vines = %v (locked %v), want [a b]", d.Vines, d.Locked)
+	}
+	if !reflect.DeepEqual(d.Cycle, []string{"a", "b", "a"}) {
+		t.Errorf("cycle = %v, want [a b a]", d.Cycle)
+	}
+	if !strings.Contains(d.Render, "o  >  <  o") || !strings.Contains(d.Render, ".  :  .  :") {
+		t.Errorf("render should draw a and b and shade c and d:\n%s", d.Render)
+	}
+	if got := d.summary(); got != "circular blocking: a -> b -> a" {
+		t.Errorf("summary = %q", got)
+	}
+
+	// Without b the level clears, so there is nothing to explain
+	solvable := lvl
+	solvable.Vines = lvl.Vines[:3]
+	if d, err := ExplainUnsolvable(ctx, solvable, 10000); err != nil || d != nil {
+		t.Errorf("solvable level explained as %+v, %v", d, err)
+	}
+
+	// A vine waiting for more clears than the level has can never open
+	locked := solvable
+	locked.Vines = append([]model.Vine{}, solvable.Vines...)
+	locked.Vines[2].LockedUntil = 3
+	d, err = ExplainUnsolvable(ctx, locked, 10000)
+	if err != nil || d == nil || !d.Locked || !reflect.DeepEqual(d.Vines, []string{"d"}) {
+		t.Errorf("locked level explained as %+v, %v", d, err)
+	}
+}
@@ -0,0 +1,69 @@
+package validator
+
+import (
+	"context"
+	"errors"
+)
+
+// Severity says whether a finding fails a level.
+type Severity string
+
+// Finding severities.
+const (
+	SeverityError   Severity = "error"   // fails the level
+	SeverityWarning Severity = "warning" // reported, fails the level only under WithStrict
+	SeverityInfo    Severity = "info"    // detail for a human reader, never fails the level
+)
+
+// ValidationResult is one finding of a validation check.
+type ValidationResult struct {
+	Check    string   `json:"check"` // rule ID, or the level-file check that found it
+	Severity Severity `json:"severity"`
+	VineID   string   `json:"vine_id,omitempty"`
+	Message  string   `json:"message"`
+}
+
+func (r ValidationResult) Error() string {
+	return StructuralError{VineID: r.VineID, Message: r.Message}.Error()
+}
+
+// newFinding wraps err as a finding of check, keeping the vine of a StructuralError.
+func newFinding(check string, severity Severity, err error) ValidationResult {
+	var se StructuralError
+	if errors.As(err, &se) {
+		return ValidationResult{Check: check, Severity: severity, VineID: se.VineID, Message: se.Message}
+	}
+	return ValidationResult{Check: check, Severity: severity, Message: err.Error()}
+}
+
+// firstError returns the first error-severity finding, or nil.
+func firstError(findings []ValidationResult) error {
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			return f
+		}
+	}
+	return nil
+}
+
+// promoteWarnings turns warnings into errors, for WithStrict.
+func promoteWarnings(findings []ValidationResult) {
+	for i := range findings {
+		if findings[i].Severity == SeverityWarning {
+			findings[i].Severity = SeverityError
+		}
+	}
+}
+
+type strictKey struct{}
+
+// WithStrict returns a context under which ValidateReport and ValidateFile fail levels on
+// warnings as well as errors, for CI runs that must not let warnings accumulate.
+func WithStrict(ctx context.Context, strict bool) context.Context {
+	return context.WithValue(ctx, strictKey{}, strict)
+}
+
+func strictFrom(ctx context.Context) bool {
+	strict, _ := ctx.Value(strictKey{}).(bool)
+	return strict
+}
@@ -0,0 +1,83 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func TestCheckOccupancyAndCoverageSeverities(t *testing.T) {
+	lvl := baseFullGridLevel()
+	lvl.Vines[3].OrderedPath = lvl.Vines[3].OrderedPath[:3] // leaves (0,3) uncovered
+
+	findings := checkOccupancyAndCoverage(lvl, false)
+	if err := firstError(findings); err != nil {
+		t.Fatalf("unexpected error finding: %v", err)
+	}
+	if len(findings) != 2 || findings[0].Severity != SeverityWarning || findings[1].Severity != SeverityInfo ||
+		!strings.Contains(findings[1].Message, "(0,3)") {
+		t.Errorf("findings = %+v, want a coverage warning and the uncovered cell as info", findings)
+	}
+
+	lvl.Vines = lvl.Vines[:1]
+	if err := firstError(checkOccupancyAndCoverage(lvl, false)); err == nil || !strings.Contains(err.Error(), "occupancy") {
+		t.Errorf("low occupancy = %v, want an error", err)
+	}
+	for _, f := range checkOccupancyAndCoverage(lvl, true) {
+		if f.Check == "occupancy" && f.Severity != SeverityWarning {
+			t.Errorf("ignored low occupancy has severity %s, want warning", f.Severity)
+		}
+	}
+}
+
+func TestGapsCountAsCovered(t *testing.T) {
+	lvl := baseFullGridLevel()
+	lvl.Vines[3].OrderedPath = lvl.Vines[3].OrderedPath[:3]
+	lvl.Mask = model.NewGapMask([]model.Point{{X: 0, Y: 3}})
+	if findings := checkOccupancyAndCoverage(lvl, false); len(findings) != 0 {
+		t.Errorf("findings = %+v, want none for a decorative gap", findings)
+	}
+
+	result, _, err := ValidateLevel(WithStrict(context.Background(), true), &lvl, false, 1000, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed() {
+		t.Errorf("strict result = %+v, want a pass", result)
+	}
+}
+
+func TestStrictPromotesWarnings(t *testing.T) {
+	lvl := baseFullGridLevel()
+	lvl.Vines[3].OrderedPath = lvl.Vines[3].OrderedPath[:3]
+
+	result, _, err := ValidateLevel(context.Background(), &lvl, false, 1000, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed() || len(result.Warnings()) != 1 {
+		t.Fatalf("result = %+v, want a pass with one warning", result)
+	}
+
+	r := Report{Levels: []LevelResult{result}}
+	r.finish()
+	if r.Warned != 1 || r.Err() != nil {
+		t.Errorf("report warned=%d err=%v, want one warned level and no error", r.Warned, r.Err())
+	}
+	var buf bytes.Buffer
+	r.WriteText(&buf)
+	if !strings.Contains(buf.String(), "[coverage] incomplete coverage") {
+		t.Errorf("text report missing the warning:\n%s", buf.String())
+	}
+
+	result, _, err = ValidateLevel(WithStrict(context.Background(), true), &lvl, false, 1000, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passed() || !strings.Contains(result.Error, "incomplete coverage") {
+		t.Errorf("strict result = %+v, want the coverage warning as a failure", result)
+	}
+}
@@ -0,0 +1,115 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// The fuzz targets feed malformed and adversarial level files through the
+// paths the CLI runs on untrusted input. None of them may panic, and the
+// solver must stop within its state and time budgets. Run one with
+//
+//	go test ./pkg/validator -run '^$' -fuzz FuzzReadLevelFile -fuzztime 1m
+//
+// Without -fuzz they only replay the seed corpus: the shipped levels and
+// lessons, the generator's failure dumps and golden levels (which carry the
+// portals, locks and multi-head vines the assets don't yet), and any
+// reproducers saved under testdata/fuzz.
+
+const (
+	fuzzMaxStates = 2000
+	fuzzTimeout   = 2 * time.Second
+)
+
+// addLevelCorpus seeds f with every level file in the assets, the failure
+// dumps and the levelgen goldens. Missing directories are skipped, so the
+// targets still run from a partial checkout.
+func addLevelCorpus(f *testing.F) {
+	f.Helper()
+	var dirs []string
+	if dir, err := common.LevelsDir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+	if dir, err := common.LessonsDir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+	dirs = append(dirs,
+		filepath.Join("..", "..", "test", "fixtures", "failing_dumps"),
+		filepath.Join("..", "levelgen", "testdata", "golden"))
+	for _, dir := range dirs {
+		files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				f.Fatalf("read seed %s: %v", file, err)
+			}
+			f.Add(data)
+		}
+	}
+	f.Add([]byte(`{}`))
+	f.Add([]byte(`{"id":1,"grid_size":[3,3],"vines":[],"max_moves":1,"color_scheme":["#fff"]}`))
+}
+
+func FuzzReadLevelFile(f *testing.F) {
+	addLevelCorpus(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Name the file after its ID so the filename check lets the rest run.
+		var head struct {
+			ID int `json:"id"`
+		}
+		_ = json.Unmarshal(data, &head)
+		path := filepath.Join(t.TempDir(), fmt.Sprintf("level_%d.json", head.ID))
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		var result LevelResult
+		_, _ = readLevelFile(context.Background(), path, false, &result)
+	})
+}
+
+func FuzzValidateStructural(f *testing.F) {
+	addLevelCorpus(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var lvl model.Level
+		if err := json.Unmarshal(data, &lvl); err != nil {
+			return
+		}
+		_ = ValidateStructural(lvl)
+		_ = CheckRules(lvl, RuleFilter{})
+	})
+}
+
+func FuzzSolve(f *testing.F) {
+	addLevelCorpus(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var lvl model.Level
+		if err := json.Unmarshal(data, &lvl); err != nil {
+			return
+		}
+		// The CLI only solves levels that pass the structural checks.
+		if len(lvl.GridSize) != 2 || len(ValidateStructural(lvl)) > 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), fuzzTimeout)
+		defer cancel()
+		start := time.Now()
+		ok, solution, stats, err := SolveContext(ctx, lvl, fuzzMaxStates)
+		if elapsed := time.Since(start); elapsed > 2*fuzzTimeout {
+			t.Fatalf("solver ran %v past a %v timeout", elapsed, fuzzTimeout)
+		}
+		if err == nil && stats.StatesExplored > 2*fuzzMaxStates {
+			t.Errorf("solver explored %d states with a budget of %d", stats.StatesExplored, fuzzMaxStates)
+		}
+		if ok && len(solution) != len(lvl.Vines) {
+			t.Errorf("solution clears %d of %d vines", len(solution), len(lvl.Vines))
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// VerifyMirrorEquivalence checks that mirror is an exact reflection-equivalent
+// of src: same structure, same per-vine lengths and blocking relations, and the
+// same solvability outcome under the given budget.
+func VerifyMirrorEquivalence(src, mirror model.Level, maxStates int) error {
+	if src.GetGridWidth() != mirror.GetGridWidth() || src.GetGridHeight() != mirror.GetGridHeight() {
+		return fmt.Errorf("grid size differs: %v vs %v", src.GridSize, mirror.GridSize)
+	}
+	sameVine := func(a, b model.Vine) bool {
+		return a.ID == b.ID && a.Length() == b.Length() && a.ColorIndex == b.ColorIndex
+	}
+	return verifyEquivalence(src, mirror, maxStates, "mirror", sameVine)
+}
+
+// VerifyVariantEquivalence checks that variant plays like src after a
+// rotation, reflection or relabeling: the same grid up to a quarter turn,
+// vines of the same length at each index with the same blocking relations,
+// and the same solvability outcome under the given budget. IDs and colors
+// may differ.
+func VerifyVariantEquivalence(src, variant model.Level, maxStates int) error {
+	w, h := src.GetGridWidth(), src.GetGridHeight()
+	vw, vh := variant.GetGridWidth(), variant.GetGridHeight()
+	if (vw != w || vh != h) && (vw != h || vh != w) {
+		return fmt.Errorf("grid size differs: %v vs %v", src.GridSize, variant.GridSize)
+	}
+	sameVine := func(a, b model.Vine) bool { return a.Length() == b.Length() }
+	return verifyEquivalence(src, variant, maxStates, "variant", sameVine)
+}
+
+// verifyEquivalence holds the checks shared by mirrors and variants; vine i
+// of other must be sameVine as, and block like, vine i of src.
+func verifyEquivalence(src, other model.Level, maxStates int, label string, sameVine func(a, b model.Vine) bool) error {
+	if len(src.Vines) != len(other.Vines) {
+		return fmt.Errorf("vine count differs: %d vs %d", len(src.Vines), len(other.Vines))
+	}
+	if src.MinMoves != other.MinMoves || src.MaxMoves != other.MaxMoves || src.Grace != other.Grace {
+		return fmt.Errorf("move budget differs: min %d/%d max %d/%d grace %d/%d",
+			src.MinMoves, other.MinMoves, src.MaxMoves, other.MaxMoves, src.Grace, other.Grace)
+	}
+	if len(src.Portals) != len(other.Portals) {
+		return fmt.Errorf("portal count differs: %d vs %d", len(src.Portals), len(other.Portals))
+	}
+	if maskSize(src.Mask) != maskSize(other.Mask) {
+		return fmt.Errorf("mask size differs: %d vs %d", maskSize(src.Mask), maskSize(other.Mask))
+	}
+
+	for i := range src.Vines {
+		a, b := src.Vines[i], other.Vines[i]
+		if !sameVine(a, b) {
+			return fmt.Errorf("vine %d differs: %s(len %d) vs %s(len %d)", i, a.ID, a.Length(), b.ID, b.Length())
+		}
+	}
+
+	// Blocking relations must be preserved pairwise
+	for i := range src.Vines {
+		for j := range src.Vines {
+			if i == j {
+				continue
+			}
+			srcBlocks := doesVineBlockVineFast(src.Vines[i], src.Vines[j], &src)
+			otherBlocks := doesVineBlockVineFast(other.Vines[i], other.Vines[j], &other)
+			if srcBlocks != otherBlocks {
+				return fmt.Errorf("blocking relation %s->%s differs", src.Vines[i].ID, src.Vines[j].ID)
+			}
+		}
+	}
+
+	if errs := ValidateStructural(other); len(errs) > 0 {
+		return fmt.Errorf("%s failed structural validation: %v", label, errs[0])
+	}
+
+	srcOK, srcStats, _ := IsSolvable(src, maxStates)
+	otherOK, otherStats, _ := IsSolvable(other, maxStates)
+	if srcOK != otherOK || srcStats.Solver != otherStats.Solver {
+		return fmt.Errorf("solvability differs: %v (%s) vs %v (%s)", srcOK, srcStats.Solver, otherOK, otherStats.Solver)
+	}
+
+	return nil
+}
+
+func maskSize(m *model.Mask) int {
+	if m == nil {
+		return 0
+	}
+	return len(m.Points)
+}
@@ -0,0 +1,101 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func mirrorTestLevel(t *testing.T) model.Level {
+	t.Helper()
+	// A blocks B: B's head at (1,1) facing right moves into A at (2,1).
+	a, err := model.NewVine("A", []model.Point{{X: 2, Y: 2}, {X: 2, Y: 1}, {X: 2, Y: 0}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := model.NewVine("B", []model.Point{{X: 1, Y: 1}, {X: 0, Y: 1}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return model.Level{
+		ID:       1,
+		Name:     "Mirror Source",
+		GridSize: []int{4, 3},
+		Vines:    []model.Vine{a, b},
+		MaxMoves: 4,
+		MinMoves: 2,
+		Mask:     &model.Mask{Mode: "hide", Points: []model.Point{{X: 3, Y: 0}}},
+	}
+}
+
+func TestMirrorLevelEquivalence(t *testing.T) {
+	src := mirrorTestLevel(t)
+
+	for _, axis := range []string{common.MirrorHorizontal, common.MirrorVertical} {
+		t.Run(axis, func(t *testing.T) {
+			mirror, err := common.MirrorLevel(&src, 1001, axis)
+			if err != nil {
+				t.Fatalf("MirrorLevel: %v", err)
+			}
+			if mirror.MirrorOf != src.ID || mirror.ID != 1001 || mirror.MirrorAxis != axis {
+				t.Fatalf("unexpected mirror metadata: id=%d of=%d axis=%s", mirror.ID, mirror.MirrorOf, mirror.MirrorAxis)
+			}
+			if err := VerifyMirrorEquivalence(src, mirror, 10000); err != nil {
+				t.Fatalf("expected equivalence, got %v", err)
+			}
+
+			back, err := common.MirrorLevel(&mirror, src.ID, axis)
+			if err != nil {
+				t.Fatalf("MirrorLevel back: %v", err)
+			}
+			if !reflect.DeepEqual(back.Vines, src.Vines) || !reflect.DeepEqual(back.Mask, src.Mask) {
+				t.Fatalf("mirroring twice should restore the source level")
+			}
+		})
+	}
+}
+
+func TestVerifyMirrorEquivalenceDetectsDifference(t *testing.T) {
+	src := mirrorTestLevel(t)
+	mirror, err := common.MirrorLevel(&src, 1001, common.MirrorHorizontal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mirror.Vines = mirror.Vines[:1]
+	if err := VerifyMirrorEquivalence(src, mirror, 10000); err == nil {
+		t.Fatal("expected vine count mismatch to be reported")
+	}
+}
+
+func TestRotateLevelEquivalence(t *testing.T) {
+	src := mirrorTestLevel(t)
+
+	for turns := 1; turns <= 3; turns++ {
+		rotated, err := common.RotateLevel(&src, turns)
+		if err != nil {
+			t.Fatalf("RotateLevel(%d): %v", turns, err)
+		}
+		if turns%2 == 1 && (rotated.GetGridWidth() != 3 || rotated.GetGridHeight() != 4) {
+			t.Fatalf("quarter turn grid = %v, want [3 4]", rotated.GridSize)
+		}
+		if err := VerifyVariantEquivalence(src, rotated, 10000); err != nil {
+			t.Fatalf("%d turns: expected equivalence, got %v", turns, err)
+		}
+
+		back, err := common.RotateLevel(&rotated, 4-turns)
+		if err != nil {
+			t.Fatalf("RotateLevel back: %v", err)
+		}
+		if !reflect.DeepEqual(back.Vines, src.Vines) || !reflect.DeepEqual(back.Mask, src.Mask) {
+			t.Fatalf("%d turns and back should restore the source level", turns)
+		}
+	}
+
+	// A quarter turn is not a mirror
+	rotated, _ := common.RotateLevel(&src, 1)
+	if err := VerifyMirrorEquivalence(src, rotated, 10000); err == nil {
+		t.Fatal("expected a rotated grid to fail mirror equivalence")
+	}
+}
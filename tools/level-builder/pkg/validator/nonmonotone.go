@@ -0,0 +1,317 @@
+package validator
+
+import (
+	"context"
+	"encoding/binary"
+	"slices"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// Mechanics toggles game mechanics that break the exact solver's monotone
+// clearing assumption (cleared vines never return and remaining vines never
+// change shape). With no mechanics enabled the regular solvers are used.
+type Mechanics struct {
+	// GrowingVines extends each remaining vine's tail by one cell (straight
+	// on, if free) after every move.
+	GrowingVines bool
+	// Reveals maps vine IDs to the move count after which they appear
+	// (staged reveals). Vines not listed are present from the start.
+	Reveals map[string]int
+	// PartialMoves lets a tapped vine that is blocked part way advance up to
+	// its blocker and stay there, instead of bumping back to where it was.
+	// Each such tap costs a move without clearing anything.
+	PartialMoves bool
+}
+
+// Monotone reports whether the mechanics preserve the monotone clearing
+// assumption, allowing the vine-subset mask solvers to be used.
+func (m Mechanics) Monotone() bool {
+	return !m.GrowingVines && len(m.Reveals) == 0 && !m.PartialMoves
+}
+
+type mechanicsKey struct{}
+
+// WithMechanics returns a context under which ValidateReport and ValidateFile check
+// solvability under mech, so levels are held to the rules the game actually plays by.
+// Results under non-monotone mechanics bypass the validation cache.
+func WithMechanics(ctx context.Context, mech Mechanics) context.Context {
+	return context.WithValue(ctx, mechanicsKey{}, mech)
+}
+
+func mechanicsFrom(ctx context.Context) Mechanics {
+	mech, _ := ctx.Value(mechanicsKey{}).(Mechanics)
+	return mech
+}
+
+// IsSolvableWithMechanics checks solvability under the given mechanics. Monotone
+// mechanics delegate to IsSolvable; otherwise a BFS over full board states
+// (vine geometry, presence and pending reveals) is used.
+func IsSolvableWithMechanics(lvl model.Level, maxStates int, mech Mechanics) (bool, SolvabilityStats, error) {
+	return IsSolvableWithMechanicsContext(context.Background(), lvl, maxStates, mech)
+}
+
+// IsSolvableWithMechanicsContext is IsSolvableWithMechanics with cancellation.
+func IsSolvableWithMechanicsContext(ctx context.Context, lvl model.Level, maxStates int, mech Mechanics) (bool, SolvabilityStats, error) {
+	ok, _, stats, err := solveWithMechanics(ctx, lvl, maxStates, mech)
+	return ok, stats, err
+}
+
+// solveWithMechanics is IsSolvableWithMechanicsContext that also returns the
+// fewest moves that solve the level.
+func solveWithMechanics(ctx context.Context, lvl model.Level, maxStates int, mech Mechanics) (bool, int, SolvabilityStats, error) {
+	if mech.Monotone() {
+		ok, stats, err := IsSolvableContext(ctx, lvl, maxStates)
+		return ok, len(lvl.Vines), stats, err
+	}
+	if mech.PartialMoves && !mech.GrowingVines && len(mech.Reveals) == 0 {
+		// Every vine takes at least one tap, so a plain clear order is already
+		// the shortest solution; only search partial moves without one.
+		if ok, stats, err := IsSolvableContext(ctx, lvl, maxStates); ok || err != nil {
+			return ok, len(lvl.Vines), stats, err
+		}
+	}
+	ok, states, moves := isSolvableFullStateWithStats(ctx, lvl, maxStates, mech)
+	stats := SolvabilityStats{Solver: "full-state", StatesExplored: states, GaveUp: !ok && states >= maxStates}
+	if !ok && ctx.Err() != nil {
+		stats.GaveUp = true
+		return false, 0, stats, ctx.Err()
+	}
+	return ok, moves, stats, nil
+}
+
+// boardState is a full snapshot of the board. paths[i] holds vine i's cell
+// indices, or nil when the vine is cleared or not yet revealed.
+type boardState struct {
+	paths   [][]int
+	cleared []bool
+	moves   int
+}
+
+// isSolvableFullStateWithStats runs BFS over hashed board states so that
+// transitions may add, move or grow vines. It returns whether the level is
+// solvable, the states explored and, when solvable, the fewest moves needed.
+func isSolvableFullStateWithStats(ctx context.Context, lvl model.Level, maxStates int, mech Mechanics) (bool, int, int) {
+	w, h := lvl.GridSize[0], lvl.GridSize[1]
+	vineCount := len(lvl.Vines)
+
+	// Reveals and locks only matter until the last one fires; cap the move
+	// counter so otherwise identical boards hash equally afterwards.
+	lastReveal := 0
+	for _, at := range mech.Reveals {
+		if at > lastReveal {
+			lastReveal = at
+		}
+	}
+	for _, v := range lvl.Vines {
+		if v.LockedUntil > lastReveal {
+			lastReveal = v.LockedUntil
+		}
+	}
+
+	origin := make([][]int, vineCount)
+	for i, v := range lvl.Vines {
+		origin[i] = make([]int, len(v.OrderedPath))
+		for j, p := range v.OrderedPath {
+			origin[i][j] = p.Y*w + p.X
+		}
+	}
+
+	start := boardState{paths: make([][]int, vineCount), cleared: make([]bool, vineCount)}
+	for i, v := range lvl.Vines {
+		if mech.Reveals[v.ID] <= 0 {
+			start.paths[i] = origin[i]
+		}
+	}
+
+	visited := map[string]bool{start.key(lastReveal): true}
+	queue := []boardState{start}
+	states := 0
+	occupied := make([]bool, w*h)
+
+	for len(queue) > 0 {
+		if states >= maxStates || cancelled(ctx, states) {
+			return false, states, 0
+		}
+		state := queue[0]
+		queue = queue[1:]
+		states++
+
+		if state.solved() {
+			return true, states, state.moves
+		}
+
+		state.fillOccupancy(occupied)
+		for i := 0; i < vineCount; i++ {
+			if state.paths[i] == nil || lvl.Vines[i].IsLocked(state.clears()) {
+				continue
+			}
+			for _, path := range vineMoves(lvl, i, occupied, state.paths[i], mech.PartialMoves) {
+				next, ok := state.move(i, path, lvl, origin, mech, w, h)
+				if !ok {
+					continue
+				}
+				key := next.key(lastReveal)
+				if !visited[key] {
+					visited[key] = true
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+
+	return false, states, 0
+}
+
+// vineMoves returns where a tap can take vine i: nil when it clears, or its new
+// cells when partial moves are on and it advances part way to a blocker. A
+// multi-head vine is tried from both ends.
+func vineMoves(lvl model.Level, i int, occupied []bool, path []int, partial bool) [][]int {
+	v := lvl.Vines[i]
+	if canVineClearFast(lvl, i, occupied, path) {
+		return [][]int{nil}
+	}
+	if !partial {
+		return nil
+	}
+	var moves [][]int
+	if moved, ok := advanceToBlocker(lvl, v.HeadDirection, occupied, path); ok {
+		moves = append(moves, moved)
+	}
+	if v.IsMultiHead() {
+		reversed := slices.Clone(path)
+		slices.Reverse(reversed)
+		if moved, ok := advanceToBlocker(lvl, v.TailDirection, occupied, reversed); ok {
+			slices.Reverse(moved)
+			moves = append(moves, moved)
+		}
+	}
+	return moves
+}
+
+// advanceToBlocker slides a vine whose cells (leading head first) are path in
+// dir, as canSlideOut does, and returns its cells once another vine stops it.
+// ok is false when it cannot move at all, or when nothing stops it because it
+// leaves the grid or portals send it round in a loop.
+func advanceToBlocker(lvl model.Level, dir string, occupied []bool, path []int) (moved []int, ok bool) {
+	w := lvl.GridSize[0]
+	positions := slices.Clone(path)
+	steps, blocked := 0, false
+	lvl.ExitRay(model.Point{X: positions[0] % w, Y: positions[0] / w}, dir, func(p model.Point) bool {
+		next := p.Y*w + p.X
+		if occupied[next] && !slices.Contains(positions, next) {
+			blocked = true
+			return false
+		}
+		copy(positions[1:], positions[:len(positions)-1])
+		positions[0] = next
+		steps++
+		return true
+	})
+	return positions, blocked && steps > 0
+}
+
+// move takes vine i to path, or clears it when path is nil, advances the move
+// counter and applies mechanics. Returns false when the resulting board is
+// invalid (a reveal overlaps a vine).
+func (s boardState) move(i int, path []int, lvl model.Level, origin [][]int, mech Mechanics, w, h int) (boardState, bool) {
+	next := boardState{
+		paths:   make([][]int, len(s.paths)),
+		cleared: make([]bool, len(s.cleared)),
+		moves:   s.moves + 1,
+	}
+	copy(next.paths, s.paths)
+	copy(next.cleared, s.cleared)
+	next.paths[i] = path
+	next.cleared[i] = path == nil
+
+	occupied := make([]bool, w*h)
+	next.fillOccupancy(occupied)
+
+	for j, v := range lvl.Vines {
+		if at, ok := mech.Reveals[v.ID]; ok && at == next.moves && !next.cleared[j] {
+			for _, idx := range origin[j] {
+				if occupied[idx] {
+					return boardState{}, false
+				}
+				occupied[idx] = true
+			}
+			next.paths[j] = origin[j]
+		}
+	}
+
+	if mech.GrowingVines {
+		for j, path := range next.paths {
+			if len(path) < 2 {
+				continue
+			}
+			tail, prev := path[len(path)-1], path[len(path)-2]
+			tx, ty := tail%w, tail/w
+			nx, ny := tx+(tx-prev%w), ty+(ty-prev/w)
+			if nx < 0 || nx >= w || ny < 0 || ny >= h || occupied[ny*w+nx] || lvl.Mask.IsMasked(nx, ny) {
+				continue
+			}
+			grown := make([]int, len(path)+1)
+			copy(grown, path)
+			grown[len(path)] = ny*w + nx
+			next.paths[j] = grown
+			occupied[ny*w+nx] = true
+		}
+	}
+
+	return next, true
+}
+
+func (s boardState) solved() bool {
+	for _, c := range s.cleared {
+		if !c {
+			return false
+		}
+	}
+	return true
+}
+
+// clears counts the vines that have left the board, which is what locks wait on.
+func (s boardState) clears() int {
+	n := 0
+	for _, c := range s.cleared {
+		if c {
+			n++
+		}
+	}
+	return n
+}
+
+func (s boardState) fillOccupancy(occupied []bool) {
+	for i := range occupied {
+		occupied[i] = false
+	}
+	for _, path := range s.paths {
+		for _, idx := range path {
+			occupied[idx] = true
+		}
+	}
+}
+
+// key encodes the full board: capped move counter plus every vine's cells.
+func (s boardState) key(lastReveal int) string {
+	moves := s.moves
+	if moves > lastReveal {
+		moves = lastReveal
+	}
+	buf := binary.AppendUvarint(nil, uint64(moves))
+	for i, path := range s.paths {
+		switch {
+		case s.cleared[i]:
+			buf = append(buf, 0)
+		case path == nil:
+			buf = append(buf, 1)
+		default:
+			buf = binary.AppendUvarint(append(buf, 2), uint64(len(path)))
+			for _, idx := range path {
+				buf = binary.AppendUvarint(buf, uint64(idx))
+			}
+		}
+	}
+	return string(buf)
+}
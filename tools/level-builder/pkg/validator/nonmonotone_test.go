@@ -0,0 +1,113 @@
+package validator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func mustVine(t *testing.T, id string, path ...model.Point) model.Vine {
+	t.Helper()
+	v, err := model.NewVine(id, path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestIsSolvableWithMechanicsMonotoneDelegates(t *testing.T) {
+	lvl := model.Level{
+		GridSize: []int{3, 3},
+		Vines: []model.Vine{
+			mustVine(t, "A", model.Point{X: 0, Y: 0}, model.Point{X: 1, Y: 0}),
+		},
+	}
+	ok, stats, err := IsSolvableWithMechanics(lvl, 1000, Mechanics{})
+	if err != nil || !ok {
+		t.Fatalf("expected solvable, got ok=%v err=%v", ok, err)
+	}
+	if stats.Solver == "full-state" {
+		t.Fatalf("monotone mechanics should not use the full-state solver")
+	}
+}
+
+func TestIsSolvableWithMechanicsStagedReveal(t *testing.T) {
+	// A exits left; B exits right. Z is revealed after the first move on top
+	// of B's neck, so B must be cleared first.
+	a := mustVine(t, "A", model.Point{X: 0, Y: 0}, model.Point{X: 1, Y: 0})
+	b := mustVine(t, "B", model.Point{X: 3, Y: 2}, model.Point{X: 2, Y: 2})
+	z := mustVine(t, "Z", model.Point{X: 2, Y: 2}, model.Point{X: 2, Y: 1})
+	lvl := model.Level{GridSize: []int{4, 3}, Vines: []model.Vine{a, b, z}}
+
+	ok, stats, err := IsSolvableWithMechanics(lvl, 1000, Mechanics{Reveals: map[string]int{"Z": 1}})
+	if err != nil || !ok {
+		t.Fatalf("expected solvable by clearing B first, got ok=%v err=%v", ok, err)
+	}
+	if stats.Solver != "full-state" {
+		t.Fatalf("expected full-state solver, got %s", stats.Solver)
+	}
+
+	// Now B is blocked by A, so A must move first and the reveal always collides.
+	b = mustVine(t, "B", model.Point{X: 1, Y: 1}, model.Point{X: 1, Y: 2})
+	z = mustVine(t, "Z", model.Point{X: 1, Y: 2}, model.Point{X: 0, Y: 2})
+	lvl.Vines = []model.Vine{a, b, z}
+	ok, _, _ = IsSolvableWithMechanics(lvl, 1000, Mechanics{Reveals: map[string]int{"Z": 1}})
+	if ok {
+		t.Fatal("expected unsolvable when every order triggers an overlapping reveal")
+	}
+}
+
+func TestIsSolvableWithMechanicsGrowingVines(t *testing.T) {
+	// C clears first; A then grows its tail into (2,2), B's only exit, but A
+	// can still leave to the left and free B.
+	a := mustVine(t, "A", model.Point{X: 0, Y: 2}, model.Point{X: 1, Y: 2})
+	b := mustVine(t, "B", model.Point{X: 2, Y: 1}, model.Point{X: 2, Y: 0})
+	c := mustVine(t, "C", model.Point{X: 0, Y: 0}, model.Point{X: 1, Y: 0})
+	lvl := model.Level{GridSize: []int{3, 3}, Vines: []model.Vine{a, b, c}}
+
+	ok, stats, err := IsSolvableWithMechanics(lvl, 1000, Mechanics{GrowingVines: true})
+	if err != nil || !ok {
+		t.Fatalf("expected solvable, got ok=%v err=%v", ok, err)
+	}
+	if stats.StatesExplored == 0 {
+		t.Fatal("expected explored states to be recorded")
+	}
+}
+
+func TestIsSolvableWithMechanicsPartialMoves(t *testing.T) {
+	// A exits right but stops at B's tail; B exits down through A's tail. Neither
+	// clears first, but A can advance one cell, let B out and then leave.
+	a := mustVine(t, "A", model.Point{X: 2, Y: 0}, model.Point{X: 1, Y: 0}, model.Point{X: 0, Y: 0})
+	b := mustVine(t, "B",
+		model.Point{X: 0, Y: 1}, model.Point{X: 0, Y: 2}, model.Point{X: 1, Y: 2}, model.Point{X: 2, Y: 2},
+		model.Point{X: 3, Y: 2}, model.Point{X: 4, Y: 2}, model.Point{X: 4, Y: 1}, model.Point{X: 4, Y: 0})
+	lvl := model.Level{GridSize: []int{6, 3}, Vines: []model.Vine{a, b}}
+
+	if ok, _, _ := IsSolvableWithMechanics(lvl, 1000, Mechanics{}); ok {
+		t.Fatal("expected unsolvable when blocked vines bump back")
+	}
+	ok, moves, stats, err := solveWithMechanics(context.Background(), lvl, 1000, Mechanics{PartialMoves: true})
+	if err != nil || !ok || stats.Solver != "full-state" {
+		t.Fatalf("expected the full-state solver to solve it, got ok=%v stats=%+v err=%v", ok, stats, err)
+	}
+	if moves != 3 {
+		t.Errorf("moves = %d, want 3 (advance A, clear B, clear A)", moves)
+	}
+
+	ctx := WithMechanics(context.Background(), Mechanics{PartialMoves: true})
+	if ok, stat, err := IsSolvableWithStatsContext(ctx, lvl, 1000, true, DefaultAStarWeight); err != nil || !ok || stat.SolutionLength != 3 {
+		t.Errorf("IsSolvableWithStatsContext = %v, %+v, %v; want a 3 move solution", ok, stat, err)
+	}
+}
+
+func TestPartialMovesKeepPlainSolutions(t *testing.T) {
+	a := mustVine(t, "A", model.Point{X: 0, Y: 0}, model.Point{X: 1, Y: 0})
+	b := mustVine(t, "B", model.Point{X: 2, Y: 1}, model.Point{X: 2, Y: 0})
+	lvl := model.Level{GridSize: []int{3, 3}, Vines: []model.Vine{a, b}}
+
+	ok, moves, stats, err := solveWithMechanics(context.Background(), lvl, 1000, Mechanics{PartialMoves: true})
+	if err != nil || !ok || moves != 2 || stats.Solver == "full-state" {
+		t.Fatalf("solveWithMechanics = %v, %d, %+v, %v; want the plain solvers' 2 move solution", ok, moves, stats, err)
+	}
+}
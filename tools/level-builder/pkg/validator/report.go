@@ -0,0 +1,536 @@
+package validator
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+)
+
+// Report formats accepted by WriteReport.
+const (
+	FormatText     = "text"
+	FormatJSON     = "json"
+	FormatJUnit    = "junit"
+	FormatMarkdown = "markdown"
+)
+
+// ReportFormats lists the supported report formats.
+var ReportFormats = []string{FormatText, FormatJSON, FormatJUnit, FormatMarkdown}
+
+// CheckReportFormat returns an error unless format is one of ReportFormats.
+func CheckReportFormat(format string) error {
+	for _, f := range ReportFormats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown report format %q (want one of %s)", format, strings.Join(ReportFormats, ", "))
+}
+
+// LevelResult is the validation outcome for one level file.
+type LevelResult struct {
+	File        string             `json:"file"`
+	LevelID     int                `json:"level_id,omitempty"`
+	Error       string             `json:"error,omitempty"`       // structural or parse error
+	Solvability *LevelStat         `json:"solvability,omitempty"` // nil unless solvability was checked
+	Deadlock    *Deadlock          `json:"deadlock,omitempty"`    // why an unsolvable level is stuck, when proven
+	Skipped     bool               `json:"skipped,omitempty"`     // solvability not checked: the total timeout ran out first
+	Findings    []ValidationResult `json:"findings,omitempty"`    // occupancy and coverage findings
+	Rules       []RuleResult       `json:"rules,omitempty"`       // structural rules that ran, nil when the level failed to decode
+}
+
+// Warnings returns the level's warnings from every check that ran.
+func (r LevelResult) Warnings() []ValidationResult {
+	return r.withSeverity(SeverityWarning)
+}
+
+// withSeverity returns the findings of every check that ran with the given severity.
+func (r LevelResult) withSeverity(severity Severity) []ValidationResult {
+	var out []ValidationResult
+	collect := func(findings []ValidationResult) {
+		for _, f := range findings {
+			if f.Severity == severity {
+				out = append(out, f)
+			}
+		}
+	}
+	collect(r.Findings)
+	for _, rule := range r.Rules {
+		collect(rule.Findings)
+	}
+	return out
+}
+
+// FailedRules returns the IDs of the structural rules the level failed.
+func (r LevelResult) FailedRules() []string {
+	var ids []string
+	for _, rule := range r.Rules {
+		if rule.Failed() {
+			ids = append(ids, rule.Rule)
+		}
+	}
+	return ids
+}
+
+// Passed reports whether the level passed every check that was run.
+func (r LevelResult) Passed() bool {
+	return r.Error == "" && (r.Solvability == nil || r.Solvability.Solvable)
+}
+
+// Unsolvable reports whether the level is structurally valid but failed the solvability check.
+func (r LevelResult) Unsolvable() bool {
+	return r.Error == "" && r.Solvability != nil && !r.Solvability.Solvable
+}
+
+// WriteText prints a one-line pass or fail status for the level, followed by its deadlock
+// when one was found and its warnings.
+func (r LevelResult) WriteText(w io.Writer) {
+	defer r.writeWarnings(w, "   ")
+	name := r.File
+	if r.LevelID != 0 {
+		name = fmt.Sprintf("%s (level %d)", r.File, r.LevelID)
+	}
+	s := r.Solvability
+	switch {
+	case r.Error != "":
+		_, _ = fmt.Fprintf(w, "❌ %s%s: %s\n", name, ruleSuffix(r), r.Error)
+	case r.Unsolvable():
+		_, _ = fmt.Fprintf(w, "❌ %s: not solvable (solver=%s states=%d gave_up=%v)\n", name, s.Solver, s.StatesExplored, s.GaveUp)
+		if s.TimedOut || s.OverBudget {
+			_, _ = fmt.Fprintf(w, "   %s\n", s.Error)
+		}
+		if r.Deadlock != nil {
+			r.Deadlock.writeText(w)
+		}
+	case r.Skipped:
+		_, _ = fmt.Fprintf(w, "⏭ %s: valid, solvability skipped (total timeout)\n", name)
+	case s != nil:
+		_, _ = fmt.Fprintf(w, "✓ %s: solvable (solver=%s states=%d time=%dms)\n", name, s.Solver, s.StatesExplored, s.TimeMs)
+	default:
+		_, _ = fmt.Fprintf(w, "✓ %s: valid\n", name)
+	}
+}
+
+// writeWarnings prints the level's warnings, and in verbose mode its info findings, one per
+// line after indent.
+func (r LevelResult) writeWarnings(w io.Writer, indent string) {
+	for _, f := range r.Warnings() {
+		_, _ = fmt.Fprintf(w, "%s⚠️ [%s] %s\n", indent, f.Check, f.Error())
+	}
+	if common.VerboseEnabled {
+		for _, f := range r.withSeverity(SeverityInfo) {
+			_, _ = fmt.Fprintf(w, "%sℹ️ [%s] %s\n", indent, f.Check, f.Error())
+		}
+	}
+}
+
+// Report collects the results of a validation run.
+type Report struct {
+	CheckSolvable bool   `json:"check_solvable"`
+	Strict        bool   `json:"strict,omitempty"` // warnings were promoted to errors
+	ModuleError   string `json:"module_error,omitempty"`
+	Total         int    `json:"total"`
+	Passed        int    `json:"passed"`
+	Structural    int    `json:"failed_structural"`
+	Unsolvable    int    `json:"failed_solvability"`
+	TimedOut      int    `json:"timed_out,omitempty"`           // unsolvable levels stopped by a timeout
+	Skipped       int    `json:"skipped_solvability,omitempty"` // levels the total timeout left unsolved
+	Warned        int    `json:"warned"`                        // levels with warnings, failed or not
+	// BudgetExhausted is set when the total timeout ran out before every level was solved.
+	BudgetExhausted bool          `json:"budget_exhausted,omitempty"`
+	StatsPath       string        `json:"stats_path,omitempty"`
+	Levels          []LevelResult `json:"levels"`
+}
+
+// finish orders the levels by file name and fills in the summary counts.
+func (r *Report) finish() {
+	sort.Slice(r.Levels, func(i, j int) bool {
+		return levelFileLess(r.Levels[i].File, r.Levels[j].File)
+	})
+	r.Total, r.Passed, r.Structural, r.Unsolvable = len(r.Levels), 0, 0, 0
+	r.TimedOut, r.Skipped, r.Warned = 0, 0, 0
+	for _, l := range r.Levels {
+		if len(l.Warnings()) > 0 {
+			r.Warned++
+		}
+		switch {
+		case l.Error != "":
+			r.Structural++
+		case l.Unsolvable():
+			r.Unsolvable++
+			if l.Solvability.TimedOut {
+				r.TimedOut++
+			}
+		case l.Skipped:
+			r.Skipped++
+		default:
+			r.Passed++
+		}
+	}
+}
+
+// Failed returns the number of levels that failed any check.
+func (r Report) Failed() int {
+	return r.Structural + r.Unsolvable
+}
+
+// Err returns a non-nil error when modules or any level failed validation, or when the total
+// timeout left levels unchecked.
+func (r Report) Err() error {
+	if r.ModuleError != "" {
+		return fmt.Errorf("module validation failed: %s", r.ModuleError)
+	}
+	if n := r.Failed(); n > 0 {
+		return fmt.Errorf("%d levels failed validation", n)
+	}
+	if r.Skipped > 0 {
+		return fmt.Errorf("total timeout reached: solvability of %d levels not checked", r.Skipped)
+	}
+	return nil
+}
+
+// WriteReport writes the report to w in the given format.
+func WriteReport(w io.Writer, r Report, format string) error {
+	switch format {
+	case FormatText:
+		r.WriteText(w)
+		return nil
+	case FormatJSON:
+		return r.WriteJSON(w)
+	case FormatJUnit:
+		return r.WriteJUnit(w)
+	case FormatMarkdown:
+		r.WriteMarkdown(w)
+		return nil
+	default:
+		return CheckReportFormat(format)
+	}
+}
+
+// WriteText prints the per-level solver lines and the failure summary for a terminal.
+func (r Report) WriteText(w io.Writer) {
+	if r.ModuleError != "" {
+		_, _ = fmt.Fprintf(w, "\n❌ Module validation failed: %s\n", r.ModuleError)
+		return
+	}
+
+	for _, l := range r.Levels {
+		if s := l.Solvability; s != nil {
+			_, _ = fmt.Fprintf(w, "Level %d (%s): solvable=%v solver=%s states=%d time=%dms gave_up=%v\n",
+				s.LevelID, l.File, s.Solvable, s.Solver, s.StatesExplored, s.TimeMs, s.GaveUp)
+		}
+	}
+	if r.StatsPath != "" {
+		_, _ = fmt.Fprintf(w, "\n✓ Detailed results written to %s\n", r.StatsPath)
+	}
+
+	if r.Structural > 0 {
+		title := "Validation failed"
+		if r.CheckSolvable {
+			title = "Structural validation failed"
+		}
+		_, _ = fmt.Fprintf(w, "\n❌ %s for %d levels:\n\n", title, r.Structural)
+		for _, l := range r.Levels {
+			if l.Error != "" {
+				_, _ = fmt.Fprintf(w, "  • %s%s: %s\n", l.File, ruleSuffix(l), l.Error)
+			}
+		}
+	}
+
+	if r.Unsolvable > 0 {
+		_, _ = fmt.Fprintf(w, "\n❌ Solvability check failed for %d levels:\n\n", r.Unsolvable)
+		for _, l := range r.Levels {
+			if l.Unsolvable() {
+				_, _ = fmt.Fprintf(w, "  • %s (level %d): gave_up=%v states=%d\n",
+					l.File, l.LevelID, l.Solvability.GaveUp, l.Solvability.StatesExplored)
+				if l.Solvability.TimedOut || l.Solvability.OverBudget {
+					_, _ = fmt.Fprintf(w, "    %s\n", l.Solvability.Error)
+				}
+				if l.Deadlock != nil {
+					l.Deadlock.writeText(w)
+				}
+			}
+		}
+	}
+
+	if r.Skipped > 0 {
+		_, _ = fmt.Fprintf(w, "\n⏭ Total timeout reached; solvability not checked for %d levels:\n\n", r.Skipped)
+		for _, l := range r.Levels {
+			if l.Skipped && l.Error == "" {
+				_, _ = fmt.Fprintf(w, "  • %s\n", l.File)
+			}
+		}
+	}
+
+	if r.Warned > 0 {
+		_, _ = fmt.Fprintf(w, "\n⚠️ Warnings for %d levels:\n\n", r.Warned)
+		for _, l := range r.Levels {
+			if len(l.Warnings()) > 0 {
+				_, _ = fmt.Fprintf(w, "  • %s\n", l.File)
+				l.writeWarnings(w, "    ")
+			}
+		}
+	}
+
+	switch {
+	case r.Failed() == 0 && r.Skipped == 0:
+		_, _ = fmt.Fprintf(w, "\n✓ All %d levels and modules validated successfully.\n", r.Total)
+	case r.Failed() == 0:
+		_, _ = fmt.Fprintf(w, "\n📊 Summary: %d passed, %d skipped by the total timeout (total %d levels)\n",
+			r.Passed, r.Skipped, r.Total)
+	case r.CheckSolvable && r.Skipped > 0:
+		_, _ = fmt.Fprintf(w, "\n📊 Summary: %d passed, %d failed structural validation, %d failed solvability (%d timed out), %d skipped (total %d levels)\n",
+			r.Passed, r.Structural, r.Unsolvable, r.TimedOut, r.Skipped, r.Total)
+	case r.CheckSolvable:
+		_, _ = fmt.Fprintf(w, "\n📊 Summary: %d passed, %d failed structural validation, %d failed solvability (total %d levels)\n",
+			r.Passed, r.Structural, r.Unsolvable, r.Total)
+	default:
+		_, _ = fmt.Fprintf(w, "\nTotal: %d/%d levels passed validation\n", r.Passed, r.Total)
+	}
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"` // warnings, which JUnit has no element for
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report as JUnit XML: one test case per level file, plus one for
+// modules.json, so CI can fail the build on unsolvable or malformed levels.
+func (r Report) WriteJUnit(w io.Writer) error {
+	modules := junitCase{Name: "modules.json", ClassName: "modules", Time: "0.000"}
+	if r.ModuleError != "" {
+		modules.Failure = &junitFailure{Message: r.ModuleError, Type: "module"}
+	}
+	suites := junitSuites{
+		Name: "level-builder validate",
+		Suites: []junitSuite{
+			{Name: "modules", Cases: []junitCase{modules}},
+			{Name: "levels"},
+		},
+	}
+
+	levels := &suites.Suites[1]
+	var totalMs int64
+	for _, l := range r.Levels {
+		c := junitCase{Name: l.File, ClassName: "levels", Time: "0.000"}
+		switch {
+		case l.Error != "":
+			c.Failure = &junitFailure{Message: l.Error, Type: "structural", Text: strings.Join(l.FailedRules(), ", ")}
+		case l.Unsolvable():
+			s := l.Solvability
+			msg := "level is not solvable"
+			switch {
+			case s.TimedOut, s.OverBudget:
+				msg = s.Error
+			case s.GaveUp:
+				msg = fmt.Sprintf("solver gave up after %d states", s.StatesExplored)
+			}
+			c.Failure = &junitFailure{
+				Message: msg,
+				Type:    "unsolvable",
+				Text: fmt.Sprintf("level=%d solver=%s states=%d max_states=%d gave_up=%v %s",
+					s.LevelID, s.Solver, s.StatesExplored, s.MaxStates, s.GaveUp, s.Error),
+			}
+			if l.Deadlock != nil {
+				c.Failure.Message = l.Deadlock.summary()
+				var text strings.Builder
+				l.Deadlock.writeText(&text)
+				c.Failure.Text += "\n" + text.String()
+			}
+		case l.Skipped:
+			c.Skipped = &junitSkipped{Message: "solvability not checked: total timeout reached"}
+		}
+		if s := l.Solvability; s != nil {
+			c.Time = junitSeconds(s.TimeMs)
+			totalMs += s.TimeMs
+		}
+		for _, f := range l.Warnings() {
+			c.SystemOut += fmt.Sprintf("warning [%s] %s\n", f.Check, f.Error())
+		}
+		levels.Cases = append(levels.Cases, c)
+	}
+	levels.Time = junitSeconds(totalMs)
+	suites.Suites[0].Time = "0.000"
+
+	for i := range suites.Suites {
+		s := &suites.Suites[i]
+		s.Tests = len(s.Cases)
+		for _, c := range s.Cases {
+			if c.Failure != nil {
+				s.Failures++
+			}
+		}
+		suites.Tests += s.Tests
+		suites.Failures += s.Failures
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func junitSeconds(ms int64) string {
+	return fmt.Sprintf("%.3f", float64(ms)/1000)
+}
+
+// WriteMarkdown writes a summary suitable for pasting into a pull request: a headline,
+// a table of failures and, when solvability was checked, the solver stats per level.
+func (r Report) WriteMarkdown(w io.Writer) {
+	_, _ = fmt.Fprintln(w, "## Level validation")
+	_, _ = fmt.Fprintln(w)
+
+	if r.ModuleError != "" {
+		_, _ = fmt.Fprintf(w, "❌ **Module validation failed:** %s\n", markdownCell(r.ModuleError))
+		return
+	}
+	if r.Failed() == 0 {
+		_, _ = fmt.Fprintf(w, "✅ **All %d levels passed**", r.Total)
+	} else {
+		_, _ = fmt.Fprintf(w, "❌ **%d of %d levels failed** (%d structural, %d solvability)",
+			r.Failed(), r.Total, r.Structural, r.Unsolvable)
+	}
+	if r.Skipped > 0 {
+		_, _ = fmt.Fprintf(w, ", ⏭ %d skipped by the total timeout", r.Skipped)
+	}
+	if r.Warned > 0 {
+		_, _ = fmt.Fprintf(w, ", ⚠️ %d with warnings", r.Warned)
+	}
+	if r.CheckSolvable {
+		_, _ = fmt.Fprint(w, " — structure and solvability checked")
+	} else {
+		_, _ = fmt.Fprint(w, " — structure checked")
+	}
+	_, _ = fmt.Fprintln(w)
+
+	if r.Failed() > 0 {
+		_, _ = fmt.Fprintln(w)
+		_, _ = fmt.Fprintln(w, "| File | Level | Check | Details |")
+		_, _ = fmt.Fprintln(w, "| --- | --- | --- | --- |")
+		for _, l := range r.Levels {
+			switch {
+			case l.Error != "":
+				_, _ = fmt.Fprintf(w, "| %s | %s | structural%s | %s |\n", l.File, markdownLevelID(l.LevelID), ruleSuffix(l), markdownCell(l.Error))
+			case l.Unsolvable():
+				s := l.Solvability
+				details := fmt.Sprintf("solver=%s states=%d gave_up=%v", s.Solver, s.StatesExplored, s.GaveUp)
+				if s.TimedOut || s.OverBudget {
+					details += "; " + s.Error
+				}
+				if l.Deadlock != nil {
+					details += "; " + l.Deadlock.summary()
+				}
+				_, _ = fmt.Fprintf(w, "| %s | %d | solvability | %s |\n", l.File, l.LevelID, markdownCell(details))
+			}
+		}
+	}
+
+	if r.Warned > 0 {
+		_, _ = fmt.Fprintln(w)
+		_, _ = fmt.Fprintln(w, "| File | Level | Warning | Details |")
+		_, _ = fmt.Fprintln(w, "| --- | --- | --- | --- |")
+		for _, l := range r.Levels {
+			for _, f := range l.Warnings() {
+				_, _ = fmt.Fprintf(w, "| %s | %s | %s | %s |\n", l.File, markdownLevelID(l.LevelID), f.Check, markdownCell(f.Error()))
+			}
+		}
+	}
+
+	if !r.CheckSolvable || len(r.Levels) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "<details><summary>Solver stats</summary>")
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "| File | Level | Solvable | Solver | States | Time (ms) |")
+	_, _ = fmt.Fprintln(w, "| --- | --- | --- | --- | --- | --- |")
+	for _, l := range r.Levels {
+		if s := l.Solvability; s != nil {
+			_, _ = fmt.Fprintf(w, "| %s | %d | %v | %s | %d | %d |\n",
+				l.File, l.LevelID, s.Solvable, s.Solver, s.StatesExplored, s.TimeMs)
+		}
+	}
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "</details>")
+}
+
+// ruleSuffix names the structural rules a level failed, e.g. " [overlaps, hints]".
+func ruleSuffix(l LevelResult) string {
+	if ids := l.FailedRules(); len(ids) > 0 {
+		return " [" + strings.Join(ids, ", ") + "]"
+	}
+	return ""
+}
+
+func markdownLevelID(id int) string {
+	if id == 0 {
+		return "—"
+	}
+	return fmt.Sprint(id)
+}
+
+// markdownCell keeps free text from breaking a table row.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// levelFileLess orders level_N.json names by N, falling back to plain string order.
+func levelFileLess(a, b string) bool {
+	var na, nb int
+	_, errA := fmt.Sscanf(filepath.Base(a), "level_%d.json", &na)
+	_, errB := fmt.Sscanf(filepath.Base(b), "level_%d.json", &nb)
+	if errA == nil && errB == nil && na != nb {
+		return na < nb
+	}
+	return a < b
+}
@@ -0,0 +1,246 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/pack"
+)
+
+func sampleReport() Report {
+	r := Report{
+		CheckSolvable: true,
+		Levels: []LevelResult{
+			{File: "level_10.json", LevelID: 10, Solvability: &LevelStat{LevelID: 10, Solvable: true, Solver: "greedy-fast", TimeMs: 5}},
+			{File: "level_2.json", Error: "overlapping vines at (1,1)"},
+			{File: "level_3.json", LevelID: 3, Solvability: &LevelStat{LevelID: 3, Solver: "exact-astar", StatesExplored: 500, GaveUp: true, TimeMs: 40}},
+		},
+	}
+	r.finish()
+	return r
+}
+
+func TestReportSummary(t *testing.T) {
+	r := sampleReport()
+	if r.Levels[0].File != "level_2.json" || r.Levels[2].File != "level_10.json" {
+		t.Errorf("levels not in numeric order: %v, %v, %v", r.Levels[0].File, r.Levels[1].File, r.Levels[2].File)
+	}
+	if r.Total != 3 || r.Passed != 1 || r.Structural != 1 || r.Unsolvable != 1 {
+		t.Errorf("unexpected counts: %+v", r)
+	}
+	if err := r.Err(); err == nil || !strings.Contains(err.Error(), "2 levels failed") {
+		t.Errorf("Err() = %v, want 2 failed levels", err)
+	}
+	if err := (Report{ModuleError: "module 1 missing theme_seed"}).Err(); err == nil {
+		t.Error("module error must fail the report")
+	}
+}
+
+func TestWriteReportFormats(t *testing.T) {
+	r := sampleReport()
+
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, r, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || decoded.Unsolvable != 1 || len(decoded.Levels) != 3 {
+		t.Errorf("JSON round trip = %+v, %v", decoded, err)
+	}
+
+	buf.Reset()
+	if err := WriteReport(&buf, r, FormatJUnit); err != nil {
+		t.Fatal(err)
+	}
+	var suites junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("invalid JUnit XML: %v\n%s", err, buf.String())
+	}
+	if suites.Tests != 4 || suites.Failures != 2 {
+		t.Errorf("JUnit tests=%d failures=%d, want 4 and 2", suites.Tests, suites.Failures)
+	}
+	if f := suites.Suites[1].Cases[1].Failure; f == nil || f.Type != "unsolvable" {
+		t.Errorf("expected level_3.json to fail as unsolvable, got %+v", f)
+	}
+
+	buf.Reset()
+	if err := WriteReport(&buf, r, FormatMarkdown); err != nil {
+		t.Fatal(err)
+	}
+	md := buf.String()
+	for _, want := range []string{"2 of 3 levels failed", "| level_2.json | — | structural |", "| level_3.json | 3 | solvability |", "<details>"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	if err := WriteReport(&buf, r, "yaml"); err == nil {
+		t.Error("expected unknown format to be rejected")
+	}
+}
+
+func TestReportIncludesDeadlock(t *testing.T) {
+	r := sampleReport()
+	r.Levels[1].Solvability.GaveUp = false
+	r.Levels[1].Deadlock = &Deadlock{Vines: []string{"a", "b"}, Cycle: []string{"a", "b", "a"}, Render: "board\n"}
+
+	var buf bytes.Buffer
+	r.WriteText(&buf)
+	for _, want := range []string{"Deadlocked vines: a, b", "Cycle (each waits for the next to clear): a -> b -> a", "      board"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("text report missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	r.WriteMarkdown(&buf)
+	if !strings.Contains(buf.String(), "circular blocking: a -> b -> a") {
+		t.Errorf("markdown report missing the cycle:\n%s", buf.String())
+	}
+}
+
+func TestLevelResultWriteText(t *testing.T) {
+	r := sampleReport()
+	var buf bytes.Buffer
+	for _, l := range r.Levels {
+		l.WriteText(&buf)
+	}
+	want := "❌ level_2.json: overlapping vines at (1,1)\n" +
+		"❌ level_3.json (level 3): not solvable (solver=exact-astar states=500 gave_up=true)\n" +
+		"✓ level_10.json (level 10): solvable (solver=greedy-fast states=0 time=5ms)\n"
+	if buf.String() != want {
+		t.Errorf("WriteText =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestValidateInputStdin(t *testing.T) {
+	old := common.Stdin
+	t.Cleanup(func() { common.Stdin = old })
+
+	lvl := baseFullGridLevel()
+	data, err := json.Marshal(lvl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	common.Stdin = bytes.NewReader(data)
+	report, ok, err := ValidateInput(context.Background(), common.StdioPath, true, 1000, false, 0, false)
+	if err != nil || !ok {
+		t.Fatalf("ValidateInput: ok=%v err=%v", ok, err)
+	}
+	if report.Total != 1 || report.Passed != 1 || report.Levels[0].File != "stdin" {
+		t.Errorf("report = %+v, want one passing level from stdin", report)
+	}
+
+	lvl.Vines[1].OrderedPath[0] = lvl.Vines[0].OrderedPath[0] // overlap
+	data, _ = json.Marshal(lvl)
+	common.Stdin = bytes.NewReader(data)
+	report, _, _ = ValidateInput(context.Background(), common.StdioPath, false, 1000, false, 0, false)
+	if report.Err() == nil {
+		t.Error("expected an overlapping piped level to fail")
+	}
+}
+
+func TestSelectLevelFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, id := range []int{4, 41, 42, 7} {
+		lvl := baseFullGridLevel()
+		lvl.ID = id
+		if err := common.WriteLevel(common.GetLevelFilePath(id, dir), &lvl, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := SelectLevelFiles(dir, []int{7, 42}, []string{filepath.Join(dir, "level_4?.json")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, filepath.Base(f))
+	}
+	if want := []string{"level_7.json", "level_42.json", "level_41.json"}; !slices.Equal(names, want) {
+		t.Errorf("SelectLevelFiles = %v, want %v", names, want)
+	}
+
+	if _, err := SelectLevelFiles(dir, []int{99}, nil); err == nil {
+		t.Error("expected an error for a level ID without a file")
+	}
+	if _, err := SelectLevelFiles(dir, nil, []string{filepath.Join(dir, "level_9*.json")}); err == nil {
+		t.Error("expected an error for a pattern matching nothing")
+	}
+
+	report, err := ValidateFiles(context.Background(), files[:1], false, 1000, false, 0, false)
+	if err != nil || report.Total != 1 || report.Passed != 1 {
+		t.Errorf("ValidateFiles = %+v, %v; want one passing level", report, err)
+	}
+}
+
+func TestValidatePack(t *testing.T) {
+	dir := t.TempDir()
+	for _, id := range []int{4, 5} {
+		lvl := baseFullGridLevel()
+		lvl.ID = id
+		if id == 5 {
+			lvl.Vines[1].OrderedPath[0] = lvl.Vines[0].OrderedPath[0] // overlap
+		}
+		if err := common.WriteLevel(common.GetLevelFilePath(id, dir), &lvl, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	library := filepath.Join(t.TempDir(), "levels.pbpack")
+	if _, err := pack.WriteLibraryFile(library, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := ValidatePack(context.Background(), library, nil, false, 1000, false, 0, false)
+	if err != nil || report.Total != 2 || report.Passed != 1 || report.Structural != 1 {
+		t.Fatalf("ValidatePack = %+v, %v; want one pass and one structural failure", report, err)
+	}
+	if got := report.Levels[0].File; got != "levels.pbpack:level_4.json" {
+		t.Errorf("first level reported as %q", got)
+	}
+
+	report, err = ValidatePack(context.Background(), library, []int{4}, false, 1000, false, 0, false)
+	if err != nil || report.Total != 1 || report.Err() != nil {
+		t.Errorf("ValidatePack of level 4 = %+v, %v; want one passing level", report, err)
+	}
+	if _, err := ValidatePack(context.Background(), library, []int{6}, false, 1000, false, 0, false); err == nil {
+		t.Error("expected an error for a level the library does not hold")
+	}
+}
+
+func TestValidateLevelFlagsSolutionsOverMaxMoves(t *testing.T) {
+	lvl := baseFullGridLevel()
+	result, ok, err := ValidateLevel(context.Background(), &lvl, true, 1000, false)
+	if err != nil || !ok || !result.Passed() || result.Solvability.SolutionLength != len(lvl.Vines) {
+		t.Fatalf("ValidateLevel = %+v, %v, %v; want a pass with a %d move solution", result, ok, err, len(lvl.Vines))
+	}
+
+	// Leave the structural move-budget rule out so the solver's own check is what fails
+	rules, err := NewRuleFilter(nil, []string{"move-budget"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lvl.MaxMoves = len(lvl.Vines) - 1
+	result, _, _ = ValidateLevel(WithRuleFilter(context.Background(), rules), &lvl, true, 1000, false)
+	if s := result.Solvability; !result.Unsolvable() || s == nil || !s.OverBudget || !strings.Contains(s.Error, "max_moves is 3") {
+		t.Errorf("ValidateLevel = %+v; want a level solvable only past max_moves", result)
+	}
+}
+
+func TestWithWorkers(t *testing.T) {
+	if got := workersFrom(context.Background()); got != runtime.NumCPU() {
+		t.Errorf("workersFrom(no setting) = %d, want runtime.NumCPU() = %d", got, runtime.NumCPU())
+	}
+	if got := workersFrom(WithWorkers(context.Background(), 3)); got != 3 {
+		t.Errorf("workersFrom(WithWorkers 3) = %d, want 3", got)
+	}
+}
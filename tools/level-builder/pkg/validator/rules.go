@@ -0,0 +1,198 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// RuleCheck returns a rule's findings for a level, nil when it passes.
+type RuleCheck func(lvl model.Level) []error
+
+// Rule is one structural check run by ValidateStructural.
+type Rule struct {
+	ID          string
+	Severity    Severity
+	Description string
+	Check       RuleCheck
+}
+
+// RuleResult is the outcome of one rule on one level.
+type RuleResult struct {
+	Rule     string             `json:"rule"`
+	Severity Severity           `json:"severity"`
+	Findings []ValidationResult `json:"findings,omitempty"`
+}
+
+// Failed reports whether the rule found problems that fail the level.
+func (r RuleResult) Failed() bool {
+	return firstError(r.Findings) != nil
+}
+
+var (
+	ruleList  []Rule
+	rulesLock sync.RWMutex
+)
+
+// RegisterRule adds a structural rule, run after those registered before it. Registering an
+// ID again replaces that rule in place.
+func RegisterRule(r Rule) {
+	rulesLock.Lock()
+	defer rulesLock.Unlock()
+
+	for i := range ruleList {
+		if ruleList[i].ID == r.ID {
+			ruleList[i] = r
+			return
+		}
+	}
+	ruleList = append(ruleList, r)
+}
+
+// ListRules returns the registered rules in the order they run.
+func ListRules() []Rule {
+	rulesLock.RLock()
+	defer rulesLock.RUnlock()
+
+	return append([]Rule(nil), ruleList...)
+}
+
+// RuleFilter selects which rules run. The zero value runs every rule.
+type RuleFilter struct {
+	only map[string]bool
+	skip map[string]bool
+}
+
+// NewRuleFilter returns a filter that runs only the rules in only (all when empty), minus those
+// in skip. Unknown rule IDs are an error so a typo cannot silently disable nothing.
+func NewRuleFilter(only, skip []string) (RuleFilter, error) {
+	known := make(map[string]bool)
+	for _, r := range ListRules() {
+		known[r.ID] = true
+	}
+	set := func(ids []string) (map[string]bool, error) {
+		if len(ids) == 0 {
+			return nil, nil
+		}
+		m := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			if !known[id] {
+				return nil, fmt.Errorf("unknown rule %q (want one of %s)", id, strings.Join(RuleIDs(), ", "))
+			}
+			m[id] = true
+		}
+		return m, nil
+	}
+
+	var f RuleFilter
+	var err error
+	if f.only, err = set(only); err != nil {
+		return RuleFilter{}, err
+	}
+	if f.skip, err = set(skip); err != nil {
+		return RuleFilter{}, err
+	}
+	return f, nil
+}
+
+// Enabled reports whether the filter runs the rule with the given ID.
+func (f RuleFilter) Enabled(id string) bool {
+	return (f.only == nil || f.only[id]) && !f.skip[id]
+}
+
+// RuleIDs returns the IDs of the registered rules, sorted.
+func RuleIDs() []string {
+	var ids []string
+	for _, r := range ListRules() {
+		ids = append(ids, r.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+type ruleFilterKey struct{}
+
+// WithRuleFilter returns a context under which ValidateReport and ValidateFile run only the
+// structural rules f enables, and record every rule that ran in LevelResult.Rules.
+func WithRuleFilter(ctx context.Context, f RuleFilter) context.Context {
+	return context.WithValue(ctx, ruleFilterKey{}, f)
+}
+
+func ruleFilterFrom(ctx context.Context) RuleFilter {
+	f, _ := ctx.Value(ruleFilterKey{}).(RuleFilter)
+	return f
+}
+
+// CheckRules runs the rules f enables on lvl and returns one result per rule that ran, in
+// registration order. Each finding takes its rule's severity.
+func CheckRules(lvl model.Level, f RuleFilter) []RuleResult {
+	var results []RuleResult
+	runRules(lvl, f, func(r Rule, errs []error) {
+		result := RuleResult{Rule: r.ID, Severity: r.Severity}
+		for _, err := range errs {
+			result.Findings = append(result.Findings, newFinding(r.ID, r.Severity, err))
+		}
+		results = append(results, result)
+	})
+	return results
+}
+
+// runRules calls report with the findings of each rule f enables, in registration order. The
+// rules index the grid, so on a malformed grid_size only the grid rule runs.
+func runRules(lvl model.Level, f RuleFilter, report func(Rule, []error)) {
+	gridOK := checkGridSize(lvl) == nil
+	for _, r := range ListRules() {
+		if !gridOK && r.ID != "grid" {
+			continue
+		}
+		if f.Enabled(r.ID) {
+			report(r, r.Check(lvl))
+		}
+	}
+}
+
+// init registers the core structural rules in the order ValidateStructural has always run them,
+// after the grid check the others depend on.
+func init() {
+	RegisterRule(Rule{ID: "grid", Severity: SeverityError,
+		Description: fmt.Sprintf("grid_size is two sides of 1 to %d cells", MaxGridSide), Check: validateGrid})
+	RegisterRule(Rule{ID: "bounds", Severity: SeverityError,
+		Description: "every vine cell lies inside the grid", Check: validateBounds})
+	RegisterRule(Rule{ID: "masked-cells", Severity: SeverityError,
+		Description: "no vine occupies a cell hidden by the mask", Check: validateMaskedCells})
+	RegisterRule(Rule{ID: "gaps", Severity: SeverityError,
+		Description: "show-all gap cells are in bounds, unique and free of vines and portals", Check: validateGaps})
+	RegisterRule(Rule{ID: "overlaps", Severity: SeverityError,
+		Description: "no cell is occupied twice", Check: validateOverlaps})
+	RegisterRule(Rule{ID: "portals", Severity: SeverityError,
+		Description: "portal cells are in bounds, visible, free, unique and not adjacent", Check: validatePortals})
+	RegisterRule(Rule{ID: "vine-length", Severity: SeverityError,
+		Description: "every vine has at least 2 segments", Check: validateVineLength})
+	RegisterRule(Rule{ID: "head-direction", Severity: SeverityError,
+		Description: "head_direction is known and points away from the neck", Check: validateHeadDirections})
+	RegisterRule(Rule{ID: "tail-direction", Severity: SeverityError,
+		Description: "multi-head vines have a valid tail_direction", Check: validateTailDirections})
+	RegisterRule(Rule{ID: "connectivity", Severity: SeverityError,
+		Description: "consecutive vine segments are 4-adjacent", Check: validateConnectivity})
+	RegisterRule(Rule{ID: "circular-blocking", Severity: SeverityError,
+		Description: "the blocking graph has no cycle", Check: validateCircularBlocking})
+	RegisterRule(Rule{ID: "locks", Severity: SeverityError,
+		Description: "every locked vine can collect its locked_until clears", Check: validateLocks})
+	RegisterRule(Rule{ID: "hints", Severity: SeverityError,
+		Description: "hints name distinct vines that clear in the listed order", Check: validateHints})
+	RegisterRule(Rule{ID: "solution-order", Severity: SeverityError,
+		Description: "an embedded solution_order clears every vine in the listed order", Check: validateSolutionOrder})
+	RegisterRule(Rule{ID: "move-budget", Severity: SeverityError,
+		Description: "min_moves and max_moves fit the solution length", Check: validateMoveBudget})
+	RegisterRule(Rule{ID: "self-blocking", Severity: SeverityError,
+		Description: "no vine blocks its own exit path", Check: ValidateSelfBlocking})
+	RegisterRule(Rule{ID: "theme", Severity: SeverityError,
+		Description: "theme accent cells are in bounds and free, density within [0, 1]", Check: validateTheme})
+
+	// vine_color is not checked yet: it's not used in level files and the Vine model has no
+	// VineColor field. Once it does, register a rule rejecting colors not in KnownVineColors.
+}
@@ -0,0 +1,125 @@
+package validator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+func TestRuleFilter(t *testing.T) {
+	if _, err := NewRuleFilter([]string{"no-such-rule"}, nil); err == nil {
+		t.Error("expected an unknown --only-rule to be rejected")
+	}
+	if _, err := NewRuleFilter(nil, []string{"no-such-rule"}); err == nil {
+		t.Error("expected an unknown --skip-rule to be rejected")
+	}
+
+	f, err := NewRuleFilter([]string{"overlaps", "hints"}, []string{"hints"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, want := range map[string]bool{"overlaps": true, "hints": false, "bounds": false} {
+		if got := f.Enabled(id); got != want {
+			t.Errorf("Enabled(%q) = %v, want %v", id, got, want)
+		}
+	}
+	if !(RuleFilter{}).Enabled("bounds") {
+		t.Error("the zero filter must run every rule")
+	}
+}
+
+func TestCheckRules(t *testing.T) {
+	lvl := baseFullGridLevel()
+	lvl.Vines[1].OrderedPath[0] = model.Point{X: 3, Y: 0} // overlaps v1's head
+	lvl.MinMoves = 7
+
+	results := CheckRules(lvl, RuleFilter{})
+	if len(results) != len(ListRules()) {
+		t.Fatalf("got %d results for %d rules", len(results), len(ListRules()))
+	}
+	failed := LevelResult{Rules: results}.FailedRules()
+	for _, want := range []string{"overlaps", "move-budget"} {
+		found := false
+		for _, id := range failed {
+			found = found || id == want
+		}
+		if !found {
+			t.Errorf("failed rules %v missing %q", failed, want)
+		}
+	}
+
+	skip, _ := NewRuleFilter(nil, failed)
+	for _, r := range CheckRules(lvl, skip) {
+		if r.Failed() {
+			t.Errorf("rule %s failed after skipping %v: %v", r.Rule, failed, r.Findings)
+		}
+	}
+
+	only, _ := NewRuleFilter([]string{"move-budget"}, nil)
+	results = CheckRules(lvl, only)
+	if len(results) != 1 || results[0].Rule != "move-budget" || !results[0].Failed() {
+		t.Errorf("--only-rule move-budget results = %+v", results)
+	}
+}
+
+func TestValidateLevelRecordsRules(t *testing.T) {
+	lvl := baseFullGridLevel()
+	lvl.MinMoves = 7
+	result, _, err := ValidateLevel(context.Background(), &lvl, false, 1000, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Error == "" || !reflect.DeepEqual(result.FailedRules(), []string{"move-budget"}) {
+		t.Errorf("result = %+v, want a move-budget failure", result)
+	}
+
+	f, _ := NewRuleFilter(nil, []string{"move-budget"})
+	result, _, err = ValidateLevel(WithRuleFilter(context.Background(), f), &lvl, false, 1000, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Error != "" || len(result.Rules) != len(ListRules())-1 {
+		t.Errorf("with move-budget skipped: error %q, %d rules ran", result.Error, len(result.Rules))
+	}
+}
+
+func TestThemeRule(t *testing.T) {
+	lvl := baseFullGridLevel()
+	lvl.Vines = lvl.Vines[:3] // row 3 stays empty
+	lvl.Theme = &model.LevelTheme{Biome: "forest", DecorationDensity: 0.3, AccentCells: []model.Point{{X: 0, Y: 3}}}
+	if errs := validateTheme(lvl); len(errs) != 0 {
+		t.Fatalf("valid theme rejected: %v", errs)
+	}
+
+	lvl.Theme = &model.LevelTheme{
+		DecorationDensity: 1.5,
+		AccentCells:       []model.Point{{X: 0, Y: 0}, {X: 4, Y: 3}, {X: 1, Y: 3}, {X: 1, Y: 3}},
+	}
+	// No biome, bad density, a vine cell, an out-of-bounds cell and a duplicate
+	if errs := validateTheme(lvl); len(errs) != 5 {
+		t.Errorf("got %d errors, want 5: %v", len(errs), errs)
+	}
+}
+
+func TestGapsRule(t *testing.T) {
+	lvl := baseFullGridLevel()
+	lvl.Vines[3].OrderedPath = lvl.Vines[3].OrderedPath[:3] // leaves (0,3) empty
+	lvl.Mask = model.NewGapMask([]model.Point{{X: 0, Y: 3}})
+	if errs := validateGaps(lvl); len(errs) != 0 {
+		t.Fatalf("valid gap rejected: %v", errs)
+	}
+
+	// A vine cell, an out-of-bounds cell and a duplicate
+	lvl.Mask.Points = append(lvl.Mask.Points, model.Point{X: 1, Y: 1}, model.Point{X: 4, Y: 0}, model.Point{X: 0, Y: 3})
+	if errs := validateGaps(lvl); len(errs) != 3 {
+		t.Errorf("got %d errors, want 3: %v", len(errs), errs)
+	}
+
+	// Other modes list no gaps
+	lvl.Mask.Mode = model.MaskModeShow
+	if errs := validateGaps(lvl); len(errs) != 0 {
+		t.Errorf("show mask checked as gaps: %v", errs)
+	}
+}
@@ -0,0 +1,40 @@
+package validator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+)
+
+// BenchmarkSearchSolvers runs each mask search directly on the shipped levels,
+// bypassing the greedy shortcut.
+func BenchmarkSearchSolvers(b *testing.B) {
+	dir, err := common.LevelsDir()
+	if err != nil {
+		b.Skip(err)
+	}
+	levels, err := common.ReadLevelsFromDir(dir)
+	if err != nil || len(levels) == 0 {
+		b.Skipf("no levels in %s: %v", dir, err)
+	}
+	ctx := context.Background()
+	searches := map[string]func(int) (bool, int, []int){
+		"exact": func(i int) (bool, int, []int) { return isSolvableExactWithStats(ctx, *levels[i], 20000) },
+		"astar": func(i int) (bool, int, []int) {
+			return isSolvableExactAStarWithStats(ctx, *levels[i], 20000, DefaultAStarWeight)
+		},
+		"heuristic": func(i int) (bool, int, []int) { return isSolvableHeuristicWithStats(ctx, *levels[i], 20000) },
+	}
+	for name, search := range searches {
+		b.Run(name, func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				for i, lvl := range levels {
+					if len(lvl.Vines) < 64 {
+						_, _, _ = search(i)
+					}
+				}
+			}
+		})
+	}
+}
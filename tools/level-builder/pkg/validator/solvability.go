@@ -2,6 +2,7 @@ package validator
 
 import (
 	"container/heap"
+	"context"
 	"fmt"
 	"sort"
 
@@ -10,11 +11,16 @@ import (
 )
 
 // IsSolvable performs a bounded-search solvability check for a level. It uses an exact BFS for
-// up to 24 vines (optionally A*) and a lightweight heuristic for larger levels.
+// up to 24 vines (optionally A*) and a lightweight heuristic for larger levels. The searches'
+// vineMask states grow with the level, so levels of up to maxMaskVines are searched; only
+// larger ones rely on the greedy solver alone.
 //
 // SolvabilityStats now contains extra instrumentation useful for experiments and diagnostics.
 const DefaultAStarWeight = 10
 
+// cancelCheckInterval is how many states a search expands between context checks.
+const cancelCheckInterval = 1024
+
 type SolvabilityStats struct {
 	Solver         string `json:"solver"`
 	StatesExplored int    `json:"states_explored"`
@@ -31,42 +37,144 @@ func IsSolvable(lvl model.Level, maxStates int) (bool, SolvabilityStats, error)
 // IsSolvableWithOptions selects an appropriate solver (exact, A*, or heuristic) and returns
 // instrumentation stats. A* is used for small vine counts when requested.
 func IsSolvableWithOptions(lvl model.Level, maxStates int, useAstar bool, astarWeight int) (bool, SolvabilityStats, error) {
+	ok, _, stats, err := SolveWithOptions(lvl, maxStates, useAstar, astarWeight)
+	return ok, stats, err
+}
+
+// IsSolvableContext is IsSolvable with cancellation. When ctx is done the search
+// stops, GaveUp is set and ctx.Err() is returned.
+func IsSolvableContext(ctx context.Context, lvl model.Level, maxStates int) (bool, SolvabilityStats, error) {
+	ok, _, stats, err := SolveWithOptionsContext(ctx, lvl, maxStates, true, DefaultAStarWeight)
+	return ok, stats, err
+}
+
+// Solve is the solution-returning counterpart of IsSolvable.
+func Solve(lvl model.Level, maxStates int) (bool, []string, SolvabilityStats, error) {
+	return SolveWithOptions(lvl, maxStates, true, DefaultAStarWeight)
+}
+
+// SolveContext is Solve with cancellation.
+func SolveContext(ctx context.Context, lvl model.Level, maxStates int) (bool, []string, SolvabilityStats, error) {
+	return SolveWithOptionsContext(ctx, lvl, maxStates, true, DefaultAStarWeight)
+}
+
+// SolveWithOptions runs the same solvers as IsSolvableWithOptions and, when the level is
+// solvable, also returns the solution: vine IDs in the order they are cleared. Each clear is
+// one move, so len(solution) is the level's minimum move count.
+func SolveWithOptions(lvl model.Level, maxStates int, useAstar bool, astarWeight int) (bool, []string, SolvabilityStats, error) {
+	return SolveWithOptionsContext(context.Background(), lvl, maxStates, useAstar, astarWeight)
+}
+
+// SolveWithOptionsContext is SolveWithOptions with cancellation. The searches check ctx
+// every cancelCheckInterval states; a cancelled search reports GaveUp and returns ctx.Err().
+func SolveWithOptionsContext(ctx context.Context, lvl model.Level, maxStates int, useAstar bool, astarWeight int) (bool, []string, SolvabilityStats, error) {
+	if err := ctx.Err(); err != nil {
+		return false, nil, SolvabilityStats{Solver: "none", GaveUp: true}, err
+	}
 	vineCount := len(lvl.Vines)
 	if vineCount == 0 {
-		return true, SolvabilityStats{Solver: "none", StatesExplored: 0, GaveUp: false}, nil
+		return true, []string{}, SolvabilityStats{Solver: "none", StatesExplored: 0, GaveUp: false}, nil
+	}
+	// A solution_order embedded at generation only needs replaying
+	if len(lvl.SolutionOrder) > 0 && VerifySolutionOrder(lvl, lvl.SolutionOrder) == nil {
+		return true, append([]string(nil), lvl.SolutionOrder...), SolvabilityStats{Solver: "embedded"}, nil
 	}
 	// Optimization: Always try greedy solver first. It's very fast and correct for "easy" levels.
 	// This prevents the slow A* solver from timing out on large levels that are actually trivial.
 	solver := common.NewSolver(&lvl)
-	if solver.IsSolvableGreedy() {
-		return true, SolvabilityStats{Solver: "greedy-fast", StatesExplored: 0, GaveUp: false}, nil
+	if order, ok := solver.GreedyClearOrder(); ok {
+		return true, vineIDs(lvl, order), SolvabilityStats{Solver: "greedy-fast", StatesExplored: 0, GaveUp: false}, nil
 	}
 
-	if vineCount >= 64 {
-		// If greedy fails on massive levels, we can't do exact search anyway
-		return false, SolvabilityStats{Solver: "greedy-unlimited", GaveUp: true}, fmt.Errorf("greedy solver failed for %d vines", vineCount)
+	if vineCount > maxMaskVines {
+		// If greedy fails on levels too large for a vineMask, we can't search them at all
+		return false, nil, SolvabilityStats{Solver: "greedy-unlimited", GaveUp: true},
+			fmt.Errorf("greedy solver failed for %d vines (searches hold at most %d)", vineCount, maxMaskVines)
 	}
 	if vineCount <= 24 {
 		if useAstar {
-			ok, states := isSolvableExactAStarWithStats(lvl, maxStates, astarWeight)
-			stats := SolvabilityStats{Solver: "exact-astar", StatesExplored: states, GaveUp: states >= maxStates}
-			return ok, stats, nil
+			ok, states, order := isSolvableExactAStarWithStats(ctx, lvl, maxStates, astarWeight)
+			return searchResult(ctx, "exact-astar", ok, states, maxStates, vineIDs(lvl, order))
+		}
+		ok, states, order := isSolvableExactWithStats(ctx, lvl, maxStates)
+		return searchResult(ctx, "exact", ok, states, maxStates, vineIDs(lvl, order))
+	}
+
+	ok, states, order := isSolvableHeuristicWithStats(ctx, lvl, maxStates)
+	return searchResult(ctx, "heuristic", ok, states, maxStates, vineIDs(lvl, order))
+}
+
+// searchResult packages a search outcome, reporting cancellation as giving up with ctx.Err().
+func searchResult(ctx context.Context, solver string, ok bool, states, maxStates int, solution []string) (bool, []string, SolvabilityStats, error) {
+	stats := SolvabilityStats{Solver: solver, StatesExplored: states, GaveUp: states >= maxStates}
+	if !ok {
+		if err := ctx.Err(); err != nil {
+			stats.GaveUp = true
+			return false, nil, stats, err
 		}
-		ok, states := isSolvableExactWithStats(lvl, maxStates)
-		stats := SolvabilityStats{Solver: "exact", StatesExplored: states, GaveUp: states >= maxStates}
-		return ok, stats, nil
 	}
+	return ok, solution, stats, nil
+}
 
-	ok, states := isSolvableHeuristicWithStats(lvl, maxStates)
-	stats := SolvabilityStats{Solver: "heuristic", StatesExplored: states, GaveUp: states >= maxStates}
-	return ok, stats, nil
+// cancelled reports whether a search that has expanded states should stop for ctx.
+func cancelled(ctx context.Context, states int) bool {
+	return states%cancelCheckInterval == 0 && ctx.Err() != nil
+}
+
+// clearOrder walks parent links back from the empty mask to start and returns the
+// vine indices in the order they were cleared.
+func clearOrder[M vineMask](parents map[M]M, start M) []int {
+	var order []int
+	for mask := *new(M); mask != start; {
+		parent := parents[mask]
+		order = append(order, maskLowestDiff(parent, mask))
+		mask = parent
+	}
+	for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+		order[i], order[j] = order[j], order[i]
+	}
+	return order
+}
+
+// vineIDs maps vine indices to their IDs. A nil order (unsolved) stays nil.
+func vineIDs(lvl model.Level, order []int) []string {
+	if order == nil {
+		return nil
+	}
+	ids := make([]string, len(order))
+	for i, idx := range order {
+		ids[i] = lvl.Vines[idx].ID
+	}
+	return ids
 }
 
 // IsSolvableWithStats reports whether the given level is solvable within the provided maxStates limit.
-// It delegates to the options-aware solver and populates a LevelStat suitable for JSON output.
+// It delegates to the options-aware solver and populates a LevelStat suitable for JSON output,
+// including the length of the solution found.
 func IsSolvableWithStats(lvl model.Level, maxStates int, useAstar bool, astarWeight int) (bool, LevelStat, error) {
-	ok, stats, err := IsSolvableWithOptions(lvl, maxStates, useAstar, astarWeight)
+	return IsSolvableWithStatsContext(context.Background(), lvl, maxStates, useAstar, astarWeight)
+}
+
+// IsSolvableWithStatsContext is IsSolvableWithStats with cancellation. Under non-monotone
+// mechanics set by WithMechanics it solves with those instead, and the A* options are unused.
+func IsSolvableWithStatsContext(ctx context.Context, lvl model.Level, maxStates int, useAstar bool, astarWeight int) (bool, LevelStat, error) {
+	var (
+		ok    bool
+		moves int
+		stats SolvabilityStats
+		err   error
+	)
+	if mech := mechanicsFrom(ctx); mech.Monotone() {
+		var solution []string
+		ok, solution, stats, err = SolveWithOptionsContext(ctx, lvl, maxStates, useAstar, astarWeight)
+		moves = len(solution)
+	} else {
+		ok, moves, stats, err = solveWithMechanics(ctx, lvl, maxStates, mech)
+	}
 	stat := LevelStat{}
+	if ok {
+		stat.SolutionLength = moves
+	}
 	if stats.Solver != "" {
 		stat.Solver = stats.Solver
 	}
@@ -76,8 +184,23 @@ func IsSolvableWithStats(lvl model.Level, maxStates int, useAstar bool, astarWei
 	return ok, stat, err
 }
 
-// isSolvableExactWithStats returns whether the level is solvable and the number of states explored.
-func isSolvableExactWithStats(lvl model.Level, maxStates int) (bool, int) {
+// isSolvableExactWithStats returns whether the level is solvable, the number of states explored
+// and, when solvable, the vine indices in clear order.
+func isSolvableExactWithStats(ctx context.Context, lvl model.Level, maxStates int) (bool, int, []int) {
+	switch n := len(lvl.Vines); {
+	case n <= 64:
+		return exactSearch[[1]uint64](ctx, lvl, maxStates)
+	case n <= 128:
+		return exactSearch[[2]uint64](ctx, lvl, maxStates)
+	case n <= 256:
+		return exactSearch[[4]uint64](ctx, lvl, maxStates)
+	default:
+		return exactSearch[[8]uint64](ctx, lvl, maxStates)
+	}
+}
+
+// exactSearch is the breadth-first search behind isSolvableExactWithStats.
+func exactSearch[M vineMask](ctx context.Context, lvl model.Level, maxStates int) (bool, int, []int) {
 	vines := lvl.Vines
 	vineCount := len(vines)
 	w, h := lvl.GridSize[0], lvl.GridSize[1]
@@ -93,26 +216,27 @@ func isSolvableExactWithStats(lvl model.Level, maxStates int) (bool, int) {
 		vineIndices[i] = indices
 	}
 
-	fullMask := (uint64(1) << uint(vineCount)) - 1
-	visited := make(map[uint64]bool)
-	queue := make([]uint64, 0, 1024)
-	queue = append(queue, fullMask)
-	visited[fullMask] = true
+	full := fullMask[M](vineCount)
+	limit := searchTableCapacity[M](ctx)
+	parents := make(map[M]M)
+	queue := make([]M, 0, 1024)
+	queue = append(queue, full)
+	parents[full] = full
 	states := 0
 
 	// Reusable occupancy buffer
 	occupied := make([]bool, gridArea)
 
 	for len(queue) > 0 {
-		if states >= maxStates {
-			return false, states
+		if states >= maxStates || cancelled(ctx, states) {
+			return false, states, nil
 		}
 
 		mask := queue[0]
 		queue = queue[1:]
 		states++
-		if mask == 0 {
-			return true, states
+		if maskEmpty(mask) {
+			return true, states, clearOrder(parents, full)
 		}
 
 		// Update occupancy bitset with active vines (masked cells are ignored - they are passible)
@@ -120,52 +244,63 @@ func isSolvableExactWithStats(lvl model.Level, maxStates int) (bool, int) {
 			occupied[i] = false
 		}
 		for i := 0; i < vineCount; i++ {
-			if (mask & (uint64(1) << uint(i))) != 0 {
+			if maskHas(mask, i) {
 				for _, idx := range vineIndices[i] {
 					occupied[idx] = true
 				}
 			}
 		}
 
+		cleared := vineCount - maskCount(mask)
 		for i := 0; i < vineCount; i++ {
-			if (mask & (uint64(1) << uint(i))) == 0 {
+			if !maskHas(mask, i) || vines[i].IsLocked(cleared) {
 				continue
 			}
 			if canVineClearFast(lvl, i, occupied, vineIndices[i]) {
-				next := mask & ^(uint64(1) << uint(i))
-				if !visited[next] {
-					visited[next] = true
+				next := maskWithout(mask, i)
+				if _, seen := parents[next]; !seen {
+					parents[next] = mask
 					queue = append(queue, next)
+					if limit > 0 && len(parents) >= limit {
+						return continueBounded[M](ctx, lvl, maxStates, states)
+					}
 				}
 			}
 		}
 	}
 
-	return false, states
+	return false, states, nil
 }
 
+// canVineClearFast reports whether vine vineIndex can slide off the grid head first or,
+// for multi-head vines, tail first.
 func canVineClearFast(lvl model.Level, vineIndex int, occupiedAll []bool, selfIndices []int) bool {
 	v := lvl.Vines[vineIndex]
-	w, h := lvl.GridSize[0], lvl.GridSize[1]
-	dx, dy := directionDelta(v.HeadDirection)
+	if canSlideOut(lvl, v.HeadDirection, occupiedAll, selfIndices) {
+		return true
+	}
+	if !v.IsMultiHead() {
+		return false
+	}
+	reversed := make([]int, len(selfIndices))
+	for i, idx := range selfIndices {
+		reversed[len(selfIndices)-1-i] = idx
+	}
+	return canSlideOut(lvl, v.TailDirection, occupiedAll, reversed)
+}
+
+// canSlideOut simulates a vine whose cells (leading head first) are selfIndices sliding
+// in dir, through any portals, until it leaves the grid or hits another vine.
+func canSlideOut(lvl model.Level, dir string, occupiedAll []bool, selfIndices []int) bool {
+	w := lvl.GridSize[0]
 
 	// Current positions (as indices)
 	positions := make([]int, len(selfIndices))
 	copy(positions, selfIndices)
 
-	maxDist := w + h + len(positions)
-	for step := 0; step < maxDist; step++ {
-		// Calculate next head position
-		headIdx := positions[0]
-		hx, hy := headIdx%w, headIdx/w
-		nx, ny := hx+dx, hy+dy
-
-		// Check head exit
-		if nx < 0 || nx >= w || ny < 0 || ny >= h {
-			return true
-		}
-
-		nextIdx := ny*w + nx
+	head := model.Point{X: positions[0] % w, Y: positions[0] / w}
+	_, ok := lvl.ExitRay(head, dir, func(p model.Point) bool {
+		nextIdx := p.Y*w + p.X
 		// Check collision with others (ignoring self)
 		if occupiedAll[nextIdx] {
 			collidesWithSelf := false
@@ -185,26 +320,35 @@ func canVineClearFast(lvl model.Level, vineIndex int, occupiedAll []bool, selfIn
 			positions[i] = positions[i-1]
 		}
 		positions[0] = nextIdx
+		return true
+	})
+	return ok
+}
+
+// isSolvableHeuristicWithStats uses a best-first search ranked by remaining vines and
+// blocking-chain depth (see blockingChains), trying first the moves that unblock the most
+// vines. Like the exact search it returns the clear order when solvable.
+func isSolvableHeuristicWithStats(ctx context.Context, lvl model.Level, maxStates int) (bool, int, []int) {
+	switch n := len(lvl.Vines); {
+	case n <= 64:
+		return heuristicSearch[[1]uint64](ctx, lvl, maxStates)
+	case n <= 128:
+		return heuristicSearch[[2]uint64](ctx, lvl, maxStates)
+	case n <= 256:
+		return heuristicSearch[[4]uint64](ctx, lvl, maxStates)
+	default:
+		return heuristicSearch[[8]uint64](ctx, lvl, maxStates)
 	}
-	return false
 }
 
-// isSolvableHeuristicWithStats uses a best-first search with a simple unblocking heuristic
-func isSolvableHeuristicWithStats(lvl model.Level, maxStates int) (bool, int) {
+// heuristicSearch is the best-first search behind isSolvableHeuristicWithStats.
+func heuristicSearch[M vineMask](ctx context.Context, lvl model.Level, maxStates int) (bool, int, []int) {
 	vines := lvl.Vines
 	vineCount := len(vines)
 	w, h := lvl.GridSize[0], lvl.GridSize[1]
 	gridArea := w * h
 
-	// Precompute vine indices
-	vineIndices := make([][]int, vineCount)
-	for i, v := range vines {
-		indices := make([]int, len(v.OrderedPath))
-		for j, p := range v.OrderedPath {
-			indices[j] = p.Y*w + p.X
-		}
-		vineIndices[i] = indices
-	}
+	vineIndices := vineCellIndices(lvl)
 
 	// Precompute blocking relationships
 	blocking := make([][]bool, vineCount)
@@ -216,35 +360,39 @@ func isSolvableHeuristicWithStats(lvl model.Level, maxStates int) (bool, int) {
 			if i == j {
 				continue
 			}
-			if doesVineBlockVineFast(vines[i], vines[j], lvl.GridSize) {
+			if doesVineBlockVineFast(vines[i], vines[j], &lvl) {
 				blocking[i][j] = true
 			}
 		}
 	}
 
-	visited := make(map[uint64]bool)
-	pq := &priorityQueueMask{}
+	parents := make(map[M]M)
+	pq := &priorityQueueMask[M]{}
 	heap.Init(pq)
 
-	var fullMask uint64
-	if vineCount >= 64 {
-		fullMask = ^uint64(0)
-	} else {
-		fullMask = (uint64(1) << uint(vineCount)) - 1
+	full := fullMask[M](vineCount)
+	limit := searchTableCapacity[M](ctx)
+	chains := newBlockingChains[M](lvl, vineIndices)
+	if _, ok := chains.priority(full, 1); !ok {
+		// The starting vines block each other in a cycle; no order can clear them
+		return false, 1, nil
 	}
 
-	heap.Push(pq, &maskItem{mask: fullMask, priority: 0})
-	visited[fullMask] = true
+	heap.Push(pq, &maskItem[M]{mask: full, priority: 0})
+	parents[full] = full
 	states := 0
 	occupied := make([]bool, gridArea)
 
 	for pq.Len() > 0 && states < maxStates {
-		item := heap.Pop(pq).(*maskItem)
+		if cancelled(ctx, states) {
+			return false, states, nil
+		}
+		item := heap.Pop(pq).(*maskItem[M])
 		mask := item.mask
 		states++
 
-		if mask == 0 {
-			return true, states
+		if maskEmpty(mask) {
+			return true, states, clearOrder(parents, full)
 		}
 
 		// Update occupancy with active vines (masked cells are passible)
@@ -252,7 +400,7 @@ func isSolvableHeuristicWithStats(lvl model.Level, maxStates int) (bool, int) {
 			occupied[idx] = false
 		}
 		for i := 0; i < vineCount; i++ {
-			if (mask & (uint64(1) << uint(i))) != 0 {
+			if maskHas(mask, i) {
 				for _, idx := range vineIndices[i] {
 					occupied[idx] = true
 				}
@@ -268,7 +416,7 @@ func isSolvableHeuristicWithStats(lvl model.Level, maxStates int) (bool, int) {
 		sort.Slice(movable, func(a, b int) bool {
 			aCount, bCount := 0, 0
 			for k := 0; k < vineCount; k++ {
-				if (mask & (uint64(1) << uint(k))) != 0 {
+				if maskHas(mask, k) {
 					if blocking[movable[a]][k] {
 						aCount++
 					}
@@ -281,43 +429,38 @@ func isSolvableHeuristicWithStats(lvl model.Level, maxStates int) (bool, int) {
 		})
 
 		for _, i := range movable {
-			next := mask & ^(uint64(1) << uint(i))
-			if !visited[next] {
-				visited[next] = true
-				// Priority: fewer vines remaining is better
-				priority := countSetBits64(next)
-				heap.Push(pq, &maskItem{mask: next, priority: priority})
+			next := maskWithout(mask, i)
+			if _, seen := parents[next]; !seen {
+				parents[next] = mask
+				if limit > 0 && len(parents) >= limit {
+					return continueBounded[M](ctx, lvl, maxStates, states)
+				}
+				// Priority: fewer vines remaining, then shallower blocking chains
+				if priority, ok := chains.priority(next, 1); ok {
+					heap.Push(pq, &maskItem[M]{mask: next, priority: priority})
+				}
 			}
 		}
 	}
 
-	return false, states
+	return false, states, nil
 }
 
-func countSetBits64(n uint64) int {
-	count := 0
-	for n != 0 {
-		n &= (n - 1)
-		count++
-	}
-	return count
-}
-
-type maskItem struct {
-	mask     uint64
+type maskItem[M vineMask] struct {
+	mask     M
 	priority int
 }
 
-type priorityQueueMask []*maskItem
+type priorityQueueMask[M vineMask] []*maskItem[M]
 
-func (pq priorityQueueMask) Len() int           { return len(pq) }
-func (pq priorityQueueMask) Less(i, j int) bool { return pq[i].priority < pq[j].priority }
-func (pq priorityQueueMask) Swap(i, j int)      { pq[i], pq[j] = pq[j], pq[i] }
-func (pq *priorityQueueMask) Push(x interface{}) {
-	*pq = append(*pq, x.(*maskItem))
+func (pq priorityQueueMask[M]) Len() int           { return len(pq) }
+func (pq priorityQueueMask[M]) Less(i, j int) bool { return pq[i].priority < pq[j].priority }
+func (pq priorityQueueMask[M]) Swap(i, j int)      { pq[i], pq[j] = pq[j], pq[i] }
+func (pq *priorityQueueMask[M]) Push(x interface{}) {
+	*pq = append(*pq, x.(*maskItem[M]))
 }
 
-func (pq *priorityQueueMask) Pop() interface{} {
+func (pq *priorityQueueMask[M]) Pop() interface{} {
 	old := *pq
 	n := len(old)
 	item := old[n-1]
@@ -325,46 +468,49 @@ func (pq *priorityQueueMask) Pop() interface{} {
 	return item
 }
 
-func determineMovableVinesFast(lvl model.Level, mask uint64, occupied []bool, vineIndices [][]int) []int {
+func determineMovableVinesFast[M vineMask](lvl model.Level, mask M, occupied []bool, vineIndices [][]int) []int {
 	vines := lvl.Vines
-	w, h := lvl.GridSize[0], lvl.GridSize[1]
+	w := lvl.GridSize[0]
+	cleared := len(vines) - maskCount(mask)
 	movable := make([]int, 0, 8)
 	for i := 0; i < len(vines); i++ {
-		if (mask & (uint64(1) << uint(i))) == 0 {
+		if !maskHas(mask, i) || vines[i].IsLocked(cleared) {
 			continue
 		}
-		v := vines[i]
-		head := v.OrderedPath[0]
-		dx, dy := directionDelta(v.HeadDirection)
-		nx, ny := head.X+dx, head.Y+dy
-
-		if nx < 0 || nx >= w || ny < 0 || ny >= h {
-			movable = append(movable, i)
-			continue
-		}
-
-		idx := ny*w + nx
-		if !occupied[idx] {
-			movable = append(movable, i)
+		for _, v := range vines[i].Heads() {
+			next, inGrid, loops := lvl.NextCell(v.OrderedPath[0], v.HeadDirection)
+			if loops {
+				continue // portals trap this head for good
+			}
+			if !inGrid || !occupied[next.Y*w+next.X] {
+				movable = append(movable, i)
+				break
+			}
 		}
 	}
 	return movable
 }
 
-func doesVineBlockVineFast(blocker, blocked model.Vine, gridSize []int) bool {
+// doesVineBlockVineFast reports whether blocker sits directly in front of blocked's head,
+// or of both heads of a multi-head vine. A head facing a portal is in front of the cell
+// past the twin.
+func doesVineBlockVineFast(blocker, blocked model.Vine, lvl *model.Level) bool {
 	if len(blocked.OrderedPath) == 0 {
 		return false
 	}
-	w, h := gridSize[0], gridSize[1]
-	head := blocked.OrderedPath[0]
-	dx, dy := directionDelta(blocked.HeadDirection)
-	nx, ny := head.X+dx, head.Y+dy
-	if nx < 0 || nx >= w || ny < 0 || ny >= h {
-		return false // can exit, not blocked
+	if blocked.IsMultiHead() {
+		return blocksHead(blocker, blocked, lvl) && blocksHead(blocker, blocked.Reversed(), lvl)
+	}
+	return blocksHead(blocker, blocked, lvl)
+}
+
+func blocksHead(blocker, blocked model.Vine, lvl *model.Level) bool {
+	next, inGrid, _ := lvl.NextCell(blocked.OrderedPath[0], blocked.HeadDirection)
+	if !inGrid {
+		return false // exits, or is trapped by portals rather than by a vine
 	}
-	blockIdx := ny*w + nx
 	for _, p := range blocker.OrderedPath {
-		if p.Y*w+p.X == blockIdx {
+		if p == next {
 			return true
 		}
 	}
@@ -0,0 +1,571 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// blockedChainLevel has "blocked" listed first but only clearable after "blocker" leaves its exit path.
+func blockedChainLevel() model.Level {
+	return model.Level{
+		ID:       1,
+		GridSize: []int{5, 3},
+		Vines: []model.Vine{
+			{ID: "blocked", HeadDirection: "right", OrderedPath: []model.Point{{X: 2, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 0}}},
+			{ID: "blocker", HeadDirection: "up", OrderedPath: []model.Point{{X: 3, Y: 1}, {X: 3, Y: 0}}},
+		},
+	}
+}
+
+// replaySolution clears vines in solution order and fails if any move is blocked.
+func replaySolution(t *testing.T, lvl model.Level, solution []string) {
+	t.Helper()
+	if len(solution) != len(lvl.Vines) {
+		t.Fatalf("solution has %d moves for %d vines: %v", len(solution), len(lvl.Vines), solution)
+	}
+
+	w := lvl.GridSize[0]
+	remaining := make(map[string]int, len(lvl.Vines))
+	for i, v := range lvl.Vines {
+		remaining[v.ID] = i
+	}
+
+	for step, id := range solution {
+		idx, ok := remaining[id]
+		if !ok {
+			t.Fatalf("step %d: vine %q unknown or already cleared", step, id)
+		}
+		occupied := make([]bool, w*lvl.GridSize[1])
+		for _, i := range remaining {
+			for _, p := range lvl.Vines[i].OrderedPath {
+				occupied[p.Y*w+p.X] = true
+			}
+		}
+		indices := make([]int, 0, len(lvl.Vines[idx].OrderedPath))
+		for _, p := range lvl.Vines[idx].OrderedPath {
+			indices = append(indices, p.Y*w+p.X)
+		}
+		if lvl.Vines[idx].IsLocked(step) {
+			t.Fatalf("step %d: vine %q is still locked", step, id)
+		}
+		if !canVineClearFast(lvl, idx, occupied, indices) {
+			t.Fatalf("step %d: vine %q is blocked", step, id)
+		}
+		delete(remaining, id)
+	}
+}
+
+func TestSolveReturnsClearOrder(t *testing.T) {
+	lvl := blockedChainLevel()
+
+	ok, solution, stats, err := Solve(lvl, 1000)
+	if err != nil || !ok {
+		t.Fatalf("Solve = %v, %v", ok, err)
+	}
+	if want := []string{"blocker", "blocked"}; !reflect.DeepEqual(solution, want) {
+		t.Fatalf("solution = %v, want %v (solver %s)", solution, want, stats.Solver)
+	}
+	replaySolution(t, lvl, solution)
+}
+
+func TestSearchSolversReturnReplayableOrder(t *testing.T) {
+	lvl := blockedChainLevel()
+	for name, search := range chainSearches(lvl) {
+		ok, _, order := search(context.Background())
+		if !ok {
+			t.Fatalf("%s: expected solvable", name)
+		}
+		replaySolution(t, lvl, vineIDs(lvl, order))
+	}
+}
+
+func chainSearches(lvl model.Level) map[string]func(context.Context) (bool, int, []int) {
+	return map[string]func(context.Context) (bool, int, []int){
+		"exact": func(ctx context.Context) (bool, int, []int) { return isSolvableExactWithStats(ctx, lvl, 1000) },
+		"astar": func(ctx context.Context) (bool, int, []int) {
+			return isSolvableExactAStarWithStats(ctx, lvl, 1000, DefaultAStarWeight)
+		},
+		"heuristic": func(ctx context.Context) (bool, int, []int) { return isSolvableHeuristicWithStats(ctx, lvl, 1000) },
+	}
+}
+
+func TestSearchSolversStopWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	lvl := blockedChainLevel()
+	for name, search := range chainSearches(lvl) {
+		if ok, states, _ := search(ctx); ok || states != 0 {
+			t.Errorf("%s: cancelled search returned ok=%v after %d states", name, ok, states)
+		}
+	}
+
+	ok, _, stats, err := SolveContext(ctx, lvl, 1000)
+	if ok || !stats.GaveUp || err != context.Canceled {
+		t.Errorf("SolveContext = %v, %+v, %v; want gave up with context.Canceled", ok, stats, err)
+	}
+}
+
+func TestBlockingChainsPriority(t *testing.T) {
+	lvl := blockedChainLevel()
+	chains := newBlockingChains[[1]uint64](lvl, vineCellIndices(lvl))
+
+	// "blocked" waits on "blocker": chain depth 1 with both remaining, 0 once it is gone
+	if p, ok := chains.priority([1]uint64{0b11}, 2); !ok || p != 2*1+2 {
+		t.Errorf("priority(all) = %d, %v; want 4, true", p, ok)
+	}
+	if p, ok := chains.priority([1]uint64{0b01}, 2); !ok || p != 2*0+1 {
+		t.Errorf("priority(blocked only) = %d, %v; want 1, true", p, ok)
+	}
+
+	facing := facingHeadsLevel()
+	if _, ok := newBlockingChains[[1]uint64](facing, vineCellIndices(facing)).priority([1]uint64{0b11}, 1); ok {
+		t.Error("expected facing heads to be reported as a cycle")
+	}
+}
+
+func TestSearchSolversPruneCycles(t *testing.T) {
+	lvl := facingHeadsLevel()
+	for name, search := range chainSearches(lvl) {
+		if name == "exact" {
+			continue
+		}
+		if ok, states, _ := search(context.Background()); ok || states != 1 {
+			t.Errorf("%s: got ok=%v after %d states; want unsolvable after 1", name, ok, states)
+		}
+	}
+}
+
+func TestAStarTreatsMaskedCellsAsPassable(t *testing.T) {
+	lvl := blockedChainLevel()
+	lvl.Mask = &model.Mask{Mode: "hide", Points: []model.Point{{X: 4, Y: 0}, {X: 3, Y: 2}}}
+
+	ok, states, order := isSolvableExactAStarWithStats(context.Background(), lvl, 1000, DefaultAStarWeight)
+	if !ok {
+		t.Fatalf("expected masked level to be solvable")
+	}
+	if states != len(lvl.Vines)+1 {
+		t.Errorf("expected a direct search of %d states, got %d", len(lvl.Vines)+1, states)
+	}
+	replaySolution(t, lvl, vineIDs(lvl, order))
+}
+
+// facingHeadsLevel has two heads facing each other, blocking one another forever.
+func facingHeadsLevel() model.Level {
+	return model.Level{
+		ID:       2,
+		GridSize: []int{4, 1},
+		Vines: []model.Vine{
+			{ID: "a", HeadDirection: "right", OrderedPath: []model.Point{{X: 1, Y: 0}, {X: 0, Y: 0}}},
+			{ID: "b", HeadDirection: "left", OrderedPath: []model.Point{{X: 2, Y: 0}, {X: 3, Y: 0}}},
+		},
+	}
+}
+
+// longChainLevel lines up n two-cell vines heading right in one row, so each is
+// blocked by the next and they can only clear from the right.
+func longChainLevel(n int) model.Level {
+	lvl := model.Level{ID: 3, GridSize: []int{2 * n, 1}}
+	for k := 0; k < n; k++ {
+		lvl.Vines = append(lvl.Vines, model.Vine{
+			ID:            fmt.Sprintf("vine_%d", k),
+			HeadDirection: "right",
+			OrderedPath:   []model.Point{{X: 2*k + 1, Y: 0}, {X: 2 * k, Y: 0}},
+		})
+	}
+	return lvl
+}
+
+func TestSearchSolversHandleWideMasks(t *testing.T) {
+	for _, n := range []int{64, 65, 130} {
+		lvl := longChainLevel(n)
+		for name, search := range chainSearches(lvl) {
+			ok, _, order := search(context.Background())
+			if !ok {
+				t.Errorf("%d vines, %s: expected solvable", n, name)
+				continue
+			}
+			replaySolution(t, lvl, vineIDs(lvl, order))
+		}
+	}
+}
+
+func TestSolveSearchesLargeUnsolvableLevels(t *testing.T) {
+	// A facing pair past 70 free vines: greedy stalls, and the search must
+	// prove the level unsolvable instead of giving up
+	lvl := longChainLevel(70)
+	lvl.GridSize[0] += 4
+	lvl.Vines = append(lvl.Vines,
+		model.Vine{ID: "a", HeadDirection: "right", OrderedPath: []model.Point{{X: 141, Y: 0}, {X: 140, Y: 0}}},
+		model.Vine{ID: "b", HeadDirection: "left", OrderedPath: []model.Point{{X: 142, Y: 0}, {X: 143, Y: 0}}},
+	)
+
+	ok, _, stats, err := Solve(lvl, 1000)
+	if err != nil || ok || stats.GaveUp {
+		t.Fatalf("Solve = %v, %+v, %v; want a conclusive unsolvable verdict", ok, stats, err)
+	}
+}
+
+// freeVinesLevel has n vertical vines that can each leave at any time, so the
+// mask searches see every subset of them.
+func freeVinesLevel(n int) model.Level {
+	lvl := model.Level{ID: 4, GridSize: []int{n, 2}}
+	for k := 0; k < n; k++ {
+		lvl.Vines = append(lvl.Vines, model.Vine{
+			ID:            fmt.Sprintf("vine_%d", k),
+			HeadDirection: "up",
+			OrderedPath:   []model.Point{{X: k, Y: 1}, {X: k, Y: 0}},
+		})
+	}
+	return lvl
+}
+
+func TestSearchSolversFallBackWhenTableFull(t *testing.T) {
+	lvl := freeVinesLevel(16)
+	// Room for a few dozen states, far fewer than the 2^16 subsets; without the
+	// fallback the breadth-first search runs out of its 1000-state budget
+	ctx := context.WithValue(context.Background(), maxMemoryKey{}, 4096)
+	for name, search := range chainSearches(lvl) {
+		ok, states, order := search(ctx)
+		if !ok {
+			t.Errorf("%s: expected solvable after falling back, %d states", name, states)
+			continue
+		}
+		replaySolution(t, lvl, vineIDs(lvl, order))
+	}
+}
+
+func TestBoundedSearch(t *testing.T) {
+	ctx := context.Background()
+	lvl := longChainLevel(70)
+	ok, _, order := boundedSearch[[2]uint64](ctx, lvl, 10000, 1)
+	if !ok {
+		t.Fatal("expected the chain to be solvable")
+	}
+	replaySolution(t, lvl, vineIDs(lvl, order))
+
+	// A facing pair behind free vines can never clear
+	stuck := freeVinesLevel(6)
+	stuck.GridSize[0] += 4
+	stuck.Vines = append(stuck.Vines,
+		model.Vine{ID: "a", HeadDirection: "right", OrderedPath: []model.Point{{X: 7, Y: 0}, {X: 6, Y: 0}}},
+		model.Vine{ID: "b", HeadDirection: "left", OrderedPath: []model.Point{{X: 8, Y: 0}, {X: 9, Y: 0}}},
+	)
+	if ok, states, _ := boundedSearch[[1]uint64](ctx, stuck, 10000, 1); ok || states >= 10000 {
+		t.Errorf("expected a conclusive unsolvable verdict, got ok=%v after %d states", ok, states)
+	}
+}
+
+func TestDeadTableEvictsLeastRecentlyUsed(t *testing.T) {
+	d := newDeadTable[[1]uint64](2)
+	d.add([1]uint64{1})
+	d.add([1]uint64{2})
+	d.has([1]uint64{1}) // 1 is now more recent than 2
+	d.add([1]uint64{3})
+	if !d.has([1]uint64{1}) || d.has([1]uint64{2}) || !d.has([1]uint64{3}) {
+		t.Error("expected the least recently used state to be evicted")
+	}
+}
+
+func TestSolveUnsolvableHasNoSolution(t *testing.T) {
+	lvl := facingHeadsLevel()
+
+	ok, solution, _, err := Solve(lvl, 1000)
+	if err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	if ok || solution != nil {
+		t.Fatalf("expected unsolvable with no solution, got %v %v", ok, solution)
+	}
+}
+
+// multiHeadLevel is facingHeadsLevel with a second head on "a" that exits off the left edge.
+func multiHeadLevel(t *testing.T) model.Level {
+	t.Helper()
+	lvl := facingHeadsLevel()
+	a, err := lvl.Vines[0].WithTailHead("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lvl.Vines[0] = a
+	return lvl
+}
+
+func TestSolversUseTailHeads(t *testing.T) {
+	lvl := multiHeadLevel(t)
+
+	ok, solution, _, err := Solve(lvl, 1000)
+	if err != nil || !ok {
+		t.Fatalf("Solve = %v, %v; want solvable through a's tail", ok, err)
+	}
+	if !reflect.DeepEqual(solution, []string{"a", "b"}) {
+		t.Errorf("solution = %v, want [a b]", solution)
+	}
+	for name, search := range chainSearches(lvl) {
+		ok, _, order := search(context.Background())
+		if !ok {
+			t.Errorf("%s: expected multi-head level to be solvable", name)
+			continue
+		}
+		replaySolution(t, lvl, vineIDs(lvl, order))
+	}
+	if errs := ValidateStructural(lvl); len(errs) != 0 {
+		t.Errorf("unexpected structural errors: %v", errs)
+	}
+}
+
+func TestValidateStructuralTailHead(t *testing.T) {
+	lvl := multiHeadLevel(t)
+	lvl.Vines[0].TailDirection = "up"
+	if errs := ValidateStructural(lvl); len(errs) == 0 {
+		t.Error("expected a mismatched tail_direction to be reported")
+	}
+
+	// A U-shaped vine whose tail faces back across its own head
+	u, err := model.NewVine("u", []model.Point{{X: 0, Y: 1}, {X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u, err = u.WithTailHead(""); err != nil {
+		t.Fatal(err)
+	}
+	selfBlocked := model.Level{GridSize: []int{2, 3}, Vines: []model.Vine{u}}
+	if errs := ValidateSelfBlocking(selfBlocked); len(errs) != 0 {
+		t.Errorf("both heads face up with a free exit, got %v", errs)
+	}
+	u.OrderedPath = []model.Point{{X: 1, Y: 1}, {X: 1, Y: 0}, {X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}}
+	u.HeadDirection, u.TailDirection = "up", "right"
+	selfBlocked.Vines[0] = u
+	if errs := ValidateSelfBlocking(selfBlocked); len(errs) != 1 {
+		t.Errorf("expected the head exit to be self-blocked once, got %v", errs)
+	}
+}
+
+// portalLevel has two vines facing each other in the middle row. Without the
+// portal they deadlock; (2,1) links to (0,2), so each head slides out along the
+// empty top row instead.
+func portalLevel() model.Level {
+	return model.Level{
+		ID:       3,
+		GridSize: []int{5, 3},
+		Vines: []model.Vine{
+			{ID: "a", HeadDirection: "right", OrderedPath: []model.Point{{X: 1, Y: 1}, {X: 0, Y: 1}}},
+			{ID: "b", HeadDirection: "left", OrderedPath: []model.Point{{X: 3, Y: 1}, {X: 4, Y: 1}}},
+		},
+		Portals: []model.Portal{{A: model.Point{X: 2, Y: 1}, B: model.Point{X: 0, Y: 2}}},
+	}
+}
+
+func TestSolversFollowPortals(t *testing.T) {
+	lvl := portalLevel()
+
+	ok, solution, _, err := Solve(lvl, 1000)
+	if err != nil || !ok {
+		t.Fatalf("Solve = %v, %v; want solvable through the portal", ok, err)
+	}
+	replaySolution(t, lvl, solution)
+	for name, search := range chainSearches(lvl) {
+		if ok, _, order := search(context.Background()); !ok {
+			t.Errorf("%s: expected portal level to be solvable", name)
+		} else {
+			replaySolution(t, lvl, vineIDs(lvl, order))
+		}
+	}
+	if errs := ValidateStructural(lvl); len(errs) != 0 {
+		t.Errorf("unexpected structural errors: %v", errs)
+	}
+
+	lvl.Portals = nil
+	if ok, _, _, _ := Solve(lvl, 1000); ok {
+		t.Error("without the portal the vines should deadlock")
+	}
+}
+
+func TestValidateStructuralPortals(t *testing.T) {
+	tests := []struct {
+		name   string
+		portal model.Portal
+	}{
+		{"occupied cell", model.Portal{A: model.Point{X: 2, Y: 1}, B: model.Point{X: 0, Y: 1}}},
+		{"adjacent cells", model.Portal{A: model.Point{X: 2, Y: 1}, B: model.Point{X: 2, Y: 2}}},
+		{"out of bounds", model.Portal{A: model.Point{X: 2, Y: 1}, B: model.Point{X: 5, Y: 2}}},
+		{"same cell", model.Portal{A: model.Point{X: 2, Y: 1}, B: model.Point{X: 2, Y: 1}}},
+	}
+	for _, tt := range tests {
+		lvl := portalLevel()
+		lvl.Portals = []model.Portal{tt.portal}
+		if errs := ValidateStructural(lvl); len(errs) == 0 {
+			t.Errorf("%s: expected a portal error", tt.name)
+		}
+	}
+
+	lvl := portalLevel()
+	lvl.Mask = &model.Mask{Mode: model.MaskModeHide, Points: []model.Point{{X: 0, Y: 2}}}
+	if errs := ValidateStructural(lvl); len(errs) == 0 {
+		t.Error("expected a masked portal cell to be reported")
+	}
+}
+
+// lockedLevel has two free vines; "first" is listed first but locked until one
+// other vine has cleared.
+func lockedLevel() model.Level {
+	return model.Level{
+		ID:       4,
+		GridSize: []int{4, 3},
+		Vines: []model.Vine{
+			{ID: "first", HeadDirection: "up", OrderedPath: []model.Point{{X: 0, Y: 1}, {X: 0, Y: 0}}, LockedUntil: 1},
+			{ID: "second", HeadDirection: "up", OrderedPath: []model.Point{{X: 3, Y: 1}, {X: 3, Y: 0}}},
+		},
+	}
+}
+
+func TestSolversRespectLocks(t *testing.T) {
+	lvl := lockedLevel()
+
+	ok, solution, _, err := Solve(lvl, 1000)
+	if err != nil || !ok {
+		t.Fatalf("Solve = %v, %v", ok, err)
+	}
+	if want := []string{"second", "first"}; !reflect.DeepEqual(solution, want) {
+		t.Fatalf("solution = %v, want %v", solution, want)
+	}
+	for name, search := range chainSearches(lvl) {
+		if ok, _, order := search(context.Background()); !ok {
+			t.Errorf("%s: expected locked level to be solvable", name)
+		} else {
+			replaySolution(t, lvl, vineIDs(lvl, order))
+		}
+	}
+	if ok, _, _ := isSolvableFullStateWithStats(context.Background(), lvl, 1000, Mechanics{GrowingVines: true}); !ok {
+		t.Error("full-state: expected locked level to be solvable")
+	}
+
+	// Both vines locked: nothing can ever clear first
+	lvl.Vines[1].LockedUntil = 1
+	if ok, _, _, _ := Solve(lvl, 1000); ok {
+		t.Error("expected mutually locked vines to be unsolvable")
+	}
+	for name, search := range chainSearches(lvl) {
+		if ok, _, _ := search(context.Background()); ok {
+			t.Errorf("%s: expected mutually locked vines to be unsolvable", name)
+		}
+	}
+}
+
+func TestValidateStructuralLocks(t *testing.T) {
+	if errs := ValidateStructural(lockedLevel()); len(errs) != 0 {
+		t.Errorf("unexpected structural errors: %v", errs)
+	}
+
+	tooMany := lockedLevel()
+	tooMany.Vines[0].LockedUntil = 2
+	if errs := ValidateStructural(tooMany); len(errs) != 1 {
+		t.Errorf("expected one unreachable lock error, got %v", errs)
+	}
+
+	// "blocked" waits on "blocker", so nothing can clear before blocker
+	chain := blockedChainLevel()
+	chain.Vines[1].LockedUntil = 1
+	if errs := ValidateStructural(chain); len(errs) != 1 {
+		t.Errorf("expected the lock behind a blocking chain to be reported, got %v", errs)
+	}
+
+	negative := lockedLevel()
+	negative.Vines[0].LockedUntil = -1
+	if errs := ValidateStructural(negative); len(errs) != 1 {
+		t.Errorf("expected a negative lock error, got %v", errs)
+	}
+}
+
+func TestValidateStructuralHints(t *testing.T) {
+	tests := []struct {
+		name  string
+		hints []string
+		errs  int
+	}{
+		{"solution order", []string{"second", "first"}, 0},
+		{"prefix", []string{"second"}, 0},
+		{"locked vine first", []string{"first"}, 1},
+		{"unknown vine", []string{"second", "nope"}, 1},
+		{"repeated vine", []string{"second", "second"}, 1},
+		{"too many", []string{"second", "first", "second"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lvl := lockedLevel()
+			lvl.Hints = tt.hints
+			if errs := ValidateStructural(lvl); len(errs) != tt.errs {
+				t.Errorf("got %d errors %v, want %d", len(errs), errs, tt.errs)
+			}
+		})
+	}
+}
+
+func TestValidateStructuralSolutionOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		order []string
+		errs  int
+	}{
+		{"none", nil, 0},
+		{"solution order", []string{"second", "first"}, 0},
+		{"partial", []string{"second"}, 1},
+		{"locked vine first", []string{"first", "second"}, 1},
+		{"unknown vine", []string{"second", "nope"}, 1},
+		{"repeated vine", []string{"second", "second"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lvl := lockedLevel()
+			lvl.SolutionOrder = tt.order
+			if errs := ValidateStructural(lvl); len(errs) != tt.errs {
+				t.Errorf("got %d errors %v, want %d", len(errs), errs, tt.errs)
+			}
+		})
+	}
+}
+
+func TestSolveReplaysSolutionOrder(t *testing.T) {
+	lvl := lockedLevel()
+	lvl.SolutionOrder = []string{"second", "first"}
+	ok, solution, stats, err := Solve(lvl, 1000)
+	if err != nil || !ok || stats.Solver != "embedded" || !reflect.DeepEqual(solution, lvl.SolutionOrder) {
+		t.Errorf("Solve = %v %v %+v %v, want the embedded order", ok, solution, stats, err)
+	}
+
+	// An order that no longer replays is ignored, not trusted
+	lvl.SolutionOrder = []string{"first", "second"}
+	ok, solution, stats, err = Solve(lvl, 1000)
+	if err != nil || !ok || stats.Solver == "embedded" || !reflect.DeepEqual(solution, []string{"second", "first"}) {
+		t.Errorf("Solve = %v %v %+v %v, want a searched solution", ok, solution, stats, err)
+	}
+}
+
+func TestValidateStructuralMoveBudget(t *testing.T) {
+	tests := []struct {
+		name     string
+		minMoves int
+		maxMoves int
+		errs     int
+	}{
+		{"unset", 0, 0, 0},
+		{"exact", 2, 2, 0},
+		{"slack", 2, 5, 0},
+		{"max below vines", 2, 1, 1},
+		{"min off", 3, 5, 1},
+		{"both off", 1, 1, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lvl := lockedLevel()
+			lvl.MinMoves, lvl.MaxMoves = tt.minMoves, tt.maxMoves
+			if errs := ValidateStructural(lvl); len(errs) != tt.errs {
+				t.Errorf("got %d errors %v, want %d", len(errs), errs, tt.errs)
+			}
+		})
+	}
+}
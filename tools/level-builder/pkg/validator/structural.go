@@ -37,50 +37,128 @@ func (e StructuralError) Error() string {
 	return e.Message
 }
 
-// ValidateStructural performs comprehensive structural validation on a level.
-// Returns all validation errors found (does not stop at first error).
+// ValidateStructural performs comprehensive structural validation on a level by running
+// every registered Rule in order. Returns all errors found by error-severity rules (does not
+// stop at first error).
 func ValidateStructural(lvl model.Level) []error {
 	var errors []error
+	runRules(lvl, RuleFilter{}, func(r Rule, errs []error) {
+		if r.Severity == SeverityError {
+			errors = append(errors, errs...)
+		}
+	})
+	return errors
+}
 
-	// Note: vine_color validation skipped as it's not currently used in level files
-	// and the Vine model doesn't have a VineColor field yet. When vine_color is
-	// added to the JSON schema and model, uncomment this validation:
-	//
-	// for _, v := range lvl.Vines {
-	//     if v.VineColor != "" && !KnownVineColors[v.VineColor] {
-	//         errors = append(errors, StructuralError{
-	//             VineID:  v.ID,
-	//             Message: fmt.Sprintf("unknown vine_color '%s'", v.VineColor),
-	//         })
-	//     }
-	// }
-
-	// Build occupancy map and check overlaps/bounds/masked cells
-	w, h := lvl.GridSize[0], lvl.GridSize[1]
-	occupied := make(map[string]string) // "x,y" -> vineID
+// MaxGridSide is the largest grid dimension a level may have. It is far above any tier's grid
+// and keeps a malformed file from making the validator allocate an enormous board.
+const MaxGridSide = 256
+
+// checkGridSize reports a grid_size that is not two sides of 1 to MaxGridSide cells, the
+// shape the rules and solvers index into. Level files need 2x2 at least as well.
+func checkGridSize(lvl model.Level) error {
+	if len(lvl.GridSize) != 2 {
+		return fmt.Errorf("invalid grid size %v: want [width, height]", lvl.GridSize)
+	}
+	for _, side := range lvl.GridSize {
+		if side < 1 || side > MaxGridSide {
+			return fmt.Errorf("invalid grid size %dx%d: sides must be 1 to %d",
+				lvl.GridSize[0], lvl.GridSize[1], MaxGridSide)
+		}
+	}
+	return nil
+}
 
+// validateGrid checks the grid size the other rules index into.
+func validateGrid(lvl model.Level) []error {
+	if err := checkGridSize(lvl); err != nil {
+		return []error{err}
+	}
+	return nil
+}
+
+// validateBounds checks that every vine cell lies inside the grid.
+func validateBounds(lvl model.Level) []error {
+	var errors []error
+	w, h := lvl.GridSize[0], lvl.GridSize[1]
 	for _, v := range lvl.Vines {
 		for _, p := range v.OrderedPath {
-			// Check bounds
-			if p.X < 0 || p.X >= w || p.Y < 0 || p.Y >= h {
+			if !inGrid(lvl, p) {
 				errors = append(errors, StructuralError{
 					VineID: v.ID,
 					Message: fmt.Sprintf("cell (%d,%d) out of bounds (grid %dx%d)",
 						p.X, p.Y, w, h),
 				})
-				continue
 			}
+		}
+	}
+	return errors
+}
 
-			// Check masked cells
-			if !isCellVisible(lvl, p.X, p.Y) {
+// validateMaskedCells checks that no vine occupies a cell the mask hides.
+func validateMaskedCells(lvl model.Level) []error {
+	var errors []error
+	for _, v := range lvl.Vines {
+		for _, p := range v.OrderedPath {
+			if inGrid(lvl, p) && !isCellVisible(lvl, p.X, p.Y) {
 				errors = append(errors, StructuralError{
 					VineID: v.ID,
 					Message: fmt.Sprintf("cell (%d,%d) is masked out but occupied",
 						p.X, p.Y),
 				})
 			}
+		}
+	}
+	return errors
+}
 
-			// Check overlaps
+// validateGaps checks that the decorative gaps of a "show-all" mask are in
+// bounds, listed once, and left empty by vines and portals.
+func validateGaps(lvl model.Level) []error {
+	if lvl.Mask == nil || lvl.Mask.Mode != model.MaskModeShowAll {
+		return nil
+	}
+	var errors []error
+	w, h := lvl.GridSize[0], lvl.GridSize[1]
+	taken := make(map[model.Point]string)
+	for _, v := range lvl.Vines {
+		for _, p := range v.OrderedPath {
+			taken[p] = "vine " + v.ID
+		}
+	}
+	for _, pt := range lvl.Portals {
+		taken[pt.A], taken[pt.B] = "a portal", "a portal"
+	}
+	seen := make(map[model.Point]bool)
+	for _, p := range lvl.Mask.Points {
+		switch {
+		case p.X < 0 || p.X >= w || p.Y < 0 || p.Y >= h:
+			errors = append(errors, StructuralError{
+				Message: fmt.Sprintf("gap cell (%d,%d) out of bounds (grid %dx%d)", p.X, p.Y, w, h),
+			})
+		case taken[p] != "":
+			errors = append(errors, StructuralError{
+				Message: fmt.Sprintf("gap cell (%d,%d) is covered by %s", p.X, p.Y, taken[p]),
+			})
+		case seen[p]:
+			errors = append(errors, StructuralError{
+				Message: fmt.Sprintf("gap cell (%d,%d) is listed twice", p.X, p.Y),
+			})
+		}
+		seen[p] = true
+	}
+	return errors
+}
+
+// validateOverlaps checks that no cell is occupied by two vines, or twice by one.
+func validateOverlaps(lvl model.Level) []error {
+	var errors []error
+	occupied := make(map[string]string) // "x,y" -> vineID
+	for _, v := range lvl.Vines {
+		for _, p := range v.OrderedPath {
+			if !inGrid(lvl, p) {
+				continue
+			}
 			key := fmt.Sprintf("%d,%d", p.X, p.Y)
 			if existingVine, exists := occupied[key]; exists {
 				errors = append(errors, StructuralError{
@@ -93,19 +171,30 @@ func ValidateStructural(lvl model.Level) []error {
 			}
 		}
 	}
+	return errors
+}
 
-	// Validate each vine's structure
+// validateVineLength checks that every vine has a head and a neck.
+func validateVineLength(lvl model.Level) []error {
+	var errors []error
 	for _, v := range lvl.Vines {
-		// Check minimum length
 		if len(v.OrderedPath) < 2 {
 			errors = append(errors, StructuralError{
 				VineID:  v.ID,
 				Message: fmt.Sprintf("vine has only %d segments (minimum 2)", len(v.OrderedPath)),
 			})
-			continue
 		}
+	}
+	return errors
+}
 
-		// Check head/neck orientation
+// validateHeadDirections checks that each head_direction is known and points away from the neck.
+func validateHeadDirections(lvl model.Level) []error {
+	var errors []error
+	for _, v := range lvl.Vines {
+		if len(v.OrderedPath) < 2 {
+			continue // reported by the vine-length rule
+		}
 		head := v.OrderedPath[0]
 		neck := v.OrderedPath[1]
 		dx := head.X - neck.X
@@ -137,8 +226,33 @@ func ValidateStructural(lvl model.Level) []error {
 					expectedDx, expectedDy, dx, dy),
 			})
 		}
+	}
+	return errors
+}
 
-		// Check 4-connectivity (8-point connectivity = adjacent cells only)
+// validateTailDirections checks the tail head of multi-head vines against their last two cells.
+func validateTailDirections(lvl model.Level) []error {
+	var errors []error
+	for _, v := range lvl.Vines {
+		if len(v.OrderedPath) < 2 || !v.IsMultiHead() {
+			continue
+		}
+		if _, err := v.WithTailHead(v.TailDirection); err != nil {
+			tail := v.OrderedPath[len(v.OrderedPath)-1]
+			errors = append(errors, StructuralError{
+				VineID: v.ID,
+				Message: fmt.Sprintf("invalid tail_direction '%s' for tail (%d,%d)",
+					v.TailDirection, tail.X, tail.Y),
+			})
+		}
+	}
+	return errors
+}
+
+// validateConnectivity checks that consecutive segments of each vine are 4-adjacent.
+func validateConnectivity(lvl model.Level) []error {
+	var errors []error
+	for _, v := range lvl.Vines {
 		for i := 1; i < len(v.OrderedPath); i++ {
 			prev := v.OrderedPath[i-1]
 			curr := v.OrderedPath[i]
@@ -155,68 +269,174 @@ func ValidateStructural(lvl model.Level) []error {
 			}
 		}
 	}
+	return errors
+}
 
-	// Check for circular blocking (deadlock detection)
-	if circularError := checkCircularBlocking(lvl); circularError != nil {
-		errors = append(errors, circularError)
-	}
-
-	// Check for self-blocking vines (vine blocking its own exit path)
-	if selfBlockingErrors := ValidateSelfBlocking(lvl); len(selfBlockingErrors) > 0 {
-		errors = append(errors, selfBlockingErrors...)
+// validateCircularBlocking reports a deadlock in the blocking graph.
+func validateCircularBlocking(lvl model.Level) []error {
+	if err := checkCircularBlocking(lvl); err != nil {
+		return []error{err}
 	}
+	return nil
+}
 
-	return errors
+func inGrid(lvl model.Level, p model.Point) bool {
+	return p.X >= 0 && p.X < lvl.GridSize[0] && p.Y >= 0 && p.Y < lvl.GridSize[1]
 }
 
 // ValidateSelfBlocking checks if any vine blocks its own exit path.
-// The "exit path" is the straight line from the vine's head in its HeadDirection to the grid edge.
+// The "exit path" is the straight line from the vine's head in its HeadDirection to the grid edge,
+// continuing from the twin of any portal it enters.
 // If any segment of the SAME vine occupies a cell on this path, the vine is self-blocking.
+// Both heads of a multi-head vine are checked.
 func ValidateSelfBlocking(lvl model.Level) []error {
 	var errors []error
-	w, h := lvl.GridSize[0], lvl.GridSize[1]
 
-	for _, v := range lvl.Vines {
-		if len(v.OrderedPath) < 1 {
+	for _, vine := range lvl.Vines {
+		if len(vine.OrderedPath) < 1 {
 			continue
 		}
 
-		head := v.OrderedPath[0]
-		dx, dy := 0, 0
-		switch v.HeadDirection {
-		case "right":
-			dx, dy = 1, 0
-		case "left":
-			dx, dy = -1, 0
-		case "up":
-			dx, dy = 0, 1
-		case "down":
-			dx, dy = 0, -1
-		default:
-			continue // Invalid direction handled elsewhere
+		for i, v := range vine.Heads() {
+			if err := selfBlockingError(&lvl, v, i > 0); err != nil {
+				errors = append(errors, err)
+			}
+		}
+	}
+
+	return errors
+}
+
+// selfBlockingError reports the first segment of v on its head's exit path.
+// tail marks v as the reversed view of a multi-head vine.
+func selfBlockingError(lvl *model.Level, v model.Vine, tail bool) error {
+	// Calculate exit path points
+	exitPath := make(map[model.Point]bool)
+	lvl.ExitRay(v.OrderedPath[0], v.HeadDirection, func(p model.Point) bool {
+		exitPath[p] = true
+		return true
+	})
+
+	// Check if any segment of THIS vine intersects the exit path
+	// Skip head (index 0) as it defines the start of the path
+	which := "head"
+	if tail {
+		which = "tail"
+	}
+	for i := 1; i < len(v.OrderedPath); i++ {
+		p := v.OrderedPath[i]
+		if exitPath[p] {
+			return StructuralError{
+				VineID:  v.ID,
+				Message: fmt.Sprintf("self-blocking: segment at (%d,%d) blocks %s exit path", p.X, p.Y, which),
+			}
 		}
+	}
+	return nil
+}
+
+// validateTheme checks that a level's theme names a biome, has a decoration
+// density between 0 and 1, and places accent cells only on distinct in-grid
+// cells no vine or portal covers.
+func validateTheme(lvl model.Level) []error {
+	t := lvl.Theme
+	if t == nil {
+		return nil
+	}
+	var errors []error
+	if t.Biome == "" {
+		errors = append(errors, StructuralError{Message: "theme has no biome"})
+	}
+	if t.DecorationDensity < 0 || t.DecorationDensity > 1 {
+		errors = append(errors, StructuralError{
+			Message: fmt.Sprintf("theme decoration_density %.2f outside [0, 1]", t.DecorationDensity),
+		})
+	}
 
-		// Calculate exit path points
-		exitPath := make(map[string]bool)
-		currX, currY := head.X+dx, head.Y+dy
-		for currX >= 0 && currX < w && currY >= 0 && currY < h {
-			exitPath[fmt.Sprintf("%d,%d", currX, currY)] = true
-			currX += dx
-			currY += dy
+	w, h := lvl.GridSize[0], lvl.GridSize[1]
+	taken := make(map[model.Point]string)
+	for _, v := range lvl.Vines {
+		for _, p := range v.OrderedPath {
+			taken[p] = "vine " + v.ID
 		}
+	}
+	for _, pt := range lvl.Portals {
+		taken[pt.A], taken[pt.B] = "a portal", "a portal"
+	}
+	seen := make(map[model.Point]bool)
+	for _, p := range t.AccentCells {
+		switch {
+		case p.X < 0 || p.X >= w || p.Y < 0 || p.Y >= h:
+			errors = append(errors, StructuralError{
+				Message: fmt.Sprintf("theme accent cell (%d,%d) out of bounds (grid %dx%d)", p.X, p.Y, w, h),
+			})
+		case taken[p] != "":
+			errors = append(errors, StructuralError{
+				Message: fmt.Sprintf("theme accent cell (%d,%d) is covered by %s", p.X, p.Y, taken[p]),
+			})
+		case seen[p]:
+			errors = append(errors, StructuralError{
+				Message: fmt.Sprintf("theme accent cell (%d,%d) is listed twice", p.X, p.Y),
+			})
+		}
+		seen[p] = true
+	}
+	return errors
+}
 
-		// Check if any segment of THIS vine intersects the exit path
-		// Skip head (index 0) as it defines the start of the path
-		for i := 1; i < len(v.OrderedPath); i++ {
-			p := v.OrderedPath[i]
-			key := fmt.Sprintf("%d,%d", p.X, p.Y)
-			if exitPath[key] {
+// validatePortals checks that portal cells are in bounds, visible, free of
+// vines, used once, and not next to another portal cell (adjacent portal cells
+// could hand a head back and forth forever).
+func validatePortals(lvl model.Level) []error {
+	var errors []error
+	w, h := lvl.GridSize[0], lvl.GridSize[1]
+	seen := make(map[model.Point]bool)
+	occupied := make(map[string]string) // "x,y" -> first vineID
+	for _, v := range lvl.Vines {
+		for _, p := range v.OrderedPath {
+			if key := fmt.Sprintf("%d,%d", p.X, p.Y); occupied[key] == "" {
+				occupied[key] = v.ID
+			}
+		}
+	}
+
+	for i, pt := range lvl.Portals {
+		if pt.A == pt.B {
+			errors = append(errors, StructuralError{
+				Message: fmt.Sprintf("portal %d links (%d,%d) to itself", i, pt.A.X, pt.A.Y),
+			})
+			continue
+		}
+		for _, p := range []model.Point{pt.A, pt.B} {
+			switch {
+			case p.X < 0 || p.X >= w || p.Y < 0 || p.Y >= h:
+				errors = append(errors, StructuralError{
+					Message: fmt.Sprintf("portal %d cell (%d,%d) out of bounds (grid %dx%d)", i, p.X, p.Y, w, h),
+				})
+			case !isCellVisible(lvl, p.X, p.Y):
+				errors = append(errors, StructuralError{
+					Message: fmt.Sprintf("portal %d cell (%d,%d) is masked out", i, p.X, p.Y),
+				})
+			case occupied[fmt.Sprintf("%d,%d", p.X, p.Y)] != "":
+				errors = append(errors, StructuralError{
+					Message: fmt.Sprintf("portal %d cell (%d,%d) is occupied by vine %s",
+						i, p.X, p.Y, occupied[fmt.Sprintf("%d,%d", p.X, p.Y)]),
+				})
+			case seen[p]:
 				errors = append(errors, StructuralError{
-					VineID:  v.ID,
-					Message: fmt.Sprintf("self-blocking: segment at (%d,%d) blocks head exit path", p.X, p.Y),
+					Message: fmt.Sprintf("portal %d cell (%d,%d) is already a portal", i, p.X, p.Y),
+				})
+			}
+			seen[p] = true
+		}
+	}
+
+	for p := range seen {
+		for _, d := range []model.Point{{X: 1, Y: 0}, {X: 0, Y: 1}} {
+			if q := (model.Point{X: p.X + d.X, Y: p.Y + d.Y}); seen[q] {
+				errors = append(errors, StructuralError{
+					Message: fmt.Sprintf("portal cells (%d,%d) and (%d,%d) are adjacent", p.X, p.Y, q.X, q.Y),
 				})
-				// Report once per vine
-				break
 			}
 		}
 	}
@@ -257,6 +477,47 @@ func isCellVisible(lvl model.Level, x, y int) bool {
 // checkCircularBlocking detects circular dependencies in the blocking graph.
 // Returns an error if a circular blocking pattern is detected (deadlock).
 func checkCircularBlocking(lvl model.Level) error {
+	graph := blockingGraph(lvl)
+
+	// Detect cycles using DFS
+	visited := make(map[string]bool)
+	recStack := make(map[string]bool)
+
+	var hasCycle func(string) bool
+	hasCycle = func(nodeID string) bool {
+		if recStack[nodeID] {
+			return true // Cycle detected
+		}
+		if visited[nodeID] {
+			return false // Already processed
+		}
+
+		visited[nodeID] = true
+		recStack[nodeID] = true
+
+		for _, neighbor := range graph[nodeID] {
+			if hasCycle(neighbor) {
+				return true
+			}
+		}
+
+		recStack[nodeID] = false
+		return false
+	}
+
+	for _, v := range lvl.Vines {
+		if hasCycle(v.ID) {
+			return StructuralError{
+				Message: "circular blocking detected (unsolvable deadlock)",
+			}
+		}
+	}
+
+	return nil
+}
+
+// blockingGraph returns the level's blocking graph: A -> B means "A blocks B".
+func blockingGraph(lvl model.Level) map[string][]string {
 	// Build occupancy map
 	occupied := make(map[string]string) // "x,y" -> vineID
 	for _, v := range lvl.Vines {
@@ -266,7 +527,6 @@ func checkCircularBlocking(lvl model.Level) error {
 		}
 	}
 
-	// Build blocking graph: A -> B means "A blocks B"
 	graph := make(map[string][]string)
 	for _, v := range lvl.Vines {
 		graph[v.ID] = []string{}
@@ -277,74 +537,188 @@ func checkCircularBlocking(lvl model.Level) error {
 			if i == j {
 				continue
 			}
-			if vineBlocksVine(lvl.Vines[i], lvl.Vines[j], occupied) {
+			if vineBlocksVine(&lvl, lvl.Vines[i], lvl.Vines[j], occupied) {
 				graph[lvl.Vines[i].ID] = append(graph[lvl.Vines[i].ID], lvl.Vines[j].ID)
 			}
 		}
 	}
+	return graph
+}
 
-	// Detect cycles using DFS
-	visited := make(map[string]bool)
-	recStack := make(map[string]bool)
+// validateLocks checks that every locked vine can unlock. Vines that wait on a
+// locked vine, directly or down a blocking chain, cannot clear before it, so
+// the rest of the level must supply its locked_until clears.
+func validateLocks(lvl model.Level) []error {
+	var errors []error
+	var graph map[string][]string
 
-	var hasCycle func(string) bool
-	hasCycle = func(nodeID string) bool {
-		if recStack[nodeID] {
-			return true // Cycle detected
+	for _, v := range lvl.Vines {
+		if v.LockedUntil == 0 {
+			continue
 		}
-		if visited[nodeID] {
-			return false // Already processed
+		if v.LockedUntil < 0 {
+			errors = append(errors, StructuralError{
+				VineID:  v.ID,
+				Message: fmt.Sprintf("locked_until %d is negative", v.LockedUntil),
+			})
+			continue
+		}
+		if graph == nil {
+			graph = blockingGraph(lvl)
 		}
 
-		visited[nodeID] = true
-		recStack[nodeID] = true
-
-		for _, neighbor := range graph[nodeID] {
-			if hasCycle(neighbor) {
-				return true
+		waiting := make(map[string]bool)
+		stack := append([]string(nil), graph[v.ID]...)
+		for len(stack) > 0 {
+			id := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if id == v.ID || waiting[id] {
+				continue
 			}
+			waiting[id] = true
+			stack = append(stack, graph[id]...)
 		}
 
-		recStack[nodeID] = false
-		return false
+		if free := len(lvl.Vines) - 1 - len(waiting); v.LockedUntil > free {
+			errors = append(errors, StructuralError{
+				VineID: v.ID,
+				Message: fmt.Sprintf("locked until %d clears but only %d vines can clear before it (%d wait on it)",
+					v.LockedUntil, free, len(waiting)),
+			})
+		}
 	}
 
-	for _, v := range lvl.Vines {
-		if hasCycle(v.ID) {
-			return StructuralError{
-				Message: "circular blocking detected (unsolvable deadlock)",
-			}
+	return errors
+}
+
+// validateMoveBudget checks max_moves and min_moves against the solution
+// length. Every solution clears each vine exactly once, so any order the
+// solver verifies takes len(Vines) moves: min_moves, when set, must equal it
+// and max_moves must not be below it. A zero max_moves is left to the
+// max_moves check in readLevelFile.
+func validateMoveBudget(lvl model.Level) []error {
+	var errors []error
+	minMoves := len(lvl.Vines)
+	if lvl.MinMoves != 0 && lvl.MinMoves != minMoves {
+		errors = append(errors, StructuralError{
+			Message: fmt.Sprintf("min_moves %d does not match the %d moves every solution takes", lvl.MinMoves, minMoves),
+		})
+	}
+	if lvl.MaxMoves > 0 && lvl.MaxMoves < minMoves {
+		errors = append(errors, StructuralError{
+			Message: fmt.Sprintf("max_moves %d is below the %d moves every solution takes", lvl.MaxMoves, minMoves),
+		})
+	}
+	return errors
+}
+
+// validateHints checks that the level's hints name distinct vines that can be
+// cleared one after another, in the listed order, from the starting grid.
+func validateHints(lvl model.Level) []error {
+	if len(lvl.Hints) == 0 {
+		return nil
+	}
+	if len(lvl.Hints) > len(lvl.Vines) {
+		return []error{StructuralError{
+			Message: fmt.Sprintf("%d hints for %d vines", len(lvl.Hints), len(lvl.Vines)),
+		}}
+	}
+
+	if validateBounds(lvl) != nil {
+		return nil // already reported; the clear order cannot be simulated
+	}
+	if err := replayClearOrder(lvl, lvl.Hints, "hint"); err != nil {
+		return []error{err}
+	}
+	return nil
+}
+
+// validateSolutionOrder checks that an embedded solution_order clears every vine.
+func validateSolutionOrder(lvl model.Level) []error {
+	if len(lvl.SolutionOrder) == 0 || validateBounds(lvl) != nil {
+		return nil
+	}
+	if err := VerifySolutionOrder(lvl, lvl.SolutionOrder); err != nil {
+		return []error{err}
+	}
+	return nil
+}
+
+// VerifySolutionOrder reports whether order, a list of vine IDs, clears every vine of lvl one
+// after another from the starting grid. Replaying an order is linear in the level, so a level
+// carrying its solution_order is checked without a search.
+func VerifySolutionOrder(lvl model.Level, order []string) error {
+	if err := checkGridSize(lvl); err != nil {
+		return err
+	}
+	if errs := validateBounds(lvl); len(errs) > 0 {
+		return errs[0]
+	}
+	if len(order) != len(lvl.Vines) {
+		return StructuralError{Message: fmt.Sprintf("solution_order clears %d of %d vines", len(order), len(lvl.Vines))}
+	}
+	return replayClearOrder(lvl, order, "solution step")
+}
+
+// replayClearOrder clears the vines order names one after another from the starting grid and
+// reports the first that is unknown, repeated, locked or blocked. step names an entry of order
+// in the message. Every vine cell must lie inside the grid.
+func replayClearOrder(lvl model.Level, order []string, step string) error {
+	index := make(map[string]int, len(lvl.Vines))
+	for i, v := range lvl.Vines {
+		index[v.ID] = i
+	}
+	vineIndices := vineCellIndices(lvl)
+	occupied := make([]bool, lvl.GridSize[0]*lvl.GridSize[1])
+	for _, cells := range vineIndices {
+		for _, idx := range cells {
+			occupied[idx] = true
 		}
 	}
 
+	seen := make(map[string]bool, len(order))
+	for n, id := range order {
+		i, ok := index[id]
+		if !ok {
+			return StructuralError{Message: fmt.Sprintf("%s %d names unknown vine %s", step, n+1, id)}
+		}
+		if seen[id] {
+			return StructuralError{VineID: id, Message: fmt.Sprintf("%s %d repeats an earlier %s", step, n+1, step)}
+		}
+		seen[id] = true
+		if len(vineIndices[i]) == 0 || lvl.Vines[i].IsLocked(n) || !canVineClearFast(lvl, i, occupied, vineIndices[i]) {
+			return StructuralError{VineID: id, Message: fmt.Sprintf("%s %d cannot clear after the %ss before it", step, n+1, step)}
+		}
+		for _, idx := range vineIndices[i] {
+			occupied[idx] = false
+		}
+	}
 	return nil
 }
 
 // vineBlocksVine checks if blocker prevents blocked from moving.
-// Blocked vine is blocked if the cell it would move into is occupied by blocker.
-func vineBlocksVine(blocker, blocked model.Vine, occupied map[string]string) bool {
+// Blocked vine is blocked if the cell it would move into (past the twin when its
+// head faces a portal) is occupied by blocker; a multi-head vine only when blocker
+// is in front of both heads.
+func vineBlocksVine(lvl *model.Level, blocker, blocked model.Vine, occupied map[string]string) bool {
 	if len(blocked.OrderedPath) == 0 {
 		return false
 	}
+	if blocked.IsMultiHead() {
+		return headBlockedBy(lvl, blocker, blocked, occupied) && headBlockedBy(lvl, blocker, blocked.Reversed(), occupied)
+	}
+	return headBlockedBy(lvl, blocker, blocked, occupied)
+}
 
-	head := blocked.OrderedPath[0]
-	targetX, targetY := head.X, head.Y
-
+// headBlockedBy checks whether blocker occupies the cell blocked's head would move into.
+func headBlockedBy(lvl *model.Level, blocker, blocked model.Vine, occupied map[string]string) bool {
 	// Calculate where blocked vine's head would move
-	switch blocked.HeadDirection {
-	case "right":
-		targetX++
-	case "left":
-		targetX--
-	case "up":
-		targetY++
-	case "down":
-		targetY--
-	default:
-		return false
+	target, inGrid, _ := lvl.NextCell(blocked.OrderedPath[0], blocked.HeadDirection)
+	if !inGrid {
+		return false // exits, or is trapped by portals rather than by a vine
 	}
 
-	key := fmt.Sprintf("%d,%d", targetX, targetY)
+	key := fmt.Sprintf("%d,%d", target.X, target.Y)
 	return occupied[key] == blocker.ID
 }
 
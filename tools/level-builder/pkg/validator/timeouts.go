@@ -0,0 +1,63 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type timeoutsKey struct{}
+
+type timeouts struct {
+	perLevel, total time.Duration
+}
+
+// Causes recorded on contexts whose time budget ran out, telling a timeout apart from an
+// interrupt.
+var (
+	errLevelTimeout = errors.New("per-level timeout")
+	errTotalTimeout = errors.New("total timeout")
+)
+
+// WithTimeouts returns a context under which each level's solvability check runs for at most
+// perLevel and a ValidateReport run for at most total; 0 leaves either unbounded. A check that
+// runs out of time is reported as timed out rather than unsolvable, and the levels a run has
+// not reached when its total budget runs out are reported as skipped after their structural
+// checks, so the report still covers every level.
+func WithTimeouts(ctx context.Context, perLevel, total time.Duration) context.Context {
+	return context.WithValue(ctx, timeoutsKey{}, timeouts{perLevel: perLevel, total: total})
+}
+
+func timeoutsFrom(ctx context.Context) timeouts {
+	t, _ := ctx.Value(timeoutsKey{}).(timeouts)
+	return t
+}
+
+// withTotalTimeout bounds a whole validation run by ctx's total budget.
+func withTotalTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if t := timeoutsFrom(ctx); t.total > 0 {
+		return context.WithTimeoutCause(ctx, t.total, fmt.Errorf("%w of %s", errTotalTimeout, t.total))
+	}
+	return context.WithCancel(ctx)
+}
+
+// withLevelTimeout bounds one solvability check by ctx's per-level budget.
+func withLevelTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if t := timeoutsFrom(ctx); t.perLevel > 0 {
+		return context.WithTimeoutCause(ctx, t.perLevel, fmt.Errorf("%w of %s", errLevelTimeout, t.perLevel))
+	}
+	return context.WithCancel(ctx)
+}
+
+// timeoutCause returns the budget that stopped ctx, or nil when ctx is still live or was
+// cancelled for another reason.
+func timeoutCause(ctx context.Context) error {
+	if ctx.Err() == nil {
+		return nil
+	}
+	if cause := context.Cause(ctx); errors.Is(cause, errLevelTimeout) || errors.Is(cause, errTotalTimeout) {
+		return cause
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateLevelPerLevelTimeout(t *testing.T) {
+	lvl := baseFullGridLevel()
+	ctx := WithTimeouts(context.Background(), time.Nanosecond, 0)
+	time.Sleep(time.Millisecond)
+
+	result, ok, err := ValidateLevel(ctx, &lvl, true, 1000, true)
+	if err != nil || !ok {
+		t.Fatalf("ValidateLevel = %v, %v", ok, err)
+	}
+	s := result.Solvability
+	if result.Error != "" || s == nil {
+		t.Fatalf("expected a solvability result, got %+v", result)
+	}
+	if !s.TimedOut || s.Solvable || !strings.Contains(s.Error, "per-level timeout") {
+		t.Errorf("stat = %+v, want timed out by the per-level timeout", s)
+	}
+	if result.Deadlock != nil {
+		t.Error("a timed-out level must not be diagnosed")
+	}
+}
+
+func TestReportPartialAfterTimeouts(t *testing.T) {
+	r := Report{
+		CheckSolvable:   true,
+		BudgetExhausted: true,
+		Levels: []LevelResult{
+			{File: "level_1.json", LevelID: 1, Solvability: &LevelStat{LevelID: 1, Solvable: true, Solver: "greedy-fast"}},
+			{File: "level_2.json", LevelID: 2, Solvability: &LevelStat{LevelID: 2, Solver: "exact-astar", GaveUp: true, TimedOut: true,
+				Error: "solver stopped by the per-level timeout of 1s"}},
+			{File: "level_3.json", LevelID: 3, Skipped: true},
+		},
+	}
+	r.finish()
+	if r.Passed != 1 || r.Unsolvable != 1 || r.TimedOut != 1 || r.Skipped != 1 {
+		t.Errorf("unexpected counts: %+v", r)
+	}
+	if err := r.Err(); err == nil {
+		t.Error("a report with failures and skipped levels must fail")
+	}
+	if err := (Report{Skipped: 2}).Err(); err == nil || !strings.Contains(err.Error(), "2 levels not checked") {
+		t.Errorf("Err() = %v, want skipped levels reported", err)
+	}
+
+	var buf bytes.Buffer
+	r.WriteText(&buf)
+	for _, want := range []string{"per-level timeout of 1s", "solvability not checked for 1 levels", "  • level_3.json", "1 skipped"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("text report missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := r.WriteJUnit(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var suites junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatal(err)
+	}
+	cases := suites.Suites[1].Cases
+	if f := cases[1].Failure; f == nil || !strings.Contains(f.Message, "timeout") {
+		t.Errorf("level_2.json failure = %+v, want a timeout", f)
+	}
+	if cases[2].Skipped == nil || cases[2].Failure != nil {
+		t.Errorf("level_3.json = %+v, want skipped", cases[2])
+	}
+}
@@ -5,9 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/schema"
 )
 
 // Path resolution uses common.LessonsDir() - no hardcoded paths
@@ -31,19 +36,13 @@ func ValidateTutorials(checkSolvable bool, maxStates int) error {
 
 	var lessonStats []LevelStat
 	for _, f := range files {
-		if err := validateLessonFile(f); err != nil {
+		lesson, err := validateLessonFile(f)
+		if err != nil {
 			return fmt.Errorf("lesson %s validation failed: %w", filepath.Base(f), err)
 		}
 
 		if checkSolvable {
-			bytes, err := os.ReadFile(f)
-			if err != nil {
-				return err
-			}
-			var lvl model.Level
-			if err := json.Unmarshal(bytes, &lvl); err != nil {
-				return err
-			}
+			lvl := lesson.Level()
 
 			ok, stats, err := IsSolvableWithOptions(lvl, maxStates, true, DefaultAStarWeight)
 			ls := LevelStat{
@@ -84,29 +83,65 @@ func ValidateTutorials(checkSolvable bool, maxStates int) error {
 	return nil
 }
 
-func validateLessonFile(path string) error {
+// Lesson text limits, matching LessonData.fromJson in the game.
+const (
+	MaxLessonTitle        = 80
+	MaxLessonObjective    = 120
+	MaxLessonInstructions = 200
+	MaxLearningPoint      = 80
+	MinLearningPoints     = 2
+)
+
+// MaxGuidedLessonVines is the most vines a guided (stepped) lesson may have;
+// a lesson teaches one idea, and more vines than this bury it.
+const MaxGuidedLessonVines = 3
+
+// textKeyPattern matches the camelCase keys of the game's ARB files.
+var textKeyPattern = regexp.MustCompile(`^[a-z][A-Za-z0-9]*$`)
+
+func validateLessonFile(path string) (model.Lesson, error) {
+	var lesson model.Lesson
 	bytes, err := os.ReadFile(path)
 	if err != nil {
-		return err
+		return lesson, err
 	}
-	var lvl model.Level
-	if err := json.Unmarshal(bytes, &lvl); err != nil {
-		return err
+	if errs := schema.Lesson().Validate(bytes); len(errs) > 0 {
+		return lesson, schemaError(errs)
+	}
+	if err := json.Unmarshal(bytes, &lesson); err != nil {
+		return lesson, err
 	}
 
-	// 1. Check ID matches filename
+	// Check ID matches filename
 	base := filepath.Base(path)
-	expectedName := fmt.Sprintf("lesson_%d.json", lvl.ID)
+	expectedName := fmt.Sprintf("lesson_%d.json", lesson.ID)
 	if base != expectedName {
-		return fmt.Errorf("filename %s does not match ID %d", base, lvl.ID)
+		return lesson, fmt.Errorf("filename %s does not match ID %d", base, lesson.ID)
+	}
+
+	if err := ValidateLesson(lesson); err != nil {
+		return lesson, fmt.Errorf("%s: %w", base, err)
 	}
+	return lesson, nil
+}
+
+// ValidateLesson applies the relaxed lesson rules: a grid of at least 2x2,
+// in-bounds vines that do not overlap (sparse grids are fine), color indices
+// within color_scheme when one is given, max_moves >= 1, the instructional
+// text the game requires and the lesson metadata (see validateLessonMetadata
+// and validateLessonSteps).
+func ValidateLesson(lesson model.Lesson) error {
+	lvl := lesson.Level()
 
-	// 2. Check Grid Size (>=2x2)
-	if len(lvl.GridSize) != 2 || lvl.GridSize[0] < 2 || lvl.GridSize[1] < 2 {
+	// 1. Check Grid Size (>=2x2, sides at most MaxGridSide)
+	if err := checkGridSize(lvl); err != nil {
+		return err
+	}
+	if lvl.GridSize[0] < 2 || lvl.GridSize[1] < 2 {
 		return fmt.Errorf("invalid grid size")
 	}
 
-	// 3. In-bounds and no overlaps (relaxed occupancy: not required to be 100%)
+	// 2. In-bounds and no overlaps (relaxed occupancy: not required to be 100%)
 	w, h := lvl.GridSize[0], lvl.GridSize[1]
 	seen := make(map[int]bool)
 	for _, v := range lvl.Vines {
@@ -122,7 +157,7 @@ func validateLessonFile(path string) error {
 		}
 	}
 
-	// 4. Colors: if color_scheme present, enforce bounds; else skip
+	// 3. Colors: if color_scheme present, enforce bounds; else skip
 	if len(lvl.ColorScheme) > 0 {
 		for _, v := range lvl.Vines {
 			if v.ColorIndex >= len(lvl.ColorScheme) {
@@ -131,10 +166,142 @@ func validateLessonFile(path string) error {
 		}
 	}
 
-	// 5. Structure: require max_moves >= 1.
+	// 4. Structure: require max_moves >= 1.
 	if lvl.MaxMoves < 1 {
-		return fmt.Errorf("missing or invalid max_moves in %s", base)
+		return fmt.Errorf("missing or invalid max_moves")
 	}
 
+	// 5. Instructional text, trimmed as the game reads it
+	type text struct {
+		field, value string
+		max          int
+	}
+	texts := []text{
+		{"title", lesson.Title, MaxLessonTitle},
+		{"objective", lesson.Objective, MaxLessonObjective},
+		{"instructions", lesson.Instructions, MaxLessonInstructions},
+	}
+	for _, p := range lesson.LearningPoints {
+		texts = append(texts, text{"learning_point", p, MaxLearningPoint})
+	}
+	for _, t := range texts {
+		if n := utf8.RuneCountInString(strings.TrimSpace(t.value)); n == 0 || n > t.max {
+			return fmt.Errorf("%s must be 1..%d chars, got %d", t.field, t.max, n)
+		}
+	}
+	if len(lesson.LearningPoints) < MinLearningPoints {
+		return fmt.Errorf("learning_points must contain at least %d items", MinLearningPoints)
+	}
+
+	// 6. Metadata and step gating
+	if err := validateLessonMetadata(lesson); err != nil {
+		return err
+	}
+	return validateLessonSteps(lesson)
+}
+
+// validateLessonMetadata checks the text keys, that allowed_interactions
+// names known interactions and includes tap, and that highlight_vines names
+// distinct vines of the lesson.
+func validateLessonMetadata(lesson model.Lesson) error {
+	keys := []struct{ field, key string }{
+		{"text_keys.title", lesson.TextKeys.Title},
+		{"text_keys.objective", lesson.TextKeys.Objective},
+		{"text_keys.instructions", lesson.TextKeys.Instructions},
+	}
+	for i, step := range lesson.Steps {
+		if step.TextKey != "" {
+			keys = append(keys, struct{ field, key string }{fmt.Sprintf("steps[%d].text_key", i), step.TextKey})
+		}
+	}
+	for _, k := range keys {
+		if !textKeyPattern.MatchString(k.key) {
+			return fmt.Errorf("%s %q is not a camelCase text key", k.field, k.key)
+		}
+	}
+
+	allowed := make(map[string]bool, len(lesson.AllowedInteractions))
+	for _, in := range lesson.AllowedInteractions {
+		if !slices.Contains(model.LessonInteractions, in) {
+			return fmt.Errorf("unknown interaction %q (want one of %s)", in, strings.Join(model.LessonInteractions, ", "))
+		}
+		if allowed[in] {
+			return fmt.Errorf("interaction %q listed twice", in)
+		}
+		allowed[in] = true
+	}
+	if !allowed[model.InteractionTap] {
+		return fmt.Errorf("allowed_interactions must include %q", model.InteractionTap)
+	}
+
+	ids := make(map[string]bool, len(lesson.Vines))
+	for _, v := range lesson.Vines {
+		ids[v.ID] = true
+	}
+	highlighted := make(map[string]bool, len(lesson.HighlightVines))
+	for _, id := range lesson.HighlightVines {
+		if !ids[id] {
+			return fmt.Errorf("highlight_vines names unknown vine %s", id)
+		}
+		if highlighted[id] {
+			return fmt.Errorf("highlight_vines lists %s twice", id)
+		}
+		highlighted[id] = true
+	}
+	return nil
+}
+
+// validateLessonSteps checks a guided lesson: at most MaxGuidedLessonVines
+// vines, one step per vine, and a forced solution order. Replaying the steps,
+// each step's vine must be the only vine that can clear, so the lesson never
+// leaves the player a choice the step gate would refuse. Free-play lessons
+// (no steps) are not checked.
+func validateLessonSteps(lesson model.Lesson) error {
+	if !lesson.Guided() {
+		return nil
+	}
+	if len(lesson.Vines) > MaxGuidedLessonVines {
+		return fmt.Errorf("guided lesson has %d vines, at most %d allowed", len(lesson.Vines), MaxGuidedLessonVines)
+	}
+	if len(lesson.Steps) != len(lesson.Vines) {
+		return fmt.Errorf("guided lesson has %d steps for %d vines", len(lesson.Steps), len(lesson.Vines))
+	}
+
+	lvl := lesson.Level()
+	index := make(map[string]int, len(lvl.Vines))
+	for i, v := range lvl.Vines {
+		index[v.ID] = i
+	}
+	vineIndices := vineCellIndices(lvl)
+	occupied := make([]bool, lvl.GridSize[0]*lvl.GridSize[1])
+	for _, cells := range vineIndices {
+		for _, idx := range cells {
+			occupied[idx] = true
+		}
+	}
+
+	cleared := make(map[int]bool, len(lvl.Vines))
+	for n, step := range lesson.Steps {
+		want, ok := index[step.Vine]
+		if !ok {
+			return fmt.Errorf("step %d names unknown vine %s", n+1, step.Vine)
+		}
+		if cleared[want] {
+			return fmt.Errorf("step %d repeats vine %s", n+1, step.Vine)
+		}
+		var clearable []string
+		for i, v := range lvl.Vines {
+			if !cleared[i] && len(vineIndices[i]) > 0 && canVineClearFast(lvl, i, occupied, vineIndices[i]) {
+				clearable = append(clearable, v.ID)
+			}
+		}
+		if len(clearable) != 1 || clearable[0] != step.Vine {
+			return fmt.Errorf("step %d expects %s, but the clearable vines are %v; the order must be forced", n+1, step.Vine, clearable)
+		}
+		cleared[want] = true
+		for _, idx := range vineIndices[want] {
+			occupied[idx] = false
+		}
+	}
 	return nil
 }
@@ -0,0 +1,96 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+)
+
+// guidedLesson is a 5x5 lesson where vine_1 points into vine_2, so vine_2
+// must clear first.
+func guidedLesson() model.Lesson {
+	return model.Lesson{
+		ID:             3,
+		Title:          "Lesson 3: Blocking",
+		Objective:      "Clear the blocker first",
+		Instructions:   "One vine blocks another.",
+		LearningPoints: []string{"Look where heads point", "Clear the blocker first"},
+		TextKeys:       model.LessonTextKeys{Title: "lesson3Title", Objective: "lesson3Objective", Instructions: "lesson3Instructions"},
+		GridSize:       []int{5, 5},
+		MaxMoves:       999,
+		Vines: []model.Vine{
+			{ID: "vine_1", HeadDirection: "right", OrderedPath: []model.Point{{X: 3, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 1}}},
+			{ID: "vine_2", HeadDirection: "down", OrderedPath: []model.Point{{X: 4, Y: 0}, {X: 4, Y: 1}, {X: 4, Y: 2}}},
+		},
+		HighlightVines:      []string{"vine_2"},
+		AllowedInteractions: []string{"tap", "restart"},
+		Steps:               []model.LessonStep{{Vine: "vine_2", TextKey: "lesson3Step1"}, {Vine: "vine_1"}},
+	}
+}
+
+func TestValidateLessonMetadata(t *testing.T) {
+	if err := ValidateLesson(guidedLesson()); err != nil {
+		t.Fatalf("valid lesson rejected: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		edit func(l *model.Lesson)
+		want string
+	}{
+		{"missing text key", func(l *model.Lesson) { l.TextKeys.Objective = "" }, "text_keys.objective"},
+		{"malformed step key", func(l *model.Lesson) { l.Steps[0].TextKey = "lesson.3.step" }, "steps[0].text_key"},
+		{"no tap", func(l *model.Lesson) { l.AllowedInteractions = []string{"hint"} }, `must include "tap"`},
+		{"unknown interaction", func(l *model.Lesson) { l.AllowedInteractions = []string{"tap", "swipe"} }, `unknown interaction "swipe"`},
+		{"unknown highlight", func(l *model.Lesson) { l.HighlightVines = []string{"vine_9"} }, "unknown vine vine_9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := guidedLesson()
+			tt.edit(&l)
+			if err := ValidateLesson(l); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateLessonSteps(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(l *model.Lesson)
+		want string
+	}{
+		{"blocked vine first", func(l *model.Lesson) {
+			l.Steps = []model.LessonStep{{Vine: "vine_1"}, {Vine: "vine_2"}}
+		}, "step 1 expects vine_1"},
+		{"order not forced", func(l *model.Lesson) {
+			// vine_1 now faces away from vine_2, so either can go first
+			l.Vines[0] = model.Vine{ID: "vine_1", HeadDirection: "left", OrderedPath: []model.Point{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1}}}
+		}, "the order must be forced"},
+		{"step missing", func(l *model.Lesson) { l.Steps = l.Steps[:1] }, "1 steps for 2 vines"},
+		{"too many vines", func(l *model.Lesson) {
+			for i := 0; i < MaxGuidedLessonVines; i++ {
+				l.Vines = append(l.Vines, model.Vine{ID: "extra", HeadDirection: "up", OrderedPath: []model.Point{{X: i, Y: 4}, {X: i, Y: 3}}})
+			}
+		}, "at most 3 allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := guidedLesson()
+			tt.edit(&l)
+			if err := ValidateLesson(l); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+
+	// Free-play lessons may be larger and leave the order open
+	l := guidedLesson()
+	l.Steps = nil
+	l.Vines[0] = model.Vine{ID: "vine_1", HeadDirection: "left", OrderedPath: []model.Point{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1}}}
+	if err := ValidateLesson(l); err != nil {
+		t.Errorf("free-play lesson rejected: %v", err)
+	}
+}
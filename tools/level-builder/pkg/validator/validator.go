@@ -1,16 +1,19 @@
 package validator
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
-	"runtime"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/config"
 	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/schema"
 )
 
 // SolverVersion is incremented when validator rules or search logic change,
@@ -32,72 +35,139 @@ type LevelStat struct {
 	MaxStates      int    `json:"max_states"`
 	TimeMs         int64  `json:"time_ms"`
 	GaveUp         bool   `json:"gave_up"`
+	TimedOut       bool   `json:"timed_out,omitempty"` // stopped by a per-level or total timeout
+	// SolutionLength is the number of moves in the solution the solver found;
+	// zero when none was found or the verdict came from the cache
+	SolutionLength int    `json:"solution_length,omitempty"`
+	OverBudget     bool   `json:"over_budget,omitempty"` // solvable, but not within max_moves
 	Error          string `json:"error,omitempty"`
 }
 
 // Validate validates the level builder's modules and level files, and optionally runs solvability checks.
 //
-// When checkSolvable is false, Validate only validates modules and parses all level files matching
-// LevelsDir/level_*.json, returning an error on the first failure. When checkSolvable is true, it
-// additionally runs solvability checks for each parsed level by calling IsSolvableWithStats with the
-// provided maxStates budget. Solvability checks are executed concurrently (bounded by runtime.NumCPU).
+// It runs ValidateReport, prints the report as text to stdout and returns Report.Err, so the caller
+// sees a non-nil error when any module, structural or solvability check fails.
+func Validate(ctx context.Context, checkSolvable bool, maxStates int, useAstar bool, astarWeight int, ignoreOccupancy bool) error {
+	report, err := ValidateReport(ctx, checkSolvable, maxStates, useAstar, astarWeight, ignoreOccupancy)
+	if err != nil {
+		return err
+	}
+	report.WriteText(os.Stdout)
+	return report.Err()
+}
+
+// ValidateReport validates modules and every level file matching LevelsDir/level_*.json and returns
+// the outcome as a Report instead of printing it.
 //
-// For each level, Validate records a LevelStat (including fields such as LevelID, File, Solver,
-// StatesExplored, TimeMs, MaxStates, Solvable, GaveUp and any Error string), prints a per-level summary to
-// stdout, and writes all collected stats to validation_stats.json in the current working directory. A level
-// that reports GaveUp is treated as not solvable under the given budget. If any module validation or level
-// parsing fails, or if one or more levels are determined not solvable, Validate returns a non-nil error
-// (for unsolvable levels the error includes the count of such levels). On success it prints a confirmation
-// message and returns nil.
+// Module validation failures stop the run and are recorded in Report.ModuleError. Otherwise every
+// level gets a LevelResult; structural errors are collected rather than failing fast. When
+// checkSolvable is true, each structurally valid level is also checked with IsSolvableWithStats under
+// the maxStates budget, concurrently (bounded by WithWorkers, default runtime.NumCPU) and through
+// the validation cache. A level that reports GaveUp is treated as not solvable under the given
+// budget. The solver stats are also written to validation_stats.json in the logs directory,
+// recorded in Report.StatsPath.
 //
-// Note: this function has side effects (printing to stdout and writing validation_stats.json) and performs
-// concurrent work that blocks until all checks complete.
-func Validate(checkSolvable bool, maxStates int, useAstar bool, astarWeight int, ignoreOccupancy bool) error {
+// The returned error is reserved for problems running the validation itself; failed checks are
+// reported through Report.Err. Cancelling ctx stops in-flight solver searches and skips levels not
+// yet checked; results gathered so far are still cached and the ctx error is returned. Timeouts set
+// with WithTimeouts do not interrupt the run: they end up in the report as timed-out and skipped
+// levels. Warnings pass unless ctx comes from WithStrict, which promotes them to errors.
+func ValidateReport(ctx context.Context, checkSolvable bool, maxStates int, useAstar bool, astarWeight int, ignoreOccupancy bool) (Report, error) {
+	report := Report{CheckSolvable: checkSolvable, Strict: strictFrom(ctx)}
+
 	// 1. Validate Modules
 	if err := validateModules(); err != nil {
-		return fmt.Errorf("module validation failed: %w", err)
+		report.ModuleError = err.Error()
+		return report, nil
 	}
 
 	// 2. Resolve levels directory
 	levelsDir, err := common.LevelsDir()
 	if err != nil {
-		return fmt.Errorf("failed to resolve levels directory: %w", err)
+		return report, fmt.Errorf("failed to resolve levels directory: %w", err)
 	}
 
 	// 3. Validate Levels
 	files, err := filepath.Glob(filepath.Join(levelsDir, "level_*.json"))
 	if err != nil {
-		return err
+		return report, err
 	}
+	return validateFiles(ctx, report, files, checkSolvable, maxStates, useAstar, astarWeight, ignoreOccupancy)
+}
 
-	if !checkSolvable {
-		// Collect all validation errors instead of failing fast
-		type ValidationError struct {
-			File  string
-			Error string
-		}
-		var validationErrors []ValidationError
+// ValidateFiles validates the given level files like ValidateReport validates the levels
+// directory, through the validation cache, without checking modules.json. Use SelectLevelFiles
+// to pick the files by level ID or glob pattern.
+func ValidateFiles(ctx context.Context, files []string, checkSolvable bool, maxStates int, useAstar bool, astarWeight int, ignoreOccupancy bool) (Report, error) {
+	report := Report{CheckSolvable: checkSolvable, Strict: strictFrom(ctx)}
+	return validateFiles(ctx, report, files, checkSolvable, maxStates, useAstar, astarWeight, ignoreOccupancy)
+}
 
-		for _, f := range files {
-			if _, err := readLevelFile(f, ignoreOccupancy); err != nil {
-				validationErrors = append(validationErrors, ValidationError{
-					File:  filepath.Base(f),
-					Error: err.Error(),
-				})
+// SelectLevelFiles returns the level files named by ids (level_<id>.json in levelsDir) and by
+// patterns, each a file path or a filepath.Match glob such as "assets/levels/level_4*.json",
+// in order and without duplicates. A missing ID or a glob matching nothing is an error, so a
+// typo is not mistaken for a clean run; a plain path that does not exist is left for
+// validation to report.
+func SelectLevelFiles(levelsDir string, ids []int, patterns []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	add := func(f string) {
+		if key := filepath.Clean(f); !seen[key] {
+			seen[key] = true
+			files = append(files, f)
+		}
+	}
+	for _, id := range ids {
+		f := common.GetLevelFilePath(id, levelsDir)
+		if _, err := os.Stat(f); err != nil {
+			return nil, fmt.Errorf("level %d: %w", id, err)
+		}
+		add(f)
+	}
+	for _, p := range patterns {
+		matches, err := filepath.Glob(p)
+		if err != nil {
+			return nil, fmt.Errorf("bad pattern %q: %w", p, err)
+		}
+		switch {
+		case len(matches) > 0:
+			for _, m := range matches {
+				add(m)
 			}
+		case strings.ContainsAny(p, "*?["):
+			return nil, fmt.Errorf("no level files match %q", p)
+		default:
+			add(p)
 		}
+	}
+	return files, nil
+}
 
-		if len(validationErrors) > 0 {
-			fmt.Printf("\n❌ Validation failed for %d levels:\n\n", len(validationErrors))
-			for _, ve := range validationErrors {
-				fmt.Printf("  • %s: %s\n", ve.File, ve.Error)
+type workersKey struct{}
+
+// WithWorkers returns a context under which ValidateReport and ValidateFiles check at most n
+// levels' solvability at once. n <= 0 uses runtime.NumCPU.
+func WithWorkers(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, workersKey{}, n)
+}
+
+func workersFrom(ctx context.Context) int {
+	n, _ := ctx.Value(workersKey{}).(int)
+	return common.WorkerCount(n)
+}
+
+// validateFiles adds a LevelResult for each of files to report, as described on ValidateReport.
+func validateFiles(ctx context.Context, report Report, files []string, checkSolvable bool, maxStates int, useAstar bool, astarWeight int, ignoreOccupancy bool) (Report, error) {
+	if !checkSolvable {
+		for _, f := range files {
+			if err := ctx.Err(); err != nil {
+				return report, err
 			}
-			fmt.Printf("\nTotal: %d/%d levels passed validation\n", len(files)-len(validationErrors), len(files))
-			return fmt.Errorf("%d levels failed validation", len(validationErrors))
+			result, _ := validateLevelFile(ctx, f, nil, false, maxStates, useAstar, astarWeight, ignoreOccupancy)
+			report.Levels = append(report.Levels, result)
 		}
-
-		fmt.Printf("✓ All %d levels and modules validated successfully.\n", len(files))
-		return nil
+		report.finish()
+		return report, nil
 	}
 
 	// If we reach here, we need to run solvability checks and collect stats.
@@ -107,15 +177,14 @@ func Validate(checkSolvable bool, maxStates int, useAstar bool, astarWeight int,
 		cache = NewValidationCache()
 	}
 
-	concurrency := runtime.NumCPU()
+	// Levels not started when the total budget runs out are still checked structurally
+	runCtx, cancel := withTotalTimeout(ctx)
+	defer cancel()
+
+	concurrency := workersFrom(ctx)
 	sem := make(chan struct{}, concurrency)
 	var wg sync.WaitGroup
-	statsCh := make(chan LevelStat, len(files))
-	type ValidationError struct {
-		File  string
-		Error string
-	}
-	errCh := make(chan ValidationError, len(files))
+	resultCh := make(chan LevelResult, len(files))
 
 	for _, f := range files {
 		f := f
@@ -124,89 +193,42 @@ func Validate(checkSolvable bool, maxStates int, useAstar bool, astarWeight int,
 			defer wg.Done()
 			sem <- struct{}{}
 			defer func() { <-sem }()
-
-			fileBytes, rerr := os.ReadFile(f)
-			if rerr != nil {
-				errCh <- ValidationError{
-					File:  filepath.Base(f),
-					Error: fmt.Errorf("failed to read file bytes: %w", rerr).Error(),
-				}
+			if ctx.Err() != nil {
 				return
 			}
-
-			lvl, err := readLevelFile(f, ignoreOccupancy)
-			if err != nil {
-				errCh <- ValidationError{
-					File:  filepath.Base(f),
-					Error: err.Error(),
-				}
+			if runCtx.Err() != nil {
+				result, _ := validateLevelFile(ctx, f, nil, false, maxStates, useAstar, astarWeight, ignoreOccupancy)
+				result.Skipped = result.Error == ""
+				resultCh <- result
 				return
 			}
 
-			// Cache lookup
-			levelKey := filepath.Base(f)
-			if hit, solvable := cache.Lookup(levelKey, fileBytes, SolverVersion); hit {
-				statsCh <- LevelStat{
-					File:           f,
-					LevelID:        lvl.ID,
-					Solvable:       solvable,
-					Solver:         "cached",
-					StatesExplored: 0,
-					MaxStates:      maxStates,
-					TimeMs:         0,
-					GaveUp:         false,
-				}
-				return
+			if result, ok := validateLevelFile(runCtx, f, cache, true, maxStates, useAstar, astarWeight, ignoreOccupancy); ok {
+				resultCh <- result
 			}
-
-			start := time.Now()
-			ok, stat, serr := IsSolvableWithStats(lvl, maxStates, useAstar, astarWeight)
-			dur := time.Since(start)
-			stat.TimeMs = dur.Milliseconds()
-			stat.File = f
-			stat.LevelID = lvl.ID
-			stat.MaxStates = maxStates
-			stat.Solvable = ok
-			if serr != nil {
-				stat.Error = serr.Error()
-			}
-
-			if stat.GaveUp {
-				// mark as not solvable under budget
-				stat.Solvable = false
-			}
-
-			// Update cache
-			cache.Update(levelKey, fileBytes, SolverVersion, stat.Solvable)
-
-			statsCh <- stat
 		}()
 	}
 
 	wg.Wait()
-	close(statsCh)
-	close(errCh)
+	close(resultCh)
 
 	// Save cache atomically
 	if serr := cache.SaveCache(); serr != nil {
 		common.Warning("Failed to save validation cache: %v", serr)
 	}
-
-	// Collect validation errors
-	var validationErrors []ValidationError
-	for ve := range errCh {
-		validationErrors = append(validationErrors, ve)
+	if err := ctx.Err(); err != nil {
+		return report, fmt.Errorf("validation interrupted: %w", err)
 	}
 
-	// Collect stats
-	var allStats []LevelStat
-	unsolvable := []LevelStat{}
-	for s := range statsCh {
-		allStats = append(allStats, s)
-		fmt.Printf("Level %d (%s): solvable=%v solver=%s states=%d time=%dms gave_up=%v\n",
-			s.LevelID, filepath.Base(s.File), s.Solvable, s.Solver, s.StatesExplored, s.TimeMs, s.GaveUp)
-		if !s.Solvable {
-			unsolvable = append(unsolvable, s)
+	report.BudgetExhausted = timeoutCause(runCtx) != nil
+	allStats := []LevelStat{}
+	for r := range resultCh {
+		report.Levels = append(report.Levels, r)
+	}
+	report.finish()
+	for _, r := range report.Levels {
+		if r.Solvability != nil {
+			allStats = append(allStats, *r.Solvability)
 		}
 	}
 
@@ -216,38 +238,197 @@ func Validate(checkSolvable bool, maxStates int, useAstar bool, astarWeight int,
 	if err == nil {
 		if err := os.MkdirAll(logsDir, 0o755); err == nil {
 			statsPath := filepath.Join(logsDir, "validation_stats.json")
-			_ = os.WriteFile(statsPath, b, 0o644)
-			fmt.Printf("\n✓ Detailed results written to %s\n", statsPath)
+			if err := os.WriteFile(statsPath, b, 0o644); err == nil {
+				report.StatsPath = statsPath
+			}
 		}
 	}
 
-	// Print summary of all issues
-	hasErrors := false
-	if len(validationErrors) > 0 {
-		hasErrors = true
-		fmt.Printf("\n❌ Structural validation failed for %d levels:\n\n", len(validationErrors))
-		for _, ve := range validationErrors {
-			fmt.Printf("  • %s: %s\n", ve.File, ve.Error)
+	return report, nil
+}
+
+// ValidateFile validates a single level file like ValidateReport does, without modules or the
+// validation cache. ok is false when ctx was cancelled before the result was complete.
+func ValidateFile(ctx context.Context, path string, checkSolvable bool, maxStates int, useAstar bool, astarWeight int, ignoreOccupancy bool) (result LevelResult, ok bool) {
+	return validateLevelFile(ctx, path, nil, checkSolvable, maxStates, useAstar, astarWeight, ignoreOccupancy)
+}
+
+// ValidateInput validates one level like ValidateFile and reports it like ValidateReport,
+// without modules or the validation cache. path is a level file or common.StdioPath to read
+// the level from stdin; a piped level is checked under the file name its ID implies and
+// reported as "stdin". ok is false when ctx was cancelled.
+func ValidateInput(ctx context.Context, path string, checkSolvable bool, maxStates int, useAstar bool, astarWeight int, ignoreOccupancy bool) (report Report, ok bool, err error) {
+	report = Report{CheckSolvable: checkSolvable, Strict: strictFrom(ctx)}
+	file, label := path, ""
+	if path == common.StdioPath {
+		data, err := common.ReadInput(path)
+		if err != nil {
+			return report, false, fmt.Errorf("failed to read stdin: %w", err)
+		}
+		// The ID only names the scratch file; readLevelFile reports bad JSON
+		var head struct {
+			ID int `json:"id"`
+		}
+		_ = json.Unmarshal(data, &head)
+		tmp, err := os.MkdirTemp("", "level-validate-")
+		if err != nil {
+			return report, false, err
+		}
+		defer func() { _ = os.RemoveAll(tmp) }()
+		file, label = common.GetLevelFilePath(head.ID, tmp), "stdin"
+		if err := os.WriteFile(file, data, 0o644); err != nil {
+			return report, false, err
 		}
 	}
 
-	if len(unsolvable) > 0 {
-		hasErrors = true
-		fmt.Printf("\n❌ Solvability check failed for %d levels:\n\n", len(unsolvable))
-		for _, s := range unsolvable {
-			fmt.Printf("  • %s (level %d): gave_up=%v states=%d\n",
-				filepath.Base(s.File), s.LevelID, s.GaveUp, s.StatesExplored)
+	result, ok := ValidateFile(ctx, file, checkSolvable, maxStates, useAstar, astarWeight, ignoreOccupancy)
+	if !ok {
+		return report, false, nil
+	}
+	if label != "" {
+		result.File = label
+	}
+	report.Levels = []LevelResult{result}
+	report.finish()
+	return report, true, nil
+}
+
+// ValidateLevel validates a level that has no file yet, such as a generated or imported one,
+// by running ValidateFile on a scratch copy. ok is false when ctx was cancelled; err reports
+// a failure to write the copy.
+func ValidateLevel(ctx context.Context, lvl *model.Level, checkSolvable bool, maxStates int, ignoreOccupancy bool) (result LevelResult, ok bool, err error) {
+	tmp, err := os.MkdirTemp("", "level-validate-")
+	if err != nil {
+		return LevelResult{}, false, err
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+	path := common.GetLevelFilePath(lvl.ID, tmp)
+	if err := common.WriteLevel(path, lvl, true); err != nil {
+		return LevelResult{}, false, err
+	}
+	result, ok = ValidateFile(ctx, path, checkSolvable, maxStates, false, 0, ignoreOccupancy)
+	return result, ok, nil
+}
+
+// validateLevelFile runs the structural checks on f and, when checkSolvable is true, the
+// solvability check through cache (nil to always solve). ok is false when ctx was cancelled
+// mid-search, leaving the level uncached so the next run checks it.
+func validateLevelFile(ctx context.Context, f string, cache *ValidationCache, checkSolvable bool, maxStates int, useAstar bool, astarWeight int, ignoreOccupancy bool) (LevelResult, bool) {
+	levelKey := filepath.Base(f)
+	if !checkSolvable {
+		result := LevelResult{File: levelKey}
+		lvl, err := readLevelFile(ctx, f, ignoreOccupancy, &result)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.LevelID = lvl.ID
 		}
+		return result, true
 	}
 
-	if hasErrors {
-		fmt.Printf("\n📊 Summary: %d passed, %d failed structural validation, %d failed solvability (total %d levels)\n",
-			len(files)-len(validationErrors)-len(unsolvable), len(validationErrors), len(unsolvable), len(files))
-		return fmt.Errorf("%d levels failed validation (see summary above)", len(validationErrors)+len(unsolvable))
+	fileBytes, rerr := os.ReadFile(f)
+	if rerr != nil {
+		return LevelResult{
+			File:  levelKey,
+			Error: fmt.Errorf("failed to read file bytes: %w", rerr).Error(),
+		}, true
 	}
 
-	fmt.Printf("\n✓ All %d levels and modules validated successfully.\n", len(files))
-	return nil
+	checked := LevelResult{File: levelKey}
+	lvl, err := readLevelFile(ctx, f, ignoreOccupancy, &checked)
+	if err != nil {
+		checked.Error = err.Error()
+		return checked, true
+	}
+
+	// The cache holds verdicts under the default mechanics only
+	if !mechanicsFrom(ctx).Monotone() {
+		cache = nil
+	}
+
+	// Cache lookup
+	if cache != nil {
+		if hit, solvable := cache.Lookup(levelKey, fileBytes, SolverVersion); hit {
+			result := checked
+			result.LevelID = lvl.ID
+			result.Solvability = &LevelStat{
+				File:           f,
+				LevelID:        lvl.ID,
+				Solvable:       solvable,
+				Solver:         "cached",
+				StatesExplored: 0,
+				MaxStates:      maxStates,
+				TimeMs:         0,
+				GaveUp:         false,
+			}
+			explainResult(ctx, lvl, maxStates, &result)
+			return result, true
+		}
+	}
+
+	solveCtx, cancel := withLevelTimeout(ctx)
+	defer cancel()
+	start := time.Now()
+	ok, stat, serr := IsSolvableWithStatsContext(solveCtx, lvl, maxStates, useAstar, astarWeight)
+	timedOut := timeoutCause(solveCtx)
+	if ctx.Err() != nil && timedOut == nil {
+		return LevelResult{}, false
+	}
+	dur := time.Since(start)
+	stat.TimeMs = dur.Milliseconds()
+	stat.File = f
+	stat.LevelID = lvl.ID
+	stat.MaxStates = maxStates
+	stat.Solvable = ok
+	if serr != nil {
+		stat.Error = serr.Error()
+	}
+
+	if timedOut != nil && !ok {
+		// A search cut off by the clock proves nothing either way
+		stat.Solvable, stat.GaveUp, stat.TimedOut = false, true, true
+		stat.Error = "solver stopped by the " + timedOut.Error()
+	}
+	if stat.GaveUp {
+		// mark as not solvable under budget
+		stat.Solvable = false
+	}
+	if stat.Solvable && lvl.MaxMoves > 0 && stat.SolutionLength > lvl.MaxMoves {
+		// The game ends when the moves run out, so a solution past max_moves does not count
+		stat.Solvable, stat.OverBudget = false, true
+		stat.Error = fmt.Sprintf("solvable in %d moves, but max_moves is %d", stat.SolutionLength, lvl.MaxMoves)
+	}
+
+	// Update cache; timeouts depend on the machine, so they are left to the next run. Over-budget
+	// levels are solved again too, so their report keeps the solution length.
+	if cache != nil && !stat.TimedOut && !stat.OverBudget {
+		cache.Update(levelKey, fileBytes, SolverVersion, stat.Solvable)
+	}
+
+	result := checked
+	result.LevelID, result.Solvability = lvl.ID, &stat
+	explainResult(ctx, lvl, maxStates, &result)
+	return result, true
+}
+
+// explainResult attaches a Deadlock to an unsolvable result when one can be proven. Levels
+// the solver gave up on are left alone: a bigger budget might still solve them.
+func explainResult(ctx context.Context, lvl model.Level, maxStates int, result *LevelResult) {
+	if !result.Unsolvable() || result.Solvability.GaveUp || result.Solvability.Error != "" {
+		return
+	}
+	if !mechanicsFrom(ctx).Monotone() {
+		// Deadlocks are explained under plain clearing, which is not what was solved
+		return
+	}
+	deadlock, err := ExplainUnsolvable(ctx, lvl, maxStates)
+	if err != nil {
+		if ctx.Err() == nil {
+			common.Warning("Level %d: failed to explain unsolvability: %v", lvl.ID, err)
+		}
+		return
+	}
+	result.Deadlock = deadlock
 }
 
 func validateModules() error {
@@ -259,6 +440,9 @@ func validateModules() error {
 	if err != nil {
 		return err
 	}
+	if errs := schema.Modules().Validate(bytes); len(errs) > 0 {
+		return schemaError(errs)
+	}
 	var reg model.ModuleRegistry
 	if err := json.Unmarshal(bytes, &reg); err != nil {
 		return err
@@ -286,11 +470,19 @@ func validateModules() error {
 	return nil
 }
 
-func readLevelFile(path string, ignoreOccupancy bool) (model.Level, error) {
+// readLevelFile decodes a level file and runs the level-file checks, then the structural rules
+// enabled under ctx, recording their findings in result as it goes. The error is the first
+// failure; under WithStrict warnings fail too.
+func readLevelFile(ctx context.Context, path string, ignoreOccupancy bool, result *LevelResult) (model.Level, error) {
+	strict := strictFrom(ctx)
 	bytes, err := os.ReadFile(path)
 	if err != nil {
 		return model.Level{}, err
 	}
+	// 0. Strict schema: unknown fields and wrong types, before any decoding
+	if errs := schema.Level().Validate(bytes); len(errs) > 0 {
+		return model.Level{}, schemaError(errs)
+	}
 	var lvl model.Level
 	if err := json.Unmarshal(bytes, &lvl); err != nil {
 		return model.Level{}, err
@@ -303,15 +495,22 @@ func readLevelFile(path string, ignoreOccupancy bool) (model.Level, error) {
 		return model.Level{}, fmt.Errorf("filename %s does not match ID %d", base, lvl.ID)
 	}
 
-	// 2. Check Grid Size
-	if len(lvl.GridSize) != 2 || lvl.GridSize[0] < 2 || lvl.GridSize[1] < 2 {
+	// 2. Check Grid Size (>=2x2, sides at most MaxGridSide)
+	if err := checkGridSize(lvl); err != nil {
+		return model.Level{}, err
+	}
+	if lvl.GridSize[0] < 2 || lvl.GridSize[1] < 2 {
 		return model.Level{}, fmt.Errorf("invalid grid size")
 	}
 
 	// 3. Check Occupancy and Coverage
-	// - Occupancy: at least MinGridCoverage (90%) of grid must be occupied by vines
+	// - Occupancy: the tier's coverage minimum (config.CoverageFor) must be occupied by vines
 	// - Coverage: 100% of grid must be either occupied by vines OR masked out
-	if err := checkOccupancyAndCoverage(lvl, ignoreOccupancy); err != nil {
+	result.Findings = checkOccupancyAndCoverage(lvl, ignoreOccupancy)
+	if strict {
+		promoteWarnings(result.Findings)
+	}
+	if err := firstError(result.Findings); err != nil {
 		return model.Level{}, err
 	}
 
@@ -330,86 +529,100 @@ func readLevelFile(path string, ignoreOccupancy bool) (model.Level, error) {
 		return model.Level{}, fmt.Errorf("invalid max_moves")
 	}
 
-	// 6. Comprehensive structural validation (ported from Dart tests)
-	if structuralErrors := ValidateStructural(lvl); len(structuralErrors) > 0 {
-		// Return first error for backward compatibility
-		return model.Level{}, structuralErrors[0]
+	// 6. Comprehensive structural validation (ported from Dart tests), one rule at a time
+	result.Rules = CheckRules(lvl, ruleFilterFrom(ctx))
+	for _, r := range result.Rules {
+		if strict {
+			promoteWarnings(r.Findings)
+		}
+		if err := firstError(r.Findings); err != nil {
+			return model.Level{}, err
+		}
 	}
 
 	return lvl, nil
 }
 
+// schemaError reports the first schema violation, noting how many more there
+// are; `level-builder schema export` writes the schemas being enforced.
+func schemaError(errs []error) error {
+	if len(errs) == 1 {
+		return fmt.Errorf("schema: %w", errs[0])
+	}
+	return fmt.Errorf("schema: %w (and %d more)", errs[0], len(errs)-1)
+}
+
 // checkOccupancyAndCoverage validates two distinct metrics:
-// 1. Occupancy: at least MinGridCoverage (90%) of the grid must be occupied by vines
-// 2. Coverage: 100% of the grid must be either occupied by vines OR masked out (no empty unmasked cells)
-func checkOccupancyAndCoverage(lvl model.Level, ignoreOccupancy bool) error {
+// 1. Occupancy: at least the tier's coverage minimum (config.CoverageFor) of the grid must be
+// occupied by vines; an error, or a warning when ignoreOccupancy is set
+// 2. Coverage: 100% of the grid should be either occupied by vines OR masked out; a warning,
+// with the uncovered cells as info
+//
+// Decorative gaps of a "show-all" mask are empty on purpose: they count as covered and are left
+// out of the grid area occupancy is measured against.
+func checkOccupancyAndCoverage(lvl model.Level, ignoreOccupancy bool) []ValidationResult {
 	w, h := lvl.GridSize[0], lvl.GridSize[1]
 	gridArea := w * h
 	occupied := make([]bool, gridArea)
+	finding := func(check string, severity Severity, format string, args ...any) ValidationResult {
+		return ValidationResult{Check: check, Severity: severity, Message: fmt.Sprintf(format, args...)}
+	}
 
 	// Mark cells occupied by vines
 	vineCount := 0
 	for _, v := range lvl.Vines {
 		for _, p := range v.OrderedPath {
 			if p.X < 0 || p.X >= w || p.Y < 0 || p.Y >= h {
-				return fmt.Errorf("vine cell out of bounds")
+				return []ValidationResult{finding("occupancy", SeverityError, "vine cell out of bounds")}
 			}
 			idx := p.Y*w + p.X
 			if occupied[idx] {
-				return fmt.Errorf("overlapping vines at (%d,%d)", p.X, p.Y)
+				return []ValidationResult{finding("occupancy", SeverityError, "overlapping vines at (%d,%d)", p.X, p.Y)}
 			}
 			occupied[idx] = true
 			vineCount++
 		}
 	}
 
+	gaps := 0
+	if lvl.Mask != nil && lvl.Mask.Mode == model.MaskModeShowAll {
+		gaps = len(lvl.Mask.Points)
+	}
+
+	var findings []ValidationResult
+
 	// Check 1: Vine occupancy must meet minimum threshold for its difficulty
-	targetOccupancy := common.MinCoverageForDifficulty(lvl.Difficulty)
-	occupancy := float64(vineCount) / float64(gridArea)
+	coverage, _ := config.CoverageFor(lvl.Difficulty, "", 0)
+	targetOccupancy := coverage.Minimum
+	occupancy := float64(vineCount) / float64(max(gridArea-gaps, 1))
 	if occupancy < (targetOccupancy - OccupancyTolerance) {
-		if !ignoreOccupancy {
-			return fmt.Errorf("vine occupancy %.1f%% below minimum threshold %.0f%% for %s difficulty",
-				occupancy*100, targetOccupancy*100, lvl.Difficulty)
+		severity := SeverityError
+		if ignoreOccupancy {
+			severity = SeverityWarning
 		}
-		// If ignoring occupancy, just warn and continue
-		fmt.Printf("⚠️ Warning: vine occupancy %.1f%% below minimum threshold %.0f%% (ignored)\n",
-			occupancy*100, targetOccupancy*100)
+		findings = append(findings, finding("occupancy", severity,
+			"vine occupancy %.1f%% below minimum threshold %.0f%% for %s difficulty",
+			occupancy*100, targetOccupancy*100, lvl.Difficulty))
 	}
 
-	// Check 2: 100% coverage (every cell is either occupied by vine OR masked)
-	// Count cells that are neither occupied nor masked
-	uncoveredCount := 0
+	// Check 2: 100% coverage (every cell is occupied by a vine, a portal OR masked)
+	var uncoveredPoints []string
 	for y := 0; y < h; y++ {
 		for x := 0; x < w; x++ {
 			idx := y*w + x
-			if !occupied[idx] {
-				// Cell not occupied by vine - check if it's masked
-				if !isCellMasked(lvl.Mask, x, y) {
-					uncoveredCount++
-				}
+			if !occupied[idx] && !lvl.IsPortal(x, y) && !isCellMasked(lvl.Mask, x, y) && !lvl.Mask.IsGap(x, y) {
+				uncoveredPoints = append(uncoveredPoints, fmt.Sprintf("(%d,%d)", x, y))
 			}
 		}
 	}
-
-	var uncoveredPoints []string
-	if uncoveredCount > 0 {
-		for y := 0; y < h; y++ {
-			for x := 0; x < w; x++ {
-				idx := y*w + x
-				if !occupied[idx] && !isCellMasked(lvl.Mask, x, y) {
-					uncoveredPoints = append(uncoveredPoints, fmt.Sprintf("(%d,%d)", x, y))
-				}
-			}
-		}
-		uncoveredPercent := float64(uncoveredCount) / float64(gridArea) * 100
-		fmt.Printf("⚠️ Warning: incomplete coverage in Level %d: %d cells (%.1f%%) are neither occupied by vines nor masked\n",
-			lvl.ID, uncoveredCount, uncoveredPercent)
-		if common.VerboseEnabled {
-			fmt.Printf("   Uncovered cells: %v\n", uncoveredPoints)
-		}
+	if n := len(uncoveredPoints); n > 0 {
+		findings = append(findings,
+			finding("coverage", SeverityWarning, "incomplete coverage: %d cells (%.1f%%) are neither occupied by vines nor masked",
+				n, float64(n)/float64(gridArea)*100),
+			finding("coverage", SeverityInfo, "uncovered cells: %s", strings.Join(uncoveredPoints, " ")))
 	}
 
-	return nil
+	return findings
 }
 
 // isCellMasked returns true if the cell at (x,y) is masked according to the mask mode
@@ -0,0 +1,90 @@
+package validator
+
+import "math/bits"
+
+// vineMask is a set of vine indices, packed 64 to a word, that the mask searches use as
+// their state. The searches are generic over its width: levels of up to 64 vines keep
+// single-word masks and larger levels get just enough words. Masks are arrays so they stay
+// comparable and can key the searches' parent maps.
+type vineMask interface {
+	~[1]uint64 | ~[2]uint64 | ~[4]uint64 | ~[8]uint64
+}
+
+// maxMaskVines is the most vines the widest vineMask holds.
+const maxMaskVines = 8 * 64
+
+// fullMask returns the mask holding vines 0..n-1.
+func fullMask[M vineMask](n int) M {
+	var m M
+	for w := 0; w < len(m) && n > 0; w++ {
+		if n >= 64 {
+			m[w] = ^uint64(0)
+		} else {
+			m[w] = uint64(1)<<uint(n) - 1
+		}
+		n -= 64
+	}
+	return m
+}
+
+// maskHas reports whether vine i is in m.
+func maskHas[M vineMask](m M, i int) bool {
+	return m[i>>6]&(uint64(1)<<uint(i&63)) != 0
+}
+
+// maskWithout returns m with vine i removed.
+func maskWithout[M vineMask](m M, i int) M {
+	m[i>>6] &^= uint64(1) << uint(i&63)
+	return m
+}
+
+// maskWith returns m with vine i added.
+func maskWith[M vineMask](m M, i int) M {
+	m[i>>6] |= uint64(1) << uint(i&63)
+	return m
+}
+
+// maskAnd returns the vines in both a and b.
+func maskAnd[M vineMask](a, b M) M {
+	for w := 0; w < len(a); w++ {
+		a[w] &= b[w]
+	}
+	return a
+}
+
+// maskCount returns the number of vines in m.
+func maskCount[M vineMask](m M) int {
+	n := 0
+	for w := 0; w < len(m); w++ {
+		n += bits.OnesCount64(m[w])
+	}
+	return n
+}
+
+// maskEmpty reports whether m holds no vines.
+func maskEmpty[M vineMask](m M) bool {
+	var zero M
+	return m == zero
+}
+
+// maskEach calls fn with every vine in m in index order until fn returns false.
+func maskEach[M vineMask](m M, fn func(i int) bool) bool {
+	for w := 0; w < len(m); w++ {
+		for rest := m[w]; rest != 0; rest &= rest - 1 {
+			if !fn(w<<6 | bits.TrailingZeros64(rest)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// maskLowestDiff returns the lowest vine in exactly one of a and b, or -1 when they are equal.
+func maskLowestDiff[M vineMask](a, b M) int {
+	for w := 0; w < len(a); w++ {
+		if d := a[w] ^ b[w]; d != 0 {
+			return w<<6 | bits.TrailingZeros64(d)
+		}
+	}
+	return -1
+}
@@ -0,0 +1,270 @@
+// Package variant remixes an existing level into gameplay-preserving
+// variants for A/B tests and remixed challenge modes. Each mutation only
+// moves or relabels the board: it mirrors or rotates the grid, permutes the
+// palette, or lets two equal-length vines trade places. So the variant keeps
+// the source's blocking structure, solution, move budget and difficulty, and
+// Verify proves it before a variant is written.
+package variant
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/common"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/generator/metrics"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/naming"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/validator"
+)
+
+// Mutation kinds.
+const (
+	KindMirror  = "mirror"  // reflect the grid along a random axis
+	KindRotate  = "rotate"  // turn the grid 90, 180 or 270 degrees clockwise
+	KindRecolor = "recolor" // permute the colors the vines use
+	KindSwap    = "swap"    // two equal-length vines of different colors trade places
+)
+
+// Kinds lists every mutation kind.
+var Kinds = []string{KindMirror, KindRotate, KindRecolor, KindSwap}
+
+// Options controls Generate.
+type Options struct {
+	Mutations int      // Distinct mutations to apply (default 3)
+	Kinds     []string // Kinds to choose from (default Kinds)
+	Seed      int64    // Picks the kinds and their parameters
+	Number    int      // Numbers the variant's name, "<source> (Variant N)" (default 1)
+}
+
+// Generate returns a variant of src renumbered to newID, made by applying
+// opts.Mutations distinct mutation kinds in a seeded random order. Vines
+// keep their index, so vine i of the variant plays like vine i of src. It
+// fails when src allows fewer kinds than asked for or the mutations leave
+// a symmetric board unchanged.
+func Generate(src *model.Level, newID int, opts Options) (model.Level, error) {
+	count := opts.Mutations
+	if count <= 0 {
+		count = 3
+	}
+	kinds := opts.Kinds
+	if len(kinds) == 0 {
+		kinds = Kinds
+	}
+	for _, k := range kinds {
+		if !slices.Contains(Kinds, k) {
+			return model.Level{}, fmt.Errorf("unknown mutation %q (want one of %s)", k, strings.Join(Kinds, ", "))
+		}
+	}
+	number := opts.Number
+	if number <= 0 {
+		number = 1
+	}
+
+	// A zero turn is a deep copy
+	out, err := common.RotateLevel(src, 0)
+	if err != nil {
+		return model.Level{}, err
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+
+	var candidates []string
+	for _, k := range kinds {
+		if applicable(&out, k) && !slices.Contains(candidates, k) {
+			candidates = append(candidates, k)
+		}
+	}
+	if len(candidates) < count {
+		return model.Level{}, fmt.Errorf("level %d allows %d of the mutations %s, %d requested",
+			src.ID, len(candidates), strings.Join(kinds, ", "), count)
+	}
+	rng.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+
+	var applied []string
+	for _, kind := range candidates[:count] {
+		label, err := mutate(&out, kind, rng)
+		if err != nil {
+			return model.Level{}, fmt.Errorf("%s of level %d: %w", kind, src.ID, err)
+		}
+		applied = append(applied, label)
+	}
+	if sameBoard(src, &out) {
+		return model.Level{}, fmt.Errorf("mutations %s leave level %d unchanged", strings.Join(applied, ", "), src.ID)
+	}
+
+	out.ID = newID
+	out.Name = fmt.Sprintf("%s (Variant %d)", src.Name, number)
+	out.NameRef = nil
+	if ref := naming.ForLevel(src); ref != nil {
+		name := naming.Variant(*ref, number)
+		out.NameRef = &name
+	}
+	out.MirrorOf, out.MirrorAxis = 0, ""
+	out.VariantOf = src.ID
+	out.VariantMutations = applied
+	// The variant cannot be regenerated from the source's seed
+	out.Seed, out.GenerationSeed, out.GenerationRNG = 0, 0, ""
+	return out, nil
+}
+
+// applicable reports whether kind can change lvl.
+func applicable(lvl *model.Level, kind string) bool {
+	switch kind {
+	case KindRecolor:
+		return len(usedColors(lvl)) > 1
+	case KindSwap:
+		return len(swapPairs(lvl)) > 0
+	default:
+		return true
+	}
+}
+
+// mutate applies one mutation of kind to lvl and returns its label, such
+// as "rotate:90" or "swap:vine_2/vine_5".
+func mutate(lvl *model.Level, kind string, rng *rand.Rand) (string, error) {
+	switch kind {
+	case KindMirror:
+		axis := common.MirrorHorizontal
+		if rng.Intn(2) == 1 {
+			axis = common.MirrorVertical
+		}
+		out, err := common.ReflectLevel(lvl, axis)
+		if err != nil {
+			return "", err
+		}
+		*lvl = out
+		return KindMirror + ":" + axis, nil
+
+	case KindRotate:
+		turns := 1 + rng.Intn(3)
+		out, err := common.RotateLevel(lvl, turns)
+		if err != nil {
+			return "", err
+		}
+		*lvl = out
+		return KindRotate + ":" + strconv.Itoa(turns*90), nil
+
+	case KindRecolor:
+		used := usedColors(lvl)
+		perm := slices.Clone(used)
+		for slices.Equal(perm, used) {
+			rng.Shuffle(len(perm), func(i, j int) { perm[i], perm[j] = perm[j], perm[i] })
+		}
+		to := make(map[int]int, len(used))
+		for i, c := range used {
+			to[c] = perm[i]
+		}
+		for i := range lvl.Vines {
+			lvl.Vines[i].ColorIndex = to[lvl.Vines[i].ColorIndex]
+		}
+		return KindRecolor, nil
+
+	case KindSwap:
+		pairs := swapPairs(lvl)
+		p := pairs[rng.Intn(len(pairs))]
+		a, b := &lvl.Vines[p[0]], &lvl.Vines[p[1]]
+		idA, idB := a.ID, b.ID
+		a.ID, b.ID = idB, idA
+		a.ColorIndex, b.ColorIndex = b.ColorIndex, a.ColorIndex
+		for _, order := range [][]string{lvl.Hints, lvl.SolutionOrder} {
+			for i, id := range order {
+				switch id {
+				case idA:
+					order[i] = idB
+				case idB:
+					order[i] = idA
+				}
+			}
+		}
+		return KindSwap + ":" + idA + "/" + idB, nil
+	}
+	return "", fmt.Errorf("unknown mutation %q", kind)
+}
+
+// usedColors returns the distinct color indices of lvl's vines, ascending.
+func usedColors(lvl *model.Level) []int {
+	var used []int
+	for _, v := range lvl.Vines {
+		if !slices.Contains(used, v.ColorIndex) {
+			used = append(used, v.ColorIndex)
+		}
+	}
+	slices.Sort(used)
+	return used
+}
+
+// swapPairs returns the index pairs of equal-length vines with different
+// colors; trading places between same-colored vines changes nothing on screen.
+func swapPairs(lvl *model.Level) [][2]int {
+	var pairs [][2]int
+	for i := range lvl.Vines {
+		for j := i + 1; j < len(lvl.Vines); j++ {
+			a, b := lvl.Vines[i], lvl.Vines[j]
+			if a.Length() == b.Length() && a.ColorIndex != b.ColorIndex {
+				pairs = append(pairs, [2]int{i, j})
+			}
+		}
+	}
+	return pairs
+}
+
+// sameBoard reports whether b draws exactly like a.
+func sameBoard(a, b *model.Level) bool {
+	if !slices.Equal(a.GridSize, b.GridSize) || len(a.Vines) != len(b.Vines) {
+		return false
+	}
+	for i := range a.Vines {
+		va, vb := a.Vines[i], b.Vines[i]
+		if va.ColorIndex != vb.ColorIndex || va.HeadDirection != vb.HeadDirection || !slices.Equal(va.OrderedPath, vb.OrderedPath) {
+			return false
+		}
+	}
+	return reflect.DeepEqual(a.Mask, b.Mask) && reflect.DeepEqual(a.Portals, b.Portals)
+}
+
+// Verify checks that v plays exactly like src: validator.VerifyVariantEquivalence
+// for structure, blocking and solvability, then identical difficulty metrics
+// when both are scored along the same solution.
+func Verify(src, v model.Level, maxStates int) error {
+	if err := validator.VerifyVariantEquivalence(src, v, maxStates); err != nil {
+		return err
+	}
+
+	ok, solution, _, err := validator.Solve(src, maxStates)
+	if err != nil {
+		return fmt.Errorf("solver error on level %d: %w", src.ID, err)
+	}
+	if !ok {
+		return fmt.Errorf("level %d is not solvable within %d states", src.ID, maxStates)
+	}
+	// Vine i of the variant stands in for vine i of src
+	ids := make(map[string]string, len(src.Vines))
+	for i := range src.Vines {
+		ids[src.Vines[i].ID] = v.Vines[i].ID
+	}
+	mapped := make([]string, len(solution))
+	for i, id := range solution {
+		mapped[i] = ids[id]
+	}
+	src.SolutionOrder, v.SolutionOrder = solution, mapped
+
+	scorer := metrics.DifficultyScorer{MaxStates: maxStates}
+	want, err := scorer.Score(src)
+	if err != nil {
+		return fmt.Errorf("scoring level %d: %w", src.ID, err)
+	}
+	got, err := scorer.Score(v)
+	if err != nil {
+		return fmt.Errorf("scoring variant: %w", err)
+	}
+	if got != want {
+		return fmt.Errorf("difficulty metrics differ: %+v vs %+v", want, got)
+	}
+	if src.Difficulty != v.Difficulty {
+		return fmt.Errorf("difficulty differs: %s vs %s", src.Difficulty, v.Difficulty)
+	}
+	return nil
+}
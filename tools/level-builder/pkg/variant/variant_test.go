@@ -0,0 +1,120 @@
+package variant
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/model"
+	"github.com/eng618/parable-bloom/tools/level-builder/pkg/naming"
+)
+
+func testLevel(t *testing.T) model.Level {
+	t.Helper()
+	vine := func(id string, color int, path ...model.Point) model.Vine {
+		v, err := model.NewVine(id, path, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		v.ColorIndex = color
+		return v
+	}
+	// vine_1 blocks vine_2; vine_2 and vine_3 have the same length
+	return model.Level{
+		ID:       12,
+		Name:     "Level 12",
+		GridSize: []int{4, 4},
+		Vines: []model.Vine{
+			vine("vine_1", 0, model.Point{X: 2, Y: 2}, model.Point{X: 2, Y: 1}, model.Point{X: 2, Y: 0}),
+			vine("vine_2", 1, model.Point{X: 1, Y: 1}, model.Point{X: 0, Y: 1}),
+			vine("vine_3", 2, model.Point{X: 0, Y: 3}, model.Point{X: 1, Y: 3}),
+		},
+		MaxMoves:      6,
+		MinMoves:      3,
+		Grace:         3,
+		ColorScheme:   []string{"#ff0000", "#00ff00", "#0000ff"},
+		Mask:          &model.Mask{Mode: "hide", Points: []model.Point{{X: 3, Y: 0}}},
+		Hints:         []string{"vine_3"},
+		SolutionOrder: []string{"vine_3", "vine_1", "vine_2"},
+	}
+}
+
+func TestGenerateVerifies(t *testing.T) {
+	src := testLevel(t)
+	for seed := int64(0); seed < 20; seed++ {
+		v, err := Generate(&src, 200, Options{Mutations: 4, Seed: seed, Number: 2})
+		if err != nil {
+			t.Fatalf("seed %d: Generate: %v", seed, err)
+		}
+		if v.ID != 200 || v.VariantOf != 12 || len(v.VariantMutations) != 4 {
+			t.Fatalf("seed %d: metadata id=%d of=%d mutations=%v", seed, v.ID, v.VariantOf, v.VariantMutations)
+		}
+		if v.Name != "Level 12 (Variant 2)" || v.NameRef == nil || naming.Render(*v.NameRef) != v.Name {
+			t.Fatalf("seed %d: name %q, ref %+v", seed, v.Name, v.NameRef)
+		}
+		if err := Verify(src, v, 10000); err != nil {
+			t.Fatalf("seed %d (%v): Verify: %v", seed, v.VariantMutations, err)
+		}
+	}
+
+	// The source is left as it was
+	if !reflect.DeepEqual(src, testLevel(t)) {
+		t.Error("Generate changed the source level")
+	}
+}
+
+func TestGenerateIsSeeded(t *testing.T) {
+	src := testLevel(t)
+	a, errA := Generate(&src, 200, Options{Mutations: 3, Seed: 7})
+	b, errB := Generate(&src, 200, Options{Mutations: 3, Seed: 7})
+	if errA != nil || errB != nil {
+		t.Fatalf("Generate: %v, %v", errA, errB)
+	}
+	if !reflect.DeepEqual(a, b) {
+		t.Error("the same seed gave different variants")
+	}
+}
+
+func TestSwapFollowsHintsAndSolution(t *testing.T) {
+	src := testLevel(t)
+	v, err := Generate(&src, 200, Options{Mutations: 1, Kinds: []string{KindSwap}})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if v.Vines[1].ID != "vine_3" || v.Vines[2].ID != "vine_2" || v.Vines[1].ColorIndex != 2 {
+		t.Fatalf("vines after swap = %s/%d, %s/%d", v.Vines[1].ID, v.Vines[1].ColorIndex, v.Vines[2].ID, v.Vines[2].ColorIndex)
+	}
+	if v.Hints[0] != "vine_2" || !reflect.DeepEqual(v.SolutionOrder, []string{"vine_2", "vine_1", "vine_3"}) {
+		t.Errorf("hints %v, solution %v did not follow the swap", v.Hints, v.SolutionOrder)
+	}
+	if err := Verify(src, v, 10000); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+}
+
+func TestGenerateRejects(t *testing.T) {
+	src := testLevel(t)
+	for i := range src.Vines {
+		src.Vines[i].ColorIndex = 0
+	}
+	for name, opts := range map[string]Options{
+		"too many":     {Mutations: 3, Kinds: []string{KindMirror, KindRecolor, KindSwap}},
+		"unknown kind": {Mutations: 1, Kinds: []string{"shuffle"}},
+	} {
+		if _, err := Generate(&src, 200, opts); err == nil {
+			t.Errorf("%s: Generate succeeded", name)
+		}
+	}
+}
+
+func TestVerifyDetectsDifference(t *testing.T) {
+	src := testLevel(t)
+	v, err := Generate(&src, 200, Options{Mutations: 2, Kinds: []string{KindMirror, KindRecolor}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.MaxMoves++
+	if err := Verify(src, v, 10000); err == nil || !strings.Contains(err.Error(), "budget") {
+		t.Errorf("Verify of a changed budget: %v", err)
+	}
+}
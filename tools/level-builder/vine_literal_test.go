@@ -0,0 +1,103 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestNoVineStructLiterals guards against constructing model.Vine values with
+// struct literals outside pkg/model. Use model.NewVine so the path and head
+// direction are validated together. Zero values (model.Vine{}) are allowed,
+// and a deliberate exception can be marked with a "vine-literal:" comment on
+// the preceding line.
+func TestNoVineStructLiterals(t *testing.T) {
+	fset := token.NewFileSet()
+	for _, root := range []string{"cmd", "pkg"} {
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+				return nil
+			}
+			if filepath.Dir(path) == filepath.Join("pkg", "model") {
+				return nil
+			}
+
+			file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+			if err != nil {
+				return err
+			}
+			allowed := allowedVineLiteralLines(fset, file)
+
+			ast.Inspect(file, func(n ast.Node) bool {
+				lit, ok := n.(*ast.CompositeLit)
+				if !ok {
+					return true
+				}
+				for _, v := range vineLiterals(lit) {
+					pos := fset.Position(v.Pos())
+					if !allowed[pos.Line] {
+						t.Errorf("%s: model.Vine struct literal; use model.NewVine", pos)
+					}
+				}
+				return true
+			})
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("walk %s: %v", root, err)
+		}
+	}
+}
+
+// vineLiterals returns non-empty model.Vine literals in lit, including
+// elements of []model.Vine literals whose type is elided.
+func vineLiterals(lit *ast.CompositeLit) []*ast.CompositeLit {
+	if isVineType(lit.Type) {
+		if len(lit.Elts) == 0 {
+			return nil
+		}
+		return []*ast.CompositeLit{lit}
+	}
+
+	arr, ok := lit.Type.(*ast.ArrayType)
+	if !ok || !isVineType(arr.Elt) {
+		return nil
+	}
+	var out []*ast.CompositeLit
+	for _, e := range lit.Elts {
+		if inner, ok := e.(*ast.CompositeLit); ok && inner.Type == nil && len(inner.Elts) > 0 {
+			out = append(out, inner)
+		}
+	}
+	return out
+}
+
+func isVineType(expr ast.Expr) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Vine" {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "model"
+}
+
+// allowedVineLiteralLines returns the lines directly following a
+// "vine-literal:" comment.
+func allowedVineLiteralLines(fset *token.FileSet, file *ast.File) map[int]bool {
+	allowed := map[int]bool{}
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			if strings.Contains(c.Text, "vine-literal:") {
+				allowed[fset.Position(c.End()).Line+1] = true
+			}
+		}
+	}
+	return allowed
+}